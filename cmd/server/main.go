@@ -65,6 +65,11 @@ func runServer(cmd *cobra.Command, args []string) error {
 	}
 	defer cleanup()
 
+	// 启动后台组件
+	if err := app.Start(context.Background()); err != nil {
+		return err
+	}
+
 	// 启动服务
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%d", app.Config.Server.Port),
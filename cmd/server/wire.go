@@ -16,9 +16,12 @@ import (
 	"github.com/google/wire"
 
 	"github.com/laolishu/go-nexus/core/app"
+	"github.com/laolishu/go-nexus/internal/fulltext"
 	"github.com/laolishu/go-nexus/internal/handler"
+	"github.com/laolishu/go-nexus/internal/plugin"
 	"github.com/laolishu/go-nexus/internal/repository"
 	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/storage"
 	"github.com/laolishu/go-nexus/pkg/config"
 	"github.com/laolishu/go-nexus/pkg/logger"
 )
@@ -30,9 +33,11 @@ func InitializeApp(configFile string) (*app.App, func(), error) {
 		logger.NewLogger,
 		repository.NewDB,
 		repository.ProviderSet,
+		storage.ProviderSet,
+		fulltext.ProviderSet,
+		plugin.ProviderSet,
 		service.ProviderSet,
-		handler.NewRepositoryHandler,
-		handler.NewArtifactHandler,
+		handler.ProviderSet,
 		app.NewApp,
 	)
 	return nil, nil, nil
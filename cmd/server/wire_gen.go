@@ -8,11 +8,14 @@ package main
 
 import (
 	"github.com/laolishu/go-nexus/core/app"
+	"github.com/laolishu/go-nexus/internal/fulltext"
 	"github.com/laolishu/go-nexus/internal/handler"
+	"github.com/laolishu/go-nexus/internal/plugin"
 	"github.com/laolishu/go-nexus/internal/repository"
 	"github.com/laolishu/go-nexus/internal/repository/dao"
 	"github.com/laolishu/go-nexus/internal/repository/impl"
 	impl2 "github.com/laolishu/go-nexus/internal/service/impl"
+	"github.com/laolishu/go-nexus/internal/storage"
 	"github.com/laolishu/go-nexus/pkg/config"
 	"github.com/laolishu/go-nexus/pkg/logger"
 )
@@ -30,16 +33,99 @@ func InitializeApp(configFile2 string) (*app.App, func(), error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	auditLogDAO := dao.NewAuditLogDAO(slogLogger, db)
+	auditLogRepositoryImpl := impl.NewAuditLogRepository(slogLogger, auditLogDAO)
+	auditRecorder := impl2.NewAuditRecorder(slogLogger, auditLogRepositoryImpl)
+	auditHandler := handler.NewAuditHandler(slogLogger, auditRecorder)
+	userDAO := dao.NewUserDAO(slogLogger, db)
+	userRepositoryImpl := impl.NewUserRepository(slogLogger, userDAO)
+	roleDAO := dao.NewRoleDAO(slogLogger, db)
+	roleRepositoryImpl := impl.NewRoleRepository(slogLogger, roleDAO)
+	userServiceImpl := impl2.NewUserService(configConfig, slogLogger, userRepositoryImpl, roleRepositoryImpl)
+	realmChain, err := impl2.NewRealmChain(configConfig, slogLogger, userServiceImpl)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	oidcRealm, err := impl2.NewOIDCRealm(configConfig, slogLogger, userServiceImpl)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	revokedTokenDAO := dao.NewRevokedTokenDAO(slogLogger, db)
+	revokedTokenRepositoryImpl := impl.NewRevokedTokenRepository(slogLogger, revokedTokenDAO)
+	accessTokenDAO := dao.NewAccessTokenDAO(slogLogger, db)
+	accessTokenRepositoryImpl := impl.NewAccessTokenRepository(slogLogger, accessTokenDAO)
+	tokenServiceImpl := impl2.NewTokenService(slogLogger, accessTokenRepositoryImpl, userServiceImpl)
+	authServiceImpl, err := impl2.NewAuthService(configConfig, slogLogger, userRepositoryImpl, realmChain, oidcRealm, revokedTokenRepositoryImpl, tokenServiceImpl)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
 	repositoryDAO := dao.NewRepositoryDAO(slogLogger, db)
 	repositoryRepositoryImpl := impl.NewRepositoryRepository(slogLogger, repositoryDAO)
-	repositoryServiceImpl := impl2.NewRepositoryService(slogLogger, repositoryRepositoryImpl)
-	repositoryHandler := handler.NewRepositoryHandler(slogLogger, repositoryServiceImpl)
+	authorizerImpl := impl2.NewAuthorizer(slogLogger, repositoryRepositoryImpl, roleRepositoryImpl)
+	authHandler := handler.NewAuthHandler(slogLogger, authServiceImpl, userServiceImpl, authorizerImpl)
+	manager := plugin.NewManager(slogLogger)
+	remoteMonitor := impl2.NewRemoteMonitor(slogLogger)
+	index, cleanup2, err := fulltext.NewIndex(configConfig, slogLogger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	cleanupPolicyDAO := dao.NewCleanupPolicyDAO(slogLogger, db)
+	cleanupPolicyRepositoryImpl := impl.NewCleanupPolicyRepository(slogLogger, cleanupPolicyDAO)
+	trustedKeyDAO := dao.NewTrustedKeyDAO(slogLogger, db)
+	trustedKeyRepositoryImpl := impl.NewTrustedKeyRepository(slogLogger, trustedKeyDAO)
 	artifactDAO := dao.NewArtifactDAO(slogLogger, db)
 	artifactRepositoryImpl := impl.NewArtifactRepository(slogLogger, artifactDAO)
-	artifactServiceImpl := impl2.NewArtifactService(slogLogger, artifactRepositoryImpl)
-	artifactHandler := handler.NewArtifactHandler(slogLogger, artifactServiceImpl)
-	appApp := app.NewApp(configConfig, slogLogger, repositoryHandler, artifactHandler, repositoryServiceImpl, artifactServiceImpl)
+	storagePlugin, err := storage.NewStorage(configConfig, slogLogger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	signingKeyDAO := dao.NewSigningKeyDAO(slogLogger, db)
+	signingKeyRepositoryImpl := impl.NewSigningKeyRepository(slogLogger, signingKeyDAO)
+	metadataSigner := impl2.NewMetadataSigner(configConfig, slogLogger, signingKeyRepositoryImpl)
+	artifactServiceImpl := impl2.NewArtifactService(slogLogger, artifactRepositoryImpl, repositoryRepositoryImpl, storagePlugin, manager, remoteMonitor, index, trustedKeyRepositoryImpl, metadataSigner)
+	repositoryServiceImpl := impl2.NewRepositoryService(slogLogger, repositoryRepositoryImpl, manager, remoteMonitor, index, cleanupPolicyRepositoryImpl, authorizerImpl, trustedKeyRepositoryImpl, artifactServiceImpl)
+	repositoryHandler := handler.NewRepositoryHandler(slogLogger, repositoryServiceImpl)
+	artifactHandler := handler.NewArtifactHandler(configConfig, slogLogger, artifactServiceImpl)
+	uploadSessionDAO := dao.NewUploadSessionDAO(slogLogger, db)
+	uploadSessionRepositoryImpl := impl.NewUploadSessionRepository(slogLogger, uploadSessionDAO)
+	uploadServiceImpl, cleanup3 := impl2.NewUploadService(configConfig, slogLogger, uploadSessionRepositoryImpl, storagePlugin, artifactServiceImpl)
+	uploadHandler := handler.NewUploadHandler(configConfig, slogLogger, uploadServiceImpl)
+	searchServiceImpl := impl2.NewSearchService(slogLogger, artifactServiceImpl, authorizerImpl)
+	searchHandler := handler.NewSearchHandler(slogLogger, searchServiceImpl)
+	propertyServiceImpl := impl2.NewPropertyService(slogLogger, artifactServiceImpl, searchServiceImpl, authorizerImpl)
+	propertyHandler := handler.NewPropertyHandler(slogLogger, propertyServiceImpl)
+	promotionServiceImpl := impl2.NewPromotionService(slogLogger, artifactServiceImpl, auditRecorder, authorizerImpl)
+	promotionHandler := handler.NewPromotionHandler(slogLogger, promotionServiceImpl)
+	stagingServiceImpl := impl2.NewStagingService(slogLogger, artifactServiceImpl, promotionServiceImpl, auditRecorder)
+	stagingHandler := handler.NewStagingHandler(configConfig, slogLogger, stagingServiceImpl)
+	cleanupServiceImpl := impl2.NewCleanupService(slogLogger, cleanupPolicyRepositoryImpl, artifactServiceImpl, auditRecorder)
+	cleanupHandler := handler.NewCleanupHandler(slogLogger, cleanupServiceImpl)
+	signatureServiceImpl := impl2.NewSignatureService(slogLogger, artifactServiceImpl, trustedKeyRepositoryImpl)
+	signatureHandler := handler.NewSignatureHandler(slogLogger, signatureServiceImpl)
+	signingKeyServiceImpl := impl2.NewSigningKeyService(slogLogger, signingKeyRepositoryImpl, metadataSigner, artifactServiceImpl)
+	signingHandler := handler.NewSigningHandler(slogLogger, signingKeyServiceImpl)
+	taskDAO := dao.NewTaskDAO(slogLogger, db)
+	taskRepositoryImpl := impl.NewTaskRepository(slogLogger, taskDAO)
+	taskRunDAO := dao.NewTaskRunDAO(slogLogger, db)
+	taskRunRepositoryImpl := impl.NewTaskRunRepository(slogLogger, taskRunDAO)
+	taskServiceImpl, cleanup4 := impl2.NewTaskService(configConfig, slogLogger, taskRepositoryImpl, taskRunRepositoryImpl, cleanupServiceImpl, artifactServiceImpl, uploadServiceImpl)
+	taskHandler := handler.NewTaskHandler(slogLogger, taskServiceImpl)
+	userHandler := handler.NewUserHandler(slogLogger, userServiceImpl)
+	tokenHandler := handler.NewTokenHandler(slogLogger, tokenServiceImpl)
+	registryTokenServiceImpl := impl2.NewRegistryTokenService(configConfig, slogLogger, authServiceImpl, authorizerImpl, repositoryRepositoryImpl)
+	registryHandler := handler.NewRegistryHandler(slogLogger, authServiceImpl, registryTokenServiceImpl)
+	v := handler.NewRouteRegistrars(auditHandler, authHandler, repositoryHandler, artifactHandler, uploadHandler, searchHandler, propertyHandler, promotionHandler, stagingHandler, cleanupHandler, signatureHandler, signingHandler, taskHandler, userHandler, tokenHandler, registryHandler)
+	appApp := app.NewApp(configConfig, slogLogger, v, repositoryServiceImpl, artifactServiceImpl, taskServiceImpl, userServiceImpl)
 	return appApp, func() {
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 	}, nil
 }
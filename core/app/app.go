@@ -9,6 +9,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -16,7 +17,6 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/laolishu/go-nexus/core/web"
-	"github.com/laolishu/go-nexus/internal/handler"
 	"github.com/laolishu/go-nexus/internal/service"
 	"github.com/laolishu/go-nexus/pkg/config"
 )
@@ -42,17 +42,19 @@ type App struct {
 	Router            *gin.Engine
 	RepositoryService service.RepositoryService
 	ArtifactService   service.ArtifactService
+	TaskService       service.TaskService
+	UserService       service.UserService
 }
 
 // NewApp 创建新的应用程序实例
 func NewApp(
 	cfg *config.Config,
 	logger *slog.Logger,
-	repositoryHandler *handler.RepositoryHandler,
-	artifactHandler *handler.ArtifactHandler,
+	registrars []web.RouteRegistrar,
 	repositoryService service.RepositoryService,
 	artifactService service.ArtifactService,
-
+	taskService service.TaskService,
+	userService service.UserService,
 ) *App {
 	// 设置 Gin 模式
 	if cfg.Server.Mode == "release" {
@@ -67,7 +69,7 @@ func NewApp(
 	router.Use(gin.Recovery())
 
 	// 设置路由
-	web.SetupRoutes(router, repositoryHandler, artifactHandler)
+	web.SetupRoutes(router, registrars...)
 
 	return &App{
 		Config:            cfg,
@@ -75,11 +77,27 @@ func NewApp(
 		Router:            router,
 		RepositoryService: repositoryService,
 		ArtifactService:   artifactService,
+		TaskService:       taskService,
+		UserService:       userService,
+	}
+}
+
+// Start 初始化内置用户并启动后台任务调度等后台组件，需在开始处理请求前调用
+func (a *App) Start(ctx context.Context) error {
+	if err := a.UserService.Bootstrap(ctx); err != nil {
+		return fmt.Errorf("failed to bootstrap users: %w", err)
+	}
+	if err := a.TaskService.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start task scheduler: %w", err)
 	}
+	return nil
 }
 
 // Run 运行应用程序
 func (a *App) Run() error {
+	if err := a.Start(context.Background()); err != nil {
+		return err
+	}
 	addr := fmt.Sprintf("%s:%d", a.Config.Server.Host, a.Config.Server.Port)
 	a.Logger.Info("Starting server", "address", addr)
 	return a.Router.Run(addr)
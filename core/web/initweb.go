@@ -10,13 +10,42 @@ package web
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/laolishu/go-nexus/core/global"
-	"github.com/laolishu/go-nexus/internal/handler"
 	"github.com/laolishu/go-nexus/pkg/sysinfo"
 )
 
+// RouteRegistrar 业务路由注册接口
+// 各处理器在 RegisterRoutes 中通过 RegisterApiHandle 等函数注册自己的路由
+type RouteRegistrar interface {
+	RegisterRoutes()
+}
+
+// MiddlewareRegistrar 需要注册全局中间件的处理器
+// RegisterMiddlewares 在注册任何路由之前调用，中间件通过 RegisterMiddleware 注册后对所有路由生效
+type MiddlewareRegistrar interface {
+	RegisterMiddlewares()
+}
+
+// HealthIndicator 健康检查指示器，返回附加到 /health 的详情以及是否健康
+type HealthIndicator func(c *gin.Context) (details interface{}, healthy bool)
+
+// healthIndicators 已注册的健康检查指示器
+var (
+	healthIndicators     = map[string]HealthIndicator{}
+	healthIndicatorMutex sync.RWMutex
+)
+
+// RegisterHealthIndicator 注册健康检查指示器，任一指示器不健康时 /health 的 status 为 degraded
+func RegisterHealthIndicator(name string, indicator HealthIndicator) {
+	healthIndicatorMutex.Lock()
+	defer healthIndicatorMutex.Unlock()
+	healthIndicators[name] = indicator
+}
+
 // SetupHealthCheck 设置健康检查路由
 func SetupHealthCheck() {
 	relativePath := "/health"
@@ -34,22 +63,33 @@ func SetupHealthCheck() {
 			"memory_usage": info.MemoryUsage,
 		}
 
+		healthIndicatorMutex.RLock()
+		defer healthIndicatorMutex.RUnlock()
+		for name, indicator := range healthIndicators {
+			details, healthy := indicator(c)
+			healthData[name] = details
+			if !healthy {
+				healthData["status"] = "degraded"
+			}
+		}
+
 		Success(c, healthData)
 	})
 }
 
 // SetupRoutes 设置所有路由
-func SetupRoutes(
-	router *gin.Engine,
-	repositoryHandler *handler.RepositoryHandler,
-	artifactHandler *handler.ArtifactHandler,
-) {
+func SetupRoutes(router *gin.Engine, registrars ...RouteRegistrar) {
 	// 先对全局变量赋值
 	global.RootRouter = router
-	global.APIv1Router = router.Group("/api/v1")
 
-	// 设置全局中间件
+	// 设置全局中间件，路由组在创建时复制已注册的中间件，因此需在创建路由组和注册路由之前完成
 	SetupMiddlewares(router)
+	for _, registrar := range registrars {
+		if m, ok := registrar.(MiddlewareRegistrar); ok {
+			m.RegisterMiddlewares()
+		}
+	}
+	global.APIv1Router = router.Group("/api/v1")
 
 	// 设置根路径路由
 	SetupRootRoutes()
@@ -58,7 +98,7 @@ func SetupRoutes(
 	SetupHealthCheck()
 
 	// 设置API v1路由
-	SetupAPIv1Routes(repositoryHandler, artifactHandler)
+	SetupAPIv1Routes(registrars...)
 }
 
 // SetupRootRoutes 设置根路径(/)的路由
@@ -82,6 +122,13 @@ func SetupRootRoutes() {
 			"base_url": "/api/v1",
 			"endpoints": gin.H{
 				"repositories": "/api/v1/repositories",
+				"search":       "/api/v1/search",
+				"staging":      "/api/v1/staging/repositories",
+				"tasks":        "/api/v1/tasks",
+				"users":        "/api/v1/users",
+				"auth":         "/api/v1/auth/login",
+				"tokens":       "/api/v1/tokens",
+				"audit":        "/api/v1/audit",
 				"health":       "/health",
 			},
 		})
@@ -89,10 +136,7 @@ func SetupRootRoutes() {
 }
 
 // SetupAPIv1Routes 设置API v1路径(/api/v1)的路由
-func SetupAPIv1Routes(
-	repositoryHandler *handler.RepositoryHandler,
-	artifactHandler *handler.ArtifactHandler,
-) *gin.RouterGroup {
+func SetupAPIv1Routes(registrars ...RouteRegistrar) *gin.RouterGroup {
 	// API信息接口
 	RegisterApiHandle(http.MethodGet, "", func(c *gin.Context) {
 		Success(c, gin.H{
@@ -101,25 +145,20 @@ func SetupAPIv1Routes(
 			"endpoints": gin.H{
 				"repositories": "/api/v1/repositories",
 				"artifacts":    "/api/v1/repositories/{id}/artifacts",
+				"search":       "/api/v1/search",
+				"staging":      "/api/v1/staging/repositories",
+				"tasks":        "/api/v1/tasks",
+				"users":        "/api/v1/users",
+				"auth":         "/api/v1/auth/login",
+				"tokens":       "/api/v1/tokens",
+				"audit":        "/api/v1/audit",
 			},
 		})
 	})
 
-	// 仓库管理路由
-	if repositoryHandler != nil {
-		RegisterApiHandle(http.MethodGet, "/repositories", repositoryHandler.ListRepositories)
-		RegisterApiHandle(http.MethodPost, "/repositories", repositoryHandler.CreateRepository)
-		RegisterApiHandle(http.MethodGet, "/repositories/:id", repositoryHandler.GetRepository)
-		RegisterApiHandle(http.MethodPut, "/repositories/:id", repositoryHandler.UpdateRepository)
-		RegisterApiHandle(http.MethodDelete, "/repositories/:id", repositoryHandler.DeleteRepository)
-	}
-
-	// Artifact 管理路由（嵌套在仓库路由下）
-	if artifactHandler != nil {
-		RegisterApiHandle(http.MethodGet, "/repositories/:id/artifacts", artifactHandler.ListArtifacts)
-		RegisterApiHandle(http.MethodPost, "/repositories/:id/artifacts", artifactHandler.UploadArtifact)
-		RegisterApiHandle(http.MethodGet, "/repositories/:id/artifacts/*path", artifactHandler.DownloadArtifact)
-		RegisterApiHandle(http.MethodDelete, "/repositories/:id/artifacts/*path", artifactHandler.DeleteArtifact)
+	// 业务路由由各处理器自行注册
+	for _, registrar := range registrars {
+		registrar.RegisterRoutes()
 	}
 
 	return global.APIv1Router
@@ -130,8 +169,9 @@ func SetupMiddlewares(router *gin.Engine) {
 	// CORS 中间件（如果需要）
 	RegisterMiddleware(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Expose-Headers", "Location, Upload-Offset, X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Request-ID, Content-Range, X-Checksum-Md5, X-Checksum-Sha1, X-Checksum-Sha256, X-Checksum-Sha512")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -141,14 +181,14 @@ func SetupMiddlewares(router *gin.Engine) {
 		c.Next()
 	})
 
-	// 请求ID中间件
+	// 请求ID中间件，没有请求ID时生成UUID，响应体、响应头与审计日志使用同一个请求ID
 	RegisterMiddleware(func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
 		if requestID == "" {
-			// 如果没有请求ID，会在响应时自动生成UUID
-		} else {
-			c.Set("request_id", requestID)
+			requestID = uuid.New().String()
 		}
+		c.Set("request_id", requestID)
+		c.Header("X-Request-ID", requestID)
 		c.Next()
 	})
 }
@@ -43,6 +43,34 @@ func SuccessWithMsg(c *gin.Context, msg string, data interface{}) {
 	})
 }
 
+// Created 201创建成功响应
+func Created(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusCreated, StandardResponse{
+		Code:      http.StatusCreated,
+		Msg:       "created",
+		Data:      data,
+		RequestID: getRequestID(c),
+	})
+}
+
+// Page 分页信息，NextCursor 为空表示没有下一页
+type Page struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// PageData 分页响应数据
+type PageData struct {
+	Items interface{} `json:"items"`
+	Page  Page        `json:"page"`
+}
+
+// SuccessWithPage 分页成功响应，data 为 {"items": [...], "page": {...}}
+func SuccessWithPage(c *gin.Context, items interface{}, page Page) {
+	Success(c, PageData{Items: items, Page: page})
+}
+
 // Error 错误响应
 func Error(c *gin.Context, httpStatus int, code int, msg string) {
 	c.JSON(httpStatus, StandardResponse{
@@ -83,11 +111,31 @@ func NotFound(c *gin.Context, msg string) {
 	Error(c, http.StatusNotFound, 404, msg)
 }
 
+// Conflict 409错误响应
+func Conflict(c *gin.Context, msg string) {
+	Error(c, http.StatusConflict, 409, msg)
+}
+
+// RequestEntityTooLarge 413错误响应
+func RequestEntityTooLarge(c *gin.Context, msg string) {
+	Error(c, http.StatusRequestEntityTooLarge, 413, msg)
+}
+
 // InternalServerError 500错误响应
 func InternalServerError(c *gin.Context, msg string) {
 	Error(c, http.StatusInternalServerError, 500, msg)
 }
 
+// ServiceUnavailable 503错误响应
+func ServiceUnavailable(c *gin.Context, msg string) {
+	Error(c, http.StatusServiceUnavailable, 503, msg)
+}
+
+// RequestID 获取当前请求的请求ID
+func RequestID(c *gin.Context) string {
+	return getRequestID(c)
+}
+
 // getRequestID 获取或生成请求ID
 func getRequestID(c *gin.Context) string {
 	// 先尝试从Header中获取请求ID
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// 权限格式
+// * 允许全部操作
+// repository:<格式>:<仓库>:<操作>，格式与仓库名称支持 * 通配符，操作为 read、write、delete、admin 或 *
+// system:<模块>:<操作>，模块为 users、repositories、staging、cleanup、tasks、audit、security 或 *，操作为 read、manage 或 *
+// 仓库 admin 包含该仓库的全部操作，write 与 delete 包含 read；系统 manage 包含 read
+const (
+	PermissionAll = "*"
+
+	permissionRepository = "repository"
+	permissionSystem     = "system"
+)
+
+// 仓库操作
+const (
+	ActionRead   = "read"
+	ActionWrite  = "write"
+	ActionDelete = "delete"
+	ActionAdmin  = "admin"
+)
+
+// 系统权限
+const (
+	PermissionUsersManage        = "system:users:manage"
+	PermissionRepositoriesManage = "system:repositories:manage"
+	PermissionStagingRead        = "system:staging:read"
+	PermissionStagingManage      = "system:staging:manage"
+	PermissionCleanupRead        = "system:cleanup:read"
+	PermissionCleanupManage      = "system:cleanup:manage"
+	PermissionTasksRead          = "system:tasks:read"
+	PermissionTasksManage        = "system:tasks:manage"
+	PermissionAuditRead          = "system:audit:read"
+	PermissionSecurityRead       = "system:security:read"
+	PermissionSecurityManage     = "system:security:manage"
+)
+
+var (
+	repositoryActions = map[string]bool{ActionRead: true, ActionWrite: true, ActionDelete: true, ActionAdmin: true}
+	systemModules     = map[string]bool{"users": true, "repositories": true, "staging": true, "cleanup": true, "tasks": true, "audit": true, "security": true}
+	systemActions     = map[string]bool{"read": true, "manage": true}
+)
+
+// RepositoryPermission 构造访问指定仓库所需的权限
+func RepositoryPermission(format, name, action string) string {
+	return strings.Join([]string{permissionRepository, format, name, action}, ":")
+}
+
+// ValidatePermission 校验权限格式
+func ValidatePermission(permission string) error {
+	if permission == PermissionAll {
+		return nil
+	}
+	parts := strings.Split(permission, ":")
+	switch {
+	case parts[0] == permissionRepository && len(parts) == 4:
+		for _, pattern := range parts[1:3] {
+			if pattern == "" {
+				return fmt.Errorf("invalid permission %q, format and repository must not be empty", permission)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid permission %q: %v", permission, err)
+			}
+		}
+		if parts[3] != PermissionAll && !repositoryActions[parts[3]] {
+			return fmt.Errorf("invalid permission %q, action must be read, write, delete, admin or *", permission)
+		}
+		return nil
+	case parts[0] == permissionSystem && len(parts) == 3:
+		if parts[1] != PermissionAll && !systemModules[parts[1]] {
+			return fmt.Errorf("invalid permission %q, unknown module %q", permission, parts[1])
+		}
+		if parts[2] != PermissionAll && !systemActions[parts[2]] {
+			return fmt.Errorf("invalid permission %q, action must be read, manage or *", permission)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid permission %q, expected *, repository:<format>:<name>:<action> or system:<module>:<action>", permission)
+}
+
+// Implies 判断已授予的权限是否包含所需权限，required 不含通配符
+func Implies(granted, required string) bool {
+	if granted == PermissionAll {
+		return true
+	}
+	g := strings.Split(granted, ":")
+	r := strings.Split(required, ":")
+	if len(g) != len(r) || len(r) < 3 || g[0] != r[0] {
+		return false
+	}
+	last := len(r) - 1
+	for i := 1; i < last; i++ {
+		if ok, _ := path.Match(g[i], r[i]); !ok {
+			return false
+		}
+	}
+	return impliesAction(g[0], g[last], r[last])
+}
+
+// impliesAction 判断已授予的操作是否包含所需操作
+func impliesAction(kind, granted, required string) bool {
+	if granted == PermissionAll || granted == required {
+		return true
+	}
+	if kind == permissionSystem {
+		return granted == "manage" && required == "read"
+	}
+	switch granted {
+	case ActionAdmin:
+		return true
+	case ActionWrite, ActionDelete:
+		return required == ActionRead
+	}
+	return false
+}
+
+// HasPermission 判断调用者的角色权限是否包含所需权限
+func (p *Principal) HasPermission(required string) bool {
+	for _, granted := range p.Permissions {
+		if Implies(granted, required) {
+			return true
+		}
+	}
+	return false
+}
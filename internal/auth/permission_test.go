@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePermission(t *testing.T) {
+	tests := []struct {
+		permission string
+		wantErr    bool
+	}{
+		{permission: PermissionAll},
+		{permission: "repository:maven:libs-release:read"},
+		{permission: "repository:*:*:write"},
+		{permission: "repository:npm:team-*:admin"},
+		{permission: "repository:maven:libs:*"},
+		{permission: PermissionUsersManage},
+		{permission: "system:*:read"},
+		{permission: "system:audit:*"},
+		{permission: "", wantErr: true},
+		{permission: "repository:maven:libs", wantErr: true},
+		{permission: "repository:maven:libs:read:extra", wantErr: true},
+		{permission: "repository::libs:read", wantErr: true},
+		{permission: "repository:maven::read", wantErr: true},
+		{permission: "repository:maven:[libs:read", wantErr: true},
+		{permission: "repository:maven:libs:manage", wantErr: true},
+		{permission: "system:users", wantErr: true},
+		{permission: "system:billing:read", wantErr: true},
+		{permission: "system:users:write", wantErr: true},
+		{permission: "admin", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.permission, func(t *testing.T) {
+			err := ValidatePermission(tt.permission)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestImplies(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  string
+		required string
+		want     bool
+	}{
+		{name: "all", granted: PermissionAll, required: PermissionUsersManage, want: true},
+		{name: "all_repository", granted: PermissionAll, required: "repository:maven:libs:admin", want: true},
+		{name: "exact", granted: "repository:maven:libs:read", required: "repository:maven:libs:read", want: true},
+		{name: "format_wildcard", granted: "repository:*:libs:read", required: "repository:npm:libs:read", want: true},
+		{name: "name_wildcard", granted: "repository:maven:team-*:write", required: "repository:maven:team-release:write", want: true},
+		{name: "name_wildcard_miss", granted: "repository:maven:team-*:write", required: "repository:maven:libs-release:write", want: false},
+		{name: "format_mismatch", granted: "repository:npm:libs:read", required: "repository:maven:libs:read", want: false},
+		{name: "admin_implies_delete", granted: "repository:maven:libs:admin", required: "repository:maven:libs:delete", want: true},
+		{name: "write_implies_read", granted: "repository:maven:libs:write", required: "repository:maven:libs:read", want: true},
+		{name: "delete_implies_read", granted: "repository:maven:libs:delete", required: "repository:maven:libs:read", want: true},
+		{name: "write_not_delete", granted: "repository:maven:libs:write", required: "repository:maven:libs:delete", want: false},
+		{name: "write_not_admin", granted: "repository:maven:libs:write", required: "repository:maven:libs:admin", want: false},
+		{name: "read_not_write", granted: "repository:maven:libs:read", required: "repository:maven:libs:write", want: false},
+		{name: "any_action", granted: "repository:maven:libs:*", required: "repository:maven:libs:admin", want: true},
+		{name: "manage_implies_read", granted: "system:staging:manage", required: PermissionStagingRead, want: true},
+		{name: "read_not_manage", granted: PermissionStagingRead, required: PermissionStagingManage, want: false},
+		{name: "any_module", granted: "system:*:read", required: PermissionAuditRead, want: true},
+		{name: "other_module", granted: PermissionTasksManage, required: PermissionUsersManage, want: false},
+		{name: "system_admin_is_not_an_action", granted: "system:users:admin", required: PermissionUsersManage, want: false},
+		{name: "kind_mismatch", granted: "repository:*:*:*", required: PermissionUsersManage, want: false},
+		{name: "repository_grants_no_system", granted: "repository:*:*:admin", required: "system:repositories:manage", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Implies(tt.granted, tt.required))
+		})
+	}
+}
+
+func TestPrincipal_HasPermission(t *testing.T) {
+	principal := &Principal{Username: "team", Permissions: []string{
+		RepositoryPermission("maven", "team-*", ActionAdmin),
+		RepositoryPermission("*", "*", ActionRead),
+		PermissionStagingManage,
+	}}
+	tests := []struct {
+		required string
+		want     bool
+	}{
+		{required: RepositoryPermission("maven", "team-release", ActionWrite), want: true},
+		{required: RepositoryPermission("npm", "team-release", ActionWrite), want: false},
+		{required: RepositoryPermission("npm", "npmjs", ActionRead), want: true},
+		{required: PermissionStagingRead, want: true},
+		{required: PermissionUsersManage, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.required, func(t *testing.T) {
+			assert.Equal(t, tt.want, principal.HasPermission(tt.required))
+		})
+	}
+	assert.False(t, (&Principal{}).HasPermission(PermissionAuditRead), "no roles grant nothing")
+}
+
+func TestRepositoryPermission(t *testing.T) {
+	assert.Equal(t, "repository:maven:libs-release:write", RepositoryPermission("maven", "libs-release", ActionWrite))
+}
@@ -0,0 +1,44 @@
+// Package auth 定义请求的认证主体，认证中间件将其写入请求 context，服务层据此判断调用者
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Principal 已认证的调用者
+type Principal struct {
+	UserID      string
+	Username    string
+	Roles       []string
+	Permissions []string  // 角色权限的并集，见 permission.go
+	Anonymous   bool      // 未提供凭据，按匿名读取访问
+	Credential  string    // 凭据类型，见 Credential* 常量
+	TokenID     string    // JWT ID 或个人访问令牌ID
+	ExpiresAt   time.Time // 凭据过期时间，零值表示不过期
+	Scopes      []string  // 个人访问令牌的范围，nil 表示不受范围限制
+}
+
+// 凭据类型
+const (
+	CredentialJWT         = "jwt"
+	CredentialAccessToken = "access_token"
+	CredentialOIDC        = "oidc"     // CI 工作负载身份签发的 OIDC 令牌
+	CredentialPassword    = "password" // 账户密码，只用于 Docker 令牌服务
+)
+
+// AnonymousPrincipal 匿名调用者
+var AnonymousPrincipal = &Principal{Username: "anonymous", Anonymous: true}
+
+type principalKey struct{}
+
+// WithPrincipal 将调用者写入 context
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext 从 context 读取调用者，未经过认证中间件时返回 nil, false
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
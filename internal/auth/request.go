@@ -0,0 +1,23 @@
+package auth
+
+import "context"
+
+// RequestInfo 请求来源信息，审计日志据此记录请求ID、IP 与 User-Agent
+type RequestInfo struct {
+	ID        string
+	IP        string
+	UserAgent string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo 将请求来源信息写入 context
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext 从 context 读取请求来源信息，后台任务中返回 nil, false
+func RequestInfoFromContext(ctx context.Context) (*RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info, ok && info != nil
+}
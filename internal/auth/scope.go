@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// 个人访问令牌范围
+// admin 允许全部操作；repo:read、repo:write 作用于所有仓库，repo:read:<仓库>、repo:write:<仓库> 只作用于名称匹配的仓库，仓库名称支持 * 通配符
+// repo:write 包含同一仓库的读取权限
+const (
+	ScopeAdmin     = "admin"
+	ScopeRepoRead  = "repo:read"
+	ScopeRepoWrite = "repo:write"
+)
+
+// 请求所需的访问级别
+const (
+	AccessAny   = "any"   // 任何有效凭据
+	AccessRead  = "read"  // 读取仓库内容
+	AccessWrite = "write" // 写入仓库内容
+	AccessAdmin = "admin" // 管理操作
+)
+
+// ValidateScope 校验范围格式
+func ValidateScope(scope string) error {
+	if scope == ScopeAdmin {
+		return nil
+	}
+	level, pattern, scoped := strings.Cut(strings.TrimPrefix(scope, "repo:"), ":")
+	if !strings.HasPrefix(scope, "repo:") || (level != AccessRead && level != AccessWrite) {
+		return fmt.Errorf("invalid scope %q, expected admin, repo:read[:<repository>] or repo:write[:<repository>]", scope)
+	}
+	if scoped {
+		if pattern == "" {
+			return fmt.Errorf("invalid scope %q, repository name is empty", scope)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid scope %q: %v", scope, err)
+		}
+	}
+	return nil
+}
+
+// AllowsScope 判断调用者的范围是否允许访问，repository 为空时表示不针对具体仓库的读写（如搜索与仓库列表）
+func (p *Principal) AllowsScope(access, repository string) bool {
+	if p.Scopes == nil || access == AccessAny {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		if scope == ScopeAdmin {
+			return true
+		}
+		if access == AccessAdmin {
+			continue
+		}
+		level, pattern, scoped := strings.Cut(strings.TrimPrefix(scope, "repo:"), ":")
+		if level != AccessWrite && (level != AccessRead || access != AccessRead) {
+			continue
+		}
+		if !scoped || repository == "" {
+			return true
+		}
+		if ok, _ := path.Match(pattern, repository); ok {
+			return true
+		}
+	}
+	return false
+}
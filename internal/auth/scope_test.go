@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScope(t *testing.T) {
+	tests := []struct {
+		scope   string
+		wantErr bool
+	}{
+		{scope: ScopeAdmin},
+		{scope: ScopeRepoRead},
+		{scope: ScopeRepoWrite},
+		{scope: "repo:read:libs-release"},
+		{scope: "repo:write:libs-*"},
+		{scope: "", wantErr: true},
+		{scope: "repo:delete", wantErr: true},
+		{scope: "repo:read:", wantErr: true},
+		{scope: "repo:write:[libs", wantErr: true},
+		{scope: "read", wantErr: true},
+		{scope: "read:libs", wantErr: true},
+		{scope: "Admin", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			err := ValidateScope(tt.scope)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPrincipal_AllowsScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		access     string
+		repository string
+		want       bool
+	}{
+		{name: "unrestricted", scopes: nil, access: AccessAdmin, want: true},
+		{name: "empty_scopes_any", scopes: []string{}, access: AccessAny, want: true},
+		{name: "empty_scopes_read", scopes: []string{}, access: AccessRead, repository: "libs", want: false},
+		{name: "admin_allows_admin", scopes: []string{ScopeAdmin}, access: AccessAdmin, want: true},
+		{name: "admin_allows_write", scopes: []string{ScopeAdmin}, access: AccessWrite, repository: "libs", want: true},
+		{name: "read_allows_read", scopes: []string{ScopeRepoRead}, access: AccessRead, repository: "libs", want: true},
+		{name: "read_denies_write", scopes: []string{ScopeRepoRead}, access: AccessWrite, repository: "libs", want: false},
+		{name: "read_denies_admin", scopes: []string{ScopeRepoRead}, access: AccessAdmin, want: false},
+		{name: "write_allows_read", scopes: []string{ScopeRepoWrite}, access: AccessRead, repository: "libs", want: true},
+		{name: "write_allows_write", scopes: []string{ScopeRepoWrite}, access: AccessWrite, repository: "libs", want: true},
+		{name: "write_denies_admin", scopes: []string{ScopeRepoWrite}, access: AccessAdmin, want: false},
+		{name: "scoped_read_match", scopes: []string{"repo:read:libs-release"}, access: AccessRead, repository: "libs-release", want: true},
+		{name: "scoped_read_other_repository", scopes: []string{"repo:read:libs-release"}, access: AccessRead, repository: "libs-snapshot", want: false},
+		{name: "scoped_read_without_repository", scopes: []string{"repo:read:libs-release"}, access: AccessRead, want: true},
+		{name: "scoped_write_wildcard", scopes: []string{"repo:write:libs-*"}, access: AccessWrite, repository: "libs-snapshot", want: true},
+		{name: "scoped_write_wildcard_miss", scopes: []string{"repo:write:libs-*"}, access: AccessWrite, repository: "docker-hosted", want: false},
+		{name: "scoped_write_implies_read", scopes: []string{"repo:write:libs-release"}, access: AccessRead, repository: "libs-release", want: true},
+		{name: "scoped_read_denies_write", scopes: []string{"repo:read:libs-release"}, access: AccessWrite, repository: "libs-release", want: false},
+		{name: "any_of_several", scopes: []string{"repo:read:npm-*", "repo:write:libs-release"}, access: AccessWrite, repository: "libs-release", want: true},
+		{name: "unknown_scope_ignored", scopes: []string{"repo:delete"}, access: AccessRead, repository: "libs", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := &Principal{Username: "ci", Scopes: tt.scopes}
+			assert.Equal(t, tt.want, principal.AllowsScope(tt.access, tt.repository))
+		})
+	}
+}
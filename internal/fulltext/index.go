@@ -0,0 +1,401 @@
+// Package fulltext 提供制品元数据的全文索引
+// 索引为内存中的倒排表，定期持久化到存储目录下，按 BM25 对结果排序
+package fulltext
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// 各字段的词频权重
+const (
+	nameWeight        = 3
+	keywordsWeight    = 2
+	descriptionWeight = 1
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// indexFormatVersion 索引文件格式版本，不一致时丢弃旧文件重建
+const indexFormatVersion = 1
+
+// flushInterval 有变更时写入磁盘的间隔
+const flushInterval = 5 * time.Second
+
+// Document 待索引的制品元数据
+type Document struct {
+	ID           string
+	RepositoryID string
+	Name         string
+	Description  string
+	Keywords     []string
+}
+
+// Hit 搜索命中的文档及相关度得分
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// entry 已索引的文档
+type entry struct {
+	RepositoryID string
+	Terms        map[string]float64 // 加权词频
+	Length       float64            // 加权词项总数
+}
+
+// snapshot 索引文件内容，倒排表在加载时由文档重建
+type snapshot struct {
+	Version int
+	Docs    map[string]*entry
+}
+
+// Index 制品元数据全文索引，并发安全
+type Index struct {
+	logger *slog.Logger
+	path   string
+
+	mu          sync.RWMutex
+	docs        map[string]*entry
+	postings    map[string]map[string]float64 // 词项 -> 文档ID -> 加权词频
+	totalLength float64
+	dirty       bool
+	loaded      bool // 是否从上次正常关闭时写入的索引文件加载
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewIndex 在存储目录下打开或创建全文索引，并定期持久化，返回的函数用于停止并写入磁盘
+func NewIndex(cfg *config.Config, logger *slog.Logger) (*Index, func(), error) {
+	idx, err := Open(filepath.Join(cfg.Storage.BasePath, "index", "metadata.idx"), logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return idx, idx.Close, nil
+}
+
+// Open 打开索引文件，文件不存在、格式不兼容或上次未正常关闭时创建空索引
+// 打开期间存在 <path>.open 标记文件，正常关闭并写入磁盘后删除；启动时标记仍在说明上次进程异常退出，
+// 定期写入之后的变更以及数据库已提交但尚未进入索引的变更都可能丢失，索引文件不再可信
+func Open(path string, logger *slog.Logger) (*Index, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+	idx := &Index{
+		logger:   logger,
+		path:     path,
+		docs:     make(map[string]*entry),
+		postings: make(map[string]map[string]float64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if _, err := os.Stat(idx.markerPath()); err == nil {
+		logger.Warn("Full-text index was not closed cleanly, discarding it", "path", path)
+	} else if err := idx.load(); err != nil {
+		logger.Warn("Discarding unreadable full-text index", "path", path, "error", err)
+		idx.docs = make(map[string]*entry)
+		idx.postings = make(map[string]map[string]float64)
+		idx.totalLength = 0
+		idx.loaded = false
+	}
+	if err := os.WriteFile(idx.markerPath(), nil, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to create index marker: %w", err)
+	}
+	go idx.flushLoop()
+	return idx, nil
+}
+
+// Loaded 是否从上次正常关闭时写入的索引文件加载，为 false 时调用方应重建索引
+func (idx *Index) Loaded() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.loaded
+}
+
+// Len 已索引的文档数量
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Add 索引文档，已存在的同ID文档会被替换；没有可索引内容时等同于 Remove
+func (idx *Index) Add(doc *Document) {
+	terms := make(map[string]float64)
+	addTerms(terms, doc.Name, nameWeight)
+	addTerms(terms, doc.Description, descriptionWeight)
+	for _, keyword := range doc.Keywords {
+		addTerms(terms, keyword, keywordsWeight)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(doc.ID)
+	if len(terms) == 0 {
+		return
+	}
+
+	e := &entry{RepositoryID: doc.RepositoryID, Terms: terms}
+	for _, tf := range terms {
+		e.Length += tf
+	}
+	idx.insert(doc.ID, e)
+	idx.dirty = true
+}
+
+// Remove 从索引中删除文档
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+// RemoveRepository 删除仓库的所有文档
+func (idx *Index) RemoveRepository(repositoryID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for id, e := range idx.docs {
+		if e.RepositoryID == repositoryID {
+			idx.remove(id)
+		}
+	}
+}
+
+// Reset 清空索引，用于全量重建
+func (idx *Index) Reset() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = make(map[string]*entry)
+	idx.postings = make(map[string]map[string]float64)
+	idx.totalLength = 0
+	idx.dirty = true
+}
+
+// Search 返回包含所有查询词项的文档，按相关度降序排列
+// repositoryIDs 为空时不限制仓库；limit 为 0 时不限制数量
+func (idx *Index) Search(query string, repositoryIDs []string, limit int) []Hit {
+	terms := uniqueTerms(tokenize(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	var allowed map[string]bool
+	if len(repositoryIDs) > 0 {
+		allowed = make(map[string]bool, len(repositoryIDs))
+		for _, id := range repositoryIDs {
+			allowed[id] = true
+		}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// 从文档最少的词项开始求交集
+	sort.Slice(terms, func(i, j int) bool { return len(idx.postings[terms[i]]) < len(idx.postings[terms[j]]) })
+	candidates := idx.postings[terms[0]]
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.docs))
+	avgLength := idx.totalLength / n
+	var hits []Hit
+	for id := range candidates {
+		e := idx.docs[id]
+		if allowed != nil && !allowed[e.RepositoryID] {
+			continue
+		}
+		score, matched := 0.0, true
+		for _, term := range terms {
+			tf, ok := e.Terms[term]
+			if !ok {
+				matched = false
+				break
+			}
+			df := float64(len(idx.postings[term]))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*e.Length/avgLength))
+		}
+		if matched {
+			hits = append(hits, Hit{ID: id, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Close 停止定期持久化并写入最新状态，写入成功后删除打开标记
+func (idx *Index) Close() {
+	close(idx.stop)
+	<-idx.done
+	if err := idx.Flush(); err != nil {
+		idx.logger.Error("Failed to save full-text index", "path", idx.path, "error", err)
+		return
+	}
+	if err := os.Remove(idx.markerPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		idx.logger.Warn("Failed to remove full-text index marker", "path", idx.path, "error", err)
+	}
+}
+
+// markerPath 打开标记文件的路径
+func (idx *Index) markerPath() string {
+	return idx.path + ".open"
+}
+
+// Flush 有未保存的变更时写入磁盘
+func (idx *Index) Flush() error {
+	idx.mu.Lock()
+	if !idx.dirty {
+		idx.mu.Unlock()
+		return nil
+	}
+	// 文档写入后不再修改，复制映射即可在锁外编码
+	docs := make(map[string]*entry, len(idx.docs))
+	for id, e := range idx.docs {
+		docs[id] = e
+	}
+	idx.dirty = false
+	idx.mu.Unlock()
+
+	if err := idx.save(&snapshot{Version: indexFormatVersion, Docs: docs}); err != nil {
+		idx.mu.Lock()
+		idx.dirty = true
+		idx.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// flushLoop 定期持久化索引
+func (idx *Index) flushLoop() {
+	defer close(idx.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-idx.stop:
+			return
+		case <-ticker.C:
+			if err := idx.Flush(); err != nil {
+				idx.logger.Warn("Failed to save full-text index", "path", idx.path, "error", err)
+			}
+		}
+	}
+}
+
+// insert 加入文档并更新倒排表，调用方需持有写锁
+func (idx *Index) insert(id string, e *entry) {
+	idx.docs[id] = e
+	idx.totalLength += e.Length
+	for term, tf := range e.Terms {
+		posting := idx.postings[term]
+		if posting == nil {
+			posting = make(map[string]float64)
+			idx.postings[term] = posting
+		}
+		posting[id] = tf
+	}
+}
+
+// remove 删除文档并更新倒排表，调用方需持有写锁
+func (idx *Index) remove(id string) {
+	e, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for term := range e.Terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLength -= e.Length
+	delete(idx.docs, id)
+	idx.dirty = true
+}
+
+// load 读取索引文件并重建倒排表
+func (idx *Index) load() error {
+	f, err := os.Open(idx.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return err
+	}
+	if snap.Version != indexFormatVersion {
+		return fmt.Errorf("unsupported index format version %d", snap.Version)
+	}
+	for id, e := range snap.Docs {
+		idx.insert(id, e)
+	}
+	idx.loaded = true
+	return nil
+}
+
+// save 先写临时文件再重命名，避免写入中断留下损坏的索引
+func (idx *Index) save(snap *snapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), filepath.Base(idx.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(snap); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), idx.path)
+}
+
+// addTerms 将文本中的词项按权重累加到词频表
+func addTerms(terms map[string]float64, text string, weight float64) {
+	for _, term := range tokenize(text) {
+		terms[term] += weight
+	}
+}
+
+// uniqueTerms 去除重复的查询词项
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
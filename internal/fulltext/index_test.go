@@ -0,0 +1,180 @@
+package fulltext
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func openIndex(t *testing.T, path string) *Index {
+	t.Helper()
+	idx, err := Open(path, testLogger())
+	require.NoError(t, err)
+	return idx
+}
+
+// crash 停止定期持久化但不执行关闭流程，模拟进程异常退出
+func crash(idx *Index) {
+	close(idx.stop)
+	<-idx.done
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := openIndex(t, filepath.Join(t.TempDir(), "metadata.idx"))
+	defer idx.Close()
+
+	idx.Add(&Document{ID: "name", RepositoryID: "r1", Name: "json parser"})
+	idx.Add(&Document{ID: "keyword", RepositoryID: "r1", Name: "codec", Keywords: []string{"json"}})
+	idx.Add(&Document{ID: "description", RepositoryID: "r2", Name: "serde", Description: "fast json library"})
+	idx.Add(&Document{ID: "other", RepositoryID: "r2", Name: "yaml parser"})
+	require.Equal(t, 4, idx.Len())
+
+	tests := []struct {
+		name  string
+		query string
+		repos []string
+		limit int
+		want  []string
+	}{
+		{name: "field_weights", query: "json", want: []string{"name", "keyword", "description"}},
+		{name: "all_terms_required", query: "json parser", want: []string{"name"}},
+		{name: "case_insensitive", query: "JSON Parser", want: []string{"name"}},
+		{name: "rare_term_ranks_higher", query: "parser", want: []string{"name", "other"}},
+		{name: "repository_filter", query: "json", repos: []string{"r2"}, want: []string{"description"}},
+		{name: "limit", query: "json", limit: 2, want: []string{"name", "keyword"}},
+		{name: "unknown_term", query: "xml", want: []string{}},
+		{name: "empty_query", query: "--", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hitIDs(idx.Search(tt.query, tt.repos, tt.limit)))
+		})
+	}
+
+	t.Run("scores_descending", func(t *testing.T) {
+		hits := idx.Search("json", nil, 0)
+		require.Len(t, hits, 3)
+		assert.Greater(t, hits[0].Score, hits[1].Score)
+		assert.Greater(t, hits[1].Score, hits[2].Score)
+	})
+}
+
+func TestIndex_Search_ShorterDocumentWins(t *testing.T) {
+	idx := openIndex(t, filepath.Join(t.TempDir(), "metadata.idx"))
+	defer idx.Close()
+
+	idx.Add(&Document{ID: "b-long", Name: "json", Description: "with a much longer description text"})
+	idx.Add(&Document{ID: "a-short", Name: "json"})
+	idx.Add(&Document{ID: "c-tie", Name: "json"})
+	assert.Equal(t, []string{"a-short", "c-tie", "b-long"}, hitIDs(idx.Search("json", nil, 0)), "ties are ordered by ID")
+}
+
+func TestIndex_AddRemove(t *testing.T) {
+	idx := openIndex(t, filepath.Join(t.TempDir(), "metadata.idx"))
+	defer idx.Close()
+
+	idx.Add(&Document{ID: "a", RepositoryID: "r1", Name: "alpha"})
+	idx.Add(&Document{ID: "b", RepositoryID: "r1", Name: "beta"})
+	idx.Add(&Document{ID: "c", RepositoryID: "r2", Name: "alpha"})
+
+	t.Run("replace", func(t *testing.T) {
+		idx.Add(&Document{ID: "a", RepositoryID: "r1", Name: "gamma"})
+		assert.Equal(t, []string{"c"}, hitIDs(idx.Search("alpha", nil, 0)))
+		assert.Equal(t, []string{"a"}, hitIDs(idx.Search("gamma", nil, 0)))
+		assert.Equal(t, 3, idx.Len())
+	})
+
+	t.Run("empty_document_removes", func(t *testing.T) {
+		idx.Add(&Document{ID: "a", RepositoryID: "r1", Name: "--"})
+		assert.Empty(t, idx.Search("gamma", nil, 0))
+		assert.Equal(t, 2, idx.Len())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		idx.Remove("b")
+		idx.Remove("missing")
+		assert.Empty(t, idx.Search("beta", nil, 0))
+		assert.NotContains(t, idx.postings, "beta")
+	})
+
+	t.Run("remove_repository", func(t *testing.T) {
+		idx.Add(&Document{ID: "d", RepositoryID: "r1", Name: "alpha"})
+		idx.RemoveRepository("r1")
+		assert.Equal(t, []string{"c"}, hitIDs(idx.Search("alpha", nil, 0)))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		idx.Reset()
+		assert.Equal(t, 0, idx.Len())
+		assert.Empty(t, idx.Search("alpha", nil, 0))
+		assert.Zero(t, idx.totalLength)
+	})
+}
+
+func TestIndex_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index", "metadata.idx")
+
+	idx := openIndex(t, path)
+	assert.False(t, idx.Loaded(), "no index file yet")
+	idx.Add(&Document{ID: "a", RepositoryID: "r1", Name: "json parser", Keywords: []string{"codec"}})
+	idx.Close()
+	assert.NoFileExists(t, path+".open")
+
+	t.Run("clean_reopen", func(t *testing.T) {
+		idx := openIndex(t, path)
+		assert.True(t, idx.Loaded())
+		assert.Equal(t, []string{"a"}, hitIDs(idx.Search("codec", nil, 0)))
+		assert.FileExists(t, path+".open")
+		idx.Close()
+	})
+
+	t.Run("unclean_reopen", func(t *testing.T) {
+		idx := openIndex(t, path)
+		require.True(t, idx.Loaded())
+		idx.Add(&Document{ID: "b", RepositoryID: "r1", Name: "unsaved"})
+		crash(idx)
+
+		idx = openIndex(t, path)
+		assert.False(t, idx.Loaded(), "stale index must be rebuilt")
+		assert.Equal(t, 0, idx.Len())
+
+		// 重建后正常关闭，下次启动恢复加载
+		idx.Add(&Document{ID: "a", RepositoryID: "r1", Name: "json parser"})
+		idx.Close()
+		idx = openIndex(t, path)
+		assert.True(t, idx.Loaded())
+		idx.Close()
+	})
+
+	t.Run("unreadable_file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("not a gob"), 0o644))
+		idx := openIndex(t, path)
+		assert.False(t, idx.Loaded())
+		assert.Equal(t, 0, idx.Len())
+		idx.Close()
+	})
+
+	t.Run("flush_without_changes", func(t *testing.T) {
+		idx := openIndex(t, filepath.Join(t.TempDir(), "metadata.idx"))
+		require.NoError(t, idx.Flush())
+		assert.NoFileExists(t, idx.path)
+		idx.Close()
+	})
+}
@@ -0,0 +1,10 @@
+package fulltext
+
+import (
+	"github.com/google/wire"
+)
+
+// ProviderSet 全文索引的 Wire 提供者集合
+var ProviderSet = wire.NewSet(
+	NewIndex,
+)
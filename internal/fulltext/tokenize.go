@@ -0,0 +1,32 @@
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenize 将文本切分为小写词项
+// 字母与数字的连续片段为一个词项，汉字等表意文字按单字切分
+func tokenize(text string) []string {
+	var terms []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			terms = append(terms, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r):
+			flush()
+			terms = append(terms, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return terms
+}
@@ -0,0 +1,32 @@
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "lowercase", text: "Spring-Boot Starter", want: []string{"spring", "boot", "starter"}},
+		{name: "digits", text: "log4j 2.17.1", want: []string{"log4j", "2", "17", "1"}},
+		{name: "punctuation_only", text: "--/..", want: nil},
+		{name: "han_per_rune", text: "日志lib工具", want: []string{"日", "志", "lib", "工", "具"}},
+		{name: "kana_per_rune", text: "ログ", want: []string{"ロ", "グ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tokenize(tt.text))
+		})
+	}
+}
+
+func TestUniqueTerms(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, uniqueTerms([]string{"a", "b", "a", "b"}))
+	assert.Empty(t, uniqueTerms(nil))
+}
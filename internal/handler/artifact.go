@@ -1,52 +1,250 @@
 package handler
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
 	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/pkg/config"
 )
 
 // ArtifactHandler 处理制品相关的 HTTP 请求
 type ArtifactHandler struct {
 	logger          *slog.Logger
 	artifactService service.ArtifactService
+	maxUploadSize   int64
 }
 
 // NewArtifactHandler 创建新的制品处理器
-func NewArtifactHandler(logger *slog.Logger, artifactService service.ArtifactService) *ArtifactHandler {
+func NewArtifactHandler(cfg *config.Config, logger *slog.Logger, artifactService service.ArtifactService) *ArtifactHandler {
 	return &ArtifactHandler{
 		logger:          logger,
 		artifactService: artifactService,
+		maxUploadSize:   cfg.Server.MaxUploadSize,
 	}
 }
 
-// ListArtifacts 列出仓库中的所有制品
+// RegisterRoutes 注册制品管理路由（嵌套在仓库路由下）
+func (h *ArtifactHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/artifacts", h.ListArtifacts)
+	web.RegisterApiHandle(http.MethodPost, "/repositories/:id/artifacts", h.UploadArtifact)
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/artifacts/*path", h.DownloadArtifact)
+	web.RegisterApiHandle(http.MethodPut, "/repositories/:id/artifacts/*path", h.PutArtifact)
+	web.RegisterApiHandle(http.MethodDelete, "/repositories/:id/artifacts/*path", h.DeleteArtifact)
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/latest", h.LatestVersion)
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/routing-test", h.TestRouting)
+}
+
+// ListArtifacts 分页列出仓库中的制品，支持过滤与排序
 func (h *ArtifactHandler) ListArtifacts(c *gin.Context) {
-	// TODO: 实现列出仓库中所有制品的逻辑
-	repoID := c.Param("id")
-	c.JSON(200, gin.H{"message": "List artifacts - Not implemented yet", "repositoryId": repoID})
+	var query dto.ListArtifactsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	page, err := h.artifactService.List(c.Request.Context(), c.Param("id"), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.SuccessWithPage(c, page.Items, web.Page{
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	})
 }
 
-// UploadArtifact 上传制品到仓库
+// UploadArtifact 通过 multipart/form-data 上传制品
+// 表单字段：file 为制品文件，path 为仓库内路径，properties 为可重复的 key=value 属性，signature 为可选的分离签名文件
+// 整个请求体不能超过 server.max_upload_size，超出时返回 413
 func (h *ArtifactHandler) UploadArtifact(c *gin.Context) {
-	// TODO: 实现上传制品到仓库的逻辑
-	repoID := c.Param("id")
-	c.JSON(201, gin.H{"message": "Upload artifact - Not implemented yet", "repositoryId": repoID})
+	limitBody(c, h.maxUploadSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondReadError(c, "failed to parse form", err)
+			return
+		}
+		web.BadRequest(c, "form field file is required")
+		return
+	}
+	path := c.PostForm("path")
+	if path == "" {
+		web.BadRequest(c, "form field path is required")
+		return
+	}
+	properties, err := parseProperties(c.PostFormArray("properties"))
+	if err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		web.BadRequest(c, "failed to open uploaded file: "+err.Error())
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondReadError(c, "failed to read uploaded file", err)
+		return
+	}
+	var signature []byte
+	if sigHeader, err := c.FormFile("signature"); err == nil {
+		sigFile, err := sigHeader.Open()
+		if err != nil {
+			web.BadRequest(c, "failed to open signature file: "+err.Error())
+			return
+		}
+		defer sigFile.Close()
+		if signature, err = io.ReadAll(sigFile); err != nil {
+			respondReadError(c, "failed to read signature file", err)
+			return
+		}
+	}
+
+	h.upload(c, &dto.UploadArtifactRequest{
+		Path:       path,
+		Data:       data,
+		Properties: properties,
+		Checksums:  checksumHeaders(c),
+		Signature:  signature,
+	})
+}
+
+// PutArtifact 以请求体作为制品内容上传到 path 指定的位置，properties 查询参数为可重复的 key=value 属性
+// X-Signature 请求头可携带 base64 编码的分离签名；请求体不能超过 server.max_upload_size，超出时返回 413
+func (h *ArtifactHandler) PutArtifact(c *gin.Context) {
+	properties, err := parseProperties(c.QueryArray("properties"))
+	if err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+	var signature []byte
+	if value := c.GetHeader("X-Signature"); value != "" {
+		if signature, err = base64.StdEncoding.DecodeString(value); err != nil {
+			web.BadRequest(c, "X-Signature must be base64 encoded")
+			return
+		}
+	}
+	limitBody(c, h.maxUploadSize)
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondReadError(c, "failed to read request body", err)
+		return
+	}
+
+	h.upload(c, &dto.UploadArtifactRequest{
+		Path:       c.Param("path"),
+		Data:       data,
+		Properties: properties,
+		Checksums:  checksumHeaders(c),
+		Signature:  signature,
+	})
+}
+
+// upload 调用上传服务并返回制品记录
+func (h *ArtifactHandler) upload(c *gin.Context, req *dto.UploadArtifactRequest) {
+	artifact, err := h.artifactService.Upload(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "artifact:"+c.Param("id")+"/"+artifact.Path)
+	web.Created(c, artifact)
+}
+
+// checksumHeaders 读取客户端提供的校验和请求头 X-Checksum-Md5/Sha1/Sha256/Sha512
+func checksumHeaders(c *gin.Context) map[string]string {
+	checksums := make(map[string]string)
+	for header, algorithm := range map[string]string{
+		"X-Checksum-Md5":    "md5",
+		"X-Checksum-Sha1":   "sha1",
+		"X-Checksum-Sha256": "sha256",
+		"X-Checksum-Sha512": "sha512",
+	} {
+		if v := c.GetHeader(header); v != "" {
+			checksums[algorithm] = v
+		}
+	}
+	return checksums
 }
 
-// DownloadArtifact 从仓库下载制品
+// parseProperties 解析 key=value 形式的属性列表
+func parseProperties(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	properties := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid property %q, expected key=value", v)
+		}
+		properties[strings.TrimSpace(key)] = value
+	}
+	return properties, nil
+}
+
+// DownloadArtifact 从仓库下载制品，group 仓库按成员顺序解析
 func (h *ArtifactHandler) DownloadArtifact(c *gin.Context) {
-	// TODO: 实现从仓库下载制品的逻辑
-	repoID := c.Param("id")
-	path := c.Param("path")
-	c.JSON(200, gin.H{"message": "Download artifact - Not implemented yet", "repositoryId": repoID, "path": path})
+	content, err := h.artifactService.Download(c.Request.Context(), c.Param("id"), c.Param("path"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Data(http.StatusOK, content.ContentType, content.Data)
 }
 
 // DeleteArtifact 从仓库删除制品
 func (h *ArtifactHandler) DeleteArtifact(c *gin.Context) {
-	// TODO: 实现从仓库删除制品的逻辑
-	repoID := c.Param("id")
-	path := c.Param("path")
-	c.JSON(200, gin.H{"message": "Delete artifact - Not implemented yet", "repositoryId": repoID, "path": path})
+	if err := h.artifactService.Delete(c.Request.Context(), c.Param("id"), c.Param("path")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// LatestVersion 查询满足版本范围的最高版本
+func (h *ArtifactHandler) LatestVersion(c *gin.Context) {
+	var query dto.LatestVersionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	latest, err := h.artifactService.Latest(c.Request.Context(), c.Param("id"), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, latest)
+}
+
+// TestRouting 路由测试，说明 path 查询参数指定的路径会由哪个仓库提供
+func (h *ArtifactHandler) TestRouting(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		web.BadRequest(c, "query parameter path is required")
+		return
+	}
+
+	explanation, err := h.artifactService.ExplainRouting(c.Request.Context(), c.Param("id"), path)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, explanation)
 }
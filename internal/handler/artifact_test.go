@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubArtifactService 记录上传请求的制品服务，未实现的方法调用时 panic
+type stubArtifactService struct {
+	service.ArtifactService
+	uploaded *dto.UploadArtifactRequest
+	latest   *dto.LatestVersionQuery
+}
+
+func (s *stubArtifactService) List(_ context.Context, _ string, query *dto.ListArtifactsQuery) (*dto.ArtifactPage, error) {
+	return &dto.ArtifactPage{Items: []*model.Artifact{{Path: query.Name}}, Limit: query.Limit, NextCursor: "next", HasMore: true}, nil
+}
+
+func (s *stubArtifactService) Upload(_ context.Context, _ string, req *dto.UploadArtifactRequest) (*model.Artifact, error) {
+	s.uploaded = req
+	return &model.Artifact{Path: req.Path}, nil
+}
+
+func (s *stubArtifactService) Latest(_ context.Context, repo string, query *dto.LatestVersionQuery) (*dto.LatestVersion, error) {
+	if repo == "missing" {
+		return nil, errs.NotFound("repository %q not found", repo)
+	}
+	s.latest = query
+	return &dto.LatestVersion{Name: query.Name, Version: "2.0", Scheme: "maven"}, nil
+}
+
+// multipartBody 构造包含 fields 与 files 的 multipart 请求体
+func multipartBody(t *testing.T, fields map[string][]string, files map[string][]byte) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, values := range fields {
+		for _, v := range values {
+			require.NoError(t, writer.WriteField(name, v))
+		}
+	}
+	for name, data := range files {
+		part, err := writer.CreateFormFile(name, name+".bin")
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestArtifactHandler_UploadArtifact(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int64
+		fields     map[string][]string
+		files      map[string][]byte
+		wantStatus int
+	}{
+		{
+			name:       "file_path_properties_and_signature",
+			limit:      1 << 20,
+			fields:     map[string][]string{"path": {"com/x/lib-1.0.jar"}, "properties": {"team=core", "stage=dev"}},
+			files:      map[string][]byte{"file": []byte("jar"), "signature": []byte("sig")},
+			wantStatus: http.StatusCreated,
+		},
+		{name: "missing_file", limit: 1 << 20, fields: map[string][]string{"path": {"a.jar"}}, wantStatus: http.StatusBadRequest},
+		{name: "missing_path", limit: 1 << 20, files: map[string][]byte{"file": []byte("jar")}, wantStatus: http.StatusBadRequest},
+		{name: "invalid_property", limit: 1 << 20, fields: map[string][]string{"path": {"a.jar"}, "properties": {"novalue"}}, files: map[string][]byte{"file": []byte("jar")}, wantStatus: http.StatusBadRequest},
+		{name: "body_too_large", limit: 64, fields: map[string][]string{"path": {"a.jar"}}, files: map[string][]byte{"file": bytes.Repeat([]byte("x"), 1024)}, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifacts := &stubArtifactService{}
+			h := NewArtifactHandler(testConfig(tt.limit), testLogger(), artifacts)
+			body, contentType := multipartBody(t, tt.fields, tt.files)
+			req := httptest.NewRequest(http.MethodPost, "/repositories/releases/artifacts", body)
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("X-Checksum-Sha1", "abc")
+
+			recorder := serve(t, http.MethodPost, "/repositories/:id/artifacts", h.UploadArtifact, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			if tt.wantStatus != http.StatusCreated {
+				assert.Nil(t, artifacts.uploaded)
+				return
+			}
+			require.NotNil(t, artifacts.uploaded)
+			assert.Equal(t, "com/x/lib-1.0.jar", artifacts.uploaded.Path)
+			assert.Equal(t, []byte("jar"), artifacts.uploaded.Data)
+			assert.Equal(t, []byte("sig"), artifacts.uploaded.Signature)
+			assert.Equal(t, map[string]string{"team": "core", "stage": "dev"}, artifacts.uploaded.Properties)
+			assert.Equal(t, map[string]string{"sha1": "abc"}, artifacts.uploaded.Checksums)
+		})
+	}
+}
+
+func TestArtifactHandler_PutArtifact(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int64
+		body       string
+		signature  string
+		wantStatus int
+	}{
+		{name: "ok", limit: 16, body: "jar", signature: base64.StdEncoding.EncodeToString([]byte("sig")), wantStatus: http.StatusCreated},
+		{name: "exactly_at_limit", limit: 3, body: "jar", wantStatus: http.StatusCreated},
+		{name: "over_limit", limit: 2, body: "jar", wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "invalid_signature", limit: 16, body: "jar", signature: "%%%", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifacts := &stubArtifactService{}
+			h := NewArtifactHandler(testConfig(tt.limit), testLogger(), artifacts)
+			req := httptest.NewRequest(http.MethodPut, "/repositories/releases/artifacts/com/x/lib-1.0.jar?properties=team=core", strings.NewReader(tt.body))
+			req.Header.Set("X-Checksum-Sha256", "def")
+			if tt.signature != "" {
+				req.Header.Set("X-Signature", tt.signature)
+			}
+
+			recorder := serve(t, http.MethodPut, "/repositories/:id/artifacts/*path", h.PutArtifact, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			if tt.wantStatus != http.StatusCreated {
+				assert.Nil(t, artifacts.uploaded)
+				return
+			}
+			require.NotNil(t, artifacts.uploaded)
+			assert.Equal(t, "/com/x/lib-1.0.jar", artifacts.uploaded.Path)
+			assert.Equal(t, []byte(tt.body), artifacts.uploaded.Data)
+			assert.Equal(t, map[string]string{"team": "core"}, artifacts.uploaded.Properties)
+			assert.Equal(t, map[string]string{"sha256": "def"}, artifacts.uploaded.Checksums)
+		})
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	props, err := parseProperties([]string{" a =1", "b==2", "c="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "=2", "c": ""}, props)
+
+	props, err = parseProperties(nil)
+	require.NoError(t, err)
+	assert.Nil(t, props)
+
+	_, err = parseProperties([]string{"=x"})
+	assert.Error(t, err)
+}
+
+func TestArtifactHandler_ListArtifacts(t *testing.T) {
+	h := NewArtifactHandler(testConfig(1), testLogger(), &stubArtifactService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/repositories/releases/artifacts?name=lib&limit=2", nil)
+	recorder := serve(t, http.MethodGet, "/repositories/:id/artifacts", h.ListArtifacts, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var body struct {
+		Data struct {
+			Items []model.Artifact `json:"items"`
+			Page  struct {
+				Limit      int    `json:"limit"`
+				NextCursor string `json:"next_cursor"`
+				HasMore    bool   `json:"has_more"`
+			} `json:"page"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Len(t, body.Data.Items, 1)
+	assert.Equal(t, "lib", body.Data.Items[0].Path)
+	assert.Equal(t, 2, body.Data.Page.Limit)
+	assert.Equal(t, "next", body.Data.Page.NextCursor)
+	assert.True(t, body.Data.Page.HasMore)
+
+	for _, query := range []string{"sort=name", "limit=-1", "limit=1001"} {
+		req := httptest.NewRequest(http.MethodGet, "/repositories/releases/artifacts?"+query, nil)
+		recorder := serve(t, http.MethodGet, "/repositories/:id/artifacts", h.ListArtifacts, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+	}
+}
+
+func TestArtifactHandler_LatestVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       string
+		query      string
+		wantStatus int
+		wantQuery  *dto.LatestVersionQuery
+	}{
+		{name: "resolved", repo: "releases", query: "name=lib&range=2.x&group_id=com.x&prerelease=true", wantStatus: http.StatusOK,
+			wantQuery: &dto.LatestVersionQuery{Name: "lib", Range: "2.x", GroupID: "com.x", Prerelease: true}},
+		{name: "name_required", repo: "releases", query: "range=2.x", wantStatus: http.StatusBadRequest},
+		{name: "invalid_prerelease", repo: "releases", query: "name=lib&prerelease=maybe", wantStatus: http.StatusBadRequest},
+		{name: "service_error", repo: "missing", query: "name=lib", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &stubArtifactService{}
+			h := NewArtifactHandler(testConfig(1), testLogger(), service)
+			req := httptest.NewRequest(http.MethodGet, "/repositories/"+tt.repo+"/latest?"+tt.query, nil)
+			recorder := serve(t, http.MethodGet, "/repositories/:id/latest", h.LatestVersion, req)
+			require.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			assert.Equal(t, tt.wantQuery, service.latest)
+			if tt.wantStatus == http.StatusOK {
+				assert.Contains(t, recorder.Body.String(), `"version":"2.0"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// auditResourceKey 处理器在 gin context 中设置的审计资源，用于创建类请求补充新资源的名称
+const auditResourceKey = "audit_resource"
+
+// auditRoute 需要审计的路由，resource 中的 {参数} 替换为路由参数
+type auditRoute struct {
+	action   string
+	resource string
+}
+
+// auditRoutes 按 "方法 路由" 索引的审计规则
+// 晋级与暂存仓库的关闭、发布、丢弃由服务层记录更详细的审计日志，分段上传的数据块不记录
+var auditRoutes = map[string]auditRoute{
+	"POST /api/v1/auth/login":        {action: "auth.login"},
+	"GET /api/v1/auth/oidc/callback": {action: "auth.login"},
+	"POST /api/v1/auth/logout":       {action: "auth.logout"},
+
+	"POST /api/v1/repositories":                         {action: "repository.create"},
+	"PUT /api/v1/repositories/:id":                      {action: "repository.update", resource: "repository:{id}"},
+	"DELETE /api/v1/repositories/:id":                   {action: "repository.delete", resource: "repository:{id}"},
+	"POST /api/v1/repositories/:id/artifacts":           {action: "artifact.upload", resource: "repository:{id}"},
+	"PUT /api/v1/repositories/:id/artifacts/*path":      {action: "artifact.deploy", resource: "artifact:{id}{path}"},
+	"DELETE /api/v1/repositories/:id/artifacts/*path":   {action: "artifact.delete", resource: "artifact:{id}{path}"},
+	"PUT /api/v1/repositories/:id/properties/*path":     {action: "property.set", resource: "artifact:{id}{path}"},
+	"DELETE /api/v1/repositories/:id/properties/*path":  {action: "property.delete", resource: "artifact:{id}{path}"},
+	"POST /api/v1/search/properties":                    {action: "property.bulk_update", resource: "search"},
+	"POST /api/v1/repositories/:id/signatures/*path":    {action: "signature.verify", resource: "artifact:{id}{path}"},
+	"POST /api/v1/repositories/:id/uploads":             {action: "upload.create", resource: "repository:{id}"},
+	"PUT /api/v1/repositories/:id/uploads/:uploadId":    {action: "upload.complete", resource: "upload:{uploadId}"},
+	"DELETE /api/v1/repositories/:id/uploads/:uploadId": {action: "upload.cancel", resource: "upload:{uploadId}"},
+	"POST /api/v1/staging/repositories":                 {action: "staging.start"},
+	"PUT /api/v1/staging/deploy/:target/*path":          {action: "staging.deploy", resource: "artifact:{target}{path}"},
+	"POST /api/v1/users":                                {action: "user.create"},
+	"PUT /api/v1/users/:id":                             {action: "user.update", resource: "user:{id}"},
+	"DELETE /api/v1/users/:id":                          {action: "user.delete", resource: "user:{id}"},
+	"PUT /api/v1/users/:id/password":                    {action: "user.password", resource: "user:{id}"},
+	"PUT /api/v1/users/:id/roles":                       {action: "user.roles", resource: "user:{id}"},
+	"POST /api/v1/roles":                                {action: "role.create"},
+	"PUT /api/v1/roles/:id":                             {action: "role.update", resource: "role:{id}"},
+	"DELETE /api/v1/roles/:id":                          {action: "role.delete", resource: "role:{id}"},
+	"POST /api/v1/tokens":                               {action: "token.create"},
+	"DELETE /api/v1/tokens/:id":                         {action: "token.revoke", resource: "token:{id}"},
+	"DELETE /api/v1/users/:id/tokens/:tokenId":          {action: "token.revoke", resource: "token:{tokenId}"},
+	"POST /api/v1/tasks":                                {action: "task.create"},
+	"PUT /api/v1/tasks/:id":                             {action: "task.update", resource: "task:{id}"},
+	"DELETE /api/v1/tasks/:id":                          {action: "task.delete", resource: "task:{id}"},
+	"POST /api/v1/tasks/:id/run":                        {action: "task.run", resource: "task:{id}"},
+	"POST /api/v1/tasks/:id/cancel":                     {action: "task.cancel", resource: "task:{id}"},
+	"POST /api/v1/cleanup-policies":                     {action: "cleanup_policy.create"},
+	"PUT /api/v1/cleanup-policies/:name":                {action: "cleanup_policy.update", resource: "cleanup_policy:{name}"},
+	"DELETE /api/v1/cleanup-policies/:name":             {action: "cleanup_policy.delete", resource: "cleanup_policy:{name}"},
+	"POST /api/v1/cleanup-policies/:name/run":           {action: "cleanup_policy.run", resource: "cleanup_policy:{name}"},
+	"POST /api/v1/trusted-keys":                         {action: "trusted_key.create"},
+	"DELETE /api/v1/trusted-keys/:name":                 {action: "trusted_key.delete", resource: "trusted_key:{name}"},
+	"POST /api/v1/signing-keys":                         {action: "signing_key.import"},
+	"DELETE /api/v1/signing-keys/:name":                 {action: "signing_key.delete", resource: "signing_key:{name}"},
+	"POST /api/v1/signing-keys/:name/activate":          {action: "signing_key.activate", resource: "signing_key:{name}"},
+}
+
+// auditCSVHeader 导出 CSV 的列
+var auditCSVHeader = []string{"created_at", "username", "user_id", "action", "resource", "status", "request_id", "ip", "user_agent", "details"}
+
+// AuditHandler 提供审计中间件与审计日志查询接口
+type AuditHandler struct {
+	logger       *slog.Logger
+	auditService service.AuditService
+}
+
+// NewAuditHandler 创建新的审计处理器
+func NewAuditHandler(logger *slog.Logger, auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		logger:       logger,
+		auditService: auditService,
+	}
+}
+
+// RegisterMiddlewares 注册审计中间件，需先于认证中间件注册，以便记录被拒绝的请求
+func (h *AuditHandler) RegisterMiddlewares() {
+	web.RegisterMiddleware(h.Audit)
+}
+
+// RegisterRoutes 注册审计日志路由
+func (h *AuditHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/audit", h.ListAudit)
+	web.RegisterApiHandle(http.MethodGet, "/audit/export", h.ExportAudit)
+}
+
+// Audit 审计中间件，将请求来源写入 context，请求完成后按 auditRoutes 记录修改类请求
+// 未认证（401）的请求除登录外不记录，避免构建工具的认证质询产生大量日志
+func (h *AuditHandler) Audit(c *gin.Context) {
+	c.Request = c.Request.WithContext(auth.WithRequestInfo(c.Request.Context(), &auth.RequestInfo{
+		ID:        web.RequestID(c),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}))
+	c.Next()
+
+	route, ok := auditRoutes[c.Request.Method+" "+c.FullPath()]
+	if !ok {
+		return
+	}
+	status := c.Writer.Status()
+	if status == http.StatusUnauthorized && route.action != "auth.login" {
+		return
+	}
+	resource := route.resource
+	for _, param := range c.Params {
+		resource = strings.ReplaceAll(resource, "{"+param.Key+"}", param.Value)
+	}
+	if value := c.GetString(auditResourceKey); value != "" {
+		resource = value
+	}
+	if resource == "" {
+		resource = strings.TrimPrefix(c.FullPath(), "/api/v1/")
+	}
+	h.auditService.RecordRequest(c.Request.Context(), route.action, resource, status)
+}
+
+// ListAudit 分页查询审计日志
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	var query dto.ListAuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	page, err := h.auditService.List(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.SuccessWithPage(c, page.Items, web.Page{
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	})
+}
+
+// ExportAudit 以 CSV 或 JSON 数组导出所有匹配的审计日志
+// 导出过程中出错时响应已开始写入，只能中断输出并记录日志
+func (h *AuditHandler) ExportAudit(c *gin.Context) {
+	var query dto.ExportAuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+	query.Limit, query.Cursor = 0, ""
+	format := query.Format
+	if format == "" {
+		format = "csv"
+	}
+
+	filename := "audit-" + time.Now().UTC().Format("20060102T150405Z") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	var (
+		write  func(entry *model.AuditLog) error
+		finish func() error
+	)
+	switch format {
+	case "json":
+		c.Header("Content-Type", "application/json")
+		encoder := json.NewEncoder(c.Writer)
+		count := 0
+		write = func(entry *model.AuditLog) error {
+			sep := ","
+			if count == 0 {
+				sep = "["
+			}
+			count++
+			if _, err := c.Writer.WriteString(sep); err != nil {
+				return err
+			}
+			return encoder.Encode(entry)
+		}
+		finish = func() error {
+			end := "]\n"
+			if count == 0 {
+				end = "[]\n"
+			}
+			_, err := c.Writer.WriteString(end)
+			return err
+		}
+	default:
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(c.Writer)
+		header := false
+		write = func(entry *model.AuditLog) error {
+			if !header {
+				header = true
+				if err := writer.Write(auditCSVHeader); err != nil {
+					return err
+				}
+			}
+			return writer.Write([]string{
+				entry.CreatedAt.UTC().Format(time.RFC3339), entry.Username, entry.UserID, entry.Action, entry.Resource,
+				strconv.Itoa(entry.Status), entry.RequestID, entry.IP, entry.UserAgent, entry.Details,
+			})
+		}
+		finish = func() error {
+			if !header {
+				if err := writer.Write(auditCSVHeader); err != nil {
+					return err
+				}
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	if err := h.auditService.Export(c.Request.Context(), &query.ListAuditQuery, write); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.Writer.Header().Del("Content-Type")
+			respondError(c, h.logger, err)
+			return
+		}
+		h.logger.Error("Failed to export audit logs", "error", err)
+		return
+	}
+	if err := finish(); err != nil {
+		h.logger.Error("Failed to export audit logs", "error", err)
+	}
+}
+
+// setAuditResource 设置当前请求的审计资源，用于创建类请求记录新资源的名称
+func setAuditResource(c *gin.Context, resource string) {
+	c.Set(auditResourceKey, resource)
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// auditRecord stubAuditService 记录的一次请求
+type auditRecord struct {
+	action    string
+	resource  string
+	status    int
+	requestIP string
+}
+
+// stubAuditService 记录请求并返回固定审计日志的审计服务
+type stubAuditService struct {
+	service.AuditService
+	records   []auditRecord
+	query     *dto.ListAuditQuery
+	entries   []*model.AuditLog
+	exportErr error
+}
+
+func (s *stubAuditService) RecordRequest(ctx context.Context, action, resource string, status int) {
+	record := auditRecord{action: action, resource: resource, status: status}
+	if info, ok := auth.RequestInfoFromContext(ctx); ok {
+		record.requestIP = info.IP
+	}
+	s.records = append(s.records, record)
+}
+
+func (s *stubAuditService) List(_ context.Context, query *dto.ListAuditQuery) (*dto.AuditPage, error) {
+	s.query = query
+	return &dto.AuditPage{Items: s.entries, Limit: 50, HasMore: true, NextCursor: "next"}, nil
+}
+
+func (s *stubAuditService) Export(_ context.Context, query *dto.ListAuditQuery, fn func(entry *model.AuditLog) error) error {
+	s.query = query
+	for i, entry := range s.entries {
+		if s.exportErr != nil && i == 1 {
+			return s.exportErr
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	if s.exportErr != nil && len(s.entries) <= 1 {
+		return s.exportErr
+	}
+	return nil
+}
+
+// auditEntries 导出测试使用的审计日志
+func auditEntries() []*model.AuditLog {
+	at := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	return []*model.AuditLog{
+		{ID: "a2", CreatedAt: at.Add(time.Minute), Username: "bob", UserID: "u2", Action: "user.update", Resource: "user:alice", Status: 403, RequestID: "req-2", IP: "10.0.0.2", UserAgent: "curl/8", Details: `{"a":"b,c"}`},
+		{ID: "a1", CreatedAt: at, Username: "alice", UserID: "u1", Action: "repository.create", Resource: "repository:libs", Status: 201, RequestID: "req-1", IP: "10.0.0.1", UserAgent: "maven/3.9"},
+	}
+}
+
+func TestAuditHandler_Audit(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		path   string
+		status int
+		set    string
+		want   []auditRecord
+	}{
+		{name: "route_params", method: http.MethodDelete, route: "/api/v1/repositories/:id/artifacts/*path", path: "/api/v1/repositories/libs/artifacts/com/x/a.jar", status: http.StatusOK,
+			want: []auditRecord{{action: "artifact.delete", resource: "artifact:libs/com/x/a.jar", status: http.StatusOK}}},
+		{name: "handler_resource", method: http.MethodPost, route: "/api/v1/users", path: "/api/v1/users", status: http.StatusCreated, set: "user:alice",
+			want: []auditRecord{{action: "user.create", resource: "user:alice", status: http.StatusCreated}}},
+		{name: "fallback_resource", method: http.MethodPost, route: "/api/v1/repositories", path: "/api/v1/repositories", status: http.StatusBadRequest,
+			want: []auditRecord{{action: "repository.create", resource: "repositories", status: http.StatusBadRequest}}},
+		{name: "forbidden_recorded", method: http.MethodPut, route: "/api/v1/users/:id", path: "/api/v1/users/alice", status: http.StatusForbidden,
+			want: []auditRecord{{action: "user.update", resource: "user:alice", status: http.StatusForbidden}}},
+		{name: "unauthenticated_skipped", method: http.MethodPut, route: "/api/v1/users/:id", path: "/api/v1/users/alice", status: http.StatusUnauthorized},
+		{name: "failed_login_recorded", method: http.MethodPost, route: "/api/v1/auth/login", path: "/api/v1/auth/login", status: http.StatusUnauthorized, set: "user:mallory",
+			want: []auditRecord{{action: "auth.login", resource: "user:mallory", status: http.StatusUnauthorized}}},
+		{name: "read_not_recorded", method: http.MethodGet, route: "/api/v1/users/:id", path: "/api/v1/users/alice", status: http.StatusOK},
+		{name: "unknown_route", method: http.MethodPost, route: "/api/v1/other", path: "/api/v1/missing", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuditService{}
+			h := NewAuditHandler(testLogger(), stub)
+			engine := gin.New()
+			engine.Use(h.Audit)
+			engine.Handle(tt.method, tt.route, func(c *gin.Context) {
+				if tt.set != "" {
+					setAuditResource(c, tt.set)
+				}
+				c.Status(tt.status)
+			})
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.RemoteAddr = "10.0.0.1:5555"
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+
+			for i := range tt.want {
+				tt.want[i].requestIP = "10.0.0.1"
+			}
+			if tt.want == nil {
+				assert.Empty(t, stub.records)
+				return
+			}
+			assert.Equal(t, tt.want, stub.records)
+		})
+	}
+}
+
+func TestAuditHandler_ListAudit(t *testing.T) {
+	t.Run("filters", func(t *testing.T) {
+		stub := &stubAuditService{entries: auditEntries()}
+		h := NewAuditHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodGet, "/audit?user=alice&action=repository.*&status=201&from=2026-10-01T00:00:00Z&limit=10&cursor=abc", nil)
+		recorder := serve(t, http.MethodGet, "/audit", h.ListAudit, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "alice", stub.query.User)
+		assert.Equal(t, "repository.*", stub.query.Action)
+		assert.Equal(t, 201, stub.query.Status)
+		assert.Equal(t, 10, stub.query.Limit)
+		assert.Equal(t, "abc", stub.query.Cursor)
+		require.NotNil(t, stub.query.From)
+		assert.True(t, stub.query.From.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Contains(t, dataOf(t, recorder), `"action":"user.update"`)
+		assert.Contains(t, recorder.Body.String(), `"next_cursor":"next"`)
+	})
+
+	t.Run("invalid_query", func(t *testing.T) {
+		for _, query := range []string{"limit=1001", "from=yesterday", "status=ok"} {
+			stub := &stubAuditService{}
+			h := NewAuditHandler(testLogger(), stub)
+			recorder := serve(t, http.MethodGet, "/audit", h.ListAudit, httptest.NewRequest(http.MethodGet, "/audit?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+			assert.Nil(t, stub.query, query)
+		}
+	})
+}
+
+func TestAuditHandler_ExportAudit(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		stub := &stubAuditService{entries: auditEntries()}
+		h := NewAuditHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/audit/export", h.ExportAudit, httptest.NewRequest(http.MethodGet, "/audit/export?user=alice&limit=5&cursor=abc", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Header().Get("Content-Disposition"), `.csv"`)
+		assert.Equal(t, "alice", stub.query.User)
+		assert.Zero(t, stub.query.Limit, "pagination is ignored")
+		assert.Empty(t, stub.query.Cursor)
+
+		rows, err := csv.NewReader(recorder.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, auditCSVHeader, rows[0])
+		assert.Equal(t, []string{"2026-10-01T08:31:00Z", "bob", "u2", "user.update", "user:alice", "403", "req-2", "10.0.0.2", "curl/8", `{"a":"b,c"}`}, rows[1])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		stub := &stubAuditService{entries: auditEntries()}
+		h := NewAuditHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/audit/export", h.ExportAudit, httptest.NewRequest(http.MethodGet, "/audit/export?format=json", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		var entries []*model.AuditLog
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &entries))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "a2", entries[0].ID)
+		assert.Equal(t, "a1", entries[1].ID)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		tests := []struct {
+			format string
+			want   string
+		}{
+			{format: "csv", want: strings.Join(auditCSVHeader, ",") + "\n"},
+			{format: "json", want: "[]\n"},
+		}
+		for _, tt := range tests {
+			h := NewAuditHandler(testLogger(), &stubAuditService{})
+			recorder := serve(t, http.MethodGet, "/audit/export", h.ExportAudit, httptest.NewRequest(http.MethodGet, "/audit/export?format="+tt.format, nil))
+			require.Equal(t, http.StatusOK, recorder.Code, tt.format)
+			assert.Equal(t, tt.want, recorder.Body.String(), tt.format)
+		}
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		h := NewAuditHandler(testLogger(), &stubAuditService{})
+		recorder := serve(t, http.MethodGet, "/audit/export", h.ExportAudit, httptest.NewRequest(http.MethodGet, "/audit/export?format=xml", nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("error_before_output", func(t *testing.T) {
+		stub := &stubAuditService{exportErr: errs.InvalidArgument("more than 100000 audit logs match, narrow the time range")}
+		h := NewAuditHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/audit/export", h.ExportAudit, httptest.NewRequest(http.MethodGet, "/audit/export", nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Content-Disposition"))
+		assert.Contains(t, recorder.Body.String(), "narrow the time range")
+	})
+
+	t.Run("error_after_output_truncates", func(t *testing.T) {
+		stub := &stubAuditService{entries: auditEntries(), exportErr: errs.InvalidArgument("too many")}
+		h := NewAuditHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/audit/export", h.ExportAudit, httptest.NewRequest(http.MethodGet, "/audit/export?format=json", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.False(t, json.Valid(recorder.Body.Bytes()), "an interrupted export is not a complete document")
+	})
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// authChallenges 认证失败时返回的 WWW-Authenticate 头，Basic 供构建工具以个人访问令牌作为密码
+var authChallenges = []string{`Bearer realm="go-nexus"`, `Basic realm="go-nexus"`}
+
+// oidcStateCookie 保存 OIDC 登录状态的 Cookie，只在回调路径上发送
+const (
+	oidcStateCookie     = "go_nexus_oidc"
+	oidcStateCookiePath = "/api/v1/auth/oidc"
+)
+
+// publicPaths 无需认证即可访问的路径
+var publicPaths = map[string]bool{
+	"/":                          true,
+	"/health":                    true,
+	"/api":                       true,
+	"/api/v1":                    true,
+	"/api/v1/auth/login":         true,
+	"/api/v1/auth/refresh":       true,
+	"/api/v1/auth/oidc/login":    true,
+	"/api/v1/auth/oidc/callback": true,
+	registryTokenPath:            true,
+	signingPublicKeyPath:         true,
+}
+
+// anonymousReadPrefixes 开启匿名读取时，未认证调用者可以 GET/HEAD 的路径前缀
+var anonymousReadPrefixes = []string{
+	"/api/v1/repositories",
+	"/api/v1/search",
+}
+
+// AuthHandler 处理登录、令牌刷新与注销，并提供全局认证中间件
+type AuthHandler struct {
+	logger      *slog.Logger
+	authService service.AuthService
+	userService service.UserService
+	authorizer  service.Authorizer
+}
+
+// NewAuthHandler 创建新的认证处理器
+func NewAuthHandler(
+	logger *slog.Logger,
+	authService service.AuthService,
+	userService service.UserService,
+	authorizer service.Authorizer,
+) *AuthHandler {
+	return &AuthHandler{
+		logger:      logger,
+		authService: authService,
+		userService: userService,
+		authorizer:  authorizer,
+	}
+}
+
+// RegisterMiddlewares 注册认证中间件
+func (h *AuthHandler) RegisterMiddlewares() {
+	web.RegisterMiddleware(h.Authenticate)
+}
+
+// RegisterRoutes 注册认证路由
+func (h *AuthHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodPost, "/auth/login", h.Login)
+	web.RegisterApiHandle(http.MethodPost, "/auth/refresh", h.Refresh)
+	web.RegisterApiHandle(http.MethodPost, "/auth/logout", h.Logout)
+	web.RegisterApiHandle(http.MethodGet, "/auth/me", h.Me)
+	web.RegisterApiHandle(http.MethodGet, "/auth/oidc/login", h.OIDCLogin)
+	web.RegisterApiHandle(http.MethodGet, "/auth/oidc/callback", h.OIDCCallback)
+}
+
+// Authenticate 认证中间件，校验 Authorization 头中的 Bearer 令牌或 Basic 凭据并将调用者写入请求 context
+// 公开路径与 /v2 直接放行，/v2 由 RegistryHandler 校验 Docker 令牌；未提供凭据且开启匿名读取时，仓库与搜索的读请求按匿名调用者放行
+// 认证后按 routeRules 校验调用者的权限与令牌范围
+func (h *AuthHandler) Authenticate(c *gin.Context) {
+	path := c.Request.URL.Path
+	if publicPaths[path] || path == registryPrefix || strings.HasPrefix(path, registryPrefix+"/") {
+		c.Next()
+		return
+	}
+
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		if h.authService.AnonymousRead() && isReadRequest(c.Request.Method) && isAnonymousReadable(path) {
+			h.authorize(c, auth.AnonymousPrincipal)
+			return
+		}
+		h.challenge(c, "authentication required")
+		return
+	}
+
+	var (
+		principal *auth.Principal
+		err       error
+	)
+	scheme, credentials, _ := strings.Cut(header, " ")
+	credentials = strings.TrimSpace(credentials)
+	switch {
+	case credentials == "":
+		h.challenge(c, "unsupported authorization scheme")
+		return
+	case strings.EqualFold(scheme, "Bearer"):
+		principal, err = h.authService.Authenticate(c.Request.Context(), credentials)
+	case strings.EqualFold(scheme, "Basic"):
+		username, password, ok := c.Request.BasicAuth()
+		if !ok {
+			h.challenge(c, "malformed basic credentials")
+			return
+		}
+		principal, err = h.authService.AuthenticateBasic(c.Request.Context(), username, password)
+	default:
+		h.challenge(c, "unsupported authorization scheme")
+		return
+	}
+	if err != nil {
+		h.reject(c, err)
+		return
+	}
+	h.authorize(c, principal)
+}
+
+// authorize 将调用者写入请求 context，并按路由规则校验权限
+func (h *AuthHandler) authorize(c *gin.Context, principal *auth.Principal) {
+	c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+	if c.FullPath() != "" {
+		if err := authorizeRoute(c, h.authorizer, principal); err != nil {
+			h.reject(c, err)
+			return
+		}
+	}
+	c.Next()
+}
+
+// reject 认证或授权失败，未认证时返回 401 与认证提示
+func (h *AuthHandler) reject(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrUnauthenticated) {
+		h.challenge(c, err.Error())
+		return
+	}
+	respondError(c, h.logger, err)
+	c.Abort()
+}
+
+// Login 用户名密码登录
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	setAuditResource(c, "user:"+req.Username)
+	tokens, err := h.authService.Login(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, tokens)
+}
+
+// OIDCLogin 跳转到 OIDC 签发者登录，登录状态保存在 Cookie 中
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	authorization, err := h.authService.OIDCAuthorize(c.Request.Context(), c.Query("redirect"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	h.setOIDCState(c, authorization.State, int(authorization.ExpiresIn))
+	c.Redirect(http.StatusFound, authorization.URL)
+}
+
+// OIDCCallback 签发者登录后的回调，登录时指定了 redirect 则携带令牌跳转，否则返回令牌
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	var query dto.OIDCCallbackQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+	state, _ := c.Cookie(oidcStateCookie)
+	h.setOIDCState(c, "", -1)
+
+	tokens, redirect, err := h.authService.OIDCCallback(c.Request.Context(), &query, state)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "user:"+tokens.User.Username)
+	if redirect == "" {
+		web.Success(c, tokens)
+		return
+	}
+	// 令牌放在 URL 片段中，不会发送到服务端或出现在访问日志里
+	fragment := url.Values{
+		"access_token":  {tokens.AccessToken},
+		"refresh_token": {tokens.RefreshToken},
+		"token_type":    {tokens.TokenType},
+		"expires_in":    {strconv.FormatInt(tokens.ExpiresIn, 10)},
+	}
+	c.Redirect(http.StatusFound, redirect+"#"+fragment.Encode())
+}
+
+// setOIDCState 设置或清除登录状态 Cookie
+func (h *AuthHandler) setOIDCState(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, oidcStateCookiePath, "", secure, true)
+}
+
+// Refresh 使用刷新令牌换取新的令牌
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	tokens, err := h.authService.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, tokens)
+}
+
+// Logout 注销当前令牌，请求体可选
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	principal, _ := auth.PrincipalFromContext(c.Request.Context())
+	if err := h.authService.Logout(c.Request.Context(), principal, &req); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// Me 获取当前登录用户
+func (h *AuthHandler) Me(c *gin.Context) {
+	principal, ok := auth.PrincipalFromContext(c.Request.Context())
+	if !ok || principal.Anonymous {
+		h.challenge(c, "authentication required")
+		return
+	}
+	user, err := h.userService.Get(c.Request.Context(), principal.UserID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, user)
+}
+
+// challenge 返回 401 并提示客户端使用 Bearer 或 Basic 认证
+func (h *AuthHandler) challenge(c *gin.Context, msg string) {
+	for _, challenge := range authChallenges {
+		c.Writer.Header().Add("WWW-Authenticate", challenge)
+	}
+	web.Unauthorized(c, msg)
+	c.Abort()
+}
+
+// isReadRequest 判断是否为只读请求
+func isReadRequest(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// isAnonymousReadable 判断路径是否允许匿名读取
+func isAnonymousReadable(path string) bool {
+	for _, prefix := range anonymousReadPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubAuthService 固定凭据的认证服务：Bearer good-token 与 Basic alice:secret 认证为 alice
+type stubAuthService struct {
+	service.AuthService
+	anonymous bool
+	login     *dto.LoginRequest
+	// oidcRedirect OIDC 回调返回的跳转地址，oidcState 记录回调收到的登录状态
+	oidcRedirect string
+	oidcState    string
+}
+
+func (s *stubAuthService) Authenticate(_ context.Context, token string) (*auth.Principal, error) {
+	if token != "good-token" {
+		return nil, errs.Unauthenticated("invalid token")
+	}
+	return &auth.Principal{UserID: "u1", Username: "alice", Credential: auth.CredentialJWT}, nil
+}
+
+func (s *stubAuthService) AuthenticateBasic(_ context.Context, username, password string) (*auth.Principal, error) {
+	if username == "locked" {
+		return nil, errs.Forbidden("user %q is locked", username)
+	}
+	if username != "alice" || password != "secret" {
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+	return &auth.Principal{UserID: "u1", Username: "alice", Credential: auth.CredentialAccessToken}, nil
+}
+
+func (s *stubAuthService) AuthenticatePassword(_ context.Context, username, password string) (*auth.Principal, error) {
+	if username != "alice" || password != "secret" {
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+	return &auth.Principal{UserID: "u1", Username: "alice", Credential: auth.CredentialPassword}, nil
+}
+
+func (s *stubAuthService) AnonymousRead() bool {
+	return s.anonymous
+}
+
+func (s *stubAuthService) Login(_ context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	s.login = req
+	if req.Password != "secret" {
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+	return &dto.TokenResponse{AccessToken: "good-token", TokenType: "Bearer"}, nil
+}
+
+func (s *stubAuthService) OIDCAuthorize(_ context.Context, redirect string) (*dto.OIDCAuthorization, error) {
+	if !strings.HasPrefix(redirect, "/") && redirect != "" {
+		return nil, errs.InvalidArgument("redirect must be a path on this server")
+	}
+	return &dto.OIDCAuthorization{URL: "https://id.example.com/authorize?state=s1", State: "signed-state", ExpiresIn: 600}, nil
+}
+
+func (s *stubAuthService) OIDCCallback(_ context.Context, query *dto.OIDCCallbackQuery, state string) (*dto.TokenResponse, string, error) {
+	s.oidcState = state
+	if state != "signed-state" || query.State != "s1" {
+		return nil, "", errs.Unauthenticated("OIDC login state does not match")
+	}
+	return &dto.TokenResponse{AccessToken: "good-token", RefreshToken: "refresh-token", TokenType: "Bearer", ExpiresIn: 900,
+		User: &model.User{Username: "alice"}}, s.oidcRedirect, nil
+}
+
+// stubAuthorizer 只放行 allowed 中的系统权限与仓库操作，并记录最后一次校验
+type stubAuthorizer struct {
+	allowed map[string]bool
+	checked string
+}
+
+func (a *stubAuthorizer) check(key string) error {
+	a.checked = key
+	if a.allowed[key] {
+		return nil
+	}
+	return errs.Forbidden("%s denied", key)
+}
+
+func (a *stubAuthorizer) Authorize(_ context.Context, permission string) error {
+	return a.check(permission)
+}
+
+func (a *stubAuthorizer) AuthorizeRepository(_ context.Context, repoIDOrName, action string) error {
+	return a.check(repoIDOrName + ":" + action)
+}
+
+func (a *stubAuthorizer) AuthorizeScope(_ context.Context, access string) error {
+	return a.check("scope:" + access)
+}
+
+// newAuthEngine 挂载认证中间件与若干返回调用者用户名的路由
+func newAuthEngine(h *AuthHandler) *gin.Engine {
+	whoami := func(c *gin.Context) {
+		username := "-"
+		if principal, ok := auth.PrincipalFromContext(c.Request.Context()); ok {
+			username = principal.Username
+		}
+		c.String(http.StatusOK, username)
+	}
+	engine := gin.New()
+	engine.Use(h.Authenticate)
+	engine.GET("/health", whoami)
+	engine.GET("/v2/", whoami)
+	engine.GET("/api/v1/repositories", whoami)
+	engine.GET("/api/v1/repositories/:id", whoami)
+	engine.HEAD("/api/v1/repositories/:id", whoami)
+	engine.PUT("/api/v1/repositories/:id", whoami)
+	engine.GET("/api/v1/users/:id", whoami)
+	engine.GET("/api/v1/settings", whoami)
+	engine.GET("/api/v1/auth/me", h.Me)
+	return engine
+}
+
+func TestAuthHandler_Authenticate(t *testing.T) {
+	allowed := map[string]bool{
+		"scope:" + auth.AccessRead:  true,
+		"scope:" + auth.AccessAdmin: true,
+		"libs:" + auth.ActionRead:   true,
+		"scope:" + auth.AccessAny:   true,
+	}
+	tests := []struct {
+		name          string
+		anonymous     bool
+		method        string
+		path          string
+		header        string
+		wantCode      int
+		wantUser      string
+		wantChallenge bool
+		wantChecked   string
+	}{
+		{name: "public_path", method: http.MethodGet, path: "/health", wantCode: http.StatusOK, wantUser: "-"},
+		{name: "registry_authenticates_itself", method: http.MethodGet, path: "/v2/", wantCode: http.StatusOK, wantUser: "-"},
+		{name: "missing_credentials", method: http.MethodGet, path: "/api/v1/repositories", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "anonymous_read", anonymous: true, method: http.MethodGet, path: "/api/v1/repositories", wantCode: http.StatusOK, wantUser: "anonymous", wantChecked: "scope:" + auth.AccessRead},
+		{name: "anonymous_head", anonymous: true, method: http.MethodHead, path: "/api/v1/repositories/libs", wantCode: http.StatusOK, wantChecked: "libs:" + auth.ActionRead},
+		{name: "anonymous_write_challenged", anonymous: true, method: http.MethodPut, path: "/api/v1/repositories/libs", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "anonymous_other_prefix_challenged", anonymous: true, method: http.MethodGet, path: "/api/v1/users/u1", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "anonymous_prefix_lookalike_challenged", anonymous: true, method: http.MethodGet, path: "/api/v1/repositories-x", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "bearer", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Bearer good-token", wantCode: http.StatusOK, wantUser: "alice", wantChecked: "libs:" + auth.ActionRead},
+		{name: "bearer_case_insensitive", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "bearer good-token", wantCode: http.StatusOK, wantUser: "alice"},
+		{name: "bearer_invalid", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Bearer forged", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "bearer_empty", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Bearer ", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "basic", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Basic YWxpY2U6c2VjcmV0", wantCode: http.StatusOK, wantUser: "alice"},
+		{name: "basic_wrong_password", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Basic YWxpY2U6d3Jvbmc=", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "basic_malformed", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Basic !!!", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "basic_locked_forbidden", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Basic bG9ja2VkOnNlY3JldA==", wantCode: http.StatusForbidden},
+		{name: "unsupported_scheme", method: http.MethodGet, path: "/api/v1/repositories/libs", header: "Digest abc", wantCode: http.StatusUnauthorized, wantChallenge: true},
+		{name: "repository_action_denied", method: http.MethodPut, path: "/api/v1/repositories/libs", header: "Bearer good-token", wantCode: http.StatusForbidden, wantChecked: "libs:" + auth.ActionAdmin},
+		{name: "self_needs_admin_scope_only", method: http.MethodGet, path: "/api/v1/users/alice", header: "Bearer good-token", wantCode: http.StatusOK, wantUser: "alice", wantChecked: "scope:" + auth.AccessAdmin},
+		{name: "other_user_needs_permission", method: http.MethodGet, path: "/api/v1/users/bob", header: "Bearer good-token", wantCode: http.StatusForbidden, wantChecked: auth.PermissionUsersManage},
+		{name: "unlisted_route_needs_all", method: http.MethodGet, path: "/api/v1/settings", header: "Bearer good-token", wantCode: http.StatusForbidden, wantChecked: auth.PermissionAll},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizer := &stubAuthorizer{allowed: allowed}
+			h := NewAuthHandler(testLogger(), &stubAuthService{anonymous: tt.anonymous}, &stubUserService{}, authorizer)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			newAuthEngine(h).ServeHTTP(recorder, req)
+
+			require.Equal(t, tt.wantCode, recorder.Code, recorder.Body.String())
+			if tt.wantUser != "" {
+				assert.Equal(t, tt.wantUser, recorder.Body.String())
+			}
+			if tt.wantChallenge {
+				assert.Equal(t, authChallenges, recorder.Header().Values("WWW-Authenticate"))
+			} else {
+				assert.Empty(t, recorder.Header().Values("WWW-Authenticate"))
+			}
+			if tt.wantChecked != "" {
+				assert.Equal(t, tt.wantChecked, authorizer.checked)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Me(t *testing.T) {
+	h := NewAuthHandler(testLogger(), &stubAuthService{anonymous: true}, &stubUserService{}, &stubAuthorizer{allowed: map[string]bool{"scope:" + auth.AccessAny: true}})
+
+	t.Run("authenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer good-token")
+		recorder := httptest.NewRecorder()
+		newAuthEngine(h).ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Contains(t, dataOf(t, recorder), `"username":"u1"`)
+		assert.NotContains(t, recorder.Body.String(), passwordHash)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		recorder := serve(t, http.MethodGet, "/me", withPrincipal(auth.AnonymousPrincipal, h.Me), httptest.NewRequest(http.MethodGet, "/me", nil))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, authChallenges, recorder.Header().Values("WWW-Authenticate"))
+	})
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "success", body: `{"username":"alice","password":"secret"}`, wantCode: http.StatusOK},
+		{name: "wrong_password", body: `{"username":"alice","password":"wrong"}`, wantCode: http.StatusUnauthorized},
+		{name: "missing_password", body: `{"username":"alice"}`, wantCode: http.StatusBadRequest},
+		{name: "malformed", body: `{`, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubAuthService{}
+			h := NewAuthHandler(testLogger(), stub, &stubUserService{}, &stubAuthorizer{})
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body))
+			recorder := serve(t, http.MethodPost, "/login", h.Login, req)
+			require.Equal(t, tt.wantCode, recorder.Code, recorder.Body.String())
+			if tt.wantCode == http.StatusOK {
+				assert.Contains(t, dataOf(t, recorder), `"access_token":"good-token"`)
+				assert.Equal(t, "alice", stub.login.Username)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_OIDCLogin(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		forwarded string
+		wantCode  int
+		wantSec   bool
+	}{
+		{name: "redirects_to_issuer", path: "/login", wantCode: http.StatusFound},
+		{name: "secure_cookie_behind_tls_proxy", path: "/login?redirect=/ui/", forwarded: "https", wantCode: http.StatusFound, wantSec: true},
+		{name: "open_redirect_rejected", path: "/login?redirect=https://evil.example.com", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAuthHandler(testLogger(), &stubAuthService{}, &stubUserService{}, &stubAuthorizer{})
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwarded)
+			}
+			recorder := serve(t, http.MethodGet, "/login", h.OIDCLogin, req)
+			require.Equal(t, tt.wantCode, recorder.Code, recorder.Body.String())
+			if tt.wantCode != http.StatusFound {
+				assert.Empty(t, recorder.Result().Cookies())
+				return
+			}
+			assert.Equal(t, "https://id.example.com/authorize?state=s1", recorder.Header().Get("Location"))
+			cookies := recorder.Result().Cookies()
+			require.Len(t, cookies, 1)
+			cookie := cookies[0]
+			assert.Equal(t, oidcStateCookie, cookie.Name)
+			assert.Equal(t, "signed-state", cookie.Value)
+			assert.Equal(t, oidcStateCookiePath, cookie.Path)
+			assert.Equal(t, 600, cookie.MaxAge)
+			assert.True(t, cookie.HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+			assert.Equal(t, tt.wantSec, cookie.Secure)
+		})
+	}
+}
+
+func TestAuthHandler_OIDCCallback(t *testing.T) {
+	callback := func(stub *stubAuthService, path string, cookie bool) *httptest.ResponseRecorder {
+		h := NewAuthHandler(testLogger(), stub, &stubUserService{}, &stubAuthorizer{})
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if cookie {
+			req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: "signed-state"})
+		}
+		return serve(t, http.MethodGet, "/callback", h.OIDCCallback, req)
+	}
+	// assertCleared 回调无论成功与否都清除登录状态 Cookie，避免重放
+	assertCleared := func(t *testing.T, recorder *httptest.ResponseRecorder) {
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, oidcStateCookie, cookies[0].Name)
+		assert.Empty(t, cookies[0].Value)
+		assert.Negative(t, cookies[0].MaxAge)
+	}
+
+	t.Run("returns_tokens", func(t *testing.T) {
+		stub := &stubAuthService{}
+		recorder := callback(stub, "/callback?code=c1&state=s1", true)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "signed-state", stub.oidcState)
+		assert.Contains(t, dataOf(t, recorder), `"access_token":"good-token"`)
+		assertCleared(t, recorder)
+	})
+
+	t.Run("redirects_with_fragment", func(t *testing.T) {
+		recorder := callback(&stubAuthService{oidcRedirect: "/ui/repositories"}, "/callback?code=c1&state=s1", true)
+		require.Equal(t, http.StatusFound, recorder.Code, recorder.Body.String())
+		location, err := url.Parse(recorder.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "/ui/repositories", location.Path)
+		assert.Empty(t, location.RawQuery, "tokens never appear in the query string")
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err)
+		assert.Equal(t, "good-token", fragment.Get("access_token"))
+		assert.Equal(t, "refresh-token", fragment.Get("refresh_token"))
+		assert.Equal(t, "Bearer", fragment.Get("token_type"))
+		assert.Equal(t, "900", fragment.Get("expires_in"))
+		assertCleared(t, recorder)
+	})
+
+	t.Run("missing_cookie", func(t *testing.T) {
+		stub := &stubAuthService{}
+		recorder := callback(stub, "/callback?code=c1&state=s1", false)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Empty(t, stub.oidcState)
+		assertCleared(t, recorder)
+	})
+
+	t.Run("state_mismatch", func(t *testing.T) {
+		recorder := callback(&stubAuthService{}, "/callback?code=c1&state=other", true)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assertCleared(t, recorder)
+	})
+}
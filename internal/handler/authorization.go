@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/service"
+)
+
+// routeRule 路由所需的权限
+// action 非空时校验对 param 指定仓库的操作权限；permission 非空时校验系统权限，self 参数为调用者本人时免除；
+// 两者都为空时只校验个人访问令牌范围 scope
+type routeRule struct {
+	scope      string
+	param      string
+	action     string
+	permission string
+	self       string
+}
+
+// routeRules 按 "方法 路由" 索引的授权规则，HEAD 与 GET 相同，未列出的路由需要 * 权限
+var routeRules = map[string]routeRule{
+	"GET /api/v1/auth/me":      {scope: auth.AccessAny},
+	"POST /api/v1/auth/logout": {scope: auth.AccessAny},
+
+	"GET /api/v1/tokens":                                {scope: auth.AccessAny},
+	"POST /api/v1/tokens":                               {scope: auth.AccessAdmin},
+	"DELETE /api/v1/tokens/:id":                         {scope: auth.AccessAny},
+	"GET /api/v1/users/:id/tokens":                      {permission: auth.PermissionUsersManage, self: "id"},
+	"DELETE /api/v1/users/:id/tokens/:tokenId":          {permission: auth.PermissionUsersManage, self: "id"},
+	"GET /api/v1/users":                                 {permission: auth.PermissionUsersManage},
+	"POST /api/v1/users":                                {permission: auth.PermissionUsersManage},
+	"GET /api/v1/users/:id":                             {permission: auth.PermissionUsersManage, self: "id"},
+	"PUT /api/v1/users/:id":                             {permission: auth.PermissionUsersManage},
+	"DELETE /api/v1/users/:id":                          {permission: auth.PermissionUsersManage},
+	"PUT /api/v1/users/:id/password":                    {permission: auth.PermissionUsersManage, self: "id"},
+	"PUT /api/v1/users/:id/roles":                       {permission: auth.PermissionUsersManage},
+	"GET /api/v1/roles":                                 {permission: auth.PermissionUsersManage},
+	"POST /api/v1/roles":                                {permission: auth.PermissionUsersManage},
+	"GET /api/v1/roles/:id":                             {permission: auth.PermissionUsersManage},
+	"PUT /api/v1/roles/:id":                             {permission: auth.PermissionUsersManage},
+	"DELETE /api/v1/roles/:id":                          {permission: auth.PermissionUsersManage},
+	"GET /api/v1/search":                                {scope: auth.AccessRead},
+	"POST /api/v1/search/properties":                    {scope: auth.AccessWrite},
+	"GET /api/v1/repositories":                          {scope: auth.AccessRead},
+	"POST /api/v1/repositories":                         {permission: auth.PermissionRepositoriesManage},
+	"GET /api/v1/repositories/:id":                      {param: "id", action: auth.ActionRead},
+	"PUT /api/v1/repositories/:id":                      {param: "id", action: auth.ActionAdmin},
+	"DELETE /api/v1/repositories/:id":                   {permission: auth.PermissionRepositoriesManage},
+	"GET /api/v1/repositories/:id/artifacts":            {param: "id", action: auth.ActionRead},
+	"POST /api/v1/repositories/:id/artifacts":           {param: "id", action: auth.ActionWrite},
+	"GET /api/v1/repositories/:id/artifacts/*path":      {param: "id", action: auth.ActionRead},
+	"PUT /api/v1/repositories/:id/artifacts/*path":      {param: "id", action: auth.ActionWrite},
+	"DELETE /api/v1/repositories/:id/artifacts/*path":   {param: "id", action: auth.ActionDelete},
+	"GET /api/v1/repositories/:id/latest":               {param: "id", action: auth.ActionRead},
+	"GET /api/v1/repositories/:id/routing-test":         {param: "id", action: auth.ActionRead},
+	"POST /api/v1/repositories/:id/promote":             {param: "id", action: auth.ActionRead},
+	"GET /api/v1/repositories/:id/properties/*path":     {param: "id", action: auth.ActionRead},
+	"PUT /api/v1/repositories/:id/properties/*path":     {param: "id", action: auth.ActionWrite},
+	"DELETE /api/v1/repositories/:id/properties/*path":  {param: "id", action: auth.ActionWrite},
+	"GET /api/v1/repositories/:id/signatures/*path":     {param: "id", action: auth.ActionRead},
+	"POST /api/v1/repositories/:id/signatures/*path":    {param: "id", action: auth.ActionWrite},
+	"POST /api/v1/repositories/:id/uploads":             {param: "id", action: auth.ActionWrite},
+	"GET /api/v1/repositories/:id/uploads/:uploadId":    {param: "id", action: auth.ActionRead},
+	"PATCH /api/v1/repositories/:id/uploads/:uploadId":  {param: "id", action: auth.ActionWrite},
+	"PUT /api/v1/repositories/:id/uploads/:uploadId":    {param: "id", action: auth.ActionWrite},
+	"DELETE /api/v1/repositories/:id/uploads/:uploadId": {param: "id", action: auth.ActionWrite},
+	"PUT /api/v1/staging/deploy/:target/*path":          {param: "target", action: auth.ActionWrite},
+	"GET /api/v1/staging/repositories":                  {permission: auth.PermissionStagingRead},
+	"POST /api/v1/staging/repositories":                 {permission: auth.PermissionStagingManage},
+	"GET /api/v1/staging/repositories/:id":              {permission: auth.PermissionStagingRead},
+	"POST /api/v1/staging/repositories/:id/close":       {permission: auth.PermissionStagingManage},
+	"POST /api/v1/staging/repositories/:id/release":     {permission: auth.PermissionStagingManage},
+	"POST /api/v1/staging/repositories/:id/drop":        {permission: auth.PermissionStagingManage},
+	"GET /api/v1/cleanup-policies":                      {permission: auth.PermissionCleanupRead},
+	"POST /api/v1/cleanup-policies":                     {permission: auth.PermissionCleanupManage},
+	"GET /api/v1/cleanup-policies/:name":                {permission: auth.PermissionCleanupRead},
+	"PUT /api/v1/cleanup-policies/:name":                {permission: auth.PermissionCleanupManage},
+	"DELETE /api/v1/cleanup-policies/:name":             {permission: auth.PermissionCleanupManage},
+	"GET /api/v1/cleanup-policies/:name/preview":        {permission: auth.PermissionCleanupRead},
+	"POST /api/v1/cleanup-policies/:name/run":           {permission: auth.PermissionCleanupManage},
+	"GET /api/v1/trusted-keys":                          {permission: auth.PermissionSecurityRead},
+	"POST /api/v1/trusted-keys":                         {permission: auth.PermissionSecurityManage},
+	"GET /api/v1/trusted-keys/:name":                    {permission: auth.PermissionSecurityRead},
+	"DELETE /api/v1/trusted-keys/:name":                 {permission: auth.PermissionSecurityManage},
+	"GET /api/v1/signing-keys":                          {permission: auth.PermissionSecurityRead},
+	"POST /api/v1/signing-keys":                         {permission: auth.PermissionSecurityManage},
+	"GET /api/v1/signing-keys/:name":                    {permission: auth.PermissionSecurityRead},
+	"DELETE /api/v1/signing-keys/:name":                 {permission: auth.PermissionSecurityManage},
+	"POST /api/v1/signing-keys/:name/activate":          {permission: auth.PermissionSecurityManage},
+	"GET /api/v1/audit":                                 {permission: auth.PermissionAuditRead},
+	"GET /api/v1/audit/export":                          {permission: auth.PermissionAuditRead},
+	"GET /api/v1/task-types":                            {permission: auth.PermissionTasksRead},
+	"GET /api/v1/tasks":                                 {permission: auth.PermissionTasksRead},
+	"POST /api/v1/tasks":                                {permission: auth.PermissionTasksManage},
+	"GET /api/v1/tasks/:id":                             {permission: auth.PermissionTasksRead},
+	"PUT /api/v1/tasks/:id":                             {permission: auth.PermissionTasksManage},
+	"DELETE /api/v1/tasks/:id":                          {permission: auth.PermissionTasksManage},
+	"POST /api/v1/tasks/:id/run":                        {permission: auth.PermissionTasksManage},
+	"POST /api/v1/tasks/:id/cancel":                     {permission: auth.PermissionTasksManage},
+	"GET /api/v1/tasks/:id/runs":                        {permission: auth.PermissionTasksRead},
+	"GET /api/v1/tasks/:id/runs/:runId":                 {permission: auth.PermissionTasksRead},
+}
+
+// authorizeRoute 按当前路由的规则校验调用者
+func authorizeRoute(c *gin.Context, authorizer service.Authorizer, principal *auth.Principal) error {
+	method := c.Request.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	rule, ok := routeRules[method+" "+c.FullPath()]
+	if !ok {
+		rule = routeRule{permission: auth.PermissionAll}
+	}
+
+	ctx := c.Request.Context()
+	switch {
+	case rule.action != "":
+		return authorizer.AuthorizeRepository(ctx, c.Param(rule.param), rule.action)
+	case rule.permission != "":
+		if rule.self != "" && isSelf(principal, c.Param(rule.self)) {
+			return authorizer.AuthorizeScope(ctx, auth.AccessAdmin)
+		}
+		return authorizer.Authorize(ctx, rule.permission)
+	}
+	return authorizer.AuthorizeScope(ctx, rule.scope)
+}
+
+// isSelf 判断路由参数（用户ID或用户名）是否为调用者本人
+func isSelf(principal *auth.Principal, idOrName string) bool {
+	return !principal.Anonymous && (idOrName == principal.UserID || idOrName == principal.Username)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// CleanupHandler 处理清理策略相关的 HTTP 请求
+type CleanupHandler struct {
+	logger         *slog.Logger
+	cleanupService service.CleanupService
+}
+
+// NewCleanupHandler 创建新的清理策略处理器
+func NewCleanupHandler(logger *slog.Logger, cleanupService service.CleanupService) *CleanupHandler {
+	return &CleanupHandler{
+		logger:         logger,
+		cleanupService: cleanupService,
+	}
+}
+
+// RegisterRoutes 注册清理策略路由
+func (h *CleanupHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/cleanup-policies", h.ListPolicies)
+	web.RegisterApiHandle(http.MethodPost, "/cleanup-policies", h.CreatePolicy)
+	web.RegisterApiHandle(http.MethodGet, "/cleanup-policies/:name", h.GetPolicy)
+	web.RegisterApiHandle(http.MethodPut, "/cleanup-policies/:name", h.UpdatePolicy)
+	web.RegisterApiHandle(http.MethodDelete, "/cleanup-policies/:name", h.DeletePolicy)
+	web.RegisterApiHandle(http.MethodGet, "/cleanup-policies/:name/preview", h.Preview)
+	web.RegisterApiHandle(http.MethodPost, "/cleanup-policies/:name/run", h.Run)
+}
+
+// ListPolicies 列出清理策略
+func (h *CleanupHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.cleanupService.ListPolicies(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, policies)
+}
+
+// CreatePolicy 创建清理策略
+func (h *CleanupHandler) CreatePolicy(c *gin.Context) {
+	var req dto.CleanupPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	policy, err := h.cleanupService.CreatePolicy(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "cleanup_policy:"+policy.Name)
+	web.Created(c, policy)
+}
+
+// GetPolicy 获取清理策略
+func (h *CleanupHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.cleanupService.GetPolicy(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, policy)
+}
+
+// UpdatePolicy 更新清理策略
+func (h *CleanupHandler) UpdatePolicy(c *gin.Context) {
+	var req dto.CleanupPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	policy, err := h.cleanupService.UpdatePolicy(c.Request.Context(), c.Param("name"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, policy)
+}
+
+// DeletePolicy 删除清理策略
+func (h *CleanupHandler) DeletePolicy(c *gin.Context) {
+	if err := h.cleanupService.DeletePolicy(c.Request.Context(), c.Param("name")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// Preview 预览清理策略将删除的制品，不做任何修改
+func (h *CleanupHandler) Preview(c *gin.Context) {
+	h.report(c, h.cleanupService.Preview)
+}
+
+// Run 立即执行清理策略
+func (h *CleanupHandler) Run(c *gin.Context) {
+	h.report(c, h.cleanupService.Run)
+}
+
+// report 解析清理范围并返回预览或执行报告
+func (h *CleanupHandler) report(c *gin.Context, fn func(ctx context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error)) {
+	var query dto.CleanupQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	report, err := fn(c.Request.Context(), c.Param("name"), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, report)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubCleanupService 记录调用参数的清理策略服务
+type stubCleanupService struct {
+	service.CleanupService
+	name  string
+	req   *dto.CleanupPolicyRequest
+	query *dto.CleanupQuery
+}
+
+func (s *stubCleanupService) CreatePolicy(_ context.Context, req *dto.CleanupPolicyRequest) (*model.CleanupPolicy, error) {
+	if req.Name == "exists" {
+		return nil, errs.Conflict("cleanup policy %q already exists", req.Name)
+	}
+	s.req = req
+	return &model.CleanupPolicy{Name: req.Name, Criteria: req.Criteria}, nil
+}
+
+func (s *stubCleanupService) UpdatePolicy(_ context.Context, name string, req *dto.CleanupPolicyRequest) (*model.CleanupPolicy, error) {
+	s.name, s.req = name, req
+	return &model.CleanupPolicy{Name: name, Criteria: req.Criteria}, nil
+}
+
+func (s *stubCleanupService) DeletePolicy(_ context.Context, name string) error {
+	if name == "attached" {
+		return errs.Conflict("cleanup policy %q is used by repository %q", name, "libs-release")
+	}
+	s.name = name
+	return nil
+}
+
+func (s *stubCleanupService) Preview(_ context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error) {
+	s.name, s.query = name, query
+	return &dto.CleanupReport{DryRun: true, Results: []*dto.CleanupResult{{Policy: name, Repository: query.Repository, Count: 2}}}, nil
+}
+
+func (s *stubCleanupService) Run(_ context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error) {
+	if query.Repository == "maven-public" {
+		return nil, errs.InvalidArgument("repository %q is a group repository, clean up its members", query.Repository)
+	}
+	s.name, s.query = name, query
+	return &dto.CleanupReport{Results: []*dto.CleanupResult{{Policy: name, Repository: query.Repository, Deleted: 2}}}, nil
+}
+
+func TestCleanupHandler(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		stub := &stubCleanupService{}
+		h := NewCleanupHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPost, "/cleanup-policies", strings.NewReader(`{"name":"snapshots","criteria":{"prerelease_only":true,"keep_last_versions":3}}`))
+		recorder := serve(t, http.MethodPost, "/cleanup-policies", h.CreatePolicy, req)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		require.NotNil(t, stub.req)
+		assert.True(t, stub.req.Criteria.PrereleaseOnly)
+		assert.Equal(t, 3, stub.req.Criteria.KeepLastVersions)
+	})
+
+	t.Run("create_conflict", func(t *testing.T) {
+		h := NewCleanupHandler(testLogger(), &stubCleanupService{})
+		req := httptest.NewRequest(http.MethodPost, "/cleanup-policies", strings.NewReader(`{"name":"exists"}`))
+		recorder := serve(t, http.MethodPost, "/cleanup-policies", h.CreatePolicy, req)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("create_malformed", func(t *testing.T) {
+		h := NewCleanupHandler(testLogger(), &stubCleanupService{})
+		req := httptest.NewRequest(http.MethodPost, "/cleanup-policies", strings.NewReader(`{"name":`))
+		recorder := serve(t, http.MethodPost, "/cleanup-policies", h.CreatePolicy, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		stub := &stubCleanupService{}
+		h := NewCleanupHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPut, "/cleanup-policies/snapshots", strings.NewReader(`{"criteria":{"older_than_days":30}}`))
+		recorder := serve(t, http.MethodPut, "/cleanup-policies/:name", h.UpdatePolicy, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "snapshots", stub.name)
+		assert.Equal(t, 30, stub.req.Criteria.OlderThanDays)
+	})
+
+	t.Run("delete_attached", func(t *testing.T) {
+		h := NewCleanupHandler(testLogger(), &stubCleanupService{})
+		req := httptest.NewRequest(http.MethodDelete, "/cleanup-policies/attached", nil)
+		recorder := serve(t, http.MethodDelete, "/cleanup-policies/:name", h.DeletePolicy, req)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		stub := &stubCleanupService{}
+		h := NewCleanupHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodGet, "/cleanup-policies/snapshots/preview?repository=libs-release", nil)
+		recorder := serve(t, http.MethodGet, "/cleanup-policies/:name/preview", h.Preview, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "snapshots", stub.name)
+		assert.Equal(t, "libs-release", stub.query.Repository)
+		assert.Contains(t, dataOf(t, recorder), `"dry_run":true`)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		stub := &stubCleanupService{}
+		h := NewCleanupHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPost, "/cleanup-policies/snapshots/run", nil)
+		recorder := serve(t, http.MethodPost, "/cleanup-policies/:name/run", h.Run, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Empty(t, stub.query.Repository, "no repository runs against attached repositories")
+	})
+
+	t.Run("run_group", func(t *testing.T) {
+		h := NewCleanupHandler(testLogger(), &stubCleanupService{})
+		req := httptest.NewRequest(http.MethodPost, "/cleanup-policies/snapshots/run?repository=maven-public", nil)
+		recorder := serve(t, http.MethodPost, "/cleanup-policies/:name/run", h.Run, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
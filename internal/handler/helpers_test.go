@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testLogger 丢弃输出的日志记录器
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// testConfig 返回处理器测试使用的配置，上传与分块大小限制均为 limit 字节
+func testConfig(limit int64) *config.Config {
+	cfg := &config.Config{}
+	cfg.Server.MaxUploadSize = limit
+	cfg.Server.MaxChunkSize = limit
+	return cfg
+}
+
+// serve 将 handler 挂载到 route 上并执行一次请求
+func serve(t *testing.T, method, route string, handler gin.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	engine := gin.New()
+	engine.Handle(method, route, handler)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// dataOf 返回统一响应结构中 data 字段的原始 JSON
+func dataOf(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return string(body.Data)
+}
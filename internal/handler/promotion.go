@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// PromotionHandler 处理制品晋级请求
+type PromotionHandler struct {
+	logger           *slog.Logger
+	promotionService service.PromotionService
+}
+
+// NewPromotionHandler 创建新的制品晋级处理器
+func NewPromotionHandler(logger *slog.Logger, promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		logger:           logger,
+		promotionService: promotionService,
+	}
+}
+
+// RegisterRoutes 注册制品晋级路由（嵌套在仓库路由下）
+func (h *PromotionHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodPost, "/repositories/:id/promote", h.Promote)
+}
+
+// Promote 将制品从当前仓库复制或移动到目标仓库
+func (h *PromotionHandler) Promote(c *gin.Context) {
+	var req dto.PromoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.promotionService.Promote(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, result)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubPromotionService 返回部分失败结果的晋级服务
+type stubPromotionService struct {
+	service.PromotionService
+	req *dto.PromoteRequest
+}
+
+func (s *stubPromotionService) Promote(_ context.Context, repo string, req *dto.PromoteRequest) (*dto.PromoteResult, error) {
+	if repo == "central" {
+		return nil, errs.InvalidArgument("repository %q is a proxy repository", repo)
+	}
+	s.req = req
+	return &dto.PromoteResult{Source: repo, Target: req.Target, Failed: []*dto.PromoteFailure{{Path: "a.pom", Error: "disk read error"}}}, nil
+}
+
+func TestPromotionHandler_Promote(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       string
+		body       string
+		wantStatus int
+	}{
+		{name: "partial_failure_reported", repo: "libs-staging", body: `{"target":"libs-release","path":"a.jar","move":true}`, wantStatus: http.StatusOK},
+		{name: "target_required", repo: "libs-staging", body: `{"path":"a.jar"}`, wantStatus: http.StatusBadRequest},
+		{name: "proxy_source", repo: "central", body: `{"target":"libs-release","path":"a.jar"}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubPromotionService{}
+			h := NewPromotionHandler(testLogger(), stub)
+			req := httptest.NewRequest(http.MethodPost, "/repositories/"+tt.repo+"/promote", strings.NewReader(tt.body))
+			recorder := serve(t, http.MethodPost, "/repositories/:id/promote", h.Promote, req)
+			require.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			if tt.wantStatus == http.StatusOK {
+				assert.True(t, stub.req.Move)
+				assert.JSONEq(t, `{"source":"libs-staging","target":"libs-release","move":false,"artifacts":null,
+					"failed":[{"path":"a.pom","error":"disk read error"}]}`, dataOf(t, recorder))
+			}
+		})
+	}
+}
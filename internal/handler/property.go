@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// PropertyHandler 处理制品属性相关的 HTTP 请求
+type PropertyHandler struct {
+	logger          *slog.Logger
+	propertyService service.PropertyService
+}
+
+// NewPropertyHandler 创建新的制品属性处理器
+func NewPropertyHandler(logger *slog.Logger, propertyService service.PropertyService) *PropertyHandler {
+	return &PropertyHandler{
+		logger:          logger,
+		propertyService: propertyService,
+	}
+}
+
+// RegisterRoutes 注册制品属性路由
+func (h *PropertyHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/properties/*path", h.GetProperties)
+	web.RegisterApiHandle(http.MethodPut, "/repositories/:id/properties/*path", h.SetProperties)
+	web.RegisterApiHandle(http.MethodDelete, "/repositories/:id/properties/*path", h.DeleteProperties)
+	web.RegisterApiHandle(http.MethodPost, "/search/properties", h.BulkUpdateProperties)
+}
+
+// GetProperties 获取制品属性
+func (h *PropertyHandler) GetProperties(c *gin.Context) {
+	properties, err := h.propertyService.Get(c.Request.Context(), c.Param("id"), c.Param("path"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, properties)
+}
+
+// SetProperties 设置制品属性，请求体为属性键值对，未给出的属性保持不变
+func (h *PropertyHandler) SetProperties(c *gin.Context) {
+	var properties map[string]string
+	if err := c.ShouldBindJSON(&properties); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	updated, err := h.propertyService.Set(c.Request.Context(), c.Param("id"), c.Param("path"), properties)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, updated)
+}
+
+// DeleteProperties 删除 key 查询参数指定的属性，key 可重复
+func (h *PropertyHandler) DeleteProperties(c *gin.Context) {
+	updated, err := h.propertyService.Delete(c.Request.Context(), c.Param("id"), c.Param("path"), c.QueryArray("key"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, updated)
+}
+
+// BulkUpdateProperties 对搜索条件匹配的制品批量设置和删除属性
+func (h *PropertyHandler) BulkUpdateProperties(c *gin.Context) {
+	var req dto.BulkPropertiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.propertyService.BulkUpdate(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, result)
+}
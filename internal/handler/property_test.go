@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubPropertyService 记录调用参数的属性服务
+type stubPropertyService struct {
+	service.PropertyService
+	path  string
+	set   map[string]string
+	keys  []string
+	query *dto.BulkPropertiesRequest
+}
+
+func (s *stubPropertyService) Get(_ context.Context, _, rawPath string) (map[string]string, error) {
+	s.path = rawPath
+	return map[string]string{"qa": "passed"}, nil
+}
+
+func (s *stubPropertyService) Set(_ context.Context, _, rawPath string, properties map[string]string) (map[string]string, error) {
+	s.path, s.set = rawPath, properties
+	return properties, nil
+}
+
+func (s *stubPropertyService) Delete(_ context.Context, _, rawPath string, keys []string) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, errs.InvalidArgument("no property keys to delete")
+	}
+	s.path, s.keys = rawPath, keys
+	return map[string]string{}, nil
+}
+
+func (s *stubPropertyService) BulkUpdate(_ context.Context, req *dto.BulkPropertiesRequest) (*dto.BulkPropertiesResult, error) {
+	s.query = req
+	return &dto.BulkPropertiesResult{Matched: 3, Updated: 2}, nil
+}
+
+func TestPropertyHandler(t *testing.T) {
+	const route = "/repositories/:id/properties/*path"
+	const url = "/repositories/releases/properties/com/x/lib/1.0/lib-1.0.jar"
+
+	t.Run("get", func(t *testing.T) {
+		stub := &stubPropertyService{}
+		h := NewPropertyHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, route, h.GetProperties, httptest.NewRequest(http.MethodGet, url, nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Contains(t, recorder.Body.String(), `"qa":"passed"`)
+		assert.Equal(t, "/com/x/lib/1.0/lib-1.0.jar", stub.path)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		stub := &stubPropertyService{}
+		h := NewPropertyHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPut, url, strings.NewReader(`{"build.number":"42"}`))
+		recorder := serve(t, http.MethodPut, route, h.SetProperties, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, map[string]string{"build.number": "42"}, stub.set)
+	})
+
+	t.Run("set_non_string_value", func(t *testing.T) {
+		h := NewPropertyHandler(testLogger(), &stubPropertyService{})
+		req := httptest.NewRequest(http.MethodPut, url, strings.NewReader(`{"build.number":42}`))
+		recorder := serve(t, http.MethodPut, route, h.SetProperties, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		stub := &stubPropertyService{}
+		h := NewPropertyHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodDelete, url+"?key=qa&key=git.commit", nil)
+		recorder := serve(t, http.MethodDelete, route, h.DeleteProperties, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, []string{"qa", "git.commit"}, stub.keys)
+
+		recorder = serve(t, http.MethodDelete, route, h.DeleteProperties, httptest.NewRequest(http.MethodDelete, url, nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("bulk", func(t *testing.T) {
+		stub := &stubPropertyService{}
+		h := NewPropertyHandler(testLogger(), stub)
+		body := `{"query":{"name":"lib","property":["qa=pending"]},"set":{"qa":"passed"},"delete":["tmp"]}`
+		req := httptest.NewRequest(http.MethodPost, "/search/properties", strings.NewReader(body))
+		recorder := serve(t, http.MethodPost, "/search/properties", h.BulkUpdateProperties, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.JSONEq(t, `{"matched":3,"updated":2}`, dataOf(t, recorder))
+		assert.Equal(t, "lib", stub.query.Query.Name)
+		assert.Equal(t, map[string]string{"qa": "passed"}, stub.query.Set)
+		assert.Equal(t, []string{"tmp"}, stub.query.Delete)
+
+		req = httptest.NewRequest(http.MethodPost, "/search/properties", strings.NewReader("{"))
+		recorder = serve(t, http.MethodPost, "/search/properties", h.BulkUpdateProperties, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
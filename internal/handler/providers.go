@@ -10,15 +10,87 @@ package handler
 
 import (
 	"github.com/google/wire"
+
+	"github.com/laolishu/go-nexus/core/web"
 )
 
 // ProviderSet 处理器层的 Wire 提供者集合
 var ProviderSet = wire.NewSet(
+	NewAuditHandler,
+	NewAuthHandler,
 	NewRepositoryHandler,
 	NewArtifactHandler,
+	NewUploadHandler,
+	NewSearchHandler,
+	NewPropertyHandler,
+	NewPromotionHandler,
+	NewStagingHandler,
+	NewCleanupHandler,
+	NewSignatureHandler,
+	NewSigningHandler,
+	NewTaskHandler,
+	NewUserHandler,
+	NewTokenHandler,
+	NewRegistryHandler,
+	NewRouteRegistrars,
 )
 
 var HandlerSet = wire.NewSet(
+	NewAuditHandler,
+	NewAuthHandler,
 	NewRepositoryHandler,
 	NewArtifactHandler,
+	NewUploadHandler,
+	NewSearchHandler,
+	NewPropertyHandler,
+	NewPromotionHandler,
+	NewStagingHandler,
+	NewCleanupHandler,
+	NewSignatureHandler,
+	NewSigningHandler,
+	NewTaskHandler,
+	NewUserHandler,
+	NewTokenHandler,
+	NewRegistryHandler,
+	NewRouteRegistrars,
 )
+
+// NewRouteRegistrars 汇总需要注册路由的处理器，注册顺序即路由注册顺序
+// 审计处理器排在认证处理器之前，其中间件先注册
+func NewRouteRegistrars(
+	auditHandler *AuditHandler,
+	authHandler *AuthHandler,
+	repositoryHandler *RepositoryHandler,
+	artifactHandler *ArtifactHandler,
+	uploadHandler *UploadHandler,
+	searchHandler *SearchHandler,
+	propertyHandler *PropertyHandler,
+	promotionHandler *PromotionHandler,
+	stagingHandler *StagingHandler,
+	cleanupHandler *CleanupHandler,
+	signatureHandler *SignatureHandler,
+	signingHandler *SigningHandler,
+	taskHandler *TaskHandler,
+	userHandler *UserHandler,
+	tokenHandler *TokenHandler,
+	registryHandler *RegistryHandler,
+) []web.RouteRegistrar {
+	return []web.RouteRegistrar{
+		auditHandler,
+		authHandler,
+		repositoryHandler,
+		artifactHandler,
+		uploadHandler,
+		searchHandler,
+		propertyHandler,
+		promotionHandler,
+		stagingHandler,
+		cleanupHandler,
+		signatureHandler,
+		signingHandler,
+		taskHandler,
+		userHandler,
+		tokenHandler,
+		registryHandler,
+	}
+}
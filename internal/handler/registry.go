@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+const (
+	// registryTokenPath Docker 令牌服务路径
+	registryTokenPath = "/api/v1/auth/docker/token"
+	// registryPrefix Docker registry API 路径前缀，由 RegistryHandler 自行认证
+	registryPrefix = "/v2"
+)
+
+// registryMarkers 镜像名称之后的路径段，用于从 /v2 路径中截取镜像名称
+var registryMarkers = []string{"/manifests/", "/blobs/", "/tags/"}
+
+// registryError Docker registry 错误响应
+type registryError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RegistryHandler 实现 Docker registry token 认证：签发令牌并校验 /v2 请求携带的令牌
+// /v2 请求不使用全局认证中间件，未认证或令牌范围不足时返回 Bearer 认证提示，客户端据此到令牌服务换取令牌
+type RegistryHandler struct {
+	logger        *slog.Logger
+	authService   service.AuthService
+	registryToken service.RegistryTokenService
+}
+
+// NewRegistryHandler 创建新的 Docker 仓库认证处理器
+func NewRegistryHandler(logger *slog.Logger, authService service.AuthService, registryToken service.RegistryTokenService) *RegistryHandler {
+	return &RegistryHandler{
+		logger:        logger,
+		authService:   authService,
+		registryToken: registryToken,
+	}
+}
+
+// RegisterRoutes 注册令牌服务与 /v2 路由
+func (h *RegistryHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/auth/docker/token", h.Token)
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		web.RegisterRootHandle(method, registryPrefix+"/*path", h.Registry)
+	}
+}
+
+// Token 签发 Docker 令牌，Basic 凭据的密码可以是账户密码或个人访问令牌，也接受 go-nexus 的 Bearer 令牌
+// 未提供凭据且开启匿名读取时按匿名调用者签发
+func (h *RegistryHandler) Token(c *gin.Context) {
+	var req dto.RegistryTokenRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.fail(c, http.StatusBadRequest, "UNSUPPORTED", err.Error())
+		return
+	}
+
+	var (
+		principal *auth.Principal
+		err       error
+	)
+	ctx := c.Request.Context()
+	header := c.GetHeader("Authorization")
+	scheme, credentials, _ := strings.Cut(header, " ")
+	switch {
+	case header == "":
+		if !h.authService.AnonymousRead() {
+			h.unauthorized(c, "authentication required")
+			return
+		}
+		principal = auth.AnonymousPrincipal
+	case strings.EqualFold(scheme, "Basic"):
+		username, password, ok := c.Request.BasicAuth()
+		if !ok {
+			h.unauthorized(c, "malformed basic credentials")
+			return
+		}
+		principal, err = h.authService.AuthenticatePassword(ctx, username, password)
+	case strings.EqualFold(scheme, "Bearer"):
+		principal, err = h.authService.Authenticate(ctx, strings.TrimSpace(credentials))
+	default:
+		h.unauthorized(c, "unsupported authorization scheme")
+		return
+	}
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	token, err := h.registryToken.Issue(auth.WithPrincipal(ctx, principal), &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
+
+// Registry 校验 /v2 请求携带的令牌，GET/HEAD 需要 pull，DELETE 需要 delete，其余方法需要 push
+// 当前没有 Docker 格式插件，/v2/ 之外的请求认证通过后返回 UNSUPPORTED
+func (h *RegistryHandler) Registry(c *gin.Context) {
+	c.Header("Docker-Distribution-API-Version", "registry/2.0")
+	name := registryName(c.Param("path"))
+	action := "push"
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		action = "pull"
+	case http.MethodDelete:
+		action = "delete"
+	}
+
+	scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		h.challenge(c, name, action, "", "authentication required")
+		return
+	}
+	if err := h.registryToken.Authorize(c.Request.Context(), token, name, action); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUnauthenticated):
+			h.challenge(c, name, action, "invalid_token", err.Error())
+		case errors.Is(err, errs.ErrForbidden):
+			h.challenge(c, name, action, "insufficient_scope", err.Error())
+		default:
+			h.respondError(c, err)
+		}
+		return
+	}
+
+	if c.Param("path") == "/" {
+		c.JSON(http.StatusOK, gin.H{})
+		return
+	}
+	h.fail(c, http.StatusMethodNotAllowed, "UNSUPPORTED", "docker registry API is not implemented")
+}
+
+// challenge 返回 401 与 Bearer 认证提示，name 为空时不带 scope
+func (h *RegistryHandler) challenge(c *gin.Context, name, action, reason, msg string) {
+	value := fmt.Sprintf(`Bearer realm=%q,service=%q`, h.realm(c), h.registryToken.Service())
+	if name != "" {
+		value += fmt.Sprintf(`,scope="repository:%s:%s"`, name, action)
+	}
+	if reason != "" {
+		value += fmt.Sprintf(`,error=%q`, reason)
+	}
+	c.Header("WWW-Authenticate", value)
+	h.fail(c, http.StatusUnauthorized, "UNAUTHORIZED", msg)
+}
+
+// unauthorized 令牌服务认证失败，提示客户端使用 Basic 凭据
+func (h *RegistryHandler) unauthorized(c *gin.Context, msg string) {
+	c.Header("WWW-Authenticate", `Basic realm="go-nexus"`)
+	h.fail(c, http.StatusUnauthorized, "UNAUTHORIZED", msg)
+}
+
+// realm 令牌服务地址，未配置时按请求的协议与主机生成
+func (h *RegistryHandler) realm(c *gin.Context) string {
+	if realm := h.registryToken.Realm(); realm != "" {
+		return realm
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + registryTokenPath
+}
+
+// respondError 按 Docker registry 错误格式响应服务层错误
+func (h *RegistryHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errs.ErrUnauthenticated):
+		h.unauthorized(c, err.Error())
+	case errors.Is(err, errs.ErrForbidden):
+		h.fail(c, http.StatusForbidden, "DENIED", err.Error())
+	case errors.Is(err, errs.ErrInvalidArgument):
+		h.fail(c, http.StatusBadRequest, "UNSUPPORTED", err.Error())
+	case errors.Is(err, errs.ErrUnavailable):
+		h.fail(c, http.StatusServiceUnavailable, "UNAVAILABLE", err.Error())
+	default:
+		h.logger.Error("Request failed",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"error", err,
+		)
+		h.fail(c, http.StatusInternalServerError, "UNKNOWN", "internal server error")
+	}
+}
+
+// fail 返回 Docker registry 格式的错误
+func (h *RegistryHandler) fail(c *gin.Context, status int, code, msg string) {
+	c.AbortWithStatusJSON(status, gin.H{"errors": []registryError{{Code: code, Message: msg}}})
+}
+
+// registryName 从 /v2 之后的路径中截取镜像名称，/v2/ 与 /v2/_catalog 等没有镜像名称的路径返回空
+// 镜像名称可以包含 tags、blobs 等路径段，而标签与摘要不含 /，因此以最后一个标记为界
+func registryName(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	end := -1
+	for _, marker := range registryMarkers {
+		if i := strings.LastIndex(path, marker); i > end {
+			end = i
+		}
+	}
+	if end <= 0 {
+		return ""
+	}
+	return path[:end]
+}
@@ -2,9 +2,14 @@ package handler
 
 import (
 	"log/slog"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/repository/model"
 	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
 )
 
 // RepositoryHandler 处理仓库相关的 HTTP 请求
@@ -21,35 +26,101 @@ func NewRepositoryHandler(logger *slog.Logger, repositoryService service.Reposit
 	}
 }
 
-// ListRepositories 列出所有仓库
+// RegisterRoutes 注册仓库管理路由
+func (h *RepositoryHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/repositories", h.ListRepositories)
+	web.RegisterApiHandle(http.MethodPost, "/repositories", h.CreateRepository)
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id", h.GetRepository)
+	web.RegisterApiHandle(http.MethodPut, "/repositories/:id", h.UpdateRepository)
+	web.RegisterApiHandle(http.MethodDelete, "/repositories/:id", h.DeleteRepository)
+
+	web.RegisterHealthIndicator("proxies", h.proxyHealth)
+}
+
+// proxyHealth 汇总代理仓库的远程状态，存在自动阻断的远程时视为不健康
+// 手动阻断属于预期状态，不影响健康结果
+func (h *RepositoryHandler) proxyHealth(c *gin.Context) (interface{}, bool) {
+	repos, err := h.repositoryService.List(c.Request.Context(), &dto.ListRepositoriesQuery{Type: model.RepositoryTypeProxy})
+	if err != nil {
+		h.logger.Error("Failed to list proxy repositories for health check", "error", err)
+		return gin.H{"error": "failed to list proxy repositories"}, false
+	}
+
+	healthy := true
+	statuses := make(map[string]*model.RemoteStatus, len(repos))
+	for _, repo := range repos {
+		statuses[repo.Name] = repo.RemoteStatus
+		if repo.RemoteStatus != nil && repo.RemoteStatus.Status == model.RemoteStatusAutoBlocked {
+			healthy = false
+		}
+	}
+	return statuses, healthy
+}
+
+// ListRepositories 列出所有仓库，支持按 type、format 过滤
 func (h *RepositoryHandler) ListRepositories(c *gin.Context) {
-	// TODO: 实现列出所有仓库的逻辑
-	c.JSON(200, gin.H{"message": "List repositories - Not implemented yet"})
+	var query dto.ListRepositoriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	repos, err := h.repositoryService.List(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, gin.H{"items": repos})
 }
 
 // CreateRepository 创建新仓库
 func (h *RepositoryHandler) CreateRepository(c *gin.Context) {
-	// TODO: 实现创建新仓库的逻辑
-	c.JSON(201, gin.H{"message": "Create repository - Not implemented yet"})
+	var req dto.CreateRepositoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	repo, err := h.repositoryService.Create(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "repository:"+repo.Name)
+	web.Created(c, repo)
 }
 
-// GetRepository 获取单个仓库
+// GetRepository 获取单个仓库，id 可以是仓库ID或名称
 func (h *RepositoryHandler) GetRepository(c *gin.Context) {
-	// TODO: 实现获取单个仓库的逻辑
-	id := c.Param("id")
-	c.JSON(200, gin.H{"message": "Get repository - Not implemented yet", "id": id})
+	repo, err := h.repositoryService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, repo)
 }
 
 // UpdateRepository 更新仓库
 func (h *RepositoryHandler) UpdateRepository(c *gin.Context) {
-	// TODO: 实现更新仓库的逻辑
-	id := c.Param("id")
-	c.JSON(200, gin.H{"message": "Update repository - Not implemented yet", "id": id})
+	var req dto.UpdateRepositoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	repo, err := h.repositoryService.Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, repo)
 }
 
 // DeleteRepository 删除仓库
 func (h *RepositoryHandler) DeleteRepository(c *gin.Context) {
-	// TODO: 实现删除仓库的逻辑
-	id := c.Param("id")
-	c.JSON(200, gin.H{"message": "Delete repository - Not implemented yet", "id": id})
+	if err := h.repositoryService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
 }
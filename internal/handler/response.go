@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// respondError 将服务层错误映射为标准错误响应，未知错误只记录日志不暴露细节
+func respondError(c *gin.Context, logger *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		web.NotFound(c, err.Error())
+	case errors.Is(err, errs.ErrInvalidArgument):
+		web.BadRequest(c, err.Error())
+	case errors.Is(err, errs.ErrConflict):
+		web.Conflict(c, err.Error())
+	case errors.Is(err, errs.ErrUnavailable):
+		web.ServiceUnavailable(c, err.Error())
+	case errors.Is(err, errs.ErrUnauthenticated):
+		web.Unauthorized(c, err.Error())
+	case errors.Is(err, errs.ErrForbidden):
+		web.Forbidden(c, err.Error())
+	default:
+		logger.Error("Request failed",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"error", err,
+		)
+		web.InternalServerError(c, "internal server error")
+	}
+}
+
+// limitBody 限制请求体的最大字节数，超出后读取请求体返回 *http.MaxBytesError
+func limitBody(c *gin.Context, limit int64) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+}
+
+// respondReadError 读取请求体失败时响应，超出 limitBody 的限制返回 413，其它错误返回 400
+func respondReadError(c *gin.Context, msg string, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		web.RequestEntityTooLarge(c, fmt.Sprintf("request body exceeds the limit of %d bytes", tooLarge.Limit))
+		return
+	}
+	web.BadRequest(c, msg+": "+err.Error())
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "not_found", err: errs.NotFound("artifact %q not found", "a.jar"), wantStatus: http.StatusNotFound, wantBody: "a.jar"},
+		{name: "invalid_argument", err: errs.InvalidArgument("bad path"), wantStatus: http.StatusBadRequest, wantBody: "bad path"},
+		{name: "conflict", err: errs.Conflict("redeploy is disabled"), wantStatus: http.StatusConflict, wantBody: "redeploy is disabled"},
+		{name: "wrapped_conflict", err: fmt.Errorf("upload: %w", errs.Conflict("read-only")), wantStatus: http.StatusConflict, wantBody: "read-only"},
+		{name: "unavailable", err: errs.Unavailable("remote down"), wantStatus: http.StatusServiceUnavailable, wantBody: "remote down"},
+		{name: "unauthenticated", err: errs.Unauthenticated("login required"), wantStatus: http.StatusUnauthorized, wantBody: "login required"},
+		{name: "forbidden", err: errs.Forbidden("no write"), wantStatus: http.StatusForbidden, wantBody: "no write"},
+		{name: "internal_hides_details", err: errors.New("database password wrong"), wantStatus: http.StatusInternalServerError, wantBody: "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/x", nil)
+			recorder := serve(t, http.MethodGet, "/x", func(c *gin.Context) { respondError(c, testLogger(), tt.err) }, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tt.wantBody)
+			assert.NotContains(t, recorder.Body.String(), "password")
+		})
+	}
+}
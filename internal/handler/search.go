@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// SearchHandler 处理跨仓库搜索请求
+type SearchHandler struct {
+	logger        *slog.Logger
+	searchService service.SearchService
+}
+
+// NewSearchHandler 创建新的搜索处理器
+func NewSearchHandler(logger *slog.Logger, searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		logger:        logger,
+		searchService: searchService,
+	}
+}
+
+// RegisterRoutes 注册搜索路由
+func (h *SearchHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/search", h.Search)
+}
+
+// Search 按名称、版本、校验和、元数据和属性跨仓库搜索制品
+func (h *SearchHandler) Search(c *gin.Context) {
+	var query dto.SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	page, err := h.searchService.Search(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	base := baseURL(c)
+	for _, hit := range page.Items {
+		hit.DownloadURL = base + "/api/v1/repositories/" + url.PathEscape(hit.Repository) + "/artifacts/" + escapePath(hit.Path)
+	}
+	web.SuccessWithPage(c, page.Items, web.Page{
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	})
+}
+
+// baseURL 根据请求推断服务的外部访问地址，支持反向代理设置的 X-Forwarded-Proto 与 X-Forwarded-Host
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	host := c.Request.Host
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+		host = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	return scheme + "://" + host
+}
+
+// escapePath 逐段转义制品路径
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
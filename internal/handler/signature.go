@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// SignatureHandler 处理受信任公钥与制品签名校验相关的 HTTP 请求
+type SignatureHandler struct {
+	logger           *slog.Logger
+	signatureService service.SignatureService
+}
+
+// NewSignatureHandler 创建新的制品签名处理器
+func NewSignatureHandler(logger *slog.Logger, signatureService service.SignatureService) *SignatureHandler {
+	return &SignatureHandler{
+		logger:           logger,
+		signatureService: signatureService,
+	}
+}
+
+// RegisterRoutes 注册受信任公钥与签名状态路由
+func (h *SignatureHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/trusted-keys", h.ListKeys)
+	web.RegisterApiHandle(http.MethodPost, "/trusted-keys", h.CreateKey)
+	web.RegisterApiHandle(http.MethodGet, "/trusted-keys/:name", h.GetKey)
+	web.RegisterApiHandle(http.MethodDelete, "/trusted-keys/:name", h.DeleteKey)
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/signatures/*path", h.Status)
+	web.RegisterApiHandle(http.MethodPost, "/repositories/:id/signatures/*path", h.Verify)
+}
+
+// ListKeys 列出受信任公钥
+func (h *SignatureHandler) ListKeys(c *gin.Context) {
+	keys, err := h.signatureService.ListKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, keys)
+}
+
+// CreateKey 添加受信任公钥
+func (h *SignatureHandler) CreateKey(c *gin.Context) {
+	var req dto.TrustedKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	key, err := h.signatureService.CreateKey(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "trusted_key:"+key.Name)
+	web.Created(c, key)
+}
+
+// GetKey 获取受信任公钥
+func (h *SignatureHandler) GetKey(c *gin.Context) {
+	key, err := h.signatureService.GetKey(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, key)
+}
+
+// DeleteKey 删除受信任公钥
+func (h *SignatureHandler) DeleteKey(c *gin.Context) {
+	if err := h.signatureService.DeleteKey(c.Request.Context(), c.Param("name")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// Status 获取制品的签名校验状态
+func (h *SignatureHandler) Status(c *gin.Context) {
+	status, err := h.signatureService.Status(c.Request.Context(), c.Param("id"), c.Param("path"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, status)
+}
+
+// Verify 重新校验制品签名
+func (h *SignatureHandler) Verify(c *gin.Context) {
+	status, err := h.signatureService.Verify(c.Request.Context(), c.Param("id"), c.Param("path"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, status)
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubSignatureService 记录调用参数的制品签名服务
+type stubSignatureService struct {
+	service.SignatureService
+	req        *dto.TrustedKeyRequest
+	name       string
+	repo, path string
+}
+
+func (s *stubSignatureService) CreateKey(_ context.Context, req *dto.TrustedKeyRequest) (*model.TrustedKey, error) {
+	if req.Name == "exists" {
+		return nil, errs.Conflict("trusted key %q already exists", req.Name)
+	}
+	s.req = req
+	return &model.TrustedKey{Name: req.Name, Type: req.Type, Fingerprint: "ABCD"}, nil
+}
+
+func (s *stubSignatureService) GetKey(_ context.Context, name string) (*model.TrustedKey, error) {
+	if name == "missing" {
+		return nil, errs.NotFound("trusted key %q not found", name)
+	}
+	s.name = name
+	return &model.TrustedKey{Name: name, Type: model.TrustedKeyTypePGP}, nil
+}
+
+func (s *stubSignatureService) ListKeys(context.Context) ([]*model.TrustedKey, error) {
+	return []*model.TrustedKey{{Name: "release", Type: model.TrustedKeyTypeCosign}}, nil
+}
+
+func (s *stubSignatureService) DeleteKey(_ context.Context, name string) error {
+	if name == "in-use" {
+		return errs.Conflict("trusted key %q is used by repository %q", name, "libs-release")
+	}
+	s.name = name
+	return nil
+}
+
+func (s *stubSignatureService) Status(_ context.Context, repo, path string) (*dto.SignatureStatus, error) {
+	return s.status(repo, path, "unsigned")
+}
+
+func (s *stubSignatureService) Verify(_ context.Context, repo, path string) (*dto.SignatureStatus, error) {
+	return s.status(repo, path, "verified")
+}
+
+func (s *stubSignatureService) status(repo, path, status string) (*dto.SignatureStatus, error) {
+	if strings.HasSuffix(path, ".asc") {
+		return nil, errs.InvalidArgument("%q is not an artifact that can be signed", path)
+	}
+	s.repo, s.path = repo, path
+	return &dto.SignatureStatus{Repository: repo, Path: path, Status: status}, nil
+}
+
+func TestSignatureHandler_CreateKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		resource string
+	}{
+		{name: "created", body: `{"name":"release","type":"cosign","public_key":"pem","description":"ci"}`, status: http.StatusCreated, resource: "trusted_key:release"},
+		{name: "conflict", body: `{"name":"exists","type":"pgp","public_key":"armored"}`, status: http.StatusConflict},
+		{name: "unknown_type", body: `{"name":"release","type":"x509","public_key":"pem"}`, status: http.StatusBadRequest},
+		{name: "missing_public_key", body: `{"name":"release","type":"pgp"}`, status: http.StatusBadRequest},
+		{name: "malformed", body: `{`, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubSignatureService{}
+			h := NewSignatureHandler(testLogger(), stub)
+			var resource string
+			engine := gin.New()
+			engine.POST("/trusted-keys", func(c *gin.Context) {
+				c.Next()
+				resource = c.GetString(auditResourceKey)
+			}, h.CreateKey)
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/trusted-keys", strings.NewReader(tt.body)))
+
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			assert.Equal(t, tt.resource, resource)
+			if tt.status == http.StatusCreated {
+				assert.Equal(t, "ci", stub.req.Description)
+				assert.Contains(t, dataOf(t, recorder), `"fingerprint":"ABCD"`)
+			}
+		})
+	}
+}
+
+func TestSignatureHandler_Keys(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		route   string
+		path    string
+		handler func(h *SignatureHandler) gin.HandlerFunc
+		status  int
+		want    string
+	}{
+		{name: "list", method: http.MethodGet, route: "/trusted-keys", path: "/trusted-keys",
+			handler: func(h *SignatureHandler) gin.HandlerFunc { return h.ListKeys }, status: http.StatusOK, want: `"name":"release"`},
+		{name: "get", method: http.MethodGet, route: "/trusted-keys/:name", path: "/trusted-keys/release",
+			handler: func(h *SignatureHandler) gin.HandlerFunc { return h.GetKey }, status: http.StatusOK, want: `"type":"pgp"`},
+		{name: "get_missing", method: http.MethodGet, route: "/trusted-keys/:name", path: "/trusted-keys/missing",
+			handler: func(h *SignatureHandler) gin.HandlerFunc { return h.GetKey }, status: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, route: "/trusted-keys/:name", path: "/trusted-keys/release",
+			handler: func(h *SignatureHandler) gin.HandlerFunc { return h.DeleteKey }, status: http.StatusOK},
+		{name: "delete_in_use", method: http.MethodDelete, route: "/trusted-keys/:name", path: "/trusted-keys/in-use",
+			handler: func(h *SignatureHandler) gin.HandlerFunc { return h.DeleteKey }, status: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubSignatureService{}
+			h := NewSignatureHandler(testLogger(), stub)
+			recorder := serve(t, tt.method, tt.route, tt.handler(h), httptest.NewRequest(tt.method, tt.path, nil))
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.want != "" {
+				assert.Contains(t, dataOf(t, recorder), tt.want)
+			}
+			if tt.status == http.StatusOK && tt.route != "/trusted-keys" {
+				assert.Equal(t, "release", stub.name)
+			}
+		})
+	}
+}
+
+func TestSignatureHandler_Status(t *testing.T) {
+	const route = "/repositories/:id/signatures/*path"
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		want   string
+	}{
+		{name: "status", method: http.MethodGet, path: "/repositories/libs/signatures/com/x/lib-1.0.jar", status: http.StatusOK, want: `"status":"unsigned"`},
+		{name: "verify", method: http.MethodPost, path: "/repositories/libs/signatures/com/x/lib-1.0.jar", status: http.StatusOK, want: `"status":"verified"`},
+		{name: "status_not_signable", method: http.MethodGet, path: "/repositories/libs/signatures/com/x/lib-1.0.jar.asc", status: http.StatusBadRequest},
+		{name: "verify_not_signable", method: http.MethodPost, path: "/repositories/libs/signatures/com/x/lib-1.0.jar.asc", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubSignatureService{}
+			h := NewSignatureHandler(testLogger(), stub)
+			handler := h.Status
+			if tt.method == http.MethodPost {
+				handler = h.Verify
+			}
+			recorder := serve(t, tt.method, route, handler, httptest.NewRequest(tt.method, tt.path, nil))
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.want == "" {
+				return
+			}
+			assert.Contains(t, dataOf(t, recorder), tt.want)
+			assert.Equal(t, "libs", stub.repo)
+			assert.Equal(t, "/com/x/lib-1.0.jar", stub.path)
+		})
+	}
+}
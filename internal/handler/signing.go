@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// signingPublicKeyPath 启用的签名密钥公钥地址，客户端据此导入公钥校验元数据签名
+const signingPublicKeyPath = "/.well-known/signing-key.asc"
+
+// SigningHandler 处理元数据签名密钥相关的 HTTP 请求
+type SigningHandler struct {
+	logger            *slog.Logger
+	signingKeyService service.SigningKeyService
+}
+
+// NewSigningHandler 创建新的签名密钥处理器
+func NewSigningHandler(logger *slog.Logger, signingKeyService service.SigningKeyService) *SigningHandler {
+	return &SigningHandler{
+		logger:            logger,
+		signingKeyService: signingKeyService,
+	}
+}
+
+// RegisterRoutes 注册签名密钥管理路由与公钥地址
+func (h *SigningHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/signing-keys", h.ListKeys)
+	web.RegisterApiHandle(http.MethodPost, "/signing-keys", h.ImportKey)
+	web.RegisterApiHandle(http.MethodGet, "/signing-keys/:name", h.GetKey)
+	web.RegisterApiHandle(http.MethodDelete, "/signing-keys/:name", h.DeleteKey)
+	web.RegisterApiHandle(http.MethodPost, "/signing-keys/:name/activate", h.ActivateKey)
+	web.RegisterRootHandle(http.MethodGet, signingPublicKeyPath, h.PublicKey)
+}
+
+// ListKeys 列出签名密钥
+func (h *SigningHandler) ListKeys(c *gin.Context) {
+	keys, err := h.signingKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, keys)
+}
+
+// ImportKey 导入签名私钥，响应不包含私钥
+func (h *SigningHandler) ImportKey(c *gin.Context) {
+	var req dto.SigningKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	key, err := h.signingKeyService.ImportKey(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "signing_key:"+key.Name)
+	web.Created(c, key)
+}
+
+// GetKey 获取签名密钥
+func (h *SigningHandler) GetKey(c *gin.Context) {
+	key, err := h.signingKeyService.GetKey(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, key)
+}
+
+// DeleteKey 删除签名密钥
+func (h *SigningHandler) DeleteKey(c *gin.Context) {
+	if err := h.signingKeyService.DeleteKey(c.Request.Context(), c.Param("name")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// ActivateKey 启用签名密钥
+func (h *SigningHandler) ActivateKey(c *gin.Context) {
+	key, err := h.signingKeyService.ActivateKey(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, key)
+}
+
+// PublicKey 以 application/pgp-keys 返回启用的签名密钥的 ASCII 封装公钥，无需认证
+func (h *SigningHandler) PublicKey(c *gin.Context) {
+	key, err := h.signingKeyService.ActiveKey(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.String(http.StatusNotFound, "no signing key is active\n")
+			return
+		}
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Header("X-Key-Fingerprint", key.Fingerprint)
+	c.Data(http.StatusOK, "application/pgp-keys", []byte(key.PublicKey))
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubSigningKeyService 记录调用参数的签名密钥服务
+type stubSigningKeyService struct {
+	service.SigningKeyService
+	req       *dto.SigningKeyRequest
+	name      string
+	active    *model.SigningKey
+	activeErr error
+}
+
+func (s *stubSigningKeyService) ImportKey(_ context.Context, req *dto.SigningKeyRequest) (*model.SigningKey, error) {
+	if req.Passphrase == "wrong" {
+		return nil, errs.InvalidArgument("failed to decrypt private key")
+	}
+	s.req = req
+	return &model.SigningKey{Name: req.Name, PrivateKey: "sealed", Fingerprint: "ABCD", Active: req.Active}, nil
+}
+
+func (s *stubSigningKeyService) GetKey(_ context.Context, name string) (*model.SigningKey, error) {
+	if name == "missing" {
+		return nil, errs.NotFound("signing key %q not found", name)
+	}
+	s.name = name
+	return &model.SigningKey{Name: name, PrivateKey: "sealed"}, nil
+}
+
+func (s *stubSigningKeyService) ListKeys(context.Context) ([]*model.SigningKey, error) {
+	return []*model.SigningKey{{Name: "release", PrivateKey: "sealed", Active: true}}, nil
+}
+
+func (s *stubSigningKeyService) ActivateKey(_ context.Context, name string) (*model.SigningKey, error) {
+	if name == "undecryptable" {
+		return nil, errs.Conflict("signing key %q cannot be decrypted", name)
+	}
+	s.name = name
+	return &model.SigningKey{Name: name, Active: true}, nil
+}
+
+func (s *stubSigningKeyService) DeleteKey(_ context.Context, name string) error {
+	if name == "in-use" {
+		return errs.Conflict("signing key %q is active and repository %q signs metadata", name, "libs-release")
+	}
+	s.name = name
+	return nil
+}
+
+func (s *stubSigningKeyService) ActiveKey(context.Context) (*model.SigningKey, error) {
+	return s.active, s.activeErr
+}
+
+func TestSigningHandler_ImportKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		resource string
+	}{
+		{name: "imported", body: `{"name":"release","private_key":"armored","passphrase":"s3cret","active":true}`, status: http.StatusCreated, resource: "signing_key:release"},
+		{name: "invalid_key", body: `{"name":"release","private_key":"armored","passphrase":"wrong"}`, status: http.StatusBadRequest},
+		{name: "missing_private_key", body: `{"name":"release"}`, status: http.StatusBadRequest},
+		{name: "malformed", body: `{`, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubSigningKeyService{}
+			h := NewSigningHandler(testLogger(), stub)
+			var resource string
+			engine := gin.New()
+			engine.POST("/signing-keys", func(c *gin.Context) {
+				c.Next()
+				resource = c.GetString(auditResourceKey)
+			}, h.ImportKey)
+			recorder := httptest.NewRecorder()
+			engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/signing-keys", strings.NewReader(tt.body)))
+
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			assert.Equal(t, tt.resource, resource)
+			if tt.status == http.StatusCreated {
+				assert.Equal(t, "s3cret", stub.req.Passphrase)
+				assert.True(t, stub.req.Active)
+				assert.Contains(t, dataOf(t, recorder), `"fingerprint":"ABCD"`)
+				assert.NotContains(t, recorder.Body.String(), "sealed", "the private key is never returned")
+			}
+		})
+	}
+}
+
+func TestSigningHandler_Keys(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		route   string
+		path    string
+		handler func(h *SigningHandler) gin.HandlerFunc
+		status  int
+		want    string
+	}{
+		{name: "list", method: http.MethodGet, route: "/signing-keys", path: "/signing-keys",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.ListKeys }, status: http.StatusOK, want: `"active":true`},
+		{name: "get", method: http.MethodGet, route: "/signing-keys/:name", path: "/signing-keys/release",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.GetKey }, status: http.StatusOK, want: `"name":"release"`},
+		{name: "get_missing", method: http.MethodGet, route: "/signing-keys/:name", path: "/signing-keys/missing",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.GetKey }, status: http.StatusNotFound},
+		{name: "activate", method: http.MethodPost, route: "/signing-keys/:name/activate", path: "/signing-keys/release/activate",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.ActivateKey }, status: http.StatusOK, want: `"active":true`},
+		{name: "activate_undecryptable", method: http.MethodPost, route: "/signing-keys/:name/activate", path: "/signing-keys/undecryptable/activate",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.ActivateKey }, status: http.StatusConflict},
+		{name: "delete", method: http.MethodDelete, route: "/signing-keys/:name", path: "/signing-keys/release",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.DeleteKey }, status: http.StatusOK},
+		{name: "delete_in_use", method: http.MethodDelete, route: "/signing-keys/:name", path: "/signing-keys/in-use",
+			handler: func(h *SigningHandler) gin.HandlerFunc { return h.DeleteKey }, status: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubSigningKeyService{}
+			h := NewSigningHandler(testLogger(), stub)
+			recorder := serve(t, tt.method, tt.route, tt.handler(h), httptest.NewRequest(tt.method, tt.path, nil))
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			assert.NotContains(t, recorder.Body.String(), "sealed")
+			if tt.want != "" {
+				assert.Contains(t, dataOf(t, recorder), tt.want)
+			}
+			if tt.status == http.StatusOK && tt.route != "/signing-keys" {
+				assert.Equal(t, "release", stub.name)
+			}
+		})
+	}
+}
+
+func TestSigningHandler_PublicKey(t *testing.T) {
+	const armored = "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\n-----END PGP PUBLIC KEY BLOCK-----\n"
+	tests := []struct {
+		name        string
+		stub        *stubSigningKeyService
+		status      int
+		contentType string
+		body        string
+		fingerprint string
+	}{
+		{name: "active_key", stub: &stubSigningKeyService{active: &model.SigningKey{Name: "release", PublicKey: armored, Fingerprint: "ABCD"}},
+			status: http.StatusOK, contentType: "application/pgp-keys", body: armored, fingerprint: "ABCD"},
+		{name: "no_active_key", stub: &stubSigningKeyService{activeErr: errs.NotFound("no signing key is active")},
+			status: http.StatusNotFound, body: "no signing key is active\n"},
+		{name: "internal_error", stub: &stubSigningKeyService{activeErr: errors.New("database is locked")},
+			status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSigningHandler(testLogger(), tt.stub)
+			recorder := serve(t, http.MethodGet, signingPublicKeyPath, h.PublicKey, httptest.NewRequest(http.MethodGet, signingPublicKeyPath, nil))
+			require.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, recorder.Header().Get("Content-Type"))
+			}
+			if tt.body != "" {
+				assert.Equal(t, tt.body, recorder.Body.String())
+			}
+			assert.Equal(t, tt.fingerprint, recorder.Header().Get("X-Key-Fingerprint"))
+			assert.NotContains(t, recorder.Body.String(), "database is locked", "internal errors are not exposed")
+		})
+	}
+}
+
+func TestAuthHandler_SigningPublicKeySkipsAuth(t *testing.T) {
+	h := NewAuthHandler(testLogger(), &stubAuthService{}, &stubUserService{}, &stubAuthorizer{})
+	engine := gin.New()
+	engine.Use(h.Authenticate)
+	engine.GET(signingPublicKeyPath, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, signingPublicKeyPath, nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// StagingHandler 处理暂存仓库相关的 HTTP 请求
+type StagingHandler struct {
+	logger         *slog.Logger
+	stagingService service.StagingService
+	maxUploadSize  int64
+}
+
+// NewStagingHandler 创建新的暂存仓库处理器
+func NewStagingHandler(cfg *config.Config, logger *slog.Logger, stagingService service.StagingService) *StagingHandler {
+	return &StagingHandler{
+		logger:         logger,
+		stagingService: stagingService,
+		maxUploadSize:  cfg.Server.MaxUploadSize,
+	}
+}
+
+// RegisterRoutes 注册暂存仓库路由
+func (h *StagingHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/staging/repositories", h.ListStaging)
+	web.RegisterApiHandle(http.MethodPost, "/staging/repositories", h.StartStaging)
+	web.RegisterApiHandle(http.MethodGet, "/staging/repositories/:id", h.GetStaging)
+	web.RegisterApiHandle(http.MethodPost, "/staging/repositories/:id/close", h.CloseStaging)
+	web.RegisterApiHandle(http.MethodPost, "/staging/repositories/:id/release", h.ReleaseStaging)
+	web.RegisterApiHandle(http.MethodPost, "/staging/repositories/:id/drop", h.DropStaging)
+	web.RegisterApiHandle(http.MethodPut, "/staging/deploy/:target/*path", h.Deploy)
+}
+
+// ListStaging 列出暂存仓库，支持按 target、status 过滤
+func (h *StagingHandler) ListStaging(c *gin.Context) {
+	var query dto.ListStagingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	repos, err := h.stagingService.List(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, repos)
+}
+
+// StartStaging 为目标仓库创建新的暂存仓库
+func (h *StagingHandler) StartStaging(c *gin.Context) {
+	var req dto.StartStagingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	repo, err := h.stagingService.Start(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "repository:"+repo.Name)
+	web.Created(c, repo)
+}
+
+// GetStaging 获取暂存仓库
+func (h *StagingHandler) GetStaging(c *gin.Context) {
+	repo, err := h.stagingService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, repo)
+}
+
+// CloseStaging 校验并关闭暂存仓库，校验失败时返回 400 并列出未通过的规则
+func (h *StagingHandler) CloseStaging(c *gin.Context) {
+	repo, err := h.stagingService.Close(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, repo)
+}
+
+// ReleaseStaging 将已关闭的暂存仓库发布到目标仓库
+func (h *StagingHandler) ReleaseStaging(c *gin.Context) {
+	result, err := h.stagingService.Release(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, result)
+}
+
+// DropStaging 丢弃暂存仓库
+func (h *StagingHandler) DropStaging(c *gin.Context) {
+	repo, err := h.stagingService.Drop(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, repo)
+}
+
+// Deploy 以请求体作为制品内容上传到目标仓库的暂存仓库，可作为 Maven 等客户端的部署地址
+// 请求体不能超过 server.max_upload_size，超出时返回 413
+func (h *StagingHandler) Deploy(c *gin.Context) {
+	properties, err := parseProperties(c.QueryArray("properties"))
+	if err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+	limitBody(c, h.maxUploadSize)
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondReadError(c, "failed to read request body", err)
+		return
+	}
+
+	artifact, err := h.stagingService.Deploy(c.Request.Context(), c.Param("target"), &dto.UploadArtifactRequest{
+		Path:       c.Param("path"),
+		Data:       data,
+		Properties: properties,
+		Checksums:  checksumHeaders(c),
+	})
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Created(c, artifact)
+}
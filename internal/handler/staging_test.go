@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// stubStagingService 记录部署请求的暂存服务，未实现的方法调用时 panic
+type stubStagingService struct {
+	service.StagingService
+	deployed *dto.UploadArtifactRequest
+}
+
+func (s *stubStagingService) Deploy(_ context.Context, _ string, req *dto.UploadArtifactRequest) (*model.Artifact, error) {
+	s.deployed = req
+	return &model.Artifact{Path: req.Path}, nil
+}
+
+func TestStagingHandler_Deploy(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int64
+		wantStatus int
+	}{
+		{name: "within_limit", limit: 3, wantStatus: http.StatusCreated},
+		{name: "over_limit", limit: 2, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staging := &stubStagingService{}
+			h := NewStagingHandler(testConfig(tt.limit), testLogger(), staging)
+			req := httptest.NewRequest(http.MethodPut, "/staging/deploy/releases/com/x/lib-1.0.jar", strings.NewReader("jar"))
+
+			recorder := serve(t, http.MethodPut, "/staging/deploy/:target/*path", h.Deploy, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			if tt.wantStatus == http.StatusCreated {
+				require.NotNil(t, staging.deployed)
+				assert.Equal(t, []byte("jar"), staging.deployed.Data)
+			} else {
+				assert.Nil(t, staging.deployed)
+			}
+		})
+	}
+}
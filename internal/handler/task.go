@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// TaskHandler 处理后台任务相关的 HTTP 请求
+type TaskHandler struct {
+	logger      *slog.Logger
+	taskService service.TaskService
+}
+
+// NewTaskHandler 创建新的后台任务处理器
+func NewTaskHandler(logger *slog.Logger, taskService service.TaskService) *TaskHandler {
+	return &TaskHandler{
+		logger:      logger,
+		taskService: taskService,
+	}
+}
+
+// RegisterRoutes 注册后台任务路由
+func (h *TaskHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/task-types", h.ListTypes)
+	web.RegisterApiHandle(http.MethodGet, "/tasks", h.ListTasks)
+	web.RegisterApiHandle(http.MethodPost, "/tasks", h.CreateTask)
+	web.RegisterApiHandle(http.MethodGet, "/tasks/:id", h.GetTask)
+	web.RegisterApiHandle(http.MethodPut, "/tasks/:id", h.UpdateTask)
+	web.RegisterApiHandle(http.MethodDelete, "/tasks/:id", h.DeleteTask)
+	web.RegisterApiHandle(http.MethodPost, "/tasks/:id/run", h.RunTask)
+	web.RegisterApiHandle(http.MethodPost, "/tasks/:id/cancel", h.CancelTask)
+	web.RegisterApiHandle(http.MethodGet, "/tasks/:id/runs", h.ListRuns)
+	web.RegisterApiHandle(http.MethodGet, "/tasks/:id/runs/:runId", h.GetRun)
+}
+
+// ListTypes 列出可调度的任务类型
+func (h *TaskHandler) ListTypes(c *gin.Context) {
+	web.Success(c, h.taskService.Types())
+}
+
+// ListTasks 列出后台任务
+func (h *TaskHandler) ListTasks(c *gin.Context) {
+	tasks, err := h.taskService.List(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, tasks)
+}
+
+// CreateTask 创建后台任务
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	var req dto.CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Create(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "task:"+task.Name)
+	web.Created(c, task)
+}
+
+// GetTask 获取后台任务
+func (h *TaskHandler) GetTask(c *gin.Context) {
+	task, err := h.taskService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, task)
+}
+
+// UpdateTask 更新后台任务的 cron 表达式、参数与启用状态
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	var req dto.UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, task)
+}
+
+// DeleteTask 删除后台任务
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	if err := h.taskService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// RunTask 立即在后台执行任务，返回新建的执行记录
+func (h *TaskHandler) RunTask(c *gin.Context) {
+	run, err := h.taskService.RunNow(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Created(c, run)
+}
+
+// CancelTask 取消任务正在进行的执行
+func (h *TaskHandler) CancelTask(c *gin.Context) {
+	run, err := h.taskService.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, run)
+}
+
+// ListRuns 列出任务的执行记录
+func (h *TaskHandler) ListRuns(c *gin.Context) {
+	var query dto.ListTaskRunsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	runs, err := h.taskService.ListRuns(c.Request.Context(), c.Param("id"), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, runs)
+}
+
+// GetRun 获取执行记录及其日志与结果
+func (h *TaskHandler) GetRun(c *gin.Context) {
+	run, err := h.taskService.GetRun(c.Request.Context(), c.Param("id"), c.Param("runId"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, run)
+}
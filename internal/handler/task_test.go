@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// stubTaskService 记录调用参数的后台任务服务
+type stubTaskService struct {
+	service.TaskService
+	id     string
+	create *dto.CreateTaskRequest
+	update *dto.UpdateTaskRequest
+	query  *dto.ListTaskRunsQuery
+}
+
+func (s *stubTaskService) Create(_ context.Context, req *dto.CreateTaskRequest) (*model.Task, error) {
+	if req.Cron == "sometimes" {
+		return nil, errs.InvalidArgument("invalid cron expression %q", req.Cron)
+	}
+	s.create = req
+	return &model.Task{ID: "t1", Name: req.Name, Type: req.Type, Cron: req.Cron}, nil
+}
+
+func (s *stubTaskService) Update(_ context.Context, id string, req *dto.UpdateTaskRequest) (*model.Task, error) {
+	s.id, s.update = id, req
+	return &model.Task{ID: id}, nil
+}
+
+func (s *stubTaskService) RunNow(_ context.Context, id string) (*model.TaskRun, error) {
+	if id == "busy" {
+		return nil, errs.Conflict("task %q is already running", id)
+	}
+	s.id = id
+	return &model.TaskRun{ID: "r1", TaskID: id, Status: model.TaskStatusRunning}, nil
+}
+
+func (s *stubTaskService) Cancel(_ context.Context, id string) (*model.TaskRun, error) {
+	return nil, errs.Conflict("task %q is not running", id)
+}
+
+func (s *stubTaskService) ListRuns(_ context.Context, id string, query *dto.ListTaskRunsQuery) ([]*model.TaskRun, error) {
+	s.id, s.query = id, query
+	return []*model.TaskRun{}, nil
+}
+
+func (s *stubTaskService) GetRun(_ context.Context, id, runID string) (*model.TaskRun, error) {
+	return nil, errs.NotFound("task run %q not found", runID)
+}
+
+func TestTaskHandler(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		stub := &stubTaskService{}
+		h := NewTaskHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"name":"gc","type":"upload_gc","cron":"@hourly","enabled":false}`))
+		recorder := serve(t, http.MethodPost, "/tasks", h.CreateTask, req)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		require.NotNil(t, stub.create.Enabled)
+		assert.False(t, *stub.create.Enabled)
+		assert.Equal(t, "@hourly", stub.create.Cron)
+	})
+
+	t.Run("create_missing_type", func(t *testing.T) {
+		h := NewTaskHandler(testLogger(), &stubTaskService{})
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"name":"gc"}`))
+		recorder := serve(t, http.MethodPost, "/tasks", h.CreateTask, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("create_invalid_cron", func(t *testing.T) {
+		h := NewTaskHandler(testLogger(), &stubTaskService{})
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"name":"gc","type":"upload_gc","cron":"sometimes"}`))
+		recorder := serve(t, http.MethodPost, "/tasks", h.CreateTask, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("update_keeps_absent_fields_nil", func(t *testing.T) {
+		stub := &stubTaskService{}
+		h := NewTaskHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPut, "/tasks/gc", strings.NewReader(`{"enabled":true}`))
+		recorder := serve(t, http.MethodPut, "/tasks/:id", h.UpdateTask, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "gc", stub.id)
+		assert.Nil(t, stub.update.Cron)
+		require.NotNil(t, stub.update.Enabled)
+		assert.True(t, *stub.update.Enabled)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		stub := &stubTaskService{}
+		h := NewTaskHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodPost, "/tasks/:id/run", h.RunTask, httptest.NewRequest(http.MethodPost, "/tasks/gc/run", nil))
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		assert.Contains(t, dataOf(t, recorder), `"status":"running"`)
+	})
+
+	t.Run("run_busy", func(t *testing.T) {
+		h := NewTaskHandler(testLogger(), &stubTaskService{})
+		recorder := serve(t, http.MethodPost, "/tasks/:id/run", h.RunTask, httptest.NewRequest(http.MethodPost, "/tasks/busy/run", nil))
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("cancel_idle", func(t *testing.T) {
+		h := NewTaskHandler(testLogger(), &stubTaskService{})
+		recorder := serve(t, http.MethodPost, "/tasks/:id/cancel", h.CancelTask, httptest.NewRequest(http.MethodPost, "/tasks/gc/cancel", nil))
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("list_runs_limit", func(t *testing.T) {
+		tests := []struct {
+			query string
+			want  int
+		}{
+			{query: "", want: http.StatusOK},
+			{query: "?limit=50", want: http.StatusOK},
+			{query: "?limit=0", want: http.StatusOK},
+			{query: "?limit=201", want: http.StatusBadRequest},
+			{query: "?limit=abc", want: http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			h := NewTaskHandler(testLogger(), &stubTaskService{})
+			req := httptest.NewRequest(http.MethodGet, "/tasks/gc/runs"+tt.query, nil)
+			recorder := serve(t, http.MethodGet, "/tasks/:id/runs", h.ListRuns, req)
+			assert.Equal(t, tt.want, recorder.Code, tt.query)
+		}
+	})
+
+	t.Run("get_missing_run", func(t *testing.T) {
+		h := NewTaskHandler(testLogger(), &stubTaskService{})
+		req := httptest.NewRequest(http.MethodGet, "/tasks/gc/runs/missing", nil)
+		recorder := serve(t, http.MethodGet, "/tasks/:id/runs/:runId", h.GetRun, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
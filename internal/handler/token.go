@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// TokenHandler 处理个人访问令牌相关的 HTTP 请求
+type TokenHandler struct {
+	logger       *slog.Logger
+	tokenService service.TokenService
+}
+
+// NewTokenHandler 创建新的个人访问令牌处理器
+func NewTokenHandler(logger *slog.Logger, tokenService service.TokenService) *TokenHandler {
+	return &TokenHandler{
+		logger:       logger,
+		tokenService: tokenService,
+	}
+}
+
+// RegisterRoutes 注册个人访问令牌路由，/tokens 作用于当前用户，/users/:id/tokens 供管理员管理其他用户的令牌
+func (h *TokenHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/tokens", h.ListTokens)
+	web.RegisterApiHandle(http.MethodPost, "/tokens", h.CreateToken)
+	web.RegisterApiHandle(http.MethodDelete, "/tokens/:id", h.RevokeToken)
+	web.RegisterApiHandle(http.MethodGet, "/users/:id/tokens", h.ListUserTokens)
+	web.RegisterApiHandle(http.MethodDelete, "/users/:id/tokens/:tokenId", h.RevokeUserToken)
+}
+
+// ListTokens 列出当前用户的令牌
+func (h *TokenHandler) ListTokens(c *gin.Context) {
+	principal, ok := h.principal(c)
+	if !ok {
+		return
+	}
+	tokens, err := h.tokenService.List(c.Request.Context(), principal.UserID)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, tokens)
+}
+
+// CreateToken 为当前用户创建令牌，明文只在响应中返回一次
+func (h *TokenHandler) CreateToken(c *gin.Context) {
+	principal, ok := h.principal(c)
+	if !ok {
+		return
+	}
+	var req dto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	token, err := h.tokenService.Create(c.Request.Context(), principal, &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "token:"+token.ID)
+	web.Created(c, token)
+}
+
+// RevokeToken 吊销当前用户的令牌
+func (h *TokenHandler) RevokeToken(c *gin.Context) {
+	principal, ok := h.principal(c)
+	if !ok {
+		return
+	}
+	if err := h.tokenService.Revoke(c.Request.Context(), principal.UserID, c.Param("id")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// ListUserTokens 列出指定用户的令牌
+func (h *TokenHandler) ListUserTokens(c *gin.Context) {
+	tokens, err := h.tokenService.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, tokens)
+}
+
+// RevokeUserToken 吊销指定用户的令牌
+func (h *TokenHandler) RevokeUserToken(c *gin.Context) {
+	if err := h.tokenService.Revoke(c.Request.Context(), c.Param("id"), c.Param("tokenId")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// principal 读取当前调用者，匿名调用者返回 401
+func (h *TokenHandler) principal(c *gin.Context) (*auth.Principal, bool) {
+	principal, ok := auth.PrincipalFromContext(c.Request.Context())
+	if !ok || principal.Anonymous {
+		web.Unauthorized(c, "authentication required")
+		return nil, false
+	}
+	return principal, true
+}
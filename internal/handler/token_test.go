@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// tokenHash stubTokenService 返回的令牌携带的哈希，响应中不应出现
+const tokenHash = "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"
+
+// stubTokenService 记录调用参数的个人访问令牌服务
+type stubTokenService struct {
+	service.TokenService
+	userID  string
+	tokenID string
+	create  *dto.CreateAccessTokenRequest
+}
+
+func (s *stubTokenService) Create(_ context.Context, principal *auth.Principal, req *dto.CreateAccessTokenRequest) (*dto.CreatedAccessToken, error) {
+	s.userID, s.create = principal.UserID, req
+	token := &model.AccessToken{ID: "t1", UserID: principal.UserID, Name: req.Name, Token: tokenHash, Prefix: "gnp_abcdefgh", Scopes: req.Scopes}
+	return &dto.CreatedAccessToken{AccessToken: token, Token: "gnp_abcdefghsecret"}, nil
+}
+
+func (s *stubTokenService) List(_ context.Context, userID string) ([]*model.AccessToken, error) {
+	s.userID = userID
+	return []*model.AccessToken{{ID: "t1", UserID: userID, Name: "ci", Token: tokenHash, Prefix: "gnp_abcdefgh"}}, nil
+}
+
+func (s *stubTokenService) Revoke(_ context.Context, userID, tokenID string) error {
+	s.userID, s.tokenID = userID, tokenID
+	if tokenID == "missing" {
+		return errs.NotFound("access token %q not found", tokenID)
+	}
+	return nil
+}
+
+// withPrincipal 以指定调用者执行处理器
+func withPrincipal(principal *auth.Principal, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		handler(c)
+	}
+}
+
+func TestTokenHandler(t *testing.T) {
+	alice := &auth.Principal{UserID: "u1", Username: "alice"}
+
+	t.Run("create_returns_secret_once", func(t *testing.T) {
+		stub := &stubTokenService{}
+		h := NewTokenHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(`{"name":"ci","scopes":["repo:read"],"expires_in_days":30}`))
+		recorder := serve(t, http.MethodPost, "/tokens", withPrincipal(alice, h.CreateToken), req)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "u1", stub.userID)
+		assert.Equal(t, 30, stub.create.ExpiresInDays)
+		assert.Contains(t, dataOf(t, recorder), `"token":"gnp_abcdefghsecret"`)
+		assert.NotContains(t, recorder.Body.String(), tokenHash)
+	})
+
+	t.Run("create_validation", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+		}{
+			{name: "missing_name", body: `{"scopes":["repo:read"]}`},
+			{name: "missing_scopes", body: `{"name":"ci"}`},
+			{name: "empty_scopes", body: `{"name":"ci","scopes":[]}`},
+			{name: "expiry_too_long", body: `{"name":"ci","scopes":["repo:read"],"expires_in_days":3651}`},
+			{name: "negative_expiry", body: `{"name":"ci","scopes":["repo:read"],"expires_in_days":-1}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stub := &stubTokenService{}
+				h := NewTokenHandler(testLogger(), stub)
+				req := httptest.NewRequest(http.MethodPost, "/tokens", strings.NewReader(tt.body))
+				recorder := serve(t, http.MethodPost, "/tokens", withPrincipal(alice, h.CreateToken), req)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Nil(t, stub.create)
+			})
+		}
+	})
+
+	t.Run("anonymous_rejected", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			method  string
+			handler func(h *TokenHandler) gin.HandlerFunc
+		}{
+			{name: "list", method: http.MethodGet, handler: func(h *TokenHandler) gin.HandlerFunc { return h.ListTokens }},
+			{name: "create", method: http.MethodPost, handler: func(h *TokenHandler) gin.HandlerFunc { return h.CreateToken }},
+			{name: "revoke", method: http.MethodDelete, handler: func(h *TokenHandler) gin.HandlerFunc { return h.RevokeToken }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stub := &stubTokenService{}
+				h := NewTokenHandler(testLogger(), stub)
+				recorder := serve(t, tt.method, "/tokens", withPrincipal(auth.AnonymousPrincipal, tt.handler(h)), httptest.NewRequest(tt.method, "/tokens", nil))
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assert.Empty(t, stub.userID)
+			})
+		}
+	})
+
+	t.Run("list_own_hides_hash", func(t *testing.T) {
+		stub := &stubTokenService{}
+		h := NewTokenHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/tokens", withPrincipal(alice, h.ListTokens), httptest.NewRequest(http.MethodGet, "/tokens", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "u1", stub.userID)
+		assert.NotContains(t, recorder.Body.String(), tokenHash)
+	})
+
+	t.Run("revoke_own", func(t *testing.T) {
+		stub := &stubTokenService{}
+		h := NewTokenHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodDelete, "/tokens/:id", withPrincipal(alice, h.RevokeToken), httptest.NewRequest(http.MethodDelete, "/tokens/t1", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "u1", stub.userID)
+		assert.Equal(t, "t1", stub.tokenID)
+	})
+
+	t.Run("user_tokens", func(t *testing.T) {
+		stub := &stubTokenService{}
+		h := NewTokenHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/users/:id/tokens", h.ListUserTokens, httptest.NewRequest(http.MethodGet, "/users/bob/tokens", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "bob", stub.userID)
+
+		recorder = serve(t, http.MethodDelete, "/users/:id/tokens/:tokenId", h.RevokeUserToken, httptest.NewRequest(http.MethodDelete, "/users/bob/tokens/missing", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, "bob", stub.userID)
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// UploadHandler 处理分块上传相关的 HTTP 请求
+type UploadHandler struct {
+	logger        *slog.Logger
+	uploadService service.UploadService
+	maxChunkSize  int64
+}
+
+// NewUploadHandler 创建新的分块上传处理器
+func NewUploadHandler(cfg *config.Config, logger *slog.Logger, uploadService service.UploadService) *UploadHandler {
+	return &UploadHandler{
+		logger:        logger,
+		uploadService: uploadService,
+		maxChunkSize:  cfg.Server.MaxChunkSize,
+	}
+}
+
+// RegisterRoutes 注册分块上传路由（嵌套在仓库路由下）
+func (h *UploadHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodPost, "/repositories/:id/uploads", h.CreateSession)
+	web.RegisterApiHandle(http.MethodGet, "/repositories/:id/uploads/:uploadId", h.GetSession)
+	web.RegisterApiHandle(http.MethodPatch, "/repositories/:id/uploads/:uploadId", h.WriteChunk)
+	web.RegisterApiHandle(http.MethodPut, "/repositories/:id/uploads/:uploadId", h.Complete)
+	web.RegisterApiHandle(http.MethodDelete, "/repositories/:id/uploads/:uploadId", h.Cancel)
+}
+
+// CreateSession 创建分块上传会话
+func (h *UploadHandler) CreateSession(c *gin.Context) {
+	var req dto.CreateUploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	session, err := h.uploadService.CreateSession(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	c.Header("Location", c.Request.URL.Path+"/"+session.ID)
+	setUploadOffset(c, session)
+	setAuditResource(c, "upload:"+session.ID)
+	web.Created(c, session)
+}
+
+// GetSession 查询会话，客户端断线后据此获取续传的偏移量
+func (h *UploadHandler) GetSession(c *gin.Context) {
+	session, err := h.uploadService.GetSession(c.Request.Context(), c.Param("id"), c.Param("uploadId"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setUploadOffset(c, session)
+	web.Success(c, session)
+}
+
+// WriteChunk 写入分块，请求体为分块数据，Content-Range 指定分块位置
+// 未提供 Content-Range 时追加到当前偏移量；起始位置与偏移量不一致时返回 409；分块超过 server.max_chunk_size 时返回 413
+func (h *UploadHandler) WriteChunk(c *gin.Context) {
+	limitBody(c, h.maxChunkSize)
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondReadError(c, "failed to read request body", err)
+		return
+	}
+	chunk := &dto.UploadChunk{Start: -1, Total: -1, Data: data}
+	if header := c.GetHeader("Content-Range"); header != "" {
+		if err := parseContentRange(header, chunk); err != nil {
+			web.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	session, err := h.uploadService.WriteChunk(c.Request.Context(), c.Param("id"), c.Param("uploadId"), chunk)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setUploadOffset(c, session)
+	web.Success(c, session)
+}
+
+// Complete 完成上传，digest 查询参数为整个文件的摘要 sha256:<hex>
+func (h *UploadHandler) Complete(c *gin.Context) {
+	digest := c.Query("digest")
+	if digest == "" {
+		web.BadRequest(c, "query parameter digest is required")
+		return
+	}
+
+	artifact, err := h.uploadService.Complete(c.Request.Context(), c.Param("id"), c.Param("uploadId"), digest)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Created(c, artifact)
+}
+
+// Cancel 取消上传会话
+func (h *UploadHandler) Cancel(c *gin.Context) {
+	if err := h.uploadService.Cancel(c.Request.Context(), c.Param("id"), c.Param("uploadId")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// setUploadOffset 通过 Upload-Offset 响应头返回已接收的字节数
+func setUploadOffset(c *gin.Context, session *model.UploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+}
+
+// parseContentRange 解析 Content-Range: bytes start-end/total，total 可以为 *
+func parseContentRange(header string, chunk *dto.UploadChunk) error {
+	invalid := fmt.Errorf("invalid Content-Range %q, expected bytes start-end/total", header)
+
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes ")
+	if !ok {
+		return invalid
+	}
+	rangePart, totalPart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return invalid
+	}
+	startPart, endPart, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return invalid
+	}
+	start, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil || start < 0 {
+		return invalid
+	}
+	end, err := strconv.ParseInt(endPart, 10, 64)
+	if err != nil || end < start {
+		return invalid
+	}
+	if end-start+1 != int64(len(chunk.Data)) {
+		return fmt.Errorf("Content-Range covers %d bytes but body has %d", end-start+1, len(chunk.Data))
+	}
+	if totalPart != "*" {
+		total, err := strconv.ParseInt(totalPart, 10, 64)
+		if err != nil || total <= end {
+			return invalid
+		}
+		chunk.Total = total
+	}
+	chunk.Start = start
+	return nil
+}
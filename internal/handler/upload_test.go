@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// stubUploadService 记录写入分块的上传服务，未实现的方法调用时 panic
+type stubUploadService struct {
+	service.UploadService
+	chunk *dto.UploadChunk
+}
+
+func (s *stubUploadService) WriteChunk(_ context.Context, _, sessionID string, chunk *dto.UploadChunk) (*model.UploadSession, error) {
+	s.chunk = chunk
+	return &model.UploadSession{ID: sessionID, Offset: int64(len(chunk.Data))}, nil
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header    string
+		size      int
+		wantStart int64
+		wantTotal int64
+		wantErr   bool
+	}{
+		{header: "bytes 0-3/10", size: 4, wantStart: 0, wantTotal: 10},
+		{header: "bytes 4-9/*", size: 6, wantStart: 4, wantTotal: -1},
+		{header: " bytes 0-0/1 ", size: 1, wantStart: 0, wantTotal: 1},
+		{header: "0-3/10", size: 4, wantErr: true},
+		{header: "bytes 0-3", size: 4, wantErr: true},
+		{header: "bytes 3-0/10", size: 4, wantErr: true},
+		{header: "bytes -1-3/10", size: 5, wantErr: true},
+		{header: "bytes 0-3/10", size: 3, wantErr: true},
+		{header: "bytes 0-9/9", size: 10, wantErr: true},
+		{header: "bytes 0-3/x", size: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			chunk := &dto.UploadChunk{Start: -1, Total: -1, Data: make([]byte, tt.size)}
+			err := parseContentRange(tt.header, chunk)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, chunk.Start)
+			assert.Equal(t, tt.wantTotal, chunk.Total)
+		})
+	}
+}
+
+func TestUploadHandler_WriteChunk(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int64
+		contentRange string
+		wantStatus   int
+		wantStart    int64
+	}{
+		{name: "append", limit: 8, wantStatus: http.StatusOK, wantStart: -1},
+		{name: "content_range", limit: 8, contentRange: "bytes 2-5/6", wantStatus: http.StatusOK, wantStart: 2},
+		{name: "invalid_content_range", limit: 8, contentRange: "bytes 0-1/6", wantStatus: http.StatusBadRequest},
+		{name: "chunk_too_large", limit: 3, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads := &stubUploadService{}
+			h := NewUploadHandler(testConfig(tt.limit), testLogger(), uploads)
+			req := httptest.NewRequest(http.MethodPatch, "/repositories/releases/uploads/u1", strings.NewReader("data"))
+			if tt.contentRange != "" {
+				req.Header.Set("Content-Range", tt.contentRange)
+			}
+
+			recorder := serve(t, http.MethodPatch, "/repositories/:id/uploads/:uploadId", h.WriteChunk, req)
+			assert.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				assert.Nil(t, uploads.chunk)
+				return
+			}
+			require.NotNil(t, uploads.chunk)
+			assert.Equal(t, tt.wantStart, uploads.chunk.Start)
+			assert.Equal(t, []byte("data"), uploads.chunk.Data)
+			assert.Equal(t, "4", recorder.Header().Get("Upload-Offset"))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/laolishu/go-nexus/core/web"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// UserHandler 处理用户与角色相关的 HTTP 请求
+type UserHandler struct {
+	logger      *slog.Logger
+	userService service.UserService
+}
+
+// NewUserHandler 创建新的用户处理器
+func NewUserHandler(logger *slog.Logger, userService service.UserService) *UserHandler {
+	return &UserHandler{
+		logger:      logger,
+		userService: userService,
+	}
+}
+
+// RegisterRoutes 注册用户与角色路由
+func (h *UserHandler) RegisterRoutes() {
+	web.RegisterApiHandle(http.MethodGet, "/users", h.ListUsers)
+	web.RegisterApiHandle(http.MethodPost, "/users", h.CreateUser)
+	web.RegisterApiHandle(http.MethodGet, "/users/:id", h.GetUser)
+	web.RegisterApiHandle(http.MethodPut, "/users/:id", h.UpdateUser)
+	web.RegisterApiHandle(http.MethodDelete, "/users/:id", h.DeleteUser)
+	web.RegisterApiHandle(http.MethodPut, "/users/:id/password", h.ChangePassword)
+	web.RegisterApiHandle(http.MethodPut, "/users/:id/roles", h.AssignRoles)
+	web.RegisterApiHandle(http.MethodGet, "/roles", h.ListRoles)
+	web.RegisterApiHandle(http.MethodPost, "/roles", h.CreateRole)
+	web.RegisterApiHandle(http.MethodGet, "/roles/:id", h.GetRole)
+	web.RegisterApiHandle(http.MethodPut, "/roles/:id", h.UpdateRole)
+	web.RegisterApiHandle(http.MethodDelete, "/roles/:id", h.DeleteRole)
+}
+
+// ListUsers 列出用户，支持按状态与关键字过滤
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	var query dto.ListUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	users, err := h.userService.List(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, users)
+}
+
+// CreateUser 创建用户
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.userService.Create(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "user:"+user.Username)
+	web.Created(c, user)
+}
+
+// GetUser 获取用户
+func (h *UserHandler) GetUser(c *gin.Context) {
+	user, err := h.userService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, user)
+}
+
+// UpdateUser 更新用户信息、状态或重置密码
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	var req dto.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.userService.Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, user)
+}
+
+// DeleteUser 删除用户
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	if err := h.userService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// ChangePassword 校验当前密码后修改密码
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), c.Param("id"), &req); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
+
+// AssignRoles 替换用户的角色
+func (h *UserHandler) AssignRoles(c *gin.Context) {
+	var req dto.AssignRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.userService.AssignRoles(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, user)
+}
+
+// ListRoles 列出角色
+func (h *UserHandler) ListRoles(c *gin.Context) {
+	roles, err := h.userService.ListRoles(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, roles)
+}
+
+// CreateRole 创建角色
+func (h *UserHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	role, err := h.userService.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	setAuditResource(c, "role:"+role.Name)
+	web.Created(c, role)
+}
+
+// GetRole 获取角色
+func (h *UserHandler) GetRole(c *gin.Context) {
+	role, err := h.userService.GetRole(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, role)
+}
+
+// UpdateRole 更新角色
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.BadRequest(c, err.Error())
+		return
+	}
+
+	role, err := h.userService.UpdateRole(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, role)
+}
+
+// DeleteRole 删除角色
+func (h *UserHandler) DeleteRole(c *gin.Context) {
+	if err := h.userService.DeleteRole(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, h.logger, err)
+		return
+	}
+	web.Success(c, nil)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// passwordHash stubUserService 返回的用户携带的密码哈希，响应中不应出现
+const passwordHash = "$2a$10$abcdefghijklmnopqrstuv"
+
+// stubUserService 记录调用参数的用户服务
+type stubUserService struct {
+	service.UserService
+	id       string
+	create   *dto.CreateUserRequest
+	update   *dto.UpdateUserRequest
+	password *dto.ChangePasswordRequest
+	roles    *dto.AssignRolesRequest
+	query    *dto.ListUsersQuery
+}
+
+func (s *stubUserService) user(username string) *model.User {
+	return &model.User{ID: "u1", Username: username, Email: username + "@example.com", Password: passwordHash, Status: model.UserStatusActive}
+}
+
+func (s *stubUserService) Create(_ context.Context, req *dto.CreateUserRequest) (*model.User, error) {
+	s.create = req
+	return s.user(req.Username), nil
+}
+
+func (s *stubUserService) Get(_ context.Context, id string) (*model.User, error) {
+	if id == "missing" {
+		return nil, errs.NotFound("user %q not found", id)
+	}
+	return s.user(id), nil
+}
+
+func (s *stubUserService) List(_ context.Context, query *dto.ListUsersQuery) ([]*model.User, error) {
+	s.query = query
+	return []*model.User{s.user("alice")}, nil
+}
+
+func (s *stubUserService) Update(_ context.Context, id string, req *dto.UpdateUserRequest) (*model.User, error) {
+	s.id, s.update = id, req
+	return s.user(id), nil
+}
+
+func (s *stubUserService) Delete(_ context.Context, id string) error {
+	return errs.Conflict("user %q is the last active administrator", id)
+}
+
+func (s *stubUserService) ChangePassword(_ context.Context, id string, req *dto.ChangePasswordRequest) error {
+	if req.CurrentPassword == "wrong-password" {
+		return errs.Forbidden("current password is incorrect")
+	}
+	s.id, s.password = id, req
+	return nil
+}
+
+func (s *stubUserService) AssignRoles(_ context.Context, id string, req *dto.AssignRolesRequest) (*model.User, error) {
+	s.id, s.roles = id, req
+	return s.user(id), nil
+}
+
+func TestUserHandler(t *testing.T) {
+	t.Run("create_hides_hash", func(t *testing.T) {
+		stub := &stubUserService{}
+		h := NewUserHandler(testLogger(), stub)
+		body := `{"username":"alice","email":"alice@example.com","password":"correct-horse","roles":["developer"]}`
+		recorder := serve(t, http.MethodPost, "/users", h.CreateUser, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		assert.Equal(t, []string{"developer"}, stub.create.Roles)
+		assert.NotContains(t, recorder.Body.String(), passwordHash)
+		assert.NotContains(t, recorder.Body.String(), "password")
+	})
+
+	t.Run("create_validation", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+		}{
+			{name: "missing_password", body: `{"username":"alice","email":"alice@example.com"}`},
+			{name: "invalid_email", body: `{"username":"alice","email":"alice","password":"correct-horse"}`},
+			{name: "invalid_status", body: `{"username":"alice","email":"alice@example.com","password":"correct-horse","status":"banned"}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stub := &stubUserService{}
+				h := NewUserHandler(testLogger(), stub)
+				recorder := serve(t, http.MethodPost, "/users", h.CreateUser, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body)))
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Nil(t, stub.create)
+			})
+		}
+	})
+
+	t.Run("list_hides_hash", func(t *testing.T) {
+		stub := &stubUserService{}
+		h := NewUserHandler(testLogger(), stub)
+		recorder := serve(t, http.MethodGet, "/users", h.ListUsers, httptest.NewRequest(http.MethodGet, "/users?status=locked&q=ali", nil))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, &dto.ListUsersQuery{Status: model.UserStatusLocked, Q: "ali"}, stub.query)
+		assert.NotContains(t, recorder.Body.String(), passwordHash)
+	})
+
+	t.Run("get_missing", func(t *testing.T) {
+		h := NewUserHandler(testLogger(), &stubUserService{})
+		recorder := serve(t, http.MethodGet, "/users/:id", h.GetUser, httptest.NewRequest(http.MethodGet, "/users/missing", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("update_status", func(t *testing.T) {
+		stub := &stubUserService{}
+		h := NewUserHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPut, "/users/alice", strings.NewReader(`{"status":"locked"}`))
+		recorder := serve(t, http.MethodPut, "/users/:id", h.UpdateUser, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		require.NotNil(t, stub.update.Status)
+		assert.Equal(t, model.UserStatusLocked, *stub.update.Status)
+		assert.Nil(t, stub.update.Password)
+		assert.NotContains(t, recorder.Body.String(), passwordHash)
+	})
+
+	t.Run("delete_last_admin", func(t *testing.T) {
+		h := NewUserHandler(testLogger(), &stubUserService{})
+		recorder := serve(t, http.MethodDelete, "/users/:id", h.DeleteUser, httptest.NewRequest(http.MethodDelete, "/users/admin", nil))
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("change_password", func(t *testing.T) {
+		stub := &stubUserService{}
+		h := NewUserHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPut, "/users/alice/password", strings.NewReader(`{"current_password":"correct-horse","new_password":"battery-staple"}`))
+		recorder := serve(t, http.MethodPut, "/users/:id/password", h.ChangePassword, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "alice", stub.id)
+		assert.Equal(t, "battery-staple", stub.password.NewPassword)
+	})
+
+	t.Run("change_password_wrong_current", func(t *testing.T) {
+		h := NewUserHandler(testLogger(), &stubUserService{})
+		req := httptest.NewRequest(http.MethodPut, "/users/alice/password", strings.NewReader(`{"current_password":"wrong-password","new_password":"battery-staple"}`))
+		recorder := serve(t, http.MethodPut, "/users/:id/password", h.ChangePassword, req)
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+
+	t.Run("assign_roles_requires_list", func(t *testing.T) {
+		h := NewUserHandler(testLogger(), &stubUserService{})
+		req := httptest.NewRequest(http.MethodPut, "/users/alice/roles", strings.NewReader(`{}`))
+		recorder := serve(t, http.MethodPut, "/users/:id/roles", h.AssignRoles, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("assign_roles", func(t *testing.T) {
+		stub := &stubUserService{}
+		h := NewUserHandler(testLogger(), stub)
+		req := httptest.NewRequest(http.MethodPut, "/users/alice/roles", strings.NewReader(`{"roles":["viewer"]}`))
+		recorder := serve(t, http.MethodPut, "/users/:id/roles", h.AssignRoles, req)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, []string{"viewer"}, stub.roles.Roles)
+	})
+}
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// errEntryNotFound 压缩包中未找到目标文件
+var errEntryNotFound = errors.New("archive entry not found")
+
+// maxEntrySize 从压缩包中读取单个描述文件的最大字节数
+const maxEntrySize = 4 << 20
+
+// readTarGzEntry 从 tar.gz 数据中读取第一个匹配的文件
+func readTarGzEntry(data []byte, match func(name string) bool) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip data: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errEntryNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid tar data: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && match(hdr.Name) {
+			return io.ReadAll(io.LimitReader(tr, maxEntrySize))
+		}
+	}
+}
+
+// readZipEntry 从 zip（jar/war）数据中读取第一个匹配的文件
+func readZipEntry(data []byte, match func(name string) bool) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip data: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !match(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(io.LimitReader(rc, maxEntrySize))
+	}
+	return nil, errEntryNotFound
+}
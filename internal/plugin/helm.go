@@ -0,0 +1,178 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// helmIndexFile Helm 仓库索引文件名
+const helmIndexFile = "index.yaml"
+
+// helmChartPattern chart 包文件名 name-version.tgz，版本以数字开头
+var helmChartPattern = regexp.MustCompile(`^(.+?)-(v?\d[^/]*)\.tgz$`)
+
+// HelmPlugin 内置 Helm 格式插件
+type HelmPlugin struct{}
+
+// NewHelmPlugin 创建 Helm 格式插件
+func NewHelmPlugin() *HelmPlugin {
+	return &HelmPlugin{}
+}
+
+// Name 返回插件名称
+func (p *HelmPlugin) Name() string {
+	return "helm-plugin"
+}
+
+// Version 返回插件版本
+func (p *HelmPlugin) Version() string {
+	return "1.0.0"
+}
+
+// Initialize 初始化插件
+func (p *HelmPlugin) Initialize(ctx context.Context, config map[string]interface{}) error {
+	return nil
+}
+
+// Shutdown 关闭插件
+func (p *HelmPlugin) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Format 返回支持的格式名称
+func (p *HelmPlugin) Format() string {
+	return "helm"
+}
+
+// ValidatePath 验证路径格式，仅允许 index.yaml 与 chart 包
+func (p *HelmPlugin) ValidatePath(filePath string) error {
+	name := path.Base(filePath)
+	if name == helmIndexFile || strings.HasSuffix(name, ".tgz") || strings.HasSuffix(name, ".tgz.prov") {
+		return nil
+	}
+	return fmt.Errorf("invalid helm path format: %s", filePath)
+}
+
+// ParseMetadata 解析 Chart.yaml，支持直接传入 YAML 或 chart 包
+func (p *HelmPlugin) ParseMetadata(ctx context.Context, data []byte) (*pluginapi.Metadata, error) {
+	chartData, err := readTarGzEntry(data, func(name string) bool {
+		return strings.Count(name, "/") == 1 && strings.HasSuffix(name, "/Chart.yaml")
+	})
+	if err != nil {
+		chartData = data
+	}
+
+	var chart helmChart
+	if err := yaml.Unmarshal(chartData, &chart); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+	if chart.Name == "" {
+		return nil, fmt.Errorf("chart name is missing")
+	}
+
+	return &pluginapi.Metadata{
+		Name:        chart.Name,
+		Version:     chart.Version,
+		Description: chart.Description,
+		Keywords:    chart.Keywords,
+	}, nil
+}
+
+// GenerateMetadata 生成 index.yaml
+func (p *HelmPlugin) GenerateMetadata(ctx context.Context, artifacts []*pluginapi.Artifact) ([]byte, error) {
+	index := helmIndex{
+		APIVersion: "v1",
+		Entries:    make(map[string][]map[string]interface{}),
+		Generated:  time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, a := range artifacts {
+		entry := map[string]interface{}{
+			"name":    a.Name,
+			"version": a.Version,
+			"urls":    []string{strings.TrimPrefix(a.Path, "/")},
+			"digest":  a.Checksum,
+			"created": a.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if a.Metadata != nil {
+			entry["description"] = a.Metadata.Description
+			entry["keywords"] = a.Metadata.Keywords
+		}
+		index.Entries[a.Name] = append(index.Entries[a.Name], entry)
+	}
+	return yaml.Marshal(&index)
+}
+
+// IsMetadataPath 判断路径是否为 index.yaml
+func (p *HelmPlugin) IsMetadataPath(filePath string) bool {
+	return path.Base(filePath) == helmIndexFile
+}
+
+// Coordinates 从 chart 包文件名解析 chart 名称与版本
+func (p *HelmPlugin) Coordinates(filePath string) (string, string, bool) {
+	m := helmChartPattern.FindStringSubmatch(path.Base(filePath))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// MetadataPath chart 包统一记录在仓库根目录的 index.yaml 中
+func (p *HelmPlugin) MetadataPath(filePath string) (string, bool) {
+	if _, _, ok := p.Coordinates(filePath); !ok {
+		return "", false
+	}
+	return helmIndexFile, true
+}
+
+// MergeMetadata 合并多个成员的 index.yaml
+// 同名 chart 的版本列表取并集，同一版本靠前的成员优先
+func (p *HelmPlugin) MergeMetadata(ctx context.Context, filePath string, documents [][]byte) ([]byte, error) {
+	merged := helmIndex{
+		APIVersion: "v1",
+		Entries:    make(map[string][]map[string]interface{}),
+		Generated:  time.Now().UTC().Format(time.RFC3339),
+	}
+	seen := make(map[string]bool)
+
+	for i, data := range documents {
+		var index helmIndex
+		if err := yaml.Unmarshal(data, &index); err != nil {
+			return nil, fmt.Errorf("failed to parse helm index from member %d: %w", i, err)
+		}
+		for name, versions := range index.Entries {
+			for _, entry := range versions {
+				key := name + "@" + fmt.Sprint(entry["version"])
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				merged.Entries[name] = append(merged.Entries[name], entry)
+			}
+		}
+	}
+
+	return yaml.Marshal(&merged)
+}
+
+// helmIndex index.yaml 文件结构
+type helmIndex struct {
+	APIVersion string                              `yaml:"apiVersion"`
+	Entries    map[string][]map[string]interface{} `yaml:"entries"`
+	Generated  string                              `yaml:"generated"`
+}
+
+// helmChart Chart.yaml 文件结构（仅包含需要的字段）
+type helmChart struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description"`
+	Keywords    []string `yaml:"keywords"`
+}
@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHelmPlugin_Coordinates(t *testing.T) {
+	p := NewHelmPlugin()
+	tests := []struct {
+		path        string
+		wantName    string
+		wantVersion string
+		wantOK      bool
+	}{
+		{"nginx-1.2.3.tgz", "nginx", "1.2.3", true},
+		{"charts/my-app-v0.1.0-rc.1.tgz", "my-app", "v0.1.0-rc.1", true},
+		{"index.yaml", "", "", false},
+		{"nginx.tgz", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			name, version, ok := p.Coordinates(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+
+	meta, ok := p.MetadataPath("charts/nginx-1.2.3.tgz")
+	assert.True(t, ok)
+	assert.Equal(t, "index.yaml", meta)
+	assert.NoError(t, p.ValidatePath("nginx-1.2.3.tgz.prov"))
+	assert.Error(t, p.ValidatePath("nginx/Chart.yaml"))
+}
+
+func TestHelmPlugin_MergeMetadata(t *testing.T) {
+	p := NewHelmPlugin()
+	first := `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 1.0.0
+      digest: first
+`
+	second := `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 1.0.0
+      digest: second
+    - name: nginx
+      version: 1.1.0
+      digest: second
+  redis:
+    - name: redis
+      version: 7.0.0
+`
+
+	data, err := p.MergeMetadata(context.Background(), "index.yaml", [][]byte{[]byte(first), []byte(second)})
+	require.NoError(t, err)
+
+	var index helmIndex
+	require.NoError(t, yaml.Unmarshal(data, &index))
+	require.Len(t, index.Entries["nginx"], 2)
+	assert.Equal(t, "first", index.Entries["nginx"][0]["digest"])
+	assert.Equal(t, "1.1.0", index.Entries["nginx"][1]["version"])
+	assert.Len(t, index.Entries["redis"], 1)
+	assert.NotEmpty(t, index.Generated)
+
+	_, err = p.MergeMetadata(context.Background(), "index.yaml", [][]byte{[]byte("entries: [")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// Manager 插件管理器，负责格式插件的注册与查找，并向集成插件分发事件
+type Manager struct {
+	logger       *slog.Logger
+	mutex        sync.RWMutex
+	formats      map[string]pluginapi.FormatPlugin
+	integrations []pluginapi.IntegrationPlugin
+}
+
+// NewManager 创建插件管理器并注册内置格式插件
+func NewManager(logger *slog.Logger) *Manager {
+	m := &Manager{
+		logger:  logger,
+		formats: make(map[string]pluginapi.FormatPlugin),
+	}
+
+	m.RegisterFormatPlugin(NewMavenPlugin())
+	m.RegisterFormatPlugin(NewNPMPlugin())
+	m.RegisterFormatPlugin(NewHelmPlugin())
+
+	return m
+}
+
+// RegisterFormatPlugin 注册格式插件，同名格式后注册的覆盖先注册的
+func (m *Manager) RegisterFormatPlugin(p pluginapi.FormatPlugin) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.formats[p.Format()] = p
+	m.logger.Debug("format plugin registered",
+		"name", p.Name(),
+		"version", p.Version(),
+		"format", p.Format(),
+	)
+}
+
+// GetFormatPlugin 根据格式名称获取格式插件
+func (m *Manager) GetFormatPlugin(format string) (pluginapi.FormatPlugin, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if p, ok := m.formats[format]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("format plugin not found: %s", format)
+}
+
+// Formats 返回已注册的格式名称
+func (m *Manager) Formats() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	formats := make([]string, 0, len(m.formats))
+	for format := range m.formats {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// RegisterIntegrationPlugin 注册集成插件
+func (m *Manager) RegisterIntegrationPlugin(p pluginapi.IntegrationPlugin) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.integrations = append(m.integrations, p)
+	m.logger.Debug("integration plugin registered",
+		"name", p.Name(),
+		"version", p.Version(),
+	)
+}
+
+// PublishArtifactEvent 异步通知所有集成插件，插件返回的错误只记录日志
+// deleted 事件调用 OnArtifactDeleted，其他类型调用 OnArtifactUploaded
+func (m *Manager) PublishArtifactEvent(ctx context.Context, event *pluginapi.ArtifactEvent) {
+	m.mutex.RLock()
+	integrations := append([]pluginapi.IntegrationPlugin(nil), m.integrations...)
+	m.mutex.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, p := range integrations {
+		go func(p pluginapi.IntegrationPlugin) {
+			var err error
+			if event.Type == pluginapi.ArtifactEventDeleted {
+				err = p.OnArtifactDeleted(ctx, event)
+			} else {
+				err = p.OnArtifactUploaded(ctx, event)
+			}
+			if err != nil {
+				m.logger.Warn("integration plugin failed to handle artifact event",
+					"plugin", p.Name(),
+					"type", event.Type,
+					"repository", event.Repository,
+					"error", err,
+				)
+			}
+		}(p)
+	}
+}
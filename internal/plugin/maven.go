@@ -0,0 +1,326 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// mavenMetadataFile Maven 元数据文件名
+const mavenMetadataFile = "maven-metadata.xml"
+
+// mavenTimestampLayout maven-metadata.xml 中 lastUpdated 的时间格式
+const mavenTimestampLayout = "20060102150405"
+
+// mavenSidecarExtensions 校验和与签名等附属文件扩展名，不作为独立制品
+var mavenSidecarExtensions = []string{".md5", ".sha1", ".sha256", ".sha512", ".asc", ".sig", ".sigstore"}
+
+// MavenPlugin 内置 Maven 格式插件
+type MavenPlugin struct{}
+
+// NewMavenPlugin 创建 Maven 格式插件
+func NewMavenPlugin() *MavenPlugin {
+	return &MavenPlugin{}
+}
+
+// Name 返回插件名称
+func (p *MavenPlugin) Name() string {
+	return "maven-plugin"
+}
+
+// Version 返回插件版本
+func (p *MavenPlugin) Version() string {
+	return "1.0.0"
+}
+
+// Initialize 初始化插件
+func (p *MavenPlugin) Initialize(ctx context.Context, config map[string]interface{}) error {
+	return nil
+}
+
+// Shutdown 关闭插件
+func (p *MavenPlugin) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Format 返回支持的格式名称
+func (p *MavenPlugin) Format() string {
+	return "maven"
+}
+
+// ValidatePath 验证路径格式
+// Maven 路径格式: groupId/artifactId/version/artifactId-version[-classifier].extension
+// 或 groupId/artifactId/maven-metadata.xml
+func (p *MavenPlugin) ValidatePath(filePath string) error {
+	segments := strings.Split(strings.Trim(filePath, "/"), "/")
+	if len(segments) < 3 {
+		return fmt.Errorf("invalid maven path format: %s", filePath)
+	}
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid maven path format: %s", filePath)
+		}
+	}
+	return nil
+}
+
+// ParseMetadata 解析 artifact 元数据，支持 POM 文件或包含 pom.xml 的 jar 包
+func (p *MavenPlugin) ParseMetadata(ctx context.Context, data []byte) (*pluginapi.Metadata, error) {
+	pomData := data
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		entry, err := readZipEntry(data, func(name string) bool {
+			return strings.HasPrefix(name, "META-INF/maven/") && strings.HasSuffix(name, "/pom.xml")
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate pom.xml: %w", err)
+		}
+		pomData = entry
+	}
+
+	var pom mavenPom
+	if err := xml.Unmarshal(pomData, &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse POM: %w", err)
+	}
+
+	groupID := pom.GroupID
+	if groupID == "" {
+		groupID = pom.Parent.GroupID
+	}
+	version := pom.Version
+	if version == "" {
+		version = pom.Parent.Version
+	}
+
+	dependencies := make(map[string]string, len(pom.Dependencies))
+	for _, dep := range pom.Dependencies {
+		dependencies[dep.GroupID+":"+dep.ArtifactID] = dep.Version
+	}
+
+	return &pluginapi.Metadata{
+		GroupID:      groupID,
+		ArtifactID:   pom.ArtifactID,
+		Version:      version,
+		Name:         pom.Name,
+		Description:  strings.TrimSpace(pom.Description),
+		Packaging:    pom.Packaging,
+		Dependencies: dependencies,
+	}, nil
+}
+
+// GenerateMetadata 为同一 groupId:artifactId 的制品生成 maven-metadata.xml
+// artifacts 需按版本从低到高排序
+func (p *MavenPlugin) GenerateMetadata(ctx context.Context, artifacts []*pluginapi.Artifact) ([]byte, error) {
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("no artifacts to generate metadata")
+	}
+
+	doc := mavenMetadata{}
+	seen := make(map[string]bool)
+	for _, a := range artifacts {
+		groupID, artifactID := mavenCoordinates(a)
+		if doc.GroupID == "" {
+			doc.GroupID, doc.ArtifactID = groupID, artifactID
+		}
+		if a.Version == "" || seen[a.Version] {
+			continue
+		}
+		seen[a.Version] = true
+		doc.Versioning.Versions = append(doc.Versioning.Versions, a.Version)
+		doc.Versioning.Latest = a.Version
+		if !strings.HasSuffix(a.Version, "-SNAPSHOT") {
+			doc.Versioning.Release = a.Version
+		}
+	}
+	doc.Versioning.LastUpdated = time.Now().UTC().Format(mavenTimestampLayout)
+
+	return marshalMavenMetadata(&doc)
+}
+
+// IsMetadataPath 判断路径是否为 maven-metadata.xml
+func (p *MavenPlugin) IsMetadataPath(filePath string) bool {
+	return path.Base(filePath) == mavenMetadataFile
+}
+
+// Coordinates 从路径 groupId/artifactId/version/file 解析 artifactId 与版本
+func (p *MavenPlugin) Coordinates(filePath string) (string, string, bool) {
+	segments := strings.Split(strings.Trim(filePath, "/"), "/")
+	n := len(segments)
+	if n < 4 || p.IsMetadataPath(filePath) || isMavenSidecar(filePath) {
+		return "", "", false
+	}
+	return segments[n-3], segments[n-2], true
+}
+
+// MetadataPath 返回制品对应的 groupId/artifactId/maven-metadata.xml
+func (p *MavenPlugin) MetadataPath(filePath string) (string, bool) {
+	if _, _, ok := p.Coordinates(filePath); !ok {
+		return "", false
+	}
+	return path.Join(path.Dir(path.Dir(strings.Trim(filePath, "/"))), mavenMetadataFile), true
+}
+
+// MergeMetadata 合并多个成员的 maven-metadata.xml
+// 版本列表取并集并保持成员顺序，latest/release/snapshot 取 lastUpdated 最新的文档
+func (p *MavenPlugin) MergeMetadata(ctx context.Context, filePath string, documents [][]byte) ([]byte, error) {
+	var merged *mavenMetadata
+	var newest *mavenMetadata
+	seenVersions := make(map[string]bool)
+	seenPlugins := make(map[string]bool)
+
+	for i, data := range documents {
+		var doc mavenMetadata
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse maven metadata from member %d: %w", i, err)
+		}
+
+		if merged == nil {
+			merged = &mavenMetadata{
+				GroupID:    doc.GroupID,
+				ArtifactID: doc.ArtifactID,
+				Version:    doc.Version,
+			}
+		}
+		if newest == nil || doc.Versioning.LastUpdated > newest.Versioning.LastUpdated {
+			d := doc
+			newest = &d
+		}
+
+		for _, v := range doc.Versioning.Versions {
+			if !seenVersions[v] {
+				seenVersions[v] = true
+				merged.Versioning.Versions = append(merged.Versioning.Versions, v)
+			}
+		}
+		if doc.Plugins != nil {
+			for _, entry := range doc.Plugins.Items {
+				if seenPlugins[entry.Prefix] {
+					continue
+				}
+				seenPlugins[entry.Prefix] = true
+				if merged.Plugins == nil {
+					merged.Plugins = &mavenPlugins{}
+				}
+				merged.Plugins.Items = append(merged.Plugins.Items, entry)
+			}
+		}
+	}
+	if merged == nil {
+		return nil, fmt.Errorf("no maven metadata to merge")
+	}
+
+	merged.Versioning.Latest = newest.Versioning.Latest
+	merged.Versioning.Release = newest.Versioning.Release
+	merged.Versioning.LastUpdated = newest.Versioning.LastUpdated
+	merged.Versioning.Snapshot = newest.Versioning.Snapshot
+	merged.Versioning.SnapshotVersions = newest.Versioning.SnapshotVersions
+
+	return marshalMavenMetadata(merged)
+}
+
+// isMavenSidecar 判断路径是否为校验和或签名附属文件
+func isMavenSidecar(filePath string) bool {
+	for _, ext := range mavenSidecarExtensions {
+		if strings.HasSuffix(filePath, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// mavenCoordinates 从制品元数据或路径中取出 groupId 与 artifactId
+func mavenCoordinates(a *pluginapi.Artifact) (string, string) {
+	if a.Metadata != nil && a.Metadata.GroupID != "" && a.Metadata.ArtifactID != "" {
+		return a.Metadata.GroupID, a.Metadata.ArtifactID
+	}
+	// groupId/artifactId/version/file
+	segments := strings.Split(strings.Trim(a.Path, "/"), "/")
+	if len(segments) < 4 {
+		return "", a.Name
+	}
+	n := len(segments)
+	return strings.Join(segments[:n-3], "."), segments[n-3]
+}
+
+// marshalMavenMetadata 序列化 maven-metadata.xml
+func marshalMavenMetadata(doc *mavenMetadata) ([]byte, error) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal maven metadata: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// mavenMetadata maven-metadata.xml 文件结构
+type mavenMetadata struct {
+	XMLName    xml.Name        `xml:"metadata"`
+	GroupID    string          `xml:"groupId,omitempty"`
+	ArtifactID string          `xml:"artifactId,omitempty"`
+	Version    string          `xml:"version,omitempty"`
+	Versioning mavenVersioning `xml:"versioning"`
+	Plugins    *mavenPlugins   `xml:"plugins,omitempty"`
+}
+
+type mavenVersioning struct {
+	Latest           string                 `xml:"latest,omitempty"`
+	Release          string                 `xml:"release,omitempty"`
+	Snapshot         *mavenSnapshot         `xml:"snapshot,omitempty"`
+	Versions         []string               `xml:"versions>version,omitempty"`
+	LastUpdated      string                 `xml:"lastUpdated,omitempty"`
+	SnapshotVersions *mavenSnapshotVersions `xml:"snapshotVersions,omitempty"`
+}
+
+type mavenSnapshot struct {
+	Timestamp   string `xml:"timestamp,omitempty"`
+	BuildNumber int    `xml:"buildNumber,omitempty"`
+	LocalCopy   bool   `xml:"localCopy,omitempty"`
+}
+
+type mavenSnapshotVersions struct {
+	Items []mavenSnapshotVersion `xml:"snapshotVersion"`
+}
+
+type mavenSnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension,omitempty"`
+	Value      string `xml:"value,omitempty"`
+	Updated    string `xml:"updated,omitempty"`
+}
+
+type mavenPlugins struct {
+	Items []mavenPluginEntry `xml:"plugin"`
+}
+
+type mavenPluginEntry struct {
+	Name       string `xml:"name,omitempty"`
+	Prefix     string `xml:"prefix"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+// mavenPom POM 文件结构（仅包含需要的字段）
+type mavenPom struct {
+	XMLName     xml.Name `xml:"project"`
+	GroupID     string   `xml:"groupId"`
+	ArtifactID  string   `xml:"artifactId"`
+	Version     string   `xml:"version"`
+	Packaging   string   `xml:"packaging"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"description"`
+	Parent      struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Dependencies []mavenDependency `xml:"dependencies>dependency"`
+}
+
+type mavenDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
@@ -0,0 +1,175 @@
+package plugin
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+func TestMavenPlugin_ValidatePath(t *testing.T) {
+	p := NewMavenPlugin()
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"com/example/lib/1.0/lib-1.0.jar", false},
+		{"com/example/lib/maven-metadata.xml", false},
+		{"lib/1.0", true},
+		{"com/../lib/1.0/lib-1.0.jar", true},
+		{"com/example/./1.0/lib-1.0.jar", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := p.ValidatePath(tt.path)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestMavenPlugin_Coordinates(t *testing.T) {
+	p := NewMavenPlugin()
+	tests := []struct {
+		path        string
+		wantName    string
+		wantVersion string
+		wantOK      bool
+		wantMeta    string
+	}{
+		{path: "com/example/lib/1.0/lib-1.0.jar", wantName: "lib", wantVersion: "1.0", wantOK: true, wantMeta: "com/example/lib/maven-metadata.xml"},
+		{path: "org/lib/2.0-SNAPSHOT/lib-2.0-SNAPSHOT.pom", wantName: "lib", wantVersion: "2.0-SNAPSHOT", wantOK: true, wantMeta: "org/lib/maven-metadata.xml"},
+		{path: "com/example/lib/1.0/lib-1.0.jar.sha1"},
+		{path: "com/example/lib/1.0/lib-1.0.jar.asc"},
+		{path: "com/example/lib/maven-metadata.xml"},
+		{path: "lib/1.0/lib-1.0.jar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			name, version, ok := p.Coordinates(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantVersion, version)
+
+			meta, ok := p.MetadataPath(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantMeta, meta)
+		})
+	}
+}
+
+func TestMavenPlugin_GenerateMetadata(t *testing.T) {
+	p := NewMavenPlugin()
+	artifacts := []*pluginapi.Artifact{
+		{Name: "lib", Version: "1.0", Path: "com/example/lib/1.0/lib-1.0.jar"},
+		{Name: "lib", Version: "1.0", Path: "com/example/lib/1.0/lib-1.0.pom"},
+		{Name: "lib", Version: "1.1", Path: "com/example/lib/1.1/lib-1.1.jar"},
+		{Name: "lib", Version: "2.0-SNAPSHOT", Path: "com/example/lib/2.0-SNAPSHOT/lib-2.0-SNAPSHOT.jar"},
+	}
+
+	data, err := p.GenerateMetadata(context.Background(), artifacts)
+	require.NoError(t, err)
+
+	doc := parseMavenMetadata(t, data)
+	assert.Equal(t, "com.example", doc.GroupID)
+	assert.Equal(t, "lib", doc.ArtifactID)
+	assert.Equal(t, []string{"1.0", "1.1", "2.0-SNAPSHOT"}, doc.Versioning.Versions)
+	assert.Equal(t, "2.0-SNAPSHOT", doc.Versioning.Latest)
+	assert.Equal(t, "1.1", doc.Versioning.Release)
+	assert.Len(t, doc.Versioning.LastUpdated, len(mavenTimestampLayout))
+
+	_, err = p.GenerateMetadata(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestMavenPlugin_MergeMetadata(t *testing.T) {
+	p := NewMavenPlugin()
+	older := `<metadata><groupId>com.example</groupId><artifactId>lib</artifactId>
+<versioning><latest>1.1</latest><release>1.1</release><versions><version>1.0</version><version>1.1</version></versions>
+<lastUpdated>20240101000000</lastUpdated></versioning>
+<plugins><plugin><prefix>lib</prefix><artifactId>lib-maven-plugin</artifactId></plugin></plugins></metadata>`
+	newer := `<metadata><groupId>com.example</groupId><artifactId>lib</artifactId>
+<versioning><latest>2.0</latest><release>2.0</release><versions><version>1.1</version><version>2.0</version></versions>
+<lastUpdated>20250101000000</lastUpdated></versioning>
+<plugins><plugin><prefix>lib</prefix><artifactId>other</artifactId></plugin><plugin><prefix>x</prefix><artifactId>x-maven-plugin</artifactId></plugin></plugins></metadata>`
+
+	tests := []struct {
+		name         string
+		documents    []string
+		wantVersions []string
+		wantLatest   string
+		wantPlugins  []string
+		wantErr      bool
+	}{
+		{
+			name:         "union_in_member_order",
+			documents:    []string{older, newer},
+			wantVersions: []string{"1.0", "1.1", "2.0"},
+			wantLatest:   "2.0",
+			wantPlugins:  []string{"lib-maven-plugin", "x-maven-plugin"},
+		},
+		{
+			name:         "newest_wins_regardless_of_order",
+			documents:    []string{newer, older},
+			wantVersions: []string{"1.1", "2.0", "1.0"},
+			wantLatest:   "2.0",
+			wantPlugins:  []string{"other", "x-maven-plugin"},
+		},
+		{name: "invalid_document", documents: []string{older, "<metadata"}, wantErr: true},
+		{name: "no_documents", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documents := make([][]byte, len(tt.documents))
+			for i, d := range tt.documents {
+				documents[i] = []byte(d)
+			}
+			data, err := p.MergeMetadata(context.Background(), "com/example/lib/maven-metadata.xml", documents)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			doc := parseMavenMetadata(t, data)
+			assert.Equal(t, tt.wantVersions, doc.Versioning.Versions)
+			assert.Equal(t, tt.wantLatest, doc.Versioning.Latest)
+			assert.Equal(t, "20250101000000", doc.Versioning.LastUpdated)
+			var plugins []string
+			for _, entry := range doc.Plugins.Items {
+				plugins = append(plugins, entry.ArtifactID)
+			}
+			assert.Equal(t, tt.wantPlugins, plugins)
+		})
+	}
+}
+
+func TestMavenPlugin_ParseMetadata(t *testing.T) {
+	p := NewMavenPlugin()
+	pom := `<project><parent><groupId>com.example</groupId><version>3.0</version></parent>
+<artifactId>lib</artifactId><name>Lib</name><description> A library </description>
+<dependencies><dependency><groupId>org.x</groupId><artifactId>y</artifactId><version>1.2</version></dependency></dependencies></project>`
+
+	metadata, err := p.ParseMetadata(context.Background(), []byte(pom))
+	require.NoError(t, err)
+	assert.Equal(t, "com.example", metadata.GroupID)
+	assert.Equal(t, "3.0", metadata.Version)
+	assert.Equal(t, "A library", metadata.Description)
+	assert.Equal(t, map[string]string{"org.x:y": "1.2"}, metadata.Dependencies)
+
+	_, err = p.ParseMetadata(context.Background(), []byte("not a jar"))
+	assert.Error(t, err)
+}
+
+func parseMavenMetadata(t *testing.T, data []byte) *mavenMetadata {
+	t.Helper()
+	var doc mavenMetadata
+	require.NoError(t, xml.Unmarshal(data, &doc))
+	if doc.Plugins == nil {
+		doc.Plugins = &mavenPlugins{}
+	}
+	return &doc
+}
@@ -0,0 +1,237 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// npmPathPatterns npm 合法路径
+var npmPathPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^/@[^/]+/[^/]+/-/[^/]+-[^/]+\.tgz$`),
+	regexp.MustCompile(`^/[^/@][^/]*/-/[^/]+-[^/]+\.tgz$`),
+	regexp.MustCompile(`^/@[^/]+/[^/]+$`),
+	regexp.MustCompile(`^/[^/@][^/]*$`),
+}
+
+// npmSignatureExtensions tarball 的分离签名与 provenance 签名包扩展名
+var npmSignatureExtensions = []string{".asc", ".sig", ".sigstore"}
+
+// NPMPlugin 内置 npm 格式插件
+type NPMPlugin struct{}
+
+// NewNPMPlugin 创建 npm 格式插件
+func NewNPMPlugin() *NPMPlugin {
+	return &NPMPlugin{}
+}
+
+// Name 返回插件名称
+func (p *NPMPlugin) Name() string {
+	return "npm-plugin"
+}
+
+// Version 返回插件版本
+func (p *NPMPlugin) Version() string {
+	return "1.0.0"
+}
+
+// Initialize 初始化插件
+func (p *NPMPlugin) Initialize(ctx context.Context, config map[string]interface{}) error {
+	return nil
+}
+
+// Shutdown 关闭插件
+func (p *NPMPlugin) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Format 返回支持的格式名称
+func (p *NPMPlugin) Format() string {
+	return "npm"
+}
+
+// ValidatePath 验证路径格式
+// /@scope/package-name/-/package-name-version.tgz
+// /package-name/-/package-name-version.tgz
+// /@scope/package-name 或 /package-name（包文档）
+// tarball 的签名文件 package-name-version.tgz.asc、.sig、.sigstore
+func (p *NPMPlugin) ValidatePath(filePath string) error {
+	normalized := "/" + strings.TrimPrefix(filePath, "/")
+	for _, ext := range npmSignatureExtensions {
+		if target := strings.TrimSuffix(normalized, ext); target != normalized && strings.HasSuffix(target, ".tgz") {
+			normalized = target
+			break
+		}
+	}
+	for _, pattern := range npmPathPatterns {
+		if pattern.MatchString(normalized) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid npm path format: %s", filePath)
+}
+
+// ParseMetadata 解析 package.json，支持直接传入 JSON 或 npm 打包的 tgz
+func (p *NPMPlugin) ParseMetadata(ctx context.Context, data []byte) (*pluginapi.Metadata, error) {
+	pkgData := data
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		entry, err := readTarGzEntry(data, func(name string) bool {
+			return name == "package/package.json"
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate package.json: %w", err)
+		}
+		pkgData = entry
+	}
+
+	var pkg npmPackage
+	if err := json.Unmarshal(pkgData, &pkg); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	return &pluginapi.Metadata{
+		Name:         pkg.Name,
+		Version:      pkg.Version,
+		Description:  pkg.Description,
+		Keywords:     pkg.Keywords,
+		Dependencies: pkg.Dependencies,
+	}, nil
+}
+
+// GenerateMetadata 为同一个包的所有版本生成包文档
+// artifacts 需按版本从低到高排序，最后一个非预发布版本作为 latest
+func (p *NPMPlugin) GenerateMetadata(ctx context.Context, artifacts []*pluginapi.Artifact) ([]byte, error) {
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("no artifacts to generate metadata")
+	}
+
+	name := artifacts[0].Name
+	versions := make(map[string]interface{}, len(artifacts))
+	times := make(map[string]interface{}, len(artifacts)+2)
+	distTags := make(map[string]interface{})
+	var description string
+
+	for _, a := range artifacts {
+		version := map[string]interface{}{
+			"name":    name,
+			"version": a.Version,
+			"dist": map[string]interface{}{
+				"tarball": a.Path,
+				"shasum":  a.Checksum,
+			},
+		}
+		if a.Metadata != nil {
+			version["description"] = a.Metadata.Description
+			version["keywords"] = a.Metadata.Keywords
+			version["dependencies"] = a.Metadata.Dependencies
+			description = a.Metadata.Description
+		}
+		versions[a.Version] = version
+		times[a.Version] = a.CreatedAt.UTC().Format(time.RFC3339)
+		if !strings.Contains(a.Version, "-") {
+			distTags["latest"] = a.Version
+		}
+	}
+	if _, ok := distTags["latest"]; !ok {
+		distTags["latest"] = artifacts[len(artifacts)-1].Version
+	}
+	times["modified"] = time.Now().UTC().Format(time.RFC3339)
+
+	doc := map[string]interface{}{
+		"name":        name,
+		"description": description,
+		"dist-tags":   distTags,
+		"versions":    versions,
+		"time":        times,
+	}
+	return json.Marshal(doc)
+}
+
+// IsMetadataPath 判断路径是否为包文档（不含 /-/ 的包名路径）
+func (p *NPMPlugin) IsMetadataPath(filePath string) bool {
+	normalized := "/" + strings.TrimPrefix(filePath, "/")
+	return npmPathPatterns[2].MatchString(normalized) || npmPathPatterns[3].MatchString(normalized)
+}
+
+// Coordinates 从 tarball 路径 package-name/-/package-name-version.tgz 解析包名与版本
+func (p *NPMPlugin) Coordinates(filePath string) (string, string, bool) {
+	normalized := "/" + strings.TrimPrefix(filePath, "/")
+	if !npmPathPatterns[0].MatchString(normalized) && !npmPathPatterns[1].MatchString(normalized) {
+		return "", "", false
+	}
+	name, file, _ := strings.Cut(strings.TrimPrefix(normalized, "/"), "/-/")
+	unscoped := name[strings.LastIndex(name, "/")+1:]
+	if !strings.HasPrefix(file, unscoped+"-") {
+		return "", "", false
+	}
+	version := strings.TrimSuffix(strings.TrimPrefix(file, unscoped+"-"), ".tgz")
+	if version == "" {
+		return "", "", false
+	}
+	return name, version, true
+}
+
+// MetadataPath 返回 tarball 所属包文档的路径
+func (p *NPMPlugin) MetadataPath(filePath string) (string, bool) {
+	name, _, ok := p.Coordinates(filePath)
+	return name, ok
+}
+
+// MergeMetadata 合并多个成员的包文档
+// versions、time、dist-tags 按键合并，靠前的成员优先；其余字段取第一个成员
+func (p *NPMPlugin) MergeMetadata(ctx context.Context, filePath string, documents [][]byte) ([]byte, error) {
+	var merged map[string]interface{}
+	for i, data := range documents {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse npm package document from member %d: %w", i, err)
+		}
+
+		if merged == nil {
+			merged = doc
+			continue
+		}
+		for _, key := range []string{"versions", "time", "dist-tags"} {
+			mergeJSONObject(merged, doc, key)
+		}
+	}
+	if merged == nil {
+		return nil, fmt.Errorf("no npm package documents to merge")
+	}
+	delete(merged, "_rev")
+
+	return json.Marshal(merged)
+}
+
+// mergeJSONObject 将 src[key] 中 dst[key] 没有的键补充到 dst[key]
+func mergeJSONObject(dst, src map[string]interface{}, key string) {
+	from, ok := src[key].(map[string]interface{})
+	if !ok {
+		return
+	}
+	to, ok := dst[key].(map[string]interface{})
+	if !ok {
+		to = make(map[string]interface{}, len(from))
+		dst[key] = to
+	}
+	for k, v := range from {
+		if _, exists := to[k]; !exists {
+			to[k] = v
+		}
+	}
+}
+
+// npmPackage package.json 文件结构（仅包含需要的字段）
+type npmPackage struct {
+	Name         string            `json:"name"`
+	Version      string            `json:"version"`
+	Description  string            `json:"description"`
+	Keywords     []string          `json:"keywords"`
+	Dependencies map[string]string `json:"dependencies"`
+}
@@ -0,0 +1,115 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+func TestNPMPlugin_ValidatePath(t *testing.T) {
+	p := NewNPMPlugin()
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"lodash", false},
+		{"@types/node", false},
+		{"lodash/-/lodash-4.17.21.tgz", false},
+		{"@types/node/-/node-20.1.0.tgz", false},
+		{"lodash/-/lodash-4.17.21.tgz.asc", false},
+		{"lodash/-/lodash-4.17.21.tgz.sigstore", false},
+		{"lodash/package.json", true},
+		{"@types", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := p.ValidatePath(tt.path)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestNPMPlugin_Coordinates(t *testing.T) {
+	p := NewNPMPlugin()
+	tests := []struct {
+		path        string
+		wantName    string
+		wantVersion string
+		wantOK      bool
+	}{
+		{"lodash/-/lodash-4.17.21.tgz", "lodash", "4.17.21", true},
+		{"@types/node/-/node-20.1.0-beta.1.tgz", "@types/node", "20.1.0-beta.1", true},
+		{"lodash/-/other-1.0.0.tgz", "", "", false},
+		{"lodash", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			name, version, ok := p.Coordinates(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantVersion, version)
+
+			meta, ok := p.MetadataPath(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantName, meta)
+		})
+	}
+
+	assert.True(t, p.IsMetadataPath("@types/node"))
+	assert.False(t, p.IsMetadataPath("lodash/-/lodash-4.17.21.tgz"))
+}
+
+func TestNPMPlugin_GenerateMetadata(t *testing.T) {
+	p := NewNPMPlugin()
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	artifacts := []*pluginapi.Artifact{
+		{Name: "lib", Version: "1.0.0", Path: "lib/-/lib-1.0.0.tgz", Checksum: "a", CreatedAt: created},
+		{Name: "lib", Version: "1.1.0", Path: "lib/-/lib-1.1.0.tgz", Checksum: "b", CreatedAt: created,
+			Metadata: &pluginapi.Metadata{Description: "newest"}},
+		{Name: "lib", Version: "2.0.0-rc.1", Path: "lib/-/lib-2.0.0-rc.1.tgz", Checksum: "c", CreatedAt: created},
+	}
+
+	data, err := p.GenerateMetadata(context.Background(), artifacts)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "lib", doc["name"])
+	assert.Equal(t, "newest", doc["description"])
+	assert.Equal(t, "1.1.0", doc["dist-tags"].(map[string]interface{})["latest"])
+	assert.Len(t, doc["versions"], 3)
+	assert.Equal(t, "2025-01-01T00:00:00Z", doc["time"].(map[string]interface{})["1.0.0"])
+}
+
+func TestNPMPlugin_MergeMetadata(t *testing.T) {
+	p := NewNPMPlugin()
+	first := `{"name":"lib","_rev":"1-a","description":"first","dist-tags":{"latest":"1.0.0"},
+"versions":{"1.0.0":{"version":"1.0.0","dist":{"tarball":"first"}}},"time":{"1.0.0":"t1"}}`
+	second := `{"name":"lib","description":"second","dist-tags":{"latest":"2.0.0","next":"3.0.0-rc"},
+"versions":{"1.0.0":{"version":"1.0.0","dist":{"tarball":"second"}},"2.0.0":{"version":"2.0.0"}},"time":{"2.0.0":"t2"}}`
+
+	data, err := p.MergeMetadata(context.Background(), "lib", [][]byte{[]byte(first), []byte(second)})
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.NotContains(t, doc, "_rev")
+	assert.Equal(t, "first", doc["description"])
+
+	versions := doc["versions"].(map[string]interface{})
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "first", versions["1.0.0"].(map[string]interface{})["dist"].(map[string]interface{})["tarball"])
+	assert.Equal(t, map[string]interface{}{"latest": "1.0.0", "next": "3.0.0-rc"}, doc["dist-tags"])
+	assert.Equal(t, map[string]interface{}{"1.0.0": "t1", "2.0.0": "t2"}, doc["time"])
+
+	_, err = p.MergeMetadata(context.Background(), "lib", [][]byte{[]byte(first), []byte("{")})
+	assert.Error(t, err)
+	_, err = p.MergeMetadata(context.Background(), "lib", nil)
+	assert.Error(t, err)
+}
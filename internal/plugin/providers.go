@@ -6,5 +6,5 @@ import (
 
 // ProviderSet 插件层的 Wire 提供者集合
 var ProviderSet = wire.NewSet(
-// TODO: 添加具体的插件提供者
+	NewManager,
 )
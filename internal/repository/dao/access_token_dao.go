@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// AccessTokenDAO 个人访问令牌数据访问对象
+type AccessTokenDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewAccessTokenDAO 创建新的个人访问令牌数据访问对象
+func NewAccessTokenDAO(logger *slog.Logger, db *gorm.DB) *AccessTokenDAO {
+	return &AccessTokenDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建令牌记录
+func (d *AccessTokenDAO) Create(ctx context.Context, token *model.AccessToken) error {
+	return d.db.WithContext(ctx).Omit(clause.Associations).Create(token).Error
+}
+
+// Delete 吊销令牌
+func (d *AccessTokenDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Delete(&model.AccessToken{}, "id = ?", id).Error
+}
+
+// FindByID 根据ID查找令牌，不存在时返回 nil
+func (d *AccessTokenDAO) FindByID(ctx context.Context, id string) (*model.AccessToken, error) {
+	return d.findOne(ctx, "id = ?", id)
+}
+
+// FindByHash 根据令牌哈希查找令牌，不存在时返回 nil
+func (d *AccessTokenDAO) FindByHash(ctx context.Context, hash string) (*model.AccessToken, error) {
+	return d.findOne(ctx, "token = ?", hash)
+}
+
+// ListByUser 列出用户的令牌
+func (d *AccessTokenDAO) ListByUser(ctx context.Context, userID string) ([]*model.AccessToken, error) {
+	var tokens []*model.AccessToken
+	err := d.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// UpdateLastUsed 更新令牌的最近使用时间，不修改 updated_at
+func (d *AccessTokenDAO) UpdateLastUsed(ctx context.Context, id string, at time.Time) error {
+	return d.db.WithContext(ctx).Model(&model.AccessToken{}).Where("id = ?", id).UpdateColumn("last_used", at).Error
+}
+
+// findOne 按条件查找单个令牌
+func (d *AccessTokenDAO) findOne(ctx context.Context, query string, args ...interface{}) (*model.AccessToken, error) {
+	var token model.AccessToken
+	err := d.db.WithContext(ctx).Where(query, args...).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
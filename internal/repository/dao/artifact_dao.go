@@ -1,9 +1,16 @@
 package dao
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
 )
 
 // ArtifactDAO 制品数据访问对象
@@ -19,3 +26,148 @@ func NewArtifactDAO(logger *slog.Logger, db *gorm.DB) *ArtifactDAO {
 		db:     db,
 	}
 }
+
+// Create 创建制品记录
+func (d *ArtifactDAO) Create(ctx context.Context, artifact *model.Artifact) error {
+	return d.db.WithContext(ctx).Create(artifact).Error
+}
+
+// Update 更新制品记录
+func (d *ArtifactDAO) Update(ctx context.Context, artifact *model.Artifact) error {
+	return d.db.WithContext(ctx).Save(artifact).Error
+}
+
+// DeleteByRepository 永久删除仓库中的所有制品记录，包括已软删除的记录
+func (d *ArtifactDAO) DeleteByRepository(ctx context.Context, repositoryID string) error {
+	return d.db.WithContext(ctx).Unscoped().Where("repository_id = ?", repositoryID).Delete(&model.Artifact{}).Error
+}
+
+// ListByRepository 列出仓库中的所有制品，按创建时间排序
+func (d *ArtifactDAO) ListByRepository(ctx context.Context, repositoryID string) ([]*model.Artifact, error) {
+	var artifacts []*model.Artifact
+	err := d.db.WithContext(ctx).
+		Where("repository_id = ?", repositoryID).
+		Order("created_at").
+		Find(&artifacts).Error
+	return artifacts, err
+}
+
+// List 按条件分页查询制品，Limit 为 0 时不限制数量
+func (d *ArtifactDAO) List(ctx context.Context, filter *model.ArtifactFilter) ([]*model.Artifact, error) {
+	query := d.db.WithContext(ctx).Model(&model.Artifact{})
+
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if len(filter.RepositoryIDs) > 0 {
+		query = query.Where("repository_id IN ?", filter.RepositoryIDs)
+	}
+	if filter.Name != "" {
+		query = whereMatch(query, "name", filter.Name)
+	}
+	if filter.Version != "" {
+		query = whereMatch(query, "version", filter.Version)
+	}
+	if filter.Format != "" {
+		query = query.Where("format = ?", filter.Format)
+	}
+	if filter.PathPrefix != "" {
+		query = query.Where("path LIKE ? ESCAPE '\\'", escapeLike(filter.PathPrefix)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.Checksum != "" {
+		query = query.Where("checksum = ?", filter.Checksum)
+	}
+	for key, value := range filter.Metadata {
+		query = query.Where(d.jsonField("metadata")+" = ?", key, value)
+	}
+	if filter.Keyword != "" {
+		// keywords 以逗号连接保存，两端补逗号后按整词匹配
+		query = query.Where("',' || "+d.jsonField("metadata")+" || ',' LIKE ? ESCAPE '\\'", "keywords", "%,"+escapeLike(filter.Keyword)+",%")
+	}
+	for key, value := range filter.Properties {
+		query = query.Where(d.jsonField("properties")+" = ?", key, value)
+	}
+	for _, key := range filter.PropertyKeys {
+		query = query.Where(d.jsonField("properties")+" IS NOT NULL", key)
+	}
+
+	sort := filter.Sort
+	if sort == "" {
+		sort = model.ArtifactSortCreatedAt
+	}
+	comparator, direction := ">", "ASC"
+	if filter.Desc {
+		comparator, direction = "<", "DESC"
+	}
+	if filter.After != nil {
+		query = query.Where(
+			fmt.Sprintf("(%[1]s %[2]s ?) OR (%[1]s = ? AND id %[2]s ?)", sort, comparator),
+			filter.After.Value, filter.After.Value, filter.After.ID,
+		)
+	}
+	query = query.Order(sort + " " + direction).Order("id " + direction)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var artifacts []*model.Artifact
+	err := query.Find(&artifacts).Error
+	return artifacts, err
+}
+
+// jsonField 返回按键读取 JSON 文本列的 SQL 表达式，键作为第一个参数绑定
+func (d *ArtifactDAO) jsonField(column string) string {
+	if d.db.Dialector.Name() == "postgres" {
+		return "(" + column + "::jsonb ->> ?)"
+	}
+	return "json_extract(" + column + ", '$.\"' || ? || '\"')"
+}
+
+// whereMatch 按值匹配列，值中包含 * 时作为通配符模糊匹配
+func whereMatch(query *gorm.DB, column, value string) *gorm.DB {
+	if !strings.Contains(value, "*") {
+		return query.Where(column+" = ?", value)
+	}
+	return query.Where(column+" LIKE ? ESCAPE '\\'", strings.ReplaceAll(escapeLike(value), "*", "%"))
+}
+
+// escapeLike 转义 LIKE 模式中的特殊字符
+func escapeLike(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(value)
+}
+
+// Delete 删除制品记录
+func (d *ArtifactDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Delete(&model.Artifact{}, "id = ?", id).Error
+}
+
+// FindByPath 根据仓库和路径查找制品，不存在时返回 nil
+func (d *ArtifactDAO) FindByPath(ctx context.Context, repositoryID, path string) (*model.Artifact, error) {
+	var artifact model.Artifact
+	err := d.db.WithContext(ctx).
+		Where("repository_id = ? AND path = ?", repositoryID, path).
+		First(&artifact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// IncrementDownloadCount 下载次数加一并记录下载时间
+func (d *ArtifactDAO) IncrementDownloadCount(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Model(&model.Artifact{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"download_count":  gorm.Expr("download_count + ?", 1),
+			"last_downloaded": time.Now(),
+		}).Error
+}
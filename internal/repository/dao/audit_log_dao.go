@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"context"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// AuditLogDAO 审计日志数据访问对象
+type AuditLogDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewAuditLogDAO 创建新的审计日志数据访问对象
+func NewAuditLogDAO(logger *slog.Logger, db *gorm.DB) *AuditLogDAO {
+	return &AuditLogDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建审计日志记录
+func (d *AuditLogDAO) Create(ctx context.Context, entry *model.AuditLog) error {
+	return d.db.WithContext(ctx).Create(entry).Error
+}
+
+// List 按条件倒序查询审计日志
+func (d *AuditLogDAO) List(ctx context.Context, filter *model.AuditLogFilter) ([]*model.AuditLog, error) {
+	query := d.db.WithContext(ctx).Model(&model.AuditLog{})
+	if filter.User != "" {
+		query = query.Where("user_id = ? OR username = ?", filter.User, filter.User)
+	}
+	if filter.Action != "" {
+		query = whereMatch(query, "action", filter.Action)
+	}
+	if filter.ResourcePrefix != "" {
+		query = query.Where("resource LIKE ? ESCAPE '\\'", escapeLike(filter.ResourcePrefix)+"%")
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.Status != 0 {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at < ?) OR (created_at = ? AND id < ?)",
+			filter.After.CreatedAt, filter.After.CreatedAt, filter.After.ID)
+	}
+	query = query.Order("created_at DESC").Order("id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []*model.AuditLog
+	err := query.Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// CleanupPolicyDAO 清理策略数据访问对象
+type CleanupPolicyDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewCleanupPolicyDAO 创建新的清理策略数据访问对象
+func NewCleanupPolicyDAO(logger *slog.Logger, db *gorm.DB) *CleanupPolicyDAO {
+	return &CleanupPolicyDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建清理策略记录
+func (d *CleanupPolicyDAO) Create(ctx context.Context, policy *model.CleanupPolicy) error {
+	return d.db.WithContext(ctx).Create(policy).Error
+}
+
+// Update 保存清理策略记录的全部字段
+func (d *CleanupPolicyDAO) Update(ctx context.Context, policy *model.CleanupPolicy) error {
+	return d.db.WithContext(ctx).Save(policy).Error
+}
+
+// Delete 永久删除清理策略记录，名称可以重新使用
+func (d *CleanupPolicyDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Unscoped().Delete(&model.CleanupPolicy{}, "id = ?", id).Error
+}
+
+// FindByName 根据名称查找清理策略，不存在时返回 nil
+func (d *CleanupPolicyDAO) FindByName(ctx context.Context, name string) (*model.CleanupPolicy, error) {
+	var policy model.CleanupPolicy
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindByNames 根据名称批量查找清理策略
+func (d *CleanupPolicyDAO) FindByNames(ctx context.Context, names []string) ([]*model.CleanupPolicy, error) {
+	var policies []*model.CleanupPolicy
+	if len(names) == 0 {
+		return policies, nil
+	}
+	err := d.db.WithContext(ctx).Where("name IN ?", names).Find(&policies).Error
+	return policies, err
+}
+
+// List 列出所有清理策略
+func (d *CleanupPolicyDAO) List(ctx context.Context) ([]*model.CleanupPolicy, error) {
+	var policies []*model.CleanupPolicy
+	err := d.db.WithContext(ctx).Order("name").Find(&policies).Error
+	return policies, err
+}
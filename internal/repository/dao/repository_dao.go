@@ -1,9 +1,13 @@
 package dao
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 
 	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
 )
 
 // RepositoryDAO 仓库数据访问对象
@@ -19,3 +23,66 @@ func NewRepositoryDAO(logger *slog.Logger, db *gorm.DB) *RepositoryDAO {
 		db:     db,
 	}
 }
+
+// Create 创建仓库记录
+func (d *RepositoryDAO) Create(ctx context.Context, repo *model.Repository) error {
+	return d.db.WithContext(ctx).Create(repo).Error
+}
+
+// Update 保存仓库记录的全部字段
+func (d *RepositoryDAO) Update(ctx context.Context, repo *model.Repository) error {
+	return d.db.WithContext(ctx).Save(repo).Error
+}
+
+// Delete 永久删除仓库记录，名称可以重新使用
+func (d *RepositoryDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Unscoped().Delete(&model.Repository{}, "id = ?", id).Error
+}
+
+// FindByID 根据ID查找仓库，不存在时返回 nil
+func (d *RepositoryDAO) FindByID(ctx context.Context, id string) (*model.Repository, error) {
+	return d.findOne(ctx, "id = ?", id)
+}
+
+// FindByName 根据名称查找仓库，不存在时返回 nil
+func (d *RepositoryDAO) FindByName(ctx context.Context, name string) (*model.Repository, error) {
+	return d.findOne(ctx, "name = ?", name)
+}
+
+// FindByNames 根据名称批量查找仓库
+func (d *RepositoryDAO) FindByNames(ctx context.Context, names []string) ([]*model.Repository, error) {
+	var repos []*model.Repository
+	if len(names) == 0 {
+		return repos, nil
+	}
+	err := d.db.WithContext(ctx).Where("name IN ?", names).Find(&repos).Error
+	return repos, err
+}
+
+// List 列出所有仓库，可按类型和格式过滤
+func (d *RepositoryDAO) List(ctx context.Context, repoType, format string) ([]*model.Repository, error) {
+	query := d.db.WithContext(ctx).Order("name")
+	if repoType != "" {
+		query = query.Where("type = ?", repoType)
+	}
+	if format != "" {
+		query = query.Where("format = ?", format)
+	}
+
+	var repos []*model.Repository
+	err := query.Find(&repos).Error
+	return repos, err
+}
+
+// findOne 按条件查找单个仓库
+func (d *RepositoryDAO) findOne(ctx context.Context, query string, args ...interface{}) (*model.Repository, error) {
+	var repo model.Repository
+	err := d.db.WithContext(ctx).Where(query, args...).First(&repo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// RevokedTokenDAO 已注销令牌数据访问对象
+type RevokedTokenDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewRevokedTokenDAO 创建新的已注销令牌数据访问对象
+func NewRevokedTokenDAO(logger *slog.Logger, db *gorm.DB) *RevokedTokenDAO {
+	return &RevokedTokenDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 记录已注销的令牌，重复注销时忽略，返回本次是否新增了记录
+func (d *RevokedTokenDAO) Create(ctx context.Context, token *model.RevokedToken) (bool, error) {
+	result := d.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists 判断令牌是否已注销
+func (d *RevokedTokenDAO) Exists(ctx context.Context, id string) (bool, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Model(&model.RevokedToken{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired 删除已过期的注销记录
+func (d *RevokedTokenDAO) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := d.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&model.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// RoleDAO 角色数据访问对象
+type RoleDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewRoleDAO 创建新的角色数据访问对象
+func NewRoleDAO(logger *slog.Logger, db *gorm.DB) *RoleDAO {
+	return &RoleDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建角色记录
+func (d *RoleDAO) Create(ctx context.Context, role *model.Role) error {
+	return d.db.WithContext(ctx).Create(role).Error
+}
+
+// Update 更新角色记录
+func (d *RoleDAO) Update(ctx context.Context, role *model.Role) error {
+	return d.db.WithContext(ctx).Omit(clause.Associations).Save(role).Error
+}
+
+// Delete 删除角色及其与用户的关联，角色名称可以重新使用
+func (d *RoleDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role := &model.Role{ID: id}
+		if err := tx.Model(role).Association("Users").Clear(); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(role).Error
+	})
+}
+
+// FindByID 根据ID查找角色，不存在时返回 nil
+func (d *RoleDAO) FindByID(ctx context.Context, id string) (*model.Role, error) {
+	var role model.Role
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByName 根据名称查找角色，不存在时返回 nil
+func (d *RoleDAO) FindByName(ctx context.Context, name string) (*model.Role, error) {
+	var role model.Role
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByNames 根据名称批量查找角色
+func (d *RoleDAO) FindByNames(ctx context.Context, names []string) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := d.db.WithContext(ctx).Where("name IN ?", names).Find(&roles).Error
+	return roles, err
+}
+
+// List 列出所有角色
+func (d *RoleDAO) List(ctx context.Context) ([]*model.Role, error) {
+	var roles []*model.Role
+	err := d.db.WithContext(ctx).Order("name").Find(&roles).Error
+	return roles, err
+}
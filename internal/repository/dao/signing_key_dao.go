@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// SigningKeyDAO 签名密钥数据访问对象
+type SigningKeyDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewSigningKeyDAO 创建新的签名密钥数据访问对象
+func NewSigningKeyDAO(logger *slog.Logger, db *gorm.DB) *SigningKeyDAO {
+	return &SigningKeyDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建签名密钥记录
+func (d *SigningKeyDAO) Create(ctx context.Context, key *model.SigningKey) error {
+	return d.db.WithContext(ctx).Create(key).Error
+}
+
+// Update 保存签名密钥记录的全部字段
+func (d *SigningKeyDAO) Update(ctx context.Context, key *model.SigningKey) error {
+	return d.db.WithContext(ctx).Save(key).Error
+}
+
+// Delete 永久删除签名密钥记录，加密的私钥不保留在软删除的记录中，名称可以重新导入
+func (d *SigningKeyDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Unscoped().Delete(&model.SigningKey{}, "id = ?", id).Error
+}
+
+// FindByName 根据名称查找签名密钥，不存在时返回 nil
+func (d *SigningKeyDAO) FindByName(ctx context.Context, name string) (*model.SigningKey, error) {
+	var key model.SigningKey
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindActive 查找当前用于签名的密钥，不存在时返回 nil
+func (d *SigningKeyDAO) FindActive(ctx context.Context) (*model.SigningKey, error) {
+	var key model.SigningKey
+	err := d.db.WithContext(ctx).Where("active = ?", true).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// List 列出所有签名密钥
+func (d *SigningKeyDAO) List(ctx context.Context) ([]*model.SigningKey, error) {
+	var keys []*model.SigningKey
+	err := d.db.WithContext(ctx).Order("name").Find(&keys).Error
+	return keys, err
+}
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// TaskDAO 后台任务数据访问对象
+type TaskDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewTaskDAO 创建新的后台任务数据访问对象
+func NewTaskDAO(logger *slog.Logger, db *gorm.DB) *TaskDAO {
+	return &TaskDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建任务记录
+func (d *TaskDAO) Create(ctx context.Context, task *model.Task) error {
+	return d.db.WithContext(ctx).Create(task).Error
+}
+
+// Update 保存任务记录的全部字段
+func (d *TaskDAO) Update(ctx context.Context, task *model.Task) error {
+	return d.db.WithContext(ctx).Save(task).Error
+}
+
+// Delete 删除任务记录
+func (d *TaskDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Delete(&model.Task{}, "id = ?", id).Error
+}
+
+// FindByID 根据ID查找任务，不存在时返回 nil
+func (d *TaskDAO) FindByID(ctx context.Context, id string) (*model.Task, error) {
+	return d.findOne(ctx, "id = ?", id)
+}
+
+// FindByName 根据名称查找任务，不存在时返回 nil
+func (d *TaskDAO) FindByName(ctx context.Context, name string) (*model.Task, error) {
+	return d.findOne(ctx, "name = ?", name)
+}
+
+// List 列出所有任务
+func (d *TaskDAO) List(ctx context.Context) ([]*model.Task, error) {
+	var tasks []*model.Task
+	err := d.db.WithContext(ctx).Order("name").Find(&tasks).Error
+	return tasks, err
+}
+
+// findOne 按条件查找单个任务
+func (d *TaskDAO) findOne(ctx context.Context, query string, args ...interface{}) (*model.Task, error) {
+	var task model.Task
+	err := d.db.WithContext(ctx).Where(query, args...).First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
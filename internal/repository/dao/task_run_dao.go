@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// TaskRunDAO 任务执行记录数据访问对象
+type TaskRunDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewTaskRunDAO 创建新的任务执行记录数据访问对象
+func NewTaskRunDAO(logger *slog.Logger, db *gorm.DB) *TaskRunDAO {
+	return &TaskRunDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建执行记录
+func (d *TaskRunDAO) Create(ctx context.Context, run *model.TaskRun) error {
+	return d.db.WithContext(ctx).Create(run).Error
+}
+
+// Update 保存执行记录的全部字段
+func (d *TaskRunDAO) Update(ctx context.Context, run *model.TaskRun) error {
+	return d.db.WithContext(ctx).Save(run).Error
+}
+
+// FindByID 根据ID查找执行记录，不存在时返回 nil
+func (d *TaskRunDAO) FindByID(ctx context.Context, id string) (*model.TaskRun, error) {
+	var run model.TaskRun
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListByTask 按开始时间倒序列出任务的执行记录，不包含日志与结果，limit 为 0 时不限制数量
+func (d *TaskRunDAO) ListByTask(ctx context.Context, taskID string, limit int) ([]*model.TaskRun, error) {
+	query := d.db.WithContext(ctx).
+		Omit("log", "result").
+		Where("task_id = ?", taskID).
+		Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var runs []*model.TaskRun
+	err := query.Find(&runs).Error
+	return runs, err
+}
+
+// DeleteByTask 删除任务的所有执行记录
+func (d *TaskRunDAO) DeleteByTask(ctx context.Context, taskID string) error {
+	return d.db.WithContext(ctx).Where("task_id = ?", taskID).Delete(&model.TaskRun{}).Error
+}
+
+// Prune 只保留任务最近的 keep 条执行记录
+func (d *TaskRunDAO) Prune(ctx context.Context, taskID string, keep int) error {
+	recent := d.db.Model(&model.TaskRun{}).
+		Select("id").
+		Where("task_id = ?", taskID).
+		Order("started_at DESC").
+		Limit(keep)
+	return d.db.WithContext(ctx).
+		Where("task_id = ? AND id NOT IN (?)", taskID, recent).
+		Delete(&model.TaskRun{}).Error
+}
+
+// FailRunning 将仍处于执行中的记录标记为失败，用于服务重启后清理中断的执行
+func (d *TaskRunDAO) FailRunning(ctx context.Context, message string, at time.Time) (int64, error) {
+	result := d.db.WithContext(ctx).Model(&model.TaskRun{}).
+		Where("status = ?", model.TaskStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      model.TaskStatusFailed,
+			"error":       message,
+			"finished_at": at,
+		})
+	return result.RowsAffected, result.Error
+}
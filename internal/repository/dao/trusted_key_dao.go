@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// TrustedKeyDAO 受信任公钥数据访问对象
+type TrustedKeyDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewTrustedKeyDAO 创建新的受信任公钥数据访问对象
+func NewTrustedKeyDAO(logger *slog.Logger, db *gorm.DB) *TrustedKeyDAO {
+	return &TrustedKeyDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建受信任公钥记录
+func (d *TrustedKeyDAO) Create(ctx context.Context, key *model.TrustedKey) error {
+	return d.db.WithContext(ctx).Create(key).Error
+}
+
+// Update 保存受信任公钥记录的全部字段
+func (d *TrustedKeyDAO) Update(ctx context.Context, key *model.TrustedKey) error {
+	return d.db.WithContext(ctx).Save(key).Error
+}
+
+// Delete 永久删除受信任公钥记录，名称可以重新登记
+func (d *TrustedKeyDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Unscoped().Delete(&model.TrustedKey{}, "id = ?", id).Error
+}
+
+// FindByName 根据名称查找受信任公钥，不存在时返回 nil
+func (d *TrustedKeyDAO) FindByName(ctx context.Context, name string) (*model.TrustedKey, error) {
+	var key model.TrustedKey
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByNames 根据名称批量查找受信任公钥
+func (d *TrustedKeyDAO) FindByNames(ctx context.Context, names []string) ([]*model.TrustedKey, error) {
+	var keys []*model.TrustedKey
+	if len(names) == 0 {
+		return keys, nil
+	}
+	err := d.db.WithContext(ctx).Where("name IN ?", names).Find(&keys).Error
+	return keys, err
+}
+
+// List 列出所有受信任公钥
+func (d *TrustedKeyDAO) List(ctx context.Context) ([]*model.TrustedKey, error) {
+	var keys []*model.TrustedKey
+	err := d.db.WithContext(ctx).Order("name").Find(&keys).Error
+	return keys, err
+}
@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// UploadSessionDAO 分块上传会话数据访问对象
+type UploadSessionDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewUploadSessionDAO 创建新的分块上传会话数据访问对象
+func NewUploadSessionDAO(logger *slog.Logger, db *gorm.DB) *UploadSessionDAO {
+	return &UploadSessionDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建会话记录
+func (d *UploadSessionDAO) Create(ctx context.Context, session *model.UploadSession) error {
+	return d.db.WithContext(ctx).Create(session).Error
+}
+
+// Update 保存会话记录的全部字段
+func (d *UploadSessionDAO) Update(ctx context.Context, session *model.UploadSession) error {
+	return d.db.WithContext(ctx).Save(session).Error
+}
+
+// Delete 删除会话记录
+func (d *UploadSessionDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Delete(&model.UploadSession{}, "id = ?", id).Error
+}
+
+// FindByID 根据ID查找会话，不存在时返回 nil
+func (d *UploadSessionDAO) FindByID(ctx context.Context, id string) (*model.UploadSession, error) {
+	var session model.UploadSession
+	err := d.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListExpired 列出在指定时间之前过期的会话
+func (d *UploadSessionDAO) ListExpired(ctx context.Context, before time.Time) ([]*model.UploadSession, error) {
+	var sessions []*model.UploadSession
+	err := d.db.WithContext(ctx).Where("expires_at < ?", before).Find(&sessions).Error
+	return sessions, err
+}
@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// UserDAO 用户数据访问对象
+type UserDAO struct {
+	logger *slog.Logger
+	db     *gorm.DB
+}
+
+// NewUserDAO 创建新的用户数据访问对象
+func NewUserDAO(logger *slog.Logger, db *gorm.DB) *UserDAO {
+	return &UserDAO{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Create 创建用户记录，不写入角色关联
+func (d *UserDAO) Create(ctx context.Context, user *model.User) error {
+	return d.db.WithContext(ctx).Omit(clause.Associations).Create(user).Error
+}
+
+// Update 保存用户记录的全部字段，不修改角色关联
+func (d *UserDAO) Update(ctx context.Context, user *model.User) error {
+	return d.db.WithContext(ctx).Omit(clause.Associations).Save(user).Error
+}
+
+// UpdateColumns 只更新指定字段，不修改 updated_at 以外的其他字段
+func (d *UserDAO) UpdateColumns(ctx context.Context, id string, columns map[string]interface{}) error {
+	return d.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// Delete 删除用户记录、角色关联与访问令牌
+// 用户名与邮箱带唯一索引，直接删除记录以便重新使用
+func (d *UserDAO) Delete(ctx context.Context, id string) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user := &model.User{ID: id}
+		if err := tx.Model(user).Association("Roles").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", id).Delete(&model.AccessToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(user).Error
+	})
+}
+
+// ReplaceRoles 将用户的角色替换为给定角色
+func (d *UserDAO) ReplaceRoles(ctx context.Context, user *model.User, roles []model.Role) error {
+	return d.db.WithContext(ctx).Model(user).Association("Roles").Replace(roles)
+}
+
+// FindByID 根据ID查找用户及其角色，不存在时返回 nil
+func (d *UserDAO) FindByID(ctx context.Context, id string) (*model.User, error) {
+	return d.findOne(ctx, "id = ?", id)
+}
+
+// FindByUsername 根据用户名查找用户及其角色，不存在时返回 nil
+func (d *UserDAO) FindByUsername(ctx context.Context, username string) (*model.User, error) {
+	return d.findOne(ctx, "username = ?", username)
+}
+
+// FindByEmail 根据邮箱查找用户，不存在时返回 nil
+func (d *UserDAO) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	return d.findOne(ctx, "email = ?", email)
+}
+
+// List 列出用户及其角色，status 为空时不过滤，keyword 模糊匹配用户名、邮箱与姓名
+func (d *UserDAO) List(ctx context.Context, status, keyword string) ([]*model.User, error) {
+	query := d.db.WithContext(ctx).Preload("Roles").Order("username")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("username LIKE ? OR email LIKE ? OR full_name LIKE ?", like, like, like)
+	}
+	var users []*model.User
+	err := query.Find(&users).Error
+	return users, err
+}
+
+// Count 统计用户数量
+func (d *UserDAO) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := d.db.WithContext(ctx).Model(&model.User{}).Count(&count).Error
+	return count, err
+}
+
+// findOne 按条件查找单个用户
+func (d *UserDAO) findOne(ctx context.Context, query string, args ...interface{}) (*model.User, error) {
+	var user model.User
+	err := d.db.WithContext(ctx).Preload("Roles").Where(query, args...).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
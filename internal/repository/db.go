@@ -59,5 +59,11 @@ func NewDB(cfg *config.Config) (*gorm.DB, func(), error) {
 		sqlDB.Close()
 	}
 
+	// 自动迁移表结构
+	if err := Migrate(db); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
 	return db, cleanup, nil
 }
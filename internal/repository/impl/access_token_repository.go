@@ -0,0 +1,54 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// AccessTokenRepositoryImpl 个人访问令牌持久层实现
+type AccessTokenRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.AccessTokenDAO
+}
+
+// NewAccessTokenRepository 创建新的个人访问令牌持久层实现
+func NewAccessTokenRepository(logger *slog.Logger, dao *dao.AccessTokenDAO) *AccessTokenRepositoryImpl {
+	return &AccessTokenRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建令牌
+func (r *AccessTokenRepositoryImpl) Create(ctx context.Context, token *model.AccessToken) error {
+	return r.dao.Create(ctx, token)
+}
+
+// Delete 吊销令牌
+func (r *AccessTokenRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByID 根据ID查找令牌
+func (r *AccessTokenRepositoryImpl) FindByID(ctx context.Context, id string) (*model.AccessToken, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// FindByHash 根据令牌哈希查找令牌
+func (r *AccessTokenRepositoryImpl) FindByHash(ctx context.Context, hash string) (*model.AccessToken, error) {
+	return r.dao.FindByHash(ctx, hash)
+}
+
+// ListByUser 列出用户的令牌
+func (r *AccessTokenRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*model.AccessToken, error) {
+	return r.dao.ListByUser(ctx, userID)
+}
+
+// UpdateLastUsed 更新令牌的最近使用时间
+func (r *AccessTokenRepositoryImpl) UpdateLastUsed(ctx context.Context, id string, at time.Time) error {
+	return r.dao.UpdateLastUsed(ctx, id, at)
+}
@@ -1,9 +1,11 @@
 package impl
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
 )
 
 // ArtifactRepositoryImpl 制品持久层实现
@@ -19,3 +21,43 @@ func NewArtifactRepository(logger *slog.Logger, dao *dao.ArtifactDAO) *ArtifactR
 		dao:    dao,
 	}
 }
+
+// Create 创建制品记录
+func (r *ArtifactRepositoryImpl) Create(ctx context.Context, artifact *model.Artifact) error {
+	return r.dao.Create(ctx, artifact)
+}
+
+// Update 更新制品记录
+func (r *ArtifactRepositoryImpl) Update(ctx context.Context, artifact *model.Artifact) error {
+	return r.dao.Update(ctx, artifact)
+}
+
+// Delete 删除制品记录
+func (r *ArtifactRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// DeleteByRepository 永久删除仓库中的所有制品记录
+func (r *ArtifactRepositoryImpl) DeleteByRepository(ctx context.Context, repositoryID string) error {
+	return r.dao.DeleteByRepository(ctx, repositoryID)
+}
+
+// ListByRepository 列出仓库中的所有制品
+func (r *ArtifactRepositoryImpl) ListByRepository(ctx context.Context, repositoryID string) ([]*model.Artifact, error) {
+	return r.dao.ListByRepository(ctx, repositoryID)
+}
+
+// List 按条件分页查询制品
+func (r *ArtifactRepositoryImpl) List(ctx context.Context, filter *model.ArtifactFilter) ([]*model.Artifact, error) {
+	return r.dao.List(ctx, filter)
+}
+
+// FindByPath 根据仓库和路径查找制品
+func (r *ArtifactRepositoryImpl) FindByPath(ctx context.Context, repositoryID, path string) (*model.Artifact, error) {
+	return r.dao.FindByPath(ctx, repositoryID, path)
+}
+
+// IncrementDownloadCount 下载次数加一并记录下载时间
+func (r *ArtifactRepositoryImpl) IncrementDownloadCount(ctx context.Context, id string) error {
+	return r.dao.IncrementDownloadCount(ctx, id)
+}
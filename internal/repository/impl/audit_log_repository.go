@@ -0,0 +1,33 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// AuditLogRepositoryImpl 审计日志持久层实现
+type AuditLogRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.AuditLogDAO
+}
+
+// NewAuditLogRepository 创建新的审计日志持久层实现
+func NewAuditLogRepository(logger *slog.Logger, dao *dao.AuditLogDAO) *AuditLogRepositoryImpl {
+	return &AuditLogRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建审计日志
+func (r *AuditLogRepositoryImpl) Create(ctx context.Context, entry *model.AuditLog) error {
+	return r.dao.Create(ctx, entry)
+}
+
+// List 按条件查询审计日志
+func (r *AuditLogRepositoryImpl) List(ctx context.Context, filter *model.AuditLogFilter) ([]*model.AuditLog, error) {
+	return r.dao.List(ctx, filter)
+}
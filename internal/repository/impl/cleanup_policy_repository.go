@@ -0,0 +1,53 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// CleanupPolicyRepositoryImpl 清理策略持久层实现
+type CleanupPolicyRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.CleanupPolicyDAO
+}
+
+// NewCleanupPolicyRepository 创建新的清理策略持久层实现
+func NewCleanupPolicyRepository(logger *slog.Logger, dao *dao.CleanupPolicyDAO) *CleanupPolicyRepositoryImpl {
+	return &CleanupPolicyRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建清理策略
+func (r *CleanupPolicyRepositoryImpl) Create(ctx context.Context, policy *model.CleanupPolicy) error {
+	return r.dao.Create(ctx, policy)
+}
+
+// Update 更新清理策略
+func (r *CleanupPolicyRepositoryImpl) Update(ctx context.Context, policy *model.CleanupPolicy) error {
+	return r.dao.Update(ctx, policy)
+}
+
+// Delete 删除清理策略
+func (r *CleanupPolicyRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByName 根据名称查找清理策略
+func (r *CleanupPolicyRepositoryImpl) FindByName(ctx context.Context, name string) (*model.CleanupPolicy, error) {
+	return r.dao.FindByName(ctx, name)
+}
+
+// FindByNames 根据名称批量查找清理策略
+func (r *CleanupPolicyRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]*model.CleanupPolicy, error) {
+	return r.dao.FindByNames(ctx, names)
+}
+
+// List 列出所有清理策略
+func (r *CleanupPolicyRepositoryImpl) List(ctx context.Context) ([]*model.CleanupPolicy, error) {
+	return r.dao.List(ctx)
+}
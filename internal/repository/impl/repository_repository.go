@@ -1,9 +1,11 @@
 package impl
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
 )
 
 // RepositoryRepositoryImpl 仓库持久层实现
@@ -19,3 +21,38 @@ func NewRepositoryRepository(logger *slog.Logger, dao *dao.RepositoryDAO) *Repos
 		dao:    dao,
 	}
 }
+
+// Create 创建仓库
+func (r *RepositoryRepositoryImpl) Create(ctx context.Context, repo *model.Repository) error {
+	return r.dao.Create(ctx, repo)
+}
+
+// Update 更新仓库
+func (r *RepositoryRepositoryImpl) Update(ctx context.Context, repo *model.Repository) error {
+	return r.dao.Update(ctx, repo)
+}
+
+// Delete 删除仓库
+func (r *RepositoryRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByID 根据ID查找仓库
+func (r *RepositoryRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Repository, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// FindByName 根据名称查找仓库
+func (r *RepositoryRepositoryImpl) FindByName(ctx context.Context, name string) (*model.Repository, error) {
+	return r.dao.FindByName(ctx, name)
+}
+
+// FindByNames 根据名称批量查找仓库
+func (r *RepositoryRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]*model.Repository, error) {
+	return r.dao.FindByNames(ctx, names)
+}
+
+// List 列出仓库
+func (r *RepositoryRepositoryImpl) List(ctx context.Context, repoType, format string) ([]*model.Repository, error) {
+	return r.dao.List(ctx, repoType, format)
+}
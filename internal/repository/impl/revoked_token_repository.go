@@ -0,0 +1,39 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// RevokedTokenRepositoryImpl 已注销令牌持久层实现
+type RevokedTokenRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.RevokedTokenDAO
+}
+
+// NewRevokedTokenRepository 创建新的已注销令牌持久层实现
+func NewRevokedTokenRepository(logger *slog.Logger, dao *dao.RevokedTokenDAO) *RevokedTokenRepositoryImpl {
+	return &RevokedTokenRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 记录已注销的令牌，令牌已注销时返回 false
+func (r *RevokedTokenRepositoryImpl) Create(ctx context.Context, token *model.RevokedToken) (bool, error) {
+	return r.dao.Create(ctx, token)
+}
+
+// Exists 判断令牌是否已注销
+func (r *RevokedTokenRepositoryImpl) Exists(ctx context.Context, id string) (bool, error) {
+	return r.dao.Exists(ctx, id)
+}
+
+// DeleteExpired 删除已过期的注销记录
+func (r *RevokedTokenRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return r.dao.DeleteExpired(ctx, before)
+}
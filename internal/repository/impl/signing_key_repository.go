@@ -0,0 +1,53 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// SigningKeyRepositoryImpl 签名密钥持久层实现
+type SigningKeyRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.SigningKeyDAO
+}
+
+// NewSigningKeyRepository 创建新的签名密钥持久层实现
+func NewSigningKeyRepository(logger *slog.Logger, dao *dao.SigningKeyDAO) *SigningKeyRepositoryImpl {
+	return &SigningKeyRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建签名密钥
+func (r *SigningKeyRepositoryImpl) Create(ctx context.Context, key *model.SigningKey) error {
+	return r.dao.Create(ctx, key)
+}
+
+// Update 更新签名密钥
+func (r *SigningKeyRepositoryImpl) Update(ctx context.Context, key *model.SigningKey) error {
+	return r.dao.Update(ctx, key)
+}
+
+// Delete 删除签名密钥
+func (r *SigningKeyRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByName 根据名称查找签名密钥
+func (r *SigningKeyRepositoryImpl) FindByName(ctx context.Context, name string) (*model.SigningKey, error) {
+	return r.dao.FindByName(ctx, name)
+}
+
+// FindActive 查找当前用于签名的密钥
+func (r *SigningKeyRepositoryImpl) FindActive(ctx context.Context) (*model.SigningKey, error) {
+	return r.dao.FindActive(ctx)
+}
+
+// List 列出所有签名密钥
+func (r *SigningKeyRepositoryImpl) List(ctx context.Context) ([]*model.SigningKey, error) {
+	return r.dao.List(ctx)
+}
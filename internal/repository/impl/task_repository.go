@@ -0,0 +1,103 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// TaskRepositoryImpl 后台任务持久层实现
+type TaskRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.TaskDAO
+}
+
+// NewTaskRepository 创建新的后台任务持久层实现
+func NewTaskRepository(logger *slog.Logger, dao *dao.TaskDAO) *TaskRepositoryImpl {
+	return &TaskRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建任务
+func (r *TaskRepositoryImpl) Create(ctx context.Context, task *model.Task) error {
+	return r.dao.Create(ctx, task)
+}
+
+// Update 更新任务
+func (r *TaskRepositoryImpl) Update(ctx context.Context, task *model.Task) error {
+	return r.dao.Update(ctx, task)
+}
+
+// Delete 删除任务
+func (r *TaskRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByID 根据ID查找任务
+func (r *TaskRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Task, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// FindByName 根据名称查找任务
+func (r *TaskRepositoryImpl) FindByName(ctx context.Context, name string) (*model.Task, error) {
+	return r.dao.FindByName(ctx, name)
+}
+
+// List 列出所有任务
+func (r *TaskRepositoryImpl) List(ctx context.Context) ([]*model.Task, error) {
+	return r.dao.List(ctx)
+}
+
+// TaskRunRepositoryImpl 任务执行记录持久层实现
+type TaskRunRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.TaskRunDAO
+}
+
+// NewTaskRunRepository 创建新的任务执行记录持久层实现
+func NewTaskRunRepository(logger *slog.Logger, dao *dao.TaskRunDAO) *TaskRunRepositoryImpl {
+	return &TaskRunRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建执行记录
+func (r *TaskRunRepositoryImpl) Create(ctx context.Context, run *model.TaskRun) error {
+	return r.dao.Create(ctx, run)
+}
+
+// Update 更新执行记录
+func (r *TaskRunRepositoryImpl) Update(ctx context.Context, run *model.TaskRun) error {
+	return r.dao.Update(ctx, run)
+}
+
+// FindByID 根据ID查找执行记录
+func (r *TaskRunRepositoryImpl) FindByID(ctx context.Context, id string) (*model.TaskRun, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// ListByTask 按开始时间倒序列出任务的执行记录
+func (r *TaskRunRepositoryImpl) ListByTask(ctx context.Context, taskID string, limit int) ([]*model.TaskRun, error) {
+	return r.dao.ListByTask(ctx, taskID, limit)
+}
+
+// DeleteByTask 删除任务的所有执行记录
+func (r *TaskRunRepositoryImpl) DeleteByTask(ctx context.Context, taskID string) error {
+	return r.dao.DeleteByTask(ctx, taskID)
+}
+
+// Prune 只保留任务最近的 keep 条执行记录
+func (r *TaskRunRepositoryImpl) Prune(ctx context.Context, taskID string, keep int) error {
+	return r.dao.Prune(ctx, taskID, keep)
+}
+
+// FailRunning 将仍处于执行中的记录标记为失败
+func (r *TaskRunRepositoryImpl) FailRunning(ctx context.Context, message string, at time.Time) (int64, error) {
+	return r.dao.FailRunning(ctx, message, at)
+}
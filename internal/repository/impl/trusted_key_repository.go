@@ -0,0 +1,53 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// TrustedKeyRepositoryImpl 受信任公钥持久层实现
+type TrustedKeyRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.TrustedKeyDAO
+}
+
+// NewTrustedKeyRepository 创建新的受信任公钥持久层实现
+func NewTrustedKeyRepository(logger *slog.Logger, dao *dao.TrustedKeyDAO) *TrustedKeyRepositoryImpl {
+	return &TrustedKeyRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建受信任公钥
+func (r *TrustedKeyRepositoryImpl) Create(ctx context.Context, key *model.TrustedKey) error {
+	return r.dao.Create(ctx, key)
+}
+
+// Update 更新受信任公钥
+func (r *TrustedKeyRepositoryImpl) Update(ctx context.Context, key *model.TrustedKey) error {
+	return r.dao.Update(ctx, key)
+}
+
+// Delete 删除受信任公钥
+func (r *TrustedKeyRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByName 根据名称查找受信任公钥
+func (r *TrustedKeyRepositoryImpl) FindByName(ctx context.Context, name string) (*model.TrustedKey, error) {
+	return r.dao.FindByName(ctx, name)
+}
+
+// FindByNames 根据名称批量查找受信任公钥
+func (r *TrustedKeyRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]*model.TrustedKey, error) {
+	return r.dao.FindByNames(ctx, names)
+}
+
+// List 列出所有受信任公钥
+func (r *TrustedKeyRepositoryImpl) List(ctx context.Context) ([]*model.TrustedKey, error) {
+	return r.dao.List(ctx)
+}
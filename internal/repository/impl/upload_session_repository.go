@@ -0,0 +1,49 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// UploadSessionRepositoryImpl 分块上传会话持久层实现
+type UploadSessionRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.UploadSessionDAO
+}
+
+// NewUploadSessionRepository 创建新的分块上传会话持久层实现
+func NewUploadSessionRepository(logger *slog.Logger, dao *dao.UploadSessionDAO) *UploadSessionRepositoryImpl {
+	return &UploadSessionRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建会话
+func (r *UploadSessionRepositoryImpl) Create(ctx context.Context, session *model.UploadSession) error {
+	return r.dao.Create(ctx, session)
+}
+
+// Update 更新会话
+func (r *UploadSessionRepositoryImpl) Update(ctx context.Context, session *model.UploadSession) error {
+	return r.dao.Update(ctx, session)
+}
+
+// Delete 删除会话
+func (r *UploadSessionRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByID 根据ID查找会话
+func (r *UploadSessionRepositoryImpl) FindByID(ctx context.Context, id string) (*model.UploadSession, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// ListExpired 列出已过期的会话
+func (r *UploadSessionRepositoryImpl) ListExpired(ctx context.Context, before time.Time) ([]*model.UploadSession, error) {
+	return r.dao.ListExpired(ctx, before)
+}
@@ -0,0 +1,122 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// UserRepositoryImpl 用户持久层实现
+type UserRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.UserDAO
+}
+
+// NewUserRepository 创建新的用户持久层实现
+func NewUserRepository(logger *slog.Logger, dao *dao.UserDAO) *UserRepositoryImpl {
+	return &UserRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建用户
+func (r *UserRepositoryImpl) Create(ctx context.Context, user *model.User) error {
+	return r.dao.Create(ctx, user)
+}
+
+// Update 更新用户
+func (r *UserRepositoryImpl) Update(ctx context.Context, user *model.User) error {
+	return r.dao.Update(ctx, user)
+}
+
+// UpdateColumns 更新用户的指定字段
+func (r *UserRepositoryImpl) UpdateColumns(ctx context.Context, id string, columns map[string]interface{}) error {
+	return r.dao.UpdateColumns(ctx, id, columns)
+}
+
+// Delete 删除用户
+func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// ReplaceRoles 替换用户的角色
+func (r *UserRepositoryImpl) ReplaceRoles(ctx context.Context, user *model.User, roles []model.Role) error {
+	return r.dao.ReplaceRoles(ctx, user, roles)
+}
+
+// FindByID 根据ID查找用户
+func (r *UserRepositoryImpl) FindByID(ctx context.Context, id string) (*model.User, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// FindByUsername 根据用户名查找用户
+func (r *UserRepositoryImpl) FindByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.dao.FindByUsername(ctx, username)
+}
+
+// FindByEmail 根据邮箱查找用户
+func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.dao.FindByEmail(ctx, email)
+}
+
+// List 列出用户
+func (r *UserRepositoryImpl) List(ctx context.Context, status, keyword string) ([]*model.User, error) {
+	return r.dao.List(ctx, status, keyword)
+}
+
+// Count 统计用户数量
+func (r *UserRepositoryImpl) Count(ctx context.Context) (int64, error) {
+	return r.dao.Count(ctx)
+}
+
+// RoleRepositoryImpl 角色持久层实现
+type RoleRepositoryImpl struct {
+	logger *slog.Logger
+	dao    *dao.RoleDAO
+}
+
+// NewRoleRepository 创建新的角色持久层实现
+func NewRoleRepository(logger *slog.Logger, dao *dao.RoleDAO) *RoleRepositoryImpl {
+	return &RoleRepositoryImpl{
+		logger: logger,
+		dao:    dao,
+	}
+}
+
+// Create 创建角色
+func (r *RoleRepositoryImpl) Create(ctx context.Context, role *model.Role) error {
+	return r.dao.Create(ctx, role)
+}
+
+// Update 更新角色
+func (r *RoleRepositoryImpl) Update(ctx context.Context, role *model.Role) error {
+	return r.dao.Update(ctx, role)
+}
+
+// Delete 删除角色
+func (r *RoleRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.dao.Delete(ctx, id)
+}
+
+// FindByID 根据ID查找角色
+func (r *RoleRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Role, error) {
+	return r.dao.FindByID(ctx, id)
+}
+
+// FindByName 根据名称查找角色
+func (r *RoleRepositoryImpl) FindByName(ctx context.Context, name string) (*model.Role, error) {
+	return r.dao.FindByName(ctx, name)
+}
+
+// FindByNames 根据名称批量查找角色
+func (r *RoleRepositoryImpl) FindByNames(ctx context.Context, names []string) ([]*model.Role, error) {
+	return r.dao.FindByNames(ctx, names)
+}
+
+// List 列出所有角色
+func (r *RoleRepositoryImpl) List(ctx context.Context) ([]*model.Role, error) {
+	return r.dao.List(ctx)
+}
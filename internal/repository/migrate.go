@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// Migrate 根据模型自动迁移表结构
+func Migrate(db *gorm.DB) error {
+	// 审计日志需在用户删除后保留，移除早期版本创建的用户外键；
+	// SQLite 删除约束会重建表，需在迁移前执行以便随后补建索引
+	if db.Migrator().HasTable(&model.AuditLog{}) && db.Migrator().HasConstraint(&model.AuditLog{}, "fk_audit_logs_user") {
+		if err := db.Migrator().DropConstraint(&model.AuditLog{}, "fk_audit_logs_user"); err != nil {
+			return fmt.Errorf("failed to drop audit log foreign key: %w", err)
+		}
+	}
+	if err := db.AutoMigrate(
+		&model.Repository{},
+		&model.Artifact{},
+		&model.UploadSession{},
+		&model.User{},
+		&model.Role{},
+		&model.AccessToken{},
+		&model.RevokedToken{},
+		&model.AuditLog{},
+		&model.CleanupPolicy{},
+		&model.TrustedKey{},
+		&model.SigningKey{},
+		&model.Task{},
+		&model.TaskRun{},
+	); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,45 +16,152 @@ type Repository struct {
 	Description string            `gorm:"size:500" json:"description"`
 	URL         string            `gorm:"size:500" json:"url"` // for proxy repositories
 	Config      map[string]string `gorm:"serializer:json" json:"config"`
+	Members     []string          `gorm:"serializer:json" json:"members"`       // for group repositories, ordered member names
+	Routing     RoutingRules      `gorm:"serializer:json" json:"routing"`       // path allow/deny rules
+	Blocked     bool              `gorm:"default:false" json:"blocked"`         // for proxy repositories, remote manually blocked
 	Status      string            `gorm:"default:active;size:20" json:"status"` // active, inactive
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt    `gorm:"index" json:"-"`
+	// 部署策略，限制宿主仓库中已有制品能否被覆盖
+	DeploymentPolicy string `gorm:"default:allow_redeploy;size:20" json:"deployment_policy"` // allow_redeploy, disable_redeploy, read_only
+	// 签名策略，要求上传的制品附带受信任公钥签署的有效签名
+	SignaturePolicy SignaturePolicy `gorm:"serializer:json" json:"signature_policy"`
+	// 是否使用服务器签名密钥为生成的元数据文件签名
+	SignMetadata bool `gorm:"default:false" json:"sign_metadata"`
+	// 暂存仓库状态，普通仓库为空
+	StagingStatus   string         `gorm:"size:20;index" json:"staging_status,omitempty"`     // open, closed, released, dropped
+	StagingTarget   string         `gorm:"size:100" json:"staging_target,omitempty"`          // 发布的目标宿主仓库名称
+	StagingFailures []string       `gorm:"serializer:json" json:"staging_failures,omitempty"` // 最近一次关闭时未通过的校验规则
+	CleanupPolicies []string       `gorm:"serializer:json" json:"cleanup_policies"`           // 应用的清理策略名称
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 运行时状态，不持久化
+	RemoteStatus *RemoteStatus `gorm:"-" json:"remote_status,omitempty"`
 
 	// 关联
 	Artifacts []Artifact `gorm:"foreignKey:RepositoryID" json:"-"`
 }
 
+// 仓库类型
+const (
+	RepositoryTypeProxy  = "proxy"
+	RepositoryTypeHosted = "hosted"
+	RepositoryTypeGroup  = "group"
+)
+
+// 仓库部署策略
+const (
+	DeploymentPolicyAllowRedeploy   = "allow_redeploy"   // 允许覆盖已有制品
+	DeploymentPolicyDisableRedeploy = "disable_redeploy" // 只允许上传新路径，元数据文件除外
+	DeploymentPolicyReadOnly        = "read_only"        // 不接受任何上传
+)
+
+// SignaturePolicy 仓库签名策略
+// Required 为 true 时制品主文件必须附带有效签名，签名文件必须能通过校验；Keys 为空时信任所有受信任公钥
+type SignaturePolicy struct {
+	Required bool     `json:"required"`
+	Keys     []string `json:"keys,omitempty"` // 受信任公钥名称
+}
+
+// CleanupPolicy 清理策略，附加到仓库后由定期任务删除满足全部条件的制品
+type CleanupPolicy struct {
+	ID          string          `gorm:"primaryKey;size:36" json:"id"`
+	Name        string          `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	Description string          `gorm:"size:500" json:"description"`
+	Format      string          `gorm:"size:20" json:"format"` // 限定适用的仓库格式，为空时适用于所有格式
+	Criteria    CleanupCriteria `gorm:"serializer:json" json:"criteria"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`
+}
+
+// CleanupCriteria 清理条件，设置的条件需同时满足，零值表示不限制
+type CleanupCriteria struct {
+	OlderThanDays     int    `json:"older_than_days,omitempty"`     // 上传超过 N 天
+	NotDownloadedDays int    `json:"not_downloaded_days,omitempty"` // 超过 N 天未被下载，从未下载时按上传时间计算
+	KeepLastVersions  int    `json:"keep_last_versions,omitempty"`  // 每个组件保留最新的 N 个版本
+	PathRegex         string `json:"path_regex,omitempty"`          // 完整匹配制品路径的正则表达式
+	PrereleaseOnly    bool   `json:"prerelease_only,omitempty"`     // 只清理预发布版本
+}
+
+// 暂存仓库状态
+const (
+	StagingStatusOpen     = "open"
+	StagingStatusClosed   = "closed"
+	StagingStatusReleased = "released"
+	StagingStatusDropped  = "dropped"
+)
+
+// 代理仓库远程状态
+const (
+	RemoteStatusOnline          = "online"
+	RemoteStatusAutoBlocked     = "auto-blocked"
+	RemoteStatusManuallyBlocked = "manually-blocked"
+)
+
+// RemoteStatus 代理仓库远程状态
+type RemoteStatus struct {
+	Status        string     `json:"status"`   // online, auto-blocked, manually-blocked
+	Failures      int        `json:"failures"` // 连续失败次数
+	BlockedUntil  *time.Time `json:"blocked_until,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+}
+
+// RoutingRules 仓库路由规则
+// 路径为相对仓库根目录、不带前导斜杠的路径，规则为完整匹配的正则表达式。
+// Allow 为空表示允许所有路径；Deny 优先于 Allow。
+type RoutingRules struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
 // Artifact 制品模型
 type Artifact struct {
-	ID            string            `gorm:"primaryKey;size:36" json:"id"`
-	RepositoryID  string            `gorm:"not null;size:36;index" json:"repository_id"`
-	Path          string            `gorm:"not null;size:1000;index" json:"path"`
-	Name          string            `gorm:"not null;size:200" json:"name"`
-	Version       string            `gorm:"not null;size:50" json:"version"`
-	Format        string            `gorm:"not null;size:20" json:"format"`
-	Size          int64             `gorm:"not null" json:"size"`
-	Checksum      string            `gorm:"size:64" json:"checksum"`
-	ContentType   string            `gorm:"size:100" json:"content_type"`
-	Metadata      map[string]string `gorm:"serializer:json" json:"metadata"`
-	Properties    map[string]string `gorm:"serializer:json" json:"properties"`
-	DownloadCount int64             `gorm:"default:0" json:"download_count"`
-	CreatedAt     time.Time         `json:"created_at"`
-	UpdatedAt     time.Time         `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt    `gorm:"index" json:"-"`
+	ID             string            `gorm:"primaryKey;size:36" json:"id"`
+	RepositoryID   string            `gorm:"not null;size:36;index;index:idx_artifacts_repo_path,priority:1;index:idx_artifacts_repo_name,priority:1;index:idx_artifacts_repo_created,priority:1;index:idx_artifacts_repo_size,priority:1;index:idx_artifacts_repo_downloads,priority:1" json:"repository_id"`
+	Path           string            `gorm:"not null;size:1000;index;index:idx_artifacts_repo_path,priority:2" json:"path"`
+	Name           string            `gorm:"not null;size:200;index:idx_artifacts_repo_name,priority:2" json:"name"`
+	Version        string            `gorm:"not null;size:50;index:idx_artifacts_repo_name,priority:3" json:"version"`
+	Format         string            `gorm:"not null;size:20" json:"format"`
+	Size           int64             `gorm:"not null;index:idx_artifacts_repo_size,priority:2" json:"size"`
+	Checksum       string            `gorm:"size:64" json:"checksum"`
+	ContentType    string            `gorm:"size:100" json:"content_type"`
+	Metadata       map[string]string `gorm:"serializer:json" json:"metadata"`
+	Properties     map[string]string `gorm:"serializer:json" json:"properties"`
+	DownloadCount  int64             `gorm:"default:0;index:idx_artifacts_repo_downloads,priority:2" json:"download_count"`
+	LastDownloaded *time.Time        `json:"last_downloaded"`
+	CreatedAt      time.Time         `gorm:"index:idx_artifacts_repo_created,priority:2" json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt    `gorm:"index" json:"-"`
 
 	// 关联
 	Repository Repository `gorm:"foreignKey:RepositoryID" json:"-"`
 }
 
+// UploadSession 分块上传会话，未完成的数据保存在存储后端的 uploads/{id}
+type UploadSession struct {
+	ID           string            `gorm:"primaryKey;size:36" json:"id"`
+	RepositoryID string            `gorm:"not null;size:36;index" json:"repository_id"`
+	Path         string            `gorm:"not null;size:1000" json:"path"`
+	Offset       int64             `gorm:"not null;default:0" json:"offset"` // 已接收的字节数
+	Size         int64             `gorm:"default:0" json:"size"`            // 客户端声明的总大小，0 表示未知
+	HashState    []byte            `json:"-"`                                // 已接收数据的 sha256 中间状态
+	Properties   map[string]string `gorm:"serializer:json" json:"properties"`
+	ExpiresAt    time.Time         `gorm:"index" json:"expires_at"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
 // User 用户模型
 type User struct {
 	ID        string         `gorm:"primaryKey;size:36" json:"id"`
 	Username  string         `gorm:"uniqueIndex;not null;size:50" json:"username"`
 	Email     string         `gorm:"uniqueIndex;not null;size:100" json:"email"`
-	Password  string         `gorm:"not null;size:255" json:"-"` // 密码哈希，不返回给客户端
+	Password  string         `gorm:"not null;size:255" json:"-"` // 密码哈希，不返回给客户端，外部认证域的用户为空
 	FullName  string         `gorm:"size:100" json:"full_name"`
 	Status    string         `gorm:"default:active;size:20" json:"status"` // active, inactive, locked
+	Realm     string         `gorm:"default:local;size:20" json:"realm"`   // 用户所属的认证域
 	LastLogin *time.Time     `json:"last_login"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -63,6 +171,28 @@ type User struct {
 	Roles []Role `gorm:"many2many:user_roles;" json:"roles"`
 }
 
+// 用户状态，只有 active 状态的用户可以登录
+const (
+	UserStatusActive   = "active"
+	UserStatusInactive = "inactive"
+	UserStatusLocked   = "locked"
+)
+
+// 认证域，外部认证域的用户在首次登录时创建，密码由外部系统管理
+const (
+	UserRealmLocal = "local"
+	UserRealmLDAP  = "ldap"
+	UserRealmOIDC  = "oidc"
+)
+
+// 内置角色
+const (
+	RoleAdmin     = "admin"     // 管理员，拥有全部权限
+	RoleDeveloper = "developer" // 开发者，可以读写所有仓库
+	RoleViewer    = "viewer"    // 只读用户，可以读取所有仓库
+	RoleAnonymous = "anonymous" // 未认证调用者的权限，仅在开启匿名读取时生效
+)
+
 // Role 角色模型
 type Role struct {
 	ID          string         `gorm:"primaryKey;size:36" json:"id"`
@@ -77,12 +207,56 @@ type Role struct {
 	Users []User `gorm:"many2many:user_roles;" json:"-"`
 }
 
+// TrustedKey 受信任公钥，用于校验制品签名
+type TrustedKey struct {
+	ID          string         `gorm:"primaryKey;size:36" json:"id"`
+	Name        string         `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	Type        string         `gorm:"not null;size:20" json:"type"` // pgp, cosign
+	PublicKey   string         `gorm:"type:text;not null" json:"public_key"`
+	Fingerprint string         `gorm:"size:64;index" json:"fingerprint"` // pgp 为主密钥指纹，cosign 为公钥 DER 的 sha256
+	Description string         `gorm:"size:500" json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// 受信任公钥类型
+const (
+	TrustedKeyTypePGP    = "pgp"    // ASCII 封装的 OpenPGP 公钥，校验 .asc 签名
+	TrustedKeyTypeCosign = "cosign" // PEM 编码的 ECDSA、RSA 或 Ed25519 公钥，校验 .sig 签名与 .sigstore 签名包
+)
+
+// SigningKey 服务器签名密钥，用于为生成的元数据文件签名，同一时间只有一个启用的密钥
+type SigningKey struct {
+	ID          string         `gorm:"primaryKey;size:36" json:"id"`
+	Name        string         `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	KeyID       string         `gorm:"size:16" json:"key_id"`
+	Fingerprint string         `gorm:"size:64;index" json:"fingerprint"`
+	UserID      string         `gorm:"size:255" json:"user_id"`              // 密钥的主用户 ID，如 "Releases <releases@example.com>"
+	PublicKey   string         `gorm:"type:text;not null" json:"public_key"` // ASCII 封装的公钥
+	PrivateKey  string         `gorm:"type:text;not null" json:"-"`          // 以 security.signing.secret 加密的私钥，任何接口都不返回
+	Active      bool           `gorm:"default:false;index" json:"active"`    // 是否为当前签名使用的密钥
+	Description string         `gorm:"size:500" json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// RevokedToken 已注销的 JWT，过期后不再需要保留
+type RevokedToken struct {
+	ID        string    `gorm:"primaryKey;size:36" json:"id"` // JWT ID
+	UserID    string    `gorm:"size:36;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // AccessToken 访问令牌模型
 type AccessToken struct {
 	ID        string         `gorm:"primaryKey;size:36" json:"id"`
 	UserID    string         `gorm:"not null;size:36;index" json:"user_id"`
 	Name      string         `gorm:"not null;size:100" json:"name"`
-	Token     string         `gorm:"uniqueIndex;not null;size:255" json:"token"` // 令牌哈希
+	Token     string         `gorm:"uniqueIndex;not null;size:255" json:"-"` // 令牌 SHA-256 哈希，明文只在创建时返回
+	Prefix    string         `gorm:"size:16" json:"prefix"`                  // 令牌明文前缀，用于识别令牌
 	Scopes    []string       `gorm:"serializer:json" json:"scopes"`
 	ExpiresAt *time.Time     `json:"expires_at"`
 	LastUsed  *time.Time     `json:"last_used"`
@@ -94,21 +268,66 @@ type AccessToken struct {
 	User User `gorm:"foreignKey:UserID" json:"-"`
 }
 
-// AuditLog 审计日志模型
+// AuditLog 审计日志模型，用户名冗余保存，用户删除后日志仍可追溯
 type AuditLog struct {
 	ID        string    `gorm:"primaryKey;size:36" json:"id"`
 	UserID    string    `gorm:"size:36;index" json:"user_id"`
-	Action    string    `gorm:"not null;size:50" json:"action"`
-	Resource  string    `gorm:"not null;size:100" json:"resource"`
+	Username  string    `gorm:"size:50;index" json:"username"` // 后台任务为 system，未认证调用者为 anonymous
+	Action    string    `gorm:"not null;size:50;index" json:"action"`
+	Resource  string    `gorm:"not null;size:500" json:"resource"`
+	Status    int       `json:"status"` // HTTP 状态码，后台操作为 0
 	Details   string    `gorm:"type:text" json:"details"`
+	RequestID string    `gorm:"size:64;index" json:"request_id"`
 	IP        string    `gorm:"size:45" json:"ip"`
 	UserAgent string    `gorm:"size:500" json:"user_agent"`
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
 
-	// 关联
-	User User `gorm:"foreignKey:UserID" json:"-"`
+// Task 后台任务定义，Cron 为空时只能手动执行
+type Task struct {
+	ID         string            `gorm:"primaryKey;size:36" json:"id"`
+	Name       string            `gorm:"uniqueIndex;not null;size:100" json:"name"`
+	Type       string            `gorm:"not null;size:50" json:"type"` // cleanup, metadata_rebuild, index_rebuild, upload_gc
+	Cron       string            `gorm:"size:100" json:"cron"`         // 标准 5 段 cron 表达式或 @daily、@every 1h 等描述符
+	Params     map[string]string `gorm:"serializer:json" json:"params"`
+	Enabled    bool              `json:"enabled"`
+	LastRun    *time.Time        `json:"last_run"`
+	LastStatus string            `gorm:"size:20" json:"last_status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+
+	// 运行时状态，不持久化
+	NextRun *time.Time `gorm:"-" json:"next_run,omitempty"`
+	Running bool       `gorm:"-" json:"running"`
+}
+
+// TaskRun 任务的一次执行记录
+type TaskRun struct {
+	ID         string          `gorm:"primaryKey;size:36" json:"id"`
+	TaskID     string          `gorm:"not null;size:36;index:idx_task_runs_task_started,priority:1" json:"task_id"`
+	Trigger    string          `gorm:"not null;size:20" json:"trigger"` // schedule, manual
+	Status     string          `gorm:"not null;size:20" json:"status"`  // running, succeeded, failed, cancelled
+	StartedAt  time.Time       `gorm:"index:idx_task_runs_task_started,priority:2" json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+	Log        string          `gorm:"type:text" json:"log,omitempty"`
+	Result     json.RawMessage `gorm:"serializer:json;type:text" json:"result,omitempty"` // 任务类型返回的执行结果
+	Error      string          `gorm:"size:1000" json:"error,omitempty"`
 }
 
+// 任务执行触发方式
+const (
+	TaskTriggerSchedule = "schedule"
+	TaskTriggerManual   = "manual"
+)
+
+// 任务执行状态
+const (
+	TaskStatusRunning   = "running"
+	TaskStatusSucceeded = "succeeded"
+	TaskStatusFailed    = "failed"
+	TaskStatusCancelled = "cancelled"
+)
+
 // TableName 方法定义表名
 func (Repository) TableName() string {
 	return "repositories"
@@ -118,6 +337,14 @@ func (Artifact) TableName() string {
 	return "artifacts"
 }
 
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
 func (User) TableName() string {
 	return "users"
 }
@@ -133,3 +360,23 @@ func (AccessToken) TableName() string {
 func (AuditLog) TableName() string {
 	return "audit_logs"
 }
+
+func (CleanupPolicy) TableName() string {
+	return "cleanup_policies"
+}
+
+func (TrustedKey) TableName() string {
+	return "trusted_keys"
+}
+
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
+
+func (Task) TableName() string {
+	return "tasks"
+}
+
+func (TaskRun) TableName() string {
+	return "task_runs"
+}
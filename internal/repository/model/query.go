@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// 制品列表排序字段
+const (
+	ArtifactSortCreatedAt     = "created_at"
+	ArtifactSortSize          = "size"
+	ArtifactSortDownloadCount = "download_count"
+)
+
+// ArtifactFilter 制品列表查询条件，空字段表示不过滤
+// 采用键集分页：After 为上一页最后一条记录的排序值与ID，结果按 (Sort, ID) 排序
+type ArtifactFilter struct {
+	IDs           []string
+	RepositoryIDs []string
+	Name          string // 支持 * 通配符
+	Version       string // 支持 * 通配符
+	Format        string
+	PathPrefix    string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Checksum      string            // sha256
+	Metadata      map[string]string // 元数据字段需全部相等
+	Keyword       string            // 元数据 keywords 中包含该关键字
+	Properties    map[string]string // 属性需全部匹配
+	PropertyKeys  []string          // 需存在的属性名
+
+	Sort  string // 排序字段，默认 created_at
+	Desc  bool
+	After *ArtifactCursor
+	Limit int
+}
+
+// ArtifactCursor 分页游标位置
+type ArtifactCursor struct {
+	Value interface{} // 排序字段的值：created_at 为 time.Time，其余为 int64
+	ID    string
+}
+
+// AuditLogFilter 审计日志查询条件，空字段表示不过滤，结果按 (CreatedAt, ID) 倒序键集分页
+type AuditLogFilter struct {
+	User           string // 用户ID或用户名
+	Action         string // 支持 * 通配符
+	ResourcePrefix string
+	RequestID      string
+	Status         int
+	From           *time.Time
+	To             *time.Time
+
+	After *AuditLogCursor
+	Limit int
+}
+
+// AuditLogCursor 审计日志分页游标位置
+type AuditLogCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
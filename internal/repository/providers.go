@@ -33,8 +33,41 @@ var ProviderSet = wire.NewSet(
 	ProvideDB,
 	dao.NewRepositoryDAO,
 	dao.NewArtifactDAO,
+	dao.NewUploadSessionDAO,
+	dao.NewAuditLogDAO,
+	dao.NewCleanupPolicyDAO,
+	dao.NewTrustedKeyDAO,
+	dao.NewSigningKeyDAO,
+	dao.NewTaskDAO,
+	dao.NewTaskRunDAO,
+	dao.NewUserDAO,
+	dao.NewRoleDAO,
+	dao.NewRevokedTokenDAO,
+	dao.NewAccessTokenDAO,
 	impl.NewRepositoryRepository,
 	impl.NewArtifactRepository,
+	impl.NewUploadSessionRepository,
+	impl.NewAuditLogRepository,
+	impl.NewCleanupPolicyRepository,
+	impl.NewTrustedKeyRepository,
+	impl.NewSigningKeyRepository,
+	impl.NewTaskRepository,
+	impl.NewTaskRunRepository,
+	impl.NewUserRepository,
+	impl.NewRoleRepository,
+	impl.NewRevokedTokenRepository,
+	impl.NewAccessTokenRepository,
 	wire.Bind(new(RepositoryRepository), new(*impl.RepositoryRepositoryImpl)),
 	wire.Bind(new(ArtifactRepository), new(*impl.ArtifactRepositoryImpl)),
+	wire.Bind(new(UploadSessionRepository), new(*impl.UploadSessionRepositoryImpl)),
+	wire.Bind(new(AuditLogRepository), new(*impl.AuditLogRepositoryImpl)),
+	wire.Bind(new(CleanupPolicyRepository), new(*impl.CleanupPolicyRepositoryImpl)),
+	wire.Bind(new(TrustedKeyRepository), new(*impl.TrustedKeyRepositoryImpl)),
+	wire.Bind(new(SigningKeyRepository), new(*impl.SigningKeyRepositoryImpl)),
+	wire.Bind(new(TaskRepository), new(*impl.TaskRepositoryImpl)),
+	wire.Bind(new(TaskRunRepository), new(*impl.TaskRunRepositoryImpl)),
+	wire.Bind(new(UserRepository), new(*impl.UserRepositoryImpl)),
+	wire.Bind(new(RoleRepository), new(*impl.RoleRepositoryImpl)),
+	wire.Bind(new(RevokedTokenRepository), new(*impl.RevokedTokenRepositoryImpl)),
+	wire.Bind(new(AccessTokenRepository), new(*impl.AccessTokenRepositoryImpl)),
 )
@@ -1,11 +1,150 @@
 package repository
 
+import (
+	"context"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
 // RepositoryRepository 仓库持久层接口
-// TODO: 添加仓库相关的数据访问方法
+// 查找方法在记录不存在时返回 nil, nil
 type RepositoryRepository interface {
+	Create(ctx context.Context, repo *model.Repository) error
+	Update(ctx context.Context, repo *model.Repository) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*model.Repository, error)
+	FindByName(ctx context.Context, name string) (*model.Repository, error)
+	FindByNames(ctx context.Context, names []string) ([]*model.Repository, error)
+	List(ctx context.Context, repoType, format string) ([]*model.Repository, error)
 }
 
 // ArtifactRepository 制品持久层接口
-// TODO: 添加制品相关的数据访问方法
+// 查找方法在记录不存在时返回 nil, nil
 type ArtifactRepository interface {
+	Create(ctx context.Context, artifact *model.Artifact) error
+	Update(ctx context.Context, artifact *model.Artifact) error
+	Delete(ctx context.Context, id string) error
+	DeleteByRepository(ctx context.Context, repositoryID string) error
+	ListByRepository(ctx context.Context, repositoryID string) ([]*model.Artifact, error)
+	List(ctx context.Context, filter *model.ArtifactFilter) ([]*model.Artifact, error)
+	FindByPath(ctx context.Context, repositoryID, path string) (*model.Artifact, error)
+	IncrementDownloadCount(ctx context.Context, id string) error
+}
+
+// UploadSessionRepository 分块上传会话持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type UploadSessionRepository interface {
+	Create(ctx context.Context, session *model.UploadSession) error
+	Update(ctx context.Context, session *model.UploadSession) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*model.UploadSession, error)
+	ListExpired(ctx context.Context, before time.Time) ([]*model.UploadSession, error)
+}
+
+// AuditLogRepository 审计日志持久层接口
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *model.AuditLog) error
+	List(ctx context.Context, filter *model.AuditLogFilter) ([]*model.AuditLog, error)
+}
+
+// CleanupPolicyRepository 清理策略持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type CleanupPolicyRepository interface {
+	Create(ctx context.Context, policy *model.CleanupPolicy) error
+	Update(ctx context.Context, policy *model.CleanupPolicy) error
+	Delete(ctx context.Context, id string) error
+	FindByName(ctx context.Context, name string) (*model.CleanupPolicy, error)
+	FindByNames(ctx context.Context, names []string) ([]*model.CleanupPolicy, error)
+	List(ctx context.Context) ([]*model.CleanupPolicy, error)
+}
+
+// TrustedKeyRepository 受信任公钥持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type TrustedKeyRepository interface {
+	Create(ctx context.Context, key *model.TrustedKey) error
+	Update(ctx context.Context, key *model.TrustedKey) error
+	Delete(ctx context.Context, id string) error
+	FindByName(ctx context.Context, name string) (*model.TrustedKey, error)
+	FindByNames(ctx context.Context, names []string) ([]*model.TrustedKey, error)
+	List(ctx context.Context) ([]*model.TrustedKey, error)
+}
+
+// SigningKeyRepository 签名密钥持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type SigningKeyRepository interface {
+	Create(ctx context.Context, key *model.SigningKey) error
+	Update(ctx context.Context, key *model.SigningKey) error
+	Delete(ctx context.Context, id string) error
+	FindByName(ctx context.Context, name string) (*model.SigningKey, error)
+	FindActive(ctx context.Context) (*model.SigningKey, error)
+	List(ctx context.Context) ([]*model.SigningKey, error)
+}
+
+// TaskRepository 后台任务持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type TaskRepository interface {
+	Create(ctx context.Context, task *model.Task) error
+	Update(ctx context.Context, task *model.Task) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*model.Task, error)
+	FindByName(ctx context.Context, name string) (*model.Task, error)
+	List(ctx context.Context) ([]*model.Task, error)
+}
+
+// TaskRunRepository 任务执行记录持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type TaskRunRepository interface {
+	Create(ctx context.Context, run *model.TaskRun) error
+	Update(ctx context.Context, run *model.TaskRun) error
+	FindByID(ctx context.Context, id string) (*model.TaskRun, error)
+	ListByTask(ctx context.Context, taskID string, limit int) ([]*model.TaskRun, error)
+	DeleteByTask(ctx context.Context, taskID string) error
+	Prune(ctx context.Context, taskID string, keep int) error
+	FailRunning(ctx context.Context, message string, at time.Time) (int64, error)
+}
+
+// UserRepository 用户持久层接口
+// 查找方法在记录不存在时返回 nil, nil，返回的用户包含角色
+type UserRepository interface {
+	Create(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, user *model.User) error
+	UpdateColumns(ctx context.Context, id string, columns map[string]interface{}) error
+	Delete(ctx context.Context, id string) error
+	ReplaceRoles(ctx context.Context, user *model.User, roles []model.Role) error
+	FindByID(ctx context.Context, id string) (*model.User, error)
+	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	List(ctx context.Context, status, keyword string) ([]*model.User, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+// RoleRepository 角色持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type RoleRepository interface {
+	Create(ctx context.Context, role *model.Role) error
+	Update(ctx context.Context, role *model.Role) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*model.Role, error)
+	FindByName(ctx context.Context, name string) (*model.Role, error)
+	FindByNames(ctx context.Context, names []string) ([]*model.Role, error)
+	List(ctx context.Context) ([]*model.Role, error)
+}
+
+// RevokedTokenRepository 已注销令牌持久层接口
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *model.RevokedToken) (bool, error)
+	Exists(ctx context.Context, id string) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AccessTokenRepository 个人访问令牌持久层接口
+// 查找方法在记录不存在时返回 nil, nil
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *model.AccessToken) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*model.AccessToken, error)
+	FindByHash(ctx context.Context, hash string) (*model.AccessToken, error)
+	ListByUser(ctx context.Context, userID string) ([]*model.AccessToken, error)
+	UpdateLastUsed(ctx context.Context, id string, at time.Time) error
 }
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// AuditService 审计日志服务接口，调用者与请求信息从 context 读取
+type AuditService interface {
+	// RecordRequest 记录一次修改类请求
+	RecordRequest(ctx context.Context, action, resource string, status int)
+	// List 分页查询审计日志
+	List(ctx context.Context, query *dto.ListAuditQuery) (*dto.AuditPage, error)
+	// Export 按时间倒序遍历所有匹配的审计日志，超过导出上限时返回错误
+	Export(ctx context.Context, query *dto.ListAuditQuery, fn func(entry *model.AuditLog) error) error
+}
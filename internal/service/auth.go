@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// AuthService 认证服务接口
+type AuthService interface {
+	// Login 校验用户名与密码，签发访问令牌与刷新令牌
+	Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error)
+	// Refresh 使用刷新令牌签发新的令牌，旧的刷新令牌随即失效
+	Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.TokenResponse, error)
+	// Logout 注销调用者当前的访问令牌，以及请求中给出的刷新令牌
+	Logout(ctx context.Context, principal *auth.Principal, req *dto.LogoutRequest) error
+	// OIDCAuthorize 生成 OIDC 授权地址与登录状态，redirect 为登录后跳转的站内路径
+	OIDCAuthorize(ctx context.Context, redirect string) (*dto.OIDCAuthorization, error)
+	// OIDCCallback 校验登录状态与授权回调，换取 ID 令牌后签发令牌，返回登录后跳转的路径
+	OIDCCallback(ctx context.Context, query *dto.OIDCCallbackQuery, state string) (*dto.TokenResponse, string, error)
+	// Authenticate 校验 Bearer 凭据（JWT、个人访问令牌或 CI 工作负载的 OIDC 令牌）并返回调用者
+	Authenticate(ctx context.Context, token string) (*auth.Principal, error)
+	// AuthenticateBasic 校验 HTTP Basic 凭据，密码为个人访问令牌，供 Maven/npm/Docker 客户端使用
+	AuthenticateBasic(ctx context.Context, username, password string) (*auth.Principal, error)
+	// AuthenticatePassword 校验账户密码或个人访问令牌，只用于 Docker 令牌服务等需要账户密码的场景
+	AuthenticatePassword(ctx context.Context, username, password string) (*auth.Principal, error)
+	// AnonymousRead 是否允许未认证的调用者读取仓库与制品
+	AnonymousRead() bool
+}
@@ -0,0 +1,14 @@
+package service
+
+import "context"
+
+// Authorizer 授权服务接口，从 context 读取调用者，同时校验角色权限与个人访问令牌范围
+// context 中没有调用者时视为内部调用，不做限制
+type Authorizer interface {
+	// Authorize 校验系统权限，如 system:users:manage
+	Authorize(ctx context.Context, permission string) error
+	// AuthorizeRepository 校验对仓库的操作权限，仓库不存在时不做限制，由后续处理返回 404
+	AuthorizeRepository(ctx context.Context, repoIDOrName, action string) error
+	// AuthorizeScope 只校验个人访问令牌范围，用于不针对具体仓库的接口
+	AuthorizeScope(ctx context.Context, access string) error
+}
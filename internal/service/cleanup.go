@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// CleanupService 清理策略服务接口
+type CleanupService interface {
+	// CreatePolicy 创建清理策略
+	CreatePolicy(ctx context.Context, req *dto.CleanupPolicyRequest) (*model.CleanupPolicy, error)
+	// GetPolicy 获取清理策略
+	GetPolicy(ctx context.Context, name string) (*model.CleanupPolicy, error)
+	// ListPolicies 列出清理策略
+	ListPolicies(ctx context.Context) ([]*model.CleanupPolicy, error)
+	// UpdatePolicy 更新清理策略的描述、格式与条件
+	UpdatePolicy(ctx context.Context, name string, req *dto.CleanupPolicyRequest) (*model.CleanupPolicy, error)
+	// DeletePolicy 删除未被仓库使用的清理策略
+	DeletePolicy(ctx context.Context, name string) error
+	// Preview 列出策略将删除的制品，不做任何修改
+	Preview(ctx context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error)
+	// Run 立即执行清理策略
+	Run(ctx context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error)
+	// RunAll 执行所有仓库附加的清理策略
+	RunAll(ctx context.Context) (*dto.CleanupReport, error)
+}
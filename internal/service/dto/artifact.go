@@ -0,0 +1,178 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// ArtifactContent 制品内容
+type ArtifactContent struct {
+	Repository  string // 实际提供内容的仓库名称
+	Path        string
+	ContentType string
+	Data        []byte
+}
+
+// RoutingExplanation 路由测试结果，说明路径会由哪个仓库提供
+type RoutingExplanation struct {
+	Repository string        `json:"repository"`
+	Path       string        `json:"path"`
+	ServedBy   string        `json:"served_by"`             // 提供内容的仓库，为空表示没有仓库能提供
+	Merged     bool          `json:"merged"`                // 是否为组仓库合并的元数据文件
+	MergedFrom []string      `json:"merged_from,omitempty"` // 参与合并的成员
+	Steps      []RoutingStep `json:"steps"`
+}
+
+// RoutingStep 路由测试中单个仓库的判定结果
+type RoutingStep struct {
+	Repository string `json:"repository"`
+	Type       string `json:"type"`
+	Depth      int    `json:"depth"`          // 在组仓库中的嵌套层级，0 为被测试的仓库
+	Decision   string `json:"decision"`       // allowed, denied, skipped
+	Rule       string `json:"rule,omitempty"` // 起决定作用的路由规则
+	Available  bool   `json:"available"`      // 该仓库是否能提供内容
+	Reason     string `json:"reason,omitempty"`
+}
+
+// UploadArtifactRequest 上传制品请求
+type UploadArtifactRequest struct {
+	Path       string
+	Data       []byte
+	Properties map[string]string // 制品属性
+	Checksums  map[string]string // 客户端提供的校验和，键为算法名 md5、sha1、sha256、sha512
+	Signature  []byte            // 随制品一起上传的分离签名，保存为同目录的签名文件
+}
+
+// CreateUploadSessionRequest 创建分块上传会话请求
+type CreateUploadSessionRequest struct {
+	Path       string            `json:"path" binding:"required"`
+	Size       int64             `json:"size" binding:"min=0"` // 总大小，0 表示未知
+	Properties map[string]string `json:"properties"`
+}
+
+// UploadChunk 分块数据，对应 Content-Range: bytes Start-End/Total
+type UploadChunk struct {
+	Start int64 // 为 -1 时追加到当前偏移量
+	Total int64 // 为 -1 时表示总大小未知
+	Data  []byte
+}
+
+// ListArtifactsQuery 制品列表查询条件
+// name、version 支持 * 通配符；property 可重复，key=value 匹配属性值，单独的 key 要求属性存在，需全部满足；
+// sort 为排序字段，加 - 前缀表示降序；cursor 为上一页返回的 next_cursor
+type ListArtifactsQuery struct {
+	Name          string     `form:"name"`
+	Version       string     `form:"version"`
+	Format        string     `form:"format"`
+	PathPrefix    string     `form:"path_prefix"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Properties    []string   `form:"property"`
+	Sort          string     `form:"sort" binding:"omitempty,oneof=created_at -created_at size -size download_count -download_count"`
+	Limit         int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Cursor        string     `form:"cursor"`
+}
+
+// ArtifactPage 制品分页结果
+type ArtifactPage struct {
+	Items      []*model.Artifact
+	Limit      int
+	NextCursor string
+	HasMore    bool
+}
+
+// SearchQuery 跨仓库搜索条件
+// q 为全文检索词，匹配元数据中的名称、描述与关键字，结果按相关度排序，不能指定其他 sort；
+// name、version 支持 * 通配符；version_range 为版本范围，如 [1.0,2.0) 或 >=1.0,<2.0；
+// group_id、artifact_id、keyword 匹配制品元数据；其余参数与制品列表一致
+type SearchQuery struct {
+	Q            string   `form:"q" json:"q,omitempty"`
+	Name         string   `form:"name" json:"name,omitempty"`
+	Version      string   `form:"version" json:"version,omitempty"`
+	VersionRange string   `form:"version_range" json:"version_range,omitempty"`
+	Format       string   `form:"format" json:"format,omitempty"`
+	Repository   string   `form:"repository" json:"repository,omitempty"`
+	SHA256       string   `form:"sha256" json:"sha256,omitempty" binding:"omitempty,len=64,hexadecimal"`
+	GroupID      string   `form:"group_id" json:"group_id,omitempty"`
+	ArtifactID   string   `form:"artifact_id" json:"artifact_id,omitempty"`
+	Keyword      string   `form:"keyword" json:"keyword,omitempty"`
+	Properties   []string `form:"property" json:"property,omitempty"`
+	Sort         string   `form:"sort" json:"sort,omitempty" binding:"omitempty,oneof=relevance created_at -created_at size -size download_count -download_count"`
+	Limit        int      `form:"limit" json:"limit,omitempty" binding:"omitempty,min=1,max=1000"`
+	Cursor       string   `form:"cursor" json:"cursor,omitempty"`
+}
+
+// SearchHit 搜索命中的制品
+type SearchHit struct {
+	*model.Artifact
+	Repository  string `json:"repository"`   // 制品所在仓库名称
+	DownloadURL string `json:"download_url"` // 由处理器根据请求地址填充
+}
+
+// SearchPage 搜索分页结果
+type SearchPage struct {
+	Items      []*SearchHit
+	Limit      int
+	NextCursor string
+	HasMore    bool
+}
+
+// LatestVersionQuery 最新版本查询条件
+// range 为版本范围，写法同搜索接口的 version_range；prerelease 为 true 时包含预发布版本
+type LatestVersionQuery struct {
+	Name       string `form:"name" binding:"required"`
+	Range      string `form:"range"`
+	GroupID    string `form:"group_id"` // Maven 等格式同名制品可按 groupId 区分
+	Prerelease bool   `form:"prerelease"`
+}
+
+// LatestVersion 最新版本查询结果
+type LatestVersion struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Scheme    string            `json:"scheme"`    // 使用的版本比较规则：maven、semver、pep440、generic
+	Artifacts []*model.Artifact `json:"artifacts"` // 该版本的所有文件
+}
+
+// BulkPropertiesRequest 按搜索条件批量修改属性
+// query 的字段与搜索接口的查询参数相同，分页参数会被忽略
+type BulkPropertiesRequest struct {
+	Query  SearchQuery       `json:"query"`
+	Set    map[string]string `json:"set"`
+	Delete []string          `json:"delete"`
+}
+
+// BulkPropertiesResult 批量修改属性的结果
+type BulkPropertiesResult struct {
+	Matched int `json:"matched"`
+	Updated int `json:"updated"`
+}
+
+// PromoteRequest 制品晋级请求
+// 指定 path 时晋级单个文件；否则按 name、version（可选 group_id）晋级该版本的所有文件，
+// 文件的校验和、签名等附属文件（如 .sha1、.asc）随主文件一起晋级
+type PromoteRequest struct {
+	Target    string `json:"target" binding:"required"` // 目标宿主仓库
+	Path      string `json:"path"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	GroupID   string `json:"group_id"`
+	Move      bool   `json:"move"`      // 为 true 时从源仓库删除
+	Overwrite bool   `json:"overwrite"` // 目标仓库已有同路径制品时覆盖，否则返回冲突
+}
+
+// PromoteResult 制品晋级结果
+type PromoteResult struct {
+	Source    string            `json:"source"`
+	Target    string            `json:"target"`
+	Move      bool              `json:"move"`
+	Artifacts []*model.Artifact `json:"artifacts"`        // 目标仓库中的制品记录
+	Failed    []*PromoteFailure `json:"failed,omitempty"` // 晋级失败的文件，其余文件不受影响
+}
+
+// PromoteFailure 单个文件晋级失败的原因
+type PromoteFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// ListAuditQuery 审计日志查询条件
+type ListAuditQuery struct {
+	User      string     `form:"user"`     // 用户ID或用户名
+	Action    string     `form:"action"`   // 支持 * 通配符，如 repository.*
+	Resource  string     `form:"resource"` // 资源前缀，如 repository:libs-release
+	RequestID string     `form:"request_id"`
+	Status    int        `form:"status"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit     int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Cursor    string     `form:"cursor"`
+}
+
+// ExportAuditQuery 审计日志导出条件，忽略分页参数
+type ExportAuditQuery struct {
+	ListAuditQuery
+	Format string `form:"format" binding:"omitempty,oneof=csv json"` // 缺省为 csv
+}
+
+// AuditPage 审计日志分页结果，按时间倒序
+type AuditPage struct {
+	Items      []*model.AuditLog
+	Limit      int
+	NextCursor string
+	HasMore    bool
+}
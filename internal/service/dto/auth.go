@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/laolishu/go-nexus/internal/repository/model"
+
+// LoginRequest 用户名密码登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest 使用刷新令牌换取新的令牌
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 注销请求，refresh_token 可选，提供时一并注销
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse 登录或刷新返回的令牌
+type TokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	RefreshToken     string      `json:"refresh_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        int64       `json:"expires_in"`         // 访问令牌有效秒数
+	RefreshExpiresIn int64       `json:"refresh_expires_in"` // 刷新令牌有效秒数
+	User             *model.User `json:"user"`
+}
+
+// OIDCAuthorization OIDC 登录的授权地址与登录状态，State 由处理器保存在 Cookie 中
+type OIDCAuthorization struct {
+	URL       string
+	State     string
+	ExpiresIn int64 // 登录状态有效秒数
+}
+
+// OIDCCallbackQuery 签发者回调的查询参数
+type OIDCCallbackQuery struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/laolishu/go-nexus/internal/repository/model"
+
+// CleanupPolicyRequest 创建或更新清理策略请求，更新时忽略名称
+type CleanupPolicyRequest struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Format      string                `json:"format"` // 限定适用的仓库格式，为空时适用于所有格式
+	Criteria    model.CleanupCriteria `json:"criteria"`
+}
+
+// CleanupQuery 清理预览或执行的范围，repository 为空时作用于附加了该策略的所有仓库
+type CleanupQuery struct {
+	Repository string `form:"repository"`
+}
+
+// CleanupResult 清理策略在一个仓库中的预览或执行结果
+type CleanupResult struct {
+	Policy     string            `json:"policy"`
+	Repository string            `json:"repository"`
+	Count      int               `json:"count"`
+	Size       int64             `json:"size"`
+	Artifacts  []*model.Artifact `json:"artifacts"`         // 满足条件的制品及其附属文件
+	Deleted    int               `json:"deleted,omitempty"` // 实际删除的数量，预览时为 0
+	Failed     int               `json:"failed,omitempty"`
+}
+
+// CleanupReport 清理预览或执行报告
+type CleanupReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Results []*CleanupResult `json:"results"`
+}
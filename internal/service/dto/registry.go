@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// RegistryTokenRequest Docker 令牌请求，scope 格式为 repository:<名称>:<操作>，可以有多个
+type RegistryTokenRequest struct {
+	Service string   `form:"service"`
+	Scope   []string `form:"scope"`
+	Account string   `form:"account"`
+}
+
+// RegistryAccess 令牌授予的操作
+type RegistryAccess struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Actions []string `json:"actions"`
+}
+
+// RegistryToken Docker 令牌响应，token 与 access_token 相同以兼容不同客户端
+type RegistryToken struct {
+	Token       string           `json:"token"`
+	AccessToken string           `json:"access_token"`
+	ExpiresIn   int64            `json:"expires_in"`
+	IssuedAt    time.Time        `json:"issued_at"`
+	Access      []RegistryAccess `json:"-"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import "github.com/laolishu/go-nexus/internal/repository/model"
+
+// CreateRepositoryRequest 创建仓库请求
+type CreateRepositoryRequest struct {
+	Name        string             `json:"name" binding:"required"`
+	Type        string             `json:"type" binding:"required,oneof=proxy hosted group"`
+	Format      string             `json:"format" binding:"required"`
+	Description string             `json:"description"`
+	URL         string             `json:"url"`     // proxy 仓库的远程地址
+	Config      map[string]string  `json:"config"`  // 仓库扩展配置
+	Members     []string           `json:"members"` // group 仓库的成员名称，按解析顺序排列
+	Routing     model.RoutingRules `json:"routing"` // 路由规则，限制仓库可提供的路径
+	Blocked     bool               `json:"blocked"` // 手动阻断 proxy 仓库的远程访问，只提供缓存内容
+	// 部署策略，默认 allow_redeploy
+	DeploymentPolicy string                `json:"deployment_policy" binding:"omitempty,oneof=allow_redeploy disable_redeploy read_only"`
+	CleanupPolicies  []string              `json:"cleanup_policies"` // 应用的清理策略名称
+	SignaturePolicy  model.SignaturePolicy `json:"signature_policy"` // 签名策略，默认不要求签名
+	SignMetadata     bool                  `json:"sign_metadata"`    // 使用启用的签名密钥为生成的元数据签名
+}
+
+// UpdateRepositoryRequest 更新仓库请求，名称、类型与格式创建后不可修改
+type UpdateRepositoryRequest struct {
+	Description string             `json:"description"`
+	URL         string             `json:"url"`
+	Config      map[string]string  `json:"config"`
+	Members     []string           `json:"members"`
+	Routing     model.RoutingRules `json:"routing"`
+	Blocked     bool               `json:"blocked"`
+	Status      string             `json:"status" binding:"omitempty,oneof=active inactive"`
+	// 部署策略，为空时保持不变
+	DeploymentPolicy string                `json:"deployment_policy" binding:"omitempty,oneof=allow_redeploy disable_redeploy read_only"`
+	CleanupPolicies  []string              `json:"cleanup_policies"`
+	SignaturePolicy  model.SignaturePolicy `json:"signature_policy"`
+	SignMetadata     bool                  `json:"sign_metadata"`
+}
+
+// ListRepositoriesQuery 仓库列表查询条件
+type ListRepositoriesQuery struct {
+	Type   string `form:"type"`
+	Format string `form:"format"`
+}
+
+// StartStagingRequest 创建暂存仓库请求
+type StartStagingRequest struct {
+	Target      string `json:"target" binding:"required"` // 发布的目标宿主仓库
+	Description string `json:"description"`
+}
+
+// ListStagingQuery 暂存仓库列表查询条件
+type ListStagingQuery struct {
+	Target string `form:"target"`
+	Status string `form:"status" binding:"omitempty,oneof=open closed released dropped"`
+}
+
+// StagingReleaseResult 暂存仓库发布结果
+type StagingReleaseResult struct {
+	Repository *model.Repository `json:"repository"`
+	Artifacts  []*model.Artifact `json:"artifacts"` // 目标仓库中的制品记录
+}
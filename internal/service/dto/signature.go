@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// TrustedKeyRequest 添加受信任公钥请求
+type TrustedKeyRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Type        string `json:"type" binding:"required,oneof=pgp cosign"`
+	PublicKey   string `json:"public_key" binding:"required"` // pgp 为 ASCII 封装的公钥，cosign 为 PEM 编码的公钥
+	Description string `json:"description"`
+}
+
+// SignatureStatus 制品签名校验状态
+type SignatureStatus struct {
+	Repository    string     `json:"repository"`
+	Path          string     `json:"path"`
+	Status        string     `json:"status"`                   // verified, failed, unsigned
+	Type          string     `json:"type,omitempty"`           // pgp, cosign, sigstore
+	SignaturePath string     `json:"signature_path,omitempty"` // 签名文件路径
+	Key           string     `json:"key,omitempty"`            // 校验通过的受信任公钥名称
+	Fingerprint   string     `json:"fingerprint,omitempty"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+	Error         string     `json:"error,omitempty"` // 校验失败的原因
+}
+
+// SigningKeyRequest 导入签名密钥请求
+type SigningKeyRequest struct {
+	Name        string `json:"name" binding:"required"`
+	PrivateKey  string `json:"private_key" binding:"required"` // ASCII 封装的 OpenPGP 私钥
+	Passphrase  string `json:"passphrase"`                     // 私钥受密码保护时的密码，只用于导入时解密，不保存
+	Description string `json:"description"`
+	Active      bool   `json:"active"` // 导入后立即启用，第一个导入的密钥总是启用
+}
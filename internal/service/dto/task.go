@@ -0,0 +1,29 @@
+package dto
+
+// CreateTaskRequest 创建后台任务请求
+type CreateTaskRequest struct {
+	Name    string            `json:"name" binding:"required"`
+	Type    string            `json:"type" binding:"required"`
+	Cron    string            `json:"cron"` // 为空时只能手动执行
+	Params  map[string]string `json:"params"`
+	Enabled *bool             `json:"enabled"` // 缺省为 true
+}
+
+// UpdateTaskRequest 更新后台任务请求，字段为 nil 时保持不变
+type UpdateTaskRequest struct {
+	Cron    *string           `json:"cron"`
+	Params  map[string]string `json:"params"`
+	Enabled *bool             `json:"enabled"`
+}
+
+// ListTaskRunsQuery 查询任务执行记录
+type ListTaskRunsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// TaskType 可调度的任务类型
+type TaskType struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Params      []string `json:"params"` // 支持的参数名称
+}
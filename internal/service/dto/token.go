@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/laolishu/go-nexus/internal/repository/model"
+
+// CreateAccessTokenRequest 创建个人访问令牌请求
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"` // 为 0 时不过期
+}
+
+// CreatedAccessToken 新建的个人访问令牌，token 明文只在创建时返回一次
+type CreatedAccessToken struct {
+	*model.AccessToken
+	Token string `json:"token"`
+}
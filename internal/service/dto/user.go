@@ -0,0 +1,49 @@
+package dto
+
+// CreateUserRequest 创建用户请求
+type CreateUserRequest struct {
+	Username string   `json:"username" binding:"required"`
+	Email    string   `json:"email" binding:"required,email"`
+	Password string   `json:"password" binding:"required"`
+	FullName string   `json:"full_name"`
+	Status   string   `json:"status" binding:"omitempty,oneof=active inactive locked"` // 缺省为 active
+	Roles    []string `json:"roles"`
+}
+
+// UpdateUserRequest 更新用户请求，字段为 nil 时保持不变，password 用于管理员重置密码
+type UpdateUserRequest struct {
+	Email    *string `json:"email" binding:"omitempty,email"`
+	FullName *string `json:"full_name"`
+	Status   *string `json:"status" binding:"omitempty,oneof=active inactive locked"`
+	Password *string `json:"password"`
+}
+
+// ChangePasswordRequest 用户修改自己的密码，需提供当前密码
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// AssignRolesRequest 设置用户角色，替换原有角色
+type AssignRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// CreateRoleRequest 创建角色请求，权限格式见 auth.ValidatePermission
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description" binding:"max=200"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest 更新角色请求，字段为 nil 时保持不变
+type UpdateRoleRequest struct {
+	Description *string  `json:"description" binding:"omitempty,max=200"`
+	Permissions []string `json:"permissions"`
+}
+
+// ListUsersQuery 查询用户列表
+type ListUsersQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=active inactive locked"`
+	Q      string `form:"q"` // 模糊匹配用户名、邮箱与姓名
+}
@@ -0,0 +1,62 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 错误类别，处理器根据类别映射 HTTP 状态码
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrConflict        = errors.New("conflict")
+	ErrUnavailable     = errors.New("unavailable")
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// Error 服务层错误，Kind 为错误类别，Message 为返回给客户端的描述
+type Error struct {
+	Kind    error
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap 支持 errors.Is 判断错误类别
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NotFound 资源不存在
+func NotFound(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidArgument 参数错误
+func InvalidArgument(format string, args ...interface{}) error {
+	return &Error{Kind: ErrInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict 资源冲突
+func Conflict(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Unavailable 依赖的服务暂不可用，如代理仓库的远程被阻断
+func Unavailable(format string, args ...interface{}) error {
+	return &Error{Kind: ErrUnavailable, Message: fmt.Sprintf(format, args...)}
+}
+
+// Unauthenticated 身份认证失败，如用户名或密码错误
+func Unauthenticated(format string, args ...interface{}) error {
+	return &Error{Kind: ErrUnauthenticated, Message: fmt.Sprintf(format, args...)}
+}
+
+// Forbidden 已认证但不允许执行操作，如账户被锁定
+func Forbidden(format string, args ...interface{}) error {
+	return &Error{Kind: ErrForbidden, Message: fmt.Sprintf(format, args...)}
+}
@@ -1,21 +1,198 @@
 package impl
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync"
+	"time"
 
+	"github.com/laolishu/go-nexus/internal/fulltext"
+	"github.com/laolishu/go-nexus/internal/plugin"
 	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
 )
 
 // ArtifactServiceImpl 制品服务实现
 type ArtifactServiceImpl struct {
-	logger     *slog.Logger
-	repository repository.ArtifactRepository
+	logger       *slog.Logger
+	repository   repository.ArtifactRepository
+	repositories repository.RepositoryRepository
+	storage      pluginapi.StoragePlugin
+	plugins      *plugin.Manager
+	remote       *remoteFetcher
+	index        *fulltext.Index
+	trustedKeys  repository.TrustedKeyRepository
+	signer       *MetadataSigner
+
+	// metadataMutex 串行化宿主仓库元数据的重新生成
+	metadataMutex sync.Mutex
+	// stagingLocks 每个暂存仓库一把读写锁，写入持有读锁，关闭、发布与丢弃持有写锁
+	stagingLocks sync.Map
+	// pathLocks 正在写入的制品路径锁，按仓库ID与路径区分，没有上传等待时移除
+	pathMutex sync.Mutex
+	pathLocks map[string]*pathLock
 }
 
 // NewArtifactService 创建新的制品服务实现
-func NewArtifactService(logger *slog.Logger, repo repository.ArtifactRepository) *ArtifactServiceImpl {
+func NewArtifactService(
+	logger *slog.Logger,
+	repo repository.ArtifactRepository,
+	repositories repository.RepositoryRepository,
+	storage pluginapi.StoragePlugin,
+	plugins *plugin.Manager,
+	monitor *RemoteMonitor,
+	index *fulltext.Index,
+	trustedKeys repository.TrustedKeyRepository,
+	signer *MetadataSigner,
+) *ArtifactServiceImpl {
 	return &ArtifactServiceImpl{
-		logger:     logger,
-		repository: repo,
+		logger:       logger,
+		repository:   repo,
+		repositories: repositories,
+		storage:      storage,
+		plugins:      plugins,
+		remote:       newRemoteFetcher(monitor),
+		index:        index,
+		trustedKeys:  trustedKeys,
+		signer:       signer,
+	}
+}
+
+// Download 获取制品内容
+func (s *ArtifactServiceImpl) Download(ctx context.Context, repoIDOrName, rawPath string) (*dto.ArtifactContent, error) {
+	repo, err := lookupRepository(ctx, s.repositories, repoIDOrName)
+	if err != nil {
+		return nil, err
+	}
+	p, err := normalizePath(rawPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolve(ctx, repo, p, make(map[string]bool))
+}
+
+// resolve 按仓库类型读取内容，visited 用于防止组仓库循环引用
+func (s *ArtifactServiceImpl) resolve(ctx context.Context, repo *model.Repository, p string, visited map[string]bool) (*dto.ArtifactContent, error) {
+	if repo.Status == "inactive" {
+		return nil, errs.NotFound("repository %q is inactive", repo.Name)
+	}
+	if allowed, rule := routingDecision(repo, p); !allowed {
+		return nil, errs.NotFound("artifact %q is not routed to repository %q (%s)", p, repo.Name, rule)
 	}
+
+	switch repo.Type {
+	case model.RepositoryTypeHosted:
+		return s.readHosted(ctx, repo, p)
+	case model.RepositoryTypeProxy:
+		return s.readProxy(ctx, repo, p)
+	case model.RepositoryTypeGroup:
+		return s.readGroup(ctx, repo, p, visited)
+	default:
+		return nil, fmt.Errorf("unsupported repository type: %s", repo.Type)
+	}
+}
+
+// readHosted 从宿主仓库读取内容并累加下载次数
+func (s *ArtifactServiceImpl) readHosted(ctx context.Context, repo *model.Repository, p string) (*dto.ArtifactContent, error) {
+	content, err := s.readLocal(ctx, repo, p)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact, err := s.repository.FindByPath(ctx, repo.ID, p)
+	if err != nil {
+		s.logger.Warn("Failed to find artifact record", "repository", repo.Name, "path", p, "error", err)
+	} else if artifact != nil {
+		if err := s.repository.IncrementDownloadCount(ctx, artifact.ID); err != nil {
+			s.logger.Warn("Failed to increment download count", "artifact", artifact.ID, "error", err)
+		}
+	}
+	return content, nil
+}
+
+// readProxy 读取代理仓库内容，优先使用本地缓存
+// 元数据文件会随上游变化，总是先尝试远程，远程不可用或被阻断时回退到缓存
+func (s *ArtifactServiceImpl) readProxy(ctx context.Context, repo *model.Repository, p string) (*dto.ArtifactContent, error) {
+	_, isMetadata := s.metadataMerger(repo.Format, p)
+
+	if !isMetadata {
+		if content, err := s.readLocal(ctx, repo, p); err == nil {
+			return content, nil
+		} else if !errors.Is(err, errs.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	data, err := s.remote.Fetch(ctx, repo, p)
+	if err != nil {
+		if isMetadata && !errors.Is(err, errs.ErrNotFound) {
+			if cached, cacheErr := s.readLocal(ctx, repo, p); cacheErr == nil {
+				s.logger.Warn("Remote unavailable, serving cached metadata", "repository", repo.Name, "path", p, "error", err)
+				return cached, nil
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.storage.Upload(ctx, storagePath(repo, p), data); err != nil {
+		s.logger.Warn("Failed to cache proxied content", "repository", repo.Name, "path", p, "error", err)
+	}
+	return &dto.ArtifactContent{
+		Repository:  repo.Name,
+		Path:        p,
+		ContentType: contentTypeOf(repo.Format, p),
+		Data:        data,
+	}, nil
+}
+
+// readLocal 从存储后端读取仓库内容
+func (s *ArtifactServiceImpl) readLocal(ctx context.Context, repo *model.Repository, p string) (*dto.ArtifactContent, error) {
+	location := storagePath(repo, p)
+	exists, err := s.storage.Exists(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check artifact: %w", err)
+	}
+	if !exists {
+		return nil, errs.NotFound("artifact %q not found in repository %q", p, repo.Name)
+	}
+
+	data, err := s.storage.Download(ctx, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	return &dto.ArtifactContent{
+		Repository:  repo.Name,
+		Path:        p,
+		ContentType: contentTypeOf(repo.Format, p),
+		Data:        data,
+	}, nil
+}
+
+// metadataMerger 若路径是该格式需要合并的元数据文件，返回对应的合并器
+func (s *ArtifactServiceImpl) metadataMerger(format, p string) (pluginapi.MetadataMerger, bool) {
+	formatPlugin, err := s.plugins.GetFormatPlugin(format)
+	if err != nil {
+		return nil, false
+	}
+	merger, ok := formatPlugin.(pluginapi.MetadataMerger)
+	if !ok || !merger.IsMetadataPath(p) {
+		return nil, false
+	}
+	return merger, true
+}
+
+// publish 向集成插件发布制品事件
+func (s *ArtifactServiceImpl) publish(ctx context.Context, eventType string, repo *model.Repository, artifact *model.Artifact) {
+	s.plugins.PublishArtifactEvent(ctx, &pluginapi.ArtifactEvent{
+		Type:       eventType,
+		Artifact:   toPluginArtifact(artifact),
+		Repository: repo.Name,
+		Timestamp:  time.Now(),
+	})
 }
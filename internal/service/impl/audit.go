@@ -0,0 +1,200 @@
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// 审计日志动作
+const (
+	auditActionPromote        = "artifact.promote"
+	auditActionStagingClose   = "staging.close"
+	auditActionStagingRelease = "staging.release"
+	auditActionStagingDrop    = "staging.drop"
+	auditActionCleanup        = "cleanup.delete"
+)
+
+const (
+	// auditUserSystem 后台任务记录的用户名，请求中未认证的调用者记录为 anonymous
+	auditUserSystem = "system"
+	// 资源与 User-Agent 字段的最大长度
+	maxAuditResourceLength  = 500
+	maxAuditUserAgentLength = 500
+	// maxAuditExport 单次导出的最大条数
+	maxAuditExport = 100000
+)
+
+// auditCursor 审计日志分页游标内容
+type auditCursor struct {
+	CreatedAt string `json:"t"`
+	ID        string `json:"id"`
+}
+
+// AuditRecorder 记录与查询审计日志，写入失败只记录日志，不影响业务操作
+type AuditRecorder struct {
+	logger *slog.Logger
+	logs   repository.AuditLogRepository
+}
+
+// NewAuditRecorder 创建审计日志记录器
+func NewAuditRecorder(logger *slog.Logger, logs repository.AuditLogRepository) *AuditRecorder {
+	return &AuditRecorder{
+		logger: logger,
+		logs:   logs,
+	}
+}
+
+// Record 记录一条业务操作审计日志，details 序列化为 JSON 保存
+func (r *AuditRecorder) Record(ctx context.Context, action, resource string, details interface{}) {
+	entry := &model.AuditLog{Action: action, Resource: resource}
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			r.logger.Warn("Failed to encode audit details", "action", action, "error", err)
+		} else {
+			entry.Details = string(data)
+		}
+	}
+	r.write(ctx, entry)
+}
+
+// RecordRequest 记录一次修改类请求
+func (r *AuditRecorder) RecordRequest(ctx context.Context, action, resource string, status int) {
+	r.write(ctx, &model.AuditLog{Action: action, Resource: resource, Status: status})
+}
+
+// List 分页查询审计日志
+func (r *AuditRecorder) List(ctx context.Context, query *dto.ListAuditQuery) (*dto.AuditPage, error) {
+	filter, err := auditFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	filter.Limit = limit + 1
+
+	entries, err := r.logs.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	page := &dto.AuditPage{Items: entries, Limit: limit}
+	if len(entries) > limit {
+		page.Items = entries[:limit]
+		page.HasMore = true
+		page.NextCursor = encodeAuditCursor(page.Items[limit-1])
+	}
+	return page, nil
+}
+
+// Export 按时间倒序遍历所有匹配的审计日志
+func (r *AuditRecorder) Export(ctx context.Context, query *dto.ListAuditQuery, fn func(entry *model.AuditLog) error) error {
+	filter, err := auditFilter(query)
+	if err != nil {
+		return err
+	}
+	filter.Limit = maxPageLimit
+	exported := 0
+	for {
+		entries, err := r.logs.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list audit logs: %w", err)
+		}
+		for _, entry := range entries {
+			if exported >= maxAuditExport {
+				return errs.InvalidArgument("more than %d audit logs match, narrow the time range", maxAuditExport)
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+			exported++
+		}
+		if len(entries) < filter.Limit {
+			return nil
+		}
+		last := entries[len(entries)-1]
+		filter.After = &model.AuditLogCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// write 补充调用者与请求信息后写入审计日志
+func (r *AuditRecorder) write(ctx context.Context, entry *model.AuditLog) {
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = time.Now()
+	entry.Username = auditUserSystem
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		entry.UserID = principal.UserID
+		entry.Username = principal.Username
+	}
+	if info, ok := auth.RequestInfoFromContext(ctx); ok {
+		if entry.UserID == "" {
+			entry.Username = auth.AnonymousPrincipal.Username
+		}
+		entry.RequestID = info.ID
+		entry.IP = info.IP
+		entry.UserAgent = truncate(info.UserAgent, maxAuditUserAgentLength)
+	}
+	entry.Resource = truncate(entry.Resource, maxAuditResourceLength)
+	// 请求取消后仍需写入审计日志
+	if err := r.logs.Create(context.WithoutCancel(ctx), entry); err != nil {
+		r.logger.Error("Failed to write audit log", "action", entry.Action, "resource", entry.Resource, "error", err)
+	}
+}
+
+// auditFilter 将查询条件转换为持久层过滤条件
+func auditFilter(query *dto.ListAuditQuery) (*model.AuditLogFilter, error) {
+	filter := &model.AuditLogFilter{
+		User:           query.User,
+		Action:         query.Action,
+		ResourcePrefix: query.Resource,
+		RequestID:      query.RequestID,
+		Status:         query.Status,
+		From:           query.From,
+		To:             query.To,
+	}
+	if query.Cursor != "" {
+		after, err := decodeAuditCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+	return filter, nil
+}
+
+// encodeAuditCursor 以记录的时间与ID生成游标
+func encodeAuditCursor(last *model.AuditLog) string {
+	data, _ := json.Marshal(auditCursor{CreatedAt: last.CreatedAt.Format(time.RFC3339Nano), ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeAuditCursor 解析游标
+func decodeAuditCursor(raw string) (*model.AuditLogCursor, error) {
+	invalid := errs.InvalidArgument("invalid cursor")
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor auditCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, invalid
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
+	if err != nil {
+		return nil, invalid
+	}
+	return &model.AuditLogCursor{CreatedAt: createdAt, ID: cursor.ID}, nil
+}
@@ -0,0 +1,227 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// withRequest 附带请求来源信息的 context
+func withRequest(ctx context.Context, id string) context.Context {
+	return auth.WithRequestInfo(ctx, &auth.RequestInfo{ID: id, IP: "10.0.0.1", UserAgent: "maven/3.9"})
+}
+
+// auditActions 返回审计日志的动作列表
+func auditActions(entries []*model.AuditLog) []string {
+	actions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+func TestAuditRecorder_Record(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantUser     string
+		wantUserID   string
+		wantRequest  string
+		wantIP       string
+		wantAgentLen int
+	}{
+		{name: "background_task", ctx: context.Background(), wantUser: auditUserSystem},
+		{name: "authenticated_request", ctx: withRequest(asUser("alice"), "req-1"), wantUser: "alice", wantUserID: "alice", wantRequest: "req-1", wantIP: "10.0.0.1", wantAgentLen: len("maven/3.9")},
+		{name: "unauthenticated_request", ctx: withRequest(context.Background(), "req-2"), wantUser: auth.AnonymousPrincipal.Username, wantRequest: "req-2", wantIP: "10.0.0.1", wantAgentLen: len("maven/3.9")},
+		{name: "long_user_agent", ctx: auth.WithRequestInfo(context.Background(), &auth.RequestInfo{ID: "req-3", UserAgent: strings.Repeat("a", 2*maxAuditUserAgentLength)}),
+			wantUser: auth.AnonymousPrincipal.Username, wantRequest: "req-3", wantAgentLen: maxAuditUserAgentLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.audit.RecordRequest(tt.ctx, "repository.update", "repository:libs", 200)
+
+			page, err := env.audit.List(context.Background(), &dto.ListAuditQuery{})
+			require.NoError(t, err)
+			require.Len(t, page.Items, 1)
+			entry := page.Items[0]
+			assert.NotEmpty(t, entry.ID)
+			assert.Equal(t, "repository.update", entry.Action)
+			assert.Equal(t, "repository:libs", entry.Resource)
+			assert.Equal(t, 200, entry.Status)
+			assert.Equal(t, tt.wantUser, entry.Username)
+			assert.Equal(t, tt.wantUserID, entry.UserID)
+			assert.Equal(t, tt.wantRequest, entry.RequestID)
+			assert.Equal(t, tt.wantIP, entry.IP)
+			assert.Len(t, entry.UserAgent, tt.wantAgentLen)
+		})
+	}
+
+	t.Run("details", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.audit.Record(context.Background(), auditActionPromote, "artifact:libs/a.jar", map[string]interface{}{"target": "releases", "count": 2})
+		env.audit.Record(context.Background(), auditActionCleanup, strings.Repeat("r", 2*maxAuditResourceLength), func() {})
+
+		page, err := env.audit.List(context.Background(), &dto.ListAuditQuery{})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+		assert.Empty(t, page.Items[0].Details, "unencodable details are dropped")
+		assert.Len(t, page.Items[0].Resource, maxAuditResourceLength)
+		assert.JSONEq(t, `{"target":"releases","count":2}`, page.Items[1].Details)
+	})
+
+	t.Run("canceled_request_still_recorded", func(t *testing.T) {
+		env := newTestEnv(t)
+		ctx, cancel := context.WithCancel(withRequest(asUser("alice"), "req-4"))
+		cancel()
+		env.audit.RecordRequest(ctx, "artifact.delete", "artifact:libs/a.jar", 200)
+
+		page, err := env.audit.List(context.Background(), &dto.ListAuditQuery{})
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+	})
+}
+
+func TestAuditRecorder_List(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.audit.RecordRequest(withRequest(asUser("alice"), "req-1"), "repository.create", "repository:libs-release", 201)
+	env.audit.RecordRequest(withRequest(asUser("alice"), "req-2"), "repository.delete", "repository:libs-snapshot", 409)
+	env.audit.RecordRequest(withRequest(asUser("bob"), "req-3"), "artifact.deploy", "artifact:libs-release/com/x/a.jar", 201)
+	env.audit.RecordRequest(withRequest(asUser("bob"), "req-4"), "user.update", "user:alice", 403)
+	env.audit.RecordRequest(withRequest(context.Background(), "req-5"), "auth.login", "user:mallory", 401)
+	// 最早的一条记录移到一天前
+	yesterday := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, env.db.Model(&model.AuditLog{}).Where("request_id = ?", "req-1").Update("created_at", yesterday).Error)
+	hourAgo := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		query *dto.ListAuditQuery
+		want  []string
+	}{
+		{name: "all_newest_first", query: &dto.ListAuditQuery{}, want: []string{"auth.login", "user.update", "artifact.deploy", "repository.delete", "repository.create"}},
+		{name: "user_by_name", query: &dto.ListAuditQuery{User: "alice"}, want: []string{"repository.delete", "repository.create"}},
+		{name: "user_by_id", query: &dto.ListAuditQuery{User: "bob"}, want: []string{"user.update", "artifact.deploy"}},
+		{name: "anonymous", query: &dto.ListAuditQuery{User: "anonymous"}, want: []string{"auth.login"}},
+		{name: "action_wildcard", query: &dto.ListAuditQuery{Action: "repository.*"}, want: []string{"repository.delete", "repository.create"}},
+		{name: "action_exact", query: &dto.ListAuditQuery{Action: "user.update"}, want: []string{"user.update"}},
+		{name: "resource_prefix", query: &dto.ListAuditQuery{Resource: "repository:libs-"}, want: []string{"repository.delete", "repository.create"}},
+		{name: "resource_prefix_escapes_like", query: &dto.ListAuditQuery{Resource: "repository:libs_"}, want: []string{}},
+		{name: "request_id", query: &dto.ListAuditQuery{RequestID: "req-3"}, want: []string{"artifact.deploy"}},
+		{name: "status", query: &dto.ListAuditQuery{Status: 401}, want: []string{"auth.login"}},
+		{name: "from", query: &dto.ListAuditQuery{From: &hourAgo}, want: []string{"auth.login", "user.update", "artifact.deploy", "repository.delete"}},
+		{name: "to", query: &dto.ListAuditQuery{To: &hourAgo}, want: []string{"repository.create"}},
+		{name: "combined", query: &dto.ListAuditQuery{User: "alice", Status: 409}, want: []string{"repository.delete"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := env.audit.List(ctx, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, auditActions(page.Items))
+			assert.False(t, page.HasMore)
+			assert.Equal(t, defaultPageLimit, page.Limit)
+		})
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		var actions []string
+		query := &dto.ListAuditQuery{Limit: 2}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 5)
+			page, err := env.audit.List(ctx, query)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(page.Items), 2)
+			actions = append(actions, auditActions(page.Items)...)
+			if !page.HasMore {
+				assert.Empty(t, page.NextCursor)
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		assert.Equal(t, []string{"auth.login", "user.update", "artifact.deploy", "repository.delete", "repository.create"}, actions)
+	})
+
+	t.Run("pages_with_filter", func(t *testing.T) {
+		page, err := env.audit.List(ctx, &dto.ListAuditQuery{User: "alice", Limit: 1})
+		require.NoError(t, err)
+		require.True(t, page.HasMore)
+		page, err = env.audit.List(ctx, &dto.ListAuditQuery{User: "alice", Limit: 1, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"repository.create"}, auditActions(page.Items))
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("invalid_cursor", func(t *testing.T) {
+		for _, cursor := range []string{"!!!", "bm90LWpzb24", "e30", "eyJ0Ijoibm93IiwiaWQiOiJ4In0"} {
+			_, err := env.audit.List(ctx, &dto.ListAuditQuery{Cursor: cursor})
+			assert.ErrorIs(t, err, errs.ErrInvalidArgument, cursor)
+		}
+	})
+}
+
+func TestAuditRecorder_Export(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	// 超过一页的记录，验证导出按游标遍历全部
+	total := maxPageLimit + 5
+	for i := 0; i < total; i++ {
+		action := "artifact.deploy"
+		if i%2 == 0 {
+			action = "artifact.delete"
+		}
+		env.audit.RecordRequest(ctx, action, "artifact:libs/a.jar", 200)
+	}
+
+	t.Run("all", func(t *testing.T) {
+		seen := map[string]bool{}
+		var last time.Time
+		err := env.audit.Export(ctx, &dto.ListAuditQuery{}, func(entry *model.AuditLog) error {
+			assert.False(t, seen[entry.ID], "no entry is exported twice")
+			if !last.IsZero() {
+				assert.False(t, entry.CreatedAt.After(last), "newest first")
+			}
+			seen[entry.ID], last = true, entry.CreatedAt
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Len(t, seen, total)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		count := 0
+		err := env.audit.Export(ctx, &dto.ListAuditQuery{Action: "artifact.delete"}, func(entry *model.AuditLog) error {
+			assert.Equal(t, "artifact.delete", entry.Action)
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, (total+1)/2, count)
+	})
+
+	t.Run("writer_error_stops", func(t *testing.T) {
+		broken := errors.New("client went away")
+		count := 0
+		err := env.audit.Export(ctx, &dto.ListAuditQuery{}, func(*model.AuditLog) error {
+			count++
+			return broken
+		})
+		assert.ErrorIs(t, err, broken)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("invalid_cursor", func(t *testing.T) {
+		err := env.audit.Export(ctx, &dto.ListAuditQuery{Cursor: "!!!"}, func(*model.AuditLog) error { return nil })
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+}
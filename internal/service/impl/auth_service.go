@@ -0,0 +1,490 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	// tokenIssuer JWT 签发者
+	tokenIssuer = "go-nexus"
+	// 令牌类型，刷新令牌不能用于访问接口
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+	// tokenTypeOIDCState OIDC 登录状态，只用于回调校验
+	tokenTypeOIDCState = "oidc_state"
+
+	defaultAccessExpire  = time.Hour
+	defaultRefreshExpire = 7 * 24 * time.Hour
+	// oidcStateExpire OIDC 登录状态的有效期，用户需在此时间内完成签发者的登录
+	oidcStateExpire = 10 * time.Minute
+)
+
+// sampleJWTSecrets 示例配置与文档中出现过的密钥，使用这些密钥签发的令牌可被任何人伪造
+var sampleJWTSecrets = []string{"your-secret-key-change-in-production", "your-secret-key"}
+
+// tokenClaims 签发的 JWT 声明，Subject 为用户ID
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Type     string `json:"typ"`
+	Username string `json:"username"`
+}
+
+// oidcStateClaims OIDC 登录状态，签名后保存在 Cookie 中，回调时校验 state、nonce 并取回 PKCE 校验码
+type oidcStateClaims struct {
+	jwt.RegisteredClaims
+	Type     string `json:"typ"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect,omitempty"`
+}
+
+// AuthServiceImpl 认证服务实现，签发 HS256 JWT，注销的令牌记录在数据库中直至过期
+type AuthServiceImpl struct {
+	logger        *slog.Logger
+	users         repository.UserRepository
+	realms        *RealmChain
+	oidc          *OIDCRealm
+	revoked       repository.RevokedTokenRepository
+	tokens        *TokenServiceImpl
+	secret        []byte
+	accessExpire  time.Duration
+	refreshExpire time.Duration
+	anonymousRead bool
+}
+
+// NewAuthService 创建新的认证服务实现，未配置 JWT 密钥时使用随机密钥，重启后已签发的令牌失效
+// 密钥同时用于访问令牌、OIDC 登录状态与 Docker 令牌的签名，配置为示例密钥时拒绝启动
+func NewAuthService(
+	cfg *config.Config,
+	logger *slog.Logger,
+	users repository.UserRepository,
+	realms *RealmChain,
+	oidc *OIDCRealm,
+	revoked repository.RevokedTokenRepository,
+	tokens *TokenServiceImpl,
+) (*AuthServiceImpl, error) {
+	s := &AuthServiceImpl{
+		logger:        logger,
+		users:         users,
+		realms:        realms,
+		oidc:          oidc,
+		revoked:       revoked,
+		tokens:        tokens,
+		secret:        []byte(cfg.Security.JWTSecret),
+		accessExpire:  defaultAccessExpire,
+		refreshExpire: defaultRefreshExpire,
+		anonymousRead: cfg.Security.AnonymousRead,
+	}
+	var err error
+	if cfg.Security.JWTExpire != "" {
+		if s.accessExpire, err = time.ParseDuration(cfg.Security.JWTExpire); err != nil || s.accessExpire <= 0 {
+			return nil, fmt.Errorf("invalid security.jwt_expire %q", cfg.Security.JWTExpire)
+		}
+	}
+	if cfg.Security.RefreshExpire != "" {
+		if s.refreshExpire, err = time.ParseDuration(cfg.Security.RefreshExpire); err != nil || s.refreshExpire <= 0 {
+			return nil, fmt.Errorf("invalid security.refresh_expire %q", cfg.Security.RefreshExpire)
+		}
+	}
+
+	for _, sample := range sampleJWTSecrets {
+		if cfg.Security.JWTSecret == sample {
+			return nil, fmt.Errorf("security.jwt_secret is the sample value %q, set a unique secret or leave it empty to use a random one", sample)
+		}
+	}
+	// 配置文件不展开环境变量，占位符会被原样当作密钥
+	if secret := cfg.Security.JWTSecret; strings.HasPrefix(secret, "${") && strings.HasSuffix(secret, "}") {
+		return nil, fmt.Errorf("security.jwt_secret %q is an unexpanded placeholder, set GO_NEXUS_SECURITY_JWT_SECRET instead", secret)
+	}
+	if cfg.Security.JWTSecret == "" {
+		s.secret = make([]byte, 32)
+		if _, err := rand.Read(s.secret); err != nil {
+			return nil, fmt.Errorf("failed to generate JWT secret: %w", err)
+		}
+		logger.Warn("security.jwt_secret is not set, using a random secret; tokens will not survive a restart")
+	}
+	return s, nil
+}
+
+// Login 通过认证链校验用户名与密码，记录登录时间并签发令牌
+func (s *AuthServiceImpl) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	user, err := s.realms.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnauthenticated) || errors.Is(err, errs.ErrForbidden) {
+			s.logger.Warn("Login failed", "username", req.Username, "error", err)
+		}
+		return nil, err
+	}
+
+	return s.login(ctx, user)
+}
+
+// OIDCAuthorize 生成 state、nonce 与 PKCE 校验码，签名后作为登录状态返回
+func (s *AuthServiceImpl) OIDCAuthorize(ctx context.Context, redirect string) (*dto.OIDCAuthorization, error) {
+	if !s.oidc.Enabled() {
+		return nil, errs.NotFound("OIDC login is not enabled")
+	}
+	if redirect != "" && !isLocalRedirect(redirect) {
+		return nil, errs.InvalidArgument("redirect must be a path on this server")
+	}
+	var values [3]string
+	for i := range values {
+		value, err := randomURLSafe(32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+	authURL, err := s.oidc.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := &oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcStateExpire)),
+		},
+		Type:     tokenTypeOIDCState,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: verifier,
+		Redirect: redirect,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign OIDC state: %w", err)
+	}
+	return &dto.OIDCAuthorization{URL: authURL, State: signed, ExpiresIn: int64(oidcStateExpire / time.Second)}, nil
+}
+
+// OIDCCallback 校验登录状态与回调的 state，换取 ID 令牌并登录
+func (s *AuthServiceImpl) OIDCCallback(ctx context.Context, query *dto.OIDCCallbackQuery, state string) (*dto.TokenResponse, string, error) {
+	if !s.oidc.Enabled() {
+		return nil, "", errs.NotFound("OIDC login is not enabled")
+	}
+	if query.Error != "" {
+		return nil, "", errs.Unauthenticated("OIDC login failed: %s %s", query.Error, query.ErrorDescription)
+	}
+	if query.Code == "" || query.State == "" {
+		return nil, "", errs.InvalidArgument("code and state are required")
+	}
+	claims := &oidcStateClaims{}
+	_, err := jwt.ParseWithClaims(state, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer), jwt.WithExpirationRequired())
+	if err != nil || claims.Type != tokenTypeOIDCState {
+		return nil, "", errs.Unauthenticated("OIDC login state is missing or has expired, start the login again")
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.State), []byte(query.State)) != 1 {
+		return nil, "", errs.Unauthenticated("OIDC login state does not match")
+	}
+
+	user, err := s.oidc.Exchange(ctx, query.Code, claims.Verifier, claims.Nonce)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnauthenticated) || errors.Is(err, errs.ErrForbidden) {
+			s.logger.Warn("OIDC login failed", "error", err)
+		}
+		return nil, "", err
+	}
+	tokens, err := s.login(ctx, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return tokens, claims.Redirect, nil
+}
+
+// login 记录登录时间并签发令牌
+func (s *AuthServiceImpl) login(ctx context.Context, user *model.User) (*dto.TokenResponse, error) {
+	now := time.Now()
+	if err := s.users.UpdateColumns(ctx, user.ID, map[string]interface{}{"last_login": now}); err != nil {
+		return nil, fmt.Errorf("failed to update last login: %w", err)
+	}
+	user.LastLogin = &now
+	s.logger.Info("User logged in", "username", user.Username, "realm", user.Realm)
+	return s.issue(user, now)
+}
+
+// Refresh 校验刷新令牌与用户状态后签发新的令牌，旧的刷新令牌注销
+func (s *AuthServiceImpl) Refresh(ctx context.Context, req *dto.RefreshRequest) (*dto.TokenResponse, error) {
+	claims, err := s.parse(ctx, req.RefreshToken, tokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.activeUser(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	// 并发使用同一刷新令牌时只有先注销的请求签发新令牌
+	revoked, err := s.revoke(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, errs.Unauthenticated("token has been revoked")
+	}
+	return s.issue(user, time.Now())
+}
+
+// Logout 注销调用者的访问令牌，请求中的刷新令牌属于同一用户时一并注销
+func (s *AuthServiceImpl) Logout(ctx context.Context, principal *auth.Principal, req *dto.LogoutRequest) error {
+	if principal == nil || principal.Anonymous || principal.TokenID == "" {
+		return errs.Unauthenticated("authentication required")
+	}
+	switch principal.Credential {
+	case auth.CredentialAccessToken:
+		return errs.InvalidArgument("personal access tokens cannot be logged out, revoke them via /api/v1/tokens")
+	case auth.CredentialOIDC:
+		return errs.InvalidArgument("OIDC workload tokens cannot be logged out")
+	}
+	if _, err := s.revoke(ctx, &tokenClaims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:        principal.TokenID,
+		Subject:   principal.UserID,
+		ExpiresAt: jwt.NewNumericDate(principal.ExpiresAt),
+	}}); err != nil {
+		return err
+	}
+	if req.RefreshToken != "" {
+		claims, err := s.parse(ctx, req.RefreshToken, tokenTypeRefresh)
+		if err == nil && claims.Subject == principal.UserID {
+			if _, err := s.revoke(ctx, claims); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := s.revoked.DeleteExpired(ctx, time.Now()); err != nil {
+		s.logger.Warn("Failed to prune revoked tokens", "error", err)
+	}
+	s.logger.Info("User logged out", "username", principal.Username)
+	return nil
+}
+
+// Authenticate 校验访问令牌、个人访问令牌或 CI 工作负载的 OIDC 令牌，用户被删除、停用或锁定后令牌立即失效
+func (s *AuthServiceImpl) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	if isAccessToken(token) {
+		return s.tokens.Authenticate(ctx, "", token)
+	}
+	if issuer := unverifiedIssuer(token); issuer != tokenIssuer && s.oidc.TrustsIssuer(issuer) {
+		return s.authenticateWorkload(ctx, token, issuer)
+	}
+	claims, err := s.parse(ctx, token, tokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.activeUser(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	return newPrincipal(user, auth.CredentialJWT, claims.ID, claims.ExpiresAt.Time), nil
+}
+
+// AuthenticateBasic 校验 HTTP Basic 凭据，密码必须是该用户的个人访问令牌
+func (s *AuthServiceImpl) AuthenticateBasic(ctx context.Context, username, password string) (*auth.Principal, error) {
+	if username == "" || !isAccessToken(password) {
+		return nil, errs.Unauthenticated("basic authentication requires a personal access token as password")
+	}
+	return s.tokens.Authenticate(ctx, username, password)
+}
+
+// authenticateWorkload 校验工作负载令牌，以工作负载身份配置的用户与范围访问
+func (s *AuthServiceImpl) authenticateWorkload(ctx context.Context, token, issuer string) (*auth.Principal, error) {
+	user, workload, claims, err := s.oidc.AuthenticateWorkload(ctx, token, issuer)
+	if err != nil {
+		return nil, err
+	}
+	var expiresAt time.Time
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		expiresAt = exp.Time
+	}
+	jti, _ := claims["jti"].(string)
+	principal := newPrincipal(user, auth.CredentialOIDC, jti, expiresAt)
+	if len(workload.Scopes) > 0 {
+		principal.Scopes = workload.Scopes
+	}
+	return principal, nil
+}
+
+// AuthenticatePassword 通过认证链校验账户密码，密码为个人访问令牌时按令牌校验
+func (s *AuthServiceImpl) AuthenticatePassword(ctx context.Context, username, password string) (*auth.Principal, error) {
+	if isAccessToken(password) {
+		return s.tokens.Authenticate(ctx, username, password)
+	}
+	user, err := s.realms.Authenticate(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnauthenticated) || errors.Is(err, errs.ErrForbidden) {
+			s.logger.Warn("Password authentication failed", "username", username, "error", err)
+		}
+		return nil, err
+	}
+	return newPrincipal(user, auth.CredentialPassword, "", time.Time{}), nil
+}
+
+// AnonymousRead 是否允许匿名读取
+func (s *AuthServiceImpl) AnonymousRead() bool {
+	return s.anonymousRead
+}
+
+// issue 为用户签发访问令牌与刷新令牌
+func (s *AuthServiceImpl) issue(user *model.User, now time.Time) (*dto.TokenResponse, error) {
+	access, err := s.sign(user, tokenTypeAccess, now, s.accessExpire)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := s.sign(user, tokenTypeRefresh, now, s.refreshExpire)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenResponse{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.accessExpire / time.Second),
+		RefreshExpiresIn: int64(s.refreshExpire / time.Second),
+		User:             user,
+	}, nil
+}
+
+// sign 签发指定类型的令牌
+func (s *AuthServiceImpl) sign(user *model.User, tokenType string, now time.Time, ttl time.Duration) (string, error) {
+	claims := &tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    tokenIssuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Type:     tokenType,
+		Username: user.Username,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// parse 校验令牌签名、签发者、有效期、类型与注销状态
+func (s *AuthServiceImpl) parse(ctx context.Context, token, tokenType string) (*tokenClaims, error) {
+	claims := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errs.Unauthenticated("token has expired")
+		}
+		return nil, errs.Unauthenticated("invalid token")
+	}
+	if claims.Type != tokenType || claims.ID == "" {
+		return nil, errs.Unauthenticated("invalid token")
+	}
+	revoked, err := s.revoked.Exists(ctx, claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token: %w", err)
+	}
+	if revoked {
+		return nil, errs.Unauthenticated("token has been revoked")
+	}
+	return claims, nil
+}
+
+// revoke 记录注销的令牌直至其过期，令牌此前已被注销时返回 false
+func (s *AuthServiceImpl) revoke(ctx context.Context, claims *tokenClaims) (bool, error) {
+	expiresAt := time.Now().Add(s.refreshExpire)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	revoked, err := s.revoked.Create(ctx, &model.RevokedToken{
+		ID:        claims.ID,
+		UserID:    claims.Subject,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return revoked, nil
+}
+
+// activeUser 查找令牌对应的用户，用户不存在或不是 active 状态时拒绝
+func (s *AuthServiceImpl) activeUser(ctx context.Context, id string) (*model.User, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, errs.Unauthenticated("invalid token")
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// unverifiedIssuer 读取 JWT 的签发者用于选择校验方式，签名在之后校验
+func unverifiedIssuer(token string) string {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return ""
+	}
+	return claims.Issuer
+}
+
+// isLocalRedirect 判断跳转地址是否为本站路径，避免开放重定向；
+// 浏览器会忽略制表符等控制字符并把反斜杠当作斜杠，这些字符一律拒绝
+func isLocalRedirect(redirect string) bool {
+	for i := 0; i < len(redirect); i++ {
+		if c := redirect[i]; c < 0x20 || c == 0x7f || c == '\\' {
+			return false
+		}
+	}
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+		return false
+	}
+	u, err := url.Parse(redirect)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil {
+		return false
+	}
+	return strings.HasPrefix(u.Path, "/") && !strings.HasPrefix(u.Path, "//")
+}
+
+// newPrincipal 根据用户构造调用者
+func newPrincipal(user *model.User, credential, tokenID string, expiresAt time.Time) *auth.Principal {
+	roles := make([]string, 0, len(user.Roles))
+	var permissions []string
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+		permissions = append(permissions, role.Permissions...)
+	}
+	return &auth.Principal{
+		UserID:      user.ID,
+		Username:    user.Username,
+		Roles:       roles,
+		Permissions: permissions,
+		Credential:  credential,
+		TokenID:     tokenID,
+		ExpiresAt:   expiresAt,
+	}
+}
@@ -0,0 +1,541 @@
+package impl
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	repoimpl "github.com/laolishu/go-nexus/internal/repository/impl"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// testJWTSecret 认证测试使用的 JWT 密钥
+const testJWTSecret = "test-jwt-secret-0123456789abcdef"
+
+// authEnv 认证测试环境：用户、个人访问令牌与认证服务
+type authEnv struct {
+	*testEnv
+	users  *UserServiceImpl
+	tokens *TokenServiceImpl
+	oidc   *OIDCRealm
+	auth   *AuthServiceImpl
+}
+
+// newAuthEnv 创建认证服务，configure 可在创建前修改配置
+func newAuthEnv(t *testing.T, configure func(cfg *config.Config)) *authEnv {
+	t.Helper()
+	env := newTestEnv(t)
+	env.cfg.Security.JWTSecret = testJWTSecret
+	env.cfg.Security.Realms = []string{model.UserRealmLocal}
+	env.cfg.Security.AdminPassword = "admin-password"
+	if configure != nil {
+		configure(env.cfg)
+	}
+
+	users := NewUserService(env.cfg, env.logger, env.userRepo, env.roleRepo)
+	require.NoError(t, users.Bootstrap(context.Background()))
+	realms, err := NewRealmChain(env.cfg, env.logger, users)
+	require.NoError(t, err)
+	oidc, err := NewOIDCRealm(env.cfg, env.logger, users)
+	require.NoError(t, err)
+	tokens := NewTokenService(env.logger, repoimpl.NewAccessTokenRepository(env.logger, dao.NewAccessTokenDAO(env.logger, env.db)), users)
+	revoked := repoimpl.NewRevokedTokenRepository(env.logger, dao.NewRevokedTokenDAO(env.logger, env.db))
+	service, err := NewAuthService(env.cfg, env.logger, env.userRepo, realms, oidc, revoked, tokens)
+	require.NoError(t, err)
+	return &authEnv{testEnv: env, users: users, tokens: tokens, oidc: oidc, auth: service}
+}
+
+// login 以用户名密码登录，失败时终止测试
+func (e *authEnv) login(t *testing.T, username, password string) *dto.TokenResponse {
+	t.Helper()
+	tokens, err := e.auth.Login(context.Background(), &dto.LoginRequest{Username: username, Password: password})
+	require.NoError(t, err)
+	return tokens
+}
+
+// signTestToken 以指定密钥签发任意声明的 HS256 令牌
+func signTestToken(t *testing.T, secret string, claims jwt.Claims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestNewAuthService_Secret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		expire  string
+		refresh string
+		wantErr bool
+	}{
+		{name: "configured", secret: testJWTSecret},
+		{name: "empty_uses_random", secret: ""},
+		{name: "sample_value", secret: "your-secret-key-change-in-production", wantErr: true},
+		{name: "documented_sample", secret: "your-secret-key", wantErr: true},
+		{name: "unexpanded_placeholder", secret: "${JWT_SECRET}", wantErr: true},
+		{name: "invalid_expire", secret: testJWTSecret, expire: "soon", wantErr: true},
+		{name: "negative_expire", secret: testJWTSecret, expire: "-1h", wantErr: true},
+		{name: "invalid_refresh", secret: testJWTSecret, refresh: "0s", wantErr: true},
+		{name: "custom_expire", secret: testJWTSecret, expire: "15m", refresh: "24h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			cfg := *env.cfg
+			cfg.Security.JWTSecret = tt.secret
+			cfg.Security.JWTExpire = tt.expire
+			cfg.Security.RefreshExpire = tt.refresh
+			service, err := NewAuthService(&cfg, env.logger, env.userRepo, nil, nil, nil, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, service.secret, max(len(tt.secret), 32))
+			if tt.secret != "" {
+				assert.Equal(t, tt.secret, string(service.secret))
+			}
+		})
+	}
+
+	t.Run("random_secrets_differ", func(t *testing.T) {
+		env := newTestEnv(t)
+		first, err := NewAuthService(env.cfg, env.logger, env.userRepo, nil, nil, nil, nil)
+		require.NoError(t, err)
+		second, err := NewAuthService(env.cfg, env.logger, env.userRepo, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.secret, second.secret)
+	})
+}
+
+func TestAuthService_Login(t *testing.T) {
+	env := newAuthEnv(t, func(cfg *config.Config) { cfg.Security.JWTExpire = "30m" })
+	ctx := context.Background()
+
+	tokens := env.login(t, initialAdminUsername, "admin-password")
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.EqualValues(t, 30*60, tokens.ExpiresIn)
+	assert.EqualValues(t, defaultRefreshExpire/time.Second, tokens.RefreshExpiresIn)
+	require.NotNil(t, tokens.User.LastLogin)
+
+	principal, err := env.auth.Authenticate(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, initialAdminUsername, principal.Username)
+	assert.Equal(t, auth.CredentialJWT, principal.Credential)
+	assert.Equal(t, []string{model.RoleAdmin}, principal.Roles)
+	assert.NotEmpty(t, principal.TokenID)
+
+	stored, err := env.userRepo.FindByUsername(ctx, initialAdminUsername)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastLogin, "login records last_login")
+
+	t.Run("failures", func(t *testing.T) {
+		createUser(t, env.users, "alice")
+		locked := model.UserStatusLocked
+		_, err := env.users.Update(ctx, "alice", &dto.UpdateUserRequest{Status: &locked})
+		require.NoError(t, err)
+
+		tests := []struct {
+			name     string
+			username string
+			password string
+			wantErr  error
+		}{
+			{name: "wrong_password", username: initialAdminUsername, password: "wrong-password", wantErr: errs.ErrUnauthenticated},
+			{name: "unknown_user", username: "nobody", password: "admin-password", wantErr: errs.ErrUnauthenticated},
+			{name: "locked", username: "alice", password: testPassword, wantErr: errs.ErrForbidden},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := env.auth.Login(ctx, &dto.LoginRequest{Username: tt.username, Password: tt.password})
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	env := newAuthEnv(t, nil)
+	ctx := context.Background()
+	createUser(t, env.users, "alice", model.RoleViewer)
+	tokens := env.login(t, "alice", testPassword)
+	alice, err := env.userRepo.FindByUsername(ctx, "alice")
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := func(typ, subject string, expires time.Time) *tokenClaims {
+		return &tokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "jti-" + typ,
+				Issuer:    tokenIssuer,
+				Subject:   subject,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(expires),
+			},
+			Type: typ,
+		}
+	}
+	noExpiry := claims(tokenTypeAccess, alice.ID, now)
+	noExpiry.ExpiresAt = nil
+	wrongIssuer := claims(tokenTypeAccess, alice.ID, now.Add(time.Hour))
+	wrongIssuer.Issuer = "someone-else"
+	noID := claims(tokenTypeAccess, alice.ID, now.Add(time.Hour))
+	noID.ID = ""
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "access_token", token: tokens.AccessToken},
+		{name: "forged_with_sample_secret", token: signTestToken(t, "your-secret-key-change-in-production", claims(tokenTypeAccess, alice.ID, now.Add(time.Hour))), wantErr: errs.ErrUnauthenticated},
+		{name: "refresh_token_rejected", token: tokens.RefreshToken, wantErr: errs.ErrUnauthenticated},
+		{name: "expired", token: signTestToken(t, testJWTSecret, claims(tokenTypeAccess, alice.ID, now.Add(-time.Minute))), wantErr: errs.ErrUnauthenticated},
+		{name: "missing_expiry", token: signTestToken(t, testJWTSecret, noExpiry), wantErr: errs.ErrUnauthenticated},
+		{name: "wrong_issuer", token: signTestToken(t, testJWTSecret, wrongIssuer), wantErr: errs.ErrUnauthenticated},
+		{name: "missing_id", token: signTestToken(t, testJWTSecret, noID), wantErr: errs.ErrUnauthenticated},
+		{name: "oidc_state_rejected", token: signTestToken(t, testJWTSecret, claims(tokenTypeOIDCState, alice.ID, now.Add(time.Hour))), wantErr: errs.ErrUnauthenticated},
+		{name: "unknown_user", token: signTestToken(t, testJWTSecret, claims(tokenTypeAccess, "deleted-user", now.Add(time.Hour))), wantErr: errs.ErrUnauthenticated},
+		{name: "garbage", token: "not-a-jwt", wantErr: errs.ErrUnauthenticated},
+		{name: "none_algorithm", token: unsignedToken(t, claims(tokenTypeAccess, alice.ID, now.Add(time.Hour))), wantErr: errs.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := env.auth.Authenticate(ctx, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", principal.Username)
+			assert.Equal(t, []string{auth.RepositoryPermission("*", "*", auth.ActionRead)}, principal.Permissions)
+		})
+	}
+
+	t.Run("locked_user_loses_access", func(t *testing.T) {
+		locked := model.UserStatusLocked
+		_, err := env.users.Update(ctx, "alice", &dto.UpdateUserRequest{Status: &locked})
+		require.NoError(t, err)
+		_, err = env.auth.Authenticate(ctx, tokens.AccessToken)
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+		_, err = env.auth.Refresh(ctx, &dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+}
+
+// unsignedToken 使用 none 算法的令牌
+func unsignedToken(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestAuthService_RefreshAndLogout(t *testing.T) {
+	env := newAuthEnv(t, nil)
+	ctx := context.Background()
+	tokens := env.login(t, initialAdminUsername, "admin-password")
+
+	refreshed, err := env.auth.Refresh(ctx, &dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.AccessToken, refreshed.AccessToken)
+
+	_, err = env.auth.Refresh(ctx, &dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated, "refresh tokens are single use")
+	_, err = env.auth.Refresh(ctx, &dto.RefreshRequest{RefreshToken: refreshed.AccessToken})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated, "access tokens cannot refresh")
+
+	principal, err := env.auth.Authenticate(ctx, refreshed.AccessToken)
+	require.NoError(t, err)
+	require.NoError(t, env.auth.Logout(ctx, principal, &dto.LogoutRequest{RefreshToken: refreshed.RefreshToken}))
+
+	_, err = env.auth.Authenticate(ctx, refreshed.AccessToken)
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	_, err = env.auth.Refresh(ctx, &dto.RefreshRequest{RefreshToken: refreshed.RefreshToken})
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+
+	t.Run("rejected_credentials", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			principal *auth.Principal
+			wantErr   error
+		}{
+			{name: "nil", principal: nil, wantErr: errs.ErrUnauthenticated},
+			{name: "anonymous", principal: &auth.Principal{Anonymous: true}, wantErr: errs.ErrUnauthenticated},
+			{name: "access_token", principal: &auth.Principal{TokenID: "t", Credential: auth.CredentialAccessToken}, wantErr: errs.ErrInvalidArgument},
+			{name: "oidc", principal: &auth.Principal{TokenID: "t", Credential: auth.CredentialOIDC}, wantErr: errs.ErrInvalidArgument},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.ErrorIs(t, env.auth.Logout(ctx, tt.principal, &dto.LogoutRequest{}), tt.wantErr)
+			})
+		}
+	})
+
+	t.Run("other_users_refresh_token_is_kept", func(t *testing.T) {
+		createUser(t, env.users, "alice")
+		alice := env.login(t, "alice", testPassword)
+		admin := env.login(t, initialAdminUsername, "admin-password")
+		principal, err := env.auth.Authenticate(ctx, admin.AccessToken)
+		require.NoError(t, err)
+		require.NoError(t, env.auth.Logout(ctx, principal, &dto.LogoutRequest{RefreshToken: alice.RefreshToken}))
+		_, err = env.auth.Refresh(ctx, &dto.RefreshRequest{RefreshToken: alice.RefreshToken})
+		assert.NoError(t, err)
+	})
+}
+
+// oidcLogin 开始 OIDC 登录，签发者为返回的授权地址登记授权码，claims 可修改 ID 令牌声明
+func oidcLogin(t *testing.T, env *authEnv, issuer *fakeIssuer, redirect string, claims func(claims jwt.MapClaims)) (*dto.OIDCAuthorization, *dto.OIDCCallbackQuery) {
+	t.Helper()
+	authorization, err := env.auth.OIDCAuthorize(context.Background(), redirect)
+	require.NoError(t, err)
+	authURL, err := url.Parse(authorization.URL)
+	require.NoError(t, err)
+	query := authURL.Query()
+	idClaims := issuer.idClaims(query.Get("nonce"))
+	if claims != nil {
+		claims(idClaims)
+	}
+	issuer.grant("code-1", query.Get("code_challenge"), issuer.sign(t, "k1", idClaims))
+	return authorization, &dto.OIDCCallbackQuery{Code: "code-1", State: query.Get("state")}
+}
+
+func TestAuthService_OIDCLogin(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	env := newAuthEnv(t, func(cfg *config.Config) { cfg.Security.OIDC = testOIDCConfig(issuer) })
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		authorization, query := oidcLogin(t, env, issuer, "/ui/repositories", nil)
+		assert.Equal(t, int64(oidcStateExpire/time.Second), authorization.ExpiresIn)
+		assert.NotContains(t, authorization.URL, authorization.State, "the signed state stays in the cookie")
+
+		tokens, redirect, err := env.auth.OIDCCallback(ctx, query, authorization.State)
+		require.NoError(t, err)
+		assert.Equal(t, "/ui/repositories", redirect)
+		assert.Equal(t, "alice", tokens.User.Username)
+		assert.NotNil(t, tokens.User.LastLogin)
+
+		principal, err := env.auth.Authenticate(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", principal.Username)
+		assert.Equal(t, auth.CredentialJWT, principal.Credential)
+		assert.ElementsMatch(t, []string{model.RoleViewer, model.RoleDeveloper}, principal.Roles)
+		user, err := env.userRepo.FindByUsername(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, model.UserRealmOIDC, user.Realm)
+	})
+
+	t.Run("nonce_bound_to_state", func(t *testing.T) {
+		authorization, query := oidcLogin(t, env, issuer, "", func(claims jwt.MapClaims) { claims["nonce"] = "nonce-of-another-login" })
+		_, _, err := env.auth.OIDCCallback(ctx, query, authorization.State)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+		assert.Contains(t, err.Error(), "nonce")
+	})
+
+	t.Run("verifier_bound_to_state", func(t *testing.T) {
+		// 另一次登录的登录状态携带另一个 PKCE 校验码，签发者拒绝换取令牌
+		first, query := oidcLogin(t, env, issuer, "", nil)
+		second, err := env.auth.OIDCAuthorize(ctx, "")
+		require.NoError(t, err)
+		assert.NotEqual(t, first.State, second.State)
+		_, _, err = env.auth.OIDCCallback(ctx, query, second.State)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+
+	now := time.Now()
+	expiredState := signTestToken(t, testJWTSecret, &oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Issuer: tokenIssuer, ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute))},
+		Type:             tokenTypeOIDCState,
+		State:            "state-1",
+	})
+	accessAsState := signTestToken(t, testJWTSecret, &oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Issuer: tokenIssuer, ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute))},
+		Type:             tokenTypeAccess,
+		State:            "state-1",
+	})
+	forgedState := signTestToken(t, "another-secret-0123456789abcdef", &oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Issuer: tokenIssuer, ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute))},
+		Type:             tokenTypeOIDCState,
+		State:            "state-1",
+	})
+	tests := []struct {
+		name    string
+		query   *dto.OIDCCallbackQuery
+		state   string
+		wantErr error
+		wantMsg string
+	}{
+		{name: "issuer_error", query: &dto.OIDCCallbackQuery{Error: "access_denied", ErrorDescription: "user cancelled"}, wantErr: errs.ErrUnauthenticated, wantMsg: "access_denied"},
+		{name: "missing_code", query: &dto.OIDCCallbackQuery{State: "state-1"}, wantErr: errs.ErrInvalidArgument},
+		{name: "missing_state", query: &dto.OIDCCallbackQuery{Code: "code-1"}, wantErr: errs.ErrInvalidArgument},
+		{name: "missing_cookie", query: &dto.OIDCCallbackQuery{Code: "code-1", State: "state-1"}, wantErr: errs.ErrUnauthenticated, wantMsg: "start the login again"},
+		{name: "expired_state", query: &dto.OIDCCallbackQuery{Code: "code-1", State: "state-1"}, state: expiredState, wantErr: errs.ErrUnauthenticated, wantMsg: "start the login again"},
+		{name: "wrong_token_type", query: &dto.OIDCCallbackQuery{Code: "code-1", State: "state-1"}, state: accessAsState, wantErr: errs.ErrUnauthenticated, wantMsg: "start the login again"},
+		{name: "forged_state", query: &dto.OIDCCallbackQuery{Code: "code-1", State: "state-1"}, state: forgedState, wantErr: errs.ErrUnauthenticated, wantMsg: "start the login again"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := env.auth.OIDCCallback(ctx, tt.query, tt.state)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+
+	t.Run("state_mismatch", func(t *testing.T) {
+		authorization, query := oidcLogin(t, env, issuer, "", nil)
+		query.State = "state-of-another-login"
+		_, _, err := env.auth.OIDCCallback(ctx, query, authorization.State)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+		assert.Contains(t, err.Error(), "does not match")
+	})
+
+	t.Run("open_redirect_rejected", func(t *testing.T) {
+		_, err := env.auth.OIDCAuthorize(ctx, "https://evil.example.com")
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		env := newAuthEnv(t, nil)
+		_, err := env.auth.OIDCAuthorize(ctx, "")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		_, _, err = env.auth.OIDCCallback(ctx, &dto.OIDCCallbackQuery{Code: "code-1", State: "state-1"}, "")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestAuthService_WorkloadToken(t *testing.T) {
+	const audience = "https://nexus.example.com"
+	issuer := newFakeIssuer(t)
+	env := newAuthEnv(t, func(cfg *config.Config) {
+		cfg.Security.OIDC.Workloads = []config.OIDCWorkload{
+			{Issuer: issuer.URL, Audience: audience, Subject: "repo:example/app:ref:refs/heads/*", User: "ci", Scopes: []string{"repo:write:libs-*"}},
+			{Issuer: issuer.URL, Audience: audience, Subject: "repo:example/app:environment:*", User: "deployer"},
+		}
+	})
+	ctx := context.Background()
+	createUser(t, env.users, "ci", model.RoleDeveloper)
+	createUser(t, env.users, "deployer", model.RoleDeveloper)
+
+	t.Run("scoped_workload", func(t *testing.T) {
+		claims := issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+		claims["jti"] = "run-42"
+		principal, err := env.auth.Authenticate(ctx, issuer.sign(t, "k1", claims))
+		require.NoError(t, err)
+		assert.Equal(t, "ci", principal.Username)
+		assert.Equal(t, auth.CredentialOIDC, principal.Credential)
+		assert.Equal(t, "run-42", principal.TokenID)
+		assert.Equal(t, []string{"repo:write:libs-*"}, principal.Scopes)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), principal.ExpiresAt, time.Minute)
+
+		err = env.auth.Logout(ctx, principal, &dto.LogoutRequest{})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument, "workload tokens expire on their own")
+	})
+
+	t.Run("unscoped_workload", func(t *testing.T) {
+		principal, err := env.auth.Authenticate(ctx, issuer.sign(t, "k1", issuer.claims(audience, "repo:example/app:environment:production")))
+		require.NoError(t, err)
+		assert.Equal(t, "deployer", principal.Username)
+		assert.Nil(t, principal.Scopes)
+	})
+
+	t.Run("no_matching_workload", func(t *testing.T) {
+		_, err := env.auth.Authenticate(ctx, issuer.sign(t, "k1", issuer.claims(audience, "repo:example/fork:ref:refs/heads/main")))
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+
+	t.Run("untrusted_issuer_falls_back_to_local_tokens", func(t *testing.T) {
+		claims := issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+		claims["iss"] = "https://evil.example.com"
+		_, err := env.auth.Authenticate(ctx, issuer.sign(t, "k1", claims))
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+}
+
+// checkedTogether 所有请求都查询过注销记录后才返回，使并发刷新都在注销前通过校验
+type checkedTogether struct {
+	repository.RevokedTokenRepository
+	checked *sync.WaitGroup
+}
+
+func (r *checkedTogether) Exists(ctx context.Context, id string) (bool, error) {
+	revoked, err := r.RevokedTokenRepository.Exists(ctx, id)
+	r.checked.Done()
+	r.checked.Wait()
+	return revoked, err
+}
+
+func TestAuthService_ConcurrentRefresh(t *testing.T) {
+	const refreshes = 8
+	env := newAuthEnv(t, nil)
+	tokens := env.login(t, initialAdminUsername, "admin-password")
+	checked := &sync.WaitGroup{}
+	checked.Add(refreshes)
+	env.auth.revoked = &checkedTogether{RevokedTokenRepository: env.auth.revoked, checked: checked}
+
+	var wg sync.WaitGroup
+	results := make([]error, refreshes)
+	for i := 0; i < refreshes; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, results[i] = env.auth.Refresh(context.Background(), &dto.RefreshRequest{RefreshToken: tokens.RefreshToken})
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated, "a refresh token used concurrently is rejected, not a server error")
+	}
+	assert.Equal(t, 1, succeeded, "only one refresh may rotate the token")
+}
+
+func TestAuthService_AnonymousRead(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		env := newAuthEnv(t, func(cfg *config.Config) { cfg.Security.AnonymousRead = enabled })
+		assert.Equal(t, enabled, env.auth.AnonymousRead())
+	}
+}
+
+func TestIsLocalRedirect(t *testing.T) {
+	tests := []struct {
+		redirect string
+		want     bool
+	}{
+		{"/ui/repositories", true},
+		{"/", true},
+		{"//evil.example.com", false},
+		{"https://evil.example.com", false},
+		{"/\\evil.example.com", false},
+		{"/\t/evil.com", false},
+		{"/\x00//evil.com", false},
+		{"/\x7f/evil.com", false},
+		{"/%2F/evil.com", false},
+		{"/ui?next=%2Fhome#top", true},
+		{"ui", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.redirect, func(t *testing.T) {
+			assert.Equal(t, tt.want, isLocalRedirect(tt.redirect))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// AuthorizerImpl 授权服务实现，匿名调用者使用内置 anonymous 角色的权限
+type AuthorizerImpl struct {
+	logger       *slog.Logger
+	repositories repository.RepositoryRepository
+	roles        repository.RoleRepository
+}
+
+// NewAuthorizer 创建新的授权服务实现
+func NewAuthorizer(logger *slog.Logger, repositories repository.RepositoryRepository, roles repository.RoleRepository) *AuthorizerImpl {
+	return &AuthorizerImpl{
+		logger:       logger,
+		repositories: repositories,
+		roles:        roles,
+	}
+}
+
+// Authorize 校验系统权限，个人访问令牌需要 admin 范围
+func (a *AuthorizerImpl) Authorize(ctx context.Context, permission string) error {
+	principal, err := a.principal(ctx)
+	if principal == nil {
+		return err
+	}
+	if !principal.AllowsScope(auth.AccessAdmin, "") {
+		return errs.Forbidden("token scope does not allow admin access")
+	}
+	if !principal.HasPermission(permission) {
+		return denied(principal, permission)
+	}
+	return nil
+}
+
+// AuthorizeRepository 校验对仓库的操作权限
+func (a *AuthorizerImpl) AuthorizeRepository(ctx context.Context, repoIDOrName, action string) error {
+	principal, err := a.principal(ctx)
+	if principal == nil {
+		return err
+	}
+	repo, err := lookupRepository(ctx, a.repositories, repoIDOrName)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	return authorizeRepository(principal, repo, action)
+}
+
+// AuthorizeScope 只校验个人访问令牌范围
+func (a *AuthorizerImpl) AuthorizeScope(ctx context.Context, access string) error {
+	principal, err := a.principal(ctx)
+	if principal == nil {
+		return err
+	}
+	if !principal.AllowsScope(access, "") {
+		return errs.Forbidden("token scope does not allow %s access", access)
+	}
+	return nil
+}
+
+// RepositoryFilter 返回判断调用者能否对仓库执行操作的函数，用于过滤仓库列表与搜索范围
+func (a *AuthorizerImpl) RepositoryFilter(ctx context.Context, action string) (func(repo *model.Repository) bool, error) {
+	principal, err := a.principal(ctx)
+	if principal == nil {
+		if err != nil {
+			return nil, err
+		}
+		return func(*model.Repository) bool { return true }, nil
+	}
+	return func(repo *model.Repository) bool {
+		return authorizeRepository(principal, repo, action) == nil
+	}, nil
+}
+
+// principal 读取 context 中的调用者，匿名调用者附带 anonymous 角色的权限
+func (a *AuthorizerImpl) principal(ctx context.Context) (*auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !principal.Anonymous {
+		return principal, nil
+	}
+	anonymous := *principal
+	anonymous.Permissions = nil
+	role, err := a.roles.FindByName(ctx, model.RoleAnonymous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if role != nil {
+		anonymous.Permissions = role.Permissions
+	}
+	return &anonymous, nil
+}
+
+// authorizeRepository 校验令牌范围与仓库权限
+func authorizeRepository(principal *auth.Principal, repo *model.Repository, action string) error {
+	access := auth.AccessRead
+	switch action {
+	case auth.ActionWrite, auth.ActionDelete:
+		access = auth.AccessWrite
+	case auth.ActionAdmin:
+		access = auth.AccessAdmin
+	}
+	if !principal.AllowsScope(access, repo.Name) {
+		return errs.Forbidden("token scope does not allow %s access to repository %q", access, repo.Name)
+	}
+	permission := auth.RepositoryPermission(repo.Format, repo.Name, action)
+	if !principal.HasPermission(permission) {
+		return denied(principal, permission)
+	}
+	return nil
+}
+
+// denied 权限不足时的错误，匿名调用者返回 401 以提示客户端提供凭据
+func denied(principal *auth.Principal, permission string) error {
+	if principal.Anonymous {
+		return errs.Unauthenticated("authentication required")
+	}
+	return errs.Forbidden("permission %q is required", permission)
+}
@@ -0,0 +1,145 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// withToken 以个人访问令牌调用者执行，scopes 为令牌范围
+func withToken(scopes []string, permissions ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		UserID:      "ci",
+		Username:    "ci",
+		Permissions: permissions,
+		Credential:  auth.CredentialAccessToken,
+		Scopes:      scopes,
+	})
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	env := newTestEnv(t)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "internal_call", ctx: context.Background()},
+		{name: "granted", ctx: asUser("alice", auth.PermissionUsersManage)},
+		{name: "granted_by_all", ctx: asUser("admin", auth.PermissionAll)},
+		{name: "missing", ctx: asUser("alice", auth.PermissionTasksManage), wantErr: errs.ErrForbidden},
+		{name: "token_admin_scope", ctx: withToken([]string{auth.ScopeAdmin}, auth.PermissionAll)},
+		{name: "token_repo_scope", ctx: withToken([]string{auth.ScopeRepoWrite}, auth.PermissionAll), wantErr: errs.ErrForbidden},
+		{name: "anonymous", ctx: auth.WithPrincipal(context.Background(), auth.AnonymousPrincipal), wantErr: errs.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := env.authorizer.Authorize(tt.ctx, auth.PermissionUsersManage)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizer_AuthorizeRepository(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "team-release", "maven")
+	env.hosted(t, "libs-release", "maven")
+	require.NoError(t, env.roleRepo.Create(context.Background(), &model.Role{ID: "anonymous", Name: model.RoleAnonymous,
+		Permissions: []string{auth.RepositoryPermission("maven", "libs-*", auth.ActionRead)}}))
+	anonymous := auth.WithPrincipal(context.Background(), auth.AnonymousPrincipal)
+	team := asUser("team", auth.RepositoryPermission("maven", "team-*", auth.ActionWrite))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		repo    string
+		action  string
+		wantErr error
+	}{
+		{name: "write", ctx: team, repo: "team-release", action: auth.ActionWrite},
+		{name: "write_implies_read", ctx: team, repo: "team-release", action: auth.ActionRead},
+		{name: "no_delete", ctx: team, repo: "team-release", action: auth.ActionDelete, wantErr: errs.ErrForbidden},
+		{name: "other_repository", ctx: team, repo: "libs-release", action: auth.ActionRead, wantErr: errs.ErrForbidden},
+		{name: "missing_repository_left_to_handler", ctx: team, repo: "missing", action: auth.ActionWrite},
+		{name: "anonymous_role", ctx: anonymous, repo: "libs-release", action: auth.ActionRead},
+		{name: "anonymous_denied_asks_for_credentials", ctx: anonymous, repo: "team-release", action: auth.ActionRead, wantErr: errs.ErrUnauthenticated},
+		{name: "token_scope_allows", ctx: withToken([]string{"repo:write:team-*"}, auth.PermissionAll), repo: "team-release", action: auth.ActionDelete},
+		{name: "token_scope_other_repository", ctx: withToken([]string{"repo:write:team-*"}, auth.PermissionAll), repo: "libs-release", action: auth.ActionRead, wantErr: errs.ErrForbidden},
+		{name: "token_scope_limits_roles", ctx: withToken([]string{auth.ScopeRepoRead}, auth.PermissionAll), repo: "team-release", action: auth.ActionWrite, wantErr: errs.ErrForbidden},
+		{name: "token_scope_cannot_exceed_roles", ctx: withToken([]string{auth.ScopeAdmin}), repo: "team-release", action: auth.ActionRead, wantErr: errs.ErrForbidden},
+		{name: "admin_needs_admin_scope", ctx: withToken([]string{auth.ScopeRepoWrite}, auth.PermissionAll), repo: "team-release", action: auth.ActionAdmin, wantErr: errs.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := env.authorizer.AuthorizeRepository(tt.ctx, tt.repo, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("anonymous_without_role", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.hosted(t, "libs-release", "maven")
+		err := env.authorizer.AuthorizeRepository(auth.WithPrincipal(context.Background(), auth.AnonymousPrincipal), "libs-release", auth.ActionRead)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+}
+
+func TestAuthorizer_AuthorizeScope(t *testing.T) {
+	env := newTestEnv(t)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		access  string
+		wantErr bool
+	}{
+		{name: "session", ctx: asUser("alice"), access: auth.AccessAdmin},
+		{name: "token_any", ctx: withToken([]string{auth.ScopeRepoRead}), access: auth.AccessAny},
+		{name: "token_read", ctx: withToken([]string{"repo:read:libs"}), access: auth.AccessRead},
+		{name: "token_write_denied", ctx: withToken([]string{auth.ScopeRepoRead}), access: auth.AccessWrite, wantErr: true},
+		{name: "token_admin_denied", ctx: withToken([]string{auth.ScopeRepoWrite}), access: auth.AccessAdmin, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := env.authorizer.AuthorizeScope(tt.ctx, tt.access)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errs.ErrForbidden)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizer_RepositoryFilter(t *testing.T) {
+	env := newTestEnv(t)
+	team := &model.Repository{Name: "team-release", Format: "maven"}
+	other := &model.Repository{Name: "libs-release", Format: "maven"}
+
+	readable, err := env.authorizer.RepositoryFilter(context.Background(), auth.ActionRead)
+	require.NoError(t, err)
+	assert.True(t, readable(other), "internal calls are not filtered")
+
+	readable, err = env.authorizer.RepositoryFilter(asUser("team", auth.RepositoryPermission("*", "team-*", auth.ActionRead)), auth.ActionRead)
+	require.NoError(t, err)
+	assert.True(t, readable(team))
+	assert.False(t, readable(other))
+
+	readable, err = env.authorizer.RepositoryFilter(withToken([]string{"repo:read:libs-*"}, auth.PermissionAll), auth.ActionRead)
+	require.NoError(t, err)
+	assert.False(t, readable(team), "token scope narrows role permissions")
+	assert.True(t, readable(other))
+}
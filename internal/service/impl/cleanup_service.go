@@ -0,0 +1,427 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/internal/version"
+)
+
+// cleanupPolicyNamePattern 清理策略名称格式
+var cleanupPolicyNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// CleanupServiceImpl 清理策略服务实现
+type CleanupServiceImpl struct {
+	logger    *slog.Logger
+	policies  repository.CleanupPolicyRepository
+	artifacts *ArtifactServiceImpl
+	audit     *AuditRecorder
+
+	// 同一时间只执行一次清理，定期任务与手动执行互斥
+	mu sync.Mutex
+}
+
+// NewCleanupService 创建清理策略服务，定期执行由后台任务 cleanup 负责
+func NewCleanupService(
+	logger *slog.Logger,
+	policies repository.CleanupPolicyRepository,
+	artifacts *ArtifactServiceImpl,
+	audit *AuditRecorder,
+) *CleanupServiceImpl {
+	return &CleanupServiceImpl{
+		logger:    logger,
+		policies:  policies,
+		artifacts: artifacts,
+		audit:     audit,
+	}
+}
+
+// CreatePolicy 创建清理策略
+func (s *CleanupServiceImpl) CreatePolicy(ctx context.Context, req *dto.CleanupPolicyRequest) (*model.CleanupPolicy, error) {
+	if !cleanupPolicyNamePattern.MatchString(req.Name) {
+		return nil, errs.InvalidArgument("invalid cleanup policy name %q", req.Name)
+	}
+	existing, err := s.policies.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cleanup policy: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("cleanup policy %q already exists", req.Name)
+	}
+
+	policy := &model.CleanupPolicy{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		Format:      req.Format,
+		Criteria:    req.Criteria,
+	}
+	if err := s.validate(policy); err != nil {
+		return nil, err
+	}
+	if err := s.policies.Create(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to create cleanup policy: %w", err)
+	}
+	s.logger.Info("Cleanup policy created", "name", policy.Name)
+	return policy, nil
+}
+
+// GetPolicy 获取清理策略
+func (s *CleanupServiceImpl) GetPolicy(ctx context.Context, name string) (*model.CleanupPolicy, error) {
+	policy, err := s.policies.FindByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cleanup policy: %w", err)
+	}
+	if policy == nil {
+		return nil, errs.NotFound("cleanup policy %q not found", name)
+	}
+	return policy, nil
+}
+
+// ListPolicies 列出清理策略
+func (s *CleanupServiceImpl) ListPolicies(ctx context.Context) ([]*model.CleanupPolicy, error) {
+	policies, err := s.policies.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cleanup policies: %w", err)
+	}
+	return policies, nil
+}
+
+// UpdatePolicy 更新清理策略，限定格式时已附加的仓库格式必须一致
+func (s *CleanupServiceImpl) UpdatePolicy(ctx context.Context, name string, req *dto.CleanupPolicyRequest) (*model.CleanupPolicy, error) {
+	policy, err := s.GetPolicy(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	policy.Description = req.Description
+	policy.Format = req.Format
+	policy.Criteria = req.Criteria
+	if err := s.validate(policy); err != nil {
+		return nil, err
+	}
+	if policy.Format != "" {
+		repos, err := s.attachedRepositories(ctx, policy.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			if repo.Format != policy.Format {
+				return nil, errs.InvalidArgument("cleanup policy %q is attached to %s repository %q", policy.Name, repo.Format, repo.Name)
+			}
+		}
+	}
+
+	if err := s.policies.Update(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to update cleanup policy: %w", err)
+	}
+	s.logger.Info("Cleanup policy updated", "name", policy.Name)
+	return policy, nil
+}
+
+// DeletePolicy 删除清理策略，仍被仓库使用时返回冲突
+func (s *CleanupServiceImpl) DeletePolicy(ctx context.Context, name string) error {
+	policy, err := s.GetPolicy(ctx, name)
+	if err != nil {
+		return err
+	}
+	repos, err := s.attachedRepositories(ctx, policy.Name)
+	if err != nil {
+		return err
+	}
+	if len(repos) > 0 {
+		return errs.Conflict("cleanup policy %q is used by repository %q", policy.Name, repos[0].Name)
+	}
+	if err := s.policies.Delete(ctx, policy.ID); err != nil {
+		return fmt.Errorf("failed to delete cleanup policy: %w", err)
+	}
+	s.logger.Info("Cleanup policy deleted", "name", policy.Name)
+	return nil
+}
+
+// Preview 列出策略在各仓库中将删除的制品
+func (s *CleanupServiceImpl) Preview(ctx context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error) {
+	policy, repos, err := s.resolve(ctx, name, query)
+	if err != nil {
+		return nil, err
+	}
+	report := &dto.CleanupReport{DryRun: true, Results: make([]*dto.CleanupResult, 0, len(repos))}
+	for _, repo := range repos {
+		result, err := s.evaluate(ctx, policy, repo)
+		if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// Run 立即执行清理策略
+func (s *CleanupServiceImpl) Run(ctx context.Context, name string, query *dto.CleanupQuery) (*dto.CleanupReport, error) {
+	policy, repos, err := s.resolve(ctx, name, query)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &dto.CleanupReport{Results: make([]*dto.CleanupResult, 0, len(repos))}
+	for _, repo := range repos {
+		result, err := s.execute(ctx, policy, repo)
+		if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// RunAll 依次执行每个仓库附加的清理策略
+func (s *CleanupServiceImpl) RunAll(ctx context.Context) (*dto.CleanupReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repos, err := s.artifacts.repositories.List(ctx, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	report := &dto.CleanupReport{Results: make([]*dto.CleanupResult, 0)}
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(repo.CleanupPolicies) == 0 || repo.Type == model.RepositoryTypeGroup {
+			continue
+		}
+		policies, err := s.policies.FindByNames(ctx, repo.CleanupPolicies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find cleanup policies: %w", err)
+		}
+		for _, policy := range policies {
+			result, err := s.execute(ctx, policy, repo)
+			if err != nil {
+				return nil, err
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+	return report, nil
+}
+
+// validate 校验清理策略：至少一个条件，数值不为负，正则表达式与格式有效
+func (s *CleanupServiceImpl) validate(policy *model.CleanupPolicy) error {
+	c := policy.Criteria
+	if c.OlderThanDays < 0 || c.NotDownloadedDays < 0 || c.KeepLastVersions < 0 {
+		return errs.InvalidArgument("cleanup criteria must not be negative")
+	}
+	if c.OlderThanDays == 0 && c.NotDownloadedDays == 0 && c.KeepLastVersions == 0 && c.PathRegex == "" && !c.PrereleaseOnly {
+		return errs.InvalidArgument("cleanup policy requires at least one criterion")
+	}
+	if _, err := compileCleanupPattern(c.PathRegex); err != nil {
+		return errs.InvalidArgument("invalid path_regex %q: %s", c.PathRegex, err.Error())
+	}
+	if policy.Format != "" {
+		if _, err := s.artifacts.plugins.GetFormatPlugin(policy.Format); err != nil {
+			return errs.InvalidArgument("unsupported format: %s", policy.Format)
+		}
+	}
+	return nil
+}
+
+// resolve 查找策略及其作用的仓库：指定仓库时只作用于该仓库，否则作用于附加了该策略的所有仓库
+func (s *CleanupServiceImpl) resolve(ctx context.Context, name string, query *dto.CleanupQuery) (*model.CleanupPolicy, []*model.Repository, error) {
+	policy, err := s.GetPolicy(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if query.Repository == "" {
+		repos, err := s.attachedRepositories(ctx, policy.Name)
+		return policy, repos, err
+	}
+
+	repo, err := lookupRepository(ctx, s.artifacts.repositories, query.Repository)
+	if err != nil {
+		return nil, nil, err
+	}
+	if repo.Type == model.RepositoryTypeGroup {
+		return nil, nil, errs.InvalidArgument("repository %q is a group repository, clean up its members", repo.Name)
+	}
+	if policy.Format != "" && policy.Format != repo.Format {
+		return nil, nil, errs.InvalidArgument("cleanup policy %q applies to %s repositories", policy.Name, policy.Format)
+	}
+	return policy, []*model.Repository{repo}, nil
+}
+
+// attachedRepositories 列出附加了策略的仓库
+func (s *CleanupServiceImpl) attachedRepositories(ctx context.Context, name string) ([]*model.Repository, error) {
+	repos, err := s.artifacts.repositories.List(ctx, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	attached := make([]*model.Repository, 0)
+	for _, repo := range repos {
+		for _, policy := range repo.CleanupPolicies {
+			if policy == name {
+				attached = append(attached, repo)
+				break
+			}
+		}
+	}
+	return attached, nil
+}
+
+// evaluate 找出仓库中满足策略全部条件的版本化制品，连同其校验和、签名等附属文件
+func (s *CleanupServiceImpl) evaluate(ctx context.Context, policy *model.CleanupPolicy, repo *model.Repository) (*dto.CleanupResult, error) {
+	artifacts, err := s.artifacts.repository.List(ctx, &model.ArtifactFilter{
+		RepositoryIDs: []string{repo.ID},
+		Sort:          model.ArtifactSortCreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	c := policy.Criteria
+	pattern, err := compileCleanupPattern(c.PathRegex)
+	if err != nil {
+		return nil, errs.InvalidArgument("invalid path_regex %q: %s", c.PathRegex, err.Error())
+	}
+	scheme := version.ForFormat(repo.Format)
+	kept := latestVersions(repo, artifacts, scheme, c.KeepLastVersions)
+	now := time.Now()
+
+	selected := make(map[string]bool)
+	for _, artifact := range artifacts {
+		if artifact.Version == "" || isSidecarPath(artifact.Path) {
+			continue
+		}
+		if kept[componentKey(repo, artifact)+"\x00"+artifact.Version] {
+			continue
+		}
+		if c.OlderThanDays > 0 && artifact.CreatedAt.After(now.AddDate(0, 0, -c.OlderThanDays)) {
+			continue
+		}
+		if c.NotDownloadedDays > 0 {
+			last := artifact.CreatedAt
+			if artifact.LastDownloaded != nil {
+				last = *artifact.LastDownloaded
+			}
+			if last.After(now.AddDate(0, 0, -c.NotDownloadedDays)) {
+				continue
+			}
+		}
+		if pattern != nil && !pattern.MatchString(artifact.Path) {
+			continue
+		}
+		if c.PrereleaseOnly && !scheme.IsPrerelease(artifact.Version) {
+			continue
+		}
+		selected[artifact.Path] = true
+	}
+
+	result := &dto.CleanupResult{Policy: policy.Name, Repository: repo.Name, Artifacts: make([]*model.Artifact, 0)}
+	for _, artifact := range artifacts {
+		base := artifact.Path
+		for isSidecarPath(base) {
+			base = strings.TrimSuffix(base, path.Ext(base))
+		}
+		if selected[base] {
+			result.Artifacts = append(result.Artifacts, artifact)
+			result.Size += artifact.Size
+		}
+	}
+	result.Count = len(result.Artifacts)
+	return result, nil
+}
+
+// execute 通过制品服务删除策略选中的制品，单个制品删除失败只记录日志，最后记录审计日志
+func (s *CleanupServiceImpl) execute(ctx context.Context, policy *model.CleanupPolicy, repo *model.Repository) (*dto.CleanupResult, error) {
+	result, err := s.evaluate(ctx, policy, repo)
+	if err != nil {
+		return nil, err
+	}
+	if result.Count == 0 {
+		return result, nil
+	}
+
+	deleted := make([]string, 0, result.Count)
+	for _, artifact := range result.Artifacts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		err := s.artifacts.Delete(ctx, repo.ID, artifact.Path)
+		switch {
+		case err == nil, errors.Is(err, errs.ErrNotFound):
+			result.Deleted++
+			deleted = append(deleted, artifact.Path)
+		default:
+			result.Failed++
+			s.logger.Warn("Failed to delete artifact during cleanup", "policy", policy.Name, "repository", repo.Name, "path", artifact.Path, "error", err)
+		}
+	}
+
+	s.audit.Record(ctx, auditActionCleanup, "repository:"+repo.Name, map[string]interface{}{
+		"policy": policy.Name,
+		"paths":  deleted,
+		"size":   result.Size,
+		"failed": result.Failed,
+	})
+	s.logger.Info("Cleanup policy executed", "policy", policy.Name, "repository", repo.Name, "deleted", result.Deleted, "failed", result.Failed)
+	return result, nil
+}
+
+// latestVersions 每个组件按版本从高到低保留前 n 个版本，返回 组件\x00版本 集合
+func latestVersions(repo *model.Repository, artifacts []*model.Artifact, scheme *version.Scheme, n int) map[string]bool {
+	kept := make(map[string]bool)
+	if n <= 0 {
+		return kept
+	}
+	seen := make(map[string]bool)
+	versions := make(map[string][]string)
+	for _, artifact := range artifacts {
+		if artifact.Version == "" {
+			continue
+		}
+		key := componentKey(repo, artifact)
+		if !seen[key+"\x00"+artifact.Version] {
+			seen[key+"\x00"+artifact.Version] = true
+			versions[key] = append(versions[key], artifact.Version)
+		}
+	}
+
+	for key, list := range versions {
+		sort.Slice(list, func(i, j int) bool { return scheme.Compare(list[i], list[j]) > 0 })
+		for i := 0; i < n && i < len(list); i++ {
+			kept[key+"\x00"+list[i]] = true
+		}
+	}
+	return kept
+}
+
+// componentKey 制品所属组件：Maven 为 groupId/artifactId 目录，其他格式为制品名称
+func componentKey(repo *model.Repository, artifact *model.Artifact) string {
+	if repo.Format == "maven" {
+		return path.Dir(path.Dir(artifact.Path))
+	}
+	return artifact.Name
+}
+
+// compileCleanupPattern 编译完整匹配路径的正则表达式，空表达式返回 nil
+func compileCleanupPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
@@ -0,0 +1,318 @@
+package impl
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// newCleanupEnv 创建 maven 宿主仓库 libs-release 及清理策略服务
+func newCleanupEnv(t *testing.T) (*testEnv, *CleanupServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	env.hosted(t, "libs-release", "maven")
+	return env, NewCleanupService(env.logger, env.policyRepo, env.artifacts, env.audit)
+}
+
+// backdate 将制品的创建时间与最近下载时间调整到指定天数之前，downloaded 为负时清空下载时间
+func backdate(t *testing.T, env *testEnv, p string, created, downloaded int) {
+	t.Helper()
+	updates := map[string]interface{}{"created_at": time.Now().AddDate(0, 0, -created), "last_downloaded": nil}
+	if downloaded >= 0 {
+		updates["last_downloaded"] = time.Now().AddDate(0, 0, -downloaded)
+	}
+	require.NoError(t, env.db.Model(&model.Artifact{}).Where("path = ?", p).Updates(updates).Error)
+}
+
+// cleanupPaths 返回清理结果中的制品路径（已排序）
+func cleanupPaths(result *dto.CleanupResult) []string {
+	paths := make([]string, 0, len(result.Artifacts))
+	for _, artifact := range result.Artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestCleanupService_PolicyCRUD(t *testing.T) {
+	env, cleanup := newCleanupEnv(t)
+	ctx := context.Background()
+
+	policy, err := cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "old-snapshots", Format: "maven",
+		Criteria: model.CleanupCriteria{OlderThanDays: 30, PrereleaseOnly: true}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, policy.ID)
+
+	got, err := cleanup.GetPolicy(ctx, "old-snapshots")
+	require.NoError(t, err)
+	assert.Equal(t, 30, got.Criteria.OlderThanDays)
+
+	list, err := cleanup.ListPolicies(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "old-snapshots", Criteria: model.CleanupCriteria{OlderThanDays: 1}})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("get_missing", func(t *testing.T) {
+		_, err := cleanup.GetPolicy(ctx, "missing")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "npm-hosted", Type: model.RepositoryTypeHosted, Format: "npm"})
+	repo, err := env.repos.FindByName(ctx, "libs-release")
+	require.NoError(t, err)
+	_, err = env.repositories.Update(ctx, repo.ID, &dto.UpdateRepositoryRequest{CleanupPolicies: []string{"old-snapshots"}})
+	require.NoError(t, err)
+
+	t.Run("update_format_must_match_attached", func(t *testing.T) {
+		_, err := cleanup.UpdatePolicy(ctx, "old-snapshots", &dto.CleanupPolicyRequest{Format: "npm", Criteria: model.CleanupCriteria{OlderThanDays: 7}})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updated, err := cleanup.UpdatePolicy(ctx, "old-snapshots", &dto.CleanupPolicyRequest{Description: "weekly", Criteria: model.CleanupCriteria{OlderThanDays: 7}})
+		require.NoError(t, err)
+		assert.Equal(t, "weekly", updated.Description)
+		assert.Equal(t, 7, updated.Criteria.OlderThanDays)
+		assert.Empty(t, updated.Format)
+	})
+
+	t.Run("delete_attached", func(t *testing.T) {
+		assert.ErrorIs(t, cleanup.DeletePolicy(ctx, "old-snapshots"), errs.ErrConflict)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := env.repositories.Update(ctx, repo.ID, &dto.UpdateRepositoryRequest{CleanupPolicies: []string{}})
+		require.NoError(t, err)
+		require.NoError(t, cleanup.DeletePolicy(ctx, "old-snapshots"))
+		_, err = cleanup.GetPolicy(ctx, "old-snapshots")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		assert.ErrorIs(t, cleanup.DeletePolicy(ctx, "old-snapshots"), errs.ErrNotFound)
+
+		// 删除后名称可以重新使用
+		_, err = cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "old-snapshots", Criteria: model.CleanupCriteria{OlderThanDays: 1}})
+		require.NoError(t, err)
+	})
+}
+
+func TestCleanupService_CreatePolicyValidation(t *testing.T) {
+	_, cleanup := newCleanupEnv(t)
+
+	tests := []struct {
+		name string
+		req  *dto.CleanupPolicyRequest
+	}{
+		{name: "bad_name", req: &dto.CleanupPolicyRequest{Name: "-bad", Criteria: model.CleanupCriteria{OlderThanDays: 1}}},
+		{name: "no_criteria", req: &dto.CleanupPolicyRequest{Name: "empty"}},
+		{name: "negative_age", req: &dto.CleanupPolicyRequest{Name: "neg", Criteria: model.CleanupCriteria{OlderThanDays: -1}}},
+		{name: "negative_keep", req: &dto.CleanupPolicyRequest{Name: "neg", Criteria: model.CleanupCriteria{KeepLastVersions: -2, PrereleaseOnly: true}}},
+		{name: "bad_regex", req: &dto.CleanupPolicyRequest{Name: "regex", Criteria: model.CleanupCriteria{PathRegex: "com/(x"}}},
+		{name: "unknown_format", req: &dto.CleanupPolicyRequest{Name: "fmt", Format: "cobol", Criteria: model.CleanupCriteria{OlderThanDays: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cleanup.CreatePolicy(context.Background(), tt.req)
+			assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+		})
+	}
+}
+
+func TestCleanupService_Preview(t *testing.T) {
+	paths := []string{
+		"com/x/lib/1.0/lib-1.0.jar",
+		"com/x/lib/1.1/lib-1.1.jar",
+		"com/x/lib/2.0-SNAPSHOT/lib-2.0-SNAPSHOT.jar",
+		"com/x/lib/2.0/lib-2.0.jar",
+		"com/x/app/1.0/app-1.0.jar",
+	}
+
+	tests := []struct {
+		name     string
+		criteria model.CleanupCriteria
+		want     []string
+	}{
+		{
+			name:     "keep_last_versions_per_component",
+			criteria: model.CleanupCriteria{KeepLastVersions: 2},
+			want:     []string{"com/x/lib/1.0/lib-1.0.jar", "com/x/lib/1.1/lib-1.1.jar", "com/x/lib/1.1/lib-1.1.jar.sha1"},
+		},
+		{
+			name:     "prerelease_only",
+			criteria: model.CleanupCriteria{PrereleaseOnly: true},
+			want:     []string{"com/x/lib/2.0-SNAPSHOT/lib-2.0-SNAPSHOT.jar"},
+		},
+		{
+			name:     "path_regex_is_anchored",
+			criteria: model.CleanupCriteria{PathRegex: "com/x/app/.*"},
+			want:     []string{"com/x/app/1.0/app-1.0.jar"},
+		},
+		{
+			name:     "path_regex_partial_match_selects_nothing",
+			criteria: model.CleanupCriteria{PathRegex: "app"},
+			want:     []string{},
+		},
+		{
+			name:     "older_than_days",
+			criteria: model.CleanupCriteria{OlderThanDays: 30},
+			want:     []string{"com/x/lib/1.0/lib-1.0.jar", "com/x/lib/1.1/lib-1.1.jar", "com/x/lib/1.1/lib-1.1.jar.sha1"},
+		},
+		{
+			name:     "not_downloaded_days_falls_back_to_created",
+			criteria: model.CleanupCriteria{NotDownloadedDays: 30},
+			want:     []string{"com/x/lib/1.1/lib-1.1.jar", "com/x/lib/1.1/lib-1.1.jar.sha1"},
+		},
+		{
+			name:     "criteria_combine",
+			criteria: model.CleanupCriteria{OlderThanDays: 30, KeepLastVersions: 3},
+			want:     []string{"com/x/lib/1.0/lib-1.0.jar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, cleanup := newCleanupEnv(t)
+			ctx := context.Background()
+			for _, p := range paths {
+				env.upload(t, "libs-release", p, []byte(p))
+			}
+			env.upload(t, "libs-release", "com/x/lib/1.1/lib-1.1.jar.sha1", []byte(sha1Hex([]byte("com/x/lib/1.1/lib-1.1.jar"))))
+			// 1.0 很久以前创建但最近被下载，1.1 很久以前创建且从未下载
+			backdate(t, env, "com/x/lib/1.0/lib-1.0.jar", 90, 1)
+			backdate(t, env, "com/x/lib/1.1/lib-1.1.jar", 60, -1)
+
+			_, err := cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "policy", Criteria: tt.criteria})
+			require.NoError(t, err)
+			report, err := cleanup.Preview(ctx, "policy", &dto.CleanupQuery{Repository: "libs-release"})
+			require.NoError(t, err)
+			assert.True(t, report.DryRun)
+			require.Len(t, report.Results, 1)
+			result := report.Results[0]
+			assert.Equal(t, tt.want, cleanupPaths(result))
+			assert.Equal(t, len(tt.want), result.Count)
+			assert.Zero(t, result.Deleted)
+
+			for _, p := range paths {
+				_, err := env.download("libs-release", p)
+				assert.NoError(t, err, "preview must not delete %s", p)
+			}
+		})
+	}
+}
+
+func TestCleanupService_Run(t *testing.T) {
+	env, cleanup := newCleanupEnv(t)
+	ctx := context.Background()
+	env.upload(t, "libs-release", "com/x/lib/1.0-SNAPSHOT/lib-1.0-SNAPSHOT.jar", []byte("snapshot"))
+	env.upload(t, "libs-release", "com/x/lib/1.0-SNAPSHOT/lib-1.0-SNAPSHOT.jar.sha1", []byte(sha1Hex([]byte("snapshot"))))
+	env.upload(t, "libs-release", "com/x/lib/1.0/lib-1.0.jar", []byte("release"))
+
+	_, err := cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "snapshots", Format: "maven",
+		Criteria: model.CleanupCriteria{PrereleaseOnly: true}})
+	require.NoError(t, err)
+
+	report, err := cleanup.Run(ctx, "snapshots", &dto.CleanupQuery{Repository: "libs-release"})
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, 2, report.Results[0].Deleted)
+	assert.Zero(t, report.Results[0].Failed)
+
+	_, err = env.download("libs-release", "com/x/lib/1.0-SNAPSHOT/lib-1.0-SNAPSHOT.jar")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	_, err = env.download("libs-release", "com/x/lib/1.0-SNAPSHOT/lib-1.0-SNAPSHOT.jar.sha1")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	_, err = env.download("libs-release", "com/x/lib/1.0/lib-1.0.jar")
+	assert.NoError(t, err)
+
+	page, err := env.audit.List(ctx, &dto.ListAuditQuery{Action: auditActionCleanup})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "repository:libs-release", page.Items[0].Resource)
+	assert.Contains(t, page.Items[0].Details, `"policy":"snapshots"`)
+
+	t.Run("nothing_left_skips_audit", func(t *testing.T) {
+		report, err := cleanup.Run(ctx, "snapshots", &dto.CleanupQuery{Repository: "libs-release"})
+		require.NoError(t, err)
+		assert.Zero(t, report.Results[0].Count)
+		page, err := env.audit.List(ctx, &dto.ListAuditQuery{Action: auditActionCleanup})
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+	})
+}
+
+func TestCleanupService_Resolve(t *testing.T) {
+	env, cleanup := newCleanupEnv(t)
+	ctx := context.Background()
+	env.hosted(t, "npm-hosted", "npm")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "maven-public", Type: model.RepositoryTypeGroup, Format: "maven",
+		Members: []string{"libs-release"}})
+	_, err := cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "maven-only", Format: "maven",
+		Criteria: model.CleanupCriteria{PrereleaseOnly: true}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		policy     string
+		repository string
+		wantErr    error
+	}{
+		{name: "missing_policy", policy: "missing", repository: "libs-release", wantErr: errs.ErrNotFound},
+		{name: "missing_repository", policy: "maven-only", repository: "missing", wantErr: errs.ErrNotFound},
+		{name: "group_repository", policy: "maven-only", repository: "maven-public", wantErr: errs.ErrInvalidArgument},
+		{name: "format_mismatch", policy: "maven-only", repository: "npm-hosted", wantErr: errs.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cleanup.Preview(ctx, tt.policy, &dto.CleanupQuery{Repository: tt.repository})
+			assert.ErrorIs(t, err, tt.wantErr)
+			_, err = cleanup.Run(ctx, tt.policy, &dto.CleanupQuery{Repository: tt.repository})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("defaults_to_attached_repositories", func(t *testing.T) {
+		report, err := cleanup.Preview(ctx, "maven-only", &dto.CleanupQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, report.Results)
+	})
+}
+
+func TestCleanupService_RunAll(t *testing.T) {
+	env, cleanup := newCleanupEnv(t)
+	ctx := context.Background()
+	_, err := cleanup.CreatePolicy(ctx, &dto.CleanupPolicyRequest{Name: "snapshots", Criteria: model.CleanupCriteria{PrereleaseOnly: true}})
+	require.NoError(t, err)
+
+	attached := env.createRepo(t, &dto.CreateRepositoryRequest{Name: "libs-snapshot", Type: model.RepositoryTypeHosted, Format: "maven",
+		CleanupPolicies: []string{"snapshots"}})
+	env.upload(t, "libs-snapshot", "com/x/lib/1.0-SNAPSHOT/lib-1.0-SNAPSHOT.jar", []byte("a"))
+	env.upload(t, "libs-release", "com/x/lib/2.0-SNAPSHOT/lib-2.0-SNAPSHOT.jar", []byte("b"))
+
+	report, err := cleanup.RunAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, attached.Name, report.Results[0].Repository)
+	assert.Equal(t, 1, report.Results[0].Deleted)
+
+	_, err = env.download("libs-snapshot", "com/x/lib/1.0-SNAPSHOT/lib-1.0-SNAPSHOT.jar")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	_, err = env.download("libs-release", "com/x/lib/2.0-SNAPSHOT/lib-2.0-SNAPSHOT.jar")
+	assert.NoError(t, err, "repositories without the policy are untouched")
+
+	t.Run("canceled", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := cleanup.RunAll(canceled)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
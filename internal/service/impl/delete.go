@@ -0,0 +1,131 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// Delete 删除宿主仓库中的制品或代理仓库中的缓存，并更新元数据文件与全文索引
+func (s *ArtifactServiceImpl) Delete(ctx context.Context, repoIDOrName, rawPath string) error {
+	repo, err := lookupRepository(ctx, s.repositories, repoIDOrName)
+	if err != nil {
+		return err
+	}
+	if repo.Type == model.RepositoryTypeGroup {
+		return errs.InvalidArgument("repository %q is a group repository, delete the artifact from its member", repo.Name)
+	}
+	p, err := normalizePath(rawPath)
+	if err != nil {
+		return err
+	}
+
+	artifact, err := s.repository.FindByPath(ctx, repo.ID, p)
+	if err != nil {
+		return fmt.Errorf("failed to find artifact: %w", err)
+	}
+	location := storagePath(repo, p)
+	exists, err := s.storage.Exists(ctx, location)
+	if err != nil {
+		return fmt.Errorf("failed to check artifact: %w", err)
+	}
+	if artifact == nil && !exists {
+		return errs.NotFound("artifact %q not found in repository %q", p, repo.Name)
+	}
+
+	if exists {
+		if err := s.storage.Delete(ctx, location); err != nil {
+			return fmt.Errorf("failed to delete artifact content: %w", err)
+		}
+	}
+	if artifact != nil {
+		if err := s.repository.Delete(ctx, artifact.ID); err != nil {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+		s.index.Remove(artifact.ID)
+		s.publish(ctx, pluginapi.ArtifactEventDeleted, repo, artifact)
+	}
+	s.logger.Info("Artifact deleted", "repository", repo.Name, "path", p)
+
+	if repo.Type == model.RepositoryTypeHosted {
+		s.refreshMetadataFor(ctx, repo, p)
+		if _, _, ok := signatureTarget(p); ok {
+			if formatPlugin, err := s.plugins.GetFormatPlugin(repo.Format); err == nil {
+				s.recordSignature(ctx, repo, formatPlugin, p, nil, nil)
+			}
+		}
+	}
+	return nil
+}
+
+// purge 删除仓库中的所有制品记录与存储内容（包括生成的元数据文件），并从全文索引中移除
+func (s *ArtifactServiceImpl) purge(ctx context.Context, repo *model.Repository) error {
+	artifacts, err := s.repository.ListByRepository(ctx, repo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	files, err := s.storage.List(ctx, storagePath(repo, ""))
+	if err != nil {
+		return fmt.Errorf("failed to list repository content: %w", err)
+	}
+	for _, file := range files {
+		if err := s.storage.Delete(ctx, file); err != nil {
+			return fmt.Errorf("failed to delete repository content: %w", err)
+		}
+	}
+	if err := s.repository.DeleteByRepository(ctx, repo.ID); err != nil {
+		return fmt.Errorf("failed to delete artifacts: %w", err)
+	}
+	s.index.RemoveRepository(repo.ID)
+	for _, artifact := range artifacts {
+		s.publish(ctx, pluginapi.ArtifactEventDeleted, repo, artifact)
+	}
+	return nil
+}
+
+// refreshMetadataFor 重新生成路径所属的格式元数据文件，失败只记录日志
+func (s *ArtifactServiceImpl) refreshMetadataFor(ctx context.Context, repo *model.Repository, p string) {
+	formatPlugin, err := s.plugins.GetFormatPlugin(repo.Format)
+	if err != nil {
+		return
+	}
+	locator, ok := formatPlugin.(pluginapi.ArtifactLocator)
+	if !ok {
+		return
+	}
+	if metadataPath, ok := locator.MetadataPath(p); ok {
+		if err := s.rebuildMetadata(ctx, repo, metadataPath); err != nil {
+			s.logger.Warn("Failed to rebuild metadata", "repository", repo.Name, "path", metadataPath, "error", err)
+		}
+	}
+}
+
+// refreshMetadataPaths 重新生成路径涉及的元数据文件，每个元数据文件只生成一次，返回成功生成的数量
+func (s *ArtifactServiceImpl) refreshMetadataPaths(ctx context.Context, repo *model.Repository, paths []string) int {
+	formatPlugin, err := s.plugins.GetFormatPlugin(repo.Format)
+	if err != nil {
+		return 0
+	}
+	locator, ok := formatPlugin.(pluginapi.ArtifactLocator)
+	if !ok {
+		return 0
+	}
+	done := make(map[string]bool)
+	rebuilt := 0
+	for _, p := range paths {
+		metadataPath, ok := locator.MetadataPath(p)
+		if !ok || done[metadataPath] {
+			continue
+		}
+		done[metadataPath] = true
+		if err := s.rebuildMetadata(ctx, repo, metadataPath); err != nil {
+			s.logger.Warn("Failed to rebuild metadata", "repository", repo.Name, "path", metadataPath, "error", err)
+			continue
+		}
+		rebuilt++
+	}
+	return rebuilt
+}
@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// readGroup 读取组仓库内容
+// 普通文件按成员顺序返回第一个命中的结果；元数据文件收集所有成员的版本后按格式合并
+func (s *ArtifactServiceImpl) readGroup(ctx context.Context, group *model.Repository, p string, visited map[string]bool) (*dto.ArtifactContent, error) {
+	if visited[group.ID] {
+		return nil, errs.NotFound("artifact %q not found in repository %q", p, group.Name)
+	}
+	visited[group.ID] = true
+
+	members, err := s.groupMembers(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isMetadata := s.metadataMerger(group.Format, p); isMetadata {
+		return s.mergeGroupMetadata(ctx, group, members, p, visited)
+	}
+
+	for _, member := range members {
+		content, err := s.resolve(ctx, member, p, visited)
+		if err == nil {
+			return content, nil
+		}
+		if !errors.Is(err, errs.ErrNotFound) {
+			s.logger.Warn("Group member failed, trying next", "group", group.Name, "member", member.Name, "path", p, "error", err)
+		}
+	}
+	return nil, errs.NotFound("artifact %q not found in repository %q", p, group.Name)
+}
+
+// mergeGroupMetadata 收集所有成员的元数据并合并，只有一个成员命中时原样返回
+func (s *ArtifactServiceImpl) mergeGroupMetadata(ctx context.Context, group *model.Repository, members []*model.Repository, p string, visited map[string]bool) (*dto.ArtifactContent, error) {
+	merger, _ := s.metadataMerger(group.Format, p)
+
+	var found []*dto.ArtifactContent
+	for _, member := range members {
+		content, err := s.resolve(ctx, member, p, visited)
+		if err != nil {
+			if !errors.Is(err, errs.ErrNotFound) {
+				s.logger.Warn("Group member metadata unavailable", "group", group.Name, "member", member.Name, "path", p, "error", err)
+			}
+			continue
+		}
+		found = append(found, content)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, errs.NotFound("artifact %q not found in repository %q", p, group.Name)
+	case 1:
+		return found[0], nil
+	}
+
+	documents := make([][]byte, len(found))
+	for i, content := range found {
+		documents[i] = content.Data
+	}
+	merged, err := merger.MergeMetadata(ctx, p, documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge metadata for group %q: %w", group.Name, err)
+	}
+
+	return &dto.ArtifactContent{
+		Repository:  group.Name,
+		Path:        p,
+		ContentType: found[0].ContentType,
+		Data:        merged,
+	}, nil
+}
+
+// groupMembers 按配置顺序返回组仓库的成员，已删除的成员被忽略
+func (s *ArtifactServiceImpl) groupMembers(ctx context.Context, group *model.Repository) ([]*model.Repository, error) {
+	repos, err := s.repositories.FindByNames(ctx, group.Members)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find group members: %w", err)
+	}
+	byName := make(map[string]*model.Repository, len(repos))
+	for _, repo := range repos {
+		byName[repo.Name] = repo
+	}
+
+	members := make([]*model.Repository, 0, len(group.Members))
+	for _, name := range group.Members {
+		if repo, ok := byName[name]; ok {
+			members = append(members, repo)
+		}
+	}
+	return members, nil
+}
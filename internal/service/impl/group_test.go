@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestArtifactService_ReadGroup(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	env.hosted(t, "thirdparty", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"releases", "thirdparty"}})
+
+	env.upload(t, "releases", "com/x/lib/1.0/lib-1.0.jar", []byte("releases-1.0"))
+	env.upload(t, "thirdparty", "com/x/lib/1.0/lib-1.0.jar", []byte("thirdparty-1.0"))
+	env.upload(t, "thirdparty", "com/x/lib/2.0/lib-2.0.jar", []byte("thirdparty-2.0"))
+
+	t.Run("first_member_wins", func(t *testing.T) {
+		data, err := env.download("public", "com/x/lib/1.0/lib-1.0.jar")
+		require.NoError(t, err)
+		assert.Equal(t, "releases-1.0", string(data))
+	})
+
+	t.Run("falls_through_to_later_member", func(t *testing.T) {
+		data, err := env.download("public", "com/x/lib/2.0/lib-2.0.jar")
+		require.NoError(t, err)
+		assert.Equal(t, "thirdparty-2.0", string(data))
+	})
+
+	t.Run("missing_everywhere", func(t *testing.T) {
+		_, err := env.download("public", "com/x/lib/3.0/lib-3.0.jar")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("metadata_is_merged", func(t *testing.T) {
+		data, err := env.download("public", "com/x/lib/maven-metadata.xml")
+		require.NoError(t, err)
+		var doc struct {
+			Versions []string `xml:"versioning>versions>version"`
+		}
+		require.NoError(t, xml.Unmarshal(data, &doc))
+		assert.Equal(t, []string{"1.0", "2.0"}, doc.Versions)
+	})
+
+	t.Run("inactive_member_is_skipped", func(t *testing.T) {
+		_, err := env.repositories.Update(context.Background(), "releases", &dto.UpdateRepositoryRequest{Status: "inactive"})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, err := env.repositories.Update(context.Background(), "releases", &dto.UpdateRepositoryRequest{Status: "active"})
+			require.NoError(t, err)
+		})
+
+		data, err := env.download("public", "com/x/lib/1.0/lib-1.0.jar")
+		require.NoError(t, err)
+		assert.Equal(t, "thirdparty-1.0", string(data))
+	})
+}
+
+func TestArtifactService_ReadNestedGroup(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "inner-hosted", "npm")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "inner", Type: model.RepositoryTypeGroup, Format: "npm", Members: []string{"inner-hosted"}})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "outer", Type: model.RepositoryTypeGroup, Format: "npm", Members: []string{"inner"}})
+	env.upload(t, "inner-hosted", "lib/-/lib-1.0.0.tgz", []byte("tarball"))
+
+	data, err := env.download("outer", "lib/-/lib-1.0.0.tgz")
+	require.NoError(t, err)
+	assert.Equal(t, "tarball", string(data))
+}
+
+func TestRepositoryService_ValidateMembers(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "maven-hosted", "maven")
+	env.hosted(t, "npm-hosted", "npm")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "group-a", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"maven-hosted"}})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "group-b", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"group-a"}})
+
+	tests := []struct {
+		name    string
+		members []string
+	}{
+		{name: "no_members"},
+		{name: "missing_member", members: []string{"nope"}},
+		{name: "format_mismatch", members: []string{"npm-hosted"}},
+		{name: "duplicate_member", members: []string{"maven-hosted", "maven-hosted"}},
+		{name: "contains_itself", members: []string{"group-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.repositories.Update(context.Background(), "group-a", &dto.UpdateRepositoryRequest{Members: tt.members})
+			assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+		})
+	}
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := env.repositories.Update(context.Background(), "group-a", &dto.UpdateRepositoryRequest{Members: []string{"maven-hosted", "group-b"}})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	t.Run("hosted_with_members", func(t *testing.T) {
+		_, err := env.repositories.Create(context.Background(), &dto.CreateRepositoryRequest{Name: "h", Type: model.RepositoryTypeHosted, Format: "maven", Members: []string{"maven-hosted"}})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	t.Run("member_cannot_be_deleted", func(t *testing.T) {
+		err := env.repositories.Delete(context.Background(), "maven-hosted")
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+}
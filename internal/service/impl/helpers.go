@@ -0,0 +1,69 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// lookupRepository 按ID或名称查找仓库
+func lookupRepository(ctx context.Context, repos repository.RepositoryRepository, idOrName string) (*model.Repository, error) {
+	repo, err := repos.FindByID(ctx, idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repository: %w", err)
+	}
+	if repo == nil {
+		repo, err = repos.FindByName(ctx, idOrName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find repository: %w", err)
+		}
+	}
+	if repo == nil {
+		return nil, errs.NotFound("repository %q not found", idOrName)
+	}
+	return repo, nil
+}
+
+// normalizePath 规范化制品路径，去掉首尾斜杠并拒绝目录穿越
+func normalizePath(p string) (string, error) {
+	trimmed := strings.Trim(p, "/")
+	if trimmed == "" {
+		return "", errs.InvalidArgument("artifact path is required")
+	}
+	if cleaned := path.Clean(trimmed); cleaned != trimmed || strings.HasPrefix(cleaned, "..") {
+		return "", errs.InvalidArgument("invalid artifact path: %s", p)
+	}
+	return trimmed, nil
+}
+
+// storagePath 制品在存储后端中的路径
+func storagePath(repo *model.Repository, p string) string {
+	return "repositories/" + repo.ID + "/" + p
+}
+
+// contentTypeOf 根据格式和扩展名推断内容类型
+func contentTypeOf(format, p string) string {
+	if format == "npm" && !strings.Contains(p, "/-/") {
+		return "application/json"
+	}
+	switch path.Ext(p) {
+	case ".pom", ".xml":
+		return "application/xml"
+	case ".jar", ".war", ".ear":
+		return "application/java-archive"
+	case ".tgz":
+		return "application/gzip"
+	case ".yaml", ".yml":
+		return "application/x-yaml"
+	}
+	if ct := mime.TypeByExtension(path.Ext(p)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,200 @@
+package impl
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/fulltext"
+	"github.com/laolishu/go-nexus/internal/plugin"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	repoimpl "github.com/laolishu/go-nexus/internal/repository/impl"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/internal/storage"
+	"github.com/laolishu/go-nexus/pkg/config"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// testEnv 服务层测试环境：临时目录下的 SQLite 数据库、文件系统存储与全文索引
+type testEnv struct {
+	cfg     *config.Config
+	logger  *slog.Logger
+	db      *gorm.DB
+	storage pluginapi.StoragePlugin
+	plugins *plugin.Manager
+	monitor *RemoteMonitor
+	index   *fulltext.Index
+
+	repos        *repoimpl.RepositoryRepositoryImpl
+	artifactRepo *repoimpl.ArtifactRepositoryImpl
+	policyRepo   *repoimpl.CleanupPolicyRepositoryImpl
+	keyRepo      *repoimpl.TrustedKeyRepositoryImpl
+	signingRepo  *repoimpl.SigningKeyRepositoryImpl
+	userRepo     *repoimpl.UserRepositoryImpl
+	roleRepo     *repoimpl.RoleRepositoryImpl
+
+	authorizer   *AuthorizerImpl
+	audit        *AuditRecorder
+	signer       *MetadataSigner
+	repositories *RepositoryServiceImpl
+	artifacts    *ArtifactServiceImpl
+}
+
+// newTestEnv 创建测试环境，测试结束时自动关闭
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	dir := t.TempDir()
+
+	cfg := &config.Config{}
+	cfg.Database.Type = "sqlite"
+	cfg.Database.DSN = filepath.Join(dir, "test.db")
+	cfg.Storage.Type = "filesystem"
+	cfg.Storage.BasePath = filepath.Join(dir, "storage")
+	cfg.Security.Signing.Secret = "test-signing-secret"
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := gorm.Open(sqlite.Open(cfg.Database.DSN), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{TablePrefix: "gn_", SingularTable: true},
+		Logger:         silentGormLogger(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repository.Migrate(db))
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	store, err := storage.NewStorage(cfg, logger)
+	require.NoError(t, err)
+	index, closeIndex, err := fulltext.NewIndex(cfg, logger)
+	require.NoError(t, err)
+	t.Cleanup(closeIndex)
+
+	env := &testEnv{
+		cfg:     cfg,
+		logger:  logger,
+		db:      db,
+		storage: store,
+		plugins: plugin.NewManager(logger),
+		monitor: NewRemoteMonitor(logger),
+		index:   index,
+
+		repos:        repoimpl.NewRepositoryRepository(logger, dao.NewRepositoryDAO(logger, db)),
+		artifactRepo: repoimpl.NewArtifactRepository(logger, dao.NewArtifactDAO(logger, db)),
+		policyRepo:   repoimpl.NewCleanupPolicyRepository(logger, dao.NewCleanupPolicyDAO(logger, db)),
+		keyRepo:      repoimpl.NewTrustedKeyRepository(logger, dao.NewTrustedKeyDAO(logger, db)),
+		signingRepo:  repoimpl.NewSigningKeyRepository(logger, dao.NewSigningKeyDAO(logger, db)),
+		userRepo:     repoimpl.NewUserRepository(logger, dao.NewUserDAO(logger, db)),
+		roleRepo:     repoimpl.NewRoleRepository(logger, dao.NewRoleDAO(logger, db)),
+	}
+	env.authorizer = NewAuthorizer(logger, env.repos, env.roleRepo)
+	env.audit = NewAuditRecorder(logger, repoimpl.NewAuditLogRepository(logger, dao.NewAuditLogDAO(logger, db)))
+	env.signer = NewMetadataSigner(cfg, logger, env.signingRepo)
+	env.artifacts = NewArtifactService(logger, env.artifactRepo, env.repos, store, env.plugins, env.monitor, index, env.keyRepo, env.signer)
+	env.repositories = NewRepositoryService(logger, env.repos, env.plugins, env.monitor, index, env.policyRepo, env.authorizer, env.keyRepo, env.artifacts)
+	return env
+}
+
+// silentGormLogger 测试中不输出 SQL 日志
+func silentGormLogger() logger.Interface {
+	return logger.Default.LogMode(logger.Silent)
+}
+
+// createRepo 创建仓库，失败时终止测试
+func (e *testEnv) createRepo(t *testing.T, req *dto.CreateRepositoryRequest) *model.Repository {
+	t.Helper()
+	repo, err := e.repositories.Create(context.Background(), req)
+	require.NoError(t, err)
+	return repo
+}
+
+// hosted 创建指定格式的宿主仓库
+func (e *testEnv) hosted(t *testing.T, name, format string) *model.Repository {
+	t.Helper()
+	return e.createRepo(t, &dto.CreateRepositoryRequest{Name: name, Type: model.RepositoryTypeHosted, Format: format})
+}
+
+// upload 上传制品，失败时终止测试
+func (e *testEnv) upload(t *testing.T, repo, p string, data []byte) *model.Artifact {
+	t.Helper()
+	artifact, err := e.artifacts.Upload(context.Background(), repo, &dto.UploadArtifactRequest{Path: p, Data: data})
+	require.NoError(t, err)
+	return artifact
+}
+
+// download 读取仓库内容
+func (e *testEnv) download(repo, p string) ([]byte, error) {
+	content, err := e.artifacts.Download(context.Background(), repo, p)
+	if err != nil {
+		return nil, err
+	}
+	return content.Data, nil
+}
+
+// asUser 以指定权限的调用者构造 context
+func asUser(username string, permissions ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		UserID:      username,
+		Username:    username,
+		Permissions: permissions,
+		Credential:  auth.CredentialJWT,
+	})
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "trims_slashes", input: "/com/example/lib/", want: "com/example/lib"},
+		{name: "plain", input: "a/b.jar", want: "a/b.jar"},
+		{name: "empty", input: "/", wantErr: true},
+		{name: "parent_traversal", input: "../etc/passwd", wantErr: true},
+		{name: "inner_traversal", input: "a/../../b", wantErr: true},
+		{name: "double_slash", input: "a//b", wantErr: true},
+		{name: "dot_segment", input: "a/./b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizePath(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContentTypeOf(t *testing.T) {
+	tests := []struct {
+		format, path, want string
+	}{
+		{"maven", "g/a/1.0/a-1.0.pom", "application/xml"},
+		{"maven", "g/a/1.0/a-1.0.jar", "application/java-archive"},
+		{"npm", "lodash", "application/json"},
+		{"npm", "lodash/-/lodash-1.0.0.tgz", "application/gzip"},
+		{"helm", "index.yaml", "application/x-yaml"},
+		{"maven", "g/a/1.0/a-1.0.unknownext", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, contentTypeOf(tt.format, tt.path))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/laolishu/go-nexus/internal/fulltext"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// indexDocument 由制品记录生成全文索引文档，只索引格式元数据中的名称、描述与关键字
+// 没有元数据的制品（如 jar、校验和文件）生成空文档，索引时会被移除
+func indexDocument(a *model.Artifact) *fulltext.Document {
+	doc := &fulltext.Document{ID: a.ID, RepositoryID: a.RepositoryID}
+	if len(a.Metadata) == 0 {
+		return doc
+	}
+	doc.Name = a.Name
+	if name := a.Metadata[metadataName]; name != "" && !strings.EqualFold(name, a.Name) {
+		doc.Name += " " + name
+	}
+	doc.Description = a.Metadata[metadataDescription]
+	if keywords := a.Metadata[metadataKeywords]; keywords != "" {
+		doc.Keywords = strings.Split(keywords, ",")
+	}
+	return doc
+}
+
+// reindex 根据数据库中的制品记录全量重建全文索引
+func (s *ArtifactServiceImpl) reindex(ctx context.Context) error {
+	repos, err := s.repositories.List(ctx, "", "")
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	s.index.Reset()
+	for _, repo := range repos {
+		if repo.Type == model.RepositoryTypeGroup {
+			continue
+		}
+		artifacts, err := s.repository.ListByRepository(ctx, repo.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		for _, artifact := range artifacts {
+			s.index.Add(indexDocument(artifact))
+		}
+	}
+	s.logger.Info("Full-text index rebuilt", "documents", s.index.Len())
+	return nil
+}
@@ -0,0 +1,80 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/internal/version"
+)
+
+// Latest 解析满足范围的最高版本，group 仓库在所有成员中查找
+func (s *ArtifactServiceImpl) Latest(ctx context.Context, repoIDOrName string, query *dto.LatestVersionQuery) (*dto.LatestVersion, error) {
+	if strings.Contains(query.Name, "*") {
+		return nil, errs.InvalidArgument("name must not contain wildcards")
+	}
+	repo, err := lookupRepository(ctx, s.repositories, repoIDOrName)
+	if err != nil {
+		return nil, err
+	}
+	var versionRange *version.Range
+	if query.Range != "" {
+		if versionRange, err = version.ParseRange(query.Range); err != nil {
+			return nil, errs.InvalidArgument("%s", err.Error())
+		}
+	}
+	repositoryIDs, err := s.leafRepositoryIDs(ctx, repo, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &model.ArtifactFilter{
+		RepositoryIDs: repositoryIDs,
+		Name:          query.Name,
+		Sort:          model.ArtifactSortCreatedAt,
+	}
+	switch {
+	case query.GroupID == "":
+	case repo.Format == "maven":
+		// jar 等文件不解析元数据，按 Maven 目录结构匹配以包含该版本的所有文件
+		filter.PathPrefix = strings.ReplaceAll(query.GroupID, ".", "/") + "/" + query.Name + "/"
+	default:
+		filter.Metadata = map[string]string{metadataGroupID: query.GroupID}
+	}
+	artifacts, err := s.repository.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	scheme := version.ForFormat(repo.Format)
+	latest := ""
+	for _, artifact := range artifacts {
+		v := artifact.Version
+		if v == "" || v == latest {
+			continue
+		}
+		if !query.Prerelease && scheme.IsPrerelease(v) {
+			continue
+		}
+		if versionRange != nil && !versionRange.Contains(v, scheme.Compare) {
+			continue
+		}
+		if latest == "" || scheme.Compare(v, latest) > 0 {
+			latest = v
+		}
+	}
+	if latest == "" {
+		return nil, errs.NotFound("no version of %q matches in repository %q", query.Name, repo.Name)
+	}
+
+	result := &dto.LatestVersion{Name: query.Name, Version: latest, Scheme: scheme.Name}
+	for _, artifact := range artifacts {
+		if artifact.Version == latest {
+			result.Artifacts = append(result.Artifacts, artifact)
+		}
+	}
+	return result, nil
+}
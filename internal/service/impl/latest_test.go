@@ -0,0 +1,66 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestArtifactService_Latest(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	env.hosted(t, "snapshots", "maven")
+	env.hosted(t, "npm-hosted", "npm")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"releases", "snapshots"}})
+
+	for _, v := range []string{"1.9", "2.0", "2.10", "2.9"} {
+		env.upload(t, "releases", "com/x/lib/"+v+"/lib-"+v+".jar", []byte(v))
+	}
+	env.upload(t, "releases", "com/x/lib/2.10/lib-2.10.pom", []byte("<project/>"))
+	env.upload(t, "releases", "org/y/lib/9.0/lib-9.0.jar", []byte("other group"))
+	env.upload(t, "snapshots", "com/x/lib/3.0-SNAPSHOT/lib-3.0-SNAPSHOT.jar", []byte("snapshot"))
+	env.upload(t, "npm-hosted", "lib/-/lib-1.0.0.tgz", []byte("1.0.0"))
+	env.upload(t, "npm-hosted", "lib/-/lib-1.1.0-rc.1.tgz", []byte("rc"))
+
+	tests := []struct {
+		name        string
+		repo        string
+		query       dto.LatestVersionQuery
+		want        string
+		wantScheme  string
+		wantFiles   int
+		wantErrKind error
+	}{
+		{name: "numeric_order", repo: "releases", query: dto.LatestVersionQuery{Name: "lib", GroupID: "com.x"}, want: "2.10", wantScheme: "maven", wantFiles: 2},
+		{name: "range", repo: "releases", query: dto.LatestVersionQuery{Name: "lib", GroupID: "com.x", Range: "[2.0,2.10)"}, want: "2.9", wantScheme: "maven", wantFiles: 1},
+		{name: "group_id_filter", repo: "releases", query: dto.LatestVersionQuery{Name: "lib", GroupID: "org.y"}, want: "9.0", wantScheme: "maven", wantFiles: 1},
+		{name: "group_excludes_prerelease", repo: "public", query: dto.LatestVersionQuery{Name: "lib", GroupID: "com.x"}, want: "2.10", wantScheme: "maven", wantFiles: 2},
+		{name: "group_includes_prerelease", repo: "public", query: dto.LatestVersionQuery{Name: "lib", GroupID: "com.x", Prerelease: true}, want: "3.0-SNAPSHOT", wantScheme: "maven", wantFiles: 1},
+		{name: "npm_semver", repo: "npm-hosted", query: dto.LatestVersionQuery{Name: "lib", Range: "^1.0.0"}, want: "1.0.0", wantScheme: "semver", wantFiles: 1},
+		{name: "npm_prerelease", repo: "npm-hosted", query: dto.LatestVersionQuery{Name: "lib", Prerelease: true}, want: "1.1.0-rc.1", wantScheme: "semver", wantFiles: 1},
+		{name: "no_match", repo: "releases", query: dto.LatestVersionQuery{Name: "lib", GroupID: "com.x", Range: ">=5"}, wantErrKind: errs.ErrNotFound},
+		{name: "invalid_range", repo: "releases", query: dto.LatestVersionQuery{Name: "lib", Range: "[1.0"}, wantErrKind: errs.ErrInvalidArgument},
+		{name: "wildcard_name", repo: "releases", query: dto.LatestVersionQuery{Name: "li*"}, wantErrKind: errs.ErrInvalidArgument},
+		{name: "missing_repository", repo: "nope", query: dto.LatestVersionQuery{Name: "lib"}, wantErrKind: errs.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			latest, err := env.artifacts.Latest(context.Background(), tt.repo, &query)
+			if tt.wantErrKind != nil {
+				assert.ErrorIs(t, err, tt.wantErrKind)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, latest.Version)
+			assert.Equal(t, tt.wantScheme, latest.Scheme)
+			assert.Len(t, latest.Artifacts, tt.wantFiles)
+		})
+	}
+}
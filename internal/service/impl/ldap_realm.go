@@ -0,0 +1,289 @@
+package impl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// LDAPRealm LDAP/Active Directory 认证域
+// 使用服务账户搜索用户条目，再以用户 DN 与密码绑定校验密码；认证通过后按组映射同步本地用户与角色
+type LDAPRealm struct {
+	cfg      *config.LDAPConfig
+	logger   *slog.Logger
+	users    *UserServiceImpl
+	tls      *tls.Config
+	mappings []ldapGroupMapping
+}
+
+// ldapGroupMapping 解析后的组映射，dn 为 nil 时按组名匹配
+type ldapGroupMapping struct {
+	name  string
+	dn    *ldap.DN
+	roles []string
+}
+
+// ldapGroup 用户所属的组
+type ldapGroup struct {
+	name string
+	dn   *ldap.DN
+}
+
+// NewLDAPRealm 创建 LDAP 认证域，校验连接地址、CA 证书与组映射
+func NewLDAPRealm(cfg *config.LDAPConfig, logger *slog.Logger, users *UserServiceImpl) (*LDAPRealm, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return nil, fmt.Errorf("invalid security.ldap.url %q, expected ldap://host:port or ldaps://host:port", cfg.URL)
+	}
+	if u.Scheme == "ldaps" && cfg.StartTLS {
+		return nil, fmt.Errorf("security.ldap.start_tls cannot be used with ldaps://")
+	}
+	if !strings.Contains(cfg.UserFilter, "{username}") {
+		return nil, fmt.Errorf("security.ldap.user_filter %q must contain {username}", cfg.UserFilter)
+	}
+	if cfg.UsernameAttribute == "" {
+		return nil, fmt.Errorf("security.ldap.username_attribute is required")
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read security.ldap.ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("security.ldap.ca_file %q contains no certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.InsecureSkipVerify {
+		logger.Warn("security.ldap.insecure_skip_verify is enabled, the LDAP server certificate is not verified")
+	}
+
+	realm := &LDAPRealm{cfg: cfg, logger: logger, users: users, tls: tlsConfig}
+	for _, mapping := range cfg.GroupMappings {
+		group := strings.TrimSpace(mapping.Group)
+		if group == "" || len(mapping.Roles) == 0 {
+			return nil, fmt.Errorf("security.ldap.group_mappings requires group and roles")
+		}
+		parsed := ldapGroupMapping{name: group, roles: mapping.Roles}
+		if strings.Contains(group, "=") {
+			if parsed.dn, err = ldap.ParseDN(group); err != nil {
+				return nil, fmt.Errorf("invalid group DN %q in security.ldap.group_mappings: %v", group, err)
+			}
+		}
+		realm.mappings = append(realm.mappings, parsed)
+	}
+	return realm, nil
+}
+
+// Name 认证域名称
+func (r *LDAPRealm) Name() string {
+	return model.UserRealmLDAP
+}
+
+// Authenticate 在目录中查找用户并校验密码，认证通过后创建或同步本地用户
+// 用户不存在、存在多个匹配条目或密码错误时返回 401，目录不可用时返回内部错误
+func (r *LDAPRealm) Authenticate(ctx context.Context, username, password string) (*model.User, error) {
+	// 空密码的简单绑定在 LDAP 中是未认证绑定，总是成功，必须拒绝
+	if username == "" || password == "" {
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+
+	conn, err := r.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := r.bindService(conn); err != nil {
+		return nil, err
+	}
+	entry, err := r.findUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errs.Unauthenticated("invalid username or password")
+		}
+		return nil, fmt.Errorf("failed to bind as %q: %w", entry.DN, err)
+	}
+
+	groups, err := r.userGroups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+	ext := &externalUser{
+		Realm:    model.UserRealmLDAP,
+		Username: entry.GetEqualFoldAttributeValue(r.cfg.UsernameAttribute),
+		Email:    r.attribute(entry, r.cfg.EmailAttribute),
+		FullName: r.attribute(entry, r.cfg.FullNameAttribute),
+		Roles:    r.mapRoles(groups),
+	}
+	if ext.Username == "" {
+		ext.Username = username
+	}
+	r.logger.Debug("LDAP user authenticated", "username", ext.Username, "dn", entry.DN, "groups", len(groups), "roles", ext.Roles)
+	return r.users.provision(ctx, ext)
+}
+
+// connect 连接目录服务器，按配置升级为 TLS
+func (r *LDAPRealm) connect() (*ldap.Conn, error) {
+	dialer := &net.Dialer{Timeout: r.cfg.Timeout}
+	conn, err := ldap.DialURL(r.cfg.URL, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(r.tls))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	if r.cfg.Timeout > 0 {
+		conn.SetTimeout(r.cfg.Timeout)
+	}
+	if r.cfg.StartTLS {
+		if err := conn.StartTLS(r.tls); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS with LDAP server: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// bindService 以服务账户绑定，未配置服务账户时保持匿名
+func (r *LDAPRealm) bindService(conn *ldap.Conn) error {
+	if r.cfg.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(r.cfg.BindDN, r.cfg.BindPassword); err != nil {
+		return fmt.Errorf("failed to bind LDAP service account: %w", err)
+	}
+	return nil
+}
+
+// findUser 按 user_filter 查找唯一的用户条目
+func (r *LDAPRealm) findUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	filter := strings.ReplaceAll(r.cfg.UserFilter, "{username}", ldap.EscapeFilter(username))
+	attributes := []string{r.cfg.UsernameAttribute}
+	for _, attribute := range []string{r.cfg.EmailAttribute, r.cfg.FullNameAttribute, r.cfg.MemberOfAttribute} {
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		r.cfg.UserBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		filter, attributes, nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			r.logger.Warn("LDAP user filter matches multiple entries", "username", username)
+			return nil, errs.Unauthenticated("invalid username or password")
+		}
+		return nil, fmt.Errorf("failed to search LDAP user: %w", err)
+	}
+	switch len(result.Entries) {
+	case 0:
+		return nil, errs.Unauthenticated("invalid username or password")
+	case 1:
+		return result.Entries[0], nil
+	default:
+		r.logger.Warn("LDAP user filter matches multiple entries", "username", username)
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+}
+
+// userGroups 收集用户条目 member_of_attribute 中的组与组搜索结果
+// 组搜索以服务账户重新绑定，普通用户通常没有搜索组的权限
+func (r *LDAPRealm) userGroups(conn *ldap.Conn, entry *ldap.Entry) ([]ldapGroup, error) {
+	var groups []ldapGroup
+	if r.cfg.MemberOfAttribute != "" {
+		for _, value := range entry.GetEqualFoldAttributeValues(r.cfg.MemberOfAttribute) {
+			dn, err := ldap.ParseDN(value)
+			if err != nil {
+				r.logger.Warn("Invalid LDAP group DN ignored", "dn", value, "error", err)
+				continue
+			}
+			groups = append(groups, ldapGroup{name: firstRDNValue(dn), dn: dn})
+		}
+	}
+	if r.cfg.GroupBaseDN == "" {
+		return groups, nil
+	}
+
+	if err := r.bindService(conn); err != nil {
+		return nil, err
+	}
+	username := entry.GetEqualFoldAttributeValue(r.cfg.UsernameAttribute)
+	filter := strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(entry.DN),
+		"{username}", ldap.EscapeFilter(username),
+	).Replace(r.cfg.GroupFilter)
+	result, err := conn.Search(ldap.NewSearchRequest(
+		r.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter, []string{r.cfg.GroupNameAttribute}, nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search LDAP groups: %w", err)
+	}
+	for _, group := range result.Entries {
+		dn, err := ldap.ParseDN(group.DN)
+		if err != nil {
+			r.logger.Warn("Invalid LDAP group DN ignored", "dn", group.DN, "error", err)
+			continue
+		}
+		name := group.GetEqualFoldAttributeValue(r.cfg.GroupNameAttribute)
+		if name == "" {
+			name = firstRDNValue(dn)
+		}
+		groups = append(groups, ldapGroup{name: name, dn: dn})
+	}
+	return groups, nil
+}
+
+// mapRoles 按组映射计算角色，所有用户都拥有 default_roles
+func (r *LDAPRealm) mapRoles(groups []ldapGroup) []string {
+	roles := append([]string{}, r.cfg.DefaultRoles...)
+	for _, mapping := range r.mappings {
+		for _, group := range groups {
+			matched := strings.EqualFold(mapping.name, group.name)
+			if mapping.dn != nil {
+				matched = mapping.dn.EqualFold(group.dn)
+			}
+			if matched {
+				roles = append(roles, mapping.roles...)
+				break
+			}
+		}
+	}
+	return roles
+}
+
+// attribute 读取可选属性
+func (r *LDAPRealm) attribute(entry *ldap.Entry, name string) string {
+	if name == "" {
+		return ""
+	}
+	return entry.GetEqualFoldAttributeValue(name)
+}
+
+// firstRDNValue 返回 DN 第一个 RDN 的值，如 CN=Developers,OU=Groups 返回 Developers
+func firstRDNValue(dn *ldap.DN) string {
+	if len(dn.RDNs) == 0 || len(dn.RDNs[0].Attributes) == 0 {
+		return ""
+	}
+	return dn.RDNs[0].Attributes[0].Value
+}
@@ -0,0 +1,593 @@
+package impl
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	testLDAPBindDN       = "cn=svc,dc=example,dc=com"
+	testLDAPBindPassword = "svc-secret"
+	testLDAPPeopleDN     = "ou=people,dc=example,dc=com"
+)
+
+// fakeLDAP 进程内的 LDAP 目录替身，只实现简单绑定与搜索
+// 搜索结果按反编译后的过滤器字符串查表，便于断言过滤器的转义
+type fakeLDAP struct {
+	listener net.Listener
+
+	mu        sync.Mutex
+	passwords map[string]string         // DN -> 密码
+	results   map[string][]*ldap.Entry  // 过滤器 -> 条目
+	binds     []string                  // 绑定过的 DN
+	filters   []string                  // 收到的搜索过滤器
+	failBind  map[string]ldapResultCode // DN -> 绑定时返回的错误码
+}
+
+// ldapResultCode LDAP 结果码
+type ldapResultCode = uint16
+
+// newFakeLDAP 启动目录替身，测试结束时关闭
+func newFakeLDAP(t *testing.T) *fakeLDAP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeLDAP{
+		listener:  listener,
+		passwords: map[string]string{testLDAPBindDN: testLDAPBindPassword},
+		results:   map[string][]*ldap.Entry{},
+		failBind:  map[string]ldapResultCode{},
+	}
+	t.Cleanup(func() { listener.Close() })
+	go server.serve()
+	return server
+}
+
+// URL 目录替身的地址
+func (f *fakeLDAP) URL() string {
+	return "ldap://" + f.listener.Addr().String()
+}
+
+// addUser 添加用户条目，filter 为查找该用户时的过滤器
+func (f *fakeLDAP) addUser(filter, uid, password string, attributes map[string][]string) *ldap.Entry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dn := "uid=" + uid + "," + testLDAPPeopleDN
+	attributes["uid"] = []string{uid}
+	entry := ldap.NewEntry(dn, attributes)
+	f.passwords[dn] = password
+	f.results[filter] = append(f.results[filter], entry)
+	return entry
+}
+
+// addResult 为过滤器添加搜索结果
+func (f *fakeLDAP) addResult(filter string, entries ...*ldap.Entry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[filter] = append(f.results[filter], entries...)
+}
+
+// seenFilters 返回收到的搜索过滤器
+func (f *fakeLDAP) seenFilters() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.filters...)
+}
+
+// seenBinds 返回绑定过的 DN
+func (f *fakeLDAP) seenBinds() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.binds...)
+}
+
+func (f *fakeLDAP) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+// handle 处理一个连接上的请求，直到客户端解绑或断开
+func (f *fakeLDAP) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			f.reply(conn, messageID, ldap.ApplicationBindResponse, f.bind(op))
+		case ldap.ApplicationSearchRequest:
+			f.search(conn, messageID, op)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			f.reply(conn, messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError)
+		}
+	}
+}
+
+// bind 校验简单绑定的 DN 与密码
+func (f *fakeLDAP) bind(op *ber.Packet) ldapResultCode {
+	dn := op.Children[1].Data.String()
+	password := op.Children[2].Data.String()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.binds = append(f.binds, dn)
+	if code, ok := f.failBind[dn]; ok {
+		return code
+	}
+	if expected, ok := f.passwords[dn]; !ok || password == "" || expected != password {
+		return ldap.LDAPResultInvalidCredentials
+	}
+	return ldap.LDAPResultSuccess
+}
+
+// search 按过滤器返回条目，超过 sizeLimit 时返回 sizeLimitExceeded
+func (f *fakeLDAP) search(conn net.Conn, messageID int64, op *ber.Packet) {
+	sizeLimit := int(op.Children[3].Value.(int64))
+	filter, err := ldap.DecompileFilter(op.Children[6])
+	if err != nil {
+		f.reply(conn, messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError)
+		return
+	}
+	f.mu.Lock()
+	f.filters = append(f.filters, filter)
+	entries := f.results[filter]
+	f.mu.Unlock()
+
+	code := ldapResultCode(ldap.LDAPResultSuccess)
+	if sizeLimit > 0 && len(entries) > sizeLimit {
+		entries, code = entries[:sizeLimit], ldap.LDAPResultSizeLimitExceeded
+	}
+	for _, entry := range entries {
+		response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+		attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for _, attribute := range entry.Attributes {
+			item := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			item.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute.Name, "Type"))
+			values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range attribute.Values {
+				values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			item.AppendChild(values)
+			attributes.AppendChild(item)
+		}
+		response.AppendChild(attributes)
+		f.write(conn, messageID, response)
+	}
+	f.reply(conn, messageID, ldap.ApplicationSearchResultDone, code)
+}
+
+// reply 发送只包含结果码的响应
+func (f *fakeLDAP) reply(conn net.Conn, messageID int64, tag ber.Tag, code ldapResultCode) {
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	f.write(conn, messageID, response)
+}
+
+// write 以 LDAPMessage 包装并发送响应
+func (f *fakeLDAP) write(conn net.Conn, messageID int64, op *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	envelope.AppendChild(op)
+	_, _ = conn.Write(envelope.Bytes())
+}
+
+// testLDAPConfig 指向目录替身的 LDAP 配置
+func testLDAPConfig(server *fakeLDAP) *config.LDAPConfig {
+	return &config.LDAPConfig{
+		URL:               server.URL(),
+		Timeout:           5 * time.Second,
+		BindDN:            testLDAPBindDN,
+		BindPassword:      testLDAPBindPassword,
+		UserBaseDN:        testLDAPPeopleDN,
+		UserFilter:        "(uid={username})",
+		UsernameAttribute: "uid",
+		EmailAttribute:    "mail",
+		FullNameAttribute: "cn",
+		MemberOfAttribute: "memberOf",
+	}
+}
+
+// newLDAPEnv 创建用户服务与指向目录替身的 LDAP 认证域，configure 可调整配置
+func newLDAPEnv(t *testing.T, configure func(cfg *config.LDAPConfig)) (*testEnv, *UserServiceImpl, *fakeLDAP, *LDAPRealm) {
+	t.Helper()
+	env, users := newUserEnv(t)
+	server := newFakeLDAP(t)
+	cfg := testLDAPConfig(server)
+	if configure != nil {
+		configure(cfg)
+	}
+	realm, err := NewLDAPRealm(cfg, env.logger, users)
+	require.NoError(t, err)
+	return env, users, server, realm
+}
+
+func TestNewLDAPRealm(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name      string
+		configure func(cfg *config.LDAPConfig)
+		wantErr   string
+	}{
+		{name: "valid"},
+		{name: "ldaps", configure: func(cfg *config.LDAPConfig) { cfg.URL = "ldaps://ldap.example.com:636" }},
+		{name: "group_mappings", configure: func(cfg *config.LDAPConfig) {
+			cfg.GroupMappings = []config.GroupMapping{{Group: "developers", Roles: []string{"developer"}}, {Group: "cn=admins,ou=groups,dc=example,dc=com", Roles: []string{"admin"}}}
+		}},
+		{name: "http_url", configure: func(cfg *config.LDAPConfig) { cfg.URL = "http://ldap.example.com" }, wantErr: "security.ldap.url"},
+		{name: "missing_host", configure: func(cfg *config.LDAPConfig) { cfg.URL = "ldap://" }, wantErr: "security.ldap.url"},
+		{name: "ldaps_with_start_tls", configure: func(cfg *config.LDAPConfig) { cfg.URL, cfg.StartTLS = "ldaps://ldap.example.com", true }, wantErr: "start_tls"},
+		{name: "filter_without_placeholder", configure: func(cfg *config.LDAPConfig) { cfg.UserFilter = "(uid=alice)" }, wantErr: "{username}"},
+		{name: "missing_username_attribute", configure: func(cfg *config.LDAPConfig) { cfg.UsernameAttribute = "" }, wantErr: "username_attribute"},
+		{name: "missing_ca_file", configure: func(cfg *config.LDAPConfig) { cfg.CAFile = filepath.Join(dir, "missing.pem") }, wantErr: "ca_file"},
+		{name: "ca_file_without_certificates", configure: func(cfg *config.LDAPConfig) { cfg.CAFile = notPEM }, wantErr: "no certificates"},
+		{name: "mapping_without_roles", configure: func(cfg *config.LDAPConfig) {
+			cfg.GroupMappings = []config.GroupMapping{{Group: "developers"}}
+		}, wantErr: "group_mappings"},
+		{name: "mapping_without_group", configure: func(cfg *config.LDAPConfig) {
+			cfg.GroupMappings = []config.GroupMapping{{Group: " ", Roles: []string{"developer"}}}
+		}, wantErr: "group_mappings"},
+		{name: "invalid_group_dn", configure: func(cfg *config.LDAPConfig) {
+			cfg.GroupMappings = []config.GroupMapping{{Group: "cn=admins,=broken", Roles: []string{"admin"}}}
+		}, wantErr: "invalid group DN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.LDAPConfig{URL: "ldap://ldap.example.com:389", UserFilter: "(uid={username})", UsernameAttribute: "uid"}
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			realm, err := NewLDAPRealm(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, model.UserRealmLDAP, realm.Name())
+		})
+	}
+}
+
+func TestLDAPRealm_Authenticate(t *testing.T) {
+	_, _, server, realm := newLDAPEnv(t, func(cfg *config.LDAPConfig) {
+		cfg.DefaultRoles = []string{model.RoleViewer}
+	})
+	server.addUser("(uid=alice)", "alice", "alice-password", map[string][]string{
+		"mail": {"alice@example.com"},
+		"cn":   {"Alice Liddell"},
+	})
+	server.addUser("(uid=dup)", "dup", "dup-password", map[string][]string{})
+	server.addUser("(uid=dup)", "dup2", "dup-password", map[string][]string{})
+	for _, uid := range []string{"many1", "many2", "many3"} {
+		server.addUser("(uid=many)", uid, "many-password", map[string][]string{})
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  error
+	}{
+		{name: "success", username: "alice", password: "alice-password"},
+		{name: "wrong_password", username: "alice", password: "wrong", wantErr: errs.ErrUnauthenticated},
+		{name: "unknown_user", username: "nobody", password: "whatever", wantErr: errs.ErrUnauthenticated},
+		{name: "empty_password", username: "alice", password: "", wantErr: errs.ErrUnauthenticated},
+		{name: "empty_username", username: "", password: "alice-password", wantErr: errs.ErrUnauthenticated},
+		{name: "multiple_matches", username: "dup", password: "dup-password", wantErr: errs.ErrUnauthenticated},
+		{name: "size_limit_exceeded", username: "many", password: "many-password", wantErr: errs.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := realm.Authenticate(context.Background(), tt.username, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, user)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", user.Username)
+			assert.Equal(t, model.UserRealmLDAP, user.Realm)
+			assert.Equal(t, "alice@example.com", user.Email)
+			assert.Equal(t, "Alice Liddell", user.FullName)
+			assert.Equal(t, []string{model.RoleViewer}, roleNames(user))
+			assert.Empty(t, user.Password, "LDAP users have no local password")
+		})
+	}
+
+	t.Run("empty_password_never_binds", func(t *testing.T) {
+		before := len(server.seenBinds())
+		_, err := realm.Authenticate(context.Background(), "alice", "")
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+		assert.Len(t, server.seenBinds(), before, "an empty password would be an unauthenticated bind that always succeeds")
+	})
+
+	t.Run("binds_as_service_then_user", func(t *testing.T) {
+		before := len(server.seenBinds())
+		_, err := realm.Authenticate(context.Background(), "alice", "alice-password")
+		require.NoError(t, err)
+		assert.Equal(t, []string{testLDAPBindDN, "uid=alice," + testLDAPPeopleDN}, server.seenBinds()[before:])
+	})
+}
+
+func TestLDAPRealm_FilterEscaping(t *testing.T) {
+	_, _, server, realm := newLDAPEnv(t, nil)
+	tests := []struct {
+		username string
+		want     string
+	}{
+		{username: "*", want: `(uid=\2a)`},
+		{username: "a)(uid=*", want: `(uid=a\29\28uid=\2a)`},
+		{username: `back\slash`, want: `(uid=back\5cslash)`},
+		{username: "*)(|(objectClass=*)", want: `(uid=\2a\29\28|\28objectClass=\2a\29)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			_, err := realm.Authenticate(context.Background(), tt.username, "password")
+			assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+			filters := server.seenFilters()
+			require.NotEmpty(t, filters)
+			assert.Equal(t, tt.want, filters[len(filters)-1])
+		})
+	}
+}
+
+func TestLDAPRealm_DirectoryErrors(t *testing.T) {
+	t.Run("service_bind_rejected", func(t *testing.T) {
+		_, _, server, realm := newLDAPEnv(t, func(cfg *config.LDAPConfig) { cfg.BindPassword = "stale" })
+		server.addUser("(uid=alice)", "alice", "alice-password", map[string][]string{})
+		_, err := realm.Authenticate(context.Background(), "alice", "alice-password")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, errs.ErrUnauthenticated, "a broken service account is not a wrong user password")
+		assert.Contains(t, err.Error(), "service account")
+	})
+
+	t.Run("user_bind_unavailable", func(t *testing.T) {
+		_, _, server, realm := newLDAPEnv(t, nil)
+		entry := server.addUser("(uid=alice)", "alice", "alice-password", map[string][]string{})
+		server.mu.Lock()
+		server.failBind[entry.DN] = ldap.LDAPResultBusy
+		server.mu.Unlock()
+		_, err := realm.Authenticate(context.Background(), "alice", "alice-password")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+		env, users := newUserEnv(t)
+		cfg := &config.LDAPConfig{URL: "ldap://" + addr, Timeout: time.Second, UserFilter: "(uid={username})", UsernameAttribute: "uid"}
+		realm, err := NewLDAPRealm(cfg, env.logger, users)
+		require.NoError(t, err)
+		_, err = realm.Authenticate(context.Background(), "alice", "alice-password")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to connect")
+	})
+
+	t.Run("chain_reports_unavailable", func(t *testing.T) {
+		env, users, server, _ := newLDAPEnv(t, nil)
+		server.listener.Close()
+		env.cfg.Security.Realms = []string{model.UserRealmLocal, model.UserRealmLDAP}
+		env.cfg.Security.LDAP = *testLDAPConfig(server)
+		chain, err := NewRealmChain(env.cfg, env.logger, users)
+		require.NoError(t, err)
+		_, err = chain.Authenticate(context.Background(), "alice", "alice-password")
+		assert.ErrorIs(t, err, errs.ErrUnavailable)
+	})
+}
+
+func TestLDAPRealm_GroupMappings(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *config.LDAPConfig)
+		memberOf  []string
+		groups    []*ldap.Entry
+		want      []string
+	}{
+		{
+			name:     "member_of_by_name",
+			memberOf: []string{"CN=Developers,OU=Groups,DC=example,DC=com"},
+			configure: func(cfg *config.LDAPConfig) {
+				cfg.GroupMappings = []config.GroupMapping{{Group: "developers", Roles: []string{model.RoleDeveloper}}}
+			},
+			want: []string{model.RoleDeveloper},
+		},
+		{
+			name:     "member_of_by_dn_case_insensitive",
+			memberOf: []string{"CN=Admins,OU=Groups,DC=example,DC=com"},
+			configure: func(cfg *config.LDAPConfig) {
+				cfg.GroupMappings = []config.GroupMapping{{Group: "cn=admins,ou=groups,dc=example,dc=com", Roles: []string{model.RoleAdmin}}}
+			},
+			want: []string{model.RoleAdmin},
+		},
+		{
+			name:     "dn_mapping_does_not_match_same_name_elsewhere",
+			memberOf: []string{"cn=admins,ou=contractors,dc=example,dc=com"},
+			configure: func(cfg *config.LDAPConfig) {
+				cfg.GroupMappings = []config.GroupMapping{{Group: "cn=admins,ou=groups,dc=example,dc=com", Roles: []string{model.RoleAdmin}}}
+			},
+			want: []string{},
+		},
+		{
+			name:     "invalid_member_of_ignored",
+			memberOf: []string{"not a dn", "cn=developers,ou=groups,dc=example,dc=com"},
+			configure: func(cfg *config.LDAPConfig) {
+				cfg.GroupMappings = []config.GroupMapping{{Group: "developers", Roles: []string{model.RoleDeveloper}}}
+			},
+			want: []string{model.RoleDeveloper},
+		},
+		{
+			name: "group_search",
+			configure: func(cfg *config.LDAPConfig) {
+				cfg.MemberOfAttribute = ""
+				cfg.GroupBaseDN = "ou=groups,dc=example,dc=com"
+				cfg.GroupFilter = "(member={dn})"
+				cfg.GroupNameAttribute = "cn"
+				cfg.GroupMappings = []config.GroupMapping{
+					{Group: "Release Managers", Roles: []string{model.RoleAdmin}},
+					{Group: "cn=viewers,ou=groups,dc=example,dc=com", Roles: []string{model.RoleViewer}},
+				}
+			},
+			groups: []*ldap.Entry{
+				ldap.NewEntry("cn=release,ou=groups,dc=example,dc=com", map[string][]string{"cn": {"release managers"}}),
+				ldap.NewEntry("cn=viewers,ou=groups,dc=example,dc=com", map[string][]string{}),
+			},
+			want: []string{model.RoleAdmin, model.RoleViewer},
+		},
+		{
+			name: "default_and_unknown_roles",
+			configure: func(cfg *config.LDAPConfig) {
+				cfg.DefaultRoles = []string{model.RoleViewer, "missing-role"}
+			},
+			want: []string{model.RoleViewer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, server, realm := newLDAPEnv(t, tt.configure)
+			entry := server.addUser("(uid=bob)", "bob", "bob-password", map[string][]string{"memberOf": tt.memberOf})
+			if tt.groups != nil {
+				server.addResult("(member="+entry.DN+")", tt.groups...)
+			}
+
+			user, err := realm.Authenticate(context.Background(), "bob", "bob-password")
+			require.NoError(t, err)
+			got := roleNames(user)
+			sort.Strings(got)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, "bob@ldap.localhost", user.Email, "placeholder email when the directory has none")
+		})
+	}
+}
+
+func TestLDAPRealm_Provision(t *testing.T) {
+	t.Run("syncs_on_every_login", func(t *testing.T) {
+		_, users, server, realm := newLDAPEnv(t, func(cfg *config.LDAPConfig) {
+			cfg.GroupMappings = []config.GroupMapping{{Group: "developers", Roles: []string{model.RoleDeveloper}}}
+		})
+		server.addUser("(uid=alice)", "alice", "alice-password", map[string][]string{
+			"mail":     {"alice@example.com"},
+			"cn":       {"Alice"},
+			"memberOf": {"cn=developers,ou=groups,dc=example,dc=com"},
+		})
+		first, err := realm.Authenticate(context.Background(), "alice", "alice-password")
+		require.NoError(t, err)
+		assert.Equal(t, []string{model.RoleDeveloper}, roleNames(first))
+
+		// 目录中的姓名、邮箱与组变化后再次登录
+		server.mu.Lock()
+		server.results["(uid=alice)"][0] = ldap.NewEntry(server.results["(uid=alice)"][0].DN, map[string][]string{
+			"uid":  {"alice"},
+			"mail": {"alice.liddell@example.com"},
+			"cn":   {"Alice Liddell"},
+		})
+		server.mu.Unlock()
+		second, err := realm.Authenticate(context.Background(), "alice", "alice-password")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, "alice.liddell@example.com", second.Email)
+		assert.Equal(t, "Alice Liddell", second.FullName)
+		assert.Empty(t, roleNames(second), "roles of groups the user left are removed")
+
+		list, err := users.List(context.Background(), &dto.ListUsersQuery{Q: "alice"})
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("local_account_not_taken_over", func(t *testing.T) {
+		_, users, server, realm := newLDAPEnv(t, nil)
+		local := createUser(t, users, "carol", model.RoleAdmin)
+		server.addUser("(uid=carol)", "carol", "ldap-password", map[string][]string{})
+
+		_, err := realm.Authenticate(context.Background(), "carol", "ldap-password")
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+		after, err := users.Get(context.Background(), local.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.UserRealmLocal, after.Realm)
+		assert.Equal(t, []string{model.RoleAdmin}, roleNames(after))
+	})
+
+	t.Run("email_in_use_gets_placeholder", func(t *testing.T) {
+		_, users, server, realm := newLDAPEnv(t, nil)
+		createUser(t, users, "dave")
+		server.addUser("(uid=erin)", "erin", "erin-password", map[string][]string{"mail": {"dave@example.com"}})
+
+		user, err := realm.Authenticate(context.Background(), "erin", "erin-password")
+		require.NoError(t, err)
+		assert.Equal(t, "erin@ldap.localhost", user.Email)
+	})
+
+	t.Run("unsupported_username", func(t *testing.T) {
+		_, _, server, realm := newLDAPEnv(t, nil)
+		server.addUser("(uid=frank)", "frank", "frank-password", map[string][]string{})
+		server.mu.Lock()
+		entry := server.results["(uid=frank)"][0]
+		entry.Attributes = append(entry.Attributes[:0:0], &ldap.EntryAttribute{Name: "uid", Values: []string{"frank smith"}})
+		server.mu.Unlock()
+
+		_, err := realm.Authenticate(context.Background(), "frank", "frank-password")
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+
+	t.Run("locked_user", func(t *testing.T) {
+		_, users, server, realm := newLDAPEnv(t, nil)
+		server.addUser("(uid=grace)", "grace", "grace-password", map[string][]string{})
+		user, err := realm.Authenticate(context.Background(), "grace", "grace-password")
+		require.NoError(t, err)
+		status := model.UserStatusLocked
+		_, err = users.Update(context.Background(), user.ID, &dto.UpdateUserRequest{Status: &status})
+		require.NoError(t, err)
+
+		_, err = realm.Authenticate(context.Background(), "grace", "grace-password")
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+
+	t.Run("local_password_rejected_for_ldap_user", func(t *testing.T) {
+		_, users, server, realm := newLDAPEnv(t, nil)
+		server.addUser("(uid=heidi)", "heidi", "heidi-password", map[string][]string{})
+		_, err := realm.Authenticate(context.Background(), "heidi", "heidi-password")
+		require.NoError(t, err)
+
+		_, err = users.Authenticate(context.Background(), "heidi", "heidi-password")
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated, "the local realm does not authenticate directory users")
+	})
+}
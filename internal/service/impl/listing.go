@@ -0,0 +1,262 @@
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// 制品列表每页数量
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 1000
+)
+
+// propertyKeyPattern 合法的属性名
+var propertyKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// pageCursor 分页游标内容，记录排序方式以拒绝与当前查询不匹配的游标
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// List 分页查询仓库中的制品
+func (s *ArtifactServiceImpl) List(ctx context.Context, repoIDOrName string, query *dto.ListArtifactsQuery) (*dto.ArtifactPage, error) {
+	repo, err := lookupRepository(ctx, s.repositories, repoIDOrName)
+	if err != nil {
+		return nil, err
+	}
+	repositoryIDs, err := s.leafRepositoryIDs(ctx, repo, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := buildArtifactFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	filter.RepositoryIDs = repositoryIDs
+	return s.listPage(ctx, filter)
+}
+
+// listPage 多查一条记录判断是否还有下一页，并生成下一页游标
+func (s *ArtifactServiceImpl) listPage(ctx context.Context, filter *model.ArtifactFilter) (*dto.ArtifactPage, error) {
+	return listArtifactPage(ctx, s.repository, filter, nil)
+}
+
+// listArtifactPage 分页查询制品，keep 不为空时对查询结果做进一步过滤（如版本范围），
+// 过滤后不足一页时继续向后查询，直到凑满一页或没有更多记录
+func listArtifactPage(ctx context.Context, artifacts repository.ArtifactRepository, filter *model.ArtifactFilter, keep func(*model.Artifact) bool) (*dto.ArtifactPage, error) {
+	limit := filter.Limit
+	batch := *filter
+	batch.Limit = limit + 1
+
+	var items []*model.Artifact
+	for {
+		records, err := artifacts.List(ctx, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		for _, record := range records {
+			if keep == nil || keep(record) {
+				items = append(items, record)
+			}
+		}
+		if len(items) > limit || len(records) < batch.Limit {
+			break
+		}
+		batch.After = cursorOf(&batch, records[len(records)-1])
+	}
+
+	page := &dto.ArtifactPage{Items: items, Limit: limit}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+		page.NextCursor = encodeCursor(filter, page.Items[limit-1])
+	}
+	if page.Items == nil {
+		page.Items = []*model.Artifact{}
+	}
+	return page, nil
+}
+
+// leafRepositoryIDs 返回实际存放制品的仓库ID，组仓库展开为所有成员
+func (s *ArtifactServiceImpl) leafRepositoryIDs(ctx context.Context, repo *model.Repository, visited map[string]bool) ([]string, error) {
+	leaves, err := s.leafRepositories(ctx, repo, visited)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(leaves))
+	for _, leaf := range leaves {
+		ids = append(ids, leaf.ID)
+	}
+	return ids, nil
+}
+
+// leafRepositories 返回实际存放制品的仓库，组仓库展开为所有成员
+func (s *ArtifactServiceImpl) leafRepositories(ctx context.Context, repo *model.Repository, visited map[string]bool) ([]*model.Repository, error) {
+	if visited[repo.ID] {
+		return nil, nil
+	}
+	visited[repo.ID] = true
+	if repo.Type != model.RepositoryTypeGroup {
+		return []*model.Repository{repo}, nil
+	}
+
+	members, err := s.groupMembers(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	var leaves []*model.Repository
+	for _, member := range members {
+		memberLeaves, err := s.leafRepositories(ctx, member, visited)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, memberLeaves...)
+	}
+	return leaves, nil
+}
+
+// buildArtifactFilter 将列表查询参数转换为持久层查询条件
+func buildArtifactFilter(query *dto.ListArtifactsQuery) (*model.ArtifactFilter, error) {
+	properties, propertyKeys, err := parsePropertyFilters(query.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &model.ArtifactFilter{
+		Name:          query.Name,
+		Version:       query.Version,
+		Format:        query.Format,
+		PathPrefix:    strings.TrimPrefix(query.PathPrefix, "/"),
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		Properties:    properties,
+		PropertyKeys:  propertyKeys,
+		Sort:          strings.TrimPrefix(query.Sort, "-"),
+		Desc:          strings.HasPrefix(query.Sort, "-"),
+		Limit:         query.Limit,
+	}
+	if filter.Sort == "" {
+		filter.Sort = model.ArtifactSortCreatedAt
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+	if query.Cursor != "" {
+		if filter.After, err = decodeCursor(query.Cursor, filter); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// parsePropertyFilters 解析属性过滤条件：key=value 匹配属性值，单独的 key 表示属性存在
+func parsePropertyFilters(values []string) (map[string]string, []string, error) {
+	if len(values) == 0 {
+		return nil, nil, nil
+	}
+	properties := make(map[string]string, len(values))
+	var keys []string
+	for _, v := range values {
+		if key, value, ok := strings.Cut(v, "="); ok {
+			properties[key] = value
+		} else {
+			keys = append(keys, v)
+		}
+	}
+	if err := validatePropertyKeys(properties); err != nil {
+		return nil, nil, err
+	}
+	for _, key := range keys {
+		if !propertyKeyPattern.MatchString(key) {
+			return nil, nil, errs.InvalidArgument("invalid property key %q", key)
+		}
+	}
+	return properties, keys, nil
+}
+
+// validatePropertyKeys 校验属性名，属性名会用于 JSON 路径查询
+func validatePropertyKeys(properties map[string]string) error {
+	for key := range properties {
+		if !propertyKeyPattern.MatchString(key) {
+			return errs.InvalidArgument("invalid property key %q", key)
+		}
+	}
+	return nil
+}
+
+// encodeCursor 以记录的排序值与ID生成游标
+func encodeCursor(filter *model.ArtifactFilter, last *model.Artifact) string {
+	cursor := pageCursor{Sort: filter.Sort, Desc: filter.Desc, ID: last.ID}
+	switch after := cursorOf(filter, last); value := after.Value.(type) {
+	case time.Time:
+		cursor.Value = value.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = fmt.Sprint(value)
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// cursorOf 返回从指定记录之后继续查询的位置
+func cursorOf(filter *model.ArtifactFilter, last *model.Artifact) *model.ArtifactCursor {
+	switch filter.Sort {
+	case model.ArtifactSortSize:
+		return &model.ArtifactCursor{Value: last.Size, ID: last.ID}
+	case model.ArtifactSortDownloadCount:
+		return &model.ArtifactCursor{Value: last.DownloadCount, ID: last.ID}
+	default:
+		return &model.ArtifactCursor{Value: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// decodeCursor 解析游标，游标必须与当前排序方式一致
+func decodeCursor(raw string, filter *model.ArtifactFilter) (*model.ArtifactCursor, error) {
+	invalid := errs.InvalidArgument("invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, invalid
+	}
+	if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+		return nil, errs.InvalidArgument("cursor does not match the requested sort order")
+	}
+
+	after := &model.ArtifactCursor{ID: cursor.ID}
+	switch cursor.Sort {
+	case model.ArtifactSortSize, model.ArtifactSortDownloadCount:
+		var n int64
+		if _, err := fmt.Sscan(cursor.Value, &n); err != nil {
+			return nil, invalid
+		}
+		after.Value = n
+	default:
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, invalid
+		}
+		after.Value = t
+	}
+	return after, nil
+}
@@ -0,0 +1,167 @@
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	last := &model.Artifact{ID: "a1", Size: 42, DownloadCount: 7, CreatedAt: created}
+	tests := []struct {
+		sort string
+		want interface{}
+	}{
+		{model.ArtifactSortCreatedAt, created},
+		{model.ArtifactSortSize, int64(42)},
+		{model.ArtifactSortDownloadCount, int64(7)},
+	}
+	for _, tt := range tests {
+		for _, desc := range []bool{false, true} {
+			filter := &model.ArtifactFilter{Sort: tt.sort, Desc: desc}
+			after, err := decodeCursor(encodeCursor(filter, last), filter)
+			require.NoError(t, err, tt.sort)
+			assert.Equal(t, "a1", after.ID)
+			if want, ok := tt.want.(time.Time); ok {
+				assert.True(t, want.Equal(after.Value.(time.Time)), "nanosecond precision is kept")
+			} else {
+				assert.Equal(t, tt.want, after.Value)
+			}
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	filter := &model.ArtifactFilter{Sort: model.ArtifactSortSize}
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "not_base64", raw: "!!!"},
+		{name: "not_json", raw: encode("nope")},
+		{name: "missing_id", raw: encode(`{"s":"size","v":"1"}`)},
+		{name: "sort_mismatch", raw: encode(`{"s":"created_at","v":"2025-01-01T00:00:00Z","id":"a"}`)},
+		{name: "direction_mismatch", raw: encode(`{"s":"size","d":true,"v":"1","id":"a"}`)},
+		{name: "bad_number", raw: encode(`{"s":"size","v":"x","id":"a"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.raw, filter)
+			assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+		})
+	}
+
+	_, err := decodeCursor(encode(`{"s":"created_at","v":"yesterday","id":"a"}`), &model.ArtifactFilter{Sort: model.ArtifactSortCreatedAt})
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+}
+
+func TestBuildArtifactFilter(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		filter, err := buildArtifactFilter(&dto.ListArtifactsQuery{PathPrefix: "/com/x"})
+		require.NoError(t, err)
+		assert.Equal(t, model.ArtifactSortCreatedAt, filter.Sort)
+		assert.False(t, filter.Desc)
+		assert.Equal(t, defaultPageLimit, filter.Limit)
+		assert.Equal(t, "com/x", filter.PathPrefix)
+	})
+	t.Run("descending_and_clamped", func(t *testing.T) {
+		filter, err := buildArtifactFilter(&dto.ListArtifactsQuery{Sort: "-size", Limit: maxPageLimit + 1})
+		require.NoError(t, err)
+		assert.Equal(t, model.ArtifactSortSize, filter.Sort)
+		assert.True(t, filter.Desc)
+		assert.Equal(t, maxPageLimit, filter.Limit)
+	})
+	t.Run("properties", func(t *testing.T) {
+		filter, err := buildArtifactFilter(&dto.ListArtifactsQuery{Properties: []string{"team=core", "approved", "note=a=b"}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "core", "note": "a=b"}, filter.Properties)
+		assert.Equal(t, []string{"approved"}, filter.PropertyKeys)
+	})
+	t.Run("invalid_property_key", func(t *testing.T) {
+		for _, value := range []string{"bad key=1", "$.x", "-leading"} {
+			_, err := buildArtifactFilter(&dto.ListArtifactsQuery{Properties: []string{value}})
+			assert.ErrorIs(t, err, errs.ErrInvalidArgument, value)
+		}
+	})
+	t.Run("invalid_cursor", func(t *testing.T) {
+		_, err := buildArtifactFilter(&dto.ListArtifactsQuery{Cursor: "garbage"})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+}
+
+func TestArtifactService_List(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	env.hosted(t, "snapshots", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"releases", "snapshots"}})
+	ctx := context.Background()
+
+	for i, p := range []string{"com/x/a/1.0/a-1.0.jar", "com/x/b/1.0/b-1.0.jar", "org/y/c/1.0/c-1.0.jar"} {
+		_, err := env.artifacts.Upload(ctx, "releases", &dto.UploadArtifactRequest{
+			Path: p, Data: make([]byte, (i+1)*10), Properties: map[string]string{"team": []string{"core", "web", "core"}[i]},
+		})
+		require.NoError(t, err)
+	}
+	env.upload(t, "snapshots", "com/x/a/2.0-SNAPSHOT/a-2.0-SNAPSHOT.jar", make([]byte, 5))
+
+	paths := func(page *dto.ArtifactPage) []string {
+		var out []string
+		for _, item := range page.Items {
+			out = append(out, item.Path)
+		}
+		return out
+	}
+
+	t.Run("pages_by_size_desc", func(t *testing.T) {
+		var got []string
+		query := &dto.ListArtifactsQuery{Sort: "-size", Limit: 2, PathPrefix: "com/x/"}
+		for {
+			page, err := env.artifacts.List(ctx, "public", query)
+			require.NoError(t, err)
+			got = append(got, paths(page)...)
+			if !page.HasMore {
+				assert.Empty(t, page.NextCursor)
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		assert.Equal(t, []string{"com/x/b/1.0/b-1.0.jar", "com/x/a/1.0/a-1.0.jar", "com/x/a/2.0-SNAPSHOT/a-2.0-SNAPSHOT.jar"}, got)
+	})
+
+	tests := []struct {
+		name  string
+		repo  string
+		query *dto.ListArtifactsQuery
+		want  []string
+	}{
+		{name: "hosted_only", repo: "snapshots", query: &dto.ListArtifactsQuery{}, want: []string{"com/x/a/2.0-SNAPSHOT/a-2.0-SNAPSHOT.jar"}},
+		{name: "property_value", repo: "public", query: &dto.ListArtifactsQuery{Properties: []string{"team=core"}, Sort: "size"},
+			want: []string{"com/x/a/1.0/a-1.0.jar", "org/y/c/1.0/c-1.0.jar"}},
+		{name: "property_exists", repo: "public", query: &dto.ListArtifactsQuery{Properties: []string{"team"}, Sort: "size"},
+			want: []string{"com/x/a/1.0/a-1.0.jar", "com/x/b/1.0/b-1.0.jar", "org/y/c/1.0/c-1.0.jar"}},
+		{name: "name_wildcard", repo: "public", query: &dto.ListArtifactsQuery{Name: "a*", Version: "1.*"}, want: []string{"com/x/a/1.0/a-1.0.jar"}},
+		{name: "created_in_future", repo: "public", query: &dto.ListArtifactsQuery{CreatedAfter: timePtr(time.Now().Add(time.Hour))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := env.artifacts.List(ctx, tt.repo, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, paths(page))
+			assert.NotNil(t, page.Items, "empty pages serialise as []")
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
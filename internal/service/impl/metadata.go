@@ -0,0 +1,156 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/version"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// 制品记录中元数据字段的键
+const (
+	metadataGroupID     = "group_id"
+	metadataArtifactID  = "artifact_id"
+	metadataName        = "name"
+	metadataDescription = "description"
+	metadataPackaging   = "packaging"
+	metadataKeywords    = "keywords"
+)
+
+// rebuildMetadata 根据仓库中的制品记录重新生成宿主仓库的元数据文件
+// 没有制品引用该元数据文件时删除它
+func (s *ArtifactServiceImpl) rebuildMetadata(ctx context.Context, repo *model.Repository, metadataPath string) error {
+	formatPlugin, err := s.plugins.GetFormatPlugin(repo.Format)
+	if err != nil {
+		return err
+	}
+	locator, ok := formatPlugin.(pluginapi.ArtifactLocator)
+	if !ok {
+		return nil
+	}
+
+	s.metadataMutex.Lock()
+	defer s.metadataMutex.Unlock()
+
+	records, err := s.repository.ListByRepository(ctx, repo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	var artifacts []*pluginapi.Artifact
+	for _, record := range records {
+		if target, ok := locator.MetadataPath(record.Path); ok && target == metadataPath {
+			artifacts = append(artifacts, toPluginArtifact(record))
+		}
+	}
+
+	// 插件要求制品按版本从低到高排列，上传顺序不代表版本顺序
+	compare := version.ForFormat(repo.Format).Compare
+	sort.SliceStable(artifacts, func(i, j int) bool { return compare(artifacts[i].Version, artifacts[j].Version) < 0 })
+
+	location := storagePath(repo, metadataPath)
+	if len(artifacts) == 0 {
+		if err := s.storage.Delete(ctx, location); err != nil {
+			return fmt.Errorf("failed to delete metadata: %w", err)
+		}
+		if err := s.storage.Delete(ctx, location+metadataSignatureExtension); err != nil {
+			return fmt.Errorf("failed to delete metadata signature: %w", err)
+		}
+		return nil
+	}
+
+	data, err := formatPlugin.GenerateMetadata(ctx, artifacts)
+	if err != nil {
+		return fmt.Errorf("failed to generate metadata: %w", err)
+	}
+	if err := s.storage.Upload(ctx, location, data); err != nil {
+		return fmt.Errorf("failed to store metadata: %w", err)
+	}
+	return s.signMetadata(ctx, repo, location, data)
+}
+
+// signMetadata 元数据生成后的签名钩子，为仓库开启了签名的元数据文件写入分离签名 <文件>.asc
+// 未开启签名、没有启用的签名密钥或签名失败时删除旧的签名文件，避免留下与内容不一致的签名
+func (s *ArtifactServiceImpl) signMetadata(ctx context.Context, repo *model.Repository, location string, data []byte) error {
+	sigLocation := location + metadataSignatureExtension
+	var (
+		signature []byte
+		signErr   error
+	)
+	if repo.SignMetadata {
+		var key *model.SigningKey
+		if signature, key, signErr = s.signer.Sign(ctx, data); signErr == nil && key == nil {
+			s.logger.Warn("Repository signs metadata but no signing key is active", "repository", repo.Name)
+		}
+	}
+	if signature == nil {
+		if err := s.storage.Delete(ctx, sigLocation); err != nil {
+			return fmt.Errorf("failed to delete metadata signature: %w", err)
+		}
+		if signErr != nil {
+			return fmt.Errorf("failed to sign metadata: %w", signErr)
+		}
+		return nil
+	}
+	if err := s.storage.Upload(ctx, sigLocation, signature); err != nil {
+		return fmt.Errorf("failed to store metadata signature: %w", err)
+	}
+	return nil
+}
+
+// toPluginArtifact 将制品记录转换为插件接口使用的制品信息
+func toPluginArtifact(a *model.Artifact) *pluginapi.Artifact {
+	return &pluginapi.Artifact{
+		ID:           a.ID,
+		RepositoryID: a.RepositoryID,
+		Path:         a.Path,
+		Name:         a.Name,
+		Version:      a.Version,
+		Format:       a.Format,
+		Size:         a.Size,
+		Checksum:     a.Checksum,
+		Metadata:     expandMetadata(a.Metadata),
+		Properties:   a.Properties,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+// flattenMetadata 将插件解析出的元数据展开为制品记录中的键值对
+func flattenMetadata(m *pluginapi.Metadata) map[string]string {
+	values := map[string]string{
+		metadataGroupID:     m.GroupID,
+		metadataArtifactID:  m.ArtifactID,
+		metadataName:        m.Name,
+		metadataDescription: m.Description,
+		metadataPackaging:   m.Packaging,
+		metadataKeywords:    strings.Join(m.Keywords, ","),
+	}
+	for k, v := range values {
+		if v == "" {
+			delete(values, k)
+		}
+	}
+	return values
+}
+
+// expandMetadata 将制品记录中的键值对还原为插件元数据
+func expandMetadata(values map[string]string) *pluginapi.Metadata {
+	if len(values) == 0 {
+		return nil
+	}
+	m := &pluginapi.Metadata{
+		GroupID:     values[metadataGroupID],
+		ArtifactID:  values[metadataArtifactID],
+		Name:        values[metadataName],
+		Description: values[metadataDescription],
+		Packaging:   values[metadataPackaging],
+	}
+	if keywords := values[metadataKeywords]; keywords != "" {
+		m.Keywords = strings.Split(keywords, ",")
+	}
+	return m
+}
@@ -0,0 +1,210 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// metadataSignatureExtension 元数据签名文件的扩展名
+const metadataSignatureExtension = ".asc"
+
+// MetadataSigner 使用启用的签名密钥为生成的元数据文件签名
+// 私钥以 security.signing.secret 派生的 AES-256-GCM 密钥加密保存，解密后的密钥缓存在内存中
+type MetadataSigner struct {
+	logger *slog.Logger
+	keys   repository.SigningKeyRepository
+	secret []byte // 为 nil 时未配置主密钥
+
+	mu     sync.Mutex
+	cached *openpgp.Entity
+	key    *model.SigningKey // cached 对应的密钥记录
+}
+
+// NewMetadataSigner 创建元数据签名器
+func NewMetadataSigner(cfg *config.Config, logger *slog.Logger, keys repository.SigningKeyRepository) *MetadataSigner {
+	signer := &MetadataSigner{logger: logger, keys: keys}
+	if secret := cfg.Security.Signing.Secret; secret != "" {
+		sum := sha256.Sum256([]byte(secret))
+		signer.secret = sum[:]
+	}
+	return signer
+}
+
+// Sign 生成 ASCII 封装的分离签名，没有启用的签名密钥时返回 nil
+func (s *MetadataSigner) Sign(ctx context.Context, data []byte) ([]byte, *model.SigningKey, error) {
+	entity, key, err := s.active(ctx)
+	if err != nil || entity == nil {
+		return nil, nil, err
+	}
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, entity, bytes.NewReader(data), nil); err != nil {
+		return nil, nil, fmt.Errorf("failed to sign with key %q: %w", key.Name, err)
+	}
+	return buf.Bytes(), key, nil
+}
+
+// Invalidate 签名密钥变化后清除缓存
+func (s *MetadataSigner) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cached, s.key = nil, nil
+}
+
+// active 加载并缓存启用的签名密钥
+func (s *MetadataSigner) active(ctx context.Context) (*openpgp.Entity, *model.SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := s.keys.FindActive(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+	if key == nil {
+		return nil, nil, nil
+	}
+	if s.key != nil && s.key.ID == key.ID && s.key.UpdatedAt.Equal(key.UpdatedAt) {
+		return s.cached, s.key, nil
+	}
+
+	plain, err := s.decrypt(key.PrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt signing key %q: %w", key.Name, err)
+	}
+	entity, err := openpgp.ReadEntity(packet.NewReader(bytes.NewReader(plain)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read signing key %q: %w", key.Name, err)
+	}
+	s.cached, s.key = entity, key
+	return entity, key, nil
+}
+
+// parse 解析 ASCII 封装的私钥，密钥受密码保护时用 passphrase 解密
+// 私钥必须只包含一个可用于签名的密钥
+func (s *MetadataSigner) parse(armored, passphrase string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, errs.InvalidArgument("invalid PGP private key: %v", err)
+	}
+	if len(entities) != 1 {
+		return nil, errs.InvalidArgument("PGP private key must contain exactly one key, got %d", len(entities))
+	}
+	entity := entities[0]
+	if entity.PrivateKey == nil {
+		return nil, errs.InvalidArgument("key does not contain a private key")
+	}
+	if entity.PrivateKey.Encrypted {
+		if passphrase == "" {
+			return nil, errs.InvalidArgument("private key is protected, passphrase is required")
+		}
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, errs.InvalidArgument("failed to decrypt private key: %v", err)
+		}
+	}
+	if _, ok := entity.SigningKey(time.Now()); !ok {
+		return nil, errs.InvalidArgument("key has no valid signing key")
+	}
+	return entity, nil
+}
+
+// seal 序列化解密后的私钥并加密
+func (s *MetadataSigner) seal(entity *openpgp.Entity) (string, error) {
+	if s.secret == nil {
+		return "", errs.Unavailable("security.signing.secret is not configured")
+	}
+	var plain bytes.Buffer
+	if err := entity.SerializePrivateWithoutSigning(&plain, nil); err != nil {
+		return "", fmt.Errorf("failed to serialize private key: %w", err)
+	}
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plain.Bytes(), nil)), nil
+}
+
+// decrypt 解密保存的私钥
+func (s *MetadataSigner) decrypt(sealed string) ([]byte, error) {
+	if s.secret == nil {
+		return nil, fmt.Errorf("security.signing.secret is not configured")
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := s.cipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed key is too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("security.signing.secret does not match the key: %w", err)
+	}
+	return plain, nil
+}
+
+// cipher 由主密钥创建 AES-256-GCM
+func (s *MetadataSigner) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// armoredPublicKey 导出 ASCII 封装的公钥
+func armoredPublicKey(entity *openpgp.Entity) (string, error) {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := entity.Serialize(w); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// primaryUserID 密钥的主用户 ID，没有用户 ID 时为空
+func primaryUserID(entity *openpgp.Entity) string {
+	if identity := entity.PrimaryIdentity(); identity != nil {
+		return identity.Name
+	}
+	return ""
+}
+
+// keyFingerprint 主密钥指纹与长密钥 ID
+func keyFingerprint(entity *openpgp.Entity) (string, string) {
+	fingerprint := strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))
+	return fingerprint, fmt.Sprintf("%016X", entity.PrimaryKey.KeyId)
+}
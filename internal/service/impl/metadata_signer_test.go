@@ -0,0 +1,297 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// discardLogger 丢弃输出的日志记录器
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// newPGPEntity 生成不缓存的 OpenPGP 密钥，用于需要修改密钥的测试
+func newPGPEntity(t *testing.T, name string, cfg *packet.Config) *openpgp.Entity {
+	t.Helper()
+	if cfg == nil {
+		cfg = &packet.Config{}
+	}
+	cfg.Algorithm = packet.PubKeyAlgoEdDSA
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", cfg)
+	require.NoError(t, err)
+	return entity
+}
+
+// protectedKey 以 passphrase 加密私钥后导出 ASCII 封装的私钥
+func protectedKey(t *testing.T, passphrase string) string {
+	t.Helper()
+	entity := newPGPEntity(t, "protected", nil)
+	require.NoError(t, entity.EncryptPrivateKeys([]byte(passphrase), nil))
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+// verifyPGPSignature 使用 armoredPublic 校验分离签名
+func verifyPGPSignature(t *testing.T, armoredPublic string, data, signature []byte) *openpgp.Entity {
+	t.Helper()
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredPublic))
+	require.NoError(t, err)
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	require.NoError(t, err)
+	return signer
+}
+
+// storeSigningKey 以测试环境的签名器加密 entity 并保存为签名密钥
+func storeSigningKey(t *testing.T, env *testEnv, name string, entity *openpgp.Entity, active bool) *model.SigningKey {
+	t.Helper()
+	sealed, err := env.signer.seal(entity)
+	require.NoError(t, err)
+	public, err := armoredPublicKey(entity)
+	require.NoError(t, err)
+	key := &model.SigningKey{ID: name, Name: name, PublicKey: public, PrivateKey: sealed, Active: active}
+	key.Fingerprint, key.KeyID = keyFingerprint(entity)
+	require.NoError(t, env.signingRepo.Create(context.Background(), key))
+	return key
+}
+
+func TestNewMetadataSigner(t *testing.T) {
+	cfg := &config.Config{}
+	assert.Nil(t, NewMetadataSigner(cfg, discardLogger, nil).secret)
+
+	cfg.Security.Signing.Secret = "secret"
+	assert.Len(t, NewMetadataSigner(cfg, discardLogger, nil).secret, 32, "AES-256 key")
+}
+
+func TestMetadataSigner_Parse(t *testing.T) {
+	signer := NewMetadataSigner(&config.Config{}, discardLogger, nil)
+	entity := testPGPEntity(t, "release")
+	expired := newPGPEntity(t, "expired", &packet.Config{
+		Time:            func() time.Time { return time.Now().Add(-48 * time.Hour) },
+		KeyLifetimeSecs: 60,
+	})
+	protected := protectedKey(t, "s3cret")
+
+	tests := []struct {
+		name       string
+		armored    string
+		passphrase string
+		wantErr    string
+	}{
+		{name: "private_key", armored: armoredEntities(t, true, entity)},
+		{name: "protected_key", armored: protected, passphrase: "s3cret"},
+		{name: "passphrase_ignored_for_plain_key", armored: armoredEntities(t, true, entity), passphrase: "unused"},
+		{name: "protected_without_passphrase", armored: protected, wantErr: "passphrase is required"},
+		{name: "wrong_passphrase", armored: protected, passphrase: "wrong", wantErr: "failed to decrypt private key"},
+		{name: "public_key", armored: armoredEntities(t, false, entity), wantErr: "does not contain a private key"},
+		{name: "two_keys", armored: armoredEntities(t, true, entity, testPGPEntity(t, "other")), wantErr: "exactly one key, got 2"},
+		{name: "expired_key", armored: armoredEntities(t, true, expired), wantErr: "no valid signing key"},
+		{name: "garbage", armored: "not a key", wantErr: "invalid PGP private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := signer.parse(tt.armored, tt.passphrase)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.False(t, parsed.PrivateKey.Encrypted)
+		})
+	}
+}
+
+func TestMetadataSigner_SealAndDecrypt(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Security.Signing.Secret = "secret"
+	signer := NewMetadataSigner(cfg, discardLogger, nil)
+	entity := testPGPEntity(t, "release")
+
+	sealed, err := signer.seal(entity)
+	require.NoError(t, err)
+	assert.NotContains(t, sealed, "PRIVATE KEY")
+	other, err := signer.seal(entity)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, other, "every seal uses a fresh nonce")
+
+	plain, err := signer.decrypt(sealed)
+	require.NoError(t, err)
+	restored, err := openpgp.ReadEntity(packet.NewReader(bytes.NewReader(plain)))
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrimaryKey.Fingerprint, restored.PrimaryKey.Fingerprint)
+	assert.NotNil(t, restored.PrivateKey)
+
+	cfg.Security.Signing.Secret = "rotated"
+	_, err = NewMetadataSigner(cfg, discardLogger, nil).decrypt(sealed)
+	assert.ErrorContains(t, err, "does not match the key")
+
+	tests := []struct {
+		name    string
+		sealed  string
+		wantErr string
+	}{
+		{name: "not_base64", sealed: "!!", wantErr: "illegal base64"},
+		{name: "too_short", sealed: base64.StdEncoding.EncodeToString([]byte("short")), wantErr: "too short"},
+		{name: "tampered", sealed: base64.StdEncoding.EncodeToString(append([]byte(strings.Repeat("n", 12)), "ciphertext"...)), wantErr: "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.decrypt(tt.sealed)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("secret_not_configured", func(t *testing.T) {
+		unconfigured := NewMetadataSigner(&config.Config{}, discardLogger, nil)
+		_, err := unconfigured.seal(entity)
+		assert.ErrorIs(t, err, errs.ErrUnavailable)
+		_, err = unconfigured.decrypt(sealed)
+		assert.ErrorContains(t, err, "security.signing.secret is not configured")
+	})
+}
+
+func TestMetadataSigner_Sign(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	data := []byte("<metadata/>")
+
+	signature, key, err := env.signer.Sign(ctx, data)
+	require.NoError(t, err)
+	assert.Nil(t, signature, "no active key")
+	assert.Nil(t, key)
+
+	release := storeSigningKey(t, env, "release", testPGPEntity(t, "release"), true)
+	signature, key, err = env.signer.Sign(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, "release", key.Name)
+	signer := verifyPGPSignature(t, release.PublicKey, data, signature)
+	assert.Equal(t, testPGPEntity(t, "release").PrimaryKey.Fingerprint, signer.PrimaryKey.Fingerprint)
+	cached := env.signer.cached
+
+	_, _, err = env.signer.Sign(ctx, data)
+	require.NoError(t, err)
+	assert.Same(t, cached, env.signer.cached, "the decrypted key is cached")
+
+	// 更换启用的密钥后使用新密钥
+	release.Active = false
+	require.NoError(t, env.signingRepo.Update(ctx, release))
+	ops := storeSigningKey(t, env, "ops", testPGPEntity(t, "ops"), true)
+	signature, key, err = env.signer.Sign(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, "ops", key.Name)
+	verifyPGPSignature(t, ops.PublicKey, data, signature)
+
+	env.signer.Invalidate()
+	assert.Nil(t, env.signer.cached)
+
+	// 启用的密钥无法解密或解析时返回错误
+	for name, sealed := range map[string]string{
+		"undecryptable": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 40))),
+		"unreadable":    mustSeal(t, env.signer, []byte("not a key")),
+	} {
+		ops.PrivateKey = sealed
+		require.NoError(t, env.signingRepo.Update(ctx, ops))
+		_, _, err = env.signer.Sign(ctx, data)
+		assert.ErrorContains(t, err, `signing key "ops"`, name)
+	}
+}
+
+// mustSeal 以签名器的主密钥加密任意内容
+func mustSeal(t *testing.T, signer *MetadataSigner, plain []byte) string {
+	t.Helper()
+	gcm, err := signer.cipher()
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plain, nil))
+}
+
+func TestKeyHelpers(t *testing.T) {
+	entity := testPGPEntity(t, "release")
+	assert.Equal(t, "release <release@example.com>", primaryUserID(entity))
+
+	fingerprint, keyID := keyFingerprint(entity)
+	assert.Len(t, fingerprint, 40)
+	assert.Len(t, keyID, 16)
+	assert.True(t, strings.HasSuffix(fingerprint, keyID), "the long key ID is the fingerprint suffix for v4 keys")
+
+	public, err := armoredPublicKey(entity)
+	require.NoError(t, err)
+	assert.Contains(t, public, "BEGIN PGP PUBLIC KEY BLOCK")
+	assert.NotContains(t, public, "PRIVATE")
+	assert.Equal(t, strings.ToUpper(fingerprint), mustParseKey(t, &model.TrustedKey{Type: model.TrustedKeyTypePGP, PublicKey: public}).fingerprint)
+}
+
+func TestArtifactService_SignMetadata(t *testing.T) {
+	const (
+		jar      = "com/x/lib/1.0/lib-1.0.jar"
+		metadata = "com/x/lib/maven-metadata.xml"
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		sign   bool
+		key    bool // 启用 release 签名密钥
+		broken bool // 启用的签名密钥无法解密
+		signed bool
+	}{
+		{name: "signed", sign: true, key: true, signed: true},
+		{name: "signing_disabled", key: true},
+		{name: "no_active_key", sign: true},
+		{name: "signing_fails", sign: true, key: true, broken: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			if tt.key {
+				key := storeSigningKey(t, env, "release", testPGPEntity(t, "release"), true)
+				if tt.broken {
+					key.PrivateKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 40)))
+					require.NoError(t, env.signingRepo.Update(ctx, key))
+				}
+			}
+			// 签名文件在仓库开启签名前已存在，生成元数据时按当前配置更新或删除
+			repo := env.hosted(t, "libs", "maven")
+			require.NoError(t, env.storage.Upload(ctx, storagePath(repo, metadata+".asc"), []byte("stale")))
+			_, err := env.repositories.Update(ctx, "libs", &dto.UpdateRepositoryRequest{SignMetadata: tt.sign})
+			require.NoError(t, err)
+
+			env.upload(t, "libs", jar, []byte("jar"))
+			data, err := env.download("libs", metadata)
+			require.NoError(t, err)
+			signature, sigErr := env.download("libs", metadata+".asc")
+			if !tt.signed {
+				assert.ErrorIs(t, sigErr, errs.ErrNotFound, "no stale signature is left behind")
+				return
+			}
+			require.NoError(t, sigErr)
+			public, err := armoredPublicKey(testPGPEntity(t, "release"))
+			require.NoError(t, err)
+			verifyPGPSignature(t, public, data, signature)
+
+			// 删除最后一个制品时元数据与签名一起删除
+			require.NoError(t, env.artifacts.Delete(ctx, "libs", jar))
+			_, err = env.download("libs", metadata+".asc")
+			assert.ErrorIs(t, err, errs.ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,559 @@
+package impl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	// jwksRefreshInterval 遇到未知 kid 时重新获取 JWKS 的最小间隔，避免伪造的令牌导致频繁请求签发者
+	jwksRefreshInterval = time.Minute
+	// jwksMaxAge JWKS 缓存的最长时间，签发者轮换密钥后按时更新
+	jwksMaxAge = time.Hour
+	// oidcClockSkew 校验令牌有效期时允许的时钟偏差
+	oidcClockSkew = time.Minute
+	// oidcMaxResponseSize 签发者响应的最大长度
+	oidcMaxResponseSize = 1 << 20
+)
+
+// oidcSigningMethods 接受的 ID 令牌与工作负载令牌签名算法，只接受非对称算法
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OIDCRealm OpenID Connect 认证域
+// 浏览器通过授权码与 PKCE 登录，ID 令牌使用签发者的 JWKS 校验，首次登录时创建本地用户并按组声明同步角色；
+// 同时接受已配置的 CI 工作负载身份签发的令牌作为 API 凭据
+type OIDCRealm struct {
+	cfg       *config.OIDCConfig
+	logger    *slog.Logger
+	users     *UserServiceImpl
+	sso       *oidcProvider
+	providers map[string]*oidcProvider
+	workloads []oidcWorkload
+}
+
+// oidcWorkload 解析后的 CI 工作负载身份
+type oidcWorkload struct {
+	*config.OIDCWorkload
+	subject *regexp.Regexp
+}
+
+// NewOIDCRealm 创建 OIDC 认证域，未配置签发者与工作负载身份时不启用
+func NewOIDCRealm(cfg *config.Config, logger *slog.Logger, users *UserServiceImpl) (*OIDCRealm, error) {
+	oidc := &cfg.Security.OIDC
+	client := &http.Client{Timeout: oidc.Timeout}
+	r := &OIDCRealm{
+		cfg:       oidc,
+		logger:    logger,
+		users:     users,
+		providers: make(map[string]*oidcProvider),
+	}
+	provider := func(issuer string) (*oidcProvider, error) {
+		if u, err := url.Parse(issuer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("invalid OIDC issuer %q", issuer)
+		}
+		if p, ok := r.providers[issuer]; ok {
+			return p, nil
+		}
+		p := &oidcProvider{issuer: issuer, client: client}
+		r.providers[issuer] = p
+		return p, nil
+	}
+
+	var err error
+	if oidc.Issuer != "" {
+		if r.sso, err = provider(oidc.Issuer); err != nil {
+			return nil, err
+		}
+		if oidc.UsernameClaim == "" {
+			return nil, fmt.Errorf("security.oidc.username_claim is required")
+		}
+	}
+	for i := range oidc.Workloads {
+		workload := &oidc.Workloads[i]
+		if _, err := provider(workload.Issuer); err != nil {
+			return nil, err
+		}
+		pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(workload.Subject), `\*`, ".*") + "$"
+		r.workloads = append(r.workloads, oidcWorkload{OIDCWorkload: workload, subject: regexp.MustCompile(pattern)})
+	}
+	return r, nil
+}
+
+// Enabled 是否启用单点登录
+func (r *OIDCRealm) Enabled() bool {
+	return r.sso != nil
+}
+
+// TrustsIssuer 判断是否接受该签发者的工作负载令牌
+func (r *OIDCRealm) TrustsIssuer(issuer string) bool {
+	for _, workload := range r.workloads {
+		if workload.Issuer == issuer {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthCodeURL 构造授权地址，使用 S256 PKCE
+func (r *OIDCRealm) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	if !r.Enabled() {
+		return "", errs.NotFound("OIDC login is not enabled")
+	}
+	metadata, err := r.sso.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {r.cfg.ClientID},
+		"redirect_uri":          {r.cfg.RedirectURL},
+		"scope":                 {strings.Join(r.scopes(), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange 使用授权码与 PKCE 校验码换取 ID 令牌，校验后创建或同步本地用户
+func (r *OIDCRealm) Exchange(ctx context.Context, code, verifier, nonce string) (*model.User, error) {
+	if !r.Enabled() {
+		return nil, errs.NotFound("OIDC login is not enabled")
+	}
+	metadata, err := r.sso.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {r.cfg.RedirectURL},
+		"client_id":     {r.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OIDC token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if r.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(r.cfg.ClientID), url.QueryEscape(r.cfg.ClientSecret))
+	}
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := r.sso.do(req, &token)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || token.IDToken == "" {
+		if token.Error != "" {
+			return nil, errs.Unauthenticated("OIDC token exchange failed: %s %s", token.Error, token.ErrorDescription)
+		}
+		return nil, errs.Unauthenticated("OIDC token exchange failed with status %d", status)
+	}
+
+	claims, err := r.sso.verify(ctx, token.IDToken, r.cfg.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, errs.Unauthenticated("invalid ID token nonce")
+	}
+	ext := &externalUser{
+		Realm:    model.UserRealmOIDC,
+		Username: claimString(claims, r.cfg.UsernameClaim),
+		Email:    claimString(claims, r.cfg.EmailClaim),
+		FullName: claimString(claims, r.cfg.NameClaim),
+		Roles:    r.mapRoles(claimStrings(claims, r.cfg.GroupsClaim)),
+	}
+	if ext.Username == "" {
+		return nil, errs.Forbidden("ID token has no %s claim", r.cfg.UsernameClaim)
+	}
+	r.logger.Debug("OIDC user authenticated", "username", ext.Username, "subject", claims["sub"], "roles", ext.Roles)
+	return r.users.provision(ctx, ext)
+}
+
+// AuthenticateWorkload 校验 CI 工作负载身份签发的令牌，返回匹配的工作负载身份所对应的本地用户
+func (r *OIDCRealm) AuthenticateWorkload(ctx context.Context, token, issuer string) (*model.User, *config.OIDCWorkload, jwt.MapClaims, error) {
+	provider, ok := r.providers[issuer]
+	if !ok || !r.TrustsIssuer(issuer) {
+		return nil, nil, nil, errs.Unauthenticated("invalid token")
+	}
+	claims, err := provider.verify(ctx, token, "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	subject, _ := claims.GetSubject()
+	audiences, _ := claims.GetAudience()
+	for _, workload := range r.workloads {
+		if workload.Issuer != issuer || !workload.subject.MatchString(subject) || !containsString(audiences, workload.Audience) {
+			continue
+		}
+		user, err := r.users.users.FindByUsername(ctx, workload.User)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if user == nil {
+			r.logger.Warn("OIDC workload user not found", "issuer", issuer, "subject", subject, "user", workload.User)
+			return nil, nil, nil, errs.Unauthenticated("invalid token")
+		}
+		if err := checkUserStatus(user); err != nil {
+			return nil, nil, nil, err
+		}
+		return user, workload.OIDCWorkload, claims, nil
+	}
+	r.logger.Warn("OIDC workload token does not match any workload identity", "issuer", issuer, "subject", subject, "audience", audiences)
+	return nil, nil, nil, errs.Unauthenticated("token does not match any workload identity")
+}
+
+// scopes 授权请求的范围，总是包含 openid
+func (r *OIDCRealm) scopes() []string {
+	scopes := []string{"openid"}
+	for _, scope := range r.cfg.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// mapRoles 按组映射计算角色，组名不区分大小写，所有用户都拥有 default_roles
+func (r *OIDCRealm) mapRoles(groups []string) []string {
+	roles := append([]string{}, r.cfg.DefaultRoles...)
+	for _, mapping := range r.cfg.GroupMappings {
+		for _, group := range groups {
+			if strings.EqualFold(strings.TrimSpace(mapping.Group), group) {
+				roles = append(roles, mapping.Roles...)
+				break
+			}
+		}
+	}
+	return roles
+}
+
+// oidcProvider OIDC 签发者，缓存发现文档与 JWKS
+type oidcProvider struct {
+	issuer string
+	client *http.Client
+
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+// oidcDiscovery 发现文档中使用的字段
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// metadata 获取发现文档，成功后缓存
+func (p *oidcProvider) metadata(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.metadataLocked(ctx)
+}
+
+func (p *oidcProvider) metadataLocked(ctx context.Context) (*oidcDiscovery, error) {
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OIDC discovery request: %w", err)
+	}
+	var discovery oidcDiscovery
+	status, err := p.do(req, &discovery)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, errs.Unavailable("OIDC discovery of %s failed with status %d", p.issuer, status)
+	}
+	if discovery.Issuer != p.issuer {
+		return nil, errs.Unavailable("OIDC discovery issuer %q does not match %q", discovery.Issuer, p.issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errs.Unavailable("OIDC discovery of %s has no jwks_uri", p.issuer)
+	}
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// key 查找签名公钥，kid 未知或缓存过期时重新获取 JWKS
+func (p *oidcProvider) key(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lookup := func() interface{} {
+		if kid == "" && len(p.keys) == 1 {
+			for _, key := range p.keys {
+				return key
+			}
+		}
+		return p.keys[kid]
+	}
+	age := time.Since(p.keysFetched)
+	cached := lookup()
+	if cached != nil && age < jwksMaxAge {
+		return cached, nil
+	}
+	if age >= jwksRefreshInterval {
+		if err := p.refreshKeys(ctx); err != nil {
+			if cached != nil {
+				return cached, nil
+			}
+			return nil, err
+		}
+	}
+	if key := lookup(); key != nil {
+		return key, nil
+	}
+	return nil, errs.Unauthenticated("unknown signing key %q", kid)
+}
+
+// refreshKeys 获取 JWKS，忽略不支持的密钥
+func (p *oidcProvider) refreshKeys(ctx context.Context) error {
+	discovery, err := p.metadataLocked(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	status, err := p.do(req, &jwks)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errs.Unavailable("JWKS of %s failed with status %d", p.issuer, status)
+	}
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+	return nil
+}
+
+// verify 校验令牌签名、签发者与有效期，audience 非空时要求 aud 包含该值
+func (p *oidcProvider) verify(ctx context.Context, token, audience string) (jwt.MapClaims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(oidcClockSkew),
+	}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+	claims := jwt.MapClaims{}
+	var keyErr error
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := p.key(ctx, kid)
+		keyErr = err
+		return key, err
+	}, options...)
+	if err != nil {
+		if keyErr != nil && !errors.Is(keyErr, errs.ErrUnauthenticated) {
+			return nil, keyErr
+		}
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errs.Unauthenticated("token has expired")
+		}
+		return nil, errs.Unauthenticated("invalid token")
+	}
+	return claims, nil
+}
+
+// do 发送请求并解析 JSON 响应，网络错误时返回 503
+func (p *oidcProvider) do(req *http.Request, out interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, errs.Unavailable("OIDC issuer %s is unavailable: %v", p.issuer, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize))
+	if err != nil {
+		return 0, errs.Unavailable("failed to read response of OIDC issuer %s: %v", p.issuer, err)
+	}
+	if err := json.Unmarshal(body, out); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, errs.Unavailable("invalid response of OIDC issuer %s: %v", p.issuer, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// jsonWebKey JWKS 中的公钥，支持 RSA、EC 与 Ed25519
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 解析公钥
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	decode := func(value string) (*big.Int, error) {
+		buf, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil || len(buf) == 0 {
+			return nil, fmt.Errorf("invalid JWK parameter")
+		}
+		return new(big.Int).SetBytes(buf), nil
+	}
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("invalid EC public key")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("unsupported OKP key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// claimValue 读取声明，名称支持以 . 分隔的嵌套路径
+func claimValue(claims jwt.MapClaims, name string) interface{} {
+	if name == "" {
+		return nil
+	}
+	if value, ok := claims[name]; ok {
+		return value
+	}
+	var current interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(name, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
+
+// claimString 读取字符串声明
+func claimString(claims jwt.MapClaims, name string) string {
+	value, _ := claimValue(claims, name).(string)
+	return value
+}
+
+// claimStrings 读取字符串或字符串数组声明
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	switch value := claimValue(claims, name).(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// containsString 判断切片是否包含字符串
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// randomURLSafe 生成 URL 安全的随机字符串
+func randomURLSafe(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
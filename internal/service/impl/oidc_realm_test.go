@@ -0,0 +1,812 @@
+package impl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	testOIDCClientID    = "go-nexus"
+	testOIDCRedirectURL = "https://nexus.example.com/api/v1/auth/oidc/callback"
+)
+
+// fakeIssuer 进程内的 OIDC 签发者，提供发现文档、JWKS 与校验 PKCE 的令牌端点
+type fakeIssuer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	keys          map[string]*rsa.PrivateKey
+	published     []string
+	grants        map[string]oidcGrant
+	jwksRequests  int
+	tokenRequests []url.Values
+	basicAuth     [2]string
+	discovery     map[string]interface{}
+	jwksStatus    int
+}
+
+// oidcGrant 授权码对应的 PKCE 质询与 ID 令牌
+type oidcGrant struct {
+	challenge string
+	idToken   string
+}
+
+// newFakeIssuer 启动签发者，发布一个 kid 为 k1 的 RSA 密钥，测试结束时关闭
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	f := &fakeIssuer{keys: map[string]*rsa.PrivateKey{}, grants: map[string]oidcGrant{}, jwksStatus: http.StatusOK}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", f.serveDiscovery)
+	mux.HandleFunc("/jwks", f.serveJWKS)
+	mux.HandleFunc("/token", f.serveToken)
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	f.addKey(t, "k1", true)
+	return f
+}
+
+// testRSAKeys 按 kid 缓存的签名密钥，避免每个用例都生成 RSA 密钥
+var testRSAKeys sync.Map
+
+// testRSAKey 返回 kid 对应的签名密钥，不同 kid 的密钥互不相同
+func testRSAKey(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+	if key, ok := testRSAKeys.Load(kid); ok {
+		return key.(*rsa.PrivateKey)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	actual, _ := testRSAKeys.LoadOrStore(kid, key)
+	return actual.(*rsa.PrivateKey)
+}
+
+// addKey 添加签名密钥，published 为 false 时 JWKS 中不包含该密钥
+func (f *fakeIssuer) addKey(t *testing.T, kid string, published bool) {
+	t.Helper()
+	key := testRSAKey(t, kid)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[kid] = key
+	if published {
+		f.published = append(f.published, kid)
+	}
+}
+
+// sign 以 kid 对应的密钥签发 RS256 令牌
+func (f *fakeIssuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	f.mu.Lock()
+	key := f.keys[kid]
+	f.mu.Unlock()
+	require.NotNil(t, key, kid)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// claims 签发者为自身、受众为 audience、五分钟后过期的声明
+func (f *fakeIssuer) claims(audience, subject string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss": f.URL,
+		"aud": audience,
+		"sub": subject,
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+// idClaims alice 登录时签发的 ID 令牌声明
+func (f *fakeIssuer) idClaims(nonce string) jwt.MapClaims {
+	claims := f.claims(testOIDCClientID, "alice-subject")
+	claims["nonce"] = nonce
+	claims["preferred_username"] = "alice"
+	claims["email"] = "alice@example.com"
+	claims["name"] = "Alice Liddell"
+	claims["groups"] = []interface{}{"Developers", "staff"}
+	return claims
+}
+
+// grant 登记授权码，令牌端点只在 code_verifier 与 challenge 匹配时返回 idToken
+func (f *fakeIssuer) grant(code, challenge, idToken string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.grants[code] = oidcGrant{challenge: challenge, idToken: idToken}
+}
+
+// setDiscovery 替换发现文档
+func (f *fakeIssuer) setDiscovery(discovery map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.discovery = discovery
+}
+
+// lastTokenRequest 返回令牌端点最近收到的表单与 Basic 凭据
+func (f *fakeIssuer) lastTokenRequest() (url.Values, [2]string, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.tokenRequests) == 0 {
+		return nil, f.basicAuth, 0
+	}
+	return f.tokenRequests[len(f.tokenRequests)-1], f.basicAuth, len(f.tokenRequests)
+}
+
+// jwksFetches 返回 JWKS 被请求的次数
+func (f *fakeIssuer) jwksFetches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jwksRequests
+}
+
+// setJWKSStatus 设置 JWKS 端点的响应状态码
+func (f *fakeIssuer) setJWKSStatus(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jwksStatus = status
+}
+
+func (f *fakeIssuer) serveDiscovery(w http.ResponseWriter, _ *http.Request) {
+	f.mu.Lock()
+	discovery := f.discovery
+	f.mu.Unlock()
+	if discovery == nil {
+		discovery = map[string]interface{}{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"jwks_uri":               f.URL + "/jwks",
+		}
+	}
+	writeJSON(w, http.StatusOK, discovery)
+}
+
+func (f *fakeIssuer) serveJWKS(w http.ResponseWriter, _ *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jwksRequests++
+	keys := []map[string]string{
+		// 加密用途与不支持的密钥被忽略
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+	}
+	for _, kid := range f.published {
+		key := f.keys[kid]
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	writeJSON(w, f.jwksStatus, map[string]interface{}{"keys": keys})
+}
+
+func (f *fakeIssuer) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokenRequests = append(f.tokenRequests, r.PostForm)
+	f.basicAuth[0], f.basicAuth[1], _ = r.BasicAuth()
+	grant, ok := f.grants[r.PostForm.Get("code")]
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown code"})
+		return
+	}
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": grant.idToken, "token_type": "Bearer"})
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// pkceChallenge 计算 S256 PKCE 质询
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// testOIDCConfig 指向签发者的单点登录配置
+func testOIDCConfig(issuer *fakeIssuer) config.OIDCConfig {
+	return config.OIDCConfig{
+		Issuer:        issuer.URL,
+		ClientID:      testOIDCClientID,
+		RedirectURL:   testOIDCRedirectURL,
+		Scopes:        []string{"openid", "profile", "email"},
+		Timeout:       5 * time.Second,
+		UsernameClaim: "preferred_username",
+		EmailClaim:    "email",
+		NameClaim:     "name",
+		GroupsClaim:   "groups",
+		GroupMappings: []config.GroupMapping{{Group: "developers", Roles: []string{model.RoleDeveloper}}},
+		DefaultRoles:  []string{model.RoleViewer},
+	}
+}
+
+// newOIDCEnv 创建用户服务与指向签发者的 OIDC 认证域，configure 可调整配置
+func newOIDCEnv(t *testing.T, issuer *fakeIssuer, configure func(cfg *config.OIDCConfig)) (*testEnv, *UserServiceImpl, *OIDCRealm) {
+	t.Helper()
+	env, users := newUserEnv(t)
+	env.cfg.Security.OIDC = testOIDCConfig(issuer)
+	if configure != nil {
+		configure(&env.cfg.Security.OIDC)
+	}
+	realm, err := NewOIDCRealm(env.cfg, env.logger, users)
+	require.NoError(t, err)
+	return env, users, realm
+}
+
+func TestNewOIDCRealm(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.OIDCConfig
+		wantEnabled bool
+		wantErr     string
+	}{
+		{name: "disabled"},
+		{name: "sso", cfg: config.OIDCConfig{Issuer: "https://id.example.com", UsernameClaim: "preferred_username"}, wantEnabled: true},
+		{name: "workloads_only", cfg: config.OIDCConfig{Workloads: []config.OIDCWorkload{{Issuer: "https://token.actions.githubusercontent.com", Subject: "repo:*"}}}},
+		{name: "missing_username_claim", cfg: config.OIDCConfig{Issuer: "https://id.example.com"}, wantErr: "username_claim"},
+		{name: "invalid_issuer_scheme", cfg: config.OIDCConfig{Issuer: "ftp://id.example.com", UsernameClaim: "sub"}, wantErr: "invalid OIDC issuer"},
+		{name: "issuer_without_host", cfg: config.OIDCConfig{Issuer: "https://", UsernameClaim: "sub"}, wantErr: "invalid OIDC issuer"},
+		{name: "invalid_workload_issuer", cfg: config.OIDCConfig{Workloads: []config.OIDCWorkload{{Issuer: "token.actions.githubusercontent.com"}}}, wantErr: "invalid OIDC issuer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, users := newUserEnv(t)
+			env.cfg.Security.OIDC = tt.cfg
+			realm, err := NewOIDCRealm(env.cfg, env.logger, users)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, realm.Enabled())
+		})
+	}
+
+	t.Run("shared_provider", func(t *testing.T) {
+		env, users := newUserEnv(t)
+		env.cfg.Security.OIDC = config.OIDCConfig{
+			Issuer:        "https://id.example.com",
+			UsernameClaim: "sub",
+			Workloads:     []config.OIDCWorkload{{Issuer: "https://id.example.com"}, {Issuer: "https://token.actions.githubusercontent.com"}},
+		}
+		realm, err := NewOIDCRealm(env.cfg, env.logger, users)
+		require.NoError(t, err)
+		assert.Len(t, realm.providers, 2)
+		assert.Same(t, realm.sso, realm.providers["https://id.example.com"], "SSO and workloads of one issuer share the key cache")
+		assert.True(t, realm.TrustsIssuer("https://token.actions.githubusercontent.com"))
+		assert.False(t, realm.TrustsIssuer("https://gitlab.example.com"))
+	})
+}
+
+func TestOIDCRealm_AuthCodeURL(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	_, _, realm := newOIDCEnv(t, issuer, func(cfg *config.OIDCConfig) {
+		cfg.Scopes = []string{"profile", "openid", "groups"}
+	})
+
+	authURL, err := realm.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	require.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, issuer.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, testOIDCClientID, query.Get("client_id"))
+	assert.Equal(t, testOIDCRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid profile groups", query.Get("scope"), "openid is requested once and first")
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, pkceChallenge("verifier-1"), query.Get("code_challenge"))
+	assert.Empty(t, query.Get("code_verifier"), "the verifier never leaves the server")
+
+	t.Run("endpoint_with_query", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		issuer.setDiscovery(map[string]interface{}{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/authorize?tenant=example",
+			"jwks_uri":               issuer.URL + "/jwks",
+		})
+		_, _, realm := newOIDCEnv(t, issuer, nil)
+		authURL, err := realm.AuthCodeURL(context.Background(), "s", "n", "v")
+		require.NoError(t, err)
+		parsed, err := url.Parse(authURL)
+		require.NoError(t, err)
+		assert.Equal(t, "example", parsed.Query().Get("tenant"))
+		assert.Equal(t, "s", parsed.Query().Get("state"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		env, users := newUserEnv(t)
+		realm, err := NewOIDCRealm(env.cfg, env.logger, users)
+		require.NoError(t, err)
+		_, err = realm.AuthCodeURL(context.Background(), "s", "n", "v")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		_, err = realm.Exchange(context.Background(), "code", "v", "n")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestOIDCRealm_Discovery(t *testing.T) {
+	tests := []struct {
+		name      string
+		discovery func(issuer *fakeIssuer) map[string]interface{}
+		wantErr   string
+	}{
+		{name: "issuer_mismatch", discovery: func(issuer *fakeIssuer) map[string]interface{} {
+			return map[string]interface{}{"issuer": "https://evil.example.com", "jwks_uri": issuer.URL + "/jwks"}
+		}, wantErr: "does not match"},
+		{name: "missing_jwks_uri", discovery: func(issuer *fakeIssuer) map[string]interface{} {
+			return map[string]interface{}{"issuer": issuer.URL}
+		}, wantErr: "no jwks_uri"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			issuer.setDiscovery(tt.discovery(issuer))
+			_, _, realm := newOIDCEnv(t, issuer, nil)
+			_, err := realm.AuthCodeURL(context.Background(), "s", "n", "v")
+			assert.ErrorIs(t, err, errs.ErrUnavailable)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, _, realm := newOIDCEnv(t, issuer, nil)
+		issuer.Close()
+		_, err := realm.AuthCodeURL(context.Background(), "s", "n", "v")
+		assert.ErrorIs(t, err, errs.ErrUnavailable)
+	})
+
+	t.Run("error_status", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+		env, users := newUserEnv(t)
+		env.cfg.Security.OIDC = config.OIDCConfig{Issuer: server.URL, UsernameClaim: "sub"}
+		realm, err := NewOIDCRealm(env.cfg, env.logger, users)
+		require.NoError(t, err)
+		_, err = realm.AuthCodeURL(context.Background(), "s", "n", "v")
+		assert.ErrorIs(t, err, errs.ErrUnavailable)
+		assert.Contains(t, err.Error(), "status 404")
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, _, realm := newOIDCEnv(t, issuer, nil)
+		_, err := realm.AuthCodeURL(context.Background(), "s", "n", "v")
+		require.NoError(t, err)
+		issuer.Close()
+		_, err = realm.AuthCodeURL(context.Background(), "s", "n", "v")
+		assert.NoError(t, err, "discovery is fetched once")
+	})
+}
+
+func TestOIDCRealm_Exchange(t *testing.T) {
+	const verifier, nonce = "verifier-0123456789", "nonce-0123456789"
+	tests := []struct {
+		name      string
+		configure func(cfg *config.OIDCConfig)
+		// token 返回授权码对应的 ID 令牌
+		token func(t *testing.T, issuer *fakeIssuer) string
+		// verifier 换取令牌时使用的 PKCE 校验码，为空时使用正确的校验码
+		verifier string
+		wantErr  error
+		wantMsg  string
+	}{
+		{name: "success", token: func(t *testing.T, issuer *fakeIssuer) string {
+			return issuer.sign(t, "k1", issuer.idClaims(nonce))
+		}},
+		{name: "pkce_verifier_mismatch", verifier: "another-verifier", token: func(t *testing.T, issuer *fakeIssuer) string {
+			return issuer.sign(t, "k1", issuer.idClaims(nonce))
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "PKCE verification failed"},
+		{name: "nonce_mismatch", token: func(t *testing.T, issuer *fakeIssuer) string {
+			return issuer.sign(t, "k1", issuer.idClaims("replayed-nonce"))
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "nonce"},
+		{name: "nonce_missing", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			delete(claims, "nonce")
+			return issuer.sign(t, "k1", claims)
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "nonce"},
+		{name: "wrong_audience", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			claims["aud"] = "another-client"
+			return issuer.sign(t, "k1", claims)
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "invalid token"},
+		{name: "audience_list", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			claims["aud"] = []interface{}{"another-client", testOIDCClientID}
+			return issuer.sign(t, "k1", claims)
+		}},
+		{name: "wrong_issuer", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			claims["iss"] = "https://evil.example.com"
+			return issuer.sign(t, "k1", claims)
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "invalid token"},
+		{name: "expired", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			claims["exp"] = time.Now().Add(-2 * oidcClockSkew).Unix()
+			return issuer.sign(t, "k1", claims)
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "expired"},
+		{name: "expired_within_clock_skew", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			claims["exp"] = time.Now().Add(-oidcClockSkew / 2).Unix()
+			return issuer.sign(t, "k1", claims)
+		}},
+		{name: "missing_expiry", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			delete(claims, "exp")
+			return issuer.sign(t, "k1", claims)
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "invalid token"},
+		{name: "unpublished_key", token: func(t *testing.T, issuer *fakeIssuer) string {
+			issuer.addKey(t, "rogue", false)
+			return issuer.sign(t, "rogue", issuer.idClaims(nonce))
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "invalid token"},
+		{name: "symmetric_algorithm_rejected", token: func(t *testing.T, issuer *fakeIssuer) string {
+			// 以公开的客户端 ID 作为 HMAC 密钥伪造的令牌
+			signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, issuer.idClaims(nonce)).SignedString([]byte(testOIDCClientID))
+			require.NoError(t, err)
+			return signed
+		}, wantErr: errs.ErrUnauthenticated, wantMsg: "invalid token"},
+		{name: "missing_username_claim", token: func(t *testing.T, issuer *fakeIssuer) string {
+			claims := issuer.idClaims(nonce)
+			delete(claims, "preferred_username")
+			return issuer.sign(t, "k1", claims)
+		}, wantErr: errs.ErrForbidden, wantMsg: "preferred_username"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			_, _, realm := newOIDCEnv(t, issuer, tt.configure)
+			issuer.grant("code-1", pkceChallenge(verifier), tt.token(t, issuer))
+			exchangeVerifier := verifier
+			if tt.verifier != "" {
+				exchangeVerifier = tt.verifier
+			}
+
+			user, err := realm.Exchange(context.Background(), "code-1", exchangeVerifier, nonce)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), tt.wantMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", user.Username)
+			assert.Equal(t, model.UserRealmOIDC, user.Realm)
+			assert.Equal(t, "alice@example.com", user.Email)
+			assert.Equal(t, "Alice Liddell", user.FullName)
+			assert.ElementsMatch(t, []string{model.RoleViewer, model.RoleDeveloper}, roleNames(user))
+		})
+	}
+
+	t.Run("token_request", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, _, realm := newOIDCEnv(t, issuer, func(cfg *config.OIDCConfig) { cfg.ClientSecret = "s3cret&=" })
+		issuer.grant("code-1", pkceChallenge(verifier), issuer.sign(t, "k1", issuer.idClaims(nonce)))
+		_, err := realm.Exchange(context.Background(), "code-1", verifier, nonce)
+		require.NoError(t, err)
+
+		form, basicAuth, count := issuer.lastTokenRequest()
+		require.Equal(t, 1, count)
+		assert.Equal(t, "authorization_code", form.Get("grant_type"))
+		assert.Equal(t, testOIDCRedirectURL, form.Get("redirect_uri"))
+		assert.Equal(t, testOIDCClientID, form.Get("client_id"))
+		assert.Equal(t, verifier, form.Get("code_verifier"))
+		assert.Equal(t, [2]string{testOIDCClientID, url.QueryEscape("s3cret&=")}, basicAuth, "client credentials are form-encoded per RFC 6749")
+	})
+
+	t.Run("public_client", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, _, realm := newOIDCEnv(t, issuer, nil)
+		issuer.grant("code-1", pkceChallenge(verifier), issuer.sign(t, "k1", issuer.idClaims(nonce)))
+		_, err := realm.Exchange(context.Background(), "code-1", verifier, nonce)
+		require.NoError(t, err)
+		_, basicAuth, _ := issuer.lastTokenRequest()
+		assert.Empty(t, basicAuth[0])
+	})
+
+	t.Run("unknown_code", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, _, realm := newOIDCEnv(t, issuer, nil)
+		_, err := realm.Exchange(context.Background(), "missing", verifier, nonce)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+		assert.Contains(t, err.Error(), "invalid_grant")
+	})
+
+	t.Run("token_endpoint_error_without_body", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		issuer.setDiscovery(map[string]interface{}{
+			"issuer":         issuer.URL,
+			"token_endpoint": issuer.URL + "/missing",
+			"jwks_uri":       issuer.URL + "/jwks",
+		})
+		_, _, realm := newOIDCEnv(t, issuer, nil)
+		_, err := realm.Exchange(context.Background(), "code-1", verifier, nonce)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+		assert.Contains(t, err.Error(), "status 404")
+	})
+
+	t.Run("nested_groups_claim", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, _, realm := newOIDCEnv(t, issuer, func(cfg *config.OIDCConfig) {
+			cfg.GroupsClaim = "realm_access.roles"
+			cfg.GroupMappings = []config.GroupMapping{{Group: " Nexus-Admins ", Roles: []string{model.RoleAdmin}}, {Group: "developers", Roles: []string{"missing-role"}}}
+			cfg.DefaultRoles = nil
+		})
+		claims := issuer.idClaims(nonce)
+		claims["realm_access"] = map[string]interface{}{"roles": []interface{}{"nexus-admins", 42}}
+		issuer.grant("code-1", pkceChallenge(verifier), issuer.sign(t, "k1", claims))
+		user, err := realm.Exchange(context.Background(), "code-1", verifier, nonce)
+		require.NoError(t, err)
+		assert.Equal(t, []string{model.RoleAdmin}, roleNames(user))
+	})
+
+	t.Run("local_account_not_taken_over", func(t *testing.T) {
+		issuer := newFakeIssuer(t)
+		_, users, realm := newOIDCEnv(t, issuer, nil)
+		createUser(t, users, "alice")
+		issuer.grant("code-1", pkceChallenge(verifier), issuer.sign(t, "k1", issuer.idClaims(nonce)))
+		_, err := realm.Exchange(context.Background(), "code-1", verifier, nonce)
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+}
+
+func TestOIDCRealm_KeyRefresh(t *testing.T) {
+	const verifier, nonce = "verifier-0123456789", "nonce-0123456789"
+	issuer := newFakeIssuer(t)
+	_, _, realm := newOIDCEnv(t, issuer, nil)
+	exchange := func(kid string) error {
+		code := "code-" + kid
+		issuer.grant(code, pkceChallenge(verifier), issuer.sign(t, kid, issuer.idClaims(nonce)))
+		_, err := realm.Exchange(context.Background(), code, verifier, nonce)
+		return err
+	}
+	// backdate 让缓存的 JWKS 看起来是 age 之前获取的
+	backdate := func(age time.Duration) {
+		realm.sso.mu.Lock()
+		defer realm.sso.mu.Unlock()
+		realm.sso.keysFetched = time.Now().Add(-age)
+	}
+
+	require.NoError(t, exchange("k1"))
+	require.NoError(t, exchange("k1"))
+	assert.Equal(t, 1, issuer.jwksFetches(), "known keys are served from the cache")
+
+	// 签发者轮换到新密钥，刚获取过 JWKS 时不会因为未知 kid 立即重新获取
+	issuer.addKey(t, "k2", true)
+	err := exchange("k2")
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	assert.Equal(t, 1, issuer.jwksFetches(), "unknown kids do not hammer the issuer")
+
+	backdate(jwksRefreshInterval)
+	require.NoError(t, exchange("k2"), "an unknown kid refreshes the JWKS after the refresh interval")
+	assert.Equal(t, 2, issuer.jwksFetches())
+
+	// 伪造的 kid 在间隔内只触发一次请求
+	backdate(jwksRefreshInterval)
+	issuer.addKey(t, "forged", false)
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, exchange("forged"), errs.ErrUnauthenticated)
+	}
+	assert.Equal(t, 3, issuer.jwksFetches())
+
+	t.Run("stale_cache_refreshed", func(t *testing.T) {
+		backdate(jwksMaxAge)
+		before := issuer.jwksFetches()
+		require.NoError(t, exchange("k1"))
+		assert.Equal(t, before+1, issuer.jwksFetches())
+	})
+
+	t.Run("stale_cache_used_when_issuer_fails", func(t *testing.T) {
+		backdate(jwksMaxAge)
+		issuer.setJWKSStatus(http.StatusBadGateway)
+		defer issuer.setJWKSStatus(http.StatusOK)
+		assert.NoError(t, exchange("k1"), "known keys keep working while the JWKS is unavailable")
+		assert.ErrorIs(t, exchange("forged"), errs.ErrUnavailable, "an unknown kid is not reported as a bad token when the JWKS cannot be fetched")
+	})
+}
+
+func TestOIDCRealm_AuthenticateWorkload(t *testing.T) {
+	const audience = "https://nexus.example.com"
+	tests := []struct {
+		name      string
+		issuer    string
+		claims    func(issuer *fakeIssuer) jwt.MapClaims
+		setup     func(t *testing.T, users *UserServiceImpl)
+		wantUser  string
+		wantScope []string
+		wantErr   error
+	}{
+		{name: "exact_subject", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "repo:example/app:environment:production")
+		}, wantUser: "deployer"},
+		{name: "wildcard_subject", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+		}, wantUser: "ci", wantScope: []string{"repo:write:libs-*"}},
+		{name: "audience_list", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			claims := issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+			claims["aud"] = []interface{}{"sts.amazonaws.com", audience}
+			return claims
+		}, wantUser: "ci"},
+		{name: "subject_mismatch", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "repo:example/other:ref:refs/heads/main")
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "wildcard_anchored", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "prefix-repo:example/app:ref:refs/heads/main")
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "audience_mismatch", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims("https://other.example.com", "repo:example/app:ref:refs/heads/main")
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "audience_selects_workload", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims("staging", "repo:example/app:ref:refs/heads/main")
+		}, wantUser: "deployer"},
+		{name: "wrong_issuer_claim", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			claims := issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+			claims["iss"] = "https://evil.example.com"
+			return claims
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "untrusted_issuer", issuer: "https://evil.example.com", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "expired", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			claims := issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return claims
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "user_missing", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "repo:example/app:pull_request")
+		}, wantErr: errs.ErrUnauthenticated},
+		{name: "user_locked", claims: func(issuer *fakeIssuer) jwt.MapClaims {
+			return issuer.claims(audience, "repo:example/app:ref:refs/heads/main")
+		}, setup: func(t *testing.T, users *UserServiceImpl) {
+			user, err := users.users.FindByUsername(context.Background(), "ci")
+			require.NoError(t, err)
+			require.NoError(t, users.users.UpdateColumns(context.Background(), user.ID, map[string]interface{}{"status": model.UserStatusLocked}))
+		}, wantErr: errs.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			env, users := newUserEnv(t)
+			env.cfg.Security.OIDC.Workloads = []config.OIDCWorkload{
+				{Issuer: issuer.URL, Audience: audience, Subject: "repo:example/app:environment:production", User: "deployer"},
+				{Issuer: issuer.URL, Audience: audience, Subject: "repo:example/app:ref:refs/heads/*", User: "ci", Scopes: []string{"repo:write:libs-*"}},
+				{Issuer: issuer.URL, Audience: "staging", Subject: "repo:example/app:*", User: "deployer"},
+				{Issuer: issuer.URL, Audience: audience, Subject: "repo:example/app:pull_request", User: "nobody"},
+			}
+			realm, err := NewOIDCRealm(env.cfg, env.logger, users)
+			require.NoError(t, err)
+			createUser(t, users, "deployer", model.RoleDeveloper)
+			createUser(t, users, "ci")
+			if tt.setup != nil {
+				tt.setup(t, users)
+			}
+			trusted := issuer.URL
+			if tt.issuer != "" {
+				trusted = tt.issuer
+			}
+
+			user, workload, claims, err := realm.AuthenticateWorkload(context.Background(), issuer.sign(t, "k1", tt.claims(issuer)), trusted)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, user.Username)
+			assert.Equal(t, tt.wantUser, workload.User)
+			if tt.wantScope != nil {
+				assert.Equal(t, tt.wantScope, workload.Scopes)
+			}
+			assert.Equal(t, issuer.URL, claims["iss"])
+		})
+	}
+}
+
+func TestJSONWebKey_PublicKey(t *testing.T) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	rsaKey := testRSAKey(t, "k1")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		jwk     jsonWebKey
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "rsa", jwk: jsonWebKey{Kty: "RSA", N: b64(rsaKey.N.Bytes()), E: "AQAB"}, want: &rsaKey.PublicKey},
+		{name: "ec", jwk: jsonWebKey{Kty: "EC", Crv: "P-384", X: b64(ecKey.X.Bytes()), Y: b64(ecKey.Y.Bytes())}, want: &ecKey.PublicKey},
+		{name: "ed25519", jwk: jsonWebKey{Kty: "OKP", Crv: "Ed25519", X: b64(edKey)}, want: edKey},
+		{name: "rsa_bad_modulus", jwk: jsonWebKey{Kty: "RSA", N: "!!", E: "AQAB"}, wantErr: true},
+		{name: "rsa_empty_exponent", jwk: jsonWebKey{Kty: "RSA", N: b64(rsaKey.N.Bytes())}, wantErr: true},
+		{name: "rsa_huge_exponent", jwk: jsonWebKey{Kty: "RSA", N: b64(rsaKey.N.Bytes()), E: b64(append([]byte{1}, make([]byte, 16)...))}, wantErr: true},
+		{name: "ec_unsupported_curve", jwk: jsonWebKey{Kty: "EC", Crv: "secp256k1", X: b64(ecKey.X.Bytes()), Y: b64(ecKey.Y.Bytes())}, wantErr: true},
+		{name: "ec_wrong_curve", jwk: jsonWebKey{Kty: "EC", Crv: "P-256", X: b64(ecKey.X.Bytes()), Y: b64(ecKey.Y.Bytes())}, wantErr: true},
+		{name: "ec_missing_y", jwk: jsonWebKey{Kty: "EC", Crv: "P-384", X: b64(ecKey.X.Bytes())}, wantErr: true},
+		{name: "okp_wrong_curve", jwk: jsonWebKey{Kty: "OKP", Crv: "X25519", X: b64(edKey)}, wantErr: true},
+		{name: "okp_short_key", jwk: jsonWebKey{Kty: "OKP", Crv: "Ed25519", X: b64(edKey[:16])}, wantErr: true},
+		{name: "symmetric", jwk: jsonWebKey{Kty: "oct"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := tt.jwk.publicKey()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, key)
+		})
+	}
+}
+
+func TestClaimHelpers(t *testing.T) {
+	claims := jwt.MapClaims{
+		"sub":          "alice",
+		"groups":       []interface{}{"dev", 1, "ops"},
+		"team":         "release",
+		"realm_access": map[string]interface{}{"roles": []interface{}{"admin"}},
+		"a.b":          "literal dotted name",
+	}
+	tests := []struct {
+		name        string
+		claim       string
+		wantString  string
+		wantStrings []string
+	}{
+		{name: "string", claim: "sub", wantString: "alice", wantStrings: []string{"alice"}},
+		{name: "array", claim: "groups", wantStrings: []string{"dev", "ops"}},
+		{name: "nested", claim: "realm_access.roles", wantStrings: []string{"admin"}},
+		{name: "dotted_name_first", claim: "a.b", wantString: "literal dotted name", wantStrings: []string{"literal dotted name"}},
+		{name: "missing", claim: "email"},
+		{name: "path_through_scalar", claim: "team.name"},
+		{name: "empty_name", claim: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantString, claimString(claims, tt.claim))
+			assert.Equal(t, tt.wantStrings, claimStrings(claims, tt.claim))
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// PromotionServiceImpl 制品晋级服务实现
+type PromotionServiceImpl struct {
+	logger     *slog.Logger
+	artifacts  *ArtifactServiceImpl
+	audit      *AuditRecorder
+	authorizer *AuthorizerImpl
+}
+
+// NewPromotionService 创建新的制品晋级服务实现
+func NewPromotionService(logger *slog.Logger, artifacts *ArtifactServiceImpl, audit *AuditRecorder, authorizer *AuthorizerImpl) *PromotionServiceImpl {
+	return &PromotionServiceImpl{
+		logger:     logger,
+		artifacts:  artifacts,
+		audit:      audit,
+		authorizer: authorizer,
+	}
+}
+
+// Promote 晋级制品：校验源仓库与目标仓库，按请求选出制品后晋级
+func (s *PromotionServiceImpl) Promote(ctx context.Context, repoIDOrName string, req *dto.PromoteRequest) (*dto.PromoteResult, error) {
+	source, target, err := s.resolveRepositories(ctx, repoIDOrName, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.AuthorizeRepository(ctx, target.ID, auth.ActionWrite); err != nil {
+		return nil, err
+	}
+	if req.Move {
+		if err := s.authorizer.AuthorizeRepository(ctx, source.ID, auth.ActionDelete); err != nil {
+			return nil, err
+		}
+	}
+	candidates, err := s.selectArtifacts(ctx, source, req)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := s.artifacts.lockStagingWrite(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return s.promote(ctx, source, target, candidates, req.Move, req.Overwrite)
+}
+
+// promote 先检查所有目标路径，再逐个复制或移动内容与记录，最后重新生成两个仓库的元数据
+// 单个文件失败时记录到结果中并继续处理其余文件，全部失败时返回错误
+func (s *PromotionServiceImpl) promote(ctx context.Context, source, target *model.Repository, candidates []*model.Artifact, remove, overwrite bool) (*dto.PromoteResult, error) {
+	if target.DeploymentPolicy == model.DeploymentPolicyReadOnly {
+		return nil, errs.Conflict("repository %q is read-only", target.Name)
+	}
+	existing := make(map[string]*model.Artifact, len(candidates))
+	for _, artifact := range candidates {
+		found, err := s.artifacts.repository.FindByPath(ctx, target.ID, artifact.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find artifact: %w", err)
+		}
+		if found != nil && target.DeploymentPolicy == model.DeploymentPolicyDisableRedeploy {
+			return nil, errs.Conflict("artifact %q already exists in repository %q and redeploy is disabled", artifact.Path, target.Name)
+		}
+		if found != nil && !overwrite {
+			return nil, errs.Conflict("artifact %q already exists in repository %q", artifact.Path, target.Name)
+		}
+		existing[artifact.Path] = found
+	}
+	formatPlugin, err := s.artifacts.plugins.GetFormatPlugin(target.Format)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSignaturePolicy(ctx, source, target, formatPlugin, candidates); err != nil {
+		return nil, err
+	}
+
+	result := &dto.PromoteResult{Source: source.Name, Target: target.Name, Move: remove}
+	paths := make([]string, 0, len(candidates))
+	var failed []string
+	for _, artifact := range candidates {
+		promoted, err := s.transfer(ctx, source, target, artifact, existing[artifact.Path], remove)
+		if err != nil {
+			s.logger.Error("Failed to promote artifact", "source", source.Name, "target", target.Name, "path", artifact.Path, "error", err)
+			result.Failed = append(result.Failed, &dto.PromoteFailure{Path: artifact.Path, Error: err.Error()})
+			failed = append(failed, artifact.Path)
+			continue
+		}
+		result.Artifacts = append(result.Artifacts, promoted)
+		paths = append(paths, artifact.Path)
+	}
+	if len(paths) == 0 && len(failed) > 0 {
+		return nil, fmt.Errorf("failed to promote artifacts: %s", result.Failed[0].Error)
+	}
+	// 签名校验结果按目标仓库信任的公钥重新计算
+	for _, promoted := range result.Artifacts {
+		s.artifacts.recordSignature(ctx, target, formatPlugin, promoted.Path, promoted, nil)
+	}
+
+	s.artifacts.refreshMetadataPaths(ctx, target, paths)
+	if remove {
+		s.artifacts.refreshMetadataPaths(ctx, source, paths)
+	}
+
+	s.audit.Record(ctx, auditActionPromote, "repository:"+target.Name, map[string]interface{}{
+		"source": source.Name,
+		"target": target.Name,
+		"move":   remove,
+		"paths":  paths,
+		"failed": failed,
+	})
+	s.logger.Info("Artifacts promoted", "source", source.Name, "target", target.Name, "move", remove, "count", len(paths), "failed", len(failed))
+	return result, nil
+}
+
+// checkSignaturePolicy 目标仓库要求签名时，每个制品主文件都需要在源仓库中有目标仓库信任的公钥签署的签名
+func (s *PromotionServiceImpl) checkSignaturePolicy(ctx context.Context, source, target *model.Repository, formatPlugin pluginapi.FormatPlugin, candidates []*model.Artifact) error {
+	if !target.SignaturePolicy.Required {
+		return nil
+	}
+	keys, err := s.artifacts.signatureKeys(ctx, target)
+	if err != nil {
+		return err
+	}
+	for _, artifact := range candidates {
+		if !isPrimaryFile(formatPlugin, artifact.Path) {
+			continue
+		}
+		if err := s.artifacts.checkSignature(ctx, source, keys, artifact.Path); err != nil {
+			return errs.Conflict("repository %q requires signed artifacts: %v", target.Name, err)
+		}
+	}
+	return nil
+}
+
+// resolveRepositories 校验源仓库与目标仓库：格式相同，两者都是宿主仓库且目标已启用
+// 代理仓库的内容只是远程缓存，移动会删除缓存，复制也无法保证与远程一致，因此不能作为来源
+func (s *PromotionServiceImpl) resolveRepositories(ctx context.Context, sourceIDOrName, targetIDOrName string) (*model.Repository, *model.Repository, error) {
+	source, err := lookupRepository(ctx, s.artifacts.repositories, sourceIDOrName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source.Type != model.RepositoryTypeHosted {
+		return nil, nil, errs.InvalidArgument("repository %q is a %s repository, only hosted repositories can be promoted from", source.Name, source.Type)
+	}
+	target, err := lookupRepository(ctx, s.artifacts.repositories, targetIDOrName)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case target.ID == source.ID:
+		return nil, nil, errs.InvalidArgument("source and target repository are the same")
+	case target.Type != model.RepositoryTypeHosted:
+		return nil, nil, errs.InvalidArgument("target repository %q is a %s repository, only hosted repositories accept promotions", target.Name, target.Type)
+	case target.Status == "inactive":
+		return nil, nil, errs.InvalidArgument("target repository %q is inactive", target.Name)
+	case target.Format != source.Format:
+		return nil, nil, errs.InvalidArgument("cannot promote %s artifacts to %s repository %q", source.Format, target.Format, target.Name)
+	}
+	if err := checkStagingOpen(target); err != nil {
+		return nil, nil, err
+	}
+	return source, target, nil
+}
+
+// selectArtifacts 按路径或坐标选出要晋级的制品及其附属文件
+func (s *PromotionServiceImpl) selectArtifacts(ctx context.Context, source *model.Repository, req *dto.PromoteRequest) ([]*model.Artifact, error) {
+	var primary []*model.Artifact
+	if req.Path != "" {
+		p, err := normalizePath(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		artifact, err := s.artifacts.repository.FindByPath(ctx, source.ID, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find artifact: %w", err)
+		}
+		if artifact == nil {
+			return nil, errs.NotFound("artifact %q not found in repository %q", p, source.Name)
+		}
+		primary = append(primary, artifact)
+	} else {
+		if req.Name == "" || req.Version == "" {
+			return nil, errs.InvalidArgument("path or name and version are required")
+		}
+		if strings.Contains(req.Name, "*") || strings.Contains(req.Version, "*") {
+			return nil, errs.InvalidArgument("name and version must not contain wildcards")
+		}
+		filter := &model.ArtifactFilter{
+			RepositoryIDs: []string{source.ID},
+			Name:          req.Name,
+			Version:       req.Version,
+			Sort:          model.ArtifactSortCreatedAt,
+		}
+		if req.GroupID != "" {
+			if source.Format == "maven" {
+				filter.PathPrefix = strings.ReplaceAll(req.GroupID, ".", "/") + "/" + req.Name + "/"
+			} else {
+				filter.Metadata = map[string]string{metadataGroupID: req.GroupID}
+			}
+		}
+		found, err := s.artifacts.repository.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		if len(found) == 0 {
+			return nil, errs.NotFound("no artifacts of %s:%s found in repository %q", req.Name, req.Version, source.Name)
+		}
+		primary = found
+	}
+
+	seen := make(map[string]bool)
+	var selected []*model.Artifact
+	add := func(a *model.Artifact) {
+		if !seen[a.ID] {
+			seen[a.ID] = true
+			selected = append(selected, a)
+		}
+	}
+	for _, artifact := range primary {
+		add(artifact)
+		companions, err := s.artifacts.repository.List(ctx, &model.ArtifactFilter{
+			RepositoryIDs: []string{source.ID},
+			PathPrefix:    artifact.Path + ".",
+			Sort:          model.ArtifactSortCreatedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		for _, companion := range companions {
+			add(companion)
+		}
+	}
+	return selected, nil
+}
+
+// transfer 在存储中复制或移动内容，并在目标仓库创建或覆盖制品记录
+func (s *PromotionServiceImpl) transfer(ctx context.Context, source, target *model.Repository, artifact, existing *model.Artifact, remove bool) (*model.Artifact, error) {
+	from, to := storagePath(source, artifact.Path), storagePath(target, artifact.Path)
+	var err error
+	if remove {
+		err = move(ctx, s.artifacts.storage, from, to)
+	} else {
+		err = copyContent(ctx, s.artifacts.storage, from, to)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer %q: %w", artifact.Path, err)
+	}
+
+	promoted := existing
+	if promoted == nil {
+		promoted = &model.Artifact{
+			ID:           uuid.New().String(),
+			RepositoryID: target.ID,
+			Path:         artifact.Path,
+		}
+	}
+	promoted.Name = artifact.Name
+	promoted.Version = artifact.Version
+	promoted.Format = artifact.Format
+	promoted.Size = artifact.Size
+	promoted.Checksum = artifact.Checksum
+	promoted.ContentType = artifact.ContentType
+	promoted.Metadata = maps.Clone(artifact.Metadata)
+	promoted.Properties = maps.Clone(artifact.Properties)
+	if existing != nil {
+		err = s.artifacts.repository.Update(ctx, promoted)
+	} else {
+		err = s.artifacts.repository.Create(ctx, promoted)
+	}
+	if err != nil {
+		// 移动后记录保存失败时把内容移回源仓库，避免源记录指向不存在的文件
+		if remove {
+			if rollbackErr := move(ctx, s.artifacts.storage, to, from); rollbackErr != nil {
+				s.logger.Error("Failed to restore promoted content", "path", artifact.Path, "error", rollbackErr)
+			}
+		}
+		return nil, fmt.Errorf("failed to save artifact: %w", err)
+	}
+	s.artifacts.index.Add(indexDocument(promoted))
+	s.artifacts.publish(ctx, pluginapi.ArtifactEventPromoted, target, promoted)
+
+	if remove {
+		if err := s.artifacts.repository.Delete(ctx, artifact.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete source artifact: %w", err)
+		}
+		s.artifacts.index.Remove(artifact.ID)
+		s.artifacts.publish(ctx, pluginapi.ArtifactEventDeleted, source, artifact)
+	}
+	return promoted, nil
+}
+
+// copyContent 在存储中复制文件，存储不支持复制时读出后写入
+func copyContent(ctx context.Context, store pluginapi.StoragePlugin, from, to string) error {
+	if copyable, ok := store.(pluginapi.CopyableStorage); ok {
+		return copyable.Copy(ctx, from, to)
+	}
+	data, err := store.Download(ctx, from)
+	if err != nil {
+		return err
+	}
+	return store.Upload(ctx, to, data)
+}
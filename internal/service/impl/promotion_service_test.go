@@ -0,0 +1,186 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// failingStorage 读取以 suffix 结尾的文件时失败，用于模拟晋级中途的存储错误
+type failingStorage struct {
+	plainStorage
+	suffix string
+}
+
+func (s failingStorage) Download(ctx context.Context, path string) ([]byte, error) {
+	if strings.HasSuffix(path, s.suffix) {
+		return nil, errors.New("disk read error")
+	}
+	return s.plainStorage.Download(ctx, path)
+}
+
+// newPromotionEnv 创建 staging 与 release 两个宿主仓库，staging 中有 lib 1.0 的 jar、pom 与校验和文件
+func newPromotionEnv(t *testing.T) (*testEnv, *PromotionServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	env.hosted(t, "libs-staging", "maven")
+	env.hosted(t, "libs-release", "maven")
+	env.upload(t, "libs-staging", "com/x/lib/1.0/lib-1.0.jar", []byte("jar"))
+	env.upload(t, "libs-staging", "com/x/lib/1.0/lib-1.0.jar.sha1", []byte(sha1Hex([]byte("jar"))))
+	env.upload(t, "libs-staging", "com/x/lib/1.0/lib-1.0.pom", pomOf("com.x", "lib", "1.0", "library"))
+	return env, NewPromotionService(env.logger, env.artifacts, env.audit, env.authorizer)
+}
+
+func promotedPaths(result *dto.PromoteResult) []string {
+	var paths []string
+	for _, artifact := range result.Artifacts {
+		paths = append(paths, artifact.Path)
+	}
+	return paths
+}
+
+func TestPromotionService_Copy(t *testing.T) {
+	env, promotion := newPromotionEnv(t)
+	ctx := context.Background()
+
+	result, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "libs-release", Name: "lib", Version: "1.0", GroupID: "com.x"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"com/x/lib/1.0/lib-1.0.jar", "com/x/lib/1.0/lib-1.0.jar.sha1", "com/x/lib/1.0/lib-1.0.pom"}, promotedPaths(result))
+	assert.Empty(t, result.Failed)
+
+	data, err := env.download("libs-release", "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, "jar", string(data))
+	_, err = env.download("libs-staging", "com/x/lib/1.0/lib-1.0.jar")
+	assert.NoError(t, err, "copy keeps the source")
+
+	metadata, err := env.download("libs-release", "com/x/lib/maven-metadata.xml")
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), "<version>1.0</version>")
+
+	page, err := env.audit.List(ctx, &dto.ListAuditQuery{Action: auditActionPromote})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "repository:libs-release", page.Items[0].Resource)
+	assert.Contains(t, page.Items[0].Details, `"source":"libs-staging"`)
+
+	t.Run("conflict_without_overwrite", func(t *testing.T) {
+		_, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "libs-release", Path: "com/x/lib/1.0/lib-1.0.jar"})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		result, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "libs-release", Path: "com/x/lib/1.0/lib-1.0.jar", Overwrite: true})
+		require.NoError(t, err)
+		assert.Len(t, result.Artifacts, 2, "companion files follow the primary file")
+	})
+}
+
+func TestPromotionService_Move(t *testing.T) {
+	env, promotion := newPromotionEnv(t)
+	ctx := context.Background()
+
+	result, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "libs-release", Path: "/com/x/lib/1.0/lib-1.0.jar", Move: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"com/x/lib/1.0/lib-1.0.jar", "com/x/lib/1.0/lib-1.0.jar.sha1"}, promotedPaths(result))
+	assert.True(t, result.Move)
+
+	_, err = env.download("libs-staging", "com/x/lib/1.0/lib-1.0.jar")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	_, err = env.download("libs-staging", "com/x/lib/1.0/lib-1.0.pom")
+	assert.NoError(t, err, "other files stay in the source")
+	data, err := env.download("libs-release", "com/x/lib/1.0/lib-1.0.jar.sha1")
+	require.NoError(t, err)
+	assert.Equal(t, sha1Hex([]byte("jar")), string(data))
+}
+
+func TestPromotionService_Rejects(t *testing.T) {
+	env, promotion := newPromotionEnv(t)
+	env.hosted(t, "npm-hosted", "npm")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-staging"}})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "central", Type: model.RepositoryTypeProxy, Format: "maven", URL: "http://127.0.0.1:1/maven2/"})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "frozen", Type: model.RepositoryTypeHosted, Format: "maven", DeploymentPolicy: model.DeploymentPolicyReadOnly})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "immutable", Type: model.RepositoryTypeHosted, Format: "maven", DeploymentPolicy: model.DeploymentPolicyDisableRedeploy})
+	env.hosted(t, "disabled", "maven")
+	_, err := env.repositories.Update(context.Background(), "disabled", &dto.UpdateRepositoryRequest{Status: "inactive"})
+	require.NoError(t, err)
+	env.upload(t, "immutable", "com/x/lib/1.0/lib-1.0.jar", []byte("old"))
+
+	jar := "com/x/lib/1.0/lib-1.0.jar"
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		source string
+		req    dto.PromoteRequest
+		want   error
+	}{
+		{name: "proxy_source", source: "central", req: dto.PromoteRequest{Target: "libs-release", Path: jar}, want: errs.ErrInvalidArgument},
+		{name: "group_source", source: "public", req: dto.PromoteRequest{Target: "libs-release", Path: jar}, want: errs.ErrInvalidArgument},
+		{name: "missing_source", source: "nope", req: dto.PromoteRequest{Target: "libs-release", Path: jar}, want: errs.ErrNotFound},
+		{name: "missing_target", source: "libs-staging", req: dto.PromoteRequest{Target: "nope", Path: jar}, want: errs.ErrNotFound},
+		{name: "same_repository", source: "libs-staging", req: dto.PromoteRequest{Target: "libs-staging", Path: jar}, want: errs.ErrInvalidArgument},
+		{name: "group_target", source: "libs-staging", req: dto.PromoteRequest{Target: "public", Path: jar}, want: errs.ErrInvalidArgument},
+		{name: "inactive_target", source: "libs-staging", req: dto.PromoteRequest{Target: "disabled", Path: jar}, want: errs.ErrInvalidArgument},
+		{name: "format_mismatch", source: "libs-staging", req: dto.PromoteRequest{Target: "npm-hosted", Path: jar}, want: errs.ErrInvalidArgument},
+		{name: "read_only_target", source: "libs-staging", req: dto.PromoteRequest{Target: "frozen", Path: jar}, want: errs.ErrConflict},
+		{name: "redeploy_disabled", source: "libs-staging", req: dto.PromoteRequest{Target: "immutable", Path: jar, Overwrite: true}, want: errs.ErrConflict},
+		{name: "no_selection", source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release"}, want: errs.ErrInvalidArgument},
+		{name: "wildcard", source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release", Name: "lib", Version: "1.*"}, want: errs.ErrInvalidArgument},
+		{name: "invalid_path", source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release", Path: "../x"}, want: errs.ErrInvalidArgument},
+		{name: "missing_artifact", source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release", Path: "com/x/none.jar"}, want: errs.ErrNotFound},
+		{name: "missing_version", source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release", Name: "lib", Version: "9.9"}, want: errs.ErrNotFound},
+		{name: "target_write_denied", ctx: asUser("qa", "repository:maven:*:read"),
+			source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release", Path: jar}, want: errs.ErrForbidden},
+		{name: "move_requires_delete", ctx: asUser("qa", "repository:maven:*:read", "repository:maven:*:write"),
+			source: "libs-staging", req: dto.PromoteRequest{Target: "libs-release", Path: jar, Move: true}, want: errs.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			req := tt.req
+			_, err := promotion.Promote(ctx, tt.source, &req)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err = env.download("libs-release", jar)
+	assert.ErrorIs(t, err, errs.ErrNotFound, "rejected promotions must not transfer anything")
+}
+
+// 单个文件失败时其余文件照常晋级，结果中逐个列出失败原因
+func TestPromotionService_PartialFailure(t *testing.T) {
+	env, promotion := newPromotionEnv(t)
+	ctx := context.Background()
+	env.artifacts.storage = failingStorage{plainStorage: plainStorage{env.storage}, suffix: ".pom"}
+
+	result, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "libs-release", Name: "lib", Version: "1.0", Move: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"com/x/lib/1.0/lib-1.0.jar", "com/x/lib/1.0/lib-1.0.jar.sha1"}, promotedPaths(result))
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "com/x/lib/1.0/lib-1.0.pom", result.Failed[0].Path)
+	assert.Contains(t, result.Failed[0].Error, "disk read error")
+
+	// 失败的文件仍留在源仓库中
+	repo, err := env.repos.FindByName(ctx, "libs-staging")
+	require.NoError(t, err)
+	pom, err := env.artifactRepo.FindByPath(ctx, repo.ID, "com/x/lib/1.0/lib-1.0.pom")
+	require.NoError(t, err)
+	assert.NotNil(t, pom)
+
+	t.Run("all_failed", func(t *testing.T) {
+		_, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "libs-release", Path: "com/x/lib/1.0/lib-1.0.pom"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "disk read error")
+	})
+}
@@ -0,0 +1,195 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// maxBulkArtifacts 单次批量修改属性最多处理的制品数量
+const maxBulkArtifacts = 10000
+
+// PropertyServiceImpl 制品属性服务实现
+type PropertyServiceImpl struct {
+	logger     *slog.Logger
+	artifacts  *ArtifactServiceImpl
+	search     *SearchServiceImpl
+	authorizer *AuthorizerImpl
+}
+
+// NewPropertyService 创建新的制品属性服务实现
+func NewPropertyService(logger *slog.Logger, artifacts *ArtifactServiceImpl, search *SearchServiceImpl, authorizer *AuthorizerImpl) *PropertyServiceImpl {
+	return &PropertyServiceImpl{
+		logger:     logger,
+		artifacts:  artifacts,
+		search:     search,
+		authorizer: authorizer,
+	}
+}
+
+// Get 获取制品属性
+func (s *PropertyServiceImpl) Get(ctx context.Context, repoIDOrName, rawPath string) (map[string]string, error) {
+	artifact, err := s.find(ctx, repoIDOrName, rawPath)
+	if err != nil {
+		return nil, err
+	}
+	return propertiesOf(artifact), nil
+}
+
+// Set 设置属性
+func (s *PropertyServiceImpl) Set(ctx context.Context, repoIDOrName, rawPath string, properties map[string]string) (map[string]string, error) {
+	if len(properties) == 0 {
+		return nil, errs.InvalidArgument("no properties to set")
+	}
+	if err := validatePropertyKeys(properties); err != nil {
+		return nil, err
+	}
+	artifact, err := s.find(ctx, repoIDOrName, rawPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.update(ctx, artifact, properties, nil); err != nil {
+		return nil, err
+	}
+	return propertiesOf(artifact), nil
+}
+
+// Delete 删除属性
+func (s *PropertyServiceImpl) Delete(ctx context.Context, repoIDOrName, rawPath string, keys []string) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, errs.InvalidArgument("no property keys to delete")
+	}
+	artifact, err := s.find(ctx, repoIDOrName, rawPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.update(ctx, artifact, nil, keys); err != nil {
+		return nil, err
+	}
+	return propertiesOf(artifact), nil
+}
+
+// BulkUpdate 先收集所有匹配的制品再逐个修改，避免修改属性影响后续分页；调用者需要对所有匹配制品所在仓库的写入权限
+func (s *PropertyServiceImpl) BulkUpdate(ctx context.Context, req *dto.BulkPropertiesRequest) (*dto.BulkPropertiesResult, error) {
+	if len(req.Set) == 0 && len(req.Delete) == 0 {
+		return nil, errs.InvalidArgument("set or delete is required")
+	}
+	if err := validatePropertyKeys(req.Set); err != nil {
+		return nil, err
+	}
+	if !hasSearchCondition(&req.Query) {
+		return nil, errs.InvalidArgument("query requires at least one search condition")
+	}
+
+	query := req.Query
+	query.Sort, query.Limit, query.Cursor = "", maxPageLimit, ""
+	var matched []*model.Artifact
+	for {
+		page, err := s.search.Search(ctx, &query)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range page.Items {
+			matched = append(matched, hit.Artifact)
+		}
+		if len(matched) > maxBulkArtifacts {
+			return nil, errs.InvalidArgument("query matches more than %d artifacts, narrow the search", maxBulkArtifacts)
+		}
+		if !page.HasMore {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	checked := make(map[string]bool)
+	for _, artifact := range matched {
+		if checked[artifact.RepositoryID] {
+			continue
+		}
+		if err := s.authorizer.AuthorizeRepository(ctx, artifact.RepositoryID, auth.ActionWrite); err != nil {
+			return nil, err
+		}
+		checked[artifact.RepositoryID] = true
+	}
+
+	result := &dto.BulkPropertiesResult{Matched: len(matched)}
+	for _, artifact := range matched {
+		changed, err := s.update(ctx, artifact, req.Set, req.Delete)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			result.Updated++
+		}
+	}
+	s.logger.Info("Artifact properties updated in bulk", "matched", result.Matched, "updated", result.Updated)
+	return result, nil
+}
+
+// find 查找宿主或代理仓库中的制品记录
+func (s *PropertyServiceImpl) find(ctx context.Context, repoIDOrName, rawPath string) (*model.Artifact, error) {
+	repo, err := lookupRepository(ctx, s.artifacts.repositories, repoIDOrName)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Type == model.RepositoryTypeGroup {
+		return nil, errs.InvalidArgument("repository %q is a group repository, set properties on its member", repo.Name)
+	}
+	p, err := normalizePath(rawPath)
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := s.artifacts.repository.FindByPath(ctx, repo.ID, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find artifact: %w", err)
+	}
+	if artifact == nil {
+		return nil, errs.NotFound("artifact %q not found in repository %q", p, repo.Name)
+	}
+	return artifact, nil
+}
+
+// update 设置并删除属性，没有变化时不写数据库
+func (s *PropertyServiceImpl) update(ctx context.Context, artifact *model.Artifact, set map[string]string, remove []string) (bool, error) {
+	properties := maps.Clone(artifact.Properties)
+	if properties == nil {
+		properties = make(map[string]string)
+	}
+	maps.Copy(properties, set)
+	for _, key := range remove {
+		delete(properties, key)
+	}
+	if maps.Equal(properties, artifact.Properties) {
+		return false, nil
+	}
+
+	artifact.Properties = properties
+	if err := s.artifacts.repository.Update(ctx, artifact); err != nil {
+		return false, fmt.Errorf("failed to update artifact properties: %w", err)
+	}
+	return true, nil
+}
+
+// propertiesOf 返回制品属性，没有属性时返回空映射
+func propertiesOf(artifact *model.Artifact) map[string]string {
+	if artifact.Properties == nil {
+		return map[string]string{}
+	}
+	return artifact.Properties
+}
+
+// hasSearchCondition 批量操作必须带有过滤条件，防止误改所有制品
+func hasSearchCondition(q *dto.SearchQuery) bool {
+	for _, v := range []string{q.Q, q.Name, q.Version, q.VersionRange, q.Format, q.Repository, q.SHA256, q.GroupID, q.ArtifactID, q.Keyword} {
+		if v != "" {
+			return true
+		}
+	}
+	return len(q.Properties) > 0
+}
@@ -0,0 +1,187 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// newPropertyEnv 在搜索测试环境上创建属性服务
+func newPropertyEnv(t *testing.T) (*testEnv, *SearchServiceImpl, *PropertyServiceImpl) {
+	t.Helper()
+	env, search := newSearchEnv(t)
+	return env, search, NewPropertyService(env.logger, env.artifacts, search, env.authorizer)
+}
+
+func TestPropertyService_SetGetDelete(t *testing.T) {
+	env, _, properties := newPropertyEnv(t)
+	ctx := context.Background()
+	path := "com/x/json/1.0/json-1.0.pom"
+
+	got, err := properties.Get(ctx, "releases", path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{}, got)
+
+	got, err = properties.Set(ctx, "releases", "/"+path, map[string]string{"build.number": "42", "git:commit": "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"build.number": "42", "git:commit": "abc"}, got)
+
+	got, err = properties.Set(ctx, "releases", path, map[string]string{"build.number": "43", "qa": "passed"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"build.number": "43", "git:commit": "abc", "qa": "passed"}, got)
+
+	got, err = properties.Delete(ctx, "releases", path, []string{"git:commit", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"build.number": "43", "qa": "passed"}, got)
+
+	repo, err := env.repos.FindByName(ctx, "releases")
+	require.NoError(t, err)
+	stored, err := env.artifactRepo.FindByPath(ctx, repo.ID, path)
+	require.NoError(t, err)
+	assert.Equal(t, got, stored.Properties)
+}
+
+func TestPropertyService_RedeployKeepsProperties(t *testing.T) {
+	env, _, properties := newPropertyEnv(t)
+	ctx := context.Background()
+	path := "com/x/json/1.0/json-1.0.pom"
+	_, err := properties.Set(ctx, "releases", path, map[string]string{"qa": "passed", "build.number": "42"})
+	require.NoError(t, err)
+
+	env.upload(t, "releases", path, pomOf("com.x", "json", "1.0", "json parser"))
+	got, err := properties.Get(ctx, "releases", path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"qa": "passed", "build.number": "42"}, got, "a redeploy without properties keeps them")
+
+	_, err = env.artifacts.Upload(ctx, "releases", &dto.UploadArtifactRequest{Path: path, Data: pomOf("com.x", "json", "1.0", "json parser"),
+		Properties: map[string]string{"build.number": "43"}})
+	require.NoError(t, err)
+	got, err = properties.Get(ctx, "releases", path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"qa": "passed", "build.number": "43"}, got, "uploaded properties override only their keys")
+}
+
+func TestPropertyService_Rejects(t *testing.T) {
+	_, _, properties := newPropertyEnv(t)
+	ctx := context.Background()
+	path := "com/x/json/1.0/json-1.0.pom"
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "set_nothing", call: func() error { _, err := properties.Set(ctx, "releases", path, nil); return err }, want: errs.ErrInvalidArgument},
+		{name: "invalid_key", call: func() error {
+			_, err := properties.Set(ctx, "releases", path, map[string]string{"a'b": "x"})
+			return err
+		}, want: errs.ErrInvalidArgument},
+		{name: "delete_nothing", call: func() error { _, err := properties.Delete(ctx, "releases", path, nil); return err }, want: errs.ErrInvalidArgument},
+		{name: "group_repository", call: func() error { _, err := properties.Get(ctx, "public", path); return err }, want: errs.ErrInvalidArgument},
+		{name: "invalid_path", call: func() error { _, err := properties.Get(ctx, "releases", "../x"); return err }, want: errs.ErrInvalidArgument},
+		{name: "missing_artifact", call: func() error { _, err := properties.Get(ctx, "releases", "com/x/none.jar"); return err }, want: errs.ErrNotFound},
+		{name: "missing_repository", call: func() error { _, err := properties.Get(ctx, "nope", path); return err }, want: errs.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), tt.want)
+		})
+	}
+}
+
+func TestPropertyService_BulkUpdate(t *testing.T) {
+	_, search, properties := newPropertyEnv(t)
+	ctx := context.Background()
+
+	result, err := properties.BulkUpdate(ctx, &dto.BulkPropertiesRequest{
+		Query: dto.SearchQuery{Name: "http-client"},
+		Set:   map[string]string{"qa": "passed"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &dto.BulkPropertiesResult{Matched: 2, Updated: 2}, result)
+
+	t.Run("unchanged_not_counted", func(t *testing.T) {
+		result, err := properties.BulkUpdate(ctx, &dto.BulkPropertiesRequest{
+			Query: dto.SearchQuery{Name: "http-client"},
+			Set:   map[string]string{"qa": "passed"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.BulkPropertiesResult{Matched: 2, Updated: 0}, result)
+	})
+
+	t.Run("filter_by_property", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			filters []string
+			want    []string
+		}{
+			{name: "value", filters: []string{"qa=passed"}, want: []string{
+				"releases:com/x/http-client/2.0/http-client-2.0.pom", "releases:com/x/http-client/1.0/http-client-1.0.pom"}},
+			{name: "exists", filters: []string{"qa"}, want: []string{
+				"releases:com/x/http-client/2.0/http-client-2.0.pom", "releases:com/x/http-client/1.0/http-client-1.0.pom"}},
+			{name: "other_value", filters: []string{"qa=failed"}},
+			{name: "missing_key", filters: []string{"build.number"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := search.Search(ctx, &dto.SearchQuery{Properties: tt.filters})
+				require.NoError(t, err)
+				assert.ElementsMatch(t, tt.want, searchPaths(page))
+			})
+		}
+	})
+
+	t.Run("delete_by_property", func(t *testing.T) {
+		result, err := properties.BulkUpdate(ctx, &dto.BulkPropertiesRequest{
+			Query:  dto.SearchQuery{Properties: []string{"qa=passed"}},
+			Delete: []string{"qa"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &dto.BulkPropertiesResult{Matched: 2, Updated: 2}, result)
+		page, err := search.Search(ctx, &dto.SearchQuery{Properties: []string{"qa"}})
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+}
+
+func TestPropertyService_BulkUpdateRejects(t *testing.T) {
+	_, search, properties := newPropertyEnv(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *dto.BulkPropertiesRequest
+		want error
+	}{
+		{name: "nothing_to_change", req: &dto.BulkPropertiesRequest{Query: dto.SearchQuery{Name: "json"}}, want: errs.ErrInvalidArgument},
+		{name: "no_condition", req: &dto.BulkPropertiesRequest{Set: map[string]string{"qa": "x"}}, want: errs.ErrInvalidArgument},
+		{name: "invalid_key", req: &dto.BulkPropertiesRequest{Query: dto.SearchQuery{Name: "json"}, Set: map[string]string{"bad key": "x"}}, want: errs.ErrInvalidArgument},
+		{name: "invalid_filter", req: &dto.BulkPropertiesRequest{Query: dto.SearchQuery{Properties: []string{"bad key=x"}}, Set: map[string]string{"qa": "x"}}, want: errs.ErrInvalidArgument},
+		{
+			// 可以读取两个仓库但只能写入其中一个时整体拒绝
+			name: "write_denied_on_any_repository",
+			ctx:  asUser("ci", "repository:maven:*:read", "repository:maven:releases:write"),
+			req:  &dto.BulkPropertiesRequest{Query: dto.SearchQuery{Q: "http"}, Set: map[string]string{"qa": "x"}},
+			want: errs.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			_, err := properties.BulkUpdate(ctx, tt.req)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	page, err := search.Search(context.Background(), &dto.SearchQuery{Properties: []string{"qa"}})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items, "rejected requests must not modify any artifact")
+}
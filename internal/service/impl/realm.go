@@ -0,0 +1,96 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// Realm 认证域，校验用户名与密码并返回本地用户
+// 用户名或密码错误时返回 errs.ErrUnauthenticated，认证链继续尝试下一个认证域
+type Realm interface {
+	Name() string
+	Authenticate(ctx context.Context, username, password string) (*model.User, error)
+}
+
+// externalUser 外部认证域认证通过的用户，Roles 为映射后的角色名称
+type externalUser struct {
+	Realm    string
+	Username string
+	Email    string
+	FullName string
+	Roles    []string
+}
+
+// localRealm 本地认证域，校验数据库中的密码哈希
+type localRealm struct {
+	users *UserServiceImpl
+}
+
+// Name 认证域名称
+func (r *localRealm) Name() string {
+	return model.UserRealmLocal
+}
+
+// Authenticate 校验本地用户的密码
+func (r *localRealm) Authenticate(ctx context.Context, username, password string) (*model.User, error) {
+	return r.users.Authenticate(ctx, username, password)
+}
+
+// RealmChain 认证链，登录时按 security.realms 的顺序尝试各认证域
+type RealmChain struct {
+	logger *slog.Logger
+	realms []Realm
+}
+
+// NewRealmChain 按配置创建认证链
+func NewRealmChain(cfg *config.Config, logger *slog.Logger, users *UserServiceImpl) (*RealmChain, error) {
+	chain := &RealmChain{logger: logger}
+	for _, name := range cfg.Security.Realms {
+		switch name {
+		case model.UserRealmLocal:
+			chain.realms = append(chain.realms, &localRealm{users: users})
+		case model.UserRealmLDAP:
+			realm, err := NewLDAPRealm(&cfg.Security.LDAP, logger, users)
+			if err != nil {
+				return nil, err
+			}
+			chain.realms = append(chain.realms, realm)
+		default:
+			return nil, fmt.Errorf("unsupported security realm %q", name)
+		}
+	}
+	if len(chain.realms) == 0 {
+		chain.realms = append(chain.realms, &localRealm{users: users})
+	}
+	return chain, nil
+}
+
+// Authenticate 依次尝试各认证域，第一个认证通过的认证域生效
+// 账户被停用或锁定时立即返回；所有认证域都失败且有认证域不可用时返回 503，否则返回 401
+func (c *RealmChain) Authenticate(ctx context.Context, username, password string) (*model.User, error) {
+	var unavailable []string
+	for _, realm := range c.realms {
+		user, err := realm.Authenticate(ctx, username, password)
+		if err == nil {
+			return user, nil
+		}
+		if errors.Is(err, errs.ErrUnauthenticated) {
+			continue
+		}
+		if errors.Is(err, errs.ErrForbidden) {
+			return nil, err
+		}
+		c.logger.Error("Authentication realm failed", "realm", realm.Name(), "username", username, "error", err)
+		unavailable = append(unavailable, realm.Name())
+	}
+	if len(unavailable) > 0 {
+		return nil, errs.Unavailable("authentication realm %v is unavailable", unavailable)
+	}
+	return nil, errs.Unauthenticated("invalid username or password")
+}
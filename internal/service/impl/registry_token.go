@@ -0,0 +1,215 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	// tokenTypeRegistry Docker 仓库令牌，不能用于访问 /api/v1
+	tokenTypeRegistry = "registry"
+
+	defaultRegistryTokenExpire = 5 * time.Minute
+)
+
+// registryActions Docker 操作对应的仓库操作
+var registryActions = map[string]string{
+	"pull":   auth.ActionRead,
+	"push":   auth.ActionWrite,
+	"delete": auth.ActionDelete,
+	"*":      auth.ActionAdmin,
+}
+
+// registryClaims Docker 仓库令牌声明，Subject 为用户名，匿名调用者为空
+type registryClaims struct {
+	jwt.RegisteredClaims
+	Type   string               `json:"typ"`
+	Access []dto.RegistryAccess `json:"access"`
+}
+
+// RegistryTokenServiceImpl Docker 仓库令牌服务实现
+// 镜像名称的第一段为 go-nexus 仓库名称，如 docker-hosted/library/alpine 属于 docker-hosted 仓库；
+// pull、push、delete 分别需要仓库的 read、write、delete 权限，* 需要 admin 权限
+type RegistryTokenServiceImpl struct {
+	logger       *slog.Logger
+	auth         *AuthServiceImpl
+	authorizer   *AuthorizerImpl
+	repositories repository.RepositoryRepository
+	service      string
+	realm        string
+	expire       time.Duration
+}
+
+// NewRegistryTokenService 创建新的 Docker 仓库令牌服务实现，令牌使用 JWT 密钥签名
+func NewRegistryTokenService(
+	cfg *config.Config,
+	logger *slog.Logger,
+	authService *AuthServiceImpl,
+	authorizer *AuthorizerImpl,
+	repositories repository.RepositoryRepository,
+) *RegistryTokenServiceImpl {
+	s := &RegistryTokenServiceImpl{
+		logger:       logger,
+		auth:         authService,
+		authorizer:   authorizer,
+		repositories: repositories,
+		service:      cfg.Security.Docker.Service,
+		realm:        cfg.Security.Docker.Realm,
+		expire:       cfg.Security.Docker.TokenExpire,
+	}
+	if s.service == "" {
+		s.service = tokenIssuer
+	}
+	if s.expire <= 0 {
+		s.expire = defaultRegistryTokenExpire
+	}
+	return s
+}
+
+// Issue 按调用者的角色权限与令牌范围计算每个 scope 可授予的操作并签发令牌
+func (s *RegistryTokenServiceImpl) Issue(ctx context.Context, req *dto.RegistryTokenRequest) (*dto.RegistryToken, error) {
+	if req.Service != "" && req.Service != s.service {
+		return nil, errs.InvalidArgument("unknown service %q", req.Service)
+	}
+	principal, err := s.authorizer.principal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if principal == nil {
+		return nil, errs.Unauthenticated("authentication required")
+	}
+
+	access := []dto.RegistryAccess{}
+	for _, param := range req.Scope {
+		for _, scope := range strings.Fields(param) {
+			granted, err := s.grant(ctx, principal, scope)
+			if err != nil {
+				return nil, err
+			}
+			if granted != nil {
+				access = append(access, *granted)
+			}
+		}
+	}
+
+	now := time.Now()
+	subject := principal.Username
+	if principal.Anonymous {
+		subject = ""
+	}
+	claims := &registryClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    tokenIssuer,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{s.service},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.expire)),
+		},
+		Type:   tokenTypeRegistry,
+		Access: access,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.auth.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign registry token: %w", err)
+	}
+	s.logger.Debug("Registry token issued", "username", principal.Username, "access", access)
+	return &dto.RegistryToken{
+		Token:       signed,
+		AccessToken: signed,
+		ExpiresIn:   int64(s.expire / time.Second),
+		IssuedAt:    now.UTC(),
+		Access:      access,
+	}, nil
+}
+
+// Authorize 校验令牌签名、受众与有效期，name 非空时要求令牌授予了对该镜像的操作
+func (s *RegistryTokenServiceImpl) Authorize(ctx context.Context, token, name, action string) error {
+	claims := &registryClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.auth.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithAudience(s.service),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Type != tokenTypeRegistry {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return errs.Unauthenticated("token has expired")
+		}
+		return errs.Unauthenticated("invalid token")
+	}
+	if name == "" {
+		return nil
+	}
+	for _, access := range claims.Access {
+		if access.Type != "repository" || access.Name != name {
+			continue
+		}
+		for _, granted := range access.Actions {
+			if granted == action || granted == "*" {
+				return nil
+			}
+		}
+	}
+	return errs.Forbidden("token does not grant %s access to %q", action, name)
+}
+
+// Service 认证提示中的 service
+func (s *RegistryTokenServiceImpl) Service() string {
+	return s.service
+}
+
+// Realm 配置的令牌服务地址
+func (s *RegistryTokenServiceImpl) Realm() string {
+	return s.realm
+}
+
+// grant 计算单个 scope 可授予的操作，不支持的资源类型不授予，仓库不存在时授予空操作列表
+func (s *RegistryTokenServiceImpl) grant(ctx context.Context, principal *auth.Principal, scope string) (*dto.RegistryAccess, error) {
+	// 名称中不含冒号，操作列表位于最后一个冒号之后
+	first := strings.Index(scope, ":")
+	last := strings.LastIndex(scope, ":")
+	if first <= 0 || last == first {
+		return nil, errs.InvalidArgument("invalid scope %q, expected repository:<name>:<actions>", scope)
+	}
+	resourceType, name, actions := scope[:first], scope[first+1:last], strings.Split(scope[last+1:], ",")
+	if resourceType != "repository" || name == "" {
+		return nil, nil
+	}
+
+	repoName, _, _ := strings.Cut(name, "/")
+	repo, err := s.repositories.FindByName(ctx, repoName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repository: %w", err)
+	}
+	granted := &dto.RegistryAccess{Type: resourceType, Name: name, Actions: []string{}}
+	if repo == nil {
+		return granted, nil
+	}
+	for _, action := range actions {
+		required, ok := registryActions[action]
+		if !ok || containsString(granted.Actions, action) {
+			continue
+		}
+		if authorizeRepository(principal, repo, required) == nil {
+			granted.Actions = append(granted.Actions, action)
+		}
+	}
+	return granted, nil
+}
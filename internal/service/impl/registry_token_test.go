@@ -0,0 +1,231 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+// newRegistryEnv 创建 Docker 仓库令牌服务与 docker-team、docker-libs 两个 Docker 仓库
+// 没有 Docker 格式插件，仓库直接写入数据库
+func newRegistryEnv(t *testing.T, configure func(cfg *config.DockerTokenConfig)) (*authEnv, *RegistryTokenServiceImpl) {
+	t.Helper()
+	env := newAuthEnv(t, func(cfg *config.Config) {
+		cfg.Security.Docker = config.DockerTokenConfig{Service: "registry.example.com", TokenExpire: 10 * time.Minute}
+		if configure != nil {
+			configure(&cfg.Security.Docker)
+		}
+	})
+	for _, name := range []string{"docker-team", "docker-libs"} {
+		require.NoError(t, env.repos.Create(context.Background(), &model.Repository{
+			ID: uuid.New().String(), Name: name, Type: model.RepositoryTypeHosted, Format: "docker",
+		}))
+	}
+	return env, NewRegistryTokenService(env.cfg, env.logger, env.auth, env.authorizer, env.repos)
+}
+
+// parseRegistryToken 解析令牌声明，不校验签名
+func parseRegistryToken(t *testing.T, token string) *registryClaims {
+	t.Helper()
+	claims := &registryClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	require.NoError(t, err)
+	return claims
+}
+
+func TestNewRegistryTokenService(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.DockerTokenConfig
+		wantService string
+		wantRealm   string
+		wantExpire  time.Duration
+	}{
+		{name: "defaults", wantService: tokenIssuer, wantExpire: defaultRegistryTokenExpire},
+		{name: "configured", cfg: config.DockerTokenConfig{Service: "registry.example.com", Realm: "https://nexus.example.com/api/v1/auth/docker/token", TokenExpire: time.Hour},
+			wantService: "registry.example.com", wantRealm: "https://nexus.example.com/api/v1/auth/docker/token", wantExpire: time.Hour},
+		{name: "negative_expire", cfg: config.DockerTokenConfig{TokenExpire: -time.Minute}, wantService: tokenIssuer, wantExpire: defaultRegistryTokenExpire},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Security.Docker = tt.cfg
+			s := NewRegistryTokenService(cfg, nil, nil, nil, nil)
+			assert.Equal(t, tt.wantService, s.Service())
+			assert.Equal(t, tt.wantRealm, s.Realm())
+			assert.Equal(t, tt.wantExpire, s.expire)
+		})
+	}
+}
+
+func TestRegistryTokenService_Issue(t *testing.T) {
+	env, registry := newRegistryEnv(t, nil)
+	anonymous, err := env.roleRepo.FindByName(context.Background(), model.RoleAnonymous)
+	require.NoError(t, err)
+	anonymous.Permissions = []string{auth.RepositoryPermission("docker", "docker-libs", auth.ActionRead)}
+	require.NoError(t, env.roleRepo.Update(context.Background(), anonymous))
+	team := asUser("team",
+		auth.RepositoryPermission("docker", "docker-team", auth.ActionWrite),
+		auth.RepositoryPermission("docker", "docker-libs", auth.ActionRead),
+	)
+	access := func(name string, actions ...string) dto.RegistryAccess {
+		if actions == nil {
+			actions = []string{}
+		}
+		return dto.RegistryAccess{Type: "repository", Name: name, Actions: actions}
+	}
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		scope []string
+		want  []dto.RegistryAccess
+	}{
+		{name: "no_scope", ctx: team, want: []dto.RegistryAccess{}},
+		{name: "pull_push", ctx: team, scope: []string{"repository:docker-team/app:pull,push"}, want: []dto.RegistryAccess{access("docker-team/app", "pull", "push")}},
+		{name: "push_needs_write", ctx: team, scope: []string{"repository:docker-libs/alpine:pull,push"}, want: []dto.RegistryAccess{access("docker-libs/alpine", "pull")}},
+		{name: "delete_needs_delete", ctx: team, scope: []string{"repository:docker-team/app:delete"}, want: []dto.RegistryAccess{access("docker-team/app")}},
+		{name: "wildcard_needs_admin", ctx: team, scope: []string{"repository:docker-team/app:*"}, want: []dto.RegistryAccess{access("docker-team/app")}},
+		{name: "admin_gets_everything", ctx: asUser("admin", auth.PermissionAll), scope: []string{"repository:docker-team/app:pull,push,delete,*"},
+			want: []dto.RegistryAccess{access("docker-team/app", "pull", "push", "delete", "*")}},
+		{name: "nested_name", ctx: team, scope: []string{"repository:docker-team/library/alpine:push"}, want: []dto.RegistryAccess{access("docker-team/library/alpine", "push")}},
+		{name: "repository_root", ctx: team, scope: []string{"repository:docker-team:pull"}, want: []dto.RegistryAccess{access("docker-team", "pull")}},
+		{name: "unknown_repository", ctx: team, scope: []string{"repository:missing/app:pull"}, want: []dto.RegistryAccess{access("missing/app")}},
+		{name: "duplicates_and_unknown_actions", ctx: team, scope: []string{"repository:docker-team/app:pull,pull,fly"}, want: []dto.RegistryAccess{access("docker-team/app", "pull")}},
+		{name: "other_resource_type_ignored", ctx: team, scope: []string{"registry:catalog:*"}, want: []dto.RegistryAccess{}},
+		{name: "scopes_in_one_parameter", ctx: team, scope: []string{"repository:docker-team/app:push repository:docker-libs/alpine:pull"},
+			want: []dto.RegistryAccess{access("docker-team/app", "push"), access("docker-libs/alpine", "pull")}},
+		{name: "repeated_parameters", ctx: team, scope: []string{"repository:docker-team/app:pull", "repository:docker-libs/alpine:pull"},
+			want: []dto.RegistryAccess{access("docker-team/app", "pull"), access("docker-libs/alpine", "pull")}},
+		{name: "token_scope_narrows", ctx: withToken([]string{auth.ScopeRepoRead}, auth.PermissionAll), scope: []string{"repository:docker-team/app:pull,push"},
+			want: []dto.RegistryAccess{access("docker-team/app", "pull")}},
+		{name: "token_scope_other_repository", ctx: withToken([]string{"repo:write:docker-libs"}, auth.PermissionAll), scope: []string{"repository:docker-team/app:pull"},
+			want: []dto.RegistryAccess{access("docker-team/app")}},
+		{name: "anonymous_role", ctx: auth.WithPrincipal(context.Background(), auth.AnonymousPrincipal), scope: []string{"repository:docker-libs/alpine:pull,push", "repository:docker-team/app:pull"},
+			want: []dto.RegistryAccess{access("docker-libs/alpine", "pull"), access("docker-team/app")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := registry.Issue(tt.ctx, &dto.RegistryTokenRequest{Service: "registry.example.com", Scope: tt.scope})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, token.Access)
+			assert.Equal(t, token.Token, token.AccessToken)
+			claims := parseRegistryToken(t, token.Token)
+			assert.Equal(t, tt.want, claims.Access, "the signed token carries the granted access")
+		})
+	}
+
+	t.Run("claims", func(t *testing.T) {
+		token, err := registry.Issue(team, &dto.RegistryTokenRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(600), token.ExpiresIn)
+		assert.WithinDuration(t, time.Now(), token.IssuedAt, time.Minute)
+		claims := parseRegistryToken(t, token.Token)
+		assert.Equal(t, tokenTypeRegistry, claims.Type)
+		assert.Equal(t, tokenIssuer, claims.Issuer)
+		assert.Equal(t, "team", claims.Subject)
+		assert.Equal(t, jwt.ClaimStrings{"registry.example.com"}, claims.Audience)
+		assert.NotEmpty(t, claims.ID)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt.Time, time.Minute)
+
+		anonymous, err := registry.Issue(auth.WithPrincipal(context.Background(), auth.AnonymousPrincipal), &dto.RegistryTokenRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, parseRegistryToken(t, anonymous.Token).Subject)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			ctx     context.Context
+			req     *dto.RegistryTokenRequest
+			wantErr error
+		}{
+			{name: "unknown_service", ctx: team, req: &dto.RegistryTokenRequest{Service: "other"}, wantErr: errs.ErrInvalidArgument},
+			{name: "missing_actions", ctx: team, req: &dto.RegistryTokenRequest{Scope: []string{"repository:docker-team"}}, wantErr: errs.ErrInvalidArgument},
+			{name: "missing_type", ctx: team, req: &dto.RegistryTokenRequest{Scope: []string{":docker-team:pull"}}, wantErr: errs.ErrInvalidArgument},
+			{name: "not_a_scope", ctx: team, req: &dto.RegistryTokenRequest{Scope: []string{"pull"}}, wantErr: errs.ErrInvalidArgument},
+			{name: "no_principal", ctx: context.Background(), req: &dto.RegistryTokenRequest{}, wantErr: errs.ErrUnauthenticated},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := registry.Issue(tt.ctx, tt.req)
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestRegistryTokenService_Authorize(t *testing.T) {
+	env, registry := newRegistryEnv(t, nil)
+	ctx := context.Background()
+	team := asUser("team", auth.RepositoryPermission("docker", "docker-team", auth.ActionWrite))
+	issued, err := registry.Issue(team, &dto.RegistryTokenRequest{Scope: []string{"repository:docker-team/app:pull,push"}})
+	require.NoError(t, err)
+	admin, err := registry.Issue(asUser("admin", auth.PermissionAll), &dto.RegistryTokenRequest{Scope: []string{"repository:docker-team/app:*"}})
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := func(typ, audience string, expires time.Time) *registryClaims {
+		return &registryClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    tokenIssuer,
+				Subject:   "team",
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(expires),
+			},
+			Type:   typ,
+			Access: []dto.RegistryAccess{{Type: "repository", Name: "docker-team/app", Actions: []string{"pull"}}},
+		}
+	}
+	_, other := newRegistryEnv(t, func(cfg *config.DockerTokenConfig) { cfg.Service = "other-registry" })
+	otherService, err := other.Issue(team, &dto.RegistryTokenRequest{Scope: []string{"repository:docker-team/app:pull"}})
+	require.NoError(t, err)
+	noExpiry := claims(tokenTypeRegistry, "registry.example.com", now)
+	noExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name    string
+		token   string
+		image   string
+		action  string
+		wantErr error
+		wantMsg string
+	}{
+		{name: "pull", token: issued.Token, image: "docker-team/app", action: "pull"},
+		{name: "push", token: issued.Token, image: "docker-team/app", action: "push"},
+		{name: "action_not_granted", token: issued.Token, image: "docker-team/app", action: "delete", wantErr: errs.ErrForbidden},
+		{name: "other_image", token: issued.Token, image: "docker-team/other", action: "pull", wantErr: errs.ErrForbidden},
+		{name: "wildcard_grants_all", token: admin.Token, image: "docker-team/app", action: "delete"},
+		{name: "ping_only_checks_token", token: issued.Token},
+		{name: "expired", token: signTestToken(t, testJWTSecret, claims(tokenTypeRegistry, "registry.example.com", now.Add(-time.Minute))),
+			wantErr: errs.ErrUnauthenticated, wantMsg: "expired"},
+		{name: "missing_expiry", token: signTestToken(t, testJWTSecret, noExpiry), wantErr: errs.ErrUnauthenticated},
+		{name: "other_service", token: otherService.Token, image: "docker-team/app", action: "pull", wantErr: errs.ErrUnauthenticated},
+		{name: "access_token_rejected", token: env.login(t, "admin", "admin-password").AccessToken, wantErr: errs.ErrUnauthenticated},
+		{name: "wrong_type", token: signTestToken(t, testJWTSecret, claims(tokenTypeAccess, "registry.example.com", now.Add(time.Minute))), wantErr: errs.ErrUnauthenticated},
+		{name: "forged", token: signTestToken(t, "another-secret-0123456789abcdef", claims(tokenTypeRegistry, "registry.example.com", now.Add(time.Minute))), wantErr: errs.ErrUnauthenticated},
+		{name: "garbage", token: "not-a-jwt", wantErr: errs.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Authorize(ctx, tt.token, tt.image, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), tt.wantMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// defaultRemoteTimeout 代理仓库访问远程地址的默认超时时间
+const defaultRemoteTimeout = 60 * time.Second
+
+// remoteFetcher 代理仓库远程内容获取器
+// 每次访问结果都会反馈给 monitor，远程被阻断时直接返回 Unavailable 错误而不发起请求
+type remoteFetcher struct {
+	client  *http.Client
+	monitor *RemoteMonitor
+}
+
+// newRemoteFetcher 创建远程内容获取器
+func newRemoteFetcher(monitor *RemoteMonitor) *remoteFetcher {
+	return &remoteFetcher{client: &http.Client{}, monitor: monitor}
+}
+
+// Exists 通过 HEAD 请求检查远程是否存在该路径
+func (f *remoteFetcher) Exists(ctx context.Context, repo *model.Repository, p string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout(repo))
+	defer cancel()
+
+	resp, err := f.do(ctx, http.MethodHead, repo, p)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		err := fmt.Errorf("remote %s returned status %d", resp.Request.URL, resp.StatusCode)
+		f.monitor.Failure(repo, err)
+		return false, err
+	}
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// Fetch 从代理仓库的远程地址获取内容，远程返回 404/410 时返回 NotFound 错误
+// 超时时间可通过仓库配置 remote_timeout 设置，如 "30s"
+func (f *remoteFetcher) Fetch(ctx context.Context, repo *model.Repository, p string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout(repo))
+	defer cancel()
+
+	resp, err := f.do(ctx, http.MethodGet, repo, p)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, errs.NotFound("artifact %q not found in remote of %q", p, repo.Name)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		err := fmt.Errorf("remote %s returned status %d", resp.Request.URL, resp.StatusCode)
+		f.monitor.Failure(repo, err)
+		return nil, err
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("remote %s returned status %d", resp.Request.URL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		err = fmt.Errorf("failed to read remote response: %w", err)
+		f.recordError(ctx, repo, err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// do 向代理仓库的远程地址发送请求
+// 远程被阻断时返回 Unavailable 错误；连接失败计入熔断，5xx 由调用方计入
+func (f *remoteFetcher) do(ctx context.Context, method string, repo *model.Repository, p string) (*http.Response, error) {
+	if !f.monitor.Allow(repo) {
+		return nil, errs.Unavailable("remote of repository %q is %s", repo.Name, f.monitor.Status(repo).Status)
+	}
+
+	remoteURL := strings.TrimSuffix(repo.URL, "/") + "/" + p
+	req, err := http.NewRequestWithContext(ctx, method, remoteURL, nil)
+	if err != nil {
+		f.monitor.Abandon(repo)
+		return nil, fmt.Errorf("failed to build remote request: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		err = fmt.Errorf("failed to request %s: %w", remoteURL, err)
+		f.recordError(ctx, repo, err)
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusInternalServerError {
+		f.monitor.Success(repo)
+	}
+	return resp, nil
+}
+
+// recordError 记录远程访问失败，调用方主动取消的请求不计入熔断，但需要释放探测名额
+func (f *remoteFetcher) recordError(ctx context.Context, repo *model.Repository, err error) {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		f.monitor.Abandon(repo)
+		return
+	}
+	f.monitor.Failure(repo, err)
+}
+
+// remoteTimeout 代理仓库的远程超时时间
+func remoteTimeout(repo *model.Repository) time.Duration {
+	if v, ok := repo.Config["remote_timeout"]; ok {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultRemoteTimeout
+}
@@ -0,0 +1,198 @@
+package impl
+
+import (
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+)
+
+// 自动阻断的默认参数，可通过仓库配置 auto_block、block_threshold、block_cooldown、block_max_cooldown 覆盖
+const (
+	defaultBlockThreshold   = 3
+	defaultBlockCooldown    = time.Minute
+	defaultBlockMaxCooldown = 30 * time.Minute
+)
+
+// RemoteMonitor 代理仓库远程健康监控（熔断器）
+//
+// 连续失败达到阈值后自动阻断远程，冷却期内只提供缓存内容；
+// 冷却期结束后放行一次探测请求，成功则恢复，失败则以加倍的冷却期再次阻断。
+// 状态只保存在内存中，手动阻断通过 model.Repository.Blocked 持久化。
+type RemoteMonitor struct {
+	logger *slog.Logger
+	mutex  sync.Mutex
+	states map[string]*remoteState
+	now    func() time.Time
+}
+
+// remoteState 单个代理仓库的熔断状态
+type remoteState struct {
+	failures      int
+	cooldown      time.Duration
+	blockedUntil  time.Time
+	probing       bool
+	lastError     string
+	lastFailureAt time.Time
+}
+
+// NewRemoteMonitor 创建代理仓库远程健康监控
+func NewRemoteMonitor(logger *slog.Logger) *RemoteMonitor {
+	return &RemoteMonitor{
+		logger: logger,
+		states: make(map[string]*remoteState),
+		now:    time.Now,
+	}
+}
+
+// Allow 判断当前是否允许访问远程
+func (m *RemoteMonitor) Allow(repo *model.Repository) bool {
+	if repo.Blocked {
+		return false
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	state, ok := m.states[repo.ID]
+	if !ok || state.blockedUntil.IsZero() {
+		return true
+	}
+	if m.now().Before(state.blockedUntil) || state.probing {
+		return false
+	}
+	// 冷却期结束，放行一次探测请求
+	state.probing = true
+	return true
+}
+
+// Success 记录一次远程访问成功，解除自动阻断
+func (m *RemoteMonitor) Success(repo *model.Repository) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if state, ok := m.states[repo.ID]; ok {
+		if !state.blockedUntil.IsZero() {
+			m.logger.Info("Proxy remote back online", "repository", repo.Name)
+		}
+		delete(m.states, repo.ID)
+	}
+}
+
+// Failure 记录一次远程访问失败，连续失败达到阈值时自动阻断
+func (m *RemoteMonitor) Failure(repo *model.Repository, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	state, ok := m.states[repo.ID]
+	if !ok {
+		state = &remoteState{}
+		m.states[repo.ID] = state
+	}
+	now := m.now()
+	state.failures++
+	state.lastError = err.Error()
+	state.lastFailureAt = now
+
+	if !configBool(repo, "auto_block", true) {
+		return
+	}
+
+	wasProbing := state.probing
+	state.probing = false
+	if !wasProbing && state.failures < configInt(repo, "block_threshold", defaultBlockThreshold) {
+		return
+	}
+
+	maxCooldown := configDuration(repo, "block_max_cooldown", defaultBlockMaxCooldown)
+	switch {
+	case state.cooldown == 0:
+		state.cooldown = configDuration(repo, "block_cooldown", defaultBlockCooldown)
+	case wasProbing:
+		state.cooldown *= 2
+	}
+	if state.cooldown > maxCooldown {
+		state.cooldown = maxCooldown
+	}
+	state.blockedUntil = now.Add(state.cooldown)
+
+	m.logger.Warn("Proxy remote auto-blocked",
+		"repository", repo.Name,
+		"failures", state.failures,
+		"cooldown", state.cooldown.String(),
+		"error", state.lastError,
+	)
+}
+
+// Abandon 放弃进行中的探测请求而不改变失败计数，探测请求被取消或未能发出时调用
+// 冷却期已结束，下一次请求会重新作为探测放行
+func (m *RemoteMonitor) Abandon(repo *model.Repository) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if state, ok := m.states[repo.ID]; ok {
+		state.probing = false
+	}
+}
+
+// Reset 清除仓库的熔断状态，仓库配置变更或删除时调用
+func (m *RemoteMonitor) Reset(repoID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.states, repoID)
+}
+
+// Status 返回代理仓库的远程状态，非代理仓库返回 nil
+func (m *RemoteMonitor) Status(repo *model.Repository) *model.RemoteStatus {
+	if repo.Type != model.RepositoryTypeProxy {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	status := &model.RemoteStatus{Status: model.RemoteStatusOnline}
+	if state, ok := m.states[repo.ID]; ok {
+		status.Failures = state.failures
+		status.LastError = state.lastError
+		lastFailureAt := state.lastFailureAt
+		status.LastFailureAt = &lastFailureAt
+		if !state.blockedUntil.IsZero() {
+			blockedUntil := state.blockedUntil
+			status.Status = model.RemoteStatusAutoBlocked
+			status.BlockedUntil = &blockedUntil
+		}
+	}
+	if repo.Blocked {
+		status.Status = model.RemoteStatusManuallyBlocked
+		status.BlockedUntil = nil
+	}
+	return status
+}
+
+// configInt 读取仓库整数配置
+func configInt(repo *model.Repository, key string, def int) int {
+	if v, err := strconv.Atoi(repo.Config[key]); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+// configBool 读取仓库布尔配置
+func configBool(repo *model.Repository, key string, def bool) bool {
+	if v, err := strconv.ParseBool(repo.Config[key]); err == nil {
+		return v
+	}
+	return def
+}
+
+// configDuration 读取仓库时长配置
+func configDuration(repo *model.Repository, key string, def time.Duration) time.Duration {
+	if v, err := time.ParseDuration(repo.Config[key]); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
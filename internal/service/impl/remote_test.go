@@ -0,0 +1,203 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// newTestMonitor 创建时间可控的熔断器
+func newTestMonitor() (*RemoteMonitor, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	monitor := NewRemoteMonitor(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	monitor.now = func() time.Time { return now }
+	return monitor, &now
+}
+
+func proxyRepo(url string, config map[string]string) *model.Repository {
+	return &model.Repository{ID: "proxy-id", Name: "proxy", Type: model.RepositoryTypeProxy, Format: "maven", URL: url, Config: config}
+}
+
+func TestRemoteMonitor_AutoBlock(t *testing.T) {
+	monitor, now := newTestMonitor()
+	repo := proxyRepo("http://remote", map[string]string{"block_threshold": "2", "block_cooldown": "1m", "block_max_cooldown": "3m"})
+	failure := errors.New("connection refused")
+
+	monitor.Failure(repo, failure)
+	assert.True(t, monitor.Allow(repo), "below threshold")
+	monitor.Failure(repo, failure)
+	assert.False(t, monitor.Allow(repo), "threshold reached")
+
+	status := monitor.Status(repo)
+	assert.Equal(t, model.RemoteStatusAutoBlocked, status.Status)
+	assert.Equal(t, 2, status.Failures)
+	assert.Equal(t, "connection refused", status.LastError)
+	assert.Equal(t, now.Add(time.Minute), *status.BlockedUntil)
+
+	// 冷却期结束后只放行一个探测请求
+	*now = now.Add(time.Minute)
+	assert.True(t, monitor.Allow(repo))
+	assert.False(t, monitor.Allow(repo), "only one probe at a time")
+
+	// 探测失败，冷却期加倍，不超过上限
+	monitor.Failure(repo, failure)
+	assert.Equal(t, now.Add(2*time.Minute), *monitor.Status(repo).BlockedUntil)
+	*now = now.Add(2 * time.Minute)
+	require.True(t, monitor.Allow(repo))
+	monitor.Failure(repo, failure)
+	assert.Equal(t, now.Add(3*time.Minute), *monitor.Status(repo).BlockedUntil)
+
+	// 探测成功后恢复
+	*now = now.Add(3 * time.Minute)
+	require.True(t, monitor.Allow(repo))
+	monitor.Success(repo)
+	assert.True(t, monitor.Allow(repo))
+	assert.Equal(t, model.RemoteStatusOnline, monitor.Status(repo).Status)
+}
+
+func TestRemoteMonitor_Settings(t *testing.T) {
+	monitor, _ := newTestMonitor()
+	failure := errors.New("timeout")
+
+	t.Run("auto_block_disabled", func(t *testing.T) {
+		repo := proxyRepo("http://remote", map[string]string{"auto_block": "false"})
+		for i := 0; i < 10; i++ {
+			monitor.Failure(repo, failure)
+		}
+		assert.True(t, monitor.Allow(repo))
+		assert.Equal(t, model.RemoteStatusOnline, monitor.Status(repo).Status)
+		assert.Equal(t, 10, monitor.Status(repo).Failures)
+		monitor.Reset(repo.ID)
+	})
+
+	t.Run("manually_blocked", func(t *testing.T) {
+		repo := proxyRepo("http://remote", nil)
+		repo.Blocked = true
+		assert.False(t, monitor.Allow(repo))
+		assert.Equal(t, model.RemoteStatusManuallyBlocked, monitor.Status(repo).Status)
+	})
+
+	t.Run("not_a_proxy", func(t *testing.T) {
+		assert.Nil(t, monitor.Status(&model.Repository{Type: model.RepositoryTypeHosted}))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		repo := proxyRepo("http://remote", nil)
+		for i := 0; i < defaultBlockThreshold; i++ {
+			monitor.Failure(repo, failure)
+		}
+		require.False(t, monitor.Allow(repo))
+		monitor.Reset(repo.ID)
+		assert.True(t, monitor.Allow(repo))
+	})
+}
+
+// blockAndExpire 让仓库进入自动阻断并结束冷却期，下一次 Allow 即为探测请求
+func blockAndExpire(t *testing.T, monitor *RemoteMonitor, now *time.Time, repo *model.Repository) {
+	t.Helper()
+	for i := 0; i < defaultBlockThreshold; i++ {
+		monitor.Failure(repo, errors.New("down"))
+	}
+	require.Equal(t, model.RemoteStatusAutoBlocked, monitor.Status(repo).Status)
+	*now = now.Add(defaultBlockMaxCooldown)
+}
+
+func TestRemoteFetcher_Fetch(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		io.WriteString(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	monitor, _ := newTestMonitor()
+	fetcher := newRemoteFetcher(monitor)
+	repo := proxyRepo(server.URL+"/maven2/", nil)
+
+	data, err := fetcher.Fetch(context.Background(), repo, "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, "/maven2/com/x/lib/1.0/lib-1.0.jar", string(data))
+
+	status.Store(http.StatusNotFound)
+	_, err = fetcher.Fetch(context.Background(), repo, "missing")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	exists, err := fetcher.Exists(context.Background(), repo, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	status.Store(http.StatusBadGateway)
+	for i := 0; i < defaultBlockThreshold; i++ {
+		_, err = fetcher.Fetch(context.Background(), repo, "x")
+		require.Error(t, err)
+	}
+	_, err = fetcher.Fetch(context.Background(), repo, "x")
+	assert.ErrorIs(t, err, errs.ErrUnavailable)
+}
+
+// 探测请求被调用方取消后不应一直占用探测名额
+func TestRemoteFetcher_CancelledProbe(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+	defer close(release)
+
+	monitor, now := newTestMonitor()
+	fetcher := newRemoteFetcher(monitor)
+	repo := proxyRepo(server.URL, nil)
+	blockAndExpire(t, monitor, now, repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetcher.Fetch(ctx, repo, "slow")
+		done <- err
+	}()
+	require.Eventually(t, func() bool {
+		monitor.mutex.Lock()
+		defer monitor.mutex.Unlock()
+		return monitor.states[repo.ID].probing
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// 取消不计入失败，下一次请求重新作为探测放行
+	status := monitor.Status(repo)
+	assert.Equal(t, defaultBlockThreshold, status.Failures)
+	assert.True(t, monitor.Allow(repo))
+}
+
+func TestRemoteFetcher_InvalidRequestReleasesProbe(t *testing.T) {
+	monitor, now := newTestMonitor()
+	fetcher := newRemoteFetcher(monitor)
+	repo := proxyRepo("http://remote\x7f", nil)
+	blockAndExpire(t, monitor, now, repo)
+
+	_, err := fetcher.Fetch(context.Background(), repo, "x")
+	require.Error(t, err)
+	assert.True(t, monitor.Allow(repo))
+}
+
+func TestRemoteTimeout(t *testing.T) {
+	assert.Equal(t, defaultRemoteTimeout, remoteTimeout(proxyRepo("", nil)))
+	assert.Equal(t, 5*time.Second, remoteTimeout(proxyRepo("", map[string]string{"remote_timeout": "5s"})))
+	assert.Equal(t, defaultRemoteTimeout, remoteTimeout(proxyRepo("", map[string]string{"remote_timeout": "-1s"})))
+}
@@ -1,21 +1,403 @@
 package impl
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"net/url"
 
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/fulltext"
+	"github.com/laolishu/go-nexus/internal/plugin"
 	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
 )
 
 // RepositoryServiceImpl 仓库服务实现
 type RepositoryServiceImpl struct {
 	logger     *slog.Logger
 	repository repository.RepositoryRepository
+	plugins    *plugin.Manager
+	monitor    *RemoteMonitor
+	index      *fulltext.Index
+	policies   repository.CleanupPolicyRepository
+	authorizer *AuthorizerImpl
+	keys       repository.TrustedKeyRepository
+	artifacts  *ArtifactServiceImpl
 }
 
 // NewRepositoryService 创建新的仓库服务实现
-func NewRepositoryService(logger *slog.Logger, repo repository.RepositoryRepository) *RepositoryServiceImpl {
+func NewRepositoryService(logger *slog.Logger, repo repository.RepositoryRepository, plugins *plugin.Manager, monitor *RemoteMonitor, index *fulltext.Index, policies repository.CleanupPolicyRepository, authorizer *AuthorizerImpl, keys repository.TrustedKeyRepository, artifacts *ArtifactServiceImpl) *RepositoryServiceImpl {
 	return &RepositoryServiceImpl{
 		logger:     logger,
 		repository: repo,
+		plugins:    plugins,
+		monitor:    monitor,
+		index:      index,
+		policies:   policies,
+		authorizer: authorizer,
+		keys:       keys,
+		artifacts:  artifacts,
+	}
+}
+
+// Create 创建仓库
+func (s *RepositoryServiceImpl) Create(ctx context.Context, req *dto.CreateRepositoryRequest) (*model.Repository, error) {
+	existing, err := s.repository.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repository: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("repository %q already exists", req.Name)
+	}
+	if _, err := s.plugins.GetFormatPlugin(req.Format); err != nil {
+		return nil, errs.InvalidArgument("unsupported repository format: %s", req.Format)
+	}
+
+	repo := &model.Repository{
+		ID:               uuid.New().String(),
+		Name:             req.Name,
+		Type:             req.Type,
+		Format:           req.Format,
+		Description:      req.Description,
+		URL:              req.URL,
+		Config:           req.Config,
+		Members:          req.Members,
+		Routing:          req.Routing,
+		Blocked:          req.Blocked,
+		Status:           "active",
+		DeploymentPolicy: req.DeploymentPolicy,
+		CleanupPolicies:  req.CleanupPolicies,
+		SignaturePolicy:  req.SignaturePolicy,
+		SignMetadata:     req.SignMetadata,
+	}
+	if repo.DeploymentPolicy == "" {
+		repo.DeploymentPolicy = model.DeploymentPolicyAllowRedeploy
+	}
+	if err := s.validate(ctx, repo, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(ctx, repo); err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	s.logger.Info("Repository created", "id", repo.ID, "name", repo.Name, "type", repo.Type, "format", repo.Format)
+	repo.RemoteStatus = s.monitor.Status(repo)
+	return repo, nil
+}
+
+// Get 获取仓库
+func (s *RepositoryServiceImpl) Get(ctx context.Context, idOrName string) (*model.Repository, error) {
+	repo, err := lookupRepository(ctx, s.repository, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	repo.RemoteStatus = s.monitor.Status(repo)
+	return repo, nil
+}
+
+// List 列出调用者可读的仓库
+func (s *RepositoryServiceImpl) List(ctx context.Context, query *dto.ListRepositoriesQuery) ([]*model.Repository, error) {
+	all, err := s.repository.List(ctx, query.Type, query.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	readable, err := s.authorizer.RepositoryFilter(ctx, auth.ActionRead)
+	if err != nil {
+		return nil, err
+	}
+	repos := make([]*model.Repository, 0, len(all))
+	for _, repo := range all {
+		if !readable(repo) {
+			continue
+		}
+		repo.RemoteStatus = s.monitor.Status(repo)
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// Update 更新仓库
+// 仓库 admin 权限即可更新，修改代理仓库的远程地址会让服务端访问任意地址，额外要求仓库管理的系统权限
+func (s *RepositoryServiceImpl) Update(ctx context.Context, idOrName string, req *dto.UpdateRepositoryRequest) (*model.Repository, error) {
+	repo, err := lookupRepository(ctx, s.repository, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Type == model.RepositoryTypeProxy && req.URL != repo.URL {
+		if err := s.authorizer.Authorize(ctx, auth.PermissionRepositoriesManage); err != nil {
+			return nil, err
+		}
+	}
+
+	previousMembers := repo.Members
+	repo.Description = req.Description
+	repo.URL = req.URL
+	repo.Config = req.Config
+	repo.Members = req.Members
+	repo.Routing = req.Routing
+	repo.Blocked = req.Blocked
+	repo.CleanupPolicies = req.CleanupPolicies
+	repo.SignaturePolicy = req.SignaturePolicy
+	repo.SignMetadata = req.SignMetadata
+	if req.Status != "" {
+		repo.Status = req.Status
+	}
+	if req.DeploymentPolicy != "" {
+		repo.DeploymentPolicy = req.DeploymentPolicy
+	}
+	if err := s.validate(ctx, repo, previousMembers); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Update(ctx, repo); err != nil {
+		return nil, fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	// 远程地址或熔断配置可能已变化，重新开始统计
+	s.monitor.Reset(repo.ID)
+
+	s.logger.Info("Repository updated", "id", repo.ID, "name", repo.Name)
+	repo.RemoteStatus = s.monitor.Status(repo)
+	return repo, nil
+}
+
+// Delete 删除仓库及其中的制品记录与存储内容，名称可以重新使用
+func (s *RepositoryServiceImpl) Delete(ctx context.Context, idOrName string) error {
+	repo, err := lookupRepository(ctx, s.repository, idOrName)
+	if err != nil {
+		return err
+	}
+
+	groups, err := s.repository.List(ctx, model.RepositoryTypeGroup, repo.Format)
+	if err != nil {
+		return fmt.Errorf("failed to list group repositories: %w", err)
+	}
+	for _, group := range groups {
+		for _, member := range group.Members {
+			if member == repo.Name {
+				return errs.Conflict("repository %q is a member of group %q", repo.Name, group.Name)
+			}
+		}
+	}
+
+	// 先清理内容再删除记录，清理失败时仓库保留，可以重新删除
+	if err := s.artifacts.purge(ctx, repo); err != nil {
+		return err
+	}
+	if err := s.repository.Delete(ctx, repo.ID); err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+	s.monitor.Reset(repo.ID)
+
+	s.logger.Info("Repository deleted", "id", repo.ID, "name", repo.Name)
+	return nil
+}
+
+// validate 按仓库类型校验配置，previousMembers 为更新前的组成员
+func (s *RepositoryServiceImpl) validate(ctx context.Context, repo *model.Repository, previousMembers []string) error {
+	if err := validateRoutingRules(repo.Routing); err != nil {
+		return errs.InvalidArgument("%s", err.Error())
+	}
+	if err := s.validateCleanupPolicies(ctx, repo); err != nil {
+		return err
+	}
+	if err := s.validateSignaturePolicy(ctx, repo); err != nil {
+		return err
+	}
+	if repo.SignMetadata && repo.Type != model.RepositoryTypeHosted {
+		return errs.InvalidArgument("only hosted repositories generate metadata that can be signed")
+	}
+
+	switch repo.Type {
+	case model.RepositoryTypeProxy:
+		u, err := url.Parse(repo.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errs.InvalidArgument("proxy repository requires a valid http(s) url")
+		}
+	case model.RepositoryTypeHosted:
+		if repo.Blocked {
+			return errs.InvalidArgument("only proxy repositories can be blocked")
+		}
+		if repo.URL != "" {
+			return errs.InvalidArgument("hosted repository does not accept a url")
+		}
+	case model.RepositoryTypeGroup:
+		if repo.Blocked {
+			return errs.InvalidArgument("only proxy repositories can be blocked")
+		}
+		return s.validateMembers(ctx, repo, previousMembers)
+	default:
+		return errs.InvalidArgument("unsupported repository type: %s", repo.Type)
+	}
+
+	if len(repo.Members) > 0 {
+		return errs.InvalidArgument("only group repositories can have members")
+	}
+	return nil
+}
+
+// validateCleanupPolicies 校验仓库附加的清理策略：必须存在、不重复，且适用于仓库格式
+func (s *RepositoryServiceImpl) validateCleanupPolicies(ctx context.Context, repo *model.Repository) error {
+	if len(repo.CleanupPolicies) == 0 {
+		return nil
+	}
+	if repo.Type == model.RepositoryTypeGroup {
+		return errs.InvalidArgument("group repositories cannot have cleanup policies, attach them to the members")
+	}
+	policies, err := s.policies.FindByNames(ctx, repo.CleanupPolicies)
+	if err != nil {
+		return fmt.Errorf("failed to find cleanup policies: %w", err)
+	}
+	byName := make(map[string]*model.CleanupPolicy, len(policies))
+	for _, policy := range policies {
+		byName[policy.Name] = policy
+	}
+
+	seen := make(map[string]bool, len(repo.CleanupPolicies))
+	for _, name := range repo.CleanupPolicies {
+		if seen[name] {
+			return errs.InvalidArgument("duplicate cleanup policy: %s", name)
+		}
+		seen[name] = true
+		policy, ok := byName[name]
+		if !ok {
+			return errs.InvalidArgument("cleanup policy not found: %s", name)
+		}
+		if policy.Format != "" && policy.Format != repo.Format {
+			return errs.InvalidArgument("cleanup policy %q applies to %s repositories", name, policy.Format)
+		}
+	}
+	return nil
+}
+
+// validateSignaturePolicy 校验签名策略：只适用于宿主仓库，公钥必须存在且不重复，要求签名时至少有一个受信任公钥
+func (s *RepositoryServiceImpl) validateSignaturePolicy(ctx context.Context, repo *model.Repository) error {
+	policy := repo.SignaturePolicy
+	if !policy.Required && len(policy.Keys) == 0 {
+		return nil
+	}
+	if repo.Type != model.RepositoryTypeHosted {
+		return errs.InvalidArgument("only hosted repositories can have a signature policy")
+	}
+
+	var (
+		keys []*model.TrustedKey
+		err  error
+	)
+	if len(policy.Keys) > 0 {
+		keys, err = s.keys.FindByNames(ctx, policy.Keys)
+	} else {
+		keys, err = s.keys.List(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find trusted keys: %w", err)
+	}
+	found := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		found[key.Name] = true
+	}
+	seen := make(map[string]bool, len(policy.Keys))
+	for _, name := range policy.Keys {
+		if seen[name] {
+			return errs.InvalidArgument("duplicate trusted key: %s", name)
+		}
+		seen[name] = true
+		if !found[name] {
+			return errs.InvalidArgument("trusted key not found: %s", name)
+		}
+	}
+	if policy.Required && len(keys) == 0 {
+		return errs.InvalidArgument("signature policy requires at least one trusted key")
+	}
+	return nil
+}
+
+// validateMembers 校验组仓库成员：必须存在、格式一致、不重复且不能形成循环引用
+// 组仓库的读取者可以读取全部成员，新加入的成员要求调用者对其有读取权限，避免借助组仓库读取无权访问的仓库
+func (s *RepositoryServiceImpl) validateMembers(ctx context.Context, group *model.Repository, previousMembers []string) error {
+	if len(group.Members) == 0 {
+		return errs.InvalidArgument("group repository requires at least one member")
+	}
+
+	seen := make(map[string]bool, len(group.Members))
+	for _, name := range group.Members {
+		if name == group.Name {
+			return errs.InvalidArgument("group repository cannot contain itself")
+		}
+		if seen[name] {
+			return errs.InvalidArgument("duplicate group member: %s", name)
+		}
+		seen[name] = true
+	}
+
+	members, err := s.repository.FindByNames(ctx, group.Members)
+	if err != nil {
+		return fmt.Errorf("failed to find group members: %w", err)
+	}
+	byName := make(map[string]*model.Repository, len(members))
+	for _, member := range members {
+		byName[member.Name] = member
+	}
+	previous := make(map[string]bool, len(previousMembers))
+	for _, name := range previousMembers {
+		previous[name] = true
+	}
+	readable, err := s.authorizer.RepositoryFilter(ctx, auth.ActionRead)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range group.Members {
+		member, ok := byName[name]
+		if !ok {
+			return errs.InvalidArgument("group member not found: %s", name)
+		}
+		if member.Format != group.Format {
+			return errs.InvalidArgument("group member %q has format %s, expected %s", name, member.Format, group.Format)
+		}
+		if !previous[name] && !readable(member) {
+			return errs.Forbidden("read permission on group member %q is required", name)
+		}
+		if member.Type == model.RepositoryTypeGroup {
+			contains, err := s.groupContains(ctx, member, group.Name, map[string]bool{})
+			if err != nil {
+				return err
+			}
+			if contains {
+				return errs.InvalidArgument("group member %q would create a cycle", name)
+			}
+		}
+	}
+	return nil
+}
+
+// groupContains 判断组仓库是否直接或间接包含指定仓库
+func (s *RepositoryServiceImpl) groupContains(ctx context.Context, group *model.Repository, target string, visited map[string]bool) (bool, error) {
+	if visited[group.Name] {
+		return false, nil
+	}
+	visited[group.Name] = true
+
+	members, err := s.repository.FindByNames(ctx, group.Members)
+	if err != nil {
+		return false, fmt.Errorf("failed to find group members: %w", err)
+	}
+	for _, member := range members {
+		if member.Name == target {
+			return true, nil
+		}
+		if member.Type == model.RepositoryTypeGroup {
+			contains, err := s.groupContains(ctx, member, target, visited)
+			if err != nil || contains {
+				return contains, err
+			}
+		}
 	}
+	return false, nil
 }
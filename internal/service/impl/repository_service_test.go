@@ -0,0 +1,295 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestRepositoryService_DeploymentPolicy(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	repo := env.hosted(t, "releases", "maven")
+	assert.Equal(t, model.DeploymentPolicyAllowRedeploy, repo.DeploymentPolicy, "default policy")
+
+	immutable := env.createRepo(t, &dto.CreateRepositoryRequest{Name: "immutable", Type: model.RepositoryTypeHosted, Format: "maven",
+		DeploymentPolicy: model.DeploymentPolicyDisableRedeploy})
+	assert.Equal(t, model.DeploymentPolicyDisableRedeploy, immutable.DeploymentPolicy)
+
+	updated, err := env.repositories.Update(ctx, "releases", &dto.UpdateRepositoryRequest{DeploymentPolicy: model.DeploymentPolicyReadOnly})
+	require.NoError(t, err)
+	assert.Equal(t, model.DeploymentPolicyReadOnly, updated.DeploymentPolicy)
+
+	// 未给出策略的更新保持原值
+	updated, err = env.repositories.Update(ctx, "releases", &dto.UpdateRepositoryRequest{Description: "frozen"})
+	require.NoError(t, err)
+	assert.Equal(t, model.DeploymentPolicyReadOnly, updated.DeploymentPolicy)
+}
+
+func TestRepositoryService_SignaturePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    bool // 登记 release 公钥
+		typ     string
+		policy  model.SignaturePolicy
+		wantErr string
+	}{
+		{name: "no_policy", typ: model.RepositoryTypeHosted},
+		{name: "required_trusts_all_keys", keys: true, typ: model.RepositoryTypeHosted, policy: model.SignaturePolicy{Required: true}},
+		{name: "required_with_key", keys: true, typ: model.RepositoryTypeHosted, policy: model.SignaturePolicy{Required: true, Keys: []string{"release"}}},
+		{name: "keys_without_requirement", keys: true, typ: model.RepositoryTypeHosted, policy: model.SignaturePolicy{Keys: []string{"release"}}},
+		{name: "required_without_keys", typ: model.RepositoryTypeHosted, policy: model.SignaturePolicy{Required: true},
+			wantErr: "requires at least one trusted key"},
+		{name: "missing_key", keys: true, typ: model.RepositoryTypeHosted, policy: model.SignaturePolicy{Required: true, Keys: []string{"missing"}},
+			wantErr: "trusted key not found: missing"},
+		{name: "duplicate_key", keys: true, typ: model.RepositoryTypeHosted, policy: model.SignaturePolicy{Keys: []string{"release", "release"}},
+			wantErr: "duplicate trusted key: release"},
+		{name: "group_repository", keys: true, typ: model.RepositoryTypeGroup, policy: model.SignaturePolicy{Required: true},
+			wantErr: "only hosted repositories"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			if tt.keys {
+				require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "release", testPGPEntity(t, "release"))))
+			}
+
+			req := &dto.CreateRepositoryRequest{Name: "signed", Type: tt.typ, Format: "maven", SignaturePolicy: tt.policy}
+			repo, err := env.repositories.Create(ctx, req)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+				assert.ErrorContains(t, err, tt.wantErr)
+
+				// 更新时同样校验
+				if tt.typ == model.RepositoryTypeHosted {
+					env.hosted(t, "plain", "maven")
+					_, err = env.repositories.Update(ctx, "plain", &dto.UpdateRepositoryRequest{SignaturePolicy: tt.policy})
+					assert.ErrorContains(t, err, tt.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.policy, repo.SignaturePolicy)
+		})
+	}
+}
+
+func TestRepositoryService_CreateValidation(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "libs-release", "maven")
+	env.hosted(t, "npm-hosted", "npm")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "inner", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-release"}})
+
+	tests := []struct {
+		name    string
+		req     *dto.CreateRepositoryRequest
+		wantErr error
+	}{
+		{name: "proxy", req: &dto.CreateRepositoryRequest{Name: "central", Type: model.RepositoryTypeProxy, Format: "maven", URL: "https://repo1.example.com/maven2/"}},
+		{name: "group", req: &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-release", "inner"}}},
+		{name: "duplicate_name", req: &dto.CreateRepositoryRequest{Name: "libs-release", Type: model.RepositoryTypeHosted, Format: "maven"}, wantErr: errs.ErrConflict},
+		{name: "unsupported_format", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeHosted, Format: "cobol"}, wantErr: errs.ErrInvalidArgument},
+		{name: "unsupported_type", req: &dto.CreateRepositoryRequest{Name: "x", Type: "virtual", Format: "maven"}, wantErr: errs.ErrInvalidArgument},
+		{name: "proxy_without_url", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeProxy, Format: "maven"}, wantErr: errs.ErrInvalidArgument},
+		{name: "proxy_file_url", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeProxy, Format: "maven", URL: "file:///etc/passwd"}, wantErr: errs.ErrInvalidArgument},
+		{name: "proxy_with_members", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeProxy, Format: "maven", URL: "https://repo1.example.com/", Members: []string{"libs-release"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "proxy_sign_metadata", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeProxy, Format: "maven", URL: "https://repo1.example.com/", SignMetadata: true}, wantErr: errs.ErrInvalidArgument},
+		{name: "hosted_with_url", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeHosted, Format: "maven", URL: "https://repo1.example.com/"}, wantErr: errs.ErrInvalidArgument},
+		{name: "hosted_blocked", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeHosted, Format: "maven", Blocked: true}, wantErr: errs.ErrInvalidArgument},
+		{name: "group_blocked", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-release"}, Blocked: true}, wantErr: errs.ErrInvalidArgument},
+		{name: "group_without_members", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeGroup, Format: "maven"}, wantErr: errs.ErrInvalidArgument},
+		{name: "group_contains_itself", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"x"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "group_duplicate_member", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-release", "libs-release"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "group_missing_member", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"missing"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "group_format_mismatch", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"npm-hosted"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "unknown_cleanup_policy", req: &dto.CreateRepositoryRequest{Name: "x", Type: model.RepositoryTypeHosted, Format: "maven", CleanupPolicies: []string{"missing"}}, wantErr: errs.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := env.repositories.Create(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "active", repo.Status)
+		})
+	}
+}
+
+func TestRepositoryService_GroupCycle(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "libs-release", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "inner", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-release"}})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "outer", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"inner"}})
+
+	_, err := env.repositories.Update(context.Background(), "inner", &dto.UpdateRepositoryRequest{Members: []string{"libs-release", "outer"}})
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	assert.ErrorContains(t, err, "cycle")
+}
+
+func TestRepositoryService_GroupMemberPermission(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "team-release", "maven")
+	env.hosted(t, "team-snapshot", "maven")
+	env.hosted(t, "secret-release", "maven")
+	// 管理员创建的组仓库已包含团队无权读取的成员
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "team-public", Type: model.RepositoryTypeGroup, Format: "maven",
+		Members: []string{"team-release", "secret-release"}})
+
+	team := asUser("team", auth.RepositoryPermission("maven", "team-*", auth.ActionAdmin))
+	tests := []struct {
+		name    string
+		members []string
+		wantErr error
+	}{
+		{name: "keep_existing_unreadable_member", members: []string{"team-release", "secret-release"}},
+		{name: "add_readable_member", members: []string{"team-release", "secret-release", "team-snapshot"}},
+		{name: "remove_member", members: []string{"team-release"}},
+		{name: "add_unreadable_member", members: []string{"team-release", "secret-release"}, wantErr: errs.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := env.repositories.Update(team, "team-public", &dto.UpdateRepositoryRequest{Members: tt.members})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, "secret-release")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.members, updated.Members)
+		})
+	}
+
+	t.Run("create_requires_read_on_members", func(t *testing.T) {
+		manager := asUser("manager", auth.PermissionRepositoriesManage)
+		_, err := env.repositories.Create(manager, &dto.CreateRepositoryRequest{Name: "mirror", Type: model.RepositoryTypeGroup, Format: "maven",
+			Members: []string{"secret-release"}})
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+
+		reader := asUser("reader", auth.PermissionRepositoriesManage, auth.RepositoryPermission("*", "*", auth.ActionRead))
+		_, err = env.repositories.Create(reader, &dto.CreateRepositoryRequest{Name: "mirror", Type: model.RepositoryTypeGroup, Format: "maven",
+			Members: []string{"secret-release"}})
+		assert.NoError(t, err)
+	})
+}
+
+func TestRepositoryService_ProxyURLPermission(t *testing.T) {
+	env := newTestEnv(t)
+	const upstream = "https://repo1.example.com/maven2/"
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "central", Type: model.RepositoryTypeProxy, Format: "maven", URL: upstream})
+	env.hosted(t, "libs-release", "maven")
+
+	repoAdmin := auth.RepositoryPermission("maven", "*", auth.ActionAdmin)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		repo    string
+		req     *dto.UpdateRepositoryRequest
+		wantErr error
+	}{
+		{name: "same_url", ctx: asUser("team", repoAdmin), repo: "central", req: &dto.UpdateRepositoryRequest{URL: upstream, Description: "Maven Central"}},
+		{name: "blocked_keeps_url", ctx: asUser("team", repoAdmin), repo: "central", req: &dto.UpdateRepositoryRequest{URL: upstream, Blocked: true}},
+		{name: "changed_url", ctx: asUser("team", repoAdmin), repo: "central", req: &dto.UpdateRepositoryRequest{URL: "http://169.254.169.254/latest/"}, wantErr: errs.ErrForbidden},
+		{name: "changed_url_by_token_without_admin_scope", ctx: auth.WithPrincipal(context.Background(), &auth.Principal{Username: "ci",
+			Permissions: []string{repoAdmin, auth.PermissionRepositoriesManage}, Scopes: []string{"repo:write"}}), repo: "central",
+			req: &dto.UpdateRepositoryRequest{URL: "https://mirror.example.com/"}, wantErr: errs.ErrForbidden},
+		{name: "changed_url_by_manager", ctx: asUser("manager", repoAdmin, auth.PermissionRepositoriesManage), repo: "central", req: &dto.UpdateRepositoryRequest{URL: "https://mirror.example.com/"}},
+		{name: "hosted_unaffected", ctx: asUser("team", repoAdmin), repo: "libs-release", req: &dto.UpdateRepositoryRequest{Description: "releases"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := env.repositories.Update(tt.ctx, tt.repo, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				stored := mustRepo(t, env, tt.repo)
+				assert.NotEqual(t, tt.req.URL, stored.URL, "rejected updates are not saved")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.req.URL, updated.URL)
+		})
+	}
+}
+
+func TestRepositoryService_ListFiltersUnreadable(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "team-release", "maven")
+	env.hosted(t, "secret-release", "maven")
+	env.hosted(t, "team-npm", "npm")
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		query *dto.ListRepositoriesQuery
+		want  []string
+	}{
+		{name: "internal", ctx: context.Background(), query: &dto.ListRepositoriesQuery{}, want: []string{"secret-release", "team-npm", "team-release"}},
+		{name: "team", ctx: asUser("team", auth.RepositoryPermission("*", "team-*", auth.ActionRead)), query: &dto.ListRepositoriesQuery{}, want: []string{"team-npm", "team-release"}},
+		{name: "team_maven", ctx: asUser("team", auth.RepositoryPermission("*", "team-*", auth.ActionRead)), query: &dto.ListRepositoriesQuery{Format: "maven"}, want: []string{"team-release"}},
+		{name: "no_roles", ctx: asUser("nobody"), query: &dto.ListRepositoriesQuery{}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, err := env.repositories.List(tt.ctx, tt.query)
+			require.NoError(t, err)
+			names := make([]string, 0, len(repos))
+			for _, repo := range repos {
+				names = append(names, repo.Name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestRepositoryService_DeleteGroupMember(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.hosted(t, "libs-release", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs-release"}})
+
+	assert.ErrorIs(t, env.repositories.Delete(ctx, "libs-release"), errs.ErrConflict)
+	require.NoError(t, env.repositories.Delete(ctx, "public"))
+	require.NoError(t, env.repositories.Delete(ctx, "libs-release"))
+	_, err := env.repositories.Get(ctx, "libs-release")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestRepositoryService_DeletePurgesContent(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	repo := env.hosted(t, "libs", "maven")
+	env.upload(t, "libs", "com/x/lib/1.0/lib-1.0.jar", []byte("jar"))
+	env.upload(t, "libs", "com/x/lib/1.0/lib-1.0.pom", pomOf("com.x", "lib", "1.0", "purged library"))
+	old, err := env.artifactRepo.FindByPath(ctx, repo.ID, "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	require.NoError(t, env.artifactRepo.Delete(ctx, old.ID), "soft-deleted rows are purged as well")
+	require.Equal(t, 1, env.index.Len())
+
+	require.NoError(t, env.repositories.Delete(ctx, "libs"))
+
+	var count int64
+	require.NoError(t, env.db.Unscoped().Model(&model.Artifact{}).Where("repository_id = ?", repo.ID).Count(&count).Error)
+	assert.Zero(t, count, "no artifact rows are left behind")
+	require.NoError(t, env.db.Unscoped().Model(&model.Repository{}).Where("name = ?", "libs").Count(&count).Error)
+	assert.Zero(t, count)
+	files, err := env.storage.List(ctx, storagePath(repo, ""))
+	require.NoError(t, err)
+	assert.Empty(t, files, "stored content and metadata are deleted")
+	assert.Zero(t, env.index.Len())
+
+	// 名称可以重新使用，新仓库不会看到旧内容
+	env.hosted(t, "libs", "maven")
+	_, err = env.download("libs", "com/x/lib/1.0/lib-1.0.pom")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	env.upload(t, "libs", "com/x/lib/1.0/lib-1.0.pom", pomOf("com.x", "lib", "1.0", "new library"))
+}
@@ -0,0 +1,208 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// routingCacheSize 路由规则缓存的最大条目数，超出时清空后重新缓存
+const routingCacheSize = 1024
+
+// routingPatterns 已编译的路由规则缓存，只缓存已保存到仓库配置中的规则
+var routingPatterns = struct {
+	sync.RWMutex
+	compiled map[string]*regexp.Regexp
+}{compiled: make(map[string]*regexp.Regexp)}
+
+// compileRoutingPattern 编译路由规则，规则需完整匹配路径
+func compileRoutingPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// cachedRoutingPattern 从缓存中取出已编译的路由规则
+func cachedRoutingPattern(pattern string) (*regexp.Regexp, error) {
+	routingPatterns.RLock()
+	re, ok := routingPatterns.compiled[pattern]
+	routingPatterns.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := compileRoutingPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	routingPatterns.Lock()
+	if len(routingPatterns.compiled) >= routingCacheSize {
+		routingPatterns.compiled = make(map[string]*regexp.Regexp)
+	}
+	routingPatterns.compiled[pattern] = re
+	routingPatterns.Unlock()
+	return re, nil
+}
+
+// validateRoutingRules 校验路由规则是否都是合法的正则表达式
+func validateRoutingRules(rules model.RoutingRules) error {
+	for _, list := range [][]string{rules.Allow, rules.Deny} {
+		for _, pattern := range list {
+			if _, err := compileRoutingPattern(pattern); err != nil {
+				return fmt.Errorf("invalid routing pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// routingDecision 判断仓库是否可以提供该路径，返回是否允许以及起决定作用的规则
+func routingDecision(repo *model.Repository, p string) (bool, string) {
+	for _, pattern := range repo.Routing.Deny {
+		if re, err := cachedRoutingPattern(pattern); err == nil && re.MatchString(p) {
+			return false, "deny: " + pattern
+		}
+	}
+	if len(repo.Routing.Allow) == 0 {
+		return true, ""
+	}
+	for _, pattern := range repo.Routing.Allow {
+		if re, err := cachedRoutingPattern(pattern); err == nil && re.MatchString(p) {
+			return true, "allow: " + pattern
+		}
+	}
+	return false, "no allow rule matched"
+}
+
+// ExplainRouting 说明路径在仓库（及其组成员）中的路由结果，不读取制品内容
+func (s *ArtifactServiceImpl) ExplainRouting(ctx context.Context, repoIDOrName, rawPath string) (*dto.RoutingExplanation, error) {
+	repo, err := lookupRepository(ctx, s.repositories, repoIDOrName)
+	if err != nil {
+		return nil, err
+	}
+	p, err := normalizePath(rawPath)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &dto.RoutingExplanation{Repository: repo.Name, Path: p}
+	if repo.Type == model.RepositoryTypeGroup {
+		_, explanation.Merged = s.metadataMerger(repo.Format, p)
+	}
+	s.explain(ctx, repo, p, 0, make(map[string]bool), explanation)
+	if explanation.Merged && len(explanation.MergedFrom) > 0 {
+		explanation.ServedBy = repo.Name
+	}
+	return explanation, nil
+}
+
+// explain 记录单个仓库的判定结果，返回该仓库能否提供内容
+func (s *ArtifactServiceImpl) explain(ctx context.Context, repo *model.Repository, p string, depth int, visited map[string]bool, out *dto.RoutingExplanation) bool {
+	step := dto.RoutingStep{Repository: repo.Name, Type: repo.Type, Depth: depth}
+	record := func() {
+		out.Steps = append(out.Steps, step)
+	}
+
+	if repo.Status == "inactive" {
+		step.Decision, step.Reason = "skipped", "repository is inactive"
+		record()
+		return false
+	}
+
+	allowed, rule := routingDecision(repo, p)
+	step.Rule = rule
+	if !allowed {
+		step.Decision = "denied"
+		record()
+		return false
+	}
+	step.Decision = "allowed"
+
+	switch repo.Type {
+	case model.RepositoryTypeHosted:
+		step.Available, step.Reason = s.explainLocal(ctx, repo, p)
+	case model.RepositoryTypeProxy:
+		step.Available, step.Reason = s.explainLocal(ctx, repo, p)
+		if !step.Available {
+			exists, err := s.remote.Exists(ctx, repo, p)
+			switch {
+			case errors.Is(err, errs.ErrUnavailable):
+				step.Reason = err.Error()
+			case err != nil:
+				step.Reason = "remote unavailable: " + err.Error()
+			case exists:
+				step.Available, step.Reason = true, "available from remote"
+			default:
+				step.Reason = "not found in remote"
+			}
+		}
+	case model.RepositoryTypeGroup:
+		// 与 readGroup 一致，只有组仓库会因重复访问而跳过，同一成员在不同组中会被再次查询
+		if visited[repo.ID] {
+			step.Decision, step.Reason = "skipped", "already visited"
+			record()
+			return false
+		}
+		visited[repo.ID] = true
+		record()
+		index := len(out.Steps) - 1
+		served := s.explainGroup(ctx, repo, p, depth, visited, out)
+		out.Steps[index].Available = served
+		return served
+	}
+
+	record()
+	if step.Available && out.ServedBy == "" && !out.Merged {
+		out.ServedBy = repo.Name
+	}
+	if step.Available && out.Merged {
+		out.MergedFrom = append(out.MergedFrom, repo.Name)
+	}
+	return step.Available
+}
+
+// explainGroup 依次判定组仓库成员，非元数据路径在第一个命中的成员处停止
+func (s *ArtifactServiceImpl) explainGroup(ctx context.Context, group *model.Repository, p string, depth int, visited map[string]bool, out *dto.RoutingExplanation) bool {
+	members, err := s.groupMembers(ctx, group)
+	if err != nil {
+		out.Steps[len(out.Steps)-1].Reason = "failed to load members: " + err.Error()
+		return false
+	}
+
+	served := false
+	for _, member := range members {
+		if served && !out.Merged {
+			out.Steps = append(out.Steps, dto.RoutingStep{
+				Repository: member.Name,
+				Type:       member.Type,
+				Depth:      depth + 1,
+				Decision:   "skipped",
+				Reason:     "served by an earlier member",
+			})
+			continue
+		}
+		if s.explain(ctx, member, p, depth+1, visited, out) {
+			served = true
+		}
+	}
+	return served
+}
+
+// explainLocal 检查仓库本地存储中是否存在该路径
+func (s *ArtifactServiceImpl) explainLocal(ctx context.Context, repo *model.Repository, p string) (bool, string) {
+	exists, err := s.storage.Exists(ctx, storagePath(repo, p))
+	switch {
+	case err != nil:
+		return false, "storage error: " + err.Error()
+	case exists && repo.Type == model.RepositoryTypeProxy:
+		return true, "cached"
+	case exists:
+		return true, "stored"
+	default:
+		return false, "not stored"
+	}
+}
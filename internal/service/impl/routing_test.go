@@ -0,0 +1,116 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestRoutingDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    model.RoutingRules
+		path     string
+		want     bool
+		wantRule string
+	}{
+		{name: "no_rules", path: "com/x/lib/1.0/lib-1.0.jar", want: true},
+		{name: "allow_match", rules: model.RoutingRules{Allow: []string{`com/x/.*`}}, path: "com/x/lib/1.0/lib-1.0.jar", want: true, wantRule: "allow: com/x/.*"},
+		{name: "allow_miss", rules: model.RoutingRules{Allow: []string{`com/x/.*`}}, path: "org/y/lib/1.0/lib-1.0.jar", want: false, wantRule: "no allow rule matched"},
+		{name: "allow_is_anchored", rules: model.RoutingRules{Allow: []string{`com/x`}}, path: "com/x/lib/1.0/lib-1.0.jar", want: false, wantRule: "no allow rule matched"},
+		{name: "alternation_is_anchored", rules: model.RoutingRules{Allow: []string{`a|com/x/.*`}}, path: "com/x/lib", want: true, wantRule: "allow: a|com/x/.*"},
+		{name: "deny_wins_over_allow", rules: model.RoutingRules{Allow: []string{`.*`}, Deny: []string{`.*-SNAPSHOT/.*`}}, path: "com/x/lib/1.0-SNAPSHOT/lib.jar", want: false, wantRule: "deny: .*-SNAPSHOT/.*"},
+		{name: "deny_miss", rules: model.RoutingRules{Deny: []string{`internal/.*`}}, path: "com/x/lib", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rule := routingDecision(&model.Repository{Routing: tt.rules}, tt.path)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantRule, rule)
+		})
+	}
+}
+
+func TestValidateRoutingRules(t *testing.T) {
+	assert.NoError(t, validateRoutingRules(model.RoutingRules{Allow: []string{`com/.*`}, Deny: []string{`.*\.sha1`}}))
+	assert.Error(t, validateRoutingRules(model.RoutingRules{Allow: []string{`com/(`}}))
+	assert.Error(t, validateRoutingRules(model.RoutingRules{Deny: []string{`[`}}))
+}
+
+func TestCachedRoutingPattern_Bounded(t *testing.T) {
+	for i := 0; i < routingCacheSize*2; i++ {
+		_, err := cachedRoutingPattern(fmt.Sprintf("bounded-%d/.*", i))
+		require.NoError(t, err)
+	}
+	routingPatterns.RLock()
+	size := len(routingPatterns.compiled)
+	routingPatterns.RUnlock()
+	assert.LessOrEqual(t, size, routingCacheSize)
+
+	// 校验不会写入缓存
+	require.NoError(t, validateRoutingRules(model.RoutingRules{Allow: []string{"validate-only/.*"}}))
+	routingPatterns.RLock()
+	_, cached := routingPatterns.compiled["validate-only/.*"]
+	routingPatterns.RUnlock()
+	assert.False(t, cached)
+}
+
+func TestArtifactService_ExplainRouting(t *testing.T) {
+	env := newTestEnv(t)
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "snapshots", Type: model.RepositoryTypeHosted, Format: "maven",
+		Routing: model.RoutingRules{Allow: []string{`.*-SNAPSHOT/.*`}}})
+	env.hosted(t, "releases", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"snapshots", "releases"}})
+	env.upload(t, "releases", "com/x/lib/1.0/lib-1.0.jar", []byte("jar"))
+
+	explanation, err := env.artifacts.ExplainRouting(context.Background(), "public", "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, "releases", explanation.ServedBy)
+	require.Len(t, explanation.Steps, 3)
+	assert.Equal(t, "denied", explanation.Steps[1].Decision)
+	assert.Equal(t, "no allow rule matched", explanation.Steps[1].Rule)
+	assert.True(t, explanation.Steps[2].Available)
+
+	_, err = env.artifacts.ExplainRouting(context.Background(), "public", "../x")
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+}
+
+// 同一宿主仓库位于两个嵌套组中时，解析会再次查询它，说明结果也应如此；只有重复访问的组仓库被跳过
+func TestArtifactService_ExplainRoutingDiamond(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "shared", "maven")
+	env.hosted(t, "extra", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "left", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"shared"}})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "right", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"left", "shared", "extra"}})
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "top", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"left", "right"}})
+	env.upload(t, "extra", "com/x/lib/1.0/lib-1.0.jar", []byte("jar"))
+
+	data, err := env.download("top", "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, "jar", string(data))
+
+	explanation, err := env.artifacts.ExplainRouting(context.Background(), "top", "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, "extra", explanation.ServedBy)
+
+	var steps []string
+	for _, step := range explanation.Steps {
+		steps = append(steps, fmt.Sprintf("%d %s:%s %s", step.Depth, step.Repository, step.Decision, step.Reason))
+	}
+	assert.Equal(t, []string{
+		"0 top:allowed ",
+		"1 left:allowed ",
+		"2 shared:allowed not stored",
+		"1 right:allowed ",
+		"2 left:skipped already visited",
+		"2 shared:allowed not stored",
+		"2 extra:allowed stored",
+	}, steps)
+}
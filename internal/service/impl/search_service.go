@@ -0,0 +1,253 @@
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/fulltext"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/internal/version"
+)
+
+// sortRelevance 按全文检索相关度排序
+const sortRelevance = "relevance"
+
+// maxFullTextHits 全文检索最多返回的命中数量
+const maxFullTextHits = 1000
+
+// SearchServiceImpl 跨仓库搜索服务实现
+type SearchServiceImpl struct {
+	logger     *slog.Logger
+	artifacts  *ArtifactServiceImpl
+	authorizer *AuthorizerImpl
+}
+
+// NewSearchService 创建新的搜索服务实现，全文索引文件不存在时在后台根据数据库重建
+func NewSearchService(logger *slog.Logger, artifacts *ArtifactServiceImpl, authorizer *AuthorizerImpl) *SearchServiceImpl {
+	if !artifacts.index.Loaded() {
+		go func() {
+			if err := artifacts.reindex(context.Background()); err != nil {
+				logger.Error("Failed to rebuild full-text index", "error", err)
+			}
+		}()
+	}
+	return &SearchServiceImpl{
+		logger:     logger,
+		artifacts:  artifacts,
+		authorizer: authorizer,
+	}
+}
+
+// Search 搜索制品，全文检索与版本范围在数据库查询之外处理
+// 全文检索只返回得分最高的 maxFullTextHits 条命中，只能按相关度排序，按其他字段排序会截断结果，直接拒绝
+func (s *SearchServiceImpl) Search(ctx context.Context, query *dto.SearchQuery) (*dto.SearchPage, error) {
+	if query.Sort == sortRelevance && query.Q == "" {
+		return nil, errs.InvalidArgument("sort by relevance requires q")
+	}
+	if query.Q != "" && query.Sort != "" && query.Sort != sortRelevance {
+		return nil, errs.InvalidArgument("q results are ordered by relevance, sort %q cannot be combined with q", query.Sort)
+	}
+	relevance := query.Q != ""
+
+	repos, err := s.readableRepositories(ctx, query.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	listQuery := &dto.ListArtifactsQuery{
+		Name:       query.Name,
+		Version:    query.Version,
+		Format:     query.Format,
+		Properties: query.Properties,
+		Sort:       query.Sort,
+		Limit:      query.Limit,
+		Cursor:     query.Cursor,
+	}
+	if relevance {
+		// 相关度排序使用偏移量游标，单独解析
+		listQuery.Sort, listQuery.Cursor = "", ""
+	}
+	filter, err := buildArtifactFilter(listQuery)
+	if err != nil {
+		return nil, err
+	}
+	filter.Checksum = strings.ToLower(query.SHA256)
+	filter.Keyword = query.Keyword
+	filter.Metadata = make(map[string]string)
+	if query.GroupID != "" {
+		filter.Metadata[metadataGroupID] = query.GroupID
+	}
+	if query.ArtifactID != "" {
+		filter.Metadata[metadataArtifactID] = query.ArtifactID
+	}
+	for id := range repos {
+		filter.RepositoryIDs = append(filter.RepositoryIDs, id)
+	}
+	if len(filter.RepositoryIDs) == 0 {
+		return emptySearchPage(filter.Limit), nil
+	}
+
+	var keep func(*model.Artifact) bool
+	if query.VersionRange != "" {
+		versionRange, err := version.ParseRange(query.VersionRange)
+		if err != nil {
+			return nil, errs.InvalidArgument("%s", err.Error())
+		}
+		keep = func(a *model.Artifact) bool {
+			return a.Version != "" && versionRange.Contains(a.Version, version.ForFormat(a.Format).Compare)
+		}
+	}
+
+	var page *dto.ArtifactPage
+	if relevance {
+		hits := s.artifacts.index.Search(query.Q, filter.RepositoryIDs, maxFullTextHits)
+		if len(hits) == 0 {
+			return emptySearchPage(filter.Limit), nil
+		}
+		for _, hit := range hits {
+			filter.IDs = append(filter.IDs, hit.ID)
+		}
+		page, err = s.rankedPage(ctx, filter, hits, keep, query.Cursor)
+	} else {
+		page, err = listArtifactPage(ctx, s.artifacts.repository, filter, keep)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.SearchPage{
+		Items:      make([]*dto.SearchHit, 0, len(page.Items)),
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	for _, artifact := range page.Items {
+		result.Items = append(result.Items, &dto.SearchHit{
+			Artifact:   artifact,
+			Repository: repos[artifact.RepositoryID].Name,
+		})
+	}
+	return result, nil
+}
+
+// rankedPage 按全文检索得分排序并分页，游标为结果中的偏移量
+func (s *SearchServiceImpl) rankedPage(ctx context.Context, filter *model.ArtifactFilter, hits []fulltext.Hit, keep func(*model.Artifact) bool, cursor string) (*dto.ArtifactPage, error) {
+	offset, err := decodeOffsetCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	all := *filter
+	all.After, all.Limit = nil, 0
+	records, err := s.artifacts.repository.List(ctx, &all)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	byID := make(map[string]*model.Artifact, len(records))
+	for _, record := range records {
+		byID[record.ID] = record
+	}
+
+	var ranked []*model.Artifact
+	for _, hit := range hits {
+		if artifact, ok := byID[hit.ID]; ok && (keep == nil || keep(artifact)) {
+			ranked = append(ranked, artifact)
+		}
+	}
+
+	page := &dto.ArtifactPage{Items: []*model.Artifact{}, Limit: filter.Limit}
+	if offset < len(ranked) {
+		end := min(offset+filter.Limit, len(ranked))
+		page.Items = ranked[offset:end]
+		if end < len(ranked) {
+			page.HasMore = true
+			page.NextCursor = encodeOffsetCursor(end)
+		}
+	}
+	return page, nil
+}
+
+// emptySearchPage 没有结果时的空页
+func emptySearchPage(limit int) *dto.SearchPage {
+	return &dto.SearchPage{Items: []*dto.SearchHit{}, Limit: limit}
+}
+
+// encodeOffsetCursor 生成相关度排序的游标
+func encodeOffsetCursor(offset int) string {
+	data, _ := json.Marshal(pageCursor{Sort: sortRelevance, Value: strconv.Itoa(offset)})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeOffsetCursor 解析相关度排序的游标，空游标表示第一页
+func decodeOffsetCursor(raw string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, errs.InvalidArgument("invalid cursor")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return 0, errs.InvalidArgument("invalid cursor")
+	}
+	if cursor.Sort != sortRelevance {
+		return 0, errs.InvalidArgument("cursor does not match the requested sort order")
+	}
+	offset, err := strconv.Atoi(cursor.Value)
+	if err != nil || offset < 0 {
+		return 0, errs.InvalidArgument("invalid cursor")
+	}
+	return offset, nil
+}
+
+// readableRepositories 返回可搜索的仓库，键为仓库ID
+// 只包含启用的宿主和代理仓库；指定 repoIDOrName 时只搜索该仓库，组仓库展开为成员，
+// 此时只校验调用者对该仓库的读取权限，与通过组仓库下载一致；否则只包含调用者可读的仓库
+func (s *SearchServiceImpl) readableRepositories(ctx context.Context, repoIDOrName string) (map[string]*model.Repository, error) {
+	var candidates []*model.Repository
+	if repoIDOrName != "" {
+		repo, err := lookupRepository(ctx, s.artifacts.repositories, repoIDOrName)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizer.AuthorizeRepository(ctx, repo.ID, auth.ActionRead); err != nil {
+			return nil, err
+		}
+		candidates, err = s.artifacts.leafRepositories(ctx, repo, make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		all, err := s.artifacts.repositories.List(ctx, "", "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		readable, err := s.authorizer.RepositoryFilter(ctx, auth.ActionRead)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range all {
+			if readable(repo) {
+				candidates = append(candidates, repo)
+			}
+		}
+	}
+
+	repos := make(map[string]*model.Repository, len(candidates))
+	for _, repo := range candidates {
+		if repo.Type == model.RepositoryTypeGroup || repo.Status == "inactive" {
+			continue
+		}
+		repos[repo.ID] = repo
+	}
+	return repos, nil
+}
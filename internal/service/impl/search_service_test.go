@@ -0,0 +1,155 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// pomOf 生成最小的 POM，上传后解析出名称与描述并写入全文索引
+func pomOf(groupID, artifactID, version, description string) []byte {
+	return []byte(fmt.Sprintf(`<project><groupId>%s</groupId><artifactId>%s</artifactId><version>%s</version><description>%s</description></project>`,
+		groupID, artifactID, version, description))
+}
+
+// newSearchEnv 创建两个宿主仓库并上传带描述的 POM
+func newSearchEnv(t *testing.T) (*testEnv, *SearchServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	env.hosted(t, "internal", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "public", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"releases", "internal"}})
+
+	env.upload(t, "releases", "com/x/http-client/1.0/http-client-1.0.pom", pomOf("com.x", "http-client", "1.0", "http client"))
+	env.upload(t, "releases", "com/x/http-client/2.0/http-client-2.0.pom", pomOf("com.x", "http-client", "2.0", "http client"))
+	env.upload(t, "releases", "com/x/json/1.0/json-1.0.pom", pomOf("com.x", "json", "1.0", "json parser with an optional http codec and lots of other words"))
+	env.upload(t, "internal", "org/y/secret/1.0/secret-1.0.pom", pomOf("org.y", "secret", "1.0", "internal http gateway"))
+	// 直接构造，避免 NewSearchService 在后台重建索引与测试并发
+	return env, &SearchServiceImpl{logger: env.logger, artifacts: env.artifacts, authorizer: env.authorizer}
+}
+
+func searchPaths(page *dto.SearchPage) []string {
+	var out []string
+	for _, hit := range page.Items {
+		out = append(out, hit.Repository+":"+hit.Path)
+	}
+	return out
+}
+
+func TestSearchService_Search(t *testing.T) {
+	_, search := newSearchEnv(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query *dto.SearchQuery
+		want  []string
+	}{
+		{name: "group_id", query: &dto.SearchQuery{GroupID: "org.y"}, want: []string{"internal:org/y/secret/1.0/secret-1.0.pom"}},
+		{name: "version_range", query: &dto.SearchQuery{Name: "http-client", VersionRange: "[2.0,)"}, want: []string{"releases:com/x/http-client/2.0/http-client-2.0.pom"}},
+		{name: "repository_expands_group", query: &dto.SearchQuery{Repository: "public", Name: "json"}, want: []string{"releases:com/x/json/1.0/json-1.0.pom"}},
+		{name: "all_terms_must_match", query: &dto.SearchQuery{Q: "json http"}, want: []string{"releases:com/x/json/1.0/json-1.0.pom"}},
+		{name: "no_hits", query: &dto.SearchQuery{Q: "nothing-matches"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := search.Search(ctx, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, searchPaths(page))
+			assert.NotNil(t, page.Items)
+		})
+	}
+
+	t.Run("relevance_order", func(t *testing.T) {
+		page, err := search.Search(ctx, &dto.SearchQuery{Q: "http"})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 4)
+		// 描述较长的文档得分更低，排在最后
+		assert.Equal(t, "com/x/json/1.0/json-1.0.pom", page.Items[3].Path)
+	})
+
+	t.Run("relevance_paging", func(t *testing.T) {
+		query := &dto.SearchQuery{Q: "http", Limit: 3}
+		first, err := search.Search(ctx, query)
+		require.NoError(t, err)
+		require.True(t, first.HasMore)
+		query.Cursor = first.NextCursor
+		second, err := search.Search(ctx, query)
+		require.NoError(t, err)
+		assert.False(t, second.HasMore)
+		assert.Len(t, append(searchPaths(first), searchPaths(second)...), 4)
+	})
+}
+
+func TestSearchService_SearchRejects(t *testing.T) {
+	_, search := newSearchEnv(t)
+	tests := []struct {
+		name  string
+		query *dto.SearchQuery
+	}{
+		{name: "q_with_other_sort", query: &dto.SearchQuery{Q: "http", Sort: "-size"}},
+		{name: "relevance_without_q", query: &dto.SearchQuery{Sort: sortRelevance}},
+		{name: "invalid_version_range", query: &dto.SearchQuery{VersionRange: "[1.0"}},
+		{name: "list_cursor_with_q", query: &dto.SearchQuery{Q: "http", Cursor: encodeCursor(&model.ArtifactFilter{Sort: model.ArtifactSortCreatedAt}, &model.Artifact{ID: "a"})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := search.Search(context.Background(), tt.query)
+			assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+		})
+	}
+}
+
+func TestSearchService_OnlyReadableRepositories(t *testing.T) {
+	_, search := newSearchEnv(t)
+	ctx := asUser("dev", "repository:maven:releases:read")
+
+	page, err := search.Search(ctx, &dto.SearchQuery{Q: "http"})
+	require.NoError(t, err)
+	for _, hit := range page.Items {
+		assert.Equal(t, "releases", hit.Repository)
+	}
+	assert.Len(t, page.Items, 3)
+
+	_, err = search.Search(ctx, &dto.SearchQuery{Repository: "internal"})
+	assert.ErrorIs(t, err, errs.ErrForbidden)
+}
+
+func TestSearchService_RepositoryUpdateKeepsIndex(t *testing.T) {
+	env, search := newSearchEnv(t)
+	ctx := context.Background()
+
+	_, err := env.repositories.Update(ctx, "internal", &dto.UpdateRepositoryRequest{Description: "internal gateways"})
+	require.NoError(t, err)
+	page, err := search.Search(ctx, &dto.SearchQuery{Q: "gateway"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal:org/y/secret/1.0/secret-1.0.pom"}, searchPaths(page), "editing a repository keeps its documents indexed")
+
+	require.NoError(t, env.repositories.Delete(ctx, "public"))
+	require.NoError(t, env.repositories.Delete(ctx, "internal"))
+	page, err = search.Search(ctx, &dto.SearchQuery{Q: "gateway"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+}
+
+func TestOffsetCursor(t *testing.T) {
+	offset, err := decodeOffsetCursor(encodeOffsetCursor(25))
+	require.NoError(t, err)
+	assert.Equal(t, 25, offset)
+
+	offset, err = decodeOffsetCursor("")
+	require.NoError(t, err)
+	assert.Zero(t, offset)
+
+	for _, raw := range []string{"!!!", encodeOffsetCursor(-1)} {
+		_, err := decodeOffsetCursor(raw)
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument, raw)
+	}
+}
@@ -0,0 +1,643 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// 签名类型，由签名文件的扩展名决定
+const (
+	signatureTypePGP      = "pgp"      // .asc，ASCII 封装的 OpenPGP 分离签名
+	signatureTypeCosign   = "cosign"   // .sig，cosign sign-blob 生成的 base64 签名
+	signatureTypeSigstore = "sigstore" // .sigstore，Sigstore 签名包，如 npm provenance 的 DSSE 证明
+)
+
+// 签名校验状态
+const (
+	signatureStatusVerified = "verified"
+	signatureStatusFailed   = "failed"
+	signatureStatusUnsigned = "unsigned"
+)
+
+// 制品记录元数据中签名校验结果的键
+const (
+	metadataSignatureStatus      = "signature_status"
+	metadataSignatureType        = "signature_type"
+	metadataSignaturePath        = "signature_path"
+	metadataSignatureKey         = "signature_key"
+	metadataSignatureFingerprint = "signature_fingerprint"
+	metadataSignatureVerifiedAt  = "signature_verified_at"
+	metadataSignatureError       = "signature_error"
+)
+
+// signatureFile 签名文件扩展名与签名类型，同一制品有多个签名文件时按此顺序取第一个
+type signatureFile struct {
+	ext     string
+	sigType string
+	keyType string // 校验该类型签名的受信任公钥类型
+}
+
+var signatureFiles = []signatureFile{
+	{".asc", signatureTypePGP, model.TrustedKeyTypePGP},
+	{".sig", signatureTypeCosign, model.TrustedKeyTypeCosign},
+	{".sigstore", signatureTypeSigstore, model.TrustedKeyTypeCosign},
+}
+
+// maxSignatureFileSize 签名文件的最大字节数
+const maxSignatureFileSize = 1 << 20
+
+// errUntrustedSignature 签名不是由受信任公钥签署的
+var errUntrustedSignature = errors.New("signature is not made by a trusted key")
+
+// trustedKey 解析后的受信任公钥
+type trustedKey struct {
+	record      *model.TrustedKey
+	fingerprint string
+	entity      *openpgp.Entity  // pgp
+	public      crypto.PublicKey // cosign
+}
+
+// sigstoreBundle Sigstore 签名包中校验所需的字段
+type sigstoreBundle struct {
+	MessageSignature *struct {
+		MessageDigest struct {
+			Algorithm string `json:"algorithm"`
+			Digest    string `json:"digest"`
+		} `json:"messageDigest"`
+		Signature string `json:"signature"`
+	} `json:"messageSignature"`
+	DSSEEnvelope *struct {
+		Payload     string `json:"payload"`
+		PayloadType string `json:"payloadType"`
+		Signatures  []struct {
+			Sig string `json:"sig"`
+		} `json:"signatures"`
+	} `json:"dsseEnvelope"`
+}
+
+// inTotoStatement in-toto 证明中的被证明对象
+type inTotoStatement struct {
+	Subject []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// parseTrustedKey 解析受信任公钥并计算指纹，私钥或无法解析的公钥返回参数错误
+func parseTrustedKey(record *model.TrustedKey) (*trustedKey, error) {
+	key := &trustedKey{record: record}
+	switch record.Type {
+	case model.TrustedKeyTypePGP:
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(record.PublicKey))
+		if err != nil {
+			return nil, errs.InvalidArgument("invalid PGP public key: %v", err)
+		}
+		if len(entities) != 1 {
+			return nil, errs.InvalidArgument("PGP public key must contain exactly one key, got %d", len(entities))
+		}
+		if entities[0].PrivateKey != nil {
+			return nil, errs.InvalidArgument("trusted keys must not contain a private key")
+		}
+		key.entity = entities[0]
+		key.fingerprint = strings.ToUpper(hex.EncodeToString(key.entity.PrimaryKey.Fingerprint))
+	case model.TrustedKeyTypeCosign:
+		block, _ := pem.Decode([]byte(record.PublicKey))
+		if block == nil || block.Type != "PUBLIC KEY" {
+			return nil, errs.InvalidArgument("cosign public key must be a PEM encoded PUBLIC KEY")
+		}
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errs.InvalidArgument("invalid cosign public key: %v", err)
+		}
+		switch public.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, errs.InvalidArgument("unsupported cosign public key type %T", public)
+		}
+		sum := sha256.Sum256(block.Bytes)
+		key.public = public
+		key.fingerprint = hex.EncodeToString(sum[:])
+	default:
+		return nil, errs.InvalidArgument("unsupported trusted key type: %s", record.Type)
+	}
+	return key, nil
+}
+
+// verifySignature 使用受信任公钥校验制品内容的分离签名，返回签署的公钥
+// 制品内容从 content 流式读取一次，不整体读入内存
+func verifySignature(keys []*trustedKey, sigType string, content io.Reader, signature []byte) (*trustedKey, error) {
+	candidates := make([]*trustedKey, 0, len(keys))
+	for _, key := range keys {
+		if key.record.Type == keyTypeOf(sigType) {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no trusted %s key is configured", keyTypeOf(sigType))
+	}
+
+	switch sigType {
+	case signatureTypePGP:
+		return verifyPGP(candidates, content, signature)
+	case signatureTypeCosign:
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return nil, fmt.Errorf("signature is not base64 encoded")
+		}
+		digests, err := digestMessage(content, hasEd25519Key(candidates))
+		if err != nil {
+			return nil, err
+		}
+		return verifyWithKeys(candidates, digests, raw)
+	case signatureTypeSigstore:
+		return verifySigstore(candidates, content, signature)
+	}
+	return nil, fmt.Errorf("unsupported signature type: %s", sigType)
+}
+
+// verifyPGP 校验 ASCII 封装的 OpenPGP 分离签名
+func verifyPGP(keys []*trustedKey, content io.Reader, signature []byte) (*trustedKey, error) {
+	keyring := make(openpgp.EntityList, 0, len(keys))
+	for _, key := range keys {
+		keyring = append(keyring, key.entity)
+	}
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, content, bytes.NewReader(signature), nil)
+	if err != nil {
+		if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+			return nil, errUntrustedSignature
+		}
+		return nil, fmt.Errorf("invalid PGP signature: %v", err)
+	}
+	for _, key := range keys {
+		if key.entity == signer {
+			return key, nil
+		}
+	}
+	return nil, errUntrustedSignature
+}
+
+// verifySigstore 校验 Sigstore 签名包：消息签名直接校验制品内容，
+// DSSE 信封校验 PAE 编码后的证明，并要求证明的 subject 摘要与制品一致
+// 只支持以受信任公钥签署的签名包，不校验 Fulcio 证书与透明日志
+func verifySigstore(keys []*trustedKey, content io.Reader, signature []byte) (*trustedKey, error) {
+	var bundle sigstoreBundle
+	if err := json.Unmarshal(signature, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid sigstore bundle: %v", err)
+	}
+
+	switch {
+	case bundle.MessageSignature != nil:
+		digests, err := digestMessage(content, hasEd25519Key(keys))
+		if err != nil {
+			return nil, err
+		}
+		if digest := bundle.MessageSignature.MessageDigest.Digest; digest != "" {
+			expected, err := base64.StdEncoding.DecodeString(digest)
+			if err != nil || !bytes.Equal(expected, digests.sha256) {
+				return nil, fmt.Errorf("sigstore bundle digest does not match the artifact")
+			}
+		}
+		raw, err := base64.StdEncoding.DecodeString(bundle.MessageSignature.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("sigstore bundle signature is not base64 encoded")
+		}
+		return verifyWithKeys(keys, digests, raw)
+
+	case bundle.DSSEEnvelope != nil:
+		envelope := bundle.DSSEEnvelope
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("DSSE payload is not base64 encoded")
+		}
+		pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(envelope.PayloadType), envelope.PayloadType, len(payload), payload)
+		paeDigests, err := digestMessage(strings.NewReader(pae), true)
+		if err != nil {
+			return nil, err
+		}
+		var signer *trustedKey
+		for _, sig := range envelope.Signatures {
+			raw, err := base64.StdEncoding.DecodeString(sig.Sig)
+			if err != nil {
+				continue
+			}
+			if signer, err = verifyWithKeys(keys, paeDigests, raw); err == nil {
+				break
+			}
+		}
+		if signer == nil {
+			return nil, errUntrustedSignature
+		}
+		var statement inTotoStatement
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			return nil, fmt.Errorf("DSSE payload is not an in-toto statement: %v", err)
+		}
+		digests, err := digestMessage(content, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range statement.Subject {
+			if strings.EqualFold(subject.Digest["sha256"], hex.EncodeToString(digests.sha256)) ||
+				strings.EqualFold(subject.Digest["sha512"], hex.EncodeToString(digests.sha512)) {
+				return signer, nil
+			}
+		}
+		return nil, fmt.Errorf("attestation subject does not match the artifact")
+	}
+	return nil, fmt.Errorf("sigstore bundle contains no message signature or DSSE envelope")
+}
+
+// maxEd25519MessageSize Ed25519 只能对原文校验，校验时最多在内存中保留的原文字节数
+const maxEd25519MessageSize = 32 << 20
+
+// errEd25519MessageTooLarge 原文超过 maxEd25519MessageSize，无法使用 Ed25519 公钥校验
+var errEd25519MessageTooLarge = fmt.Errorf("Ed25519 signatures can only be verified for artifacts up to %d bytes", maxEd25519MessageSize)
+
+// messageDigests 流式读取一次消息得到的摘要，message 为 Ed25519 校验保留的原文，未保留时为 nil
+type messageDigests struct {
+	sha256, sha384, sha512 []byte
+	message                []byte
+}
+
+// digestMessage 读取消息并计算 sigstore 默认算法所需的摘要，keepMessage 时保留不超过 maxEd25519MessageSize 的原文
+func digestMessage(r io.Reader, keepMessage bool) (*messageDigests, error) {
+	h256, h384, h512 := sha256.New(), sha512.New384(), sha512.New()
+	writers := []io.Writer{h256, h384, h512}
+	var message *cappedBuffer
+	if keepMessage {
+		message = &cappedBuffer{limit: maxEd25519MessageSize}
+		writers = append(writers, message)
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	digests := &messageDigests{sha256: h256.Sum(nil), sha384: h384.Sum(nil), sha512: h512.Sum(nil)}
+	if message != nil && !message.overflow {
+		digests.message = message.Bytes()
+	}
+	return digests, nil
+}
+
+// cappedBuffer 超过 limit 后丢弃已写入的内容并忽略后续写入
+type cappedBuffer struct {
+	bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if !b.overflow {
+		if b.Len()+len(p) > b.limit {
+			b.overflow = true
+			b.Reset()
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// hasEd25519Key 判断公钥中是否有 Ed25519 公钥，只有此时才需要保留原文
+func hasEd25519Key(keys []*trustedKey) bool {
+	for _, key := range keys {
+		if _, ok := key.public.(ed25519.PublicKey); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyWithKeys 依次使用 cosign 公钥校验签名
+func verifyWithKeys(keys []*trustedKey, digests *messageDigests, signature []byte) (*trustedKey, error) {
+	skipped := false
+	for _, key := range keys {
+		if _, ok := key.public.(ed25519.PublicKey); ok && digests.message == nil {
+			skipped = true
+			continue
+		}
+		if verifyPublicKey(key.public, digests, signature) {
+			return key, nil
+		}
+	}
+	if skipped {
+		return nil, errEd25519MessageTooLarge
+	}
+	return nil, errUntrustedSignature
+}
+
+// verifyPublicKey 按 sigstore 的默认算法校验签名：ECDSA 按曲线选择摘要算法，RSA 使用 SHA-256，Ed25519 直接校验消息
+func verifyPublicKey(public crypto.PublicKey, digests *messageDigests, signature []byte) bool {
+	switch key := public.(type) {
+	case *ecdsa.PublicKey:
+		digest := digests.sha256
+		switch key.Curve {
+		case elliptic.P384():
+			digest = digests.sha384
+		case elliptic.P521():
+			digest = digests.sha512
+		}
+		return ecdsa.VerifyASN1(key, digest, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digests.sha256, signature) == nil ||
+			rsa.VerifyPSS(key, crypto.SHA256, digests.sha256, signature, nil) == nil
+	case ed25519.PublicKey:
+		return digests.message != nil && ed25519.Verify(key, digests.message, signature)
+	}
+	return false
+}
+
+// keyTypeOf 校验签名类型所需的受信任公钥类型
+func keyTypeOf(sigType string) string {
+	for _, file := range signatureFiles {
+		if file.sigType == sigType {
+			return file.keyType
+		}
+	}
+	return ""
+}
+
+// signatureTarget 签名文件对应的被签名制品路径与签名类型
+func signatureTarget(p string) (string, string, bool) {
+	for _, file := range signatureFiles {
+		if target := strings.TrimSuffix(p, file.ext); target != p && target != "" && !strings.HasSuffix(target, "/") {
+			return target, file.sigType, true
+		}
+	}
+	return "", "", false
+}
+
+// signatureExtension 按内容判断随制品上传的签名对应的扩展名
+func signatureExtension(signature []byte) string {
+	trimmed := bytes.TrimSpace(signature)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte(pgpSignatureHeader)):
+		return ".asc"
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return ".sigstore"
+	}
+	return ".sig"
+}
+
+// isPrimaryFile 判断路径是否为制品主文件，校验和、签名与元数据文件及没有坐标的路径不是主文件
+func isPrimaryFile(formatPlugin pluginapi.FormatPlugin, p string) bool {
+	if isSidecarPath(p) || isMetadataFile(formatPlugin, p) {
+		return false
+	}
+	if locator, ok := formatPlugin.(pluginapi.ArtifactLocator); ok {
+		if _, _, ok := locator.Coordinates(p); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// signatureKeys 仓库签名策略信任的公钥，策略未指定公钥时信任所有受信任公钥
+func (s *ArtifactServiceImpl) signatureKeys(ctx context.Context, repo *model.Repository) ([]*trustedKey, error) {
+	var (
+		records []*model.TrustedKey
+		err     error
+	)
+	if len(repo.SignaturePolicy.Keys) > 0 {
+		records, err = s.trustedKeys.FindByNames(ctx, repo.SignaturePolicy.Keys)
+	} else {
+		records, err = s.trustedKeys.List(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trusted keys: %w", err)
+	}
+	keys := make([]*trustedKey, 0, len(records))
+	for _, record := range records {
+		key, err := parseTrustedKey(record)
+		if err != nil {
+			s.logger.Warn("Invalid trusted key ignored", "name", record.Name, "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// enforceSignaturePolicy 仓库要求签名时校验上传内容，未要求签名时直接通过
+// 签名文件必须能校验已上传的被签名制品，被签名制品尚未上传时先接受签名文件；
+// 制品主文件必须随上传附带签名，或同目录下已有能通过校验的签名文件
+// 上传内容从 content 流式读取，签名文件不能超过 maxSignatureFileSize
+func (s *ArtifactServiceImpl) enforceSignaturePolicy(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string, content io.Reader, signature []byte) error {
+	if !repo.SignaturePolicy.Required {
+		return nil
+	}
+	keys, err := s.signatureKeys(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	if target, sigType, ok := signatureTarget(p); ok {
+		artifact, err := s.repository.FindByPath(ctx, repo.ID, target)
+		if err != nil {
+			return fmt.Errorf("failed to find artifact: %w", err)
+		}
+		if artifact == nil {
+			return nil
+		}
+		data, err := io.ReadAll(io.LimitReader(content, maxSignatureFileSize+1))
+		if err != nil {
+			return fmt.Errorf("failed to read signature: %w", err)
+		}
+		if len(data) > maxSignatureFileSize {
+			return errs.InvalidArgument("signature %q exceeds %d bytes", p, maxSignatureFileSize)
+		}
+		targetContent, err := openContent(ctx, s.storage, storagePath(repo, target))
+		if err != nil {
+			return fmt.Errorf("failed to read artifact: %w", err)
+		}
+		defer targetContent.Close()
+		if _, err := verifySignature(keys, sigType, targetContent, data); err != nil {
+			return errs.InvalidArgument("signature %q is not valid for %q: %v", p, target, err)
+		}
+		return nil
+	}
+	if !isPrimaryFile(formatPlugin, p) {
+		return nil
+	}
+
+	sigPath := p + signatureExtension(signature)
+	if signature == nil {
+		var found bool
+		if sigPath, signature, found, err = s.findSignature(ctx, repo, p); err != nil {
+			return err
+		}
+		if !found {
+			return errs.InvalidArgument("repository %q requires a signature for %q, upload the signature file first or include it with the artifact", repo.Name, p)
+		}
+	}
+	_, sigType, _ := signatureTarget(sigPath)
+	if _, err := verifySignature(keys, sigType, content, signature); err != nil {
+		return errs.InvalidArgument("signature %q is not valid for %q: %v", sigPath, p, err)
+	}
+	return nil
+}
+
+// findSignature 查找制品同目录下的签名文件并读取内容
+func (s *ArtifactServiceImpl) findSignature(ctx context.Context, repo *model.Repository, p string) (string, []byte, bool, error) {
+	for _, file := range signatureFiles {
+		sigPath := p + file.ext
+		artifact, err := s.repository.FindByPath(ctx, repo.ID, sigPath)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("failed to find artifact: %w", err)
+		}
+		if artifact == nil {
+			continue
+		}
+		data, err := s.storage.Download(ctx, storagePath(repo, sigPath))
+		if err != nil {
+			return "", nil, false, fmt.Errorf("failed to read signature: %w", err)
+		}
+		return sigPath, data, true, nil
+	}
+	return "", nil, false, nil
+}
+
+// checkSignature 校验仓库中制品同目录下的签名文件是否由给定公钥签署
+func (s *ArtifactServiceImpl) checkSignature(ctx context.Context, repo *model.Repository, keys []*trustedKey, p string) error {
+	sigPath, signature, found, err := s.findSignature(ctx, repo, p)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no signature file for %s", p)
+	}
+	content, err := openContent(ctx, s.storage, storagePath(repo, p))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	defer content.Close()
+	_, sigType, _ := signatureTarget(sigPath)
+	if _, err := verifySignature(keys, sigType, content, signature); err != nil {
+		return fmt.Errorf("%s is not valid for %s: %v", sigPath, p, err)
+	}
+	return nil
+}
+
+// recordSignature 上传或删除制品后重新校验受影响制品的签名，失败只记录日志
+// 签名文件变化时校验被签名制品，artifact 为 nil 时按路径查找制品记录
+func (s *ArtifactServiceImpl) recordSignature(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string, artifact *model.Artifact, data []byte) {
+	if target, _, ok := signatureTarget(p); ok {
+		p, artifact, data = target, nil, nil
+	}
+	if !isPrimaryFile(formatPlugin, p) {
+		return
+	}
+	if artifact == nil {
+		var err error
+		if artifact, err = s.repository.FindByPath(ctx, repo.ID, p); err != nil || artifact == nil {
+			return
+		}
+	}
+	if _, err := s.verifyArtifact(ctx, repo, artifact, data); err != nil {
+		s.logger.Warn("Failed to verify artifact signature", "repository", repo.Name, "path", p, "error", err)
+	}
+}
+
+// verifyArtifact 使用仓库信任的公钥校验制品的签名文件，校验结果写入制品记录的元数据
+// data 为 nil 时从存储读取制品内容
+func (s *ArtifactServiceImpl) verifyArtifact(ctx context.Context, repo *model.Repository, artifact *model.Artifact, data []byte) (*dto.SignatureStatus, error) {
+	sigPath, signature, found, err := s.findSignature(ctx, repo, artifact.Path)
+	if err != nil {
+		return nil, err
+	}
+	status := &dto.SignatureStatus{Repository: repo.Name, Path: artifact.Path, Status: signatureStatusUnsigned}
+	if found {
+		var content io.Reader = bytes.NewReader(data)
+		if data == nil {
+			file, err := openContent(ctx, s.storage, storagePath(repo, artifact.Path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read artifact: %w", err)
+			}
+			defer file.Close()
+			content = file
+		}
+		keys, err := s.signatureKeys(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		_, sigType, _ := signatureTarget(sigPath)
+		now := time.Now().UTC()
+		status.Type, status.SignaturePath, status.VerifiedAt = sigType, sigPath, &now
+		if signer, err := verifySignature(keys, sigType, content, signature); err != nil {
+			status.Status, status.Error = signatureStatusFailed, err.Error()
+		} else {
+			status.Status, status.Key, status.Fingerprint = signatureStatusVerified, signer.record.Name, signer.fingerprint
+		}
+	}
+
+	if artifact.Metadata == nil {
+		artifact.Metadata = make(map[string]string)
+	}
+	for _, key := range []string{metadataSignatureStatus, metadataSignatureType, metadataSignaturePath, metadataSignatureKey,
+		metadataSignatureFingerprint, metadataSignatureVerifiedAt, metadataSignatureError} {
+		delete(artifact.Metadata, key)
+	}
+	if found {
+		for key, value := range map[string]string{
+			metadataSignatureStatus:      status.Status,
+			metadataSignatureType:        status.Type,
+			metadataSignaturePath:        status.SignaturePath,
+			metadataSignatureKey:         status.Key,
+			metadataSignatureFingerprint: status.Fingerprint,
+			metadataSignatureVerifiedAt:  status.VerifiedAt.Format(time.RFC3339),
+			metadataSignatureError:       status.Error,
+		} {
+			if value != "" {
+				artifact.Metadata[key] = value
+			}
+		}
+	}
+	if err := s.repository.Update(ctx, artifact); err != nil {
+		return nil, fmt.Errorf("failed to save artifact: %w", err)
+	}
+	s.index.Add(indexDocument(artifact))
+	s.logger.Debug("Artifact signature verified", "repository", repo.Name, "path", artifact.Path, "status", status.Status)
+	return status, nil
+}
+
+// signatureStatusOf 读取制品记录中保存的签名校验结果
+func signatureStatusOf(repo *model.Repository, artifact *model.Artifact) *dto.SignatureStatus {
+	status := &dto.SignatureStatus{Repository: repo.Name, Path: artifact.Path, Status: signatureStatusUnsigned}
+	values := artifact.Metadata
+	if values[metadataSignatureStatus] == "" {
+		return status
+	}
+	status.Status = values[metadataSignatureStatus]
+	status.Type = values[metadataSignatureType]
+	status.SignaturePath = values[metadataSignaturePath]
+	status.Key = values[metadataSignatureKey]
+	status.Fingerprint = values[metadataSignatureFingerprint]
+	status.Error = values[metadataSignatureError]
+	if verifiedAt, err := time.Parse(time.RFC3339, values[metadataSignatureVerifiedAt]); err == nil {
+		status.VerifiedAt = &verifiedAt
+	}
+	return status
+}
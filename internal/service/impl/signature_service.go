@@ -0,0 +1,163 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// SignatureServiceImpl 制品签名服务实现
+// 受信任公钥用于校验上传的分离签名，校验结果保存在制品记录的元数据中
+type SignatureServiceImpl struct {
+	logger    *slog.Logger
+	artifacts *ArtifactServiceImpl
+	keys      repository.TrustedKeyRepository
+}
+
+// NewSignatureService 创建新的制品签名服务实现
+func NewSignatureService(logger *slog.Logger, artifacts *ArtifactServiceImpl, keys repository.TrustedKeyRepository) *SignatureServiceImpl {
+	return &SignatureServiceImpl{
+		logger:    logger,
+		artifacts: artifacts,
+		keys:      keys,
+	}
+}
+
+// CreateKey 解析公钥并计算指纹后保存，名称格式与清理策略相同
+func (s *SignatureServiceImpl) CreateKey(ctx context.Context, req *dto.TrustedKeyRequest) (*model.TrustedKey, error) {
+	if !cleanupPolicyNamePattern.MatchString(req.Name) {
+		return nil, errs.InvalidArgument("invalid trusted key name %q", req.Name)
+	}
+	existing, err := s.keys.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trusted key: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("trusted key %q already exists", req.Name)
+	}
+
+	key := &model.TrustedKey{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Type:        req.Type,
+		PublicKey:   req.PublicKey,
+		Description: req.Description,
+	}
+	parsed, err := parseTrustedKey(key)
+	if err != nil {
+		return nil, err
+	}
+	key.Fingerprint = parsed.fingerprint
+	if err := s.keys.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create trusted key: %w", err)
+	}
+	s.logger.Info("Trusted key created", "name", key.Name, "type", key.Type, "fingerprint", key.Fingerprint)
+	return key, nil
+}
+
+// GetKey 获取受信任公钥
+func (s *SignatureServiceImpl) GetKey(ctx context.Context, name string) (*model.TrustedKey, error) {
+	key, err := s.keys.FindByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trusted key: %w", err)
+	}
+	if key == nil {
+		return nil, errs.NotFound("trusted key %q not found", name)
+	}
+	return key, nil
+}
+
+// ListKeys 列出受信任公钥
+func (s *SignatureServiceImpl) ListKeys(ctx context.Context) ([]*model.TrustedKey, error) {
+	keys, err := s.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trusted keys: %w", err)
+	}
+	return keys, nil
+}
+
+// DeleteKey 删除受信任公钥，仍被仓库签名策略引用时返回冲突
+// 未指定公钥且要求签名的仓库信任所有公钥，不能删除最后一个公钥
+func (s *SignatureServiceImpl) DeleteKey(ctx context.Context, name string) error {
+	key, err := s.GetKey(ctx, name)
+	if err != nil {
+		return err
+	}
+	repos, err := s.artifacts.repositories.List(ctx, model.RepositoryTypeHosted, "")
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+	keys, err := s.keys.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list trusted keys: %w", err)
+	}
+	for _, repo := range repos {
+		policy := repo.SignaturePolicy
+		if containsString(policy.Keys, key.Name) {
+			return errs.Conflict("trusted key %q is used by repository %q", key.Name, repo.Name)
+		}
+		if policy.Required && len(policy.Keys) == 0 && len(keys) == 1 {
+			return errs.Conflict("trusted key %q is the only key trusted by repository %q", key.Name, repo.Name)
+		}
+	}
+	if err := s.keys.Delete(ctx, key.ID); err != nil {
+		return fmt.Errorf("failed to delete trusted key: %w", err)
+	}
+	s.logger.Info("Trusted key deleted", "name", key.Name)
+	return nil
+}
+
+// Status 获取制品记录中保存的签名校验结果，未校验过的制品为 unsigned
+func (s *SignatureServiceImpl) Status(ctx context.Context, repoIDOrName, path string) (*dto.SignatureStatus, error) {
+	repo, artifact, err := s.resolve(ctx, repoIDOrName, path)
+	if err != nil {
+		return nil, err
+	}
+	return signatureStatusOf(repo, artifact), nil
+}
+
+// Verify 重新校验制品签名，受信任公钥或仓库签名策略变化后使用
+func (s *SignatureServiceImpl) Verify(ctx context.Context, repoIDOrName, path string) (*dto.SignatureStatus, error) {
+	repo, artifact, err := s.resolve(ctx, repoIDOrName, path)
+	if err != nil {
+		return nil, err
+	}
+	return s.artifacts.verifyArtifact(ctx, repo, artifact, nil)
+}
+
+// resolve 查找制品主文件的记录，签名与校验和等附属文件没有签名状态
+func (s *SignatureServiceImpl) resolve(ctx context.Context, repoIDOrName, rawPath string) (*model.Repository, *model.Artifact, error) {
+	repo, err := lookupRepository(ctx, s.artifacts.repositories, repoIDOrName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if repo.Type == model.RepositoryTypeGroup {
+		return nil, nil, errs.InvalidArgument("repository %q is a group repository, query the artifact in its member", repo.Name)
+	}
+	p, err := normalizePath(rawPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	formatPlugin, err := s.artifacts.plugins.GetFormatPlugin(repo.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isPrimaryFile(formatPlugin, p) {
+		return nil, nil, errs.InvalidArgument("%q is not an artifact that can be signed", p)
+	}
+	artifact, err := s.artifacts.repository.FindByPath(ctx, repo.ID, p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find artifact: %w", err)
+	}
+	if artifact == nil {
+		return nil, nil, errs.NotFound("artifact %q not found in repository %q", p, repo.Name)
+	}
+	return repo, artifact, nil
+}
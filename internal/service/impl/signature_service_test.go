@@ -0,0 +1,172 @@
+package impl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// newSignatureEnv 创建测试环境与签名服务
+func newSignatureEnv(t *testing.T) (*testEnv, *SignatureServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	return env, NewSignatureService(env.logger, env.artifacts, env.keyRepo)
+}
+
+func TestSignatureService_CreateKey(t *testing.T) {
+	entity := testPGPEntity(t, "release")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cosign := cosignRecord(t, "cosign", &ecKey.PublicKey)
+
+	tests := []struct {
+		name    string
+		req     dto.TrustedKeyRequest
+		wantErr error
+	}{
+		{name: "pgp", req: dto.TrustedKeyRequest{Name: "release-pgp", Type: model.TrustedKeyTypePGP, PublicKey: armoredEntities(t, false, entity), Description: "release"}},
+		{name: "cosign", req: dto.TrustedKeyRequest{Name: "release.cosign", Type: model.TrustedKeyTypeCosign, PublicKey: cosign.PublicKey}},
+		{name: "duplicate_name", req: dto.TrustedKeyRequest{Name: "existing", Type: model.TrustedKeyTypeCosign, PublicKey: cosign.PublicKey}, wantErr: errs.ErrConflict},
+		{name: "invalid_name", req: dto.TrustedKeyRequest{Name: "bad name", Type: model.TrustedKeyTypeCosign, PublicKey: cosign.PublicKey}, wantErr: errs.ErrInvalidArgument},
+		{name: "invalid_key", req: dto.TrustedKeyRequest{Name: "broken", Type: model.TrustedKeyTypePGP, PublicKey: cosign.PublicKey}, wantErr: errs.ErrInvalidArgument},
+		{name: "private_key", req: dto.TrustedKeyRequest{Name: "secret", Type: model.TrustedKeyTypePGP, PublicKey: armoredEntities(t, true, entity)}, wantErr: errs.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, signatures := newSignatureEnv(t)
+			ctx := context.Background()
+			require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "existing", testPGPEntity(t, "ops"))))
+
+			req := tt.req
+			key, err := signatures.CreateKey(ctx, &req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				keys, err := signatures.ListKeys(ctx)
+				require.NoError(t, err)
+				assert.Len(t, keys, 1)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, key.ID)
+			assert.Equal(t, mustParseKey(t, key).fingerprint, key.Fingerprint)
+
+			stored, err := signatures.GetKey(ctx, req.Name)
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, stored.ID)
+			assert.Equal(t, req.Type, stored.Type)
+			assert.Equal(t, req.Description, stored.Description)
+			assert.Equal(t, key.Fingerprint, stored.Fingerprint)
+		})
+	}
+}
+
+func TestSignatureService_GetKey(t *testing.T) {
+	_, signatures := newSignatureEnv(t)
+	_, err := signatures.GetKey(context.Background(), "missing")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestSignatureService_DeleteKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		policy  model.SignaturePolicy
+		extra   bool // 另外登记 ops 公钥
+		wantErr error
+	}{
+		{name: "unused", key: "release", policy: model.SignaturePolicy{Keys: []string{"ops"}}, extra: true},
+		{name: "referenced_by_policy", key: "release", policy: model.SignaturePolicy{Required: true, Keys: []string{"release"}}, extra: true, wantErr: errs.ErrConflict},
+		{name: "only_key_of_required_policy", key: "release", policy: model.SignaturePolicy{Required: true}, wantErr: errs.ErrConflict},
+		{name: "one_of_several_keys", key: "release", policy: model.SignaturePolicy{Required: true}, extra: true},
+		{name: "missing", key: "missing", wantErr: errs.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, signatures := newSignatureEnv(t)
+			ctx := context.Background()
+			require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "release", testPGPEntity(t, "release"))))
+			if tt.extra {
+				require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "ops", testPGPEntity(t, "ops"))))
+			}
+			env.createRepo(t, &dto.CreateRepositoryRequest{Name: "signed", Type: model.RepositoryTypeHosted, Format: "maven", SignaturePolicy: tt.policy})
+
+			err := signatures.DeleteKey(ctx, tt.key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			_, err = signatures.GetKey(ctx, tt.key)
+			assert.ErrorIs(t, err, errs.ErrNotFound)
+
+			// 删除后名称可以重新登记
+			_, err = signatures.CreateKey(ctx, &dto.TrustedKeyRequest{Name: tt.key, Type: model.TrustedKeyTypePGP,
+				PublicKey: armoredEntities(t, false, testPGPEntity(t, tt.key))})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSignatureService_StatusAndVerify(t *testing.T) {
+	const jar = "com/x/lib/1.0/lib-1.0.jar"
+	ctx := context.Background()
+	data := []byte("jar")
+	env, signatures := newSignatureEnv(t)
+	env.hosted(t, "libs", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "libs-group", Type: model.RepositoryTypeGroup, Format: "maven", Members: []string{"libs"}})
+	env.upload(t, "libs", jar, data)
+	env.upload(t, "libs", jar+".asc", pgpSign(t, testPGPEntity(t, "release"), data))
+
+	status, err := signatures.Status(ctx, "libs", "/"+jar)
+	require.NoError(t, err)
+	assert.Equal(t, signatureStatusFailed, status.Status, "no trusted key when the signature was uploaded")
+	assert.Contains(t, status.Error, "no trusted pgp key")
+
+	// 添加公钥后重新校验
+	require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "release", testPGPEntity(t, "release"))))
+	status, err = signatures.Verify(ctx, "libs", jar)
+	require.NoError(t, err)
+	assert.Equal(t, signatureStatusVerified, status.Status)
+	assert.Equal(t, "release", status.Key)
+	assert.Equal(t, jar+".asc", status.SignaturePath)
+	assert.Equal(t, "libs", status.Repository)
+
+	stored, err := signatures.Status(ctx, "libs", jar)
+	require.NoError(t, err)
+	assert.Equal(t, status.Status, stored.Status)
+	assert.Equal(t, status.Fingerprint, stored.Fingerprint)
+	require.NotNil(t, stored.VerifiedAt)
+	assert.WithinDuration(t, *status.VerifiedAt, *stored.VerifiedAt, time.Second)
+
+	tests := []struct {
+		name    string
+		repo    string
+		path    string
+		wantErr error
+	}{
+		{name: "group_repository", repo: "libs-group", path: jar, wantErr: errs.ErrInvalidArgument},
+		{name: "signature_file", repo: "libs", path: jar + ".asc", wantErr: errs.ErrInvalidArgument},
+		{name: "metadata_file", repo: "libs", path: "com/x/lib/maven-metadata.xml", wantErr: errs.ErrInvalidArgument},
+		{name: "invalid_path", repo: "libs", path: "../etc/passwd", wantErr: errs.ErrInvalidArgument},
+		{name: "missing_artifact", repo: "libs", path: "com/x/lib/2.0/lib-2.0.jar", wantErr: errs.ErrNotFound},
+		{name: "missing_repository", repo: "missing", path: jar, wantErr: errs.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signatures.Status(ctx, tt.repo, tt.path)
+			assert.ErrorIs(t, err, tt.wantErr)
+			_, err = signatures.Verify(ctx, tt.repo, tt.path)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
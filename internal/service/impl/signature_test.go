@@ -0,0 +1,624 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// testPGPEntities 按名称缓存的 OpenPGP 测试密钥
+var testPGPEntities sync.Map
+
+// testPGPEntity 生成或复用名为 name 的 Ed25519 OpenPGP 密钥
+func testPGPEntity(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+	if entity, ok := testPGPEntities.Load(name); ok {
+		return entity.(*openpgp.Entity)
+	}
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+	actual, _ := testPGPEntities.LoadOrStore(name, entity)
+	return actual.(*openpgp.Entity)
+}
+
+// armoredEntities ASCII 封装密钥，private 为 true 时包含私钥
+func armoredEntities(t *testing.T, private bool, entities ...*openpgp.Entity) string {
+	t.Helper()
+	blockType := openpgp.PublicKeyType
+	if private {
+		blockType = openpgp.PrivateKeyType
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, blockType, nil)
+	require.NoError(t, err)
+	for _, entity := range entities {
+		if private {
+			require.NoError(t, entity.SerializePrivate(w, nil))
+		} else {
+			require.NoError(t, entity.Serialize(w))
+		}
+	}
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+// pgpSign 生成 ASCII 封装的分离签名
+func pgpSign(t *testing.T, entity *openpgp.Entity, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&buf, entity, bytes.NewReader(data), nil))
+	return buf.Bytes()
+}
+
+// pgpRecord 以 entity 的公钥构造受信任公钥记录
+func pgpRecord(t *testing.T, name string, entity *openpgp.Entity) *model.TrustedKey {
+	t.Helper()
+	return &model.TrustedKey{ID: name, Name: name, Type: model.TrustedKeyTypePGP, PublicKey: armoredEntities(t, false, entity)}
+}
+
+// cosignRecord 以 PEM 编码的公钥构造受信任公钥记录
+func cosignRecord(t *testing.T, name string, public crypto.PublicKey) *model.TrustedKey {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	return &model.TrustedKey{ID: name, Name: name, Type: model.TrustedKeyTypeCosign,
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}
+}
+
+// mustParseKey 解析受信任公钥，失败时终止测试
+func mustParseKey(t *testing.T, record *model.TrustedKey) *trustedKey {
+	t.Helper()
+	key, err := parseTrustedKey(record)
+	require.NoError(t, err)
+	return key
+}
+
+// signBlob 按 sigstore 的默认算法生成原始签名
+func signBlob(t *testing.T, signer crypto.Signer, data []byte) []byte {
+	t.Helper()
+	var (
+		sig []byte
+		err error
+	)
+	switch key := signer.(type) {
+	case *ecdsa.PrivateKey:
+		var digest []byte
+		switch key.Curve {
+		case elliptic.P384():
+			sum := sha512.Sum384(data)
+			digest = sum[:]
+		case elliptic.P521():
+			sum := sha512.Sum512(data)
+			digest = sum[:]
+		default:
+			sum := sha256.Sum256(data)
+			digest = sum[:]
+		}
+		sig, err = ecdsa.SignASN1(rand.Reader, key, digest)
+	case *rsa.PrivateKey:
+		sum := sha256.Sum256(data)
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(key, data)
+	default:
+		t.Fatalf("unsupported signer %T", signer)
+	}
+	require.NoError(t, err)
+	return sig
+}
+
+// b64 标准 base64 编码
+func b64(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// messageBundle 构造包含消息签名的 sigstore 签名包，digest 为空时省略摘要
+func messageBundle(t *testing.T, digest string, sig []byte) []byte {
+	t.Helper()
+	bundle := map[string]interface{}{"messageSignature": map[string]interface{}{
+		"messageDigest": map[string]string{"algorithm": "SHA2_256", "digest": digest},
+		"signature":     b64(sig),
+	}}
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	return data
+}
+
+// dsseBundle 构造包含 DSSE 信封的 sigstore 签名包，sigs 为各签名的 base64 内容
+func dsseBundle(t *testing.T, payload string, sigs ...string) []byte {
+	t.Helper()
+	signatures := make([]map[string]string, 0, len(sigs))
+	for _, sig := range sigs {
+		signatures = append(signatures, map[string]string{"sig": sig})
+	}
+	data, err := json.Marshal(map[string]interface{}{"dsseEnvelope": map[string]interface{}{
+		"payload":     payload,
+		"payloadType": "application/vnd.in-toto+json",
+		"signatures":  signatures,
+	}})
+	require.NoError(t, err)
+	return data
+}
+
+// inTotoPayload 以 subject 摘要构造 in-toto 证明
+func inTotoPayload(t *testing.T, digest map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"_type":   "https://in-toto.io/Statement/v1",
+		"subject": []map[string]interface{}{{"name": "pkg.tgz", "digest": digest}},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// signDSSE 以 PAE 编码签署证明
+func signDSSE(t *testing.T, signer crypto.Signer, payload []byte) string {
+	t.Helper()
+	pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len("application/vnd.in-toto+json"), "application/vnd.in-toto+json", len(payload), payload)
+	return b64(signBlob(t, signer, []byte(pae)))
+}
+
+func TestParseTrustedKey(t *testing.T) {
+	entity := testPGPEntity(t, "release")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	x25519, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecRecord := cosignRecord(t, "ec", &ecKey.PublicKey)
+	ecBlock, _ := pem.Decode([]byte(ecRecord.PublicKey))
+	ecSum := sha256.Sum256(ecBlock.Bytes)
+	ecPrivate, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		record      *model.TrustedKey
+		fingerprint string
+		wantErr     string
+	}{
+		{name: "pgp", record: pgpRecord(t, "pgp", entity), fingerprint: strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))},
+		{name: "pgp_invalid", record: &model.TrustedKey{Type: model.TrustedKeyTypePGP, PublicKey: "not a key"}, wantErr: "invalid PGP public key"},
+		{name: "pgp_two_keys", record: &model.TrustedKey{Type: model.TrustedKeyTypePGP,
+			PublicKey: armoredEntities(t, false, entity, testPGPEntity(t, "other"))}, wantErr: "exactly one key, got 2"},
+		{name: "pgp_private_key", record: &model.TrustedKey{Type: model.TrustedKeyTypePGP,
+			PublicKey: armoredEntities(t, true, entity)}, wantErr: "must not contain a private key"},
+		{name: "cosign_ecdsa", record: ecRecord, fingerprint: hex.EncodeToString(ecSum[:])},
+		{name: "cosign_rsa", record: cosignRecord(t, "rsa", &testRSAKey(t, "cosign").PublicKey)},
+		{name: "cosign_ed25519", record: cosignRecord(t, "ed", edPublic)},
+		{name: "cosign_not_pem", record: &model.TrustedKey{Type: model.TrustedKeyTypeCosign, PublicKey: "not a key"}, wantErr: "PEM encoded PUBLIC KEY"},
+		{name: "cosign_private_key", record: &model.TrustedKey{Type: model.TrustedKeyTypeCosign,
+			PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecPrivate}))}, wantErr: "PEM encoded PUBLIC KEY"},
+		{name: "cosign_invalid_der", record: &model.TrustedKey{Type: model.TrustedKeyTypeCosign,
+			PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("garbage")}))}, wantErr: "invalid cosign public key"},
+		{name: "cosign_unsupported_key", record: cosignRecord(t, "x25519", x25519.PublicKey()), wantErr: "unsupported cosign public key type"},
+		{name: "unknown_type", record: &model.TrustedKey{Type: "x509", PublicKey: ecRecord.PublicKey}, wantErr: "unsupported trusted key type: x509"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := parseTrustedKey(tt.record)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, tt.record, key.record)
+			assert.NotEmpty(t, key.fingerprint)
+			if tt.fingerprint != "" {
+				assert.Equal(t, tt.fingerprint, key.fingerprint)
+			}
+			if tt.record.Type == model.TrustedKeyTypePGP {
+				assert.NotNil(t, key.entity)
+			} else {
+				assert.NotNil(t, key.public)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	data := []byte("artifact content")
+	entity, stranger := testPGPEntity(t, "release"), testPGPEntity(t, "stranger")
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey := testRSAKey(t, "cosign")
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pgpKey := mustParseKey(t, pgpRecord(t, "pgp", entity))
+	strangerKey := mustParseKey(t, pgpRecord(t, "stranger", stranger))
+	p256Key := mustParseKey(t, cosignRecord(t, "p256", p256.Public()))
+	p384Key := mustParseKey(t, cosignRecord(t, "p384", p384.Public()))
+	p521Key := mustParseKey(t, cosignRecord(t, "p521", p521.Public()))
+	rsaTrusted := mustParseKey(t, cosignRecord(t, "rsa", rsaKey.Public()))
+	edTrusted := mustParseKey(t, cosignRecord(t, "ed", edKey.Public()))
+
+	sum := sha256.Sum256(data)
+	sum512 := sha512.Sum512(data)
+	pssSig, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, sum[:], nil)
+	require.NoError(t, err)
+	cosign := func(signer crypto.Signer) []byte { return []byte(b64(signBlob(t, signer, data)) + "\n") }
+	payload := inTotoPayload(t, map[string]string{"sha256": hex.EncodeToString(sum[:])})
+	payload512 := inTotoPayload(t, map[string]string{"sha512": strings.ToUpper(hex.EncodeToString(sum512[:]))})
+	otherPayload := inTotoPayload(t, map[string]string{"sha256": strings.Repeat("0", 64)})
+
+	tests := []struct {
+		name      string
+		sigType   string
+		keys      []*trustedKey
+		content   []byte
+		signature []byte
+		wantKey   string
+		wantErr   error
+		errText   string
+	}{
+		{name: "pgp", sigType: signatureTypePGP, keys: []*trustedKey{strangerKey, pgpKey}, signature: pgpSign(t, entity, data), wantKey: "pgp"},
+		{name: "pgp_tampered", sigType: signatureTypePGP, keys: []*trustedKey{pgpKey}, content: []byte("tampered"),
+			signature: pgpSign(t, entity, data), errText: "invalid PGP signature"},
+		{name: "pgp_unknown_signer", sigType: signatureTypePGP, keys: []*trustedKey{pgpKey}, signature: pgpSign(t, stranger, data), wantErr: errUntrustedSignature},
+		{name: "pgp_not_armored", sigType: signatureTypePGP, keys: []*trustedKey{pgpKey}, signature: []byte("garbage"), errText: "invalid PGP signature"},
+		{name: "pgp_without_pgp_keys", sigType: signatureTypePGP, keys: []*trustedKey{p256Key}, signature: pgpSign(t, entity, data),
+			errText: "no trusted pgp key is configured"},
+
+		{name: "cosign_p256", sigType: signatureTypeCosign, keys: []*trustedKey{pgpKey, p256Key}, signature: cosign(p256), wantKey: "p256"},
+		{name: "cosign_p384", sigType: signatureTypeCosign, keys: []*trustedKey{p256Key, p384Key}, signature: cosign(p384), wantKey: "p384"},
+		{name: "cosign_p521", sigType: signatureTypeCosign, keys: []*trustedKey{p521Key}, signature: cosign(p521), wantKey: "p521"},
+		{name: "cosign_rsa_pkcs1v15", sigType: signatureTypeCosign, keys: []*trustedKey{rsaTrusted}, signature: cosign(rsaKey), wantKey: "rsa"},
+		{name: "cosign_rsa_pss", sigType: signatureTypeCosign, keys: []*trustedKey{rsaTrusted}, signature: []byte(b64(pssSig)), wantKey: "rsa"},
+		{name: "cosign_ed25519", sigType: signatureTypeCosign, keys: []*trustedKey{p256Key, edTrusted}, signature: cosign(edKey), wantKey: "ed"},
+		{name: "cosign_wrong_key", sigType: signatureTypeCosign, keys: []*trustedKey{p256Key, rsaTrusted}, signature: cosign(p384), wantErr: errUntrustedSignature},
+		{name: "cosign_tampered", sigType: signatureTypeCosign, keys: []*trustedKey{p256Key}, content: []byte("tampered"), signature: cosign(p256), wantErr: errUntrustedSignature},
+		{name: "cosign_not_base64", sigType: signatureTypeCosign, keys: []*trustedKey{p256Key}, signature: []byte("not base64!"), errText: "not base64 encoded"},
+		{name: "cosign_without_cosign_keys", sigType: signatureTypeCosign, keys: []*trustedKey{pgpKey}, signature: cosign(p256), errText: "no trusted cosign key"},
+
+		{name: "sigstore_message", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: messageBundle(t, b64(sum[:]), signBlob(t, p256, data)), wantKey: "p256"},
+		{name: "sigstore_message_without_digest", sigType: signatureTypeSigstore, keys: []*trustedKey{edTrusted},
+			signature: messageBundle(t, "", signBlob(t, edKey, data)), wantKey: "ed"},
+		{name: "sigstore_digest_mismatch", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: messageBundle(t, b64(sum512[:32]), signBlob(t, p256, data)), errText: "digest does not match"},
+		{name: "sigstore_digest_not_base64", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: messageBundle(t, "!!", signBlob(t, p256, data)), errText: "digest does not match"},
+		{name: "sigstore_signature_not_base64", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: []byte(`{"messageSignature":{"signature":"!!"}}`), errText: "signature is not base64 encoded"},
+		{name: "sigstore_message_untrusted", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: messageBundle(t, b64(sum[:]), signBlob(t, p384, data)), wantErr: errUntrustedSignature},
+		{name: "sigstore_invalid_json", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key}, signature: []byte("{"), errText: "invalid sigstore bundle"},
+		{name: "sigstore_empty_bundle", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key}, signature: []byte("{}"),
+			errText: "no message signature or DSSE envelope"},
+		{name: "sigstore_with_pgp_keys_only", sigType: signatureTypeSigstore, keys: []*trustedKey{pgpKey}, signature: []byte("{}"),
+			errText: "no trusted cosign key"},
+
+		{name: "dsse_sha256_subject", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, b64(payload), signDSSE(t, p256, payload)), wantKey: "p256"},
+		{name: "dsse_sha512_subject", sigType: signatureTypeSigstore, keys: []*trustedKey{edTrusted},
+			signature: dsseBundle(t, b64(payload512), signDSSE(t, edKey, payload512)), wantKey: "ed"},
+		{name: "dsse_skips_undecodable_signature", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, b64(payload), "!!", signDSSE(t, p384, payload), signDSSE(t, p256, payload)), wantKey: "p256"},
+		{name: "dsse_subject_mismatch", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, b64(otherPayload), signDSSE(t, p256, otherPayload)), errText: "attestation subject does not match"},
+		{name: "dsse_untrusted", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, b64(payload), signDSSE(t, p384, payload)), wantErr: errUntrustedSignature},
+		{name: "dsse_unsigned", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, b64(payload)), wantErr: errUntrustedSignature},
+		{name: "dsse_payload_not_base64", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, "!!"), errText: "DSSE payload is not base64 encoded"},
+		{name: "dsse_payload_not_statement", sigType: signatureTypeSigstore, keys: []*trustedKey{p256Key},
+			signature: dsseBundle(t, b64([]byte("[]")), signDSSE(t, p256, []byte("[]"))), errText: "not an in-toto statement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := data
+			if tt.content != nil {
+				content = tt.content
+			}
+			signer, err := verifySignature(tt.keys, tt.sigType, bytes.NewReader(content), tt.signature)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errText != "":
+				assert.ErrorContains(t, err, tt.errText)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantKey, signer.record.Name)
+			}
+		})
+	}
+}
+
+func TestVerifySignature_Ed25519MessageLimit(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys := []*trustedKey{{record: &model.TrustedKey{Name: "ed", Type: model.TrustedKeyTypeCosign}, public: public}}
+	sign := func(data []byte) []byte {
+		return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, data)))
+	}
+
+	small := []byte("artifact")
+	signer, err := verifySignature(keys, signatureTypeCosign, bytes.NewReader(small), sign(small))
+	require.NoError(t, err)
+	assert.Equal(t, "ed", signer.record.Name)
+
+	_, err = verifySignature(keys, signatureTypeCosign, bytes.NewReader([]byte("tampered")), sign(small))
+	assert.ErrorIs(t, err, errUntrustedSignature)
+
+	large := make([]byte, maxEd25519MessageSize+1)
+	_, err = verifySignature(keys, signatureTypeCosign, bytes.NewReader(large), sign(large))
+	assert.ErrorIs(t, err, errEd25519MessageTooLarge)
+}
+
+func TestCappedBuffer(t *testing.T) {
+	buf := &cappedBuffer{limit: 4}
+	n, err := buf.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.False(t, buf.overflow)
+
+	n, err = buf.Write([]byte("de"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "writes never fail so io.MultiWriter keeps hashing")
+	assert.True(t, buf.overflow)
+	assert.Zero(t, buf.Len())
+
+	buf.Write([]byte("f"))
+	assert.Zero(t, buf.Len())
+}
+
+func TestSignatureTarget(t *testing.T) {
+	tests := []struct {
+		path    string
+		target  string
+		sigType string
+		ok      bool
+	}{
+		{path: "com/x/lib-1.0.jar.asc", target: "com/x/lib-1.0.jar", sigType: signatureTypePGP, ok: true},
+		{path: "pkg/-/pkg-1.0.tgz.sig", target: "pkg/-/pkg-1.0.tgz", sigType: signatureTypeCosign, ok: true},
+		{path: "pkg/-/pkg-1.0.tgz.sigstore", target: "pkg/-/pkg-1.0.tgz", sigType: signatureTypeSigstore, ok: true},
+		{path: "com/x/lib-1.0.jar"},
+		{path: "com/x/lib-1.0.jar.sha1"},
+		{path: ".asc"},
+		{path: "com/x/.sig"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			target, sigType, ok := signatureTarget(tt.path)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.target, target)
+			assert.Equal(t, tt.sigType, sigType)
+		})
+	}
+}
+
+func TestSignatureExtension(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+		want      string
+	}{
+		{name: "pgp", signature: "\n" + pgpSignatureHeader + "\n\n-----END PGP SIGNATURE-----\n", want: ".asc"},
+		{name: "sigstore_bundle", signature: ` {"messageSignature":{}}`, want: ".sigstore"},
+		{name: "cosign", signature: "MEUCIQD=", want: ".sig"},
+		{name: "empty", want: ".sig"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, signatureExtension([]byte(tt.signature)))
+		})
+	}
+}
+
+func TestArtifactService_SignaturePolicy(t *testing.T) {
+	const jar = "com/x/lib/1.0/lib-1.0.jar"
+	data := []byte("signed jar")
+	release, stranger := testPGPEntity(t, "release"), testPGPEntity(t, "stranger")
+
+	tests := []struct {
+		name    string
+		keys    []string
+		setup   func(t *testing.T, env *testEnv)
+		req     dto.UploadArtifactRequest
+		wantErr error
+		errText string
+	}{
+		{name: "unsigned_artifact", req: dto.UploadArtifactRequest{Path: jar, Data: data},
+			wantErr: errs.ErrInvalidArgument, errText: "requires a signature"},
+		{name: "attached_signature", req: dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, release, data)}},
+		{name: "attached_signature_for_other_content", req: dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, release, []byte("other"))},
+			wantErr: errs.ErrInvalidArgument, errText: "is not valid"},
+		{name: "attached_signature_by_untrusted_key", req: dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, stranger, data)},
+			wantErr: errs.ErrInvalidArgument, errText: errUntrustedSignature.Error()},
+		{name: "policy_keys_exclude_signer", keys: []string{"ops"},
+			req:     dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, release, data)},
+			wantErr: errs.ErrInvalidArgument, errText: errUntrustedSignature.Error()},
+		{name: "signature_uploaded_first",
+			setup: func(t *testing.T, env *testEnv) { env.upload(t, "signed", jar+".asc", pgpSign(t, release, data)) },
+			req:   dto.UploadArtifactRequest{Path: jar, Data: data}},
+		{name: "existing_signature_does_not_match",
+			setup: func(t *testing.T, env *testEnv) {
+				env.upload(t, "signed", jar+".asc", pgpSign(t, release, []byte("other")))
+			},
+			req: dto.UploadArtifactRequest{Path: jar, Data: data}, wantErr: errs.ErrInvalidArgument},
+		{name: "new_signature_for_existing_artifact",
+			setup: func(t *testing.T, env *testEnv) {
+				_, err := env.artifacts.Upload(context.Background(), "signed", &dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, release, data)})
+				require.NoError(t, err)
+			},
+			req: dto.UploadArtifactRequest{Path: jar + ".asc", Data: pgpSign(t, release, data)}},
+		{name: "invalid_signature_for_existing_artifact",
+			setup: func(t *testing.T, env *testEnv) {
+				_, err := env.artifacts.Upload(context.Background(), "signed", &dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, release, data)})
+				require.NoError(t, err)
+			},
+			req: dto.UploadArtifactRequest{Path: jar + ".asc", Data: pgpSign(t, stranger, data)}, wantErr: errs.ErrInvalidArgument},
+		{name: "oversized_signature_for_existing_artifact",
+			setup: func(t *testing.T, env *testEnv) {
+				_, err := env.artifacts.Upload(context.Background(), "signed", &dto.UploadArtifactRequest{Path: jar, Data: data, Signature: pgpSign(t, release, data)})
+				require.NoError(t, err)
+			},
+			req:     dto.UploadArtifactRequest{Path: jar + ".sig", Data: make([]byte, maxSignatureFileSize+1)},
+			wantErr: errs.ErrInvalidArgument, errText: "exceeds"},
+		{name: "checksum_needs_no_signature", req: dto.UploadArtifactRequest{Path: jar + ".sha1", Data: []byte(sha1Hex(data))}},
+		{name: "metadata_needs_no_signature", req: dto.UploadArtifactRequest{Path: "com/x/lib/maven-metadata.xml", Data: []byte("<metadata/>")}},
+		{name: "signature_with_sidecar", req: dto.UploadArtifactRequest{Path: jar + ".sha1", Data: []byte(sha1Hex(data)), Signature: pgpSign(t, release, data)},
+			wantErr: errs.ErrInvalidArgument, errText: "can only be uploaded with an artifact"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+			require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "release", release)))
+			require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "ops", testPGPEntity(t, "ops"))))
+			env.createRepo(t, &dto.CreateRepositoryRequest{Name: "signed", Type: model.RepositoryTypeHosted, Format: "maven",
+				SignaturePolicy: model.SignaturePolicy{Required: true, Keys: tt.keys}})
+			if tt.setup != nil {
+				tt.setup(t, env)
+			}
+
+			req := tt.req
+			_, err := env.artifacts.Upload(ctx, "signed", &req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, tt.errText)
+				_, downloadErr := env.download("signed", req.Path)
+				if tt.setup == nil {
+					assert.ErrorIs(t, downloadErr, errs.ErrNotFound, "rejected uploads are not stored")
+				}
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestArtifactService_RecordSignature(t *testing.T) {
+	const jar = "com/x/lib/1.0/lib-1.0.jar"
+	ctx := context.Background()
+	data := []byte("jar")
+	release := testPGPEntity(t, "release")
+	env := newTestEnv(t)
+	require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "release", release)))
+	env.hosted(t, "libs", "maven")
+	statusOf := func() *dto.SignatureStatus {
+		artifact, err := env.artifactRepo.FindByPath(ctx, mustRepo(t, env, "libs").ID, jar)
+		require.NoError(t, err)
+		return signatureStatusOf(mustRepo(t, env, "libs"), artifact)
+	}
+
+	env.upload(t, "libs", jar, data)
+	assert.Equal(t, signatureStatusUnsigned, statusOf().Status)
+
+	// 未要求签名的仓库接受无效签名，并记录校验失败
+	env.upload(t, "libs", jar+".asc", pgpSign(t, testPGPEntity(t, "stranger"), data))
+	status := statusOf()
+	assert.Equal(t, signatureStatusFailed, status.Status)
+	assert.Equal(t, signatureTypePGP, status.Type)
+	assert.Equal(t, jar+".asc", status.SignaturePath)
+	assert.Contains(t, status.Error, errUntrustedSignature.Error())
+	assert.Empty(t, status.Key)
+	require.NotNil(t, status.VerifiedAt)
+
+	env.upload(t, "libs", jar+".asc", pgpSign(t, release, data))
+	status = statusOf()
+	assert.Equal(t, signatureStatusVerified, status.Status)
+	assert.Equal(t, "release", status.Key)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(release.PrimaryKey.Fingerprint)), status.Fingerprint)
+	assert.Empty(t, status.Error)
+
+	// 重新上传制品时按新内容校验
+	env.upload(t, "libs", jar, []byte("rebuilt"))
+	assert.Equal(t, signatureStatusFailed, statusOf().Status)
+
+	require.NoError(t, env.artifacts.Delete(ctx, "libs", jar+".asc"))
+	status = statusOf()
+	assert.Equal(t, signatureStatusUnsigned, status.Status)
+	assert.Empty(t, status.SignaturePath)
+	assert.Nil(t, status.VerifiedAt)
+}
+
+func TestSignatureStatusOf(t *testing.T) {
+	repo := &model.Repository{Name: "libs"}
+	assert.Equal(t, &dto.SignatureStatus{Repository: "libs", Path: "a.jar", Status: signatureStatusUnsigned},
+		signatureStatusOf(repo, &model.Artifact{Path: "a.jar"}))
+
+	status := signatureStatusOf(repo, &model.Artifact{Path: "a.jar", Metadata: map[string]string{
+		metadataSignatureStatus:     signatureStatusFailed,
+		metadataSignatureType:       signatureTypeCosign,
+		metadataSignaturePath:       "a.jar.sig",
+		metadataSignatureError:      "bad",
+		metadataSignatureVerifiedAt: "not a time",
+	}})
+	assert.Equal(t, &dto.SignatureStatus{Repository: "libs", Path: "a.jar", Status: signatureStatusFailed, Type: signatureTypeCosign,
+		SignaturePath: "a.jar.sig", Error: "bad"}, status)
+}
+
+func TestPromotionService_SignaturePolicy(t *testing.T) {
+	const jar = "com/x/lib/1.0/lib-1.0.jar"
+	release := testPGPEntity(t, "release")
+	tests := []struct {
+		name      string
+		signature []byte
+		wantErr   error
+	}{
+		{name: "unsigned", wantErr: errs.ErrConflict},
+		{name: "untrusted_signature", signature: pgpSign(t, testPGPEntity(t, "stranger"), []byte("jar")), wantErr: errs.ErrConflict},
+		{name: "signed", signature: pgpSign(t, release, []byte("jar"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, promotion := newPromotionEnv(t)
+			ctx := context.Background()
+			require.NoError(t, env.keyRepo.Create(ctx, pgpRecord(t, "release", release)))
+			env.createRepo(t, &dto.CreateRepositoryRequest{Name: "signed", Type: model.RepositoryTypeHosted, Format: "maven",
+				SignaturePolicy: model.SignaturePolicy{Required: true}})
+			if tt.signature != nil {
+				env.upload(t, "libs-staging", jar+".asc", tt.signature)
+			}
+
+			_, err := promotion.Promote(ctx, "libs-staging", &dto.PromoteRequest{Target: "signed", Path: jar})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, "requires signed artifacts")
+				return
+			}
+			require.NoError(t, err)
+			artifact, err := env.artifactRepo.FindByPath(ctx, mustRepo(t, env, "signed").ID, jar)
+			require.NoError(t, err)
+			status := signatureStatusOf(mustRepo(t, env, "signed"), artifact)
+			assert.Equal(t, signatureStatusVerified, status.Status, "the signature file is promoted with the artifact")
+			assert.Equal(t, "release", status.Key)
+		})
+	}
+}
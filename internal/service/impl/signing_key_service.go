@@ -0,0 +1,173 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// SigningKeyServiceImpl 元数据签名密钥服务实现
+type SigningKeyServiceImpl struct {
+	logger    *slog.Logger
+	keys      repository.SigningKeyRepository
+	signer    *MetadataSigner
+	artifacts *ArtifactServiceImpl
+}
+
+// NewSigningKeyService 创建新的签名密钥服务实现
+func NewSigningKeyService(logger *slog.Logger, keys repository.SigningKeyRepository, signer *MetadataSigner, artifacts *ArtifactServiceImpl) *SigningKeyServiceImpl {
+	return &SigningKeyServiceImpl{
+		logger:    logger,
+		keys:      keys,
+		signer:    signer,
+		artifacts: artifacts,
+	}
+}
+
+// ImportKey 解析并解密私钥，以主密钥重新加密后保存，只返回公钥与指纹
+func (s *SigningKeyServiceImpl) ImportKey(ctx context.Context, req *dto.SigningKeyRequest) (*model.SigningKey, error) {
+	if !cleanupPolicyNamePattern.MatchString(req.Name) {
+		return nil, errs.InvalidArgument("invalid signing key name %q", req.Name)
+	}
+	existing, err := s.keys.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("signing key %q already exists", req.Name)
+	}
+
+	entity, err := s.signer.parse(req.PrivateKey, req.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.signer.seal(entity)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := armoredPublicKey(entity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export public key: %w", err)
+	}
+	active, err := s.keys.FindActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+
+	key := &model.SigningKey{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		UserID:      primaryUserID(entity),
+		PublicKey:   publicKey,
+		PrivateKey:  sealed,
+		Description: req.Description,
+	}
+	key.Fingerprint, key.KeyID = keyFingerprint(entity)
+	if err := s.keys.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create signing key: %w", err)
+	}
+	s.logger.Info("Signing key imported", "name", key.Name, "fingerprint", key.Fingerprint)
+
+	if req.Active || active == nil {
+		return s.ActivateKey(ctx, key.Name)
+	}
+	return key, nil
+}
+
+// GetKey 获取签名密钥
+func (s *SigningKeyServiceImpl) GetKey(ctx context.Context, name string) (*model.SigningKey, error) {
+	key, err := s.keys.FindByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+	if key == nil {
+		return nil, errs.NotFound("signing key %q not found", name)
+	}
+	return key, nil
+}
+
+// ListKeys 列出签名密钥
+func (s *SigningKeyServiceImpl) ListKeys(ctx context.Context) ([]*model.SigningKey, error) {
+	keys, err := s.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	return keys, nil
+}
+
+// ActivateKey 停用当前启用的密钥并启用指定密钥；已有的元数据签名在元数据重新生成时更新
+func (s *SigningKeyServiceImpl) ActivateKey(ctx context.Context, name string) (*model.SigningKey, error) {
+	key, err := s.GetKey(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if key.Active {
+		return key, nil
+	}
+	// 确认主密钥能解密该密钥，避免启用后所有签名失败
+	if _, err := s.signer.decrypt(key.PrivateKey); err != nil {
+		return nil, errs.Conflict("signing key %q cannot be decrypted: %v", key.Name, err)
+	}
+
+	active, err := s.keys.FindActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+	if active != nil {
+		active.Active = false
+		if err := s.keys.Update(ctx, active); err != nil {
+			return nil, fmt.Errorf("failed to deactivate signing key: %w", err)
+		}
+	}
+	key.Active = true
+	if err := s.keys.Update(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to activate signing key: %w", err)
+	}
+	s.signer.Invalidate()
+	s.logger.Info("Signing key activated", "name", key.Name, "fingerprint", key.Fingerprint)
+	return key, nil
+}
+
+// DeleteKey 删除签名密钥，启用的密钥仍被开启元数据签名的仓库使用时返回冲突
+func (s *SigningKeyServiceImpl) DeleteKey(ctx context.Context, name string) error {
+	key, err := s.GetKey(ctx, name)
+	if err != nil {
+		return err
+	}
+	if key.Active {
+		repos, err := s.artifacts.repositories.List(ctx, model.RepositoryTypeHosted, "")
+		if err != nil {
+			return fmt.Errorf("failed to list repositories: %w", err)
+		}
+		for _, repo := range repos {
+			if repo.SignMetadata {
+				return errs.Conflict("signing key %q is active and repository %q signs metadata, activate another key first", key.Name, repo.Name)
+			}
+		}
+	}
+	if err := s.keys.Delete(ctx, key.ID); err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", err)
+	}
+	s.signer.Invalidate()
+	s.logger.Info("Signing key deleted", "name", key.Name)
+	return nil
+}
+
+// ActiveKey 获取启用的签名密钥
+func (s *SigningKeyServiceImpl) ActiveKey(ctx context.Context) (*model.SigningKey, error) {
+	key, err := s.keys.FindActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+	if key == nil {
+		return nil, errs.NotFound("no signing key is active")
+	}
+	return key, nil
+}
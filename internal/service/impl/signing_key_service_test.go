@@ -0,0 +1,214 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// newSigningEnv 创建测试环境与签名密钥服务
+func newSigningEnv(t *testing.T) (*testEnv, *SigningKeyServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	return env, NewSigningKeyService(env.logger, env.signingRepo, env.signer, env.artifacts)
+}
+
+// importKey 导入 testPGPEntity(name) 的私钥
+func importKey(t *testing.T, keys *SigningKeyServiceImpl, name string, active bool) *model.SigningKey {
+	t.Helper()
+	key, err := keys.ImportKey(context.Background(), &dto.SigningKeyRequest{Name: name, PrivateKey: armoredEntities(t, true, testPGPEntity(t, name)), Active: active})
+	require.NoError(t, err)
+	return key
+}
+
+// activeKeyName 当前启用的签名密钥名称，没有时为空
+func activeKeyName(t *testing.T, keys *SigningKeyServiceImpl) string {
+	t.Helper()
+	key, err := keys.ActiveKey(context.Background())
+	if errors.Is(err, errs.ErrNotFound) {
+		return ""
+	}
+	require.NoError(t, err)
+	return key.Name
+}
+
+func TestSigningKeyService_ImportKey(t *testing.T) {
+	release := testPGPEntity(t, "release")
+	protected := protectedKey(t, "s3cret")
+
+	tests := []struct {
+		name       string
+		existing   bool // 已导入并启用 ops
+		noSecret   bool
+		req        dto.SigningKeyRequest
+		wantErr    error
+		wantActive string
+	}{
+		{name: "first_key_is_activated", req: dto.SigningKeyRequest{Name: "release", PrivateKey: armoredEntities(t, true, release), Description: "releases"},
+			wantActive: "release"},
+		{name: "second_key_stays_inactive", existing: true, req: dto.SigningKeyRequest{Name: "release", PrivateKey: armoredEntities(t, true, release)},
+			wantActive: "ops"},
+		{name: "second_key_activated", existing: true, req: dto.SigningKeyRequest{Name: "release", PrivateKey: armoredEntities(t, true, release), Active: true},
+			wantActive: "release"},
+		{name: "protected_key", req: dto.SigningKeyRequest{Name: "protected", PrivateKey: protected, Passphrase: "s3cret"}, wantActive: "protected"},
+		{name: "duplicate_name", existing: true, req: dto.SigningKeyRequest{Name: "ops", PrivateKey: armoredEntities(t, true, release)},
+			wantErr: errs.ErrConflict, wantActive: "ops"},
+		{name: "invalid_name", req: dto.SigningKeyRequest{Name: "bad/name", PrivateKey: armoredEntities(t, true, release)}, wantErr: errs.ErrInvalidArgument},
+		{name: "public_key", req: dto.SigningKeyRequest{Name: "release", PrivateKey: armoredEntities(t, false, release)}, wantErr: errs.ErrInvalidArgument},
+		{name: "wrong_passphrase", req: dto.SigningKeyRequest{Name: "protected", PrivateKey: protected, Passphrase: "wrong"}, wantErr: errs.ErrInvalidArgument},
+		{name: "secret_not_configured", noSecret: true, req: dto.SigningKeyRequest{Name: "release", PrivateKey: armoredEntities(t, true, release)},
+			wantErr: errs.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, keys := newSigningEnv(t)
+			if tt.noSecret {
+				env.signer.secret = nil
+			}
+			if tt.existing {
+				importKey(t, keys, "ops", false)
+			}
+
+			req := tt.req
+			key, err := keys.ImportKey(context.Background(), &req)
+			assert.Equal(t, tt.wantActive, activeKeyName(t, keys))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive == req.Name, key.Active)
+			assert.Equal(t, req.Description, key.Description)
+			assert.Len(t, key.Fingerprint, 40)
+			assert.Len(t, key.KeyID, 16)
+			assert.Contains(t, key.UserID, "@example.com")
+			assert.Contains(t, key.PublicKey, "BEGIN PGP PUBLIC KEY BLOCK")
+			assert.NotContains(t, key.PrivateKey, "PRIVATE KEY", "the private key is sealed")
+
+			data, err := json.Marshal(key)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), key.PrivateKey, "responses never include the private key")
+			assert.NotContains(t, string(data), "private_key")
+		})
+	}
+}
+
+func TestSigningKeyService_ActivateKey(t *testing.T) {
+	env, keys := newSigningEnv(t)
+	ctx := context.Background()
+	importKey(t, keys, "release", false)
+	ops := importKey(t, keys, "ops", false)
+	assert.False(t, ops.Active)
+
+	key, err := keys.ActivateKey(ctx, "ops")
+	require.NoError(t, err)
+	assert.True(t, key.Active)
+	assert.Equal(t, "ops", activeKeyName(t, keys))
+	release, err := keys.GetKey(ctx, "release")
+	require.NoError(t, err)
+	assert.False(t, release.Active, "only one key is active")
+
+	// 启用后立即使用新密钥签名
+	_, signingKey, err := env.signer.Sign(ctx, []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, "ops", signingKey.Name)
+
+	key, err = keys.ActivateKey(ctx, "ops")
+	require.NoError(t, err)
+	assert.True(t, key.Active, "activating the active key is a no-op")
+
+	_, err = keys.ActivateKey(ctx, "missing")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+
+	// 主密钥更换后无法解密的密钥不能启用
+	env.signer.secret = make([]byte, 32)
+	_, err = keys.ActivateKey(ctx, "release")
+	assert.ErrorIs(t, err, errs.ErrConflict)
+	assert.Equal(t, "ops", activeKeyName(t, keys))
+}
+
+func TestSigningKeyService_DeleteKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		signMetadata bool
+		wantErr      error
+		wantActive   string
+	}{
+		{name: "inactive_key", key: "ops", signMetadata: true, wantActive: "release"},
+		{name: "active_key_without_signing_repositories", key: "release"},
+		{name: "active_key_used_for_signing", key: "release", signMetadata: true, wantErr: errs.ErrConflict, wantActive: "release"},
+		{name: "missing", key: "missing", wantErr: errs.ErrNotFound, wantActive: "release"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, keys := newSigningEnv(t)
+			ctx := context.Background()
+			importKey(t, keys, "release", true)
+			importKey(t, keys, "ops", false)
+			env.createRepo(t, &dto.CreateRepositoryRequest{Name: "libs", Type: model.RepositoryTypeHosted, Format: "maven", SignMetadata: tt.signMetadata})
+
+			err := keys.DeleteKey(ctx, tt.key)
+			assert.Equal(t, tt.wantActive, activeKeyName(t, keys))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			_, err = keys.GetKey(ctx, tt.key)
+			assert.ErrorIs(t, err, errs.ErrNotFound)
+
+			// 删除后私钥不再保留，名称可以重新导入
+			var count int64
+			require.NoError(t, env.db.Unscoped().Model(&model.SigningKey{}).Where("name = ?", tt.key).Count(&count).Error)
+			assert.Zero(t, count)
+			importKey(t, keys, tt.key, false)
+		})
+	}
+}
+
+func TestSigningKeyService_Keys(t *testing.T) {
+	_, keys := newSigningEnv(t)
+	ctx := context.Background()
+
+	_, err := keys.ActiveKey(ctx)
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+	_, err = keys.GetKey(ctx, "release")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+
+	importKey(t, keys, "release", false)
+	importKey(t, keys, "ops", false)
+	list, err := keys.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "ops", list[0].Name, "ordered by name")
+
+	active, err := keys.ActiveKey(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "release", active.Name)
+}
+
+func TestRepositoryService_SignMetadata(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.hosted(t, "libs", "maven")
+
+	repo := env.createRepo(t, &dto.CreateRepositoryRequest{Name: "signed", Type: model.RepositoryTypeHosted, Format: "maven", SignMetadata: true})
+	assert.True(t, repo.SignMetadata)
+
+	_, err := env.repositories.Create(ctx, &dto.CreateRepositoryRequest{Name: "libs-group", Type: model.RepositoryTypeGroup, Format: "maven",
+		Members: []string{"libs"}, SignMetadata: true})
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+
+	updated, err := env.repositories.Update(ctx, "signed", &dto.UpdateRepositoryRequest{})
+	require.NoError(t, err)
+	assert.False(t, updated.SignMetadata)
+}
@@ -0,0 +1,253 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// 暂存仓库关闭时可执行的校验规则
+const (
+	stagingRulePOM        = "pom"
+	stagingRuleChecksums  = "checksums"
+	stagingRuleSignatures = "signatures"
+)
+
+// defaultStagingRules 目标仓库未配置 staging_rules 时执行的规则
+var defaultStagingRules = []string{stagingRulePOM, stagingRuleChecksums, stagingRuleSignatures}
+
+// pgpSignatureHeader ASCII 封装的 PGP 签名起始行
+const pgpSignatureHeader = "-----BEGIN PGP SIGNATURE-----"
+
+// stagingContents 待校验的暂存仓库内容
+type stagingContents struct {
+	repo    *model.Repository
+	byPath  map[string]*model.Artifact
+	primary []*model.Artifact // 制品主文件，不含校验和、签名与元数据文件
+}
+
+// stagingPOM 校验 POM 完整性所需的字段
+type stagingPOM struct {
+	XMLName     xml.Name `xml:"project"`
+	GroupID     string   `xml:"groupId"`
+	ArtifactID  string   `xml:"artifactId"`
+	Version     string   `xml:"version"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"description"`
+	URL         string   `xml:"url"`
+	Parent      struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Licenses   []struct{} `xml:"licenses>license"`
+	Developers []struct{} `xml:"developers>developer"`
+	SCM        struct {
+		URL string `xml:"url"`
+	} `xml:"scm"`
+}
+
+// stagingRules 读取目标仓库配置的校验规则，配置为逗号分隔的规则名称
+func stagingRules(target *model.Repository) ([]string, error) {
+	raw, ok := target.Config["staging_rules"]
+	if !ok {
+		return defaultStagingRules, nil
+	}
+	var rules []string
+	for _, rule := range strings.Split(raw, ",") {
+		rule = strings.TrimSpace(rule)
+		switch rule {
+		case "":
+			continue
+		case stagingRulePOM, stagingRuleChecksums, stagingRuleSignatures:
+			rules = append(rules, rule)
+		default:
+			return nil, fmt.Errorf("unknown staging rule %q", rule)
+		}
+	}
+	return rules, nil
+}
+
+// collectStagingContents 区分暂存仓库中的制品主文件与附属文件
+func collectStagingContents(repo *model.Repository, formatPlugin pluginapi.FormatPlugin, artifacts []*model.Artifact) *stagingContents {
+	contents := &stagingContents{repo: repo, byPath: make(map[string]*model.Artifact, len(artifacts))}
+	for _, artifact := range artifacts {
+		contents.byPath[artifact.Path] = artifact
+		if !isPrimaryFile(formatPlugin, artifact.Path) {
+			continue
+		}
+		contents.primary = append(contents.primary, artifact)
+	}
+	return contents
+}
+
+// checkPOM 每个 Maven 版本目录都需要 POM，POM 需包含坐标、名称、描述、项目地址、许可证、开发者与 SCM 信息
+func (s *StagingServiceImpl) checkPOM(ctx context.Context, contents *stagingContents) []string {
+	if contents.repo.Format != "maven" {
+		return nil
+	}
+	var failures []string
+	versionDirs := make(map[string]bool)
+	for _, artifact := range contents.primary {
+		dir := path.Dir(artifact.Path)
+		if !strings.HasSuffix(artifact.Path, ".pom") {
+			if _, ok := versionDirs[dir]; !ok {
+				versionDirs[dir] = false
+			}
+			continue
+		}
+		versionDirs[dir] = true
+
+		data, err := s.artifacts.storage.Download(ctx, storagePath(contents.repo, artifact.Path))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("pom: failed to read %s: %v", artifact.Path, err))
+			continue
+		}
+		var pom stagingPOM
+		if err := xml.Unmarshal(data, &pom); err != nil {
+			failures = append(failures, fmt.Sprintf("pom: %s is not a valid POM: %v", artifact.Path, err))
+			continue
+		}
+		var missing []string
+		for _, field := range []struct {
+			element string
+			present bool
+		}{
+			{"groupId", pom.GroupID != "" || pom.Parent.GroupID != ""},
+			{"artifactId", pom.ArtifactID != ""},
+			{"version", pom.Version != "" || pom.Parent.Version != ""},
+			{"name", strings.TrimSpace(pom.Name) != ""},
+			{"description", strings.TrimSpace(pom.Description) != ""},
+			{"url", strings.TrimSpace(pom.URL) != ""},
+			{"licenses", len(pom.Licenses) > 0},
+			{"developers", len(pom.Developers) > 0},
+			{"scm", strings.TrimSpace(pom.SCM.URL) != ""},
+		} {
+			if !field.present {
+				missing = append(missing, "<"+field.element+">")
+			}
+		}
+		if len(missing) > 0 {
+			failures = append(failures, fmt.Sprintf("pom: %s is missing %s", artifact.Path, strings.Join(missing, ", ")))
+		}
+	}
+
+	for _, dir := range sortedKeys(versionDirs) {
+		if !versionDirs[dir] {
+			failures = append(failures, fmt.Sprintf("pom: no POM found in %s", dir))
+		}
+	}
+	return failures
+}
+
+// checkChecksums 每个主文件至少需要一个校验和文件，且所有校验和文件都与内容一致
+func (s *StagingServiceImpl) checkChecksums(ctx context.Context, contents *stagingContents) []string {
+	var failures []string
+	for _, artifact := range contents.primary {
+		var data []byte
+		found := false
+		for _, algorithm := range sortedKeys(checksumAlgorithms) {
+			sidecar, ok := contents.byPath[artifact.Path+"."+algorithm]
+			if !ok {
+				continue
+			}
+			found = true
+			if data == nil {
+				var err error
+				if data, err = s.artifacts.storage.Download(ctx, storagePath(contents.repo, artifact.Path)); err != nil {
+					failures = append(failures, fmt.Sprintf("checksums: failed to read %s: %v", artifact.Path, err))
+					break
+				}
+			}
+			expected, err := s.artifacts.storage.Download(ctx, storagePath(contents.repo, sidecar.Path))
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("checksums: failed to read %s: %v", sidecar.Path, err))
+				continue
+			}
+			h := checksumAlgorithms[algorithm]()
+			h.Write(data)
+			fields := strings.Fields(string(expected))
+			if len(fields) == 0 || !strings.EqualFold(fields[0], hex.EncodeToString(h.Sum(nil))) {
+				failures = append(failures, fmt.Sprintf("checksums: %s does not match %s", sidecar.Path, artifact.Path))
+			}
+		}
+		if !found {
+			failures = append(failures, fmt.Sprintf("checksums: no checksum file for %s", artifact.Path))
+		}
+	}
+	return failures
+}
+
+// checkSignatures 每个主文件都需要签名文件；目标仓库配置了受信任公钥时校验签名，
+// 否则只要求存在 ASCII 封装的 PGP 签名文件 .asc
+func (s *StagingServiceImpl) checkSignatures(ctx context.Context, contents *stagingContents, target *model.Repository) []string {
+	keys, err := s.artifacts.signatureKeys(ctx, target)
+	if err != nil {
+		return []string{fmt.Sprintf("signatures: %v", err)}
+	}
+	var failures []string
+	for _, artifact := range contents.primary {
+		if len(keys) > 0 {
+			if err := s.artifacts.checkSignature(ctx, contents.repo, keys, artifact.Path); err != nil {
+				failures = append(failures, fmt.Sprintf("signatures: %v", err))
+			}
+			continue
+		}
+		signature, ok := contents.byPath[artifact.Path+".asc"]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("signatures: no signature file for %s", artifact.Path))
+			continue
+		}
+		data, err := s.artifacts.storage.Download(ctx, storagePath(contents.repo, signature.Path))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("signatures: failed to read %s: %v", signature.Path, err))
+			continue
+		}
+		if !bytes.Contains(data, []byte(pgpSignatureHeader)) {
+			failures = append(failures, fmt.Sprintf("signatures: %s is not an armored PGP signature", signature.Path))
+		}
+	}
+	return failures
+}
+
+// isSidecarPath 判断路径是否为校验和或签名附属文件
+func isSidecarPath(p string) bool {
+	if _, _, ok := signatureTarget(p); ok {
+		return true
+	}
+	for algorithm := range checksumAlgorithms {
+		if strings.HasSuffix(p, "."+algorithm) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMetadataFile 判断路径是否为格式元数据文件或其附属文件，这些文件由仓库重新生成
+func isMetadataFile(formatPlugin pluginapi.FormatPlugin, p string) bool {
+	merger, ok := formatPlugin.(pluginapi.MetadataMerger)
+	if !ok {
+		return false
+	}
+	for isSidecarPath(p) {
+		p = strings.TrimSuffix(p, path.Ext(p))
+	}
+	return merger.IsMetadataPath(p)
+}
+
+// sortedKeys 按字典序返回映射的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
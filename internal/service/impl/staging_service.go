@@ -0,0 +1,387 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// maxDeployAttempts 暂存仓库在部署期间被关闭或丢弃时，最多尝试的部署次数
+const maxDeployAttempts = 3
+
+// StagingServiceImpl 暂存仓库服务实现
+type StagingServiceImpl struct {
+	logger    *slog.Logger
+	artifacts *ArtifactServiceImpl
+	promotion *PromotionServiceImpl
+	audit     *AuditRecorder
+
+	// 串行化暂存仓库的创建与状态变更，避免并发上传各自创建暂存仓库或同时关闭、发布
+	mu sync.Mutex
+}
+
+// NewStagingService 创建新的暂存仓库服务实现
+func NewStagingService(logger *slog.Logger, artifacts *ArtifactServiceImpl, promotion *PromotionServiceImpl, audit *AuditRecorder) *StagingServiceImpl {
+	return &StagingServiceImpl{
+		logger:    logger,
+		artifacts: artifacts,
+		promotion: promotion,
+		audit:     audit,
+	}
+}
+
+// Start 为目标宿主仓库创建新的暂存仓库
+func (s *StagingServiceImpl) Start(ctx context.Context, req *dto.StartStagingRequest) (*model.Repository, error) {
+	target, err := s.lookupTarget(ctx, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(ctx, target, req.Description)
+}
+
+// Deploy 上传制品到目标仓库最近创建的打开状态暂存仓库，没有时自动创建
+// 上传持有暂存仓库的读锁并在持锁后重新检查状态；选中的暂存仓库在上传开始前被关闭或丢弃时，改用新的打开状态暂存仓库
+func (s *StagingServiceImpl) Deploy(ctx context.Context, targetIDOrName string, req *dto.UploadArtifactRequest) (*model.Artifact, error) {
+	target, err := s.lookupTarget(ctx, targetIDOrName)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		s.mu.Lock()
+		staging, err := s.findOpen(ctx, target)
+		if err == nil && staging == nil {
+			staging, err = s.create(ctx, target, "")
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		artifact, err := s.artifacts.Upload(ctx, staging.ID, req)
+		if err == nil || attempt == maxDeployAttempts {
+			return artifact, err
+		}
+		current, findErr := s.artifacts.repositories.FindByID(ctx, staging.ID)
+		if findErr != nil || current == nil || current.StagingStatus == model.StagingStatusOpen {
+			return nil, err
+		}
+		s.logger.Info("Staging repository changed state during deploy, retrying", "repository", staging.Name, "status", current.StagingStatus)
+	}
+}
+
+// List 列出暂存仓库，按创建时间倒序排列
+func (s *StagingServiceImpl) List(ctx context.Context, query *dto.ListStagingQuery) ([]*model.Repository, error) {
+	repos, err := s.artifacts.repositories.List(ctx, model.RepositoryTypeHosted, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	result := make([]*model.Repository, 0)
+	for _, repo := range repos {
+		switch {
+		case repo.StagingStatus == "":
+		case query.Status != "" && repo.StagingStatus != query.Status:
+		case query.Target != "" && repo.StagingTarget != query.Target:
+		default:
+			result = append(result, repo)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result, nil
+}
+
+// Get 获取暂存仓库
+func (s *StagingServiceImpl) Get(ctx context.Context, idOrName string) (*model.Repository, error) {
+	return s.lookupStaging(ctx, idOrName)
+}
+
+// Close 执行目标仓库配置的校验规则，全部通过后关闭暂存仓库；未通过时仓库保持打开并记录失败原因
+func (s *StagingServiceImpl) Close(ctx context.Context, idOrName string) (*model.Repository, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staging, err := s.lookupStaging(ctx, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	defer s.artifacts.lockStaging(staging.ID)()
+	if staging.StagingStatus != model.StagingStatusOpen {
+		return nil, errs.Conflict("staging repository %q is %s, only open repositories can be closed", staging.Name, staging.StagingStatus)
+	}
+	target, err := s.lookupTarget(ctx, staging.StagingTarget)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := stagingRules(target)
+	if err != nil {
+		return nil, errs.InvalidArgument("target repository %q: %s", target.Name, err.Error())
+	}
+
+	formatPlugin, err := s.artifacts.plugins.GetFormatPlugin(staging.Format)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := s.listArtifacts(ctx, staging)
+	if err != nil {
+		return nil, err
+	}
+	contents := collectStagingContents(staging, formatPlugin, artifacts)
+
+	failures := make([]string, 0)
+	if len(contents.primary) == 0 {
+		failures = append(failures, "staging repository contains no artifacts")
+	} else {
+		for _, rule := range rules {
+			switch rule {
+			case stagingRulePOM:
+				failures = append(failures, s.checkPOM(ctx, contents)...)
+			case stagingRuleChecksums:
+				failures = append(failures, s.checkChecksums(ctx, contents)...)
+			case stagingRuleSignatures:
+				failures = append(failures, s.checkSignatures(ctx, contents, target)...)
+			}
+		}
+	}
+
+	staging.StagingFailures = failures
+	if len(failures) == 0 {
+		staging.StagingStatus = model.StagingStatusClosed
+	}
+	if err := s.artifacts.repositories.Update(ctx, staging); err != nil {
+		return nil, fmt.Errorf("failed to update staging repository: %w", err)
+	}
+	s.audit.Record(ctx, auditActionStagingClose, "repository:"+staging.Name, map[string]interface{}{
+		"target":   target.Name,
+		"rules":    rules,
+		"failures": failures,
+	})
+
+	if len(failures) > 0 {
+		s.logger.Info("Staging repository failed validation", "repository", staging.Name, "failures", len(failures))
+		return nil, errs.InvalidArgument("staging repository %q failed validation: %s", staging.Name, strings.Join(failures, "; "))
+	}
+	s.logger.Info("Staging repository closed", "repository", staging.Name, "target", target.Name)
+	return staging, nil
+}
+
+// Release 将已关闭的暂存仓库中的制品移动到目标仓库，目标仓库已有同路径制品时不发布任何内容
+func (s *StagingServiceImpl) Release(ctx context.Context, idOrName string) (*dto.StagingReleaseResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staging, err := s.lookupStaging(ctx, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	defer s.artifacts.lockStaging(staging.ID)()
+	if staging.StagingStatus != model.StagingStatusClosed {
+		return nil, errs.Conflict("staging repository %q is %s, only closed repositories can be released", staging.Name, staging.StagingStatus)
+	}
+	_, target, err := s.promotion.resolveRepositories(ctx, staging.ID, staging.StagingTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	formatPlugin, err := s.artifacts.plugins.GetFormatPlugin(staging.Format)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := s.listArtifacts(ctx, staging)
+	if err != nil {
+		return nil, err
+	}
+	// 元数据文件由目标仓库重新生成，不随制品发布
+	candidates := make([]*model.Artifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if !isMetadataFile(formatPlugin, artifact.Path) {
+			candidates = append(candidates, artifact)
+		}
+	}
+
+	promoted, err := s.promotion.promote(ctx, staging, target, candidates, true, false)
+	if err != nil {
+		return nil, err
+	}
+	// 部分文件未能发布时保持关闭状态，未发布的文件留在暂存仓库中，可以再次发布
+	if len(promoted.Failed) > 0 {
+		return nil, fmt.Errorf("failed to release %d of %d artifacts from staging repository %q, first error: %s",
+			len(promoted.Failed), len(candidates), staging.Name, promoted.Failed[0].Error)
+	}
+	if err := s.artifacts.purge(ctx, staging); err != nil {
+		return nil, err
+	}
+
+	staging.StagingStatus = model.StagingStatusReleased
+	if err := s.artifacts.repositories.Update(ctx, staging); err != nil {
+		return nil, fmt.Errorf("failed to update staging repository: %w", err)
+	}
+	s.audit.Record(ctx, auditActionStagingRelease, "repository:"+staging.Name, map[string]interface{}{
+		"target": target.Name,
+		"count":  len(promoted.Artifacts),
+	})
+	s.logger.Info("Staging repository released", "repository", staging.Name, "target", target.Name, "count", len(promoted.Artifacts))
+	return &dto.StagingReleaseResult{Repository: staging, Artifacts: promoted.Artifacts}, nil
+}
+
+// Drop 删除暂存仓库中的所有内容，仓库记录保留用于追溯
+func (s *StagingServiceImpl) Drop(ctx context.Context, idOrName string) (*model.Repository, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staging, err := s.lookupStaging(ctx, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	defer s.artifacts.lockStaging(staging.ID)()
+	if staging.StagingStatus != model.StagingStatusOpen && staging.StagingStatus != model.StagingStatusClosed {
+		return nil, errs.Conflict("staging repository %q is already %s", staging.Name, staging.StagingStatus)
+	}
+	if err := s.artifacts.purge(ctx, staging); err != nil {
+		return nil, err
+	}
+
+	staging.StagingStatus = model.StagingStatusDropped
+	if err := s.artifacts.repositories.Update(ctx, staging); err != nil {
+		return nil, fmt.Errorf("failed to update staging repository: %w", err)
+	}
+	s.audit.Record(ctx, auditActionStagingDrop, "repository:"+staging.Name, map[string]interface{}{
+		"target": staging.StagingTarget,
+	})
+	s.logger.Info("Staging repository dropped", "repository", staging.Name)
+	return staging, nil
+}
+
+// create 创建打开状态的暂存仓库，沿用目标仓库的格式与路由规则，调用方需持有锁
+func (s *StagingServiceImpl) create(ctx context.Context, target *model.Repository, description string) (*model.Repository, error) {
+	id := uuid.New().String()
+	if description == "" {
+		description = fmt.Sprintf("Staging repository for %s", target.Name)
+	}
+	staging := &model.Repository{
+		ID:            id,
+		Name:          fmt.Sprintf("%s-staging-%s", target.Name, id[:8]),
+		Type:          model.RepositoryTypeHosted,
+		Format:        target.Format,
+		Description:   description,
+		Routing:       target.Routing,
+		Status:        "active",
+		StagingStatus: model.StagingStatusOpen,
+		StagingTarget: target.Name,
+	}
+	if err := s.artifacts.repositories.Create(ctx, staging); err != nil {
+		return nil, fmt.Errorf("failed to create staging repository: %w", err)
+	}
+	s.logger.Info("Staging repository created", "repository", staging.Name, "target", target.Name)
+	return staging, nil
+}
+
+// findOpen 查找目标仓库最近创建的打开状态暂存仓库，没有时返回 nil
+func (s *StagingServiceImpl) findOpen(ctx context.Context, target *model.Repository) (*model.Repository, error) {
+	open, err := s.List(ctx, &dto.ListStagingQuery{Target: target.Name, Status: model.StagingStatusOpen})
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range open {
+		if repo.Status != "inactive" {
+			return repo, nil
+		}
+	}
+	return nil, nil
+}
+
+// lookupStaging 查找暂存仓库，普通仓库视为不存在
+func (s *StagingServiceImpl) lookupStaging(ctx context.Context, idOrName string) (*model.Repository, error) {
+	repo, err := lookupRepository(ctx, s.artifacts.repositories, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	if repo.StagingStatus == "" {
+		return nil, errs.NotFound("staging repository %q not found", idOrName)
+	}
+	return repo, nil
+}
+
+// lookupTarget 查找暂存仓库的目标仓库，必须是启用的普通宿主仓库
+func (s *StagingServiceImpl) lookupTarget(ctx context.Context, idOrName string) (*model.Repository, error) {
+	target, err := lookupRepository(ctx, s.artifacts.repositories, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case target.Type != model.RepositoryTypeHosted:
+		return nil, errs.InvalidArgument("repository %q is a %s repository, only hosted repositories can be staging targets", target.Name, target.Type)
+	case target.StagingStatus != "":
+		return nil, errs.InvalidArgument("repository %q is a staging repository", target.Name)
+	case target.Status == "inactive":
+		return nil, errs.InvalidArgument("repository %q is inactive", target.Name)
+	}
+	return target, nil
+}
+
+// listArtifacts 列出暂存仓库中的所有制品记录
+func (s *StagingServiceImpl) listArtifacts(ctx context.Context, staging *model.Repository) ([]*model.Artifact, error) {
+	artifacts, err := s.artifacts.repository.List(ctx, &model.ArtifactFilter{
+		RepositoryIDs: []string{staging.ID},
+		Sort:          model.ArtifactSortCreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	return artifacts, nil
+}
+
+// lockStagingWrite 向暂存仓库写入前获取其读锁，持锁后重新读取状态，仍为打开状态时才返回释放函数
+// 关闭、发布与丢弃持有写锁，因此会等待进行中的写入完成，写入期间状态也不会改变；普通仓库不加锁
+func (s *ArtifactServiceImpl) lockStagingWrite(ctx context.Context, repo *model.Repository) (func(), error) {
+	if repo.StagingStatus == "" {
+		return func() {}, nil
+	}
+	mutex := s.stagingLock(repo.ID)
+	mutex.RLock()
+	current, err := s.repositories.FindByID(ctx, repo.ID)
+	if err != nil {
+		mutex.RUnlock()
+		return nil, fmt.Errorf("failed to find repository: %w", err)
+	}
+	if current == nil {
+		mutex.RUnlock()
+		return nil, errs.NotFound("repository %q not found", repo.Name)
+	}
+	if err := checkStagingOpen(current); err != nil {
+		mutex.RUnlock()
+		return nil, err
+	}
+	return mutex.RUnlock, nil
+}
+
+// lockStaging 获取暂存仓库的写锁，等待进行中的写入完成，返回释放函数
+func (s *ArtifactServiceImpl) lockStaging(repoID string) func() {
+	mutex := s.stagingLock(repoID)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// stagingLock 返回暂存仓库的读写锁
+func (s *ArtifactServiceImpl) stagingLock(repoID string) *sync.RWMutex {
+	value, _ := s.stagingLocks.LoadOrStore(repoID, &sync.RWMutex{})
+	return value.(*sync.RWMutex)
+}
+
+// checkStagingOpen 暂存仓库只有打开状态时才接受写入
+func checkStagingOpen(repo *model.Repository) error {
+	if repo.StagingStatus != "" && repo.StagingStatus != model.StagingStatusOpen {
+		return errs.InvalidArgument("staging repository %q is %s and does not accept uploads", repo.Name, repo.StagingStatus)
+	}
+	return nil
+}
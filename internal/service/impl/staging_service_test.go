@@ -0,0 +1,267 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// blockingStorage 写入以 suffix 结尾的文件时通知 entered 并等待 release 关闭
+type blockingStorage struct {
+	pluginapi.StoragePlugin
+	suffix   string
+	entered  chan struct{}
+	released chan struct{}
+}
+
+func (s *blockingStorage) Upload(ctx context.Context, path string, data []byte) error {
+	if strings.HasSuffix(path, s.suffix) {
+		s.entered <- struct{}{}
+		<-s.released
+	}
+	return s.StoragePlugin.Upload(ctx, path, data)
+}
+
+// newStagingEnv 创建只校验校验和文件的目标仓库 libs-release
+func newStagingEnv(t *testing.T) (*testEnv, *StagingServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "libs-release", Type: model.RepositoryTypeHosted, Format: "maven",
+		Config: map[string]string{"staging_rules": "checksums"}})
+	promotion := NewPromotionService(env.logger, env.artifacts, env.audit, env.authorizer)
+	return env, NewStagingService(env.logger, env.artifacts, promotion, env.audit)
+}
+
+// deployChecked 部署 jar 及其 sha1 校验和文件
+func deployChecked(t *testing.T, staging *StagingServiceImpl, p string) *model.Artifact {
+	t.Helper()
+	ctx := context.Background()
+	artifact, err := staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: p, Data: []byte(p)})
+	require.NoError(t, err)
+	_, err = staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: p + ".sha1", Data: []byte(sha1Hex([]byte(p)))})
+	require.NoError(t, err)
+	return artifact
+}
+
+func TestStagingService_Lifecycle(t *testing.T) {
+	env, staging := newStagingEnv(t)
+	ctx := context.Background()
+
+	first := deployChecked(t, staging, "com/x/lib/1.0/lib-1.0.jar")
+	second := deployChecked(t, staging, "com/x/lib/1.0/lib-1.0-sources.jar")
+	assert.Equal(t, first.RepositoryID, second.RepositoryID, "deploys share the open staging repository")
+
+	repo, err := staging.Get(ctx, first.RepositoryID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusOpen, repo.StagingStatus)
+	assert.Equal(t, "libs-release", repo.StagingTarget)
+	assert.True(t, strings.HasPrefix(repo.Name, "libs-release-staging-"))
+
+	closed, err := staging.Close(ctx, repo.Name)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusClosed, closed.StagingStatus)
+
+	t.Run("closed_rejects_uploads", func(t *testing.T) {
+		_, err := env.artifacts.Upload(ctx, repo.ID, &dto.UploadArtifactRequest{Path: "com/x/lib/1.0/extra.jar", Data: []byte("x")})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	t.Run("deploy_opens_new_repository", func(t *testing.T) {
+		artifact, err := staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar", Data: []byte("2.0")})
+		require.NoError(t, err)
+		assert.NotEqual(t, repo.ID, artifact.RepositoryID)
+	})
+
+	released, err := staging.Release(ctx, repo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusReleased, released.Repository.StagingStatus)
+	assert.Len(t, released.Artifacts, 4)
+
+	data, err := env.download("libs-release", "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, "com/x/lib/1.0/lib-1.0.jar", string(data))
+	files, err := env.storage.List(ctx, storagePath(repo, ""))
+	require.NoError(t, err)
+	assert.Empty(t, files, "released staging repository is purged")
+
+	t.Run("state_conflicts", func(t *testing.T) {
+		_, err := staging.Close(ctx, repo.ID)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		_, err = staging.Release(ctx, repo.ID)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		_, err = staging.Drop(ctx, repo.ID)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		all, err := staging.List(ctx, &dto.ListStagingQuery{Target: "libs-release"})
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, model.StagingStatusOpen, all[0].StagingStatus, "newest first")
+
+		releasedOnly, err := staging.List(ctx, &dto.ListStagingQuery{Status: model.StagingStatusReleased})
+		require.NoError(t, err)
+		require.Len(t, releasedOnly, 1)
+		assert.Equal(t, repo.ID, releasedOnly[0].ID)
+	})
+}
+
+func TestStagingService_CloseValidation(t *testing.T) {
+	env, staging := newStagingEnv(t)
+	ctx := context.Background()
+
+	empty, err := staging.Start(ctx, &dto.StartStagingRequest{Target: "libs-release"})
+	require.NoError(t, err)
+	_, err = staging.Close(ctx, empty.ID)
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+
+	artifact, err := staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: "com/x/lib/1.0/lib-1.0.jar", Data: []byte("jar")})
+	require.NoError(t, err)
+	_, err = staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: "com/x/lib/1.0/lib-1.0.jar.md5", Data: []byte("0000")})
+	require.NoError(t, err)
+
+	_, err = staging.Close(ctx, artifact.RepositoryID)
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	repo, err := staging.Get(ctx, artifact.RepositoryID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusOpen, repo.StagingStatus)
+	assert.Equal(t, []string{"checksums: com/x/lib/1.0/lib-1.0.jar.md5 does not match com/x/lib/1.0/lib-1.0.jar"}, repo.StagingFailures)
+
+	page, err := env.audit.List(ctx, &dto.ListAuditQuery{Action: auditActionStagingClose})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+
+	dropped, err := staging.Drop(ctx, repo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusDropped, dropped.StagingStatus)
+	_, err = env.download(repo.Name, "com/x/lib/1.0/lib-1.0.jar")
+	assert.Error(t, err)
+}
+
+func TestStagingService_Rejects(t *testing.T) {
+	env, staging := newStagingEnv(t)
+	ctx := context.Background()
+	env.hosted(t, "plain", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "central", Type: model.RepositoryTypeProxy, Format: "maven", URL: "http://127.0.0.1:1/"})
+	open, err := staging.Start(ctx, &dto.StartStagingRequest{Target: "libs-release"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{name: "proxy_target", call: func() error {
+			_, err := staging.Start(ctx, &dto.StartStagingRequest{Target: "central"})
+			return err
+		}, want: errs.ErrInvalidArgument},
+		{name: "staging_target", call: func() error {
+			_, err := staging.Deploy(ctx, open.Name, &dto.UploadArtifactRequest{Path: "a/b/1/b-1.jar", Data: []byte("x")})
+			return err
+		}, want: errs.ErrInvalidArgument},
+		{name: "missing_target", call: func() error {
+			_, err := staging.Start(ctx, &dto.StartStagingRequest{Target: "nope"})
+			return err
+		}, want: errs.ErrNotFound},
+		{name: "not_a_staging_repository", call: func() error { _, err := staging.Get(ctx, "plain"); return err }, want: errs.ErrNotFound},
+		{name: "release_open", call: func() error { _, err := staging.Release(ctx, open.ID); return err }, want: errs.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), tt.want)
+		})
+	}
+}
+
+// 发布时部分文件失败，暂存仓库保持关闭，未发布的文件可以再次发布
+func TestStagingService_ReleasePartialFailure(t *testing.T) {
+	env, staging := newStagingEnv(t)
+	ctx := context.Background()
+	artifact := deployChecked(t, staging, "com/x/lib/1.0/lib-1.0.jar")
+	_, err := staging.Close(ctx, artifact.RepositoryID)
+	require.NoError(t, err)
+
+	store := env.artifacts.storage
+	env.artifacts.storage = failingStorage{plainStorage: plainStorage{store}, suffix: ".sha1"}
+	_, err = staging.Release(ctx, artifact.RepositoryID)
+	require.Error(t, err)
+	repo, err := staging.Get(ctx, artifact.RepositoryID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusClosed, repo.StagingStatus)
+
+	env.artifacts.storage = store
+	released, err := staging.Release(ctx, artifact.RepositoryID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StagingStatusReleased, released.Repository.StagingStatus)
+	_, err = env.download("libs-release", "com/x/lib/1.0/lib-1.0.jar.sha1")
+	assert.NoError(t, err)
+}
+
+// 关闭需要等待进行中的部署完成，部署的文件要么参与校验，要么进入新的暂存仓库
+func TestStagingService_CloseWaitsForDeploy(t *testing.T) {
+	env, staging := newStagingEnv(t)
+	ctx := context.Background()
+	first := deployChecked(t, staging, "com/x/lib/1.0/lib-1.0.jar")
+
+	store := &blockingStorage{StoragePlugin: env.artifacts.storage, suffix: "lib-1.0-extra.jar", entered: make(chan struct{}), released: make(chan struct{})}
+	env.artifacts.storage = store
+	deployed := make(chan error, 1)
+	go func() {
+		_, err := staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: "com/x/lib/1.0/lib-1.0-extra.jar", Data: []byte("extra")})
+		deployed <- err
+	}()
+	<-store.entered
+
+	closed := make(chan error, 1)
+	go func() {
+		_, err := staging.Close(ctx, first.RepositoryID)
+		closed <- err
+	}()
+	select {
+	case err := <-closed:
+		t.Fatalf("close finished during an upload: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(store.released)
+	require.NoError(t, <-deployed)
+	// 上传的 jar 没有校验和文件，关闭校验失败，说明校验包含了刚上传的文件
+	err := <-closed
+	require.ErrorIs(t, err, errs.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "no checksum file for com/x/lib/1.0/lib-1.0-extra.jar")
+}
+
+// 选中的暂存仓库在上传开始前被关闭时，部署改用新的暂存仓库
+func TestStagingService_DeployRetriesAfterClose(t *testing.T) {
+	env, staging := newStagingEnv(t)
+	ctx := context.Background()
+	first := deployChecked(t, staging, "com/x/lib/1.0/lib-1.0.jar")
+
+	unlock := env.artifacts.lockStaging(first.RepositoryID)
+	deployed := make(chan *model.Artifact, 1)
+	go func() {
+		artifact, err := staging.Deploy(ctx, "libs-release", &dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar", Data: []byte("2.0")})
+		assert.NoError(t, err)
+		deployed <- artifact
+	}()
+	// 持有写锁期间直接关闭，模拟部署选中仓库之后、上传之前发生的关闭
+	repo, err := staging.Get(ctx, first.RepositoryID)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	repo.StagingStatus = model.StagingStatusClosed
+	require.NoError(t, env.repos.Update(ctx, repo))
+	unlock()
+
+	artifact := <-deployed
+	require.NotNil(t, artifact)
+	assert.NotEqual(t, first.RepositoryID, artifact.RepositoryID)
+}
@@ -0,0 +1,540 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	// taskRunHistory 每个任务保留的执行记录数量
+	taskRunHistory = 50
+	// defaultTaskRunLimit 查询执行记录时的默认数量
+	defaultTaskRunLimit = 20
+	// defaultCleanupTaskName 启用清理配置时自动创建的任务名称
+	defaultCleanupTaskName = "cleanup"
+)
+
+// taskNamePattern 任务名称格式
+var taskNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// taskExecution 正在进行的一次任务执行
+type taskExecution struct {
+	run    *model.TaskRun
+	log    *taskLog
+	cancel context.CancelFunc
+}
+
+// TaskServiceImpl 后台任务服务实现
+// 任务定义与执行记录保存在数据库中，同一任务同一时间只允许一次执行
+type TaskServiceImpl struct {
+	logger *slog.Logger
+	tasks  repository.TaskRepository
+	runs   repository.TaskRunRepository
+	types  map[string]*taskType
+	cfg    config.CleanupConfig
+
+	cron    *cron.Cron
+	ctx     context.Context // 所有执行的父 context，服务停止时取消
+	stop    context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	entries map[string]cron.EntryID   // 任务ID -> 调度项
+	running map[string]*taskExecution // 任务ID -> 正在进行的执行
+}
+
+// NewTaskService 创建后台任务服务，返回的函数停止调度、取消正在进行的执行并等待其结束
+func NewTaskService(
+	cfg *config.Config,
+	logger *slog.Logger,
+	tasks repository.TaskRepository,
+	runs repository.TaskRunRepository,
+	cleanup *CleanupServiceImpl,
+	artifacts *ArtifactServiceImpl,
+	uploads *UploadServiceImpl,
+) (*TaskServiceImpl, func()) {
+	ctx, stop := context.WithCancel(context.Background())
+	s := &TaskServiceImpl{
+		logger:  logger,
+		tasks:   tasks,
+		runs:    runs,
+		cfg:     cfg.Cleanup,
+		cron:    cron.New(),
+		ctx:     ctx,
+		stop:    stop,
+		entries: make(map[string]cron.EntryID),
+		running: make(map[string]*taskExecution),
+	}
+	s.registerTaskTypes(cleanup, artifacts, uploads)
+
+	return s, func() {
+		<-s.cron.Stop().Done()
+		s.stop()
+		s.wg.Wait()
+	}
+}
+
+// Start 将上次未正常结束的执行标记为失败，创建默认任务并开始调度已启用的任务
+func (s *TaskServiceImpl) Start(ctx context.Context) error {
+	interrupted, err := s.runs.FailRunning(ctx, "interrupted by server restart", time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to reset interrupted task runs: %w", err)
+	}
+	if interrupted > 0 {
+		s.logger.Warn("Marked interrupted task runs as failed", "count", interrupted)
+	}
+	if err := s.seedDefaults(ctx); err != nil {
+		return err
+	}
+
+	tasks, err := s.tasks.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	s.mu.Lock()
+	for _, task := range tasks {
+		if err := s.schedule(task); err != nil {
+			s.logger.Error("Failed to schedule task", "task", task.Name, "cron", task.Cron, "error", err)
+		}
+	}
+	s.mu.Unlock()
+	s.cron.Start()
+	s.logger.Info("Task scheduler started", "tasks", len(tasks), "scheduled", len(s.entries))
+	return nil
+}
+
+// Create 创建任务
+func (s *TaskServiceImpl) Create(ctx context.Context, req *dto.CreateTaskRequest) (*model.Task, error) {
+	if !taskNamePattern.MatchString(req.Name) {
+		return nil, errs.InvalidArgument("invalid task name %q", req.Name)
+	}
+	existing, err := s.tasks.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("task %q already exists", req.Name)
+	}
+
+	task := &model.Task{
+		ID:      uuid.New().String(),
+		Name:    req.Name,
+		Type:    req.Type,
+		Cron:    req.Cron,
+		Params:  req.Params,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	if err := s.validate(task); err != nil {
+		return nil, err
+	}
+	if err := s.tasks.Create(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.schedule(task); err != nil {
+		return nil, err
+	}
+	s.decorate(task)
+	s.logger.Info("Task created", "name", task.Name, "type", task.Type, "cron", task.Cron)
+	return task, nil
+}
+
+// Get 获取任务
+func (s *TaskServiceImpl) Get(ctx context.Context, id string) (*model.Task, error) {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decorate(task)
+	return task, nil
+}
+
+// List 列出所有任务
+func (s *TaskServiceImpl) List(ctx context.Context) ([]*model.Task, error) {
+	tasks, err := s.tasks.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, task := range tasks {
+		s.decorate(task)
+	}
+	return tasks, nil
+}
+
+// Update 更新任务并重新调度
+func (s *TaskServiceImpl) Update(ctx context.Context, id string, req *dto.UpdateTaskRequest) (*model.Task, error) {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Cron != nil {
+		task.Cron = *req.Cron
+	}
+	if req.Params != nil {
+		task.Params = req.Params
+	}
+	if req.Enabled != nil {
+		task.Enabled = *req.Enabled
+	}
+	if err := s.validate(task); err != nil {
+		return nil, err
+	}
+	if err := s.tasks.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.schedule(task); err != nil {
+		return nil, err
+	}
+	s.decorate(task)
+	s.logger.Info("Task updated", "name", task.Name, "cron", task.Cron, "enabled", task.Enabled)
+	return task, nil
+}
+
+// Delete 删除任务及其执行记录，任务正在执行时返回冲突
+func (s *TaskServiceImpl) Delete(ctx context.Context, id string) error {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.running[task.ID]; ok {
+		return errs.Conflict("task %q is running", task.Name)
+	}
+	s.unschedule(task.ID)
+	if err := s.runs.DeleteByTask(ctx, task.ID); err != nil {
+		return fmt.Errorf("failed to delete task runs: %w", err)
+	}
+	if err := s.tasks.Delete(ctx, task.ID); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	s.logger.Info("Task deleted", "name", task.Name)
+	return nil
+}
+
+// RunNow 立即在后台执行任务，不受启用状态影响
+func (s *TaskServiceImpl) RunNow(ctx context.Context, id string) (*model.TaskRun, error) {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(task, model.TaskTriggerManual)
+}
+
+// Cancel 取消任务正在进行的执行，执行函数在 ctx 取消后结束
+func (s *TaskServiceImpl) Cancel(ctx context.Context, id string) (*model.TaskRun, error) {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	execution, ok := s.running[task.ID]
+	if !ok {
+		return nil, errs.Conflict("task %q is not running", task.Name)
+	}
+	execution.cancel()
+	execution.log.Printf("cancellation requested")
+	s.logger.Info("Task cancellation requested", "name", task.Name, "run", execution.run.ID)
+	copied := *execution.run
+	return &copied, nil
+}
+
+// ListRuns 按时间倒序列出任务的执行记录，不包含日志与结果
+func (s *TaskServiceImpl) ListRuns(ctx context.Context, id string, query *dto.ListTaskRunsQuery) ([]*model.TaskRun, error) {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTaskRunLimit
+	}
+	runs, err := s.runs.ListByTask(ctx, task.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun 获取执行记录，执行尚未结束时返回当前日志
+func (s *TaskServiceImpl) GetRun(ctx context.Context, id, runID string) (*model.TaskRun, error) {
+	task, err := s.lookupTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.runs.FindByID(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task run: %w", err)
+	}
+	if run == nil || run.TaskID != task.ID {
+		return nil, errs.NotFound("task run %q not found", runID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if execution, ok := s.running[task.ID]; ok && execution.run.ID == run.ID {
+		run.Log = execution.log.String()
+	}
+	return run, nil
+}
+
+// Types 列出可调度的任务类型
+func (s *TaskServiceImpl) Types() []dto.TaskType {
+	return taskTypeList(s.types)
+}
+
+// execute 创建执行记录并在后台执行任务，任务正在执行时返回冲突
+func (s *TaskServiceImpl) execute(task *model.Task, trigger string) (*model.TaskRun, error) {
+	typ, ok := s.types[task.Type]
+	if !ok {
+		return nil, errs.InvalidArgument("unknown task type %q", task.Type)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return nil, errs.Unavailable("task scheduler is stopped")
+	}
+	if _, ok := s.running[task.ID]; ok {
+		return nil, errs.Conflict("task %q is already running", task.Name)
+	}
+
+	run := &model.TaskRun{
+		ID:        uuid.New().String(),
+		TaskID:    task.ID,
+		Trigger:   trigger,
+		Status:    model.TaskStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.runs.Create(s.ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create task run: %w", err)
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	execution := &taskExecution{run: run, log: &taskLog{}, cancel: cancel}
+	s.running[task.ID] = execution
+	copied := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.finish(task, execution, s.invoke(ctx, typ, task, execution.log))
+	}()
+	s.logger.Info("Task started", "name", task.Name, "run", run.ID, "trigger", trigger)
+	return &copied, nil
+}
+
+// invoke 执行任务类型函数并记录结果，执行函数 panic 时视为失败
+func (s *TaskServiceImpl) invoke(ctx context.Context, typ *taskType, task *model.Task, log *taskLog) (outcome taskOutcome) {
+	defer func() {
+		if r := recover(); r != nil {
+			outcome = taskOutcome{err: fmt.Errorf("task panicked: %v", r)}
+		}
+	}()
+	log.Printf("task %s (%s) started", task.Name, task.Type)
+	result, err := typ.run(ctx, task.Params, log)
+	outcome = taskOutcome{result: result, err: err}
+	if ctx.Err() != nil && (err == nil || errors.Is(err, context.Canceled)) {
+		outcome.cancelled = true
+	}
+	return outcome
+}
+
+// taskOutcome 任务类型函数的执行结果
+type taskOutcome struct {
+	result    interface{}
+	err       error
+	cancelled bool
+}
+
+// finish 保存执行结果，更新任务的最近执行状态并清理过多的历史记录
+// execution.run 在执行结束前可被 Cancel 读取，结果写入其副本
+func (s *TaskServiceImpl) finish(task *model.Task, execution *taskExecution, outcome taskOutcome) {
+	// 服务停止时 s.ctx 已取消，结果仍需写入数据库
+	ctx := context.Background()
+	copied := *execution.run
+	run := &copied
+	now := time.Now()
+	run.FinishedAt = &now
+	switch {
+	case outcome.cancelled:
+		run.Status = model.TaskStatusCancelled
+		execution.log.Printf("task cancelled")
+	case outcome.err != nil:
+		run.Status = model.TaskStatusFailed
+		run.Error = truncate(outcome.err.Error(), 1000)
+		execution.log.Printf("task failed: %v", outcome.err)
+	default:
+		run.Status = model.TaskStatusSucceeded
+		execution.log.Printf("task finished in %s", now.Sub(run.StartedAt).Round(time.Millisecond))
+	}
+	if outcome.result != nil {
+		if data, err := json.Marshal(outcome.result); err == nil {
+			run.Result = data
+		}
+	}
+	run.Log = execution.log.String()
+
+	if err := s.runs.Update(ctx, run); err != nil {
+		s.logger.Error("Failed to save task run", "task", task.Name, "run", run.ID, "error", err)
+	}
+	if current, err := s.tasks.FindByID(ctx, task.ID); err == nil && current != nil {
+		current.LastRun = &run.StartedAt
+		current.LastStatus = run.Status
+		if err := s.tasks.Update(ctx, current); err != nil {
+			s.logger.Error("Failed to update task status", "task", task.Name, "error", err)
+		}
+	}
+	if err := s.runs.Prune(ctx, task.ID, taskRunHistory); err != nil {
+		s.logger.Warn("Failed to prune task runs", "task", task.Name, "error", err)
+	}
+
+	s.mu.Lock()
+	delete(s.running, task.ID)
+	s.mu.Unlock()
+	s.logger.Info("Task finished", "name", task.Name, "run", run.ID, "status", run.Status, "error", run.Error)
+}
+
+// trigger 调度器触发任务执行，任务仍在执行时跳过本次调度
+func (s *TaskServiceImpl) trigger(id string) {
+	task, err := s.tasks.FindByID(s.ctx, id)
+	if err != nil || task == nil {
+		s.logger.Error("Failed to load scheduled task", "task", id, "error", err)
+		return
+	}
+	if _, err := s.execute(task, model.TaskTriggerSchedule); err != nil {
+		s.logger.Warn("Scheduled task skipped", "task", task.Name, "error", err)
+	}
+}
+
+// schedule 按任务当前的 cron 表达式与启用状态重新调度，调用方需持有 s.mu
+func (s *TaskServiceImpl) schedule(task *model.Task) error {
+	s.unschedule(task.ID)
+	if !task.Enabled || task.Cron == "" {
+		return nil
+	}
+	id := task.ID
+	entry, err := s.cron.AddFunc(task.Cron, func() { s.trigger(id) })
+	if err != nil {
+		return errs.InvalidArgument("invalid cron expression %q: %v", task.Cron, err)
+	}
+	s.entries[task.ID] = entry
+	return nil
+}
+
+// unschedule 移除任务的调度项，调用方需持有 s.mu
+func (s *TaskServiceImpl) unschedule(id string) {
+	if entry, ok := s.entries[id]; ok {
+		s.cron.Remove(entry)
+		delete(s.entries, id)
+	}
+}
+
+// decorate 填充任务的下次执行时间与执行状态，调用方需持有 s.mu
+func (s *TaskServiceImpl) decorate(task *model.Task) {
+	if entry, ok := s.entries[task.ID]; ok {
+		if next := s.cron.Entry(entry).Schedule.Next(time.Now()); !next.IsZero() {
+			task.NextRun = &next
+		}
+	}
+	_, task.Running = s.running[task.ID]
+}
+
+// validate 校验任务类型、参数与 cron 表达式
+func (s *TaskServiceImpl) validate(task *model.Task) error {
+	typ, ok := s.types[task.Type]
+	if !ok {
+		return errs.InvalidArgument("unknown task type %q", task.Type)
+	}
+	if err := validateTaskParams(typ, task.Params); err != nil {
+		return err
+	}
+	if task.Cron != "" {
+		if _, err := cron.ParseStandard(task.Cron); err != nil {
+			return errs.InvalidArgument("invalid cron expression %q: %v", task.Cron, err)
+		}
+	}
+	return nil
+}
+
+// lookupTask 根据ID或名称查找任务
+func (s *TaskServiceImpl) lookupTask(ctx context.Context, idOrName string) (*model.Task, error) {
+	task, err := s.tasks.FindByID(ctx, idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+	if task == nil {
+		task, err = s.tasks.FindByName(ctx, idOrName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find task: %w", err)
+		}
+	}
+	if task == nil {
+		return nil, errs.NotFound("task %q not found", idOrName)
+	}
+	return task, nil
+}
+
+// seedDefaults 清理配置启用且尚无同名任务时，创建按配置间隔执行清理策略的任务
+func (s *TaskServiceImpl) seedDefaults(ctx context.Context) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+	existing, err := s.tasks.FindByName(ctx, defaultCleanupTaskName)
+	if err != nil {
+		return fmt.Errorf("failed to find task: %w", err)
+	}
+	if existing != nil {
+		return nil
+	}
+	spec := "@daily"
+	if s.cfg.Interval > 0 {
+		spec = "@every " + s.cfg.Interval.String()
+	}
+	task := &model.Task{
+		ID:      uuid.New().String(),
+		Name:    defaultCleanupTaskName,
+		Type:    taskTypeCleanup,
+		Cron:    spec,
+		Enabled: true,
+	}
+	if err := s.tasks.Create(ctx, task); err != nil {
+		return fmt.Errorf("failed to create default cleanup task: %w", err)
+	}
+	s.logger.Info("Default cleanup task created", "cron", spec)
+	return nil
+}
+
+// truncate 截断过长的字符串
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
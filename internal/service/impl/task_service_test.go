@@ -0,0 +1,356 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	repoimpl "github.com/laolishu/go-nexus/internal/repository/impl"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// 测试专用任务类型
+const (
+	taskTypeBlock = "block" // 等待 release 关闭或 ctx 取消
+	taskTypeFail  = "fail"  // 立即返回错误
+	taskTypePanic = "panic" // 执行时 panic
+)
+
+// newTaskEnv 创建任务服务，并注册由 release 控制结束时间的测试任务类型
+func newTaskEnv(t *testing.T) (*testEnv, *TaskServiceImpl, chan struct{}) {
+	t.Helper()
+	env := newTestEnv(t)
+	tasks := repoimpl.NewTaskRepository(env.logger, dao.NewTaskDAO(env.logger, env.db))
+	runs := repoimpl.NewTaskRunRepository(env.logger, dao.NewTaskRunDAO(env.logger, env.db))
+	cleanup := NewCleanupService(env.logger, env.policyRepo, env.artifacts, env.audit)
+	svc, stop := NewTaskService(env.cfg, env.logger, tasks, runs, cleanup, env.artifacts, newUploadService(t, env, nil))
+	t.Cleanup(stop)
+
+	release := make(chan struct{})
+	svc.types[taskTypeBlock] = &taskType{run: func(ctx context.Context, _ map[string]string, log *taskLog) (interface{}, error) {
+		log.Printf("waiting")
+		select {
+		case <-release:
+			return map[string]bool{"released": true}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}}
+	svc.types[taskTypeFail] = &taskType{run: func(context.Context, map[string]string, *taskLog) (interface{}, error) {
+		return nil, errors.New("boom")
+	}}
+	svc.types[taskTypePanic] = &taskType{run: func(context.Context, map[string]string, *taskLog) (interface{}, error) {
+		panic("unexpected")
+	}}
+	return env, svc, release
+}
+
+// waitRun 等待执行结束并返回最终的执行记录
+func waitRun(t *testing.T, svc *TaskServiceImpl, task, runID string) *model.TaskRun {
+	t.Helper()
+	var run *model.TaskRun
+	require.Eventually(t, func() bool {
+		var err error
+		run, err = svc.GetRun(context.Background(), task, runID)
+		require.NoError(t, err)
+		return run.Status != model.TaskStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return run
+}
+
+func TestTaskService_CreateValidation(t *testing.T) {
+	_, svc, _ := newTaskEnv(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		req     *dto.CreateTaskRequest
+		wantErr error
+	}{
+		{name: "standard_cron", req: &dto.CreateTaskRequest{Name: "standard", Type: taskTypeUploadGC, Cron: "*/15 2 * * 1-5"}},
+		{name: "descriptor", req: &dto.CreateTaskRequest{Name: "daily", Type: taskTypeUploadGC, Cron: "@daily"}},
+		{name: "every", req: &dto.CreateTaskRequest{Name: "every", Type: taskTypeUploadGC, Cron: "@every 1h30m"}},
+		{name: "manual_only", req: &dto.CreateTaskRequest{Name: "manual", Type: taskTypeIndexRebuild}},
+		{name: "supported_param", req: &dto.CreateTaskRequest{Name: "meta", Type: taskTypeMetadataRebuild, Params: map[string]string{"repository": "libs"}}},
+		{name: "seconds_field", req: &dto.CreateTaskRequest{Name: "seconds", Type: taskTypeUploadGC, Cron: "0 */5 * * * *"}, wantErr: errs.ErrInvalidArgument},
+		{name: "out_of_range", req: &dto.CreateTaskRequest{Name: "range", Type: taskTypeUploadGC, Cron: "61 * * * *"}, wantErr: errs.ErrInvalidArgument},
+		{name: "garbage_cron", req: &dto.CreateTaskRequest{Name: "garbage", Type: taskTypeUploadGC, Cron: "sometimes"}, wantErr: errs.ErrInvalidArgument},
+		{name: "bad_every", req: &dto.CreateTaskRequest{Name: "bad-every", Type: taskTypeUploadGC, Cron: "@every soon"}, wantErr: errs.ErrInvalidArgument},
+		{name: "unknown_type", req: &dto.CreateTaskRequest{Name: "unknown", Type: "reboot"}, wantErr: errs.ErrInvalidArgument},
+		{name: "unsupported_param", req: &dto.CreateTaskRequest{Name: "param", Type: taskTypeUploadGC, Params: map[string]string{"repository": "libs"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "bad_name", req: &dto.CreateTaskRequest{Name: "bad name", Type: taskTypeUploadGC}, wantErr: errs.ErrInvalidArgument},
+		{name: "duplicate", req: &dto.CreateTaskRequest{Name: "standard", Type: taskTypeUploadGC}, wantErr: errs.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := svc.Create(ctx, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, task.Enabled, "tasks are enabled by default")
+			if tt.req.Cron == "" {
+				assert.Nil(t, task.NextRun)
+			} else {
+				require.NotNil(t, task.NextRun)
+				assert.True(t, task.NextRun.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestTaskService_Update(t *testing.T) {
+	_, svc, _ := newTaskEnv(t)
+	ctx := context.Background()
+	task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: "gc", Type: taskTypeUploadGC, Cron: "@hourly"})
+	require.NoError(t, err)
+	require.NotNil(t, task.NextRun)
+
+	disabled := false
+	updated, err := svc.Update(ctx, "gc", &dto.UpdateTaskRequest{Enabled: &disabled})
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Nil(t, updated.NextRun, "disabled tasks are not scheduled")
+
+	enabled, spec := true, "0 3 * * *"
+	updated, err = svc.Update(ctx, task.ID, &dto.UpdateTaskRequest{Enabled: &enabled, Cron: &spec})
+	require.NoError(t, err)
+	require.NotNil(t, updated.NextRun)
+	assert.Equal(t, 3, updated.NextRun.Hour())
+	assert.Zero(t, updated.NextRun.Minute())
+
+	invalid := "* * *"
+	_, err = svc.Update(ctx, task.ID, &dto.UpdateTaskRequest{Cron: &invalid})
+	assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	got, err := svc.Get(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, spec, got.Cron, "invalid updates are not saved")
+
+	_, err = svc.Update(ctx, "missing", &dto.UpdateTaskRequest{})
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestTaskService_RunNow(t *testing.T) {
+	tests := []struct {
+		name      string
+		typ       string
+		wantState string
+		wantError string
+	}{
+		{name: "succeeded", typ: taskTypeUploadGC, wantState: model.TaskStatusSucceeded},
+		{name: "failed", typ: taskTypeFail, wantState: model.TaskStatusFailed, wantError: "boom"},
+		{name: "panicked", typ: taskTypePanic, wantState: model.TaskStatusFailed, wantError: "task panicked: unexpected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, svc, _ := newTaskEnv(t)
+			ctx := context.Background()
+			disabled := false
+			task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: tt.name, Type: tt.typ, Enabled: &disabled})
+			require.NoError(t, err)
+
+			run, err := svc.RunNow(ctx, tt.name)
+			require.NoError(t, err, "disabled tasks can still be run manually")
+			assert.Equal(t, model.TaskTriggerManual, run.Trigger)
+
+			finished := waitRun(t, svc, task.ID, run.ID)
+			assert.Equal(t, tt.wantState, finished.Status)
+			assert.Equal(t, tt.wantError, finished.Error)
+			assert.NotNil(t, finished.FinishedAt)
+			assert.Contains(t, finished.Log, "started")
+
+			require.Eventually(t, func() bool {
+				got, err := svc.Get(ctx, task.ID)
+				require.NoError(t, err)
+				return !got.Running && got.LastStatus == tt.wantState
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestTaskService_RunningTask(t *testing.T) {
+	_, svc, release := newTaskEnv(t)
+	ctx := context.Background()
+	task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: "block", Type: taskTypeBlock})
+	require.NoError(t, err)
+
+	_, err = svc.Cancel(ctx, task.ID)
+	assert.ErrorIs(t, err, errs.ErrConflict, "cancel requires a running execution")
+
+	run, err := svc.RunNow(ctx, task.ID)
+	require.NoError(t, err)
+
+	t.Run("get_shows_running", func(t *testing.T) {
+		got, err := svc.Get(ctx, task.ID)
+		require.NoError(t, err)
+		assert.True(t, got.Running)
+	})
+
+	t.Run("second_run_conflicts", func(t *testing.T) {
+		_, err := svc.RunNow(ctx, task.ID)
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("delete_conflicts", func(t *testing.T) {
+		assert.ErrorIs(t, svc.Delete(ctx, task.ID), errs.ErrConflict)
+	})
+
+	t.Run("live_log", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			got, err := svc.GetRun(ctx, task.ID, run.ID)
+			require.NoError(t, err)
+			return got.Status == model.TaskStatusRunning && strings.Contains(got.Log, "waiting")
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	close(release)
+	finished := waitRun(t, svc, task.ID, run.ID)
+	assert.Equal(t, model.TaskStatusSucceeded, finished.Status)
+	assert.JSONEq(t, `{"released":true}`, string(finished.Result))
+
+	runs, err := svc.ListRuns(ctx, task.ID, &dto.ListTaskRunsQuery{})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, run.ID, runs[0].ID)
+
+	_, err = svc.GetRun(ctx, task.ID, "missing")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestTaskService_Cancel(t *testing.T) {
+	_, svc, _ := newTaskEnv(t)
+	ctx := context.Background()
+	task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: "block", Type: taskTypeBlock})
+	require.NoError(t, err)
+	run, err := svc.RunNow(ctx, task.ID)
+	require.NoError(t, err)
+
+	cancelled, err := svc.Cancel(ctx, "block")
+	require.NoError(t, err)
+	assert.Equal(t, run.ID, cancelled.ID)
+
+	finished := waitRun(t, svc, task.ID, run.ID)
+	assert.Equal(t, model.TaskStatusCancelled, finished.Status)
+	assert.Contains(t, finished.Log, "cancellation requested")
+
+	require.Eventually(t, func() bool {
+		return svc.Delete(ctx, task.ID) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = svc.Get(ctx, task.ID)
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestTaskService_Stop(t *testing.T) {
+	env := newTestEnv(t)
+	tasks := repoimpl.NewTaskRepository(env.logger, dao.NewTaskDAO(env.logger, env.db))
+	runs := repoimpl.NewTaskRunRepository(env.logger, dao.NewTaskRunDAO(env.logger, env.db))
+	cleanup := NewCleanupService(env.logger, env.policyRepo, env.artifacts, env.audit)
+	svc, stop := NewTaskService(env.cfg, env.logger, tasks, runs, cleanup, env.artifacts, newUploadService(t, env, nil))
+	svc.types[taskTypeBlock] = &taskType{run: func(ctx context.Context, _ map[string]string, _ *taskLog) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	ctx := context.Background()
+	task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: "block", Type: taskTypeBlock})
+	require.NoError(t, err)
+	run, err := svc.RunNow(ctx, task.ID)
+	require.NoError(t, err)
+
+	stop()
+	finished, err := runs.FindByID(ctx, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TaskStatusCancelled, finished.Status, "stop cancels and waits for running executions")
+
+	_, err = svc.RunNow(ctx, task.ID)
+	assert.ErrorIs(t, err, errs.ErrUnavailable)
+}
+
+func TestTaskService_Start(t *testing.T) {
+	t.Run("fails_interrupted_runs", func(t *testing.T) {
+		env, svc, _ := newTaskEnv(t)
+		ctx := context.Background()
+		task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: "gc", Type: taskTypeUploadGC, Cron: "@hourly"})
+		require.NoError(t, err)
+		stale := &model.TaskRun{ID: "stale", TaskID: task.ID, Trigger: model.TaskTriggerSchedule, Status: model.TaskStatusRunning, StartedAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, env.db.Create(stale).Error)
+
+		require.NoError(t, svc.Start(ctx))
+		run, err := svc.GetRun(ctx, task.ID, "stale")
+		require.NoError(t, err)
+		assert.Equal(t, model.TaskStatusFailed, run.Status)
+		assert.Equal(t, "interrupted by server restart", run.Error)
+
+		got, err := svc.Get(ctx, task.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.NextRun)
+	})
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		interval time.Duration
+		wantCron string
+	}{
+		{name: "disabled", enabled: false},
+		{name: "default_daily", enabled: true, wantCron: "@daily"},
+		{name: "interval", enabled: true, interval: 6 * time.Hour, wantCron: "@every 6h0m0s"},
+	}
+	for _, tt := range tests {
+		t.Run("seed_"+tt.name, func(t *testing.T) {
+			_, svc, _ := newTaskEnv(t)
+			svc.cfg.Enabled = tt.enabled
+			svc.cfg.Interval = tt.interval
+			ctx := context.Background()
+			require.NoError(t, svc.Start(ctx))
+
+			task, err := svc.Get(ctx, defaultCleanupTaskName)
+			if tt.wantCron == "" {
+				assert.ErrorIs(t, err, errs.ErrNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, taskTypeCleanup, task.Type)
+			assert.Equal(t, tt.wantCron, task.Cron)
+			assert.NotNil(t, task.NextRun)
+
+			// 已存在同名任务时不覆盖用户修改
+			spec := "0 4 * * *"
+			_, err = svc.Update(ctx, task.ID, &dto.UpdateTaskRequest{Cron: &spec})
+			require.NoError(t, err)
+			require.NoError(t, svc.seedDefaults(ctx))
+			got, err := svc.Get(ctx, task.ID)
+			require.NoError(t, err)
+			assert.Equal(t, spec, got.Cron)
+		})
+	}
+}
+
+func TestTaskService_ScheduledTrigger(t *testing.T) {
+	_, svc, release := newTaskEnv(t)
+	close(release)
+	ctx := context.Background()
+	task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: "block", Type: taskTypeBlock})
+	require.NoError(t, err)
+
+	svc.trigger(task.ID)
+	require.Eventually(t, func() bool {
+		runs, err := svc.ListRuns(ctx, task.ID, &dto.ListTaskRunsQuery{})
+		require.NoError(t, err)
+		return len(runs) == 1 && runs[0].Trigger == model.TaskTriggerSchedule && runs[0].Status == model.TaskStatusSucceeded
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// 已删除的任务不再执行
+	require.Eventually(t, func() bool {
+		return svc.Delete(ctx, task.ID) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	svc.trigger(task.ID)
+}
@@ -0,0 +1,169 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// 内置任务类型
+const (
+	taskTypeCleanup         = "cleanup"
+	taskTypeMetadataRebuild = "metadata_rebuild"
+	taskTypeIndexRebuild    = "index_rebuild"
+	taskTypeUploadGC        = "upload_gc"
+)
+
+// taskFunc 任务类型的执行函数，ctx 取消时应尽快返回，返回值序列化为 JSON 作为执行结果
+type taskFunc func(ctx context.Context, params map[string]string, log *taskLog) (interface{}, error)
+
+// taskType 可调度的任务类型，新增类型只需在 registerTaskTypes 中注册
+type taskType struct {
+	description string
+	params      []string
+	run         taskFunc
+}
+
+// registerTaskTypes 注册内置任务类型
+func (s *TaskServiceImpl) registerTaskTypes(cleanup *CleanupServiceImpl, artifacts *ArtifactServiceImpl, uploads *UploadServiceImpl) {
+	s.types = map[string]*taskType{
+		taskTypeCleanup: {
+			description: "Run the cleanup policies attached to each repository",
+			run: func(ctx context.Context, _ map[string]string, log *taskLog) (interface{}, error) {
+				report, err := cleanup.RunAll(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, result := range report.Results {
+					log.Printf("policy %s on %s: %d deleted, %d failed", result.Policy, result.Repository, result.Deleted, result.Failed)
+				}
+				return report, nil
+			},
+		},
+		taskTypeMetadataRebuild: {
+			description: "Regenerate format metadata of hosted repositories",
+			params:      []string{"repository"},
+			run: func(ctx context.Context, params map[string]string, log *taskLog) (interface{}, error) {
+				return rebuildAllMetadata(ctx, artifacts, params["repository"], log)
+			},
+		},
+		taskTypeIndexRebuild: {
+			description: "Rebuild the full-text search index from the database",
+			run: func(ctx context.Context, _ map[string]string, log *taskLog) (interface{}, error) {
+				if err := artifacts.reindex(ctx); err != nil {
+					return nil, err
+				}
+				documents := artifacts.index.Len()
+				log.Printf("indexed %d documents", documents)
+				return map[string]int{"documents": documents}, nil
+			},
+		},
+		taskTypeUploadGC: {
+			description: "Remove expired chunked upload sessions and their partial data",
+			run: func(ctx context.Context, _ map[string]string, log *taskLog) (interface{}, error) {
+				removed, err := uploads.CleanupExpired(ctx)
+				if err != nil {
+					return nil, err
+				}
+				log.Printf("removed %d expired upload sessions", removed)
+				return map[string]int{"removed": removed}, nil
+			},
+		},
+	}
+}
+
+// validateTaskParams 校验任务参数只包含类型支持的参数
+func validateTaskParams(typ *taskType, params map[string]string) error {
+	for key := range params {
+		supported := false
+		for _, name := range typ.params {
+			if name == key {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errs.InvalidArgument("unsupported task parameter %q", key)
+		}
+	}
+	return nil
+}
+
+// taskTypeList 按名称排序返回任务类型说明
+func taskTypeList(types map[string]*taskType) []dto.TaskType {
+	list := make([]dto.TaskType, 0, len(types))
+	for _, name := range sortedKeys(types) {
+		params := types[name].params
+		if params == nil {
+			params = []string{}
+		}
+		list = append(list, dto.TaskType{Type: name, Description: types[name].description, Params: params})
+	}
+	return list
+}
+
+// rebuildAllMetadata 重新生成宿主仓库中所有制品涉及的元数据文件，repoName 为空时处理所有宿主仓库
+func rebuildAllMetadata(ctx context.Context, artifacts *ArtifactServiceImpl, repoName string, log *taskLog) (interface{}, error) {
+	var repos []*model.Repository
+	if repoName != "" {
+		repo, err := lookupRepository(ctx, artifacts.repositories, repoName)
+		if err != nil {
+			return nil, err
+		}
+		if repo.Type != model.RepositoryTypeHosted {
+			return nil, errs.InvalidArgument("repository %q is not a hosted repository", repo.Name)
+		}
+		repos = []*model.Repository{repo}
+	} else {
+		all, err := artifacts.repositories.List(ctx, model.RepositoryTypeHosted, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		repos = all
+	}
+
+	rebuilt := make(map[string]int, len(repos))
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return rebuilt, err
+		}
+		records, err := artifacts.repository.ListByRepository(ctx, repo.ID)
+		if err != nil {
+			return rebuilt, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		paths := make([]string, 0, len(records))
+		for _, record := range records {
+			paths = append(paths, record.Path)
+		}
+		rebuilt[repo.Name] = artifacts.refreshMetadataPaths(ctx, repo, paths)
+		log.Printf("repository %s: %d metadata files regenerated", repo.Name, rebuilt[repo.Name])
+	}
+	return rebuilt, nil
+}
+
+// taskLog 任务执行日志，执行期间可通过接口实时查看
+type taskLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// Printf 追加一行带时间戳的日志
+func (l *taskLog) Printf(format string, args ...interface{}) {
+	line := time.Now().UTC().Format(time.RFC3339) + " " + fmt.Sprintf(format, args...)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+}
+
+// String 返回完整日志
+func (l *taskLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
@@ -0,0 +1,151 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func TestValidateTaskParams(t *testing.T) {
+	typ := &taskType{params: []string{"repository"}}
+	tests := []struct {
+		name    string
+		typ     *taskType
+		params  map[string]string
+		wantErr bool
+	}{
+		{name: "nil", typ: typ},
+		{name: "supported", typ: typ, params: map[string]string{"repository": "libs"}},
+		{name: "unsupported", typ: typ, params: map[string]string{"force": "true"}, wantErr: true},
+		{name: "type_without_params", typ: &taskType{}, params: map[string]string{"repository": "libs"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTaskParams(tt.typ, tt.params)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTaskTypeList(t *testing.T) {
+	_, svc, _ := newTaskEnv(t)
+	builtin := map[string]*taskType{}
+	for _, name := range []string{taskTypeCleanup, taskTypeMetadataRebuild, taskTypeIndexRebuild, taskTypeUploadGC} {
+		builtin[name] = svc.types[name]
+	}
+
+	list := taskTypeList(builtin)
+	names := make([]string, 0, len(list))
+	for _, typ := range list {
+		names = append(names, typ.Type)
+		assert.NotEmpty(t, typ.Description)
+		assert.NotNil(t, typ.Params, "params are always an array in responses")
+	}
+	assert.Equal(t, []string{taskTypeCleanup, taskTypeIndexRebuild, taskTypeMetadataRebuild, taskTypeUploadGC}, names)
+	assert.Equal(t, []string{"repository"}, list[2].Params)
+}
+
+func TestTaskLog(t *testing.T) {
+	log := &taskLog{}
+	assert.Empty(t, log.String())
+	log.Printf("first %d", 1)
+	log.Printf("second")
+	lines := strings.Split(log.String(), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasSuffix(lines[0], " first 1"))
+	assert.True(t, strings.HasSuffix(lines[1], " second"))
+}
+
+// runTask 创建并立即执行任务，返回结束后的执行记录
+func runTask(t *testing.T, svc *TaskServiceImpl, name, typ string, params map[string]string) *model.TaskRun {
+	t.Helper()
+	ctx := context.Background()
+	task, err := svc.Create(ctx, &dto.CreateTaskRequest{Name: name, Type: typ, Params: params})
+	require.NoError(t, err)
+	run, err := svc.RunNow(ctx, task.ID)
+	require.NoError(t, err)
+	return waitRun(t, svc, task.ID, run.ID)
+}
+
+func TestTaskTypes_Builtin(t *testing.T) {
+	env, svc, _ := newTaskEnv(t)
+	env.hosted(t, "libs-release", "maven")
+	env.hosted(t, "libs-snapshot", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "maven-public", Type: model.RepositoryTypeGroup, Format: "maven",
+		Members: []string{"libs-release"}})
+	env.upload(t, "libs-release", "com/x/lib/1.0/lib-1.0.jar", []byte("1.0"))
+	env.upload(t, "libs-release", "com/x/lib/1.0/lib-1.0.pom", pomOf("com.x", "lib", "1.0", "indexed library"))
+
+	t.Run("index_rebuild", func(t *testing.T) {
+		run := runTask(t, svc, "index", taskTypeIndexRebuild, nil)
+		require.Equal(t, model.TaskStatusSucceeded, run.Status, run.Error)
+		assert.JSONEq(t, `{"documents":1}`, string(run.Result))
+		assert.Contains(t, run.Log, "indexed 1 documents")
+	})
+
+	t.Run("metadata_rebuild_all_hosted", func(t *testing.T) {
+		require.NoError(t, env.storage.Delete(context.Background(), storagePath(mustRepo(t, env, "libs-release"), "com/x/lib/maven-metadata.xml")))
+		run := runTask(t, svc, "metadata", taskTypeMetadataRebuild, nil)
+		require.Equal(t, model.TaskStatusSucceeded, run.Status, run.Error)
+		assert.Contains(t, string(run.Result), `"libs-release"`)
+		assert.Contains(t, string(run.Result), `"libs-snapshot":0`)
+		assert.NotContains(t, string(run.Result), "maven-public")
+
+		metadata, err := env.download("libs-release", "com/x/lib/maven-metadata.xml")
+		require.NoError(t, err)
+		assert.Contains(t, string(metadata), "<version>1.0</version>")
+	})
+
+	t.Run("metadata_rebuild_single", func(t *testing.T) {
+		run := runTask(t, svc, "metadata-release", taskTypeMetadataRebuild, map[string]string{"repository": "libs-release"})
+		require.Equal(t, model.TaskStatusSucceeded, run.Status, run.Error)
+		assert.NotContains(t, string(run.Result), "libs-snapshot")
+	})
+
+	t.Run("metadata_rebuild_rejects_group", func(t *testing.T) {
+		run := runTask(t, svc, "metadata-group", taskTypeMetadataRebuild, map[string]string{"repository": "maven-public"})
+		assert.Equal(t, model.TaskStatusFailed, run.Status)
+		assert.Contains(t, run.Error, "not a hosted repository")
+	})
+
+	t.Run("upload_gc", func(t *testing.T) {
+		run := runTask(t, svc, "gc", taskTypeUploadGC, nil)
+		require.Equal(t, model.TaskStatusSucceeded, run.Status, run.Error)
+		assert.JSONEq(t, `{"removed":0}`, string(run.Result))
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		cleanup := NewCleanupService(env.logger, env.policyRepo, env.artifacts, env.audit)
+		_, err := cleanup.CreatePolicy(context.Background(), &dto.CleanupPolicyRequest{Name: "all", Criteria: model.CleanupCriteria{PathRegex: ".*"}})
+		require.NoError(t, err)
+		repo := mustRepo(t, env, "libs-release")
+		_, err = env.repositories.Update(context.Background(), repo.ID, &dto.UpdateRepositoryRequest{CleanupPolicies: []string{"all"}})
+		require.NoError(t, err)
+
+		run := runTask(t, svc, "cleanup", taskTypeCleanup, nil)
+		require.Equal(t, model.TaskStatusSucceeded, run.Status, run.Error)
+		assert.Contains(t, run.Log, "policy all on libs-release: 2 deleted, 0 failed")
+		_, err = env.download("libs-release", "com/x/lib/1.0/lib-1.0.jar")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+// mustRepo 按名称查找仓库，不存在时终止测试
+func mustRepo(t *testing.T, env *testEnv, name string) *model.Repository {
+	t.Helper()
+	repo, err := env.repos.FindByName(context.Background(), name)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	return repo
+}
@@ -0,0 +1,178 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+const (
+	// accessTokenPrefix 个人访问令牌明文前缀，用于与 JWT 区分
+	accessTokenPrefix = "gnp_"
+	// accessTokenDisplayLength 保存的明文前缀长度
+	accessTokenDisplayLength = 12
+	// lastUsedInterval 最近使用时间的最小更新间隔，避免每个请求都写数据库
+	lastUsedInterval = 5 * time.Minute
+)
+
+// TokenServiceImpl 个人访问令牌服务实现，令牌只保存 SHA-256 哈希
+type TokenServiceImpl struct {
+	logger *slog.Logger
+	tokens repository.AccessTokenRepository
+	users  *UserServiceImpl
+}
+
+// NewTokenService 创建新的个人访问令牌服务实现
+func NewTokenService(logger *slog.Logger, tokens repository.AccessTokenRepository, users *UserServiceImpl) *TokenServiceImpl {
+	return &TokenServiceImpl{
+		logger: logger,
+		tokens: tokens,
+		users:  users,
+	}
+}
+
+// Create 为调用者创建令牌
+func (s *TokenServiceImpl) Create(ctx context.Context, principal *auth.Principal, req *dto.CreateAccessTokenRequest) (*dto.CreatedAccessToken, error) {
+	if principal == nil || principal.Anonymous {
+		return nil, errs.Unauthenticated("authentication required")
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if err := auth.ValidateScope(scope); err != nil {
+			return nil, errs.InvalidArgument("%v", err)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	secret, err := newAccessTokenSecret()
+	if err != nil {
+		return nil, err
+	}
+	token := &model.AccessToken{
+		ID:     uuid.New().String(),
+		UserID: principal.UserID,
+		Name:   req.Name,
+		Token:  hashAccessToken(secret),
+		Prefix: secret[:accessTokenDisplayLength],
+		Scopes: scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.tokens.Create(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+	s.logger.Info("Access token created", "username", principal.Username, "name", token.Name, "scopes", scopes)
+	return &dto.CreatedAccessToken{AccessToken: token, Token: secret}, nil
+}
+
+// List 列出用户的令牌
+func (s *TokenServiceImpl) List(ctx context.Context, userID string) ([]*model.AccessToken, error) {
+	user, err := s.users.lookupUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokens.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke 吊销用户的令牌，令牌不属于该用户时视为不存在
+func (s *TokenServiceImpl) Revoke(ctx context.Context, userID, tokenID string) error {
+	user, err := s.users.lookupUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	token, err := s.tokens.FindByID(ctx, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to find access token: %w", err)
+	}
+	if token == nil || token.UserID != user.ID {
+		return errs.NotFound("access token %q not found", tokenID)
+	}
+	if err := s.tokens.Delete(ctx, token.ID); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	s.logger.Info("Access token revoked", "username", user.Username, "name", token.Name)
+	return nil
+}
+
+// Authenticate 校验令牌明文，username 非空时要求与令牌所属用户一致
+func (s *TokenServiceImpl) Authenticate(ctx context.Context, username, secret string) (*auth.Principal, error) {
+	token, err := s.tokens.FindByHash(ctx, hashAccessToken(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find access token: %w", err)
+	}
+	if token == nil {
+		return nil, errs.Unauthenticated("invalid access token")
+	}
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, errs.Unauthenticated("access token has expired")
+	}
+	user, err := s.users.users.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || (username != "" && username != user.Username) {
+		return nil, errs.Unauthenticated("invalid access token")
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	if token.LastUsed == nil || now.Sub(*token.LastUsed) >= lastUsedInterval {
+		if err := s.tokens.UpdateLastUsed(ctx, token.ID, now); err != nil {
+			s.logger.Warn("Failed to update access token last used time", "token", token.ID, "error", err)
+		}
+	}
+
+	principal := newPrincipal(user, auth.CredentialAccessToken, token.ID, time.Time{})
+	principal.Scopes = token.Scopes
+	if principal.Scopes == nil {
+		principal.Scopes = []string{}
+	}
+	return principal, nil
+}
+
+// isAccessToken 判断凭据是否为个人访问令牌
+func isAccessToken(secret string) bool {
+	return strings.HasPrefix(secret, accessTokenPrefix)
+}
+
+// newAccessTokenSecret 生成令牌明文
+func newAccessTokenSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+	return accessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashAccessToken 计算令牌明文的 SHA-256 哈希
+func hashAccessToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
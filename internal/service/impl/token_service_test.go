@@ -0,0 +1,236 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+// createToken 以用户身份创建个人访问令牌，失败时终止测试
+func createToken(t *testing.T, e *authEnv, user *model.User, scopes ...string) *dto.CreatedAccessToken {
+	t.Helper()
+	principal := newPrincipal(user, auth.CredentialJWT, "", time.Time{})
+	created, err := e.tokens.Create(context.Background(), principal, &dto.CreateAccessTokenRequest{Name: "ci", Scopes: scopes})
+	require.NoError(t, err)
+	return created
+}
+
+// storedToken 读取数据库中的令牌记录
+func storedToken(t *testing.T, e *authEnv, id string) *model.AccessToken {
+	t.Helper()
+	token, err := e.tokens.tokens.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	return token
+}
+
+func TestTokenService_Create(t *testing.T) {
+	e := newAuthEnv(t, nil)
+	alice := createUser(t, e.users, "alice", "developer")
+	principal := newPrincipal(alice, auth.CredentialJWT, "", time.Time{})
+
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		scopes     []string
+		wantScopes []string
+		wantErr    error
+	}{
+		{name: "admin", principal: principal, scopes: []string{"admin"}, wantScopes: []string{"admin"}},
+		{name: "trimmed_and_deduplicated", principal: principal, scopes: []string{" repo:read ", "repo:read", "repo:write:libs-*"}, wantScopes: []string{"repo:read", "repo:write:libs-*"}},
+		{name: "invalid_scope", principal: principal, scopes: []string{"repo:delete"}, wantErr: errs.ErrInvalidArgument},
+		{name: "invalid_pattern", principal: principal, scopes: []string{"repo:read:[libs"}, wantErr: errs.ErrInvalidArgument},
+		{name: "anonymous", principal: auth.AnonymousPrincipal, scopes: []string{"admin"}, wantErr: errs.ErrUnauthenticated},
+		{name: "nil_principal", scopes: []string{"admin"}, wantErr: errs.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := e.tokens.Create(context.Background(), tt.principal, &dto.CreateAccessTokenRequest{Name: tt.name, Scopes: tt.scopes})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantScopes, created.Scopes)
+			assert.True(t, strings.HasPrefix(created.Token, accessTokenPrefix))
+			assert.Equal(t, created.Token[:accessTokenDisplayLength], created.Prefix)
+			assert.Nil(t, created.ExpiresAt)
+
+			stored := storedToken(t, e, created.ID)
+			assert.Equal(t, hashAccessToken(created.Token), stored.Token, "only the hash is stored")
+			assert.NotContains(t, stored.Token, created.Token)
+			assert.Equal(t, alice.ID, stored.UserID)
+		})
+	}
+
+	t.Run("expiry", func(t *testing.T) {
+		created, err := e.tokens.Create(context.Background(), principal, &dto.CreateAccessTokenRequest{Name: "short", Scopes: []string{"repo:read"}, ExpiresInDays: 7})
+		require.NoError(t, err)
+		require.NotNil(t, created.ExpiresAt)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *created.ExpiresAt, time.Minute)
+	})
+
+	t.Run("secrets_unique", func(t *testing.T) {
+		first := createToken(t, e, alice, "repo:read")
+		second := createToken(t, e, alice, "repo:read")
+		assert.NotEqual(t, first.Token, second.Token)
+	})
+}
+
+func TestTokenService_ListAndRevoke(t *testing.T) {
+	e := newAuthEnv(t, nil)
+	ctx := context.Background()
+	alice := createUser(t, e.users, "alice")
+	bob := createUser(t, e.users, "bob")
+	token := createToken(t, e, alice, "repo:read")
+	createToken(t, e, bob, "repo:read")
+
+	byName, err := e.tokens.List(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, byName, 1)
+	assert.Equal(t, token.ID, byName[0].ID)
+	byID, err := e.tokens.List(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Len(t, byID, 1)
+
+	_, err = e.tokens.List(ctx, "nobody")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+
+	assert.ErrorIs(t, e.tokens.Revoke(ctx, "bob", token.ID), errs.ErrNotFound, "tokens of other users are not visible")
+	assert.ErrorIs(t, e.tokens.Revoke(ctx, "alice", "missing"), errs.ErrNotFound)
+	require.NoError(t, e.tokens.Revoke(ctx, "alice", token.ID))
+
+	_, err = e.tokens.Authenticate(ctx, "", token.Token)
+	assert.ErrorIs(t, err, errs.ErrUnauthenticated, "revoked tokens are rejected")
+	remaining, err := e.tokens.List(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestTokenService_Authenticate(t *testing.T) {
+	e := newAuthEnv(t, nil)
+	ctx := context.Background()
+	alice := createUser(t, e.users, "alice", "developer")
+	locked := createUser(t, e.users, "locked")
+	active := createToken(t, e, alice, "repo:write:libs-release")
+	expired := createToken(t, e, alice, "repo:read")
+	require.NoError(t, e.db.Model(&model.AccessToken{}).Where("id = ?", expired.ID).
+		Update("expires_at", time.Now().Add(-time.Hour)).Error)
+	lockedToken := createToken(t, e, locked, "repo:read")
+	status := model.UserStatusLocked
+	_, err := e.users.Update(ctx, locked.ID, &dto.UpdateUserRequest{Status: &status})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		username string
+		secret   string
+		wantErr  error
+	}{
+		{name: "bearer", secret: active.Token},
+		{name: "basic_matching_user", username: "alice", secret: active.Token},
+		{name: "basic_other_user", username: "bob", secret: active.Token, wantErr: errs.ErrUnauthenticated},
+		{name: "unknown", secret: accessTokenPrefix + "unknown", wantErr: errs.ErrUnauthenticated},
+		{name: "expired", secret: expired.Token, wantErr: errs.ErrUnauthenticated},
+		{name: "locked_user", secret: lockedToken.Token, wantErr: errs.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := e.tokens.Authenticate(ctx, tt.username, tt.secret)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", principal.Username)
+			assert.Equal(t, auth.CredentialAccessToken, principal.Credential)
+			assert.Equal(t, active.ID, principal.TokenID)
+			assert.Equal(t, []string{"repo:write:libs-release"}, principal.Scopes)
+			assert.Contains(t, principal.Roles, "developer")
+			assert.True(t, principal.AllowsScope(auth.AccessWrite, "libs-release"))
+			assert.False(t, principal.AllowsScope(auth.AccessWrite, "libs-snapshot"))
+			assert.False(t, principal.AllowsScope(auth.AccessAdmin, ""))
+		})
+	}
+}
+
+func TestTokenService_LastUsedThrottled(t *testing.T) {
+	e := newAuthEnv(t, nil)
+	ctx := context.Background()
+	alice := createUser(t, e.users, "alice")
+	token := createToken(t, e, alice, "repo:read")
+	assert.Nil(t, storedToken(t, e, token.ID).LastUsed)
+
+	_, err := e.tokens.Authenticate(ctx, "", token.Token)
+	require.NoError(t, err)
+	first := storedToken(t, e, token.ID).LastUsed
+	require.NotNil(t, first)
+
+	_, err = e.tokens.Authenticate(ctx, "", token.Token)
+	require.NoError(t, err)
+	assert.True(t, first.Equal(*storedToken(t, e, token.ID).LastUsed), "uses within the interval are not written")
+
+	stale := time.Now().Add(-lastUsedInterval - time.Minute)
+	require.NoError(t, e.db.Model(&model.AccessToken{}).Where("id = ?", token.ID).Update("last_used", stale).Error)
+	_, err = e.tokens.Authenticate(ctx, "", token.Token)
+	require.NoError(t, err)
+	assert.True(t, storedToken(t, e, token.ID).LastUsed.After(stale.Add(time.Minute)))
+}
+
+func TestAuthService_AccessTokens(t *testing.T) {
+	e := newAuthEnv(t, nil)
+	ctx := context.Background()
+	alice := createUser(t, e.users, "alice")
+	token := createToken(t, e, alice, "repo:read")
+
+	t.Run("bearer", func(t *testing.T) {
+		principal, err := e.auth.Authenticate(ctx, token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, auth.CredentialAccessToken, principal.Credential)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			username string
+			password string
+			wantErr  bool
+		}{
+			{name: "token_password", username: "alice", password: token.Token},
+			{name: "account_password_rejected", username: "alice", password: testPassword, wantErr: true},
+			{name: "missing_username", password: token.Token, wantErr: true},
+			{name: "other_username", username: "admin", password: token.Token, wantErr: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				principal, err := e.auth.AuthenticateBasic(ctx, tt.username, tt.password)
+				if tt.wantErr {
+					assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, []string{"repo:read"}, principal.Scopes)
+			})
+		}
+	})
+
+	t.Run("password_accepts_token", func(t *testing.T) {
+		principal, err := e.auth.AuthenticatePassword(ctx, "alice", token.Token)
+		require.NoError(t, err)
+		assert.Equal(t, auth.CredentialAccessToken, principal.Credential)
+
+		principal, err = e.auth.AuthenticatePassword(ctx, "alice", testPassword)
+		require.NoError(t, err)
+		assert.Equal(t, auth.CredentialPassword, principal.Credential)
+		assert.Nil(t, principal.Scopes)
+	})
+}
@@ -0,0 +1,309 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// checksumAlgorithms 上传时支持校验的摘要算法
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Upload 上传制品到宿主仓库
+func (s *ArtifactServiceImpl) Upload(ctx context.Context, repoIDOrName string, req *dto.UploadArtifactRequest) (*model.Artifact, error) {
+	repo, formatPlugin, p, err := s.prepareUpload(ctx, repoIDOrName, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := s.lockStagingWrite(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if err := validatePropertyKeys(req.Properties); err != nil {
+		return nil, err
+	}
+	if err := verifyChecksums(req.Data, req.Checksums); err != nil {
+		return nil, err
+	}
+
+	// 随制品上传的签名保存为同目录的签名文件，扩展名按签名内容确定
+	var sigPath string
+	if req.Signature != nil {
+		if isSidecarPath(p) || isMetadataFile(formatPlugin, p) {
+			return nil, errs.InvalidArgument("signature can only be uploaded with an artifact, not with %q", p)
+		}
+		sigPath = p + signatureExtension(req.Signature)
+		if err := formatPlugin.ValidatePath(sigPath); err != nil {
+			return nil, errs.InvalidArgument("%s", err.Error())
+		}
+	}
+	unlockPaths, err := s.lockDeployment(ctx, repo, formatPlugin, p, sigPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockPaths()
+	if err := s.enforceSignaturePolicy(ctx, repo, formatPlugin, p, bytes.NewReader(req.Data), req.Signature); err != nil {
+		return nil, err
+	}
+
+	artifact, err := s.store(ctx, repo, formatPlugin, p, req.Data, req.Properties)
+	if err != nil || sigPath == "" {
+		return artifact, err
+	}
+	if _, err := s.store(ctx, repo, formatPlugin, sigPath, req.Signature, nil); err != nil {
+		return nil, err
+	}
+	// 签名文件提交时已重新校验并更新制品记录
+	return s.repository.FindByPath(ctx, repo.ID, p)
+}
+
+// store 写入存储并提交制品记录
+func (s *ArtifactServiceImpl) store(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string, data []byte, properties map[string]string) (*model.Artifact, error) {
+	if err := s.storage.Upload(ctx, storagePath(repo, p), data); err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return s.commitArtifact(ctx, repo, formatPlugin, p, &storedContent{
+		Size:     int64(len(data)),
+		Checksum: hex.EncodeToString(sum[:]),
+		Data:     data,
+	}, properties)
+}
+
+// storedContent 已写入存储的制品内容
+type storedContent struct {
+	Size     int64
+	Checksum string // sha256
+	Data     []byte // 用于解析元数据，为 nil 时不解析
+}
+
+// prepareUpload 校验上传目标：仓库必须是启用的宿主仓库，路径需符合格式要求
+func (s *ArtifactServiceImpl) prepareUpload(ctx context.Context, repoIDOrName, rawPath string) (*model.Repository, pluginapi.FormatPlugin, string, error) {
+	repo, err := lookupRepository(ctx, s.repositories, repoIDOrName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if repo.Type != model.RepositoryTypeHosted {
+		return nil, nil, "", errs.InvalidArgument("repository %q is a %s repository and does not accept uploads", repo.Name, repo.Type)
+	}
+	if repo.Status == "inactive" {
+		return nil, nil, "", errs.InvalidArgument("repository %q is inactive", repo.Name)
+	}
+	if err := checkStagingOpen(repo); err != nil {
+		return nil, nil, "", err
+	}
+	p, err := normalizePath(rawPath)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	formatPlugin, err := s.plugins.GetFormatPlugin(repo.Format)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if err := formatPlugin.ValidatePath(p); err != nil {
+		return nil, nil, "", errs.InvalidArgument("%s", err.Error())
+	}
+	if err := s.checkDeploymentPolicy(ctx, repo, formatPlugin, p); err != nil {
+		return nil, nil, "", err
+	}
+	return repo, formatPlugin, p, nil
+}
+
+// checkDeploymentPolicy 按仓库部署策略检查路径能否写入
+// 只读仓库拒绝所有写入；禁止重复部署的仓库拒绝覆盖已有制品，元数据文件及其附属文件每次发布都会更新，不受限制
+func (s *ArtifactServiceImpl) checkDeploymentPolicy(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string) error {
+	switch repo.DeploymentPolicy {
+	case model.DeploymentPolicyReadOnly:
+		return errs.Conflict("repository %q is read-only", repo.Name)
+	case model.DeploymentPolicyDisableRedeploy:
+		if isMetadataFile(formatPlugin, p) {
+			return nil
+		}
+		existing, err := s.repository.FindByPath(ctx, repo.ID, p)
+		if err != nil {
+			return fmt.Errorf("failed to find artifact: %w", err)
+		}
+		if existing != nil {
+			return errs.Conflict("artifact %q already exists in repository %q and redeploy is disabled", p, repo.Name)
+		}
+	}
+	return nil
+}
+
+// pathLock 制品路径锁，waiters 为持有或等待该锁的上传数
+type pathLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// lockDeployment 按路径顺序获取制品路径锁并在持锁后重新检查部署策略，返回释放函数
+// 部署策略检查、写入存储与保存记录期间持有，并发上传同一路径时后到者看到先到者的记录；空路径忽略
+func (s *ArtifactServiceImpl) lockDeployment(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, paths ...string) (func(), error) {
+	keys := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p != "" {
+			keys = append(keys, repo.ID+"/"+p)
+		}
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	unlocks := make([]func(), 0, len(keys))
+	unlock := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, key := range keys {
+		unlocks = append(unlocks, s.lockPath(key))
+	}
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if err := s.checkDeploymentPolicy(ctx, repo, formatPlugin, p); err != nil {
+			unlock()
+			return nil, err
+		}
+	}
+	return unlock, nil
+}
+
+// lockPath 获取单个路径锁，释放后没有其他上传等待时移除
+func (s *ArtifactServiceImpl) lockPath(key string) func() {
+	s.pathMutex.Lock()
+	if s.pathLocks == nil {
+		s.pathLocks = make(map[string]*pathLock)
+	}
+	lock, ok := s.pathLocks[key]
+	if !ok {
+		lock = &pathLock{}
+		s.pathLocks[key] = lock
+	}
+	lock.waiters++
+	s.pathMutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		s.pathMutex.Lock()
+		if lock.waiters--; lock.waiters == 0 {
+			delete(s.pathLocks, key)
+		}
+		s.pathMutex.Unlock()
+	}
+}
+
+// commitArtifact 内容写入存储后保存制品记录，并重新生成受影响的元数据文件
+func (s *ArtifactServiceImpl) commitArtifact(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string, content *storedContent, properties map[string]string) (*model.Artifact, error) {
+	artifact, err := s.saveArtifact(ctx, repo, formatPlugin, p, content, properties)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Artifact uploaded", "repository", repo.Name, "path", p, "size", artifact.Size)
+
+	s.recordSignature(ctx, repo, formatPlugin, p, artifact, content.Data)
+	s.refreshMetadataFor(ctx, repo, p)
+	s.publish(ctx, pluginapi.ArtifactEventUploaded, repo, artifact)
+	return artifact, nil
+}
+
+// saveArtifact 创建或覆盖制品记录，重复上传保留原记录ID、下载次数与已有属性
+func (s *ArtifactServiceImpl) saveArtifact(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string, content *storedContent, properties map[string]string) (*model.Artifact, error) {
+	existing, err := s.repository.FindByPath(ctx, repo.ID, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find artifact: %w", err)
+	}
+	artifact := existing
+	if artifact == nil {
+		artifact = &model.Artifact{
+			ID:           uuid.New().String(),
+			RepositoryID: repo.ID,
+			Path:         p,
+		}
+	}
+
+	artifact.Name, artifact.Version = path.Base(p), ""
+	artifact.Format = repo.Format
+	artifact.Size = content.Size
+	artifact.Checksum = content.Checksum
+	artifact.ContentType = contentTypeOf(repo.Format, p)
+	artifact.Metadata = nil
+	// 重复上传只覆盖随上传提供的属性，通过属性接口设置的其他属性保留
+	if len(properties) > 0 {
+		if artifact.Properties == nil {
+			artifact.Properties = make(map[string]string, len(properties))
+		}
+		maps.Copy(artifact.Properties, properties)
+	}
+
+	if locator, ok := formatPlugin.(pluginapi.ArtifactLocator); ok {
+		if name, version, ok := locator.Coordinates(p); ok {
+			artifact.Name, artifact.Version = name, version
+			if content.Data != nil {
+				if metadata, err := formatPlugin.ParseMetadata(ctx, content.Data); err == nil {
+					artifact.Metadata = flattenMetadata(metadata)
+				} else {
+					s.logger.Debug("No metadata parsed from artifact", "repository", repo.Name, "path", p, "error", err)
+				}
+			}
+		}
+	}
+
+	if existing != nil {
+		err = s.repository.Update(ctx, artifact)
+	} else {
+		err = s.repository.Create(ctx, artifact)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save artifact: %w", err)
+	}
+	s.index.Add(indexDocument(artifact))
+	return artifact, nil
+}
+
+// verifyChecksums 校验客户端提供的校验和，不一致时返回参数错误
+func verifyChecksums(data []byte, checksums map[string]string) error {
+	for algorithm, expected := range checksums {
+		expected = strings.ToLower(strings.TrimSpace(expected))
+		if expected == "" {
+			continue
+		}
+		newHash, ok := checksumAlgorithms[algorithm]
+		if !ok {
+			return errs.InvalidArgument("unsupported checksum algorithm: %s", algorithm)
+		}
+		h := newHash()
+		h.Write(data)
+		if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+			return errs.InvalidArgument("%s checksum mismatch: expected %s, got %s", algorithm, expected, actual)
+		}
+	}
+	return nil
+}
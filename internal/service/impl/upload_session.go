@@ -0,0 +1,410 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// maxMetadataParseSize 完成分块上传时解析格式元数据的最大文件大小，更大的文件不读回内存
+const maxMetadataParseSize = 32 << 20
+
+// defaultUploadExpiry 分块上传会话默认空闲过期时间
+const defaultUploadExpiry = 24 * time.Hour
+
+// UploadServiceImpl 分块上传服务实现
+type UploadServiceImpl struct {
+	logger    *slog.Logger
+	sessions  repository.UploadSessionRepository
+	storage   pluginapi.StoragePlugin
+	resumable pluginapi.ResumableStorage // 存储不支持续写时为 nil，此时不能创建会话
+	artifacts *ArtifactServiceImpl
+	expiry    time.Duration
+
+	// locks 每个会话一把锁，保证同一会话的分块按顺序写入
+	locks sync.Map
+}
+
+// NewUploadService 创建分块上传服务，并启动过期会话的定期清理，返回的函数用于停止清理
+func NewUploadService(
+	cfg *config.Config,
+	logger *slog.Logger,
+	sessions repository.UploadSessionRepository,
+	storage pluginapi.StoragePlugin,
+	artifacts *ArtifactServiceImpl,
+) (*UploadServiceImpl, func()) {
+	expiry := cfg.Storage.UploadExpiry
+	if expiry <= 0 {
+		expiry = defaultUploadExpiry
+	}
+	s := &UploadServiceImpl{
+		logger:    logger,
+		sessions:  sessions,
+		storage:   storage,
+		artifacts: artifacts,
+		expiry:    expiry,
+	}
+	s.resumable, _ = storage.(pluginapi.ResumableStorage)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.runJanitor(ctx)
+	return s, cancel
+}
+
+// CreateSession 创建上传会话，存储不支持续写时返回服务不可用
+func (s *UploadServiceImpl) CreateSession(ctx context.Context, repoIDOrName string, req *dto.CreateUploadSessionRequest) (*model.UploadSession, error) {
+	if s.resumable == nil {
+		return nil, errs.Unavailable("storage %q does not support chunked uploads", s.storage.Name())
+	}
+	repo, _, p, err := s.artifacts.prepareUpload(ctx, repoIDOrName, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePropertyKeys(req.Properties); err != nil {
+		return nil, err
+	}
+
+	state, err := marshalHashState(sha256.New())
+	if err != nil {
+		return nil, err
+	}
+	session := &model.UploadSession{
+		ID:           uuid.New().String(),
+		RepositoryID: repo.ID,
+		Path:         p,
+		Size:         req.Size,
+		HashState:    state,
+		Properties:   req.Properties,
+		ExpiresAt:    time.Now().Add(s.expiry),
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+
+	s.logger.Info("Upload session created", "id", session.ID, "repository", repo.Name, "path", p)
+	return session, nil
+}
+
+// GetSession 查询会话
+func (s *UploadServiceImpl) GetSession(ctx context.Context, repoIDOrName, sessionID string) (*model.UploadSession, error) {
+	_, session, err := s.lookupSession(ctx, repoIDOrName, sessionID)
+	return session, err
+}
+
+// WriteChunk 写入分块并推进偏移量，同时续算 sha256 并延长会话有效期
+func (s *UploadServiceImpl) WriteChunk(ctx context.Context, repoIDOrName, sessionID string, chunk *dto.UploadChunk) (*model.UploadSession, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	_, session, err := s.lookupSession(ctx, repoIDOrName, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := chunk.Start
+	if start < 0 {
+		start = session.Offset
+	}
+	if start != session.Offset {
+		return nil, errs.Conflict("chunk starts at %d but upload offset is %d", start, session.Offset)
+	}
+	if chunk.Total >= 0 {
+		if session.Size > 0 && chunk.Total != session.Size {
+			return nil, errs.InvalidArgument("total size %d does not match declared size %d", chunk.Total, session.Size)
+		}
+		session.Size = chunk.Total
+	}
+	end := start + int64(len(chunk.Data))
+	if session.Size > 0 && end > session.Size {
+		return nil, errs.InvalidArgument("chunk ends at %d beyond total size %d", end, session.Size)
+	}
+
+	h, err := unmarshalHashState(session.HashState)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resumable.WriteAt(ctx, partialPath(session), start, chunk.Data); err != nil {
+		return nil, fmt.Errorf("failed to write chunk: %w", err)
+	}
+	h.Write(chunk.Data)
+	if session.HashState, err = marshalHashState(h); err != nil {
+		return nil, err
+	}
+
+	session.Offset = end
+	session.ExpiresAt = time.Now().Add(s.expiry)
+	if err := s.sessions.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update upload session: %w", err)
+	}
+	return session, nil
+}
+
+// Complete 校验 sha256 摘要后将数据移动到制品路径并保存制品记录
+// 摘要格式为 sha256:<hex>，不一致时会话保留，客户端可以修正后重试或取消
+func (s *UploadServiceImpl) Complete(ctx context.Context, repoIDOrName, sessionID, digest string) (*model.Artifact, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	_, session, err := s.lookupSession(ctx, repoIDOrName, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Size > 0 && session.Offset != session.Size {
+		return nil, errs.InvalidArgument("upload incomplete: received %d of %d bytes", session.Offset, session.Size)
+	}
+
+	algorithm, expected, ok := strings.Cut(strings.TrimSpace(digest), ":")
+	if !ok || algorithm != "sha256" || expected == "" {
+		return nil, errs.InvalidArgument("digest must be in the form sha256:<hex>")
+	}
+	h, err := unmarshalHashState(session.HashState)
+	if err != nil {
+		return nil, err
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if strings.ToLower(expected) != actual {
+		return nil, errs.InvalidArgument("sha256 digest mismatch: expected %s, got %s", expected, actual)
+	}
+
+	// 仓库可能在上传期间被修改，完成时重新校验
+	repo, formatPlugin, p, err := s.artifacts.prepareUpload(ctx, session.RepositoryID, session.Path)
+	if err != nil {
+		return nil, err
+	}
+	unlockStaging, err := s.artifacts.lockStagingWrite(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockStaging()
+	unlockPath, err := s.artifacts.lockDeployment(ctx, repo, formatPlugin, p)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockPath()
+	if repo.SignaturePolicy.Required {
+		if err := s.enforceSignaturePolicy(ctx, repo, formatPlugin, p, session); err != nil {
+			return nil, err
+		}
+	}
+
+	if session.Offset == 0 {
+		err = s.storage.Upload(ctx, storagePath(repo, p), []byte{})
+	} else {
+		err = s.resumable.Move(ctx, partialPath(session), storagePath(repo, p))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	content := &storedContent{Size: session.Offset, Checksum: actual}
+	if session.Offset <= maxMetadataParseSize {
+		if content.Data, err = s.storage.Download(ctx, storagePath(repo, p)); err != nil {
+			return nil, fmt.Errorf("failed to read artifact: %w", err)
+		}
+	}
+	artifact, err := s.artifacts.commitArtifact(ctx, repo, formatPlugin, p, content, session.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessions.Delete(ctx, session.ID); err != nil {
+		s.logger.Warn("Failed to delete completed upload session", "id", session.ID, "error", err)
+	}
+	s.locks.Delete(session.ID)
+	return artifact, nil
+}
+
+// enforceSignaturePolicy 从存储流式读取已接收的数据校验签名策略，不将整个文件读入内存
+func (s *UploadServiceImpl) enforceSignaturePolicy(ctx context.Context, repo *model.Repository, formatPlugin pluginapi.FormatPlugin, p string, session *model.UploadSession) error {
+	if session.Offset == 0 {
+		return s.artifacts.enforceSignaturePolicy(ctx, repo, formatPlugin, p, bytes.NewReader(nil), nil)
+	}
+	content, err := s.resumable.Open(ctx, partialPath(session))
+	if err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	defer content.Close()
+	return s.artifacts.enforceSignaturePolicy(ctx, repo, formatPlugin, p, content, nil)
+}
+
+// Cancel 取消会话
+func (s *UploadServiceImpl) Cancel(ctx context.Context, repoIDOrName, sessionID string) error {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	_, session, err := s.lookupSession(ctx, repoIDOrName, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := s.discard(ctx, session); err != nil {
+		return err
+	}
+	s.logger.Info("Upload session cancelled", "id", session.ID)
+	return nil
+}
+
+// CleanupExpired 清理过期会话及其未完成的数据
+// 与 WriteChunk、Complete 持有同一把会话锁，加锁后重新读取会话，期间被续期或已完成的会话不清理
+func (s *UploadServiceImpl) CleanupExpired(ctx context.Context) (int, error) {
+	sessions, err := s.sessions.ListExpired(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired upload sessions: %w", err)
+	}
+
+	cleaned := 0
+	for _, expired := range sessions {
+		ok, err := s.cleanupSession(ctx, expired.ID)
+		if err != nil {
+			s.logger.Warn("Failed to clean up upload session", "id", expired.ID, "error", err)
+			continue
+		}
+		if ok {
+			cleaned++
+		}
+	}
+	if cleaned > 0 {
+		s.logger.Info("Expired upload sessions cleaned up", "count", cleaned)
+	}
+	return cleaned, nil
+}
+
+// cleanupSession 在会话锁内确认会话仍已过期后删除，返回是否删除
+func (s *UploadServiceImpl) cleanupSession(ctx context.Context, sessionID string) (bool, error) {
+	unlock := s.lock(sessionID)
+	defer unlock()
+
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find upload session: %w", err)
+	}
+	if session == nil || !time.Now().After(session.ExpiresAt) {
+		return false, nil
+	}
+	return true, s.discard(ctx, session)
+}
+
+// runJanitor 定期清理过期会话，直到 ctx 取消
+func (s *UploadServiceImpl) runJanitor(ctx context.Context) {
+	interval := s.expiry / 4
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CleanupExpired(ctx); err != nil {
+				s.logger.Error("Upload session cleanup failed", "error", err)
+			}
+		}
+	}
+}
+
+// discard 删除会话未完成的数据与会话记录
+func (s *UploadServiceImpl) discard(ctx context.Context, session *model.UploadSession) error {
+	if err := s.storage.Delete(ctx, partialPath(session)); err != nil {
+		return fmt.Errorf("failed to delete partial upload: %w", err)
+	}
+	if err := s.sessions.Delete(ctx, session.ID); err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+	s.locks.Delete(session.ID)
+	return nil
+}
+
+// lookupSession 查找属于指定仓库且未过期的会话
+func (s *UploadServiceImpl) lookupSession(ctx context.Context, repoIDOrName, sessionID string) (*model.Repository, *model.UploadSession, error) {
+	repo, err := lookupRepository(ctx, s.artifacts.repositories, repoIDOrName)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find upload session: %w", err)
+	}
+	if session == nil || session.RepositoryID != repo.ID || time.Now().After(session.ExpiresAt) {
+		return nil, nil, errs.NotFound("upload session %q not found", sessionID)
+	}
+	return repo, session, nil
+}
+
+// lock 获取会话锁，返回解锁函数
+func (s *UploadServiceImpl) lock(sessionID string) func() {
+	value, _ := s.locks.LoadOrStore(sessionID, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+// partialPath 会话未完成数据在存储后端中的路径
+func partialPath(session *model.UploadSession) string {
+	return "uploads/" + session.ID
+}
+
+// move 在存储中移动文件，存储不支持移动时复制后删除源文件
+func move(ctx context.Context, store pluginapi.StoragePlugin, from, to string) error {
+	if resumable, ok := store.(pluginapi.ResumableStorage); ok {
+		return resumable.Move(ctx, from, to)
+	}
+
+	data, err := store.Download(ctx, from)
+	if err != nil {
+		return err
+	}
+	if err := store.Upload(ctx, to, data); err != nil {
+		return err
+	}
+	return store.Delete(ctx, from)
+}
+
+// openContent 打开存储中的文件用于流式读取，存储不支持流式读取时整体读出
+func openContent(ctx context.Context, store pluginapi.StoragePlugin, path string) (io.ReadCloser, error) {
+	if resumable, ok := store.(pluginapi.ResumableStorage); ok {
+		return resumable.Open(ctx, path)
+	}
+	data, err := store.Download(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// marshalHashState 序列化摘要的中间状态，以便跨请求续算
+func marshalHashState(h hash.Hash) ([]byte, error) {
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save digest state: %w", err)
+	}
+	return state, nil
+}
+
+// unmarshalHashState 恢复 sha256 摘要的中间状态
+func unmarshalHashState(state []byte) (hash.Hash, error) {
+	h := sha256.New()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		return nil, fmt.Errorf("failed to restore digest state: %w", err)
+	}
+	return h, nil
+}
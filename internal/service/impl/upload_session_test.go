@@ -0,0 +1,234 @@
+package impl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/dao"
+	repoimpl "github.com/laolishu/go-nexus/internal/repository/impl"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	pluginapi "github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// newUploadService 基于测试环境创建分块上传服务，store 为 nil 时使用环境的存储
+func newUploadService(t *testing.T, env *testEnv, store pluginapi.StoragePlugin) *UploadServiceImpl {
+	t.Helper()
+	if store == nil {
+		store = env.storage
+	}
+	sessions := repoimpl.NewUploadSessionRepository(env.logger, dao.NewUploadSessionDAO(env.logger, env.db))
+	service, stop := NewUploadService(env.cfg, env.logger, sessions, store, env.artifacts)
+	t.Cleanup(stop)
+	return service
+}
+
+// plainStorage 只暴露 StoragePlugin 接口，模拟不支持续写的存储
+type plainStorage struct {
+	pluginapi.StoragePlugin
+}
+
+// trustCosignKey 生成 ECDSA 密钥并登记为受信任的 cosign 公钥
+func trustCosignKey(t *testing.T, env *testEnv, name string) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, env.keyRepo.Create(context.Background(), &model.TrustedKey{
+		ID:        name,
+		Name:      name,
+		Type:      model.TrustedKeyTypeCosign,
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}))
+	return key
+}
+
+// cosignSign 生成 cosign sign-blob 格式的 base64 签名
+func cosignSign(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+	sum := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	require.NoError(t, err)
+	return []byte(base64.StdEncoding.EncodeToString(sig))
+}
+
+func TestUploadService_ResumableUpload(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	uploads := newUploadService(t, env, nil)
+	ctx := context.Background()
+	data := []byte("0123456789")
+
+	session, err := uploads.CreateSession(ctx, "releases", &dto.CreateUploadSessionRequest{
+		Path: "com/x/lib/1.0/lib-1.0.jar", Size: int64(len(data)), Properties: map[string]string{"team": "core"},
+	})
+	require.NoError(t, err)
+
+	_, err = uploads.WriteChunk(ctx, "releases", session.ID, &dto.UploadChunk{Start: 0, Total: -1, Data: data[:4]})
+	require.NoError(t, err)
+
+	t.Run("offset_mismatch_conflicts", func(t *testing.T) {
+		_, err := uploads.WriteChunk(ctx, "releases", session.ID, &dto.UploadChunk{Start: 2, Total: -1, Data: data[2:]})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+	t.Run("chunk_beyond_declared_size", func(t *testing.T) {
+		_, err := uploads.WriteChunk(ctx, "releases", session.ID, &dto.UploadChunk{Start: -1, Total: -1, Data: append(data[4:], 'x')})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+	t.Run("incomplete_upload", func(t *testing.T) {
+		_, err := uploads.Complete(ctx, "releases", session.ID, "sha256:"+sha256Hex(data))
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	// 断线后查询偏移量并续传
+	resumed, err := uploads.GetSession(ctx, "releases", session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), resumed.Offset)
+	_, err = uploads.WriteChunk(ctx, "releases", session.ID, &dto.UploadChunk{Start: -1, Total: int64(len(data)), Data: data[4:]})
+	require.NoError(t, err)
+
+	t.Run("digest_mismatch_keeps_session", func(t *testing.T) {
+		_, err := uploads.Complete(ctx, "releases", session.ID, "sha256:"+sha256Hex([]byte("other")))
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+		_, err = uploads.GetSession(ctx, "releases", session.ID)
+		assert.NoError(t, err)
+	})
+	t.Run("invalid_digest_format", func(t *testing.T) {
+		_, err := uploads.Complete(ctx, "releases", session.ID, "md5:abc")
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	artifact, err := uploads.Complete(ctx, "releases", session.ID, "sha256:"+sha256Hex(data))
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex(data), artifact.Checksum)
+	assert.Equal(t, map[string]string{"team": "core"}, artifact.Properties)
+	stored, err := env.download("releases", "com/x/lib/1.0/lib-1.0.jar")
+	require.NoError(t, err)
+	assert.Equal(t, data, stored)
+
+	_, err = uploads.GetSession(ctx, "releases", session.ID)
+	assert.ErrorIs(t, err, errs.ErrNotFound, "completed session is removed")
+}
+
+// 部署策略在创建会话与完成上传时都会检查，上传期间仓库改为只读时完成失败
+func TestUploadService_DeploymentPolicy(t *testing.T) {
+	env := newTestEnv(t)
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "immutable", Type: model.RepositoryTypeHosted, Format: "maven", DeploymentPolicy: model.DeploymentPolicyDisableRedeploy})
+	env.upload(t, "immutable", "com/x/lib/1.0/lib-1.0.jar", []byte("old"))
+	uploads := newUploadService(t, env, nil)
+	ctx := context.Background()
+
+	_, err := uploads.CreateSession(ctx, "immutable", &dto.CreateUploadSessionRequest{Path: "com/x/lib/1.0/lib-1.0.jar"})
+	assert.ErrorIs(t, err, errs.ErrConflict)
+
+	session, err := uploads.CreateSession(ctx, "immutable", &dto.CreateUploadSessionRequest{Path: "com/x/lib/2.0/lib-2.0.jar"})
+	require.NoError(t, err)
+	_, err = uploads.WriteChunk(ctx, "immutable", session.ID, &dto.UploadChunk{Start: 0, Total: 3, Data: []byte("new")})
+	require.NoError(t, err)
+	_, err = env.repositories.Update(ctx, "immutable", &dto.UpdateRepositoryRequest{DeploymentPolicy: model.DeploymentPolicyReadOnly})
+	require.NoError(t, err)
+
+	_, err = uploads.Complete(ctx, "immutable", session.ID, "sha256:"+sha256Hex([]byte("new")))
+	assert.ErrorIs(t, err, errs.ErrConflict)
+	_, err = env.download("immutable", "com/x/lib/2.0/lib-2.0.jar")
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestUploadService_RequiresResumableStorage(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	uploads := newUploadService(t, env, plainStorage{env.storage})
+
+	_, err := uploads.CreateSession(context.Background(), "releases", &dto.CreateUploadSessionRequest{Path: "com/x/lib/1.0/lib-1.0.jar"})
+	assert.ErrorIs(t, err, errs.ErrUnavailable)
+}
+
+// 签名策略从存储流式读取已接收的数据校验签名
+func TestUploadService_CompleteEnforcesSignaturePolicy(t *testing.T) {
+	env := newTestEnv(t)
+	key := trustCosignKey(t, env, "release-key")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "signed", Type: model.RepositoryTypeHosted, Format: "maven",
+		SignaturePolicy: model.SignaturePolicy{Required: true}})
+	uploads := newUploadService(t, env, nil)
+	ctx := context.Background()
+
+	upload := func(p string, data []byte) error {
+		session, err := uploads.CreateSession(ctx, "signed", &dto.CreateUploadSessionRequest{Path: p})
+		require.NoError(t, err)
+		_, err = uploads.WriteChunk(ctx, "signed", session.ID, &dto.UploadChunk{Start: -1, Total: -1, Data: data})
+		require.NoError(t, err)
+		_, err = uploads.Complete(ctx, "signed", session.ID, "sha256:"+sha256Hex(data))
+		return err
+	}
+
+	data := []byte("signed jar")
+	assert.ErrorIs(t, upload("com/x/lib/1.0/lib-1.0.jar", data), errs.ErrInvalidArgument, "signature file missing")
+
+	env.upload(t, "signed", "com/x/lib/1.0/lib-1.0.jar.sig", cosignSign(t, key, data))
+	assert.ErrorIs(t, upload("com/x/lib/1.0/lib-1.0.jar", []byte("tampered")), errs.ErrInvalidArgument)
+	require.NoError(t, upload("com/x/lib/1.0/lib-1.0.jar", data))
+}
+
+func TestUploadService_CleanupExpired(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	uploads := newUploadService(t, env, nil)
+	ctx := context.Background()
+
+	create := func(p string) *model.UploadSession {
+		session, err := uploads.CreateSession(ctx, "releases", &dto.CreateUploadSessionRequest{Path: p})
+		require.NoError(t, err)
+		_, err = uploads.WriteChunk(ctx, "releases", session.ID, &dto.UploadChunk{Start: -1, Total: -1, Data: []byte("partial")})
+		require.NoError(t, err)
+		return session
+	}
+	expire := func(session *model.UploadSession) {
+		require.NoError(t, env.db.Model(&model.UploadSession{}).Where("id = ?", session.ID).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	}
+
+	expired := create("com/x/a/1.0/a-1.0.jar")
+	active := create("com/x/b/1.0/b-1.0.jar")
+	expire(expired)
+
+	cleaned, err := uploads.CleanupExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cleaned)
+	exists, err := env.storage.Exists(ctx, partialPath(expired))
+	require.NoError(t, err)
+	assert.False(t, exists, "partial data is deleted")
+	_, err = uploads.GetSession(ctx, "releases", active.ID)
+	assert.NoError(t, err)
+
+	t.Run("waits_for_the_session_lock", func(t *testing.T) {
+		expire(active)
+		unlock := uploads.lock(active.ID)
+		done := make(chan int, 1)
+		go func() {
+			n, _ := uploads.CleanupExpired(ctx)
+			done <- n
+		}()
+		assert.Never(t, func() bool { return len(done) > 0 }, 50*time.Millisecond, 5*time.Millisecond)
+
+		// 持锁期间会话被续期，清理加锁后重新读取，不再删除
+		require.NoError(t, env.db.Model(&model.UploadSession{}).Where("id = ?", active.ID).
+			Update("expires_at", time.Now().Add(time.Hour)).Error)
+		unlock()
+		assert.Equal(t, 0, <-done)
+		_, err := uploads.GetSession(ctx, "releases", active.ID)
+		assert.NoError(t, err)
+	})
+}
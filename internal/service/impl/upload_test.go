@@ -0,0 +1,250 @@
+package impl
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+func sha1Hex(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	data := []byte("artifact")
+	tests := []struct {
+		name      string
+		checksums map[string]string
+		wantErr   bool
+	}{
+		{name: "none"},
+		{name: "match", checksums: map[string]string{"sha1": sha1Hex(data), "sha256": sha256Hex(data)}},
+		{name: "case_and_space_insensitive", checksums: map[string]string{"sha256": " " + strings.ToUpper(sha256Hex(data)) + " "}},
+		{name: "empty_value_ignored", checksums: map[string]string{"md5": ""}},
+		{name: "mismatch", checksums: map[string]string{"sha1": sha1Hex([]byte("other"))}, wantErr: true},
+		{name: "unsupported_algorithm", checksums: map[string]string{"crc32": "00"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChecksums(data, tt.checksums)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestArtifactService_Upload(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "central", Type: model.RepositoryTypeProxy, Format: "maven", URL: "http://127.0.0.1:1/"})
+	data := []byte("jar")
+
+	t.Run("stores_data_properties_and_checksum", func(t *testing.T) {
+		artifact, err := env.artifacts.Upload(context.Background(), "releases", &dto.UploadArtifactRequest{
+			Path:       "/com/x/lib/1.0/lib-1.0.jar",
+			Data:       data,
+			Properties: map[string]string{"team": "core"},
+			Checksums:  map[string]string{"sha256": sha256Hex(data)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "com/x/lib/1.0/lib-1.0.jar", artifact.Path)
+		assert.Equal(t, sha256Hex(data), artifact.Checksum)
+		assert.Equal(t, int64(len(data)), artifact.Size)
+		assert.Equal(t, map[string]string{"team": "core"}, artifact.Properties)
+
+		stored, err := env.download("releases", "com/x/lib/1.0/lib-1.0.jar")
+		require.NoError(t, err)
+		assert.Equal(t, data, stored)
+	})
+
+	tests := []struct {
+		name    string
+		repo    string
+		req     *dto.UploadArtifactRequest
+		wantErr error
+	}{
+		{name: "checksum_mismatch", repo: "releases", req: &dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar", Data: data, Checksums: map[string]string{"sha1": sha1Hex([]byte("x"))}}, wantErr: errs.ErrInvalidArgument},
+		{name: "proxy_repository", repo: "central", req: &dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar", Data: data}, wantErr: errs.ErrInvalidArgument},
+		{name: "missing_repository", repo: "nope", req: &dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar", Data: data}, wantErr: errs.ErrNotFound},
+		{name: "invalid_path", repo: "releases", req: &dto.UploadArtifactRequest{Path: "../lib-2.0.jar", Data: data}, wantErr: errs.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.artifacts.Upload(context.Background(), tt.repo, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.repo == "releases" {
+				_, err := env.download("releases", "com/x/lib/2.0/lib-2.0.jar")
+				assert.ErrorIs(t, err, errs.ErrNotFound, "rejected upload must not be stored")
+			}
+		})
+	}
+}
+
+func TestArtifactService_DeploymentPolicy(t *testing.T) {
+	const (
+		jar      = "com/x/lib/1.0/lib-1.0.jar"
+		metadata = "com/x/lib/maven-metadata.xml"
+	)
+	signature := []byte("-----BEGIN PGP SIGNATURE-----\n\n-----END PGP SIGNATURE-----\n")
+
+	tests := []struct {
+		name   string
+		policy string
+		path   string
+		req    dto.UploadArtifactRequest
+		want   error
+	}{
+		{name: "allow_redeploy_overwrites", policy: model.DeploymentPolicyAllowRedeploy, req: dto.UploadArtifactRequest{Path: jar}},
+		{name: "default_allows_redeploy", req: dto.UploadArtifactRequest{Path: jar}},
+		{name: "disable_redeploy_new_path", policy: model.DeploymentPolicyDisableRedeploy, req: dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar"}},
+		{name: "disable_redeploy_overwrite", policy: model.DeploymentPolicyDisableRedeploy, req: dto.UploadArtifactRequest{Path: jar}, want: errs.ErrConflict},
+		{name: "disable_redeploy_metadata", policy: model.DeploymentPolicyDisableRedeploy, req: dto.UploadArtifactRequest{Path: metadata}},
+		{name: "disable_redeploy_existing_signature", policy: model.DeploymentPolicyDisableRedeploy,
+			req: dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar", Signature: signature}, want: errs.ErrConflict},
+		{name: "read_only_new_path", policy: model.DeploymentPolicyReadOnly, req: dto.UploadArtifactRequest{Path: "com/x/lib/2.0/lib-2.0.jar"}, want: errs.ErrConflict},
+		{name: "read_only_metadata", policy: model.DeploymentPolicyReadOnly, req: dto.UploadArtifactRequest{Path: metadata}, want: errs.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.hosted(t, "releases", "maven")
+			env.upload(t, "releases", jar, []byte("old"))
+			env.upload(t, "releases", "com/x/lib/2.0/lib-2.0.jar.asc", signature)
+			if tt.policy != "" {
+				_, err := env.repositories.Update(context.Background(), "releases", &dto.UpdateRepositoryRequest{DeploymentPolicy: tt.policy})
+				require.NoError(t, err)
+			}
+
+			req := tt.req
+			req.Data = []byte("new")
+			_, err := env.artifacts.Upload(context.Background(), "releases", &req)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				data, err := env.download("releases", jar)
+				require.NoError(t, err)
+				assert.Equal(t, "old", string(data), "rejected upload must not change content")
+				return
+			}
+			require.NoError(t, err)
+			data, err := env.download("releases", req.Path)
+			require.NoError(t, err)
+			assert.Equal(t, "new", string(data))
+		})
+	}
+}
+
+func TestArtifactService_MetadataVersionOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		uploads []string
+	}{
+		{name: "ascending", uploads: []string{"1.0", "1.10", "2.0"}},
+		{name: "older_version_uploaded_last", uploads: []string{"2.0", "1.10", "1.0"}},
+		{name: "mixed", uploads: []string{"1.10", "2.0", "1.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.hosted(t, "releases", "maven")
+			for _, v := range tt.uploads {
+				env.upload(t, "releases", "com/x/lib/"+v+"/lib-"+v+".pom", pomOf("com.x", "lib", v, "lib"))
+			}
+
+			data, err := env.download("releases", "com/x/lib/maven-metadata.xml")
+			require.NoError(t, err)
+			var doc struct {
+				Latest   string   `xml:"versioning>latest"`
+				Release  string   `xml:"versioning>release"`
+				Versions []string `xml:"versioning>versions>version"`
+			}
+			require.NoError(t, xml.Unmarshal(data, &doc))
+			assert.Equal(t, []string{"1.0", "1.10", "2.0"}, doc.Versions)
+			assert.Equal(t, "2.0", doc.Latest)
+			assert.Equal(t, "2.0", doc.Release)
+		})
+	}
+}
+
+func TestArtifactService_RedeployMergesProperties(t *testing.T) {
+	env := newTestEnv(t)
+	env.hosted(t, "releases", "maven")
+	ctx := context.Background()
+	path := "com/x/lib/1.0/lib-1.0.jar"
+	upload := func(properties map[string]string) *model.Artifact {
+		artifact, err := env.artifacts.Upload(ctx, "releases", &dto.UploadArtifactRequest{Path: path, Data: []byte("jar"), Properties: properties})
+		require.NoError(t, err)
+		return artifact
+	}
+
+	upload(map[string]string{"qa": "passed", "build.number": "42"})
+	assert.Equal(t, map[string]string{"qa": "passed", "build.number": "42"}, upload(nil).Properties, "a redeploy without properties keeps them")
+	assert.Equal(t, map[string]string{"qa": "passed", "build.number": "43"}, upload(map[string]string{"build.number": "43"}).Properties, "uploaded properties override only their keys")
+}
+
+func TestArtifactService_DisableRedeployConcurrentUploads(t *testing.T) {
+	const (
+		jar     = "com/x/lib/1.0/lib-1.0.jar"
+		uploads = 8
+	)
+	env := newTestEnv(t)
+	env.createRepo(t, &dto.CreateRepositoryRequest{Name: "releases", Type: model.RepositoryTypeHosted, Format: "maven",
+		DeploymentPolicy: model.DeploymentPolicyDisableRedeploy})
+
+	var wg sync.WaitGroup
+	results := make([]error, uploads)
+	for i := 0; i < uploads; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, results[i] = env.artifacts.Upload(context.Background(), "releases", &dto.UploadArtifactRequest{Path: jar, Data: []byte(fmt.Sprintf("build-%d", i))})
+		}()
+	}
+	wg.Wait()
+
+	succeeded := -1
+	for i, err := range results {
+		if err == nil {
+			assert.Equal(t, -1, succeeded, "only one upload may deploy the path")
+			succeeded = i
+			continue
+		}
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	}
+	require.NotEqual(t, -1, succeeded)
+
+	// 存储内容与唯一的制品记录一致
+	repo, err := env.repos.FindByName(context.Background(), "releases")
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, env.db.Model(&model.Artifact{}).Where("repository_id = ? AND path = ?", repo.ID, jar).Count(&count).Error)
+	assert.EqualValues(t, 1, count)
+	data, err := env.download("releases", jar)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("build-%d", succeeded), string(data))
+	artifact, err := env.artifactRepo.FindByPath(context.Background(), repo.ID, jar)
+	require.NoError(t, err)
+	assert.Equal(t, sha256Hex(data), artifact.Checksum)
+}
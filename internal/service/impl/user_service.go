@@ -0,0 +1,694 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+	"github.com/laolishu/go-nexus/pkg/config"
+)
+
+const (
+	// minPasswordLength 密码最小长度
+	minPasswordLength = 8
+	// maxPasswordLength bcrypt 只使用前 72 字节，超出部分会被忽略
+	maxPasswordLength = 72
+	// initialAdminUsername 首次启动时创建的管理员用户名
+	initialAdminUsername = "admin"
+)
+
+// usernamePattern 用户名格式
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]{0,49}$`)
+
+// roleNamePattern 角色名称格式
+var roleNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,49}$`)
+
+// builtinRoles 启动时创建的内置角色，已存在时保持管理员修改后的权限
+var builtinRoles = []*model.Role{
+	{Name: model.RoleAdmin, Description: "Built-in administrator role", Permissions: []string{auth.PermissionAll}},
+	{Name: model.RoleDeveloper, Description: "Read and write all repositories", Permissions: []string{
+		auth.RepositoryPermission("*", "*", auth.ActionWrite),
+	}},
+	{Name: model.RoleViewer, Description: "Read all repositories", Permissions: []string{
+		auth.RepositoryPermission("*", "*", auth.ActionRead),
+	}},
+	{Name: model.RoleAnonymous, Description: "Permissions of unauthenticated requests when anonymous read is enabled", Permissions: []string{
+		auth.RepositoryPermission("*", "*", auth.ActionRead),
+	}},
+}
+
+// dummyPasswordHash 用户不存在时用于比较的哈希，使响应时间与密码错误时一致
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("go-nexus-dummy-password"), bcrypt.DefaultCost)
+
+// UserServiceImpl 用户服务实现
+type UserServiceImpl struct {
+	logger        *slog.Logger
+	users         repository.UserRepository
+	roles         repository.RoleRepository
+	adminPassword string
+}
+
+// NewUserService 创建新的用户服务实现
+func NewUserService(
+	cfg *config.Config,
+	logger *slog.Logger,
+	users repository.UserRepository,
+	roles repository.RoleRepository,
+) *UserServiceImpl {
+	return &UserServiceImpl{
+		logger:        logger,
+		users:         users,
+		roles:         roles,
+		adminPassword: cfg.Security.AdminPassword,
+	}
+}
+
+// Bootstrap 创建缺失的内置角色，数据库中没有用户时创建初始管理员
+func (s *UserServiceImpl) Bootstrap(ctx context.Context) error {
+	var role *model.Role
+	for _, builtin := range builtinRoles {
+		existing, err := s.roles.FindByName(ctx, builtin.Name)
+		if err != nil {
+			return fmt.Errorf("failed to find role: %w", err)
+		}
+		if existing == nil {
+			existing = &model.Role{
+				ID:          uuid.New().String(),
+				Name:        builtin.Name,
+				Description: builtin.Description,
+				Permissions: builtin.Permissions,
+			}
+			if err := s.roles.Create(ctx, existing); err != nil {
+				return fmt.Errorf("failed to create role %q: %w", builtin.Name, err)
+			}
+			s.logger.Info("Built-in role created", "role", builtin.Name)
+		}
+		if builtin.Name == model.RoleAdmin {
+			role = existing
+		}
+	}
+
+	count, err := s.users.Count(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	password := s.adminPassword
+	generated := password == ""
+	if generated {
+		if password, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	admin := &model.User{
+		ID:       uuid.New().String(),
+		Username: initialAdminUsername,
+		Email:    initialAdminUsername + "@localhost",
+		Password: hash,
+		FullName: "Administrator",
+		Status:   model.UserStatusActive,
+		Realm:    model.UserRealmLocal,
+	}
+	if err := s.users.Create(ctx, admin); err != nil {
+		return fmt.Errorf("failed to create initial admin: %w", err)
+	}
+	if err := s.users.ReplaceRoles(ctx, admin, []model.Role{*role}); err != nil {
+		return fmt.Errorf("failed to assign admin role: %w", err)
+	}
+	if generated {
+		s.logger.Warn("Initial admin user created with a generated password, change it after the first login",
+			"username", admin.Username, "password", password)
+	} else {
+		s.logger.Info("Initial admin user created", "username", admin.Username)
+	}
+	return nil
+}
+
+// Create 创建用户
+func (s *UserServiceImpl) Create(ctx context.Context, req *dto.CreateUserRequest) (*model.User, error) {
+	if !usernamePattern.MatchString(req.Username) {
+		return nil, errs.InvalidArgument("invalid username %q", req.Username)
+	}
+	existing, err := s.users.FindByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("user %q already exists", req.Username)
+	}
+	if err := s.checkEmail(ctx, req.Email, ""); err != nil {
+		return nil, err
+	}
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.resolveRoles(ctx, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	status := req.Status
+	if status == "" {
+		status = model.UserStatusActive
+	}
+	user := &model.User{
+		ID:       uuid.New().String(),
+		Username: req.Username,
+		Email:    req.Email,
+		Password: hash,
+		FullName: req.FullName,
+		Status:   status,
+		Realm:    model.UserRealmLocal,
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	if len(roles) > 0 {
+		if err := s.users.ReplaceRoles(ctx, user, roles); err != nil {
+			return nil, fmt.Errorf("failed to assign roles: %w", err)
+		}
+	}
+	s.logger.Info("User created", "username", user.Username, "roles", req.Roles)
+	return s.Get(ctx, user.ID)
+}
+
+// Get 根据ID或用户名获取用户
+func (s *UserServiceImpl) Get(ctx context.Context, id string) (*model.User, error) {
+	return s.lookupUser(ctx, id)
+}
+
+// List 列出用户
+func (s *UserServiceImpl) List(ctx context.Context, query *dto.ListUsersQuery) ([]*model.User, error) {
+	users, err := s.users.List(ctx, query.Status, strings.TrimSpace(query.Q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+// Update 更新用户信息、状态或重置密码，不能停用或锁定最后一个可用的管理员
+func (s *UserServiceImpl) Update(ctx context.Context, id string, req *dto.UpdateUserRequest) (*model.User, error) {
+	user, err := s.lookupUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Email != nil && *req.Email != user.Email {
+		if err := s.checkEmail(ctx, *req.Email, user.ID); err != nil {
+			return nil, err
+		}
+		user.Email = *req.Email
+	}
+	if req.FullName != nil {
+		user.FullName = *req.FullName
+	}
+	if req.Status != nil && *req.Status != user.Status {
+		if *req.Status != model.UserStatusActive {
+			if err := s.checkLastAdmin(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+		user.Status = *req.Status
+	}
+	if req.Password != nil {
+		if err := checkLocalUser(user); err != nil {
+			return nil, err
+		}
+		hash, err := hashPassword(*req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hash
+	}
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	s.logger.Info("User updated", "username", user.Username, "status", user.Status, "password_reset", req.Password != nil)
+	return user, nil
+}
+
+// Delete 删除用户，不能删除最后一个可用的管理员
+func (s *UserServiceImpl) Delete(ctx context.Context, id string) error {
+	user, err := s.lookupUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkLastAdmin(ctx, user); err != nil {
+		return err
+	}
+	if err := s.users.Delete(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	s.logger.Info("User deleted", "username", user.Username)
+	return nil
+}
+
+// ChangePassword 校验当前密码后修改密码
+func (s *UserServiceImpl) ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordRequest) error {
+	user, err := s.lookupUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkLocalUser(user); err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)) != nil {
+		return errs.Forbidden("current password is incorrect")
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return errs.InvalidArgument("new password must differ from the current password")
+	}
+	hash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdateColumns(ctx, user.ID, map[string]interface{}{"password": hash}); err != nil {
+		return fmt.Errorf("failed to change password: %w", err)
+	}
+	s.logger.Info("User password changed", "username", user.Username)
+	return nil
+}
+
+// AssignRoles 替换用户的角色，不能移除最后一个可用管理员的 admin 角色
+func (s *UserServiceImpl) AssignRoles(ctx context.Context, id string, req *dto.AssignRolesRequest) (*model.User, error) {
+	user, err := s.lookupUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.resolveRoles(ctx, req.Roles)
+	if err != nil {
+		return nil, err
+	}
+	keepsAdmin := false
+	for _, role := range roles {
+		if role.Name == model.RoleAdmin {
+			keepsAdmin = true
+		}
+	}
+	if !keepsAdmin {
+		if err := s.checkLastAdmin(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.users.ReplaceRoles(ctx, user, roles); err != nil {
+		return nil, fmt.Errorf("failed to assign roles: %w", err)
+	}
+	s.logger.Info("User roles assigned", "username", user.Username, "roles", req.Roles)
+	return s.Get(ctx, user.ID)
+}
+
+// ListRoles 列出角色
+func (s *UserServiceImpl) ListRoles(ctx context.Context) ([]*model.Role, error) {
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	return roles, nil
+}
+
+// CreateRole 创建角色
+func (s *UserServiceImpl) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*model.Role, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, errs.InvalidArgument("invalid role name %q", req.Name)
+	}
+	existing, err := s.roles.FindByName(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if existing != nil {
+		return nil, errs.Conflict("role %q already exists", req.Name)
+	}
+	permissions, err := validatePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	role := &model.Role{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := s.roles.Create(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+	s.logger.Info("Role created", "role", role.Name, "permissions", permissions)
+	return role, nil
+}
+
+// GetRole 根据ID或名称获取角色
+func (s *UserServiceImpl) GetRole(ctx context.Context, id string) (*model.Role, error) {
+	return s.lookupRole(ctx, id)
+}
+
+// UpdateRole 更新角色描述与权限，内置 admin 角色的权限不能修改
+func (s *UserServiceImpl) UpdateRole(ctx context.Context, id string, req *dto.UpdateRoleRequest) (*model.Role, error) {
+	role, err := s.lookupRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+	if req.Permissions != nil {
+		if role.Name == model.RoleAdmin {
+			return nil, errs.Conflict("permissions of the built-in %q role cannot be changed", model.RoleAdmin)
+		}
+		if role.Permissions, err = validatePermissions(req.Permissions); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.roles.Update(ctx, role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+	s.logger.Info("Role updated", "role", role.Name, "permissions", role.Permissions)
+	return role, nil
+}
+
+// DeleteRole 删除角色并从所有用户上移除，内置角色不能删除
+func (s *UserServiceImpl) DeleteRole(ctx context.Context, id string) error {
+	role, err := s.lookupRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, builtin := range builtinRoles {
+		if builtin.Name == role.Name {
+			return errs.Conflict("built-in role %q cannot be deleted", role.Name)
+		}
+	}
+	if err := s.roles.Delete(ctx, role.ID); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	s.logger.Info("Role deleted", "role", role.Name)
+	return nil
+}
+
+// Authenticate 校验本地用户的用户名与密码，密码正确但账户未启用或被锁定时返回 403
+// 外部认证域的用户没有本地密码，按用户不存在处理
+func (s *UserServiceImpl) Authenticate(ctx context.Context, username, password string) (*model.User, error) {
+	user, err := s.users.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || !isLocalUser(user) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, errs.Unauthenticated("invalid username or password")
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provision 创建或更新外部认证域的用户，同步邮箱、姓名与角色
+// 同名用户属于其他认证域时拒绝登录，避免外部账户接管本地账户；不存在的角色记录警告后忽略
+func (s *UserServiceImpl) provision(ctx context.Context, ext *externalUser) (*model.User, error) {
+	if !usernamePattern.MatchString(ext.Username) {
+		return nil, errs.Forbidden("username %q is not supported", ext.Username)
+	}
+	user, err := s.users.FindByUsername(ctx, ext.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user != nil && user.Realm != ext.Realm {
+		s.logger.Warn("External login rejected, username belongs to another realm",
+			"username", ext.Username, "realm", ext.Realm, "user_realm", user.Realm)
+		return nil, errs.Forbidden("account %q belongs to another realm", ext.Username)
+	}
+	roles, err := s.externalRoles(ctx, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		user = &model.User{
+			ID:       uuid.New().String(),
+			Username: ext.Username,
+			Email:    s.externalEmail(ctx, ext, ""),
+			FullName: ext.FullName,
+			Status:   model.UserStatusActive,
+			Realm:    ext.Realm,
+		}
+		if err := s.users.Create(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		s.logger.Info("External user provisioned", "username", user.Username, "realm", user.Realm)
+	} else {
+		if err := checkUserStatus(user); err != nil {
+			return nil, err
+		}
+		columns := map[string]interface{}{}
+		if email := s.externalEmail(ctx, ext, user.ID); ext.Email != "" && email != user.Email {
+			columns["email"] = email
+		}
+		if ext.FullName != "" && ext.FullName != user.FullName {
+			columns["full_name"] = ext.FullName
+		}
+		if len(columns) > 0 {
+			if err := s.users.UpdateColumns(ctx, user.ID, columns); err != nil {
+				return nil, fmt.Errorf("failed to update user: %w", err)
+			}
+		}
+	}
+	if err := s.users.ReplaceRoles(ctx, user, roles); err != nil {
+		return nil, fmt.Errorf("failed to assign roles: %w", err)
+	}
+	return s.Get(ctx, user.ID)
+}
+
+// externalRoles 查找外部认证域映射的角色
+func (s *UserServiceImpl) externalRoles(ctx context.Context, ext *externalUser) ([]model.Role, error) {
+	roles := []model.Role{}
+	if len(ext.Roles) == 0 {
+		return roles, nil
+	}
+	found, err := s.roles.FindByNames(ctx, ext.Roles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	byName := make(map[string]*model.Role, len(found))
+	for _, role := range found {
+		byName[role.Name] = role
+	}
+	seen := make(map[string]bool, len(ext.Roles))
+	for _, name := range ext.Roles {
+		role, ok := byName[name]
+		if !ok {
+			s.logger.Warn("Mapped role not found, ignored", "realm", ext.Realm, "role", name)
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			roles = append(roles, *role)
+		}
+	}
+	return roles, nil
+}
+
+// externalEmail 外部认证域未提供邮箱或邮箱已被其他用户使用时，使用 <用户名>@<认证域>.localhost
+func (s *UserServiceImpl) externalEmail(ctx context.Context, ext *externalUser, userID string) string {
+	placeholder := ext.Username + "@" + ext.Realm + ".localhost"
+	if ext.Email == "" {
+		return placeholder
+	}
+	if err := s.checkEmail(ctx, ext.Email, userID); err != nil {
+		s.logger.Warn("External user email is already in use", "username", ext.Username, "email", ext.Email)
+		return placeholder
+	}
+	return ext.Email
+}
+
+// isLocalUser 判断用户是否由本地认证域管理密码
+func isLocalUser(user *model.User) bool {
+	return user.Realm == "" || user.Realm == model.UserRealmLocal
+}
+
+// checkLocalUser 外部认证域用户的密码由外部系统管理
+func checkLocalUser(user *model.User) error {
+	if !isLocalUser(user) {
+		return errs.Conflict("password of user %q is managed by the %s realm", user.Username, user.Realm)
+	}
+	return nil
+}
+
+// checkUserStatus 只有 active 状态的用户可以访问
+func checkUserStatus(user *model.User) error {
+	switch user.Status {
+	case model.UserStatusActive:
+		return nil
+	case model.UserStatusLocked:
+		return errs.Forbidden("account %q is locked", user.Username)
+	default:
+		return errs.Forbidden("account %q is inactive", user.Username)
+	}
+}
+
+// lookupUser 根据ID或用户名查找用户
+func (s *UserServiceImpl) lookupUser(ctx context.Context, idOrName string) (*model.User, error) {
+	user, err := s.users.FindByID(ctx, idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		user, err = s.users.FindByUsername(ctx, idOrName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+	}
+	if user == nil {
+		return nil, errs.NotFound("user %q not found", idOrName)
+	}
+	return user, nil
+}
+
+// lookupRole 根据ID或名称查找角色
+func (s *UserServiceImpl) lookupRole(ctx context.Context, idOrName string) (*model.Role, error) {
+	role, err := s.roles.FindByID(ctx, idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if role == nil {
+		role, err = s.roles.FindByName(ctx, idOrName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find role: %w", err)
+		}
+	}
+	if role == nil {
+		return nil, errs.NotFound("role %q not found", idOrName)
+	}
+	return role, nil
+}
+
+// validatePermissions 校验权限格式并去重
+func validatePermissions(permissions []string) ([]string, error) {
+	result := make([]string, 0, len(permissions))
+	seen := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if err := auth.ValidatePermission(permission); err != nil {
+			return nil, errs.InvalidArgument("%v", err)
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			result = append(result, permission)
+		}
+	}
+	return result, nil
+}
+
+// checkEmail 校验邮箱未被其他用户使用
+func (s *UserServiceImpl) checkEmail(ctx context.Context, email, userID string) error {
+	existing, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if existing != nil && existing.ID != userID {
+		return errs.Conflict("email %q is already in use", email)
+	}
+	return nil
+}
+
+// resolveRoles 按名称查找角色，任一角色不存在时返回错误
+func (s *UserServiceImpl) resolveRoles(ctx context.Context, names []string) ([]model.Role, error) {
+	if len(names) == 0 {
+		return []model.Role{}, nil
+	}
+	found, err := s.roles.FindByNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	byName := make(map[string]*model.Role, len(found))
+	for _, role := range found {
+		byName[role.Name] = role
+	}
+	roles := make([]model.Role, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		role, ok := byName[name]
+		if !ok {
+			return nil, errs.InvalidArgument("role %q not found", name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+// checkLastAdmin 用户是最后一个可用的管理员时返回冲突，避免系统失去管理员
+func (s *UserServiceImpl) checkLastAdmin(ctx context.Context, user *model.User) error {
+	if user.Status != model.UserStatusActive || !hasRole(user, model.RoleAdmin) {
+		return nil
+	}
+	active, err := s.users.List(ctx, model.UserStatusActive, "")
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	for _, other := range active {
+		if other.ID != user.ID && hasRole(other, model.RoleAdmin) {
+			return nil
+		}
+	}
+	return errs.Conflict("user %q is the last active administrator", user.Username)
+}
+
+// hasRole 判断用户是否拥有角色
+func hasRole(user *model.User, name string) bool {
+	for _, role := range user.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hashPassword 校验密码长度并生成 bcrypt 哈希
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", errs.InvalidArgument("password must be at least %d characters", minPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return "", errs.InvalidArgument("password must be at most %d bytes", maxPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return "", errs.InvalidArgument("password is too long")
+		}
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// randomPassword 生成随机密码
+func randomPassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
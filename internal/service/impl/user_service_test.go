@@ -0,0 +1,374 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+	"github.com/laolishu/go-nexus/internal/service/errs"
+)
+
+const testPassword = "correct-horse"
+
+// newUserEnv 创建用户服务并执行 Bootstrap，初始管理员密码为 admin-password
+func newUserEnv(t *testing.T) (*testEnv, *UserServiceImpl) {
+	t.Helper()
+	env := newTestEnv(t)
+	env.cfg.Security.AdminPassword = "admin-password"
+	users := NewUserService(env.cfg, env.logger, env.userRepo, env.roleRepo)
+	require.NoError(t, users.Bootstrap(context.Background()))
+	return env, users
+}
+
+// createUser 创建本地用户，失败时终止测试
+func createUser(t *testing.T, users *UserServiceImpl, username string, roles ...string) *model.User {
+	t.Helper()
+	user, err := users.Create(context.Background(), &dto.CreateUserRequest{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: testPassword,
+		Roles:    roles,
+	})
+	require.NoError(t, err)
+	return user
+}
+
+// roleNames 返回用户的角色名称
+func roleNames(user *model.User) []string {
+	names := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func TestUserService_Bootstrap(t *testing.T) {
+	env, users := newUserEnv(t)
+	ctx := context.Background()
+
+	roles, err := users.ListRoles(ctx)
+	require.NoError(t, err)
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	assert.ElementsMatch(t, []string{model.RoleAdmin, model.RoleDeveloper, model.RoleViewer, model.RoleAnonymous}, names)
+
+	admin, err := users.Authenticate(ctx, initialAdminUsername, "admin-password")
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.RoleAdmin}, roleNames(admin))
+	assert.Equal(t, model.UserRealmLocal, admin.Realm)
+
+	t.Run("keeps_modified_roles", func(t *testing.T) {
+		description := "changed"
+		_, err := users.UpdateRole(ctx, model.RoleViewer, &dto.UpdateRoleRequest{Description: &description, Permissions: []string{}})
+		require.NoError(t, err)
+		require.NoError(t, users.Bootstrap(ctx))
+		viewer, err := users.GetRole(ctx, model.RoleViewer)
+		require.NoError(t, err)
+		assert.Equal(t, "changed", viewer.Description)
+		assert.Empty(t, viewer.Permissions)
+	})
+
+	t.Run("generated_password", func(t *testing.T) {
+		other := newTestEnv(t)
+		service := NewUserService(other.cfg, other.logger, other.userRepo, other.roleRepo)
+		require.NoError(t, service.Bootstrap(ctx))
+		admin, err := other.userRepo.FindByUsername(ctx, initialAdminUsername)
+		require.NoError(t, err)
+		require.NotNil(t, admin)
+		assert.True(t, strings.HasPrefix(admin.Password, "$2"), "password is stored as a bcrypt hash")
+	})
+
+	count, err := env.userRepo.Count(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count, "bootstrap only creates the admin once")
+}
+
+func TestUserService_Create(t *testing.T) {
+	_, users := newUserEnv(t)
+	ctx := context.Background()
+
+	user := createUser(t, users, "alice", model.RoleDeveloper, model.RoleDeveloper)
+	assert.Equal(t, model.UserStatusActive, user.Status)
+	assert.Equal(t, model.UserRealmLocal, user.Realm)
+	assert.Equal(t, []string{model.RoleDeveloper}, roleNames(user), "duplicate roles are collapsed")
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(testPassword)))
+
+	tests := []struct {
+		name    string
+		req     *dto.CreateUserRequest
+		wantErr error
+	}{
+		{name: "bad_username", req: &dto.CreateUserRequest{Username: "-bob", Email: "bob@example.com", Password: testPassword}, wantErr: errs.ErrInvalidArgument},
+		{name: "username_with_space", req: &dto.CreateUserRequest{Username: "bob smith", Email: "bob@example.com", Password: testPassword}, wantErr: errs.ErrInvalidArgument},
+		{name: "duplicate_username", req: &dto.CreateUserRequest{Username: "alice", Email: "other@example.com", Password: testPassword}, wantErr: errs.ErrConflict},
+		{name: "duplicate_email", req: &dto.CreateUserRequest{Username: "bob", Email: "alice@example.com", Password: testPassword}, wantErr: errs.ErrConflict},
+		{name: "short_password", req: &dto.CreateUserRequest{Username: "bob", Email: "bob@example.com", Password: "short"}, wantErr: errs.ErrInvalidArgument},
+		{name: "long_password", req: &dto.CreateUserRequest{Username: "bob", Email: "bob@example.com", Password: strings.Repeat("x", 73)}, wantErr: errs.ErrInvalidArgument},
+		{name: "unknown_role", req: &dto.CreateUserRequest{Username: "bob", Email: "bob@example.com", Password: testPassword, Roles: []string{"root"}}, wantErr: errs.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := users.Create(ctx, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("locked_status", func(t *testing.T) {
+		user, err := users.Create(ctx, &dto.CreateUserRequest{Username: "carol", Email: "carol@example.com", Password: testPassword, Status: model.UserStatusLocked})
+		require.NoError(t, err)
+		assert.Equal(t, model.UserStatusLocked, user.Status)
+	})
+
+	t.Run("get_by_id_or_username", func(t *testing.T) {
+		byID, err := users.Get(ctx, user.ID)
+		require.NoError(t, err)
+		byName, err := users.Get(ctx, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, byID.ID, byName.ID)
+		_, err = users.Get(ctx, "missing")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("list_filters", func(t *testing.T) {
+		locked, err := users.List(ctx, &dto.ListUsersQuery{Status: model.UserStatusLocked})
+		require.NoError(t, err)
+		require.Len(t, locked, 1)
+		assert.Equal(t, "carol", locked[0].Username)
+
+		matched, err := users.List(ctx, &dto.ListUsersQuery{Q: " alice "})
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		assert.Equal(t, "alice", matched[0].Username)
+	})
+}
+
+func TestUserService_Authenticate(t *testing.T) {
+	env, users := newUserEnv(t)
+	ctx := context.Background()
+	createUser(t, users, "alice")
+	for _, status := range []string{model.UserStatusInactive, model.UserStatusLocked} {
+		_, err := users.Create(ctx, &dto.CreateUserRequest{Username: status, Email: status + "@example.com", Password: testPassword, Status: status})
+		require.NoError(t, err)
+	}
+	require.NoError(t, env.userRepo.Create(ctx, &model.User{ID: "ldap-user", Username: "ldapuser", Email: "ldap@example.com", Status: model.UserStatusActive, Realm: model.UserRealmLDAP}))
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  error
+	}{
+		{name: "valid", username: "alice", password: testPassword},
+		{name: "wrong_password", username: "alice", password: "wrong-password", wantErr: errs.ErrUnauthenticated},
+		{name: "unknown_user", username: "nobody", password: testPassword, wantErr: errs.ErrUnauthenticated},
+		{name: "inactive", username: model.UserStatusInactive, password: testPassword, wantErr: errs.ErrForbidden},
+		{name: "locked", username: model.UserStatusLocked, password: testPassword, wantErr: errs.ErrForbidden},
+		{name: "locked_wrong_password", username: model.UserStatusLocked, password: "wrong-password", wantErr: errs.ErrUnauthenticated},
+		{name: "external_realm_user", username: "ldapuser", password: "", wantErr: errs.ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := users.Authenticate(ctx, tt.username, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.username, user.Username)
+		})
+	}
+}
+
+func TestUserService_Update(t *testing.T) {
+	env, users := newUserEnv(t)
+	ctx := context.Background()
+	alice := createUser(t, users, "alice")
+	createUser(t, users, "bob")
+
+	email, fullName := "alice@corp.example.com", "Alice Liddell"
+	updated, err := users.Update(ctx, "alice", &dto.UpdateUserRequest{Email: &email, FullName: &fullName})
+	require.NoError(t, err)
+	assert.Equal(t, email, updated.Email)
+	assert.Equal(t, fullName, updated.FullName)
+
+	t.Run("email_in_use", func(t *testing.T) {
+		taken := "bob@example.com"
+		_, err := users.Update(ctx, alice.ID, &dto.UpdateUserRequest{Email: &taken})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("reset_password", func(t *testing.T) {
+		password := "new-password"
+		_, err := users.Update(ctx, alice.ID, &dto.UpdateUserRequest{Password: &password})
+		require.NoError(t, err)
+		_, err = users.Authenticate(ctx, "alice", password)
+		assert.NoError(t, err)
+		_, err = users.Authenticate(ctx, "alice", testPassword)
+		assert.ErrorIs(t, err, errs.ErrUnauthenticated)
+	})
+
+	t.Run("reset_short_password", func(t *testing.T) {
+		password := "short"
+		_, err := users.Update(ctx, alice.ID, &dto.UpdateUserRequest{Password: &password})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		locked := model.UserStatusLocked
+		user, err := users.Update(ctx, alice.ID, &dto.UpdateUserRequest{Status: &locked})
+		require.NoError(t, err)
+		assert.Equal(t, model.UserStatusLocked, user.Status)
+		_, err = users.Authenticate(ctx, "alice", "new-password")
+		assert.ErrorIs(t, err, errs.ErrForbidden)
+	})
+
+	t.Run("external_password_is_managed_elsewhere", func(t *testing.T) {
+		require.NoError(t, env.userRepo.Create(ctx, &model.User{ID: "oidc-user", Username: "oidcuser", Email: "oidc@example.com", Status: model.UserStatusActive, Realm: model.UserRealmOIDC}))
+		password := "new-password"
+		_, err := users.Update(ctx, "oidcuser", &dto.UpdateUserRequest{Password: &password})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		err = users.ChangePassword(ctx, "oidcuser", &dto.ChangePasswordRequest{CurrentPassword: "x", NewPassword: password})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := users.Update(ctx, "missing", &dto.UpdateUserRequest{})
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	_, users := newUserEnv(t)
+	ctx := context.Background()
+	createUser(t, users, "alice")
+
+	tests := []struct {
+		name    string
+		req     *dto.ChangePasswordRequest
+		wantErr error
+	}{
+		{name: "wrong_current", req: &dto.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "new-password"}, wantErr: errs.ErrForbidden},
+		{name: "same_password", req: &dto.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: testPassword}, wantErr: errs.ErrInvalidArgument},
+		{name: "too_short", req: &dto.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "short"}, wantErr: errs.ErrInvalidArgument},
+		{name: "changed", req: &dto.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "new-password"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := users.ChangePassword(ctx, "alice", tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				_, err = users.Authenticate(ctx, "alice", testPassword)
+				assert.NoError(t, err, "failed changes keep the current password")
+				return
+			}
+			require.NoError(t, err)
+			_, err = users.Authenticate(ctx, "alice", tt.req.NewPassword)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUserService_LastAdmin(t *testing.T) {
+	_, users := newUserEnv(t)
+	ctx := context.Background()
+	locked, inactive := model.UserStatusLocked, model.UserStatusInactive
+
+	t.Run("cannot_remove_last_admin", func(t *testing.T) {
+		_, err := users.Update(ctx, initialAdminUsername, &dto.UpdateUserRequest{Status: &locked})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		_, err = users.Update(ctx, initialAdminUsername, &dto.UpdateUserRequest{Status: &inactive})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		_, err = users.AssignRoles(ctx, initialAdminUsername, &dto.AssignRolesRequest{Roles: []string{model.RoleViewer}})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		assert.ErrorIs(t, users.Delete(ctx, initialAdminUsername), errs.ErrConflict)
+	})
+
+	t.Run("inactive_admins_do_not_count", func(t *testing.T) {
+		_, err := users.Create(ctx, &dto.CreateUserRequest{Username: "dormant", Email: "dormant@example.com", Password: testPassword,
+			Status: model.UserStatusInactive, Roles: []string{model.RoleAdmin}})
+		require.NoError(t, err)
+		assert.ErrorIs(t, users.Delete(ctx, initialAdminUsername), errs.ErrConflict)
+		require.NoError(t, users.Delete(ctx, "dormant"), "inactive admins can always be removed")
+	})
+
+	t.Run("second_admin_allows_removal", func(t *testing.T) {
+		createUser(t, users, "root", model.RoleAdmin)
+		user, err := users.AssignRoles(ctx, initialAdminUsername, &dto.AssignRolesRequest{Roles: []string{model.RoleViewer}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{model.RoleViewer}, roleNames(user))
+		assert.ErrorIs(t, users.Delete(ctx, "root"), errs.ErrConflict)
+		require.NoError(t, users.Delete(ctx, initialAdminUsername))
+		_, err = users.Get(ctx, initialAdminUsername)
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+	})
+
+	t.Run("unknown_role", func(t *testing.T) {
+		_, err := users.AssignRoles(ctx, "root", &dto.AssignRolesRequest{Roles: []string{model.RoleAdmin, "root"}})
+		assert.ErrorIs(t, err, errs.ErrInvalidArgument)
+	})
+}
+
+func TestUserService_Roles(t *testing.T) {
+	_, users := newUserEnv(t)
+	ctx := context.Background()
+	readPerm := auth.RepositoryPermission("maven", "libs-release", auth.ActionRead)
+
+	role, err := users.CreateRole(ctx, &dto.CreateRoleRequest{Name: "release-readers", Permissions: []string{readPerm, " " + readPerm + " "}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{readPerm}, role.Permissions, "permissions are trimmed and deduplicated")
+
+	tests := []struct {
+		name    string
+		req     *dto.CreateRoleRequest
+		wantErr error
+	}{
+		{name: "bad_name", req: &dto.CreateRoleRequest{Name: "release readers"}, wantErr: errs.ErrInvalidArgument},
+		{name: "duplicate", req: &dto.CreateRoleRequest{Name: "release-readers"}, wantErr: errs.ErrConflict},
+		{name: "bad_permission", req: &dto.CreateRoleRequest{Name: "broken", Permissions: []string{"repository:maven:read"}}, wantErr: errs.ErrInvalidArgument},
+		{name: "unknown_action", req: &dto.CreateRoleRequest{Name: "broken", Permissions: []string{"repository:maven:libs:own"}}, wantErr: errs.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := users.CreateRole(ctx, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("update", func(t *testing.T) {
+		description := "read releases"
+		updated, err := users.UpdateRole(ctx, role.ID, &dto.UpdateRoleRequest{Description: &description})
+		require.NoError(t, err)
+		assert.Equal(t, description, updated.Description)
+		assert.Equal(t, []string{readPerm}, updated.Permissions, "nil permissions are kept")
+	})
+
+	t.Run("admin_permissions_are_fixed", func(t *testing.T) {
+		_, err := users.UpdateRole(ctx, model.RoleAdmin, &dto.UpdateRoleRequest{Permissions: []string{readPerm}})
+		assert.ErrorIs(t, err, errs.ErrConflict)
+	})
+
+	t.Run("builtin_roles_cannot_be_deleted", func(t *testing.T) {
+		for _, builtin := range builtinRoles {
+			assert.ErrorIs(t, users.DeleteRole(ctx, builtin.Name), errs.ErrConflict, builtin.Name)
+		}
+	})
+
+	t.Run("delete_removes_assignment", func(t *testing.T) {
+		createUser(t, users, "alice", "release-readers")
+		require.NoError(t, users.DeleteRole(ctx, "release-readers"))
+		_, err := users.GetRole(ctx, "release-readers")
+		assert.ErrorIs(t, err, errs.ErrNotFound)
+		alice, err := users.Get(ctx, "alice")
+		require.NoError(t, err)
+		assert.Empty(t, alice.Roles)
+	})
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// PromotionService 制品晋级服务接口
+type PromotionService interface {
+	// Promote 将制品从源仓库复制或移动到同格式的目标宿主仓库，不需要重新上传内容
+	Promote(ctx context.Context, repoIDOrName string, req *dto.PromoteRequest) (*dto.PromoteResult, error)
+}
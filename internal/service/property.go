@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// PropertyService 制品属性服务接口
+// 属性只能设置在实际存放制品的仓库上，通过组仓库访问时返回参数错误
+type PropertyService interface {
+	// Get 获取制品属性
+	Get(ctx context.Context, repoIDOrName, path string) (map[string]string, error)
+
+	// Set 设置属性，只覆盖给出的键，返回设置后的全部属性
+	Set(ctx context.Context, repoIDOrName, path string, properties map[string]string) (map[string]string, error)
+
+	// Delete 删除指定的属性，返回删除后的全部属性
+	Delete(ctx context.Context, repoIDOrName, path string, keys []string) (map[string]string, error)
+
+	// BulkUpdate 对搜索条件匹配的所有制品设置和删除属性
+	BulkUpdate(ctx context.Context, req *dto.BulkPropertiesRequest) (*dto.BulkPropertiesResult, error)
+}
@@ -8,8 +8,42 @@ import (
 
 // ProviderSet 服务层的 Wire 提供者集�?
 var ProviderSet = wire.NewSet(
+	impl.NewRemoteMonitor,
 	impl.NewRepositoryService,
 	wire.Bind(new(RepositoryService), new(*impl.RepositoryServiceImpl)),
 	impl.NewArtifactService,
 	wire.Bind(new(ArtifactService), new(*impl.ArtifactServiceImpl)),
+	impl.NewUploadService,
+	wire.Bind(new(UploadService), new(*impl.UploadServiceImpl)),
+	impl.NewSearchService,
+	wire.Bind(new(SearchService), new(*impl.SearchServiceImpl)),
+	impl.NewPropertyService,
+	wire.Bind(new(PropertyService), new(*impl.PropertyServiceImpl)),
+	impl.NewAuditRecorder,
+	wire.Bind(new(AuditService), new(*impl.AuditRecorder)),
+	impl.NewPromotionService,
+	wire.Bind(new(PromotionService), new(*impl.PromotionServiceImpl)),
+	impl.NewStagingService,
+	wire.Bind(new(StagingService), new(*impl.StagingServiceImpl)),
+	impl.NewCleanupService,
+	wire.Bind(new(CleanupService), new(*impl.CleanupServiceImpl)),
+	impl.NewSignatureService,
+	wire.Bind(new(SignatureService), new(*impl.SignatureServiceImpl)),
+	impl.NewMetadataSigner,
+	impl.NewSigningKeyService,
+	wire.Bind(new(SigningKeyService), new(*impl.SigningKeyServiceImpl)),
+	impl.NewTaskService,
+	wire.Bind(new(TaskService), new(*impl.TaskServiceImpl)),
+	impl.NewUserService,
+	wire.Bind(new(UserService), new(*impl.UserServiceImpl)),
+	impl.NewAuthorizer,
+	wire.Bind(new(Authorizer), new(*impl.AuthorizerImpl)),
+	impl.NewTokenService,
+	wire.Bind(new(TokenService), new(*impl.TokenServiceImpl)),
+	impl.NewRealmChain,
+	impl.NewOIDCRealm,
+	impl.NewAuthService,
+	wire.Bind(new(AuthService), new(*impl.AuthServiceImpl)),
+	impl.NewRegistryTokenService,
+	wire.Bind(new(RegistryTokenService), new(*impl.RegistryTokenServiceImpl)),
 )
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// RegistryTokenService Docker 仓库令牌服务接口，实现 Docker registry token 认证规范
+type RegistryTokenService interface {
+	// Issue 为 context 中的调用者签发令牌，只授予调用者有权执行的操作，无权执行的操作不报错而是不授予
+	Issue(ctx context.Context, req *dto.RegistryTokenRequest) (*dto.RegistryToken, error)
+	// Authorize 校验令牌并判断是否允许对镜像执行 pull、push 或 delete，name 为空时只校验令牌
+	Authorize(ctx context.Context, token, name, action string) error
+	// Service 认证提示中的 service
+	Service() string
+	// Realm 配置的令牌服务地址，为空时由处理器按请求生成
+	Realm() string
+}
@@ -1,11 +1,48 @@
 package service
 
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
 // RepositoryService 仓库服务接口
+// 参数 idOrName 既可以是仓库ID，也可以是仓库名称
 type RepositoryService interface {
-	// TODO: 添加仓库相关方法
+	// Create 创建仓库
+	Create(ctx context.Context, req *dto.CreateRepositoryRequest) (*model.Repository, error)
+
+	// Get 获取仓库
+	Get(ctx context.Context, idOrName string) (*model.Repository, error)
+
+	// List 列出仓库
+	List(ctx context.Context, query *dto.ListRepositoriesQuery) ([]*model.Repository, error)
+
+	// Update 更新仓库
+	Update(ctx context.Context, idOrName string, req *dto.UpdateRepositoryRequest) (*model.Repository, error)
+
+	// Delete 删除仓库，仍被组仓库引用的成员不能删除
+	Delete(ctx context.Context, idOrName string) error
 }
 
 // ArtifactService 制品服务接口
 type ArtifactService interface {
-	// TODO: 添加制品相关方法
+	// Download 获取制品内容，group 仓库按成员顺序解析，元数据文件按格式合并
+	Download(ctx context.Context, repoIDOrName, path string) (*dto.ArtifactContent, error)
+
+	// List 分页查询仓库中的制品，group 仓库查询所有成员
+	List(ctx context.Context, repoIDOrName string, query *dto.ListArtifactsQuery) (*dto.ArtifactPage, error)
+
+	// Latest 按仓库格式的版本规则解析满足范围的最高版本，默认排除预发布版本
+	Latest(ctx context.Context, repoIDOrName string, query *dto.LatestVersionQuery) (*dto.LatestVersion, error)
+
+	// Upload 上传制品到宿主仓库，校验客户端提供的校验和，同一路径重复上传会覆盖
+	Upload(ctx context.Context, repoIDOrName string, req *dto.UploadArtifactRequest) (*model.Artifact, error)
+
+	// Delete 删除制品，宿主仓库同时重新生成元数据文件，代理仓库只删除缓存
+	Delete(ctx context.Context, repoIDOrName, path string) error
+
+	// ExplainRouting 说明路径经路由规则与组成员解析后会由哪个仓库提供
+	ExplainRouting(ctx context.Context, repoIDOrName, path string) (*dto.RoutingExplanation, error)
 }
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// SearchService 跨仓库制品搜索服务接口
+type SearchService interface {
+	// Search 在调用者可读的仓库中搜索制品，组仓库展开为成员仓库
+	Search(ctx context.Context, query *dto.SearchQuery) (*dto.SearchPage, error)
+}
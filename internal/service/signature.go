@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// SignatureService 制品签名服务接口
+type SignatureService interface {
+	// CreateKey 添加受信任公钥
+	CreateKey(ctx context.Context, req *dto.TrustedKeyRequest) (*model.TrustedKey, error)
+	// GetKey 获取受信任公钥
+	GetKey(ctx context.Context, name string) (*model.TrustedKey, error)
+	// ListKeys 列出受信任公钥
+	ListKeys(ctx context.Context) ([]*model.TrustedKey, error)
+	// DeleteKey 删除未被仓库签名策略引用的受信任公钥
+	DeleteKey(ctx context.Context, name string) error
+	// Status 获取制品记录中保存的签名校验结果
+	Status(ctx context.Context, repoIDOrName, path string) (*dto.SignatureStatus, error)
+	// Verify 使用仓库当前信任的公钥重新校验制品签名并保存结果
+	Verify(ctx context.Context, repoIDOrName, path string) (*dto.SignatureStatus, error)
+}
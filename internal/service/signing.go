@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// SigningKeyService 元数据签名密钥服务接口
+type SigningKeyService interface {
+	// ImportKey 导入签名私钥，私钥加密保存
+	ImportKey(ctx context.Context, req *dto.SigningKeyRequest) (*model.SigningKey, error)
+	// GetKey 获取签名密钥
+	GetKey(ctx context.Context, name string) (*model.SigningKey, error)
+	// ListKeys 列出签名密钥
+	ListKeys(ctx context.Context) ([]*model.SigningKey, error)
+	// ActivateKey 启用签名密钥，之后生成的元数据使用该密钥签名
+	ActivateKey(ctx context.Context, name string) (*model.SigningKey, error)
+	// DeleteKey 删除签名密钥
+	DeleteKey(ctx context.Context, name string) error
+	// ActiveKey 获取启用的签名密钥，用于发布公钥
+	ActiveKey(ctx context.Context) (*model.SigningKey, error)
+}
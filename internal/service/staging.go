@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// StagingService 暂存仓库服务接口
+// 暂存仓库是带有暂存状态的宿主仓库：open 时接受上传，close 校验通过后变为 closed，
+// release 将内容移动到目标仓库，drop 丢弃内容
+type StagingService interface {
+	// Start 为目标宿主仓库创建新的暂存仓库
+	Start(ctx context.Context, req *dto.StartStagingRequest) (*model.Repository, error)
+	// Deploy 上传制品到目标仓库当前打开的暂存仓库，没有时自动创建
+	Deploy(ctx context.Context, targetIDOrName string, req *dto.UploadArtifactRequest) (*model.Artifact, error)
+	// List 列出暂存仓库
+	List(ctx context.Context, query *dto.ListStagingQuery) ([]*model.Repository, error)
+	// Get 获取暂存仓库
+	Get(ctx context.Context, idOrName string) (*model.Repository, error)
+	// Close 执行校验规则，全部通过后关闭暂存仓库，不再接受上传
+	Close(ctx context.Context, idOrName string) (*model.Repository, error)
+	// Release 将已关闭的暂存仓库内容移动到目标仓库
+	Release(ctx context.Context, idOrName string) (*dto.StagingReleaseResult, error)
+	// Drop 丢弃未发布的暂存仓库内容
+	Drop(ctx context.Context, idOrName string) (*model.Repository, error)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// TaskService 后台任务服务接口
+type TaskService interface {
+	// Start 加载已启用的任务并开始按 cron 表达式调度
+	Start(ctx context.Context) error
+	// Create 创建任务
+	Create(ctx context.Context, req *dto.CreateTaskRequest) (*model.Task, error)
+	// Get 获取任务，包含下次执行时间与是否正在执行
+	Get(ctx context.Context, id string) (*model.Task, error)
+	// List 列出所有任务
+	List(ctx context.Context) ([]*model.Task, error)
+	// Update 更新任务的 cron 表达式、参数与启用状态
+	Update(ctx context.Context, id string, req *dto.UpdateTaskRequest) (*model.Task, error)
+	// Delete 删除未在执行的任务及其执行记录
+	Delete(ctx context.Context, id string) error
+	// RunNow 立即在后台执行任务，任务正在执行时返回冲突
+	RunNow(ctx context.Context, id string) (*model.TaskRun, error)
+	// Cancel 取消任务正在进行的执行
+	Cancel(ctx context.Context, id string) (*model.TaskRun, error)
+	// ListRuns 按时间倒序列出任务的执行记录
+	ListRuns(ctx context.Context, id string, query *dto.ListTaskRunsQuery) ([]*model.TaskRun, error)
+	// GetRun 获取执行记录，包含日志与结果
+	GetRun(ctx context.Context, id, runID string) (*model.TaskRun, error)
+	// Types 列出可调度的任务类型
+	Types() []dto.TaskType
+}
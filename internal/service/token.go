@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/auth"
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// TokenService 个人访问令牌服务接口
+type TokenService interface {
+	// Create 为调用者创建令牌，明文只在返回值中出现一次
+	Create(ctx context.Context, principal *auth.Principal, req *dto.CreateAccessTokenRequest) (*dto.CreatedAccessToken, error)
+	// List 列出用户的令牌，userID 可为ID或用户名
+	List(ctx context.Context, userID string) ([]*model.AccessToken, error)
+	// Revoke 吊销用户的令牌
+	Revoke(ctx context.Context, userID, tokenID string) error
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// UploadService 分块上传服务接口
+// 会话只属于创建它的仓库，通过其他仓库访问时视为不存在
+type UploadService interface {
+	// CreateSession 创建上传会话，校验规则与普通上传一致
+	CreateSession(ctx context.Context, repoIDOrName string, req *dto.CreateUploadSessionRequest) (*model.UploadSession, error)
+
+	// GetSession 查询会话及已接收的偏移量
+	GetSession(ctx context.Context, repoIDOrName, sessionID string) (*model.UploadSession, error)
+
+	// WriteChunk 写入一个分块，分块起始位置必须等于当前偏移量
+	WriteChunk(ctx context.Context, repoIDOrName, sessionID string, chunk *dto.UploadChunk) (*model.UploadSession, error)
+
+	// Complete 校验摘要并将已接收的数据保存为制品
+	Complete(ctx context.Context, repoIDOrName, sessionID, digest string) (*model.Artifact, error)
+
+	// Cancel 取消会话并删除已接收的数据
+	Cancel(ctx context.Context, repoIDOrName, sessionID string) error
+
+	// CleanupExpired 清理过期会话，返回清理的数量
+	CleanupExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/laolishu/go-nexus/internal/repository/model"
+	"github.com/laolishu/go-nexus/internal/service/dto"
+)
+
+// UserService 用户服务接口，返回的用户不包含密码哈希
+type UserService interface {
+	// Bootstrap 创建内置角色，没有任何用户时创建初始管理员
+	Bootstrap(ctx context.Context) error
+	// Create 创建用户
+	Create(ctx context.Context, req *dto.CreateUserRequest) (*model.User, error)
+	// Get 根据ID或用户名获取用户
+	Get(ctx context.Context, id string) (*model.User, error)
+	// List 列出用户
+	List(ctx context.Context, query *dto.ListUsersQuery) ([]*model.User, error)
+	// Update 更新用户信息、状态或重置密码
+	Update(ctx context.Context, id string, req *dto.UpdateUserRequest) (*model.User, error)
+	// Delete 删除用户
+	Delete(ctx context.Context, id string) error
+	// ChangePassword 校验当前密码后修改密码
+	ChangePassword(ctx context.Context, id string, req *dto.ChangePasswordRequest) error
+	// AssignRoles 替换用户的角色
+	AssignRoles(ctx context.Context, id string, req *dto.AssignRolesRequest) (*model.User, error)
+	// ListRoles 列出角色
+	ListRoles(ctx context.Context) ([]*model.Role, error)
+	// CreateRole 创建角色
+	CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*model.Role, error)
+	// GetRole 根据ID或名称获取角色
+	GetRole(ctx context.Context, id string) (*model.Role, error)
+	// UpdateRole 更新角色描述与权限
+	UpdateRole(ctx context.Context, id string, req *dto.UpdateRoleRequest) (*model.Role, error)
+	// DeleteRole 删除角色
+	DeleteRole(ctx context.Context, id string) error
+	// Authenticate 校验用户名与密码，只有 active 状态的用户可以通过
+	Authenticate(ctx context.Context, username, password string) (*model.User, error)
+}
@@ -0,0 +1,290 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/laolishu/go-nexus/pkg/config"
+	"github.com/laolishu/go-nexus/pkg/plugin"
+)
+
+// ErrNotExist 文件不存在
+var ErrNotExist = errors.New("storage: file does not exist")
+
+// FileSystemStorage 本地文件系统存储实现
+type FileSystemStorage struct {
+	logger   *slog.Logger
+	basePath string
+}
+
+// NewStorage 根据配置创建存储插件
+func NewStorage(cfg *config.Config, logger *slog.Logger) (plugin.StoragePlugin, error) {
+	switch cfg.Storage.Type {
+	case "filesystem":
+		return NewFileSystemStorage(logger, cfg.Storage.BasePath)
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", cfg.Storage.Type)
+	}
+}
+
+// NewFileSystemStorage 创建本地文件系统存储
+func NewFileSystemStorage(logger *slog.Logger, basePath string) (*FileSystemStorage, error) {
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage base path: %w", err)
+	}
+	return &FileSystemStorage{
+		logger:   logger,
+		basePath: basePath,
+	}, nil
+}
+
+// Name 返回插件名称
+func (s *FileSystemStorage) Name() string {
+	return "filesystem"
+}
+
+// Version 返回插件版本
+func (s *FileSystemStorage) Version() string {
+	return "1.0.0"
+}
+
+// Initialize 初始化插件
+func (s *FileSystemStorage) Initialize(ctx context.Context, config map[string]interface{}) error {
+	return nil
+}
+
+// Shutdown 关闭插件
+func (s *FileSystemStorage) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Upload 上传文件，先写临时文件再重命名，避免读到半个文件
+func (s *FileSystemStorage) Upload(ctx context.Context, path string, data []byte) error {
+	full, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(full), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return os.Rename(tmp.Name(), full)
+}
+
+// Download 下载文件
+func (s *FileSystemStorage) Download(ctx context.Context, path string) ([]byte, error) {
+	full, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(full)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotExist, path)
+	}
+	return data, err
+}
+
+// Open 打开文件用于流式读取
+func (s *FileSystemStorage) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	full, err := s.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(full)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotExist, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete 删除文件
+func (s *FileSystemStorage) Delete(ctx context.Context, path string) error {
+	full, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// WriteAt 从 offset 处写入数据并截断其后的内容，用于分块上传续写
+func (s *FileSystemStorage) WriteAt(ctx context.Context, path string, offset int64, data []byte) error {
+	full, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(full, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to truncate file: %w", err)
+	}
+	if _, err := f.WriteAt(data, offset); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return f.Close()
+}
+
+// Move 移动文件，目标已存在时覆盖
+func (s *FileSystemStorage) Move(ctx context.Context, from, to string) error {
+	src, err := s.resolve(from)
+	if err != nil {
+		return err
+	}
+	dst, err := s.resolve(to)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotExist, from)
+		}
+		return err
+	}
+	return nil
+}
+
+// Copy 复制文件，优先使用硬链接，跨文件系统等无法链接时复制内容
+// 文件总是通过临时文件重命名写入，硬链接的两端不会被原地修改
+func (s *FileSystemStorage) Copy(ctx context.Context, from, to string) error {
+	src, err := s.resolve(from)
+	if err != nil {
+		return err
+	}
+	dst, err := s.resolve(to)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp := filepath.Join(filepath.Dir(dst), ".copy-"+filepath.Base(dst)+"-"+strconv.FormatInt(time.Now().UnixNano(), 36))
+	if err := os.Link(src, tmp); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotExist, from)
+		}
+		if err := copyFile(src, tmp); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// copyFile 流式复制文件内容
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotExist, src)
+		}
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return out.Close()
+}
+
+// List 列出前缀下的所有文件，返回相对存储根目录的路径
+func (s *FileSystemStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	root, err := s.resolve(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.basePath, p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	return paths, err
+}
+
+// Exists 检查文件是否存在
+func (s *FileSystemStorage) Exists(ctx context.Context, path string) (bool, error) {
+	full, err := s.resolve(path)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(full)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// resolve 将存储路径转换为本地绝对路径，拒绝越过根目录的路径
+func (s *FileSystemStorage) resolve(path string) (string, error) {
+	clean := filepath.Clean("/" + filepath.FromSlash(path))
+	full := filepath.Join(s.basePath, clean)
+	if full != filepath.Clean(s.basePath) && !strings.HasPrefix(full, filepath.Clean(s.basePath)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage path: %s", path)
+	}
+	return full, nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(t *testing.T) *FileSystemStorage {
+	t.Helper()
+	store, err := NewFileSystemStorage(slog.New(slog.NewTextHandler(io.Discard, nil)), t.TempDir())
+	require.NoError(t, err)
+	return store
+}
+
+func TestFileSystemStorage_ResumableWrite(t *testing.T) {
+	store := newTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.WriteAt(ctx, "uploads/s1", 0, []byte("hello")))
+	require.NoError(t, store.WriteAt(ctx, "uploads/s1", 5, []byte(" world")))
+	// 重写中间的分块时丢弃其后的内容
+	require.NoError(t, store.WriteAt(ctx, "uploads/s1", 5, []byte("!")))
+
+	f, err := store.Open(ctx, "uploads/s1")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, "hello!", string(data))
+
+	require.NoError(t, store.Move(ctx, "uploads/s1", "repositories/r/a.bin"))
+	exists, err := store.Exists(ctx, "uploads/s1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	data, err = store.Download(ctx, "repositories/r/a.bin")
+	require.NoError(t, err)
+	assert.Equal(t, "hello!", string(data))
+}
+
+func TestFileSystemStorage_Open(t *testing.T) {
+	store := newTestStorage(t)
+	ctx := context.Background()
+
+	_, err := store.Open(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotExist)
+	_, err = store.Open(ctx, "../outside")
+	assert.Error(t, err)
+}
@@ -6,5 +6,5 @@ import (
 
 // ProviderSet 存储层的 Wire 提供者集合
 var ProviderSet = wire.NewSet(
-// TODO: 添加具体的存储提供者
+	NewStorage,
 )
@@ -0,0 +1,237 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mavenQualifiers Maven 已知限定符的顺序，空字符串为正式版本
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// mavenReleaseIndex 正式版本在限定符顺序中的位置，之前的限定符均为预发布
+const mavenReleaseIndex = 5
+
+// mavenQualifierAliases 限定符别名
+var mavenQualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// mavenItem Maven 版本中的一项：数字、限定符或由 - 引出的子列表
+type mavenItem interface {
+	compareTo(other mavenItem) int // other 为 nil 表示对方已没有更多项
+	isNull() bool
+}
+
+type mavenInt string
+
+type mavenString string
+
+type mavenList []mavenItem
+
+func (i mavenInt) isNull() bool { return strings.TrimLeft(string(i), "0") == "" }
+
+func (i mavenInt) compareTo(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case mavenInt:
+		return compareNumeric(string(i), string(o))
+	default:
+		return 1
+	}
+}
+
+func (s mavenString) isNull() bool { return s.comparable() == strconv.Itoa(mavenReleaseIndex) }
+
+// comparable 限定符的可比较形式，未知限定符排在所有已知限定符之后并按字典序比较
+func (s mavenString) comparable() string {
+	for i, q := range mavenQualifiers {
+		if q == string(s) {
+			return strconv.Itoa(i)
+		}
+	}
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + string(s)
+}
+
+func (s mavenString) compareTo(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		return strings.Compare(s.comparable(), strconv.Itoa(mavenReleaseIndex))
+	case mavenString:
+		return strings.Compare(s.comparable(), o.comparable())
+	default:
+		return -1
+	}
+}
+
+func (l mavenList) isNull() bool { return len(l) == 0 }
+
+func (l mavenList) compareTo(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compareTo(nil)
+	case mavenInt:
+		return -1
+	case mavenString:
+		return 1
+	case mavenList:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right mavenItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			var result int
+			if left == nil {
+				result = -right.compareTo(nil)
+			} else {
+				result = left.compareTo(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// normalize 去掉末尾等同于缺省值的项（0、正式版本限定符、空列表）
+func (l *mavenList) normalize() {
+	for i := len(*l) - 1; i >= 0; i-- {
+		item := (*l)[i]
+		if item.isNull() {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+		} else if _, ok := item.(mavenList); !ok {
+			break
+		}
+	}
+}
+
+// parseMaven 按 Maven ComparableVersion 规则解析版本
+// . 分隔同一层级的项，- 以及数字与字母的边界开始新的子列表
+func parseMaven(v string) mavenList {
+	v = strings.ToLower(strings.TrimSpace(v))
+
+	// 子列表以指针保存，解析结束后由内向外规范化再写回父列表
+	type frame struct {
+		list   *mavenList
+		parent *mavenList
+		index  int
+	}
+	root := &mavenList{}
+	stack := []frame{{list: root}}
+	current := root
+	push := func() {
+		child := &mavenList{}
+		*current = append(*current, mavenList(nil))
+		stack = append(stack, frame{list: child, parent: current, index: len(*current) - 1})
+		current = child
+	}
+	parseItem := func(digit bool, s string, followedByDigit bool) mavenItem {
+		if digit {
+			return mavenInt(s)
+		}
+		if followedByDigit && len(s) == 1 {
+			switch s {
+			case "a":
+				s = "alpha"
+			case "b":
+				s = "beta"
+			case "m":
+				s = "milestone"
+			}
+		}
+		if alias, ok := mavenQualifierAliases[s]; ok {
+			s = alias
+		}
+		return mavenString(s)
+	}
+
+	digit, start := false, 0
+	runes := []rune(v)
+	for i, r := range runes {
+		switch {
+		case r == '.':
+			if i == start {
+				*current = append(*current, mavenInt("0"))
+			} else {
+				*current = append(*current, parseItem(digit, string(runes[start:i]), false))
+			}
+			start = i + 1
+		case r == '-':
+			if i == start {
+				*current = append(*current, mavenInt("0"))
+			} else {
+				*current = append(*current, parseItem(digit, string(runes[start:i]), false))
+			}
+			start = i + 1
+			push()
+		case unicode.IsDigit(r):
+			if !digit && i > start {
+				*current = append(*current, parseItem(false, string(runes[start:i]), true))
+				start = i
+				push()
+			}
+			digit = true
+		default:
+			if digit && i > start {
+				*current = append(*current, parseItem(true, string(runes[start:i]), false))
+				start = i
+				push()
+			}
+			digit = false
+		}
+	}
+	if len(runes) > start {
+		*current = append(*current, parseItem(digit, string(runes[start:]), false))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		f := stack[i]
+		f.list.normalize()
+		if f.parent != nil {
+			(*f.parent)[f.index] = *f.list
+		}
+	}
+	return *root
+}
+
+// CompareMaven 按 Maven ComparableVersion 规则比较版本
+// 如 1.0-alpha-1 < 1.0-beta < 1.0-rc1 < 1.0-SNAPSHOT < 1.0 = 1.0.0 = 1.0-ga < 1.0-sp1 < 1.0.1
+func CompareMaven(a, b string) int {
+	return parseMaven(a).compareTo(parseMaven(b))
+}
+
+// IsMavenPrerelease 版本中包含排在正式版本之前的限定符（alpha、beta、milestone、rc、snapshot）
+func IsMavenPrerelease(v string) bool {
+	return mavenHasPrerelease(parseMaven(v))
+}
+
+func mavenHasPrerelease(l mavenList) bool {
+	for _, item := range l {
+		switch i := item.(type) {
+		case mavenString:
+			if c := i.comparable(); len(c) == 1 && c < strconv.Itoa(mavenReleaseIndex) {
+				return true
+			}
+		case mavenList:
+			if mavenHasPrerelease(i) {
+				return true
+			}
+		}
+	}
+	return false
+}
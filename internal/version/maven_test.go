@@ -0,0 +1,63 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareMaven(t *testing.T) {
+	assertOrdered(t, CompareMaven,
+		"1", "1.0.1-alpha-1", "1.0.1-alpha-2", "1.0.1-beta", "1.0.1-milestone-1", "1.0.1-rc1", "1.0.1-SNAPSHOT",
+		"1.0.1", "1.0.1-sp1", "1.0.1-custom", "1.0.1.1", "1.0.2", "1.1", "2")
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"1.0", "1.0-ga", 0},
+		{"1.0", "1.0-final", 0},
+		{"1.0", "1.0-release", 0},
+		{"1.0a1", "1.0-alpha-1", 0},
+		{"1.0-b2", "1.0-beta-2", 0},
+		{"1.0-m3", "1.0-milestone-3", 0},
+		{"1.0-cr1", "1.0-rc1", 0},
+		{"1.0-RC1", "1.0-rc1", 0},
+		{"1.0-alpha", "1.0", -1},
+		{"1.0-aaa", "1.0-bbb", -1},
+		{"1.0-sp", "1.0-foo", -1},
+		{"1.0.0-1", "1.0", 1},
+		{"1-1", "1.1", -1},
+		{"1.0-rc-1", "1.0-rc-2", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareMaven(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareMaven(tt.b, tt.a))
+		})
+	}
+}
+
+func TestIsMavenPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0", false},
+		{"1.0-ga", false},
+		{"1.0-sp1", false},
+		{"1.0-custom", false},
+		{"1.0-SNAPSHOT", true},
+		{"1.0-alpha-1", true},
+		{"1.0-beta", true},
+		{"1.0-M2", true},
+		{"1.0-RC1", true},
+		{"1.0-cr1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsMavenPrerelease(tt.version))
+		})
+	}
+}
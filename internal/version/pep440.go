@@ -0,0 +1,170 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pep440Pattern PEP 440 版本格式，允许规范中列出的各种宽松写法
+var pep440Pattern = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440 解析后的 PEP 440 版本
+type pep440 struct {
+	epoch   string
+	release []string
+	pre     *pep440Segment // a、b、rc
+	post    *pep440Segment
+	dev     *pep440Segment
+	local   []string
+}
+
+// pep440Segment 带序号的版本段，序号缺省为 0
+type pep440Segment struct {
+	rank   int // 仅预发布段使用：a=0、b=1、rc=2
+	number string
+}
+
+// parsePEP440 解析 PEP 440 版本
+func parsePEP440(v string) (*pep440, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(v)))
+	if m == nil {
+		return nil, false
+	}
+	p := &pep440{epoch: orZero(m[1])}
+	p.release = strings.Split(m[2], ".")
+	for len(p.release) > 1 && strings.TrimLeft(p.release[len(p.release)-1], "0") == "" {
+		p.release = p.release[:len(p.release)-1]
+	}
+	if m[3] != "" {
+		rank := 2
+		switch m[3] {
+		case "a", "alpha":
+			rank = 0
+		case "b", "beta":
+			rank = 1
+		}
+		p.pre = &pep440Segment{rank: rank, number: orZero(m[4])}
+	}
+	if m[5] != "" {
+		p.post = &pep440Segment{number: m[5]}
+	} else if m[6] != "" {
+		p.post = &pep440Segment{number: orZero(m[7])}
+	}
+	if m[8] != "" {
+		p.dev = &pep440Segment{number: orZero(m[9])}
+	}
+	if m[10] != "" {
+		p.local = strings.FieldsFunc(m[10], func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	}
+	return p, true
+}
+
+// ComparePEP440 按 PEP 440 规则比较版本
+// 如 1.0.dev0 < 1.0a1 < 1.0b2 < 1.0rc1 < 1.0 < 1.0.post1 < 1!0.1
+// 无法解析为 PEP 440 版本时使用通用比较
+func ComparePEP440(a, b string) int {
+	pa, okA := parsePEP440(a)
+	pb, okB := parsePEP440(b)
+	if !okA || !okB {
+		return Compare(a, b)
+	}
+	if c := compareNumeric(pa.epoch, pb.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(pa.release) || i < len(pb.release); i++ {
+		if c := compareNumeric(segmentAt(pa.release, i), segmentAt(pb.release, i)); c != 0 {
+			return c
+		}
+	}
+	if c := compareInt(pa.preKey(), pb.preKey()); c != 0 {
+		return c
+	}
+	if pa.pre != nil && pb.pre != nil {
+		if c := compareNumeric(pa.pre.number, pb.pre.number); c != 0 {
+			return c
+		}
+	}
+	if c := compareOptional(pa.post, pb.post, -1); c != 0 {
+		return c
+	}
+	if c := compareOptional(pa.dev, pb.dev, 1); c != 0 {
+		return c
+	}
+	return compareLocal(pa.local, pb.local)
+}
+
+// IsPEP440Prerelease 是否为预发布或开发版本，如 1.0rc1、1.0.dev3
+func IsPEP440Prerelease(v string) bool {
+	if p, ok := parsePEP440(v); ok {
+		return p.pre != nil || p.dev != nil
+	}
+	return IsGenericPrerelease(v)
+}
+
+// preKey 预发布段的排序键：只有 dev 段的版本排在所有预发布之前，正式版本排在之后
+func (p *pep440) preKey() int {
+	switch {
+	case p.pre != nil:
+		return p.pre.rank
+	case p.post == nil && p.dev != nil:
+		return -1
+	default:
+		return 3
+	}
+}
+
+// compareOptional 比较可选段，absent 为缺失一方的比较结果（-1 表示缺失时更小）
+func compareOptional(a, b *pep440Segment, absent int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return absent
+	case b == nil:
+		return -absent
+	default:
+		return compareNumeric(a.number, b.number)
+	}
+}
+
+// compareLocal 比较本地版本标识，没有本地标识的版本更小，数字段大于字母段
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		numA, numB := isNumeric(a[i]), isNumeric(b[i])
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(a[i], b[i])
+		case numA:
+			c = 1
+		case numB:
+			c = -1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a), len(b))
+}
+
+// segmentAt 返回第 i 段，超出长度视为 0
+func segmentAt(segments []string, i int) string {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return "0"
+}
+
+// orZero 空字符串视为 0
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
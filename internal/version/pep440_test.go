@@ -0,0 +1,59 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComparePEP440(t *testing.T) {
+	// PEP 440 规范中的排序示例
+	assertOrdered(t, ComparePEP440,
+		"1.0.dev456", "1.0a1", "1.0a2.dev456", "1.0a12.dev456", "1.0a12", "1.0b1.dev456", "1.0b2",
+		"1.0b2.post345.dev456", "1.0b2.post345", "1.0rc1.dev456", "1.0rc1", "1.0", "1.0+abc.5", "1.0+abc.7",
+		"1.0+5", "1.0.post456.dev34", "1.0.post456", "1.0.15", "1.1.dev1", "1!0.1")
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"1.0", "v1.0", 0},
+		{"1.0alpha1", "1.0a1", 0},
+		{"1.0-beta.2", "1.0b2", 0},
+		{"1.0c1", "1.0rc1", 0},
+		{"1.0pre1", "1.0rc1", 0},
+		{"1.0-1", "1.0.post1", 0},
+		{"1.0.rev1", "1.0.post1", 0},
+		{"1.0.dev", "1.0.dev0", 0},
+		{"1.0RC1", "1.0rc1", 0},
+		{"0!2.0", "2.0", 0},
+		{"nightly", "1.0", -1}, // 无法解析时退回通用比较，字母段小于数字段
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, ComparePEP440(tt.a, tt.b))
+			assert.Equal(t, -tt.want, ComparePEP440(tt.b, tt.a))
+		})
+	}
+}
+
+func TestIsPEP440Prerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0", false},
+		{"1.0.post1", false},
+		{"1.0+local", false},
+		{"1.0a1", true},
+		{"1.0rc2", true},
+		{"1.0.dev3", true},
+		{"1.0.post1.dev1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPEP440Prerelease(tt.version))
+		})
+	}
+}
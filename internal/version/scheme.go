@@ -0,0 +1,52 @@
+package version
+
+// Scheme 某种制品格式的版本规则
+type Scheme struct {
+	Name         string
+	Compare      Comparator
+	IsPrerelease func(v string) bool
+}
+
+// 内置的版本规则
+var (
+	Generic = &Scheme{Name: "generic", Compare: Compare, IsPrerelease: IsGenericPrerelease}
+	Maven   = &Scheme{Name: "maven", Compare: CompareMaven, IsPrerelease: IsMavenPrerelease}
+	SemVer  = &Scheme{Name: "semver", Compare: CompareSemver, IsPrerelease: IsSemverPrerelease}
+	PEP440  = &Scheme{Name: "pep440", Compare: ComparePEP440, IsPrerelease: IsPEP440Prerelease}
+)
+
+// formatSchemes 制品格式使用的版本规则
+var formatSchemes = map[string]*Scheme{
+	"maven":  Maven,
+	"gradle": Maven,
+	"npm":    SemVer,
+	"cargo":  SemVer,
+	"helm":   SemVer,
+	"go":     SemVer,
+	"pypi":   PEP440,
+}
+
+// ForFormat 返回制品格式对应的版本规则，未知格式使用通用规则
+func ForFormat(format string) *Scheme {
+	if scheme, ok := formatSchemes[format]; ok {
+		return scheme
+	}
+	return Generic
+}
+
+// genericPrereleaseTags 通用规则下表示预发布的标识
+var genericPrereleaseTags = map[string]bool{
+	"a": true, "alpha": true, "b": true, "beta": true, "m": true, "milestone": true,
+	"rc": true, "cr": true, "pre": true, "preview": true, "dev": true,
+	"snapshot": true, "canary": true, "nightly": true,
+}
+
+// IsGenericPrerelease 版本中是否含有常见的预发布标识
+func IsGenericPrerelease(v string) bool {
+	for _, t := range tokenize(v) {
+		if !t.numeric && genericPrereleaseTags[t.text] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package version
+
+import (
+	"strings"
+)
+
+// semver 解析后的语义化版本，构建元数据不参与比较
+type semver struct {
+	core       [3]string
+	prerelease []string
+}
+
+// parseSemver 宽松解析语义化版本：允许 v 前缀，缺少的次版本号与修订号视为 0
+func parseSemver(v string) (*semver, bool) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "="), "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, hasPre := strings.Cut(v, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return nil, false
+	}
+	s := &semver{core: [3]string{"0", "0", "0"}}
+	for i, part := range parts {
+		if !isNumeric(part) {
+			return nil, false
+		}
+		s.core[i] = part
+	}
+	if hasPre {
+		if pre == "" {
+			return nil, false
+		}
+		s.prerelease = strings.Split(pre, ".")
+	}
+	return s, true
+}
+
+// CompareSemver 按语义化版本 2.0 规则比较版本，如 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta < 1.0.0
+// 无法解析为语义化版本时使用通用比较
+func CompareSemver(a, b string) int {
+	sa, okA := parseSemver(a)
+	sb, okB := parseSemver(b)
+	if !okA || !okB {
+		return Compare(a, b)
+	}
+	for i := range sa.core {
+		if c := compareNumeric(sa.core[i], sb.core[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(sa.prerelease) == 0 && len(sb.prerelease) == 0:
+		return 0
+	case len(sa.prerelease) == 0:
+		return 1
+	case len(sb.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(sa.prerelease) && i < len(sb.prerelease); i++ {
+		if c := comparePrereleaseIdentifier(sa.prerelease[i], sb.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(sa.prerelease), len(sb.prerelease))
+}
+
+// IsSemverPrerelease 是否带有预发布标识，如 1.0.0-rc.1
+func IsSemverPrerelease(v string) bool {
+	if s, ok := parseSemver(v); ok {
+		return len(s.prerelease) > 0
+	}
+	return IsGenericPrerelease(v)
+}
+
+// comparePrereleaseIdentifier 数字标识按数值比较且小于字母标识，字母标识按 ASCII 顺序比较
+func comparePrereleaseIdentifier(a, b string) int {
+	numA, numB := isNumeric(a), isNumeric(b)
+	switch {
+	case numA && numB:
+		return compareNumeric(a, b)
+	case numA:
+		return -1
+	case numB:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// isNumeric 是否为非空的十进制数字串
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareInt 比较两个整数
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,62 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareSemver(t *testing.T) {
+	// semver.org 规范中的示例顺序
+	assertOrdered(t, CompareSemver,
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11",
+		"1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "1.10.0", "2.0.0")
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "v1.0.0", 0},
+		{"=1.0.0", "1.0.0", 0},
+		{"1", "1.0.0", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"1.0.0-alpha+001", "1.0.0-alpha", 0},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.1.1", -1},
+		{"1.0.0.0", "1.0.0", 1}, // 不是语义化版本，退回通用比较
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareSemver(tt.a, tt.b))
+			assert.Equal(t, -tt.want, CompareSemver(tt.b, tt.a))
+		})
+	}
+}
+
+func TestIsSemverPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0.0", false},
+		{"1.0.0+build", false},
+		{"1.0.0-rc.1", true},
+		{"v2.0.0-alpha", true},
+		{"1.0-beta", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsSemverPrerelease(tt.version))
+		})
+	}
+}
+
+func TestParseSemver_Invalid(t *testing.T) {
+	for _, v := range []string{"1.0.0-", "1.x", "1.2.3.4", "abc", ""} {
+		t.Run(v, func(t *testing.T) {
+			_, ok := parseSemver(v)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,379 @@
+// Package version 提供制品版本的比较与范围匹配
+package version
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Comparator 版本比较函数，a < b 返回负数，相等返回 0，a > b 返回正数
+type Comparator func(a, b string) int
+
+// Compare 通用版本比较
+// 版本按数字段与字母段切分，数字段按数值比较，字母段按字典序比较；
+// 一方已结束时，另一方剩余部分以字母段开头视为预发布（更小），以数字段开头视为更大。
+func Compare(a, b string) int {
+	ta, tb := tokenize(a), tokenize(b)
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		if c := compareToken(ta[i], tb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ta) == len(tb):
+		return 0
+	case len(ta) > len(tb):
+		return trailingSign(ta[len(tb)])
+	default:
+		return -trailingSign(tb[len(ta)])
+	}
+}
+
+// token 版本中的一段
+type token struct {
+	numeric bool
+	text    string
+}
+
+// tokenize 按分隔符以及数字与字母的边界切分版本
+func tokenize(v string) []token {
+	var tokens []token
+	var current strings.Builder
+	numeric := false
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, token{numeric: numeric, text: current.String()})
+			current.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(strings.TrimPrefix(strings.TrimSpace(v), "v")) {
+		switch {
+		case r == '.' || r == '-' || r == '_' || r == '+':
+			flush()
+		case unicode.IsDigit(r):
+			if !numeric {
+				flush()
+			}
+			numeric = true
+			current.WriteRune(r)
+		default:
+			if numeric {
+				flush()
+			}
+			numeric = false
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// compareToken 比较单个版本段，数字段大于字母段
+func compareToken(a, b token) int {
+	switch {
+	case a.numeric && b.numeric:
+		return compareNumeric(a.text, b.text)
+	case a.numeric:
+		return 1
+	case b.numeric:
+		return -1
+	default:
+		return strings.Compare(a.text, b.text)
+	}
+}
+
+// compareNumeric 比较任意长度的十进制数字串
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// trailingSign 较长版本多出的部分：数字段使版本更大，字母段（预发布标识）使版本更小
+func trailingSign(t token) int {
+	if t.numeric {
+		return 1
+	}
+	return -1
+}
+
+// Range 版本范围，由若干区间组成，满足任一区间即匹配
+type Range struct {
+	intervals [][]constraint
+}
+
+// constraint 单个比较条件
+type constraint struct {
+	op      string
+	version string
+	// exclusive 为简写形式展开的上界，同时排除上界的预发布版本，使 2.x 不匹配 3.0-beta
+	exclusive bool
+}
+
+// ParseRange 解析版本范围，支持以下写法：
+//   - Maven 区间：[1.0,2.0)、(,1.0]、[1.5]，多个区间用逗号连接表示并集
+//   - 比较运算：>=1.0 <2.0 或 >=1.0,<2.0，条件之间为且；|| 分隔的多组条件为并集；单独的版本号表示精确匹配
+//   - npm 风格简写：2.x、2.1.*、^1.2.3、~1.2.3，* 匹配任意版本
+func ParseRange(expr string) (*Range, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty version range")
+	}
+	if strings.HasPrefix(expr, "[") || strings.HasPrefix(expr, "(") {
+		return parseIntervals(expr)
+	}
+
+	r := &Range{}
+	for _, group := range strings.Split(expr, "||") {
+		parts := strings.FieldsFunc(group, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("invalid version range: %s", expr)
+		}
+		var constraints []constraint
+		for _, part := range parts {
+			c, err := parseConstraint(part)
+			if err != nil {
+				return nil, err
+			}
+			constraints = append(constraints, c...)
+		}
+		r.intervals = append(r.intervals, constraints)
+	}
+	return r, nil
+}
+
+// parseConstraint 解析单个比较条件，简写形式会展开为上下界两个条件
+func parseConstraint(part string) ([]constraint, error) {
+	switch {
+	case part == "*" || part == "x" || part == "X":
+		return nil, nil
+	case strings.HasPrefix(part, "^"):
+		return caretRange(strings.TrimPrefix(part, "^"))
+	case strings.HasPrefix(part, "~"):
+		return tildeRange(strings.TrimPrefix(part, "~"))
+	}
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if strings.HasPrefix(part, op) {
+			v := strings.TrimSpace(strings.TrimPrefix(part, op))
+			if v == "" {
+				return nil, fmt.Errorf("missing version after %s", op)
+			}
+			return []constraint{{op: op, version: v}}, nil
+		}
+	}
+	if prefix, ok := wildcardPrefix(part); ok {
+		return prefixRange(prefix, len(prefix)-1)
+	}
+	return []constraint{{op: "=", version: part}}, nil
+}
+
+// wildcardPrefix 解析 2.x、2.1.* 形式，返回通配符之前的数字段
+func wildcardPrefix(part string) ([]string, bool) {
+	segments := strings.Split(strings.TrimPrefix(part, "v"), ".")
+	last := segments[len(segments)-1]
+	if len(segments) < 2 || (last != "x" && last != "X" && last != "*") {
+		return nil, false
+	}
+	prefix := segments[:len(segments)-1]
+	for _, segment := range prefix {
+		if !isNumeric(segment) {
+			return nil, false
+		}
+	}
+	return prefix, true
+}
+
+// caretRange 展开 ^1.2.3：不改变最左侧非零段，^1.2.3 为 >=1.2.3 <2，^0.2.3 为 >=0.2.3 <0.3
+func caretRange(v string) ([]constraint, error) {
+	segments, err := numericSegments(v)
+	if err != nil {
+		return nil, err
+	}
+	bump := len(segments) - 1
+	for i, segment := range segments {
+		if strings.TrimLeft(segment, "0") != "" {
+			bump = i
+			break
+		}
+	}
+	return boundedRange(v, segments, bump), nil
+}
+
+// tildeRange 展开 ~1.2.3：只允许修订号变化，~1.2.3 为 >=1.2.3 <1.3，~1 为 >=1 <2
+func tildeRange(v string) ([]constraint, error) {
+	segments, err := numericSegments(v)
+	if err != nil {
+		return nil, err
+	}
+	return boundedRange(v, segments, min(1, len(segments)-1)), nil
+}
+
+// prefixRange 展开通配符形式，2.1.x 为 >=2.1 <2.2
+func prefixRange(prefix []string, bump int) ([]constraint, error) {
+	return boundedRange(strings.Join(prefix, "."), prefix, bump), nil
+}
+
+// boundedRange 以 lower 为下界，将第 bump 段加一作为上界
+func boundedRange(lower string, segments []string, bump int) []constraint {
+	upper := make([]string, bump+1)
+	copy(upper, segments[:bump+1])
+	upper[bump] = increment(upper[bump])
+	return []constraint{
+		{op: ">=", version: lower},
+		{op: "<", version: strings.Join(upper, "."), exclusive: true},
+	}
+}
+
+// numericSegments 取版本开头的数字段，预发布等后缀不参与上界计算
+func numericSegments(v string) ([]string, error) {
+	core, _, _ := strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	segments := strings.Split(core, ".")
+	for _, segment := range segments {
+		if !isNumeric(segment) {
+			return nil, fmt.Errorf("invalid version in range: %s", v)
+		}
+	}
+	return segments, nil
+}
+
+// increment 十进制数字串加一
+func increment(n string) string {
+	digits := []byte(n)
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '9' {
+			digits[i]++
+			return string(digits)
+		}
+		digits[i] = '0'
+	}
+	return "1" + string(digits)
+}
+
+// parseIntervals 解析 Maven 风格的区间列表
+func parseIntervals(expr string) (*Range, error) {
+	r := &Range{}
+	rest := expr
+	for rest != "" {
+		end := strings.IndexAny(rest, "])")
+		if end < 0 || (rest[0] != '[' && rest[0] != '(') {
+			return nil, fmt.Errorf("invalid version range: %s", expr)
+		}
+		interval, err := parseInterval(rest[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		r.intervals = append(r.intervals, interval)
+		rest = strings.TrimPrefix(strings.TrimSpace(rest[end+1:]), ",")
+		rest = strings.TrimSpace(rest)
+	}
+	return r, nil
+}
+
+// parseInterval 解析单个区间，如 [1.0,2.0)
+func parseInterval(s string) ([]constraint, error) {
+	lowerInclusive, upperInclusive := s[0] == '[', s[len(s)-1] == ']'
+	body := strings.TrimSpace(s[1 : len(s)-1])
+
+	lower, upper, hasComma := strings.Cut(body, ",")
+	lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+	if !hasComma {
+		if !lowerInclusive || !upperInclusive || lower == "" {
+			return nil, fmt.Errorf("invalid version interval: %s", s)
+		}
+		return []constraint{{op: "=", version: lower}}, nil
+	}
+
+	var constraints []constraint
+	if lower != "" {
+		op := ">"
+		if lowerInclusive {
+			op = ">="
+		}
+		constraints = append(constraints, constraint{op: op, version: lower})
+	}
+	if upper != "" {
+		op := "<"
+		if upperInclusive {
+			op = "<="
+		}
+		constraints = append(constraints, constraint{op: op, version: upper})
+	}
+	if len(constraints) == 0 {
+		return nil, fmt.Errorf("invalid version interval: %s", s)
+	}
+	return constraints, nil
+}
+
+// Contains 判断版本是否在范围内，compare 为空时使用通用比较
+func (r *Range) Contains(v string, compare Comparator) bool {
+	if compare == nil {
+		compare = Compare
+	}
+	for _, interval := range r.intervals {
+		if matchAll(v, interval, compare) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAll 判断版本是否满足区间内的所有条件
+func matchAll(v string, constraints []constraint, compare Comparator) bool {
+	for _, c := range constraints {
+		result := compare(v, c.version)
+		var ok bool
+		switch c.op {
+		case ">=":
+			ok = result >= 0
+		case ">":
+			ok = result > 0
+		case "<=":
+			ok = result <= 0
+		case "<":
+			ok = result < 0 && !(c.exclusive && sameRelease(v, c.version))
+		case "!=":
+			ok = result != 0
+		default:
+			ok = result == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sameRelease 两个版本开头的数字段（忽略末尾的 0）是否相同
+func sameRelease(a, b string) bool {
+	ra, rb := releaseTokens(a), releaseTokens(b)
+	if len(ra) != len(rb) {
+		return false
+	}
+	for i := range ra {
+		if compareNumeric(ra[i], rb[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// releaseTokens 版本开头连续的数字段，去掉末尾的 0
+func releaseTokens(v string) []string {
+	var release []string
+	for _, t := range tokenize(v) {
+		if !t.numeric {
+			break
+		}
+		release = append(release, t.text)
+	}
+	for len(release) > 1 && strings.TrimLeft(release[len(release)-1], "0") == "" {
+		release = release[:len(release)-1]
+	}
+	return release
+}
@@ -0,0 +1,151 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertOrdered 校验版本按给定顺序严格递增，且比较结果反对称
+func assertOrdered(t *testing.T, compare Comparator, versions ...string) {
+	t.Helper()
+	for i := 0; i+1 < len(versions); i++ {
+		a, b := versions[i], versions[i+1]
+		assert.Negative(t, compare(a, b), "%s < %s", a, b)
+		assert.Positive(t, compare(b, a), "%s > %s", b, a)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	assertOrdered(t, Compare, "1.0-alpha", "1.0-beta", "1.0-rc1", "1.0", "1.0.1", "1.2", "1.10", "2.0a", "2.0")
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"v1.0", "1.0", 0},
+		{"1.0.0", "1_0-0", 0},
+		{"1.010", "1.10", 0},
+		{"1.0", "1.0.1", -1},
+		{"1.0.1", "1.0", 1},
+		{"1.0-rc1", "1.0", -1},
+		{"1.0", "1.0-rc1", 1},
+		{"1.0a", "1.0b", -1},
+		{"99999999999999999999", "100000000000000000000", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, Compare(tt.a, tt.b))
+		})
+	}
+}
+
+func TestParseRange_Contains(t *testing.T) {
+	tests := []struct {
+		expr string
+		in   []string
+		out  []string
+	}{
+		{expr: "*", in: []string{"0.1", "9.9.9"}},
+		{expr: "1.2.3", in: []string{"1.2.3"}, out: []string{"1.2.4"}},
+		{expr: "=1.2", in: []string{"1.2"}, out: []string{"1.3"}},
+		{expr: ">=1.0 <2.0", in: []string{"1.0", "1.9.9"}, out: []string{"0.9", "2.0"}},
+		{expr: ">=1.0,<2.0", in: []string{"1.5"}, out: []string{"2.1"}},
+		{expr: ">1.0 <=2.0", in: []string{"1.0.1", "2.0"}, out: []string{"1.0", "2.0.1"}},
+		{expr: "!=1.5", in: []string{"1.4"}, out: []string{"1.5"}},
+		{expr: "<1.0 || >=3.0", in: []string{"0.9", "3.1"}, out: []string{"2.0"}},
+		{expr: "2.x", in: []string{"2.0", "2.99.1"}, out: []string{"1.9", "3.0", "3.0-beta"}},
+		{expr: "2.1.*", in: []string{"2.1.0", "2.1.7"}, out: []string{"2.2.0", "2.0.9"}},
+		{expr: "^1.2.3", in: []string{"1.2.3", "1.9.0"}, out: []string{"1.2.2", "2.0.0", "2.0.0-rc.1"}},
+		{expr: "^0.2.3", in: []string{"0.2.3", "0.2.9"}, out: []string{"0.3.0"}},
+		{expr: "^0.0.3", in: []string{"0.0.3"}, out: []string{"0.0.4"}},
+		{expr: "~1.2.3", in: []string{"1.2.3", "1.2.9"}, out: []string{"1.3.0"}},
+		{expr: "~1", in: []string{"1.0", "1.9"}, out: []string{"2.0"}},
+		{expr: "^1.9.9-beta", in: []string{"1.9.9"}, out: []string{"2.0"}},
+		{expr: "[1.0,2.0)", in: []string{"1.0", "1.5"}, out: []string{"0.9", "2.0"}},
+		{expr: "(1.0,2.0]", in: []string{"1.1", "2.0"}, out: []string{"1.0", "2.1"}},
+		{expr: "(,1.0]", in: []string{"0.1", "1.0"}, out: []string{"1.1"}},
+		{expr: "[1.5,)", in: []string{"1.5", "10"}, out: []string{"1.4"}},
+		{expr: "[1.5]", in: []string{"1.5"}, out: []string{"1.6"}},
+		{expr: "(,1.0],[1.2,)", in: []string{"1.0", "1.2"}, out: []string{"1.1"}},
+		{expr: "^9.9", in: []string{"9.10"}, out: []string{"10.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r, err := ParseRange(tt.expr)
+			require.NoError(t, err)
+			for _, v := range tt.in {
+				assert.True(t, r.Contains(v, nil), "%s should match %s", v, tt.expr)
+			}
+			for _, v := range tt.out {
+				assert.False(t, r.Contains(v, nil), "%s should not match %s", v, tt.expr)
+			}
+		})
+	}
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "  ", "||", ">=", "^1.x", "~abc", "[1.0", "(1.0)", "[,]", "[1.0,2.0)x"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseRange(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRange_SchemeComparator(t *testing.T) {
+	r, err := ParseRange("[1.0,1.0.1)")
+	require.NoError(t, err)
+	// Maven 规则下 1.0-sp1 介于 1.0 与 1.0.1 之间，通用规则视为预发布
+	assert.True(t, r.Contains("1.0-sp1", CompareMaven))
+	assert.False(t, r.Contains("1.0-sp1", Compare))
+}
+
+func TestIncrement(t *testing.T) {
+	assert.Equal(t, "2", increment("1"))
+	assert.Equal(t, "10", increment("9"))
+	assert.Equal(t, "200", increment("199"))
+}
+
+func TestForFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   *Scheme
+	}{
+		{"maven", Maven},
+		{"gradle", Maven},
+		{"npm", SemVer},
+		{"cargo", SemVer},
+		{"helm", SemVer},
+		{"go", SemVer},
+		{"pypi", PEP440},
+		{"raw", Generic},
+		{"", Generic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			assert.Same(t, tt.want, ForFormat(tt.format))
+		})
+	}
+}
+
+func TestIsGenericPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0", false},
+		{"1.0-final", false},
+		{"1.0-rc1", true},
+		{"1.0.0-SNAPSHOT", true},
+		{"2.0-nightly.3", true},
+		{"1.0-beta", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsGenericPrerelease(tt.version))
+		})
+	}
+}
@@ -17,6 +17,7 @@ type Config struct {
 	Log      LogConfig      `mapstructure:"log"`
 	Security SecurityConfig `mapstructure:"security"`
 	Plugins  PluginsConfig  `mapstructure:"plugins"`
+	Cleanup  CleanupConfig  `mapstructure:"cleanup"`
 }
 
 // ServerConfig 服务器配置
@@ -27,6 +28,8 @@ type ServerConfig struct {
 	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
 	MaxHeaderBytes int           `mapstructure:"max_header_bytes"`
+	MaxUploadSize  int64         `mapstructure:"max_upload_size"` // 单次上传请求体的最大字节数，超出时返回 413
+	MaxChunkSize   int64         `mapstructure:"max_chunk_size"`  // 分块上传单个分块的最大字节数，超出时返回 413
 }
 
 // DatabaseConfig 数据库配置
@@ -44,6 +47,14 @@ type StorageConfig struct {
 	BasePath string            `mapstructure:"base_path"`
 	S3       S3Config          `mapstructure:"s3"`
 	Options  map[string]string `mapstructure:"options"`
+
+	UploadExpiry time.Duration `mapstructure:"upload_expiry"` // 分块上传会话空闲过期时间
+}
+
+// CleanupConfig 清理策略执行配置
+type CleanupConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`  // 首次启动时是否创建定期执行清理策略的 cleanup 任务
+	Interval time.Duration `mapstructure:"interval"` // cleanup 任务的初始执行间隔，之后以任务的 cron 表达式为准
 }
 
 // S3Config S3存储配置
@@ -83,11 +94,92 @@ type LogConfig struct {
 
 // SecurityConfig 安全配置
 type SecurityConfig struct {
-	JWTSecret   string `mapstructure:"jwt_secret"`
-	JWTExpire   string `mapstructure:"jwt_expire"`
-	EnableHTTPS bool   `mapstructure:"enable_https"`
-	CertFile    string `mapstructure:"cert_file"`
-	KeyFile     string `mapstructure:"key_file"`
+	JWTSecret     string `mapstructure:"jwt_secret"`
+	JWTExpire     string `mapstructure:"jwt_expire"`
+	EnableHTTPS   bool   `mapstructure:"enable_https"`
+	CertFile      string `mapstructure:"cert_file"`
+	KeyFile       string `mapstructure:"key_file"`
+	AdminPassword string `mapstructure:"admin_password"` // 初始管理员密码，为空时随机生成并写入日志
+	RefreshExpire string `mapstructure:"refresh_expire"` // 刷新令牌有效期
+	AnonymousRead bool   `mapstructure:"anonymous_read"` // 是否允许未认证的调用者读取仓库与制品
+
+	Realms  []string          `mapstructure:"realms"` // 登录时按顺序尝试的认证域，可选 local、ldap
+	LDAP    LDAPConfig        `mapstructure:"ldap"`
+	OIDC    OIDCConfig        `mapstructure:"oidc"`
+	Docker  DockerTokenConfig `mapstructure:"docker"`
+	Signing SigningConfig     `mapstructure:"signing"`
+}
+
+// SigningConfig 元数据签名配置
+type SigningConfig struct {
+	Secret string `mapstructure:"secret"` // 加密保存签名私钥的主密钥，为空时不能导入签名密钥
+}
+
+// DockerTokenConfig Docker 仓库令牌认证配置
+type DockerTokenConfig struct {
+	Service     string        `mapstructure:"service"`      // 认证提示中的 service 与令牌的受众
+	Realm       string        `mapstructure:"realm"`        // 令牌服务地址，为空时按请求的协议与 Host 生成
+	TokenExpire time.Duration `mapstructure:"token_expire"` // 令牌有效期
+}
+
+// LDAPConfig LDAP/Active Directory 认证域配置
+type LDAPConfig struct {
+	URL                string        `mapstructure:"url"`                  // ldap://host:389 或 ldaps://host:636
+	StartTLS           bool          `mapstructure:"start_tls"`            // ldap:// 连接建立后升级为 TLS
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify"` // 不校验服务端证书，仅用于测试
+	CAFile             string        `mapstructure:"ca_file"`              // 校验服务端证书的 CA，为空时使用系统证书
+	Timeout            time.Duration `mapstructure:"timeout"`
+	BindDN             string        `mapstructure:"bind_dn"` // 搜索用户与组的服务账户，为空时匿名搜索
+	BindPassword       string        `mapstructure:"bind_password"`
+
+	UserBaseDN        string `mapstructure:"user_base_dn"`
+	UserFilter        string `mapstructure:"user_filter"` // {username} 替换为转义后的登录名
+	UsernameAttribute string `mapstructure:"username_attribute"`
+	EmailAttribute    string `mapstructure:"email_attribute"`
+	FullNameAttribute string `mapstructure:"full_name_attribute"`
+
+	MemberOfAttribute  string `mapstructure:"member_of_attribute"` // 用户条目上记录所属组 DN 的属性，如 AD 的 memberOf
+	GroupBaseDN        string `mapstructure:"group_base_dn"`       // 为空时不搜索组
+	GroupFilter        string `mapstructure:"group_filter"`        // {dn} 替换为用户 DN，{username} 替换为用户名
+	GroupNameAttribute string `mapstructure:"group_name_attribute"`
+
+	GroupMappings []GroupMapping `mapstructure:"group_mappings"`
+	DefaultRoles  []string       `mapstructure:"default_roles"` // 所有 LDAP 用户都拥有的角色
+}
+
+// GroupMapping 外部认证域的组与角色的映射
+type GroupMapping struct {
+	Group string   `mapstructure:"group"` // 组名，LDAP 也可以是组的 DN，不区分大小写
+	Roles []string `mapstructure:"roles"`
+}
+
+// OIDCConfig OpenID Connect 单点登录与 CI 工作负载身份配置
+type OIDCConfig struct {
+	Issuer       string        `mapstructure:"issuer"` // 单点登录的签发者，为空时不启用单点登录
+	ClientID     string        `mapstructure:"client_id"`
+	ClientSecret string        `mapstructure:"client_secret"` // 为空时作为公共客户端，只使用 PKCE
+	RedirectURL  string        `mapstructure:"redirect_url"`  // 如 https://nexus.example.com/api/v1/auth/oidc/callback
+	Scopes       []string      `mapstructure:"scopes"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+
+	UsernameClaim string `mapstructure:"username_claim"`
+	EmailClaim    string `mapstructure:"email_claim"`
+	NameClaim     string `mapstructure:"name_claim"`
+	GroupsClaim   string `mapstructure:"groups_claim"` // 支持以 . 分隔的嵌套声明，如 realm_access.roles
+
+	GroupMappings []GroupMapping `mapstructure:"group_mappings"`
+	DefaultRoles  []string       `mapstructure:"default_roles"` // 所有单点登录用户都拥有的角色
+
+	Workloads []OIDCWorkload `mapstructure:"workloads"` // 接受其签发的令牌作为 API 凭据的 CI 工作负载身份
+}
+
+// OIDCWorkload CI 工作负载身份，签发者、受众与主体匹配的令牌以 User 的身份访问
+type OIDCWorkload struct {
+	Issuer   string   `mapstructure:"issuer"`   // 如 https://token.actions.githubusercontent.com
+	Audience string   `mapstructure:"audience"` // 令牌的 aud 必须包含该值
+	Subject  string   `mapstructure:"subject"`  // 令牌的 sub，* 匹配任意字符，如 repo:example/app:ref:refs/heads/*
+	User     string   `mapstructure:"user"`     // 访问时使用的本地用户
+	Scopes   []string `mapstructure:"scopes"`   // 与个人访问令牌的范围相同，为空时不受范围限制
 }
 
 // PluginsConfig 插件配置
@@ -137,6 +229,8 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
 	viper.SetDefault("server.max_header_bytes", 1048576)
+	viper.SetDefault("server.max_upload_size", 1073741824)
+	viper.SetDefault("server.max_chunk_size", 67108864)
 
 	// 数据库默认配�?
 	viper.SetDefault("database.type", "sqlite")
@@ -148,6 +242,33 @@ func setDefaults() {
 	// 存储默认配置
 	viper.SetDefault("storage.type", "filesystem")
 	viper.SetDefault("storage.base_path", "/var/lib/go-nexus")
+	viper.SetDefault("storage.upload_expiry", "24h")
+
+	// 安全默认配置
+	viper.SetDefault("security.jwt_expire", "1h")
+	viper.SetDefault("security.refresh_expire", "168h")
+	viper.SetDefault("security.anonymous_read", true)
+	viper.SetDefault("security.realms", []string{"local"})
+	viper.SetDefault("security.ldap.timeout", "10s")
+	viper.SetDefault("security.ldap.user_filter", "(uid={username})")
+	viper.SetDefault("security.ldap.username_attribute", "uid")
+	viper.SetDefault("security.ldap.email_attribute", "mail")
+	viper.SetDefault("security.ldap.full_name_attribute", "cn")
+	viper.SetDefault("security.ldap.group_filter", "(|(member={dn})(uniqueMember={dn})(memberUid={username}))")
+	viper.SetDefault("security.ldap.group_name_attribute", "cn")
+	viper.SetDefault("security.docker.service", "go-nexus")
+	viper.SetDefault("security.docker.token_expire", "5m")
+	viper.SetDefault("security.signing.secret", "") // 注册键以便通过 GO_NEXUS_SECURITY_SIGNING_SECRET 设置
+	viper.SetDefault("security.oidc.scopes", []string{"openid", "profile", "email"})
+	viper.SetDefault("security.oidc.timeout", "10s")
+	viper.SetDefault("security.oidc.username_claim", "preferred_username")
+	viper.SetDefault("security.oidc.email_claim", "email")
+	viper.SetDefault("security.oidc.name_claim", "name")
+	viper.SetDefault("security.oidc.groups_claim", "groups")
+
+	// 清理策略默认配置
+	viper.SetDefault("cleanup.enabled", true)
+	viper.SetDefault("cleanup.interval", "24h")
 
 	// 缓存默认配置
 	viper.SetDefault("cache.type", "memory")
@@ -177,6 +298,13 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("invalid server mode: %s", config.Server.Mode)
 	}
 
+	if config.Server.MaxUploadSize <= 0 {
+		return fmt.Errorf("server.max_upload_size must be positive")
+	}
+	if config.Server.MaxChunkSize <= 0 {
+		return fmt.Errorf("server.max_chunk_size must be positive")
+	}
+
 	// 验证数据库类�?
 	if config.Database.Type != "sqlite" && config.Database.Type != "postgresql" {
 		return fmt.Errorf("unsupported database type: %s", config.Database.Type)
@@ -200,5 +328,29 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("invalid log level: %s", config.Log.Level)
 	}
 
+	// 验证认证域
+	if len(config.Security.Realms) == 0 {
+		return fmt.Errorf("security.realms must not be empty")
+	}
+	for _, realm := range config.Security.Realms {
+		switch realm {
+		case "local":
+		case "ldap":
+			if config.Security.LDAP.URL == "" || config.Security.LDAP.UserBaseDN == "" {
+				return fmt.Errorf("security.ldap.url and security.ldap.user_base_dn are required by the ldap realm")
+			}
+		default:
+			return fmt.Errorf("unsupported security realm: %s", realm)
+		}
+	}
+	if oidc := config.Security.OIDC; oidc.Issuer != "" && (oidc.ClientID == "" || oidc.RedirectURL == "") {
+		return fmt.Errorf("security.oidc.client_id and security.oidc.redirect_url are required when security.oidc.issuer is set")
+	}
+	for _, workload := range config.Security.OIDC.Workloads {
+		if workload.Issuer == "" || workload.Audience == "" || workload.Subject == "" || workload.User == "" {
+			return fmt.Errorf("security.oidc.workloads requires issuer, audience, subject and user")
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -37,6 +38,26 @@ type FormatPlugin interface {
 	GenerateMetadata(ctx context.Context, artifacts []*Artifact) ([]byte, error)
 }
 
+// MetadataMerger 元数据合并接口
+// 格式插件可选实现，用于组仓库将多个成员的元数据文件合并为一份
+type MetadataMerger interface {
+	// IsMetadataPath 判断路径是否为需要合并的元数据文件
+	IsMetadataPath(path string) bool
+
+	// MergeMetadata 按成员顺序合并元数据，靠前的成员优先
+	MergeMetadata(ctx context.Context, path string, documents [][]byte) ([]byte, error)
+}
+
+// ArtifactLocator 制品路径解析接口
+// 格式插件可选实现，用于宿主仓库上传制品时识别制品坐标以及需要重新生成的元数据文件
+type ArtifactLocator interface {
+	// Coordinates 从制品路径解析名称与版本，元数据、校验和等附属文件返回 false
+	Coordinates(path string) (name, version string, ok bool)
+
+	// MetadataPath 返回制品所属元数据文件的路径，制品不参与元数据生成时返回 false
+	MetadataPath(path string) (string, bool)
+}
+
 // StoragePlugin 存储插件接口
 type StoragePlugin interface {
 	Plugin
@@ -57,6 +78,27 @@ type StoragePlugin interface {
 	Exists(ctx context.Context, path string) (bool, error)
 }
 
+// ResumableStorage 断点续写存储接口
+// 存储插件可选实现，分块上传时直接在存储中续写未完成的文件，完成后移动到目标位置
+// 不实现该接口的存储不支持分块上传
+type ResumableStorage interface {
+	// WriteAt 从 offset 处写入数据并丢弃其后的内容，文件不存在时创建
+	WriteAt(ctx context.Context, path string, offset int64, data []byte) error
+
+	// Open 打开文件用于流式读取，调用方负责关闭
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// Move 移动文件，目标已存在时覆盖
+	Move(ctx context.Context, from, to string) error
+}
+
+// CopyableStorage 存储内复制接口
+// 存储插件可选实现，制品在仓库间晋级时不经过内存直接复制内容
+type CopyableStorage interface {
+	// Copy 复制文件，目标已存在时覆盖
+	Copy(ctx context.Context, from, to string) error
+}
+
 // IntegrationPlugin 集成插件接口
 type IntegrationPlugin interface {
 	Plugin
@@ -100,9 +142,17 @@ type Artifact struct {
 	UpdatedAt    time.Time         `json:"updated_at"`
 }
 
+// ArtifactEvent 类型
+const (
+	ArtifactEventUploaded = "uploaded"
+	ArtifactEventDeleted  = "deleted"
+	ArtifactEventUpdated  = "updated"
+	ArtifactEventPromoted = "promoted" // 从其他仓库晋级而来
+)
+
 // ArtifactEvent artifact事件
 type ArtifactEvent struct {
-	Type       string    `json:"type"` // uploaded, deleted, updated
+	Type       string    `json:"type"` // uploaded, deleted, updated, promoted
 	Artifact   *Artifact `json:"artifact"`
 	Repository string    `json:"repository"`
 	User       string    `json:"user"`